@@ -156,6 +156,9 @@ type Routes struct {
 	CustomProfileAttributesFields *mux.Router // 'api/v4/custom_profile_attributes/fields'
 	CustomProfileAttributesField  *mux.Router // 'api/v4/custom_profile_attributes/fields/{field_id:[A-Za-z0-9]+}'
 	CustomProfileAttributesValues *mux.Router // 'api/v4/custom_profile_attributes/values'
+
+	SavedSearches *mux.Router // 'api/v4/users/{user_id:[A-Za-z0-9]+}/saved_searches'
+	SavedSearch   *mux.Router // 'api/v4/users/{user_id:[A-Za-z0-9]+}/saved_searches/{saved_search_id:[A-Za-z0-9]+}'
 }
 
 type API struct {
@@ -298,6 +301,9 @@ func Init(srv *app.Server) (*API, error) {
 	api.BaseRoutes.CustomProfileAttributesField = api.BaseRoutes.CustomProfileAttributesFields.PathPrefix("/{field_id:[A-Za-z0-9]+}").Subrouter()
 	api.BaseRoutes.CustomProfileAttributesValues = api.BaseRoutes.CustomProfileAttributes.PathPrefix("/values").Subrouter()
 
+	api.BaseRoutes.SavedSearches = api.BaseRoutes.User.PathPrefix("/saved_searches").Subrouter()
+	api.BaseRoutes.SavedSearch = api.BaseRoutes.SavedSearches.PathPrefix("/{saved_search_id:[A-Za-z0-9]+}").Subrouter()
+
 	api.InitUser()
 	api.InitBot()
 	api.InitTeam()
@@ -349,6 +355,7 @@ func Init(srv *app.Server) (*API, error) {
 	api.InitClientPerformanceMetrics()
 	api.InitScheduledPost()
 	api.InitCustomProfileAttributes()
+	api.InitSavedSearches()
 
 	// If we allow testing then listen for manual testing URL hits
 	if *srv.Config().ServiceSettings.EnableTesting {
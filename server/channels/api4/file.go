@@ -500,7 +500,7 @@ func getFile(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fileReader, err := c.App.FileReader(info.Path)
+	fileReader, err := c.App.FileInfoReader(info, info.Path)
 	if err != nil {
 		c.Err = err
 		c.Err.StatusCode = http.StatusNotFound
@@ -548,7 +548,7 @@ func getFileThumbnail(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fileReader, err := c.App.FileReader(info.ThumbnailPath)
+	fileReader, err := c.App.FileInfoReader(info, info.ThumbnailPath)
 	if err != nil {
 		c.Err = err
 		c.Err.StatusCode = http.StatusNotFound
@@ -648,7 +648,7 @@ func getFilePreview(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fileReader, err := c.App.FileReader(info.PreviewPath)
+	fileReader, err := c.App.FileInfoReader(info, info.PreviewPath)
 	if err != nil {
 		c.Err = err
 		c.Err.StatusCode = http.StatusNotFound
@@ -726,7 +726,7 @@ func getPublicFile(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fileReader, err := c.App.FileReader(info.Path)
+	fileReader, err := c.App.FileInfoReader(info, info.Path)
 	if err != nil {
 		c.Err = err
 		c.Err.StatusCode = http.StatusNotFound
@@ -794,9 +794,18 @@ func searchFiles(c *Context, w http.ResponseWriter, r *http.Request, teamID stri
 		includeDeletedChannels = *params.IncludeDeletedChannels
 	}
 
+	sort := model.SearchSortRecent
+	if params.Sort != nil && *params.Sort != "" {
+		if !model.IsValidSearchSort(*params.Sort) {
+			c.SetInvalidParam("sort")
+			return
+		}
+		sort = *params.Sort
+	}
+
 	startTime := time.Now()
 
-	results, err := c.App.SearchFilesInTeamForUser(c.AppContext, terms, c.AppContext.Session().UserId, teamID, isOrSearch, includeDeletedChannels, timeZoneOffset, page, perPage)
+	results, err := c.App.SearchFilesInTeamForUser(c.AppContext, terms, c.AppContext.Session().UserId, teamID, isOrSearch, includeDeletedChannels, timeZoneOffset, sort, page, perPage)
 
 	elapsedTime := float64(time.Since(startTime)) / float64(time.Second)
 	metrics := c.App.Metrics()
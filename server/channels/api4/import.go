@@ -13,6 +13,7 @@ import (
 
 func (api *API) InitImport() {
 	api.BaseRoutes.Imports.Handle("", api.APISessionRequired(listImports)).Methods(http.MethodGet)
+	api.BaseRoutes.Imports.Handle("/dry_run/{job_id:[A-Za-z0-9]+}", api.APISessionRequired(getImportDryRunReport)).Methods(http.MethodGet)
 }
 
 func listImports(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -31,3 +32,25 @@ func listImports(c *Context, w http.ResponseWriter, r *http.Request) {
 		c.Logger.Warn("Error writing imports", mlog.Err(err))
 	}
 }
+
+func getImportDryRunReport(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireJobId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.IsSystemAdmin() {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	report, appErr := c.App.GetImportDryRunReport(c.AppContext, c.Params.JobId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		c.Logger.Warn("Error writing import dry run report", mlog.Err(err))
+	}
+}
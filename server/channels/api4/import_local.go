@@ -7,4 +7,5 @@ import "net/http"
 
 func (api *API) InitImportLocal() {
 	api.BaseRoutes.Imports.Handle("", api.APILocal(listImports)).Methods(http.MethodGet)
+	api.BaseRoutes.Imports.Handle("/dry_run/{job_id:[A-Za-z0-9]+}", api.APILocal(getImportDryRunReport)).Methods(http.MethodGet)
 }
@@ -110,6 +110,59 @@ func TestListImports(t *testing.T) {
 	}, "change import directory")
 }
 
+func TestGetImportDryRunReport(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	job := &model.Job{
+		Id:     model.NewId(),
+		Type:   model.JobTypeImportProcess,
+		Status: model.JobStatusSuccess,
+		Data:   model.StringMap{"import_file": "import.zip", "dry_run": "true"},
+	}
+	_, err := th.App.Srv().Store().Job().Save(job)
+	require.NoError(t, err)
+
+	otherJob := &model.Job{
+		Id:     model.NewId(),
+		Type:   model.JobTypeImportProcess,
+		Status: model.JobStatusSuccess,
+		Data:   model.StringMap{"import_file": "import.zip", "dry_run": "false"},
+	}
+	_, err = th.App.Srv().Store().Job().Save(otherJob)
+	require.NoError(t, err)
+
+	t.Run("no permissions", func(t *testing.T) {
+		report, _, err := th.Client.GetImportDryRunReport(context.Background(), job.Id)
+		require.Error(t, err)
+		CheckErrorID(t, err, "api.context.permissions.app_error")
+		require.Nil(t, report)
+	})
+
+	th.TestForSystemAdminAndLocal(t, func(t *testing.T, c *model.Client4) {
+		_, resp, err := c.GetImportDryRunReport(context.Background(), job.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	}, "no report")
+
+	th.TestForSystemAdminAndLocal(t, func(t *testing.T, c *model.Client4) {
+		_, resp, err := c.GetImportDryRunReport(context.Background(), otherJob.Id)
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	}, "not a dry run")
+
+	expected := model.NewImportDryRunReport()
+	expected.Add(model.ImportDryRunChange{Line: 2, Entity: "team", Name: "team", Action: model.ImportDryRunActionCreate})
+	appErr := th.App.SaveImportDryRunReport(job.Id, expected)
+	require.Nil(t, appErr)
+
+	th.TestForSystemAdminAndLocal(t, func(t *testing.T, c *model.Client4) {
+		report, _, err := c.GetImportDryRunReport(context.Background(), job.Id)
+		require.NoError(t, err)
+		require.Equal(t, expected, report)
+	}, "report")
+}
+
 func TestImportInLocalMode(t *testing.T) {
 	th := SetupWithServerOptions(t, []app.Option{app.RunEssentialJobs})
 	defer th.TearDown()
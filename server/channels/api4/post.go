@@ -36,6 +36,9 @@ func (api *API) InitPost() {
 
 	api.BaseRoutes.Team.Handle("/posts/search", api.APISessionRequiredDisableWhenBusy(searchPostsInTeam)).Methods(http.MethodPost)
 	api.BaseRoutes.Posts.Handle("/search", api.APISessionRequiredDisableWhenBusy(searchPostsInAllTeams)).Methods(http.MethodPost)
+	api.BaseRoutes.Team.Handle("/posts/search/similar", api.APISessionRequiredDisableWhenBusy(searchSimilarPostsInTeam)).Methods(http.MethodPost)
+	api.BaseRoutes.Posts.Handle("/search/similar", api.APISessionRequiredDisableWhenBusy(searchSimilarPostsInAllTeams)).Methods(http.MethodPost)
+	api.BaseRoutes.Post.Handle("/related", api.APISessionRequiredDisableWhenBusy(getRelatedPosts)).Methods(http.MethodGet)
 	api.BaseRoutes.Post.Handle("", api.APISessionRequired(updatePost)).Methods(http.MethodPut)
 	api.BaseRoutes.Post.Handle("/patch", api.APISessionRequired(patchPost)).Methods(http.MethodPut)
 	api.BaseRoutes.Post.Handle("/restore/{restore_version_id:[A-Za-z0-9]+}", api.APISessionRequired(restorePostVersion)).Methods(http.MethodPost)
@@ -790,13 +793,22 @@ func searchPosts(c *Context, w http.ResponseWriter, r *http.Request, teamId stri
 		includeDeletedChannels = *params.IncludeDeletedChannels
 	}
 
+	sort := model.SearchSortRecent
+	if params.Sort != nil && *params.Sort != "" {
+		if !model.IsValidSearchSort(*params.Sort) {
+			c.SetInvalidParam("sort")
+			return
+		}
+		sort = *params.Sort
+	}
+
 	auditRec := c.MakeAuditRecord("searchPosts", audit.Fail)
 	defer c.LogAuditRecWithLevel(auditRec, app.LevelAPI)
 	audit.AddEventParameterAuditable(auditRec, "search_params", params)
 
 	startTime := time.Now()
 
-	results, err := c.App.SearchPostsForUser(c.AppContext, terms, c.AppContext.Session().UserId, teamId, isOrSearch, includeDeletedChannels, timeZoneOffset, page, perPage)
+	results, err := c.App.SearchPostsForUser(c.AppContext, terms, c.AppContext.Session().UserId, teamId, isOrSearch, includeDeletedChannels, timeZoneOffset, sort, page, perPage)
 
 	elapsedTime := float64(time.Since(startTime)) / float64(time.Second)
 	metrics := c.App.Metrics()
@@ -817,7 +829,73 @@ func searchPosts(c *Context, w http.ResponseWriter, r *http.Request, teamId stri
 		return
 	}
 
+	scores, highlights := results.Scores, results.Highlights
 	results = model.MakePostSearchResults(clientPostList, results.Matches)
+	results.Scores = scores
+	results.Highlights = highlights
+	audit.AddEventParameterAuditable(auditRec, "search_results", results)
+	auditRec.Success()
+
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	if err := results.EncodeJSON(w); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func searchSimilarPostsInTeam(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireTeamId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToTeam(*c.AppContext.Session(), c.Params.TeamId, model.PermissionViewTeam) {
+		c.SetPermissionError(model.PermissionViewTeam)
+		return
+	}
+
+	searchSimilarPosts(c, w, r, c.Params.TeamId)
+}
+
+func searchSimilarPostsInAllTeams(c *Context, w http.ResponseWriter, r *http.Request) {
+	searchSimilarPosts(c, w, r, "")
+}
+
+func searchSimilarPosts(c *Context, w http.ResponseWriter, r *http.Request, teamId string) {
+	var params model.SearchParameter
+	if jsonErr := json.NewDecoder(r.Body).Decode(&params); jsonErr != nil {
+		c.Err = model.NewAppError("searchSimilarPosts", "api.post.search_posts.invalid_body.app_error", nil, "", http.StatusBadRequest).Wrap(jsonErr)
+		return
+	}
+
+	if params.Terms == nil || *params.Terms == "" {
+		c.SetInvalidParam("terms")
+		return
+	}
+
+	perPage := 20
+	if params.PerPage != nil {
+		perPage = *params.PerPage
+	}
+	if perPage <= 0 || perPage > app.SimilarPostsMaxPerPage {
+		c.SetInvalidParam("per_page")
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("searchSimilarPosts", audit.Fail)
+	defer c.LogAuditRecWithLevel(auditRec, app.LevelAPI)
+	audit.AddEventParameterAuditable(auditRec, "search_params", params)
+
+	results, err := c.App.SearchSimilarPostsForUser(c.AppContext, *params.Terms, c.AppContext.Session().UserId, teamId, perPage)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	results, err = prepareSimilarPostsForClient(c, results)
+	if err != nil {
+		c.Err = err
+		return
+	}
 	audit.AddEventParameterAuditable(auditRec, "search_results", results)
 	auditRec.Success()
 
@@ -827,6 +905,53 @@ func searchPosts(c *Context, w http.ResponseWriter, r *http.Request, teamId stri
 	}
 }
 
+func getRelatedPosts(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePostId()
+	if c.Err != nil {
+		return
+	}
+
+	perPage := c.Params.PerPage
+	if perPage > app.SimilarPostsMaxPerPage {
+		perPage = app.SimilarPostsMaxPerPage
+	}
+
+	post, err := c.App.GetPostIfAuthorized(c.AppContext, c.Params.PostId, c.AppContext.Session(), false)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	results, err := c.App.GetRelatedPostsForUser(c.AppContext, post, c.AppContext.Session().UserId, perPage)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	results, err = prepareSimilarPostsForClient(c, results)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	if err := results.EncodeJSON(w); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func prepareSimilarPostsForClient(c *Context, results *model.PostSearchResults) (*model.PostSearchResults, *model.AppError) {
+	clientPostList := c.App.PreparePostListForClient(c.AppContext, results.PostList)
+	clientPostList, err := c.App.SanitizePostListMetadataForUser(c.AppContext, clientPostList, c.AppContext.Session().UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	clientResults := model.MakePostSearchResults(clientPostList, nil)
+	clientResults.Scores = results.Scores
+	return clientResults, nil
+}
+
 func updatePost(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.RequirePostId()
 	if c.Err != nil {
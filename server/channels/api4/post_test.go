@@ -3790,6 +3790,17 @@ func TestSearchPosts(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, posts.Order, 1, "wrong search")
 
+	sort := model.SearchSortRelevance
+	searchParams.Sort = &sort
+	posts, _, err = client.SearchPostsWithParams(context.Background(), th.BasicTeam.Id, &searchParams)
+	require.NoError(t, err)
+	require.Len(t, posts.Order, 1, "wrong search")
+
+	sort = "oldest"
+	_, resp, err := client.SearchPostsWithParams(context.Background(), th.BasicTeam.Id, &searchParams)
+	require.Error(t, err)
+	CheckBadRequestStatus(t, resp)
+
 	posts, _, _ = client.SearchPosts(context.Background(), th.BasicTeam.Id, "*", false)
 	require.Empty(t, posts.Order, "searching for just * shouldn't return any results")
 
@@ -3797,7 +3808,7 @@ func TestSearchPosts(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, posts.Order, 2, "wrong search results")
 
-	_, resp, err := client.SearchPosts(context.Background(), "junk", "#sgtitlereview", false)
+	_, resp, err = client.SearchPosts(context.Background(), "junk", "#sgtitlereview", false)
 	require.Error(t, err)
 	CheckBadRequestStatus(t, resp)
 
@@ -3816,6 +3827,51 @@ func TestSearchPosts(t *testing.T) {
 	CheckUnauthorizedStatus(t, resp)
 }
 
+func TestSearchSimilarPosts(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	client := th.Client
+
+	t.Run("vector search disabled", func(t *testing.T) {
+		_, resp, err := client.SearchSimilarPosts(context.Background(), th.BasicTeam.Id, "search", 10)
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+
+		_, resp, err = client.SearchSimilarPosts(context.Background(), "", "search", 10)
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+
+		_, resp, err = client.GetRelatedPosts(context.Background(), th.BasicPost.Id, 10)
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		_, resp, err := client.SearchSimilarPosts(context.Background(), "", "", 10)
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+
+		_, resp, err = client.SearchSimilarPosts(context.Background(), "", "search", 1000)
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("no permission", func(t *testing.T) {
+		privateChannel := th.CreatePrivateChannel()
+		post := th.CreatePostWithClient(client, privateChannel)
+		th.RemoveUserFromChannel(th.BasicUser, privateChannel)
+
+		_, resp, err := client.GetRelatedPosts(context.Background(), post.Id, 10)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		otherTeam := th.CreateTeamWithClient(th.SystemAdminClient)
+		_, resp, err = client.SearchSimilarPosts(context.Background(), otherTeam.Id, "search", 10)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+}
+
 func TestSearchHashtagPosts(t *testing.T) {
 	th := Setup(t).InitBasic()
 	defer th.TearDown()
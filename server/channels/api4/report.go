@@ -12,12 +12,16 @@ import (
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
 )
 
 func (api *API) InitReports() {
 	api.BaseRoutes.Reports.Handle("/users", api.APISessionRequired(getUsersForReporting)).Methods(http.MethodGet)
 	api.BaseRoutes.Reports.Handle("/users/count", api.APISessionRequired(getUserCountForReporting)).Methods(http.MethodGet)
 	api.BaseRoutes.Reports.Handle("/users/export", api.APISessionRequired(startUsersBatchExport)).Methods(http.MethodPost)
+	api.BaseRoutes.Reports.Handle("/guests/export", api.APISessionRequired(startGuestAccountsBatchExport)).Methods(http.MethodPost)
+	api.BaseRoutes.Reports.Handle("/channels/export", api.APISessionRequired(startChannelActivityBatchExport)).Methods(http.MethodPost)
+	api.BaseRoutes.Reports.Handle("/teams/members/export", api.APISessionRequired(startTeamMembershipBatchExport)).Methods(http.MethodPost)
 }
 
 func getUsersForReporting(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -76,6 +80,14 @@ func getUserCountForReporting(c *Context, w http.ResponseWriter, r *http.Request
 }
 
 func startUsersBatchExport(c *Context, w http.ResponseWriter, r *http.Request) {
+	startUserReportBatchExport(c, w, r, c.App.StartUsersBatchExport)
+}
+
+func startGuestAccountsBatchExport(c *Context, w http.ResponseWriter, r *http.Request) {
+	startUserReportBatchExport(c, w, r, c.App.StartGuestAccountsBatchExport)
+}
+
+func startUserReportBatchExport(c *Context, w http.ResponseWriter, r *http.Request, start func(rctx request.CTX, ro *model.UserReportOptions, startAt int64, endAt int64, format string) *model.AppError) {
 	if !(c.IsSystemAdmin()) {
 		c.SetPermissionError(model.PermissionManageSystem)
 		return
@@ -95,7 +107,7 @@ func startUsersBatchExport(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 
 	startAt, endAt := model.GetReportDateRange(dateRange, time.Now())
-	if err := c.App.StartUsersBatchExport(c.AppContext, options, startAt, endAt); err != nil {
+	if err := start(c.AppContext, options, startAt, endAt, reportExportFormat(r.URL.Query())); err != nil {
 		c.Err = err
 		return
 	}
@@ -103,6 +115,60 @@ func startUsersBatchExport(c *Context, w http.ResponseWriter, r *http.Request) {
 	ReturnStatusOK(w)
 }
 
+func startChannelActivityBatchExport(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !(c.IsSystemAdmin()) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	teamFilter := r.URL.Query().Get("team_filter")
+	if !(teamFilter == "" || model.IsValidId(teamFilter)) {
+		c.SetInvalidURLParam("team_filter")
+		return
+	}
+
+	dateRange := r.URL.Query().Get("date_range")
+	if dateRange == "" {
+		dateRange = model.ReportDurationAllTime
+	}
+
+	startAt, endAt := model.GetReportDateRange(dateRange, time.Now())
+	if err := c.App.StartChannelActivityBatchExport(c.AppContext, teamFilter, dateRange, startAt, endAt, reportExportFormat(r.URL.Query())); err != nil {
+		c.Err = err
+		return
+	}
+
+	ReturnStatusOK(w)
+}
+
+func startTeamMembershipBatchExport(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !(c.IsSystemAdmin()) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	teamFilter := r.URL.Query().Get("team_filter")
+	if !(teamFilter == "" || model.IsValidId(teamFilter)) {
+		c.SetInvalidURLParam("team_filter")
+		return
+	}
+
+	if err := c.App.StartTeamMembershipBatchExport(c.AppContext, teamFilter, reportExportFormat(r.URL.Query())); err != nil {
+		c.Err = err
+		return
+	}
+
+	ReturnStatusOK(w)
+}
+
+// reportExportFormat returns the format of the report requested, CSV by default.
+func reportExportFormat(values url.Values) string {
+	if format := values.Get("format"); format != "" {
+		return format
+	}
+	return model.ReportExportFormatCSV
+}
+
 func fillReportingBaseOptions(values url.Values) model.ReportingBaseOptions {
 	sortColumn := "Username"
 	if values.Get("sort_column") != "" {
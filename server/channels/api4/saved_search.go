@@ -0,0 +1,178 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/audit"
+)
+
+func (api *API) InitSavedSearches() {
+	api.BaseRoutes.SavedSearches.Handle("", api.APISessionRequired(getSavedSearches)).Methods(http.MethodGet)
+	api.BaseRoutes.SavedSearches.Handle("", api.APISessionRequired(createSavedSearch)).Methods(http.MethodPost)
+	api.BaseRoutes.SavedSearch.Handle("", api.APISessionRequired(getSavedSearch)).Methods(http.MethodGet)
+	api.BaseRoutes.SavedSearch.Handle("/patch", api.APISessionRequired(patchSavedSearch)).Methods(http.MethodPut)
+	api.BaseRoutes.SavedSearch.Handle("", api.APISessionRequired(deleteSavedSearch)).Methods(http.MethodDelete)
+}
+
+// getSavedSearchForUser returns the saved search of the request, checking
+// that the session can manage the saved searches of its user.
+func getSavedSearchForUser(c *Context) *model.SavedSearch {
+	c.RequireUserId().RequireSavedSearchId()
+	if c.Err != nil {
+		return nil
+	}
+
+	if !c.App.SessionHasPermissionToUser(*c.AppContext.Session(), c.Params.UserId) {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return nil
+	}
+
+	savedSearch, appErr := c.App.GetSavedSearch(c.Params.SavedSearchId)
+	if appErr != nil {
+		c.Err = appErr
+		return nil
+	}
+
+	if savedSearch.UserId != c.Params.UserId {
+		c.Err = model.NewAppError("getSavedSearchForUser", "app.saved_search.get.not_found.app_error", nil, "", http.StatusNotFound)
+		return nil
+	}
+
+	return savedSearch
+}
+
+func getSavedSearches(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(*c.AppContext.Session(), c.Params.UserId) {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	savedSearches, appErr := c.App.GetSavedSearchesForUser(c.Params.UserId)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(savedSearches); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func createSavedSearch(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	var savedSearch *model.SavedSearch
+	if err := json.NewDecoder(r.Body).Decode(&savedSearch); err != nil || savedSearch == nil {
+		c.SetInvalidParamWithErr("saved_search", err)
+		return
+	}
+	savedSearch.UserId = c.Params.UserId
+
+	auditRec := c.MakeAuditRecord("createSavedSearch", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameterAuditable(auditRec, "saved_search", savedSearch)
+
+	if !c.App.SessionHasPermissionToUser(*c.AppContext.Session(), c.Params.UserId) {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	if savedSearch.TeamId != "" && !c.App.SessionHasPermissionToTeam(*c.AppContext.Session(), savedSearch.TeamId, model.PermissionViewTeam) {
+		c.SetPermissionError(model.PermissionViewTeam)
+		return
+	}
+
+	created, appErr := c.App.CreateSavedSearch(c.AppContext, savedSearch)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(created)
+	auditRec.AddEventObjectType("saved_search")
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(created); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func getSavedSearch(c *Context, w http.ResponseWriter, r *http.Request) {
+	savedSearch := getSavedSearchForUser(c)
+	if c.Err != nil {
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(savedSearch); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func patchSavedSearch(c *Context, w http.ResponseWriter, r *http.Request) {
+	var patch *model.SavedSearchPatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || patch == nil {
+		c.SetInvalidParamWithErr("saved_search", err)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("patchSavedSearch", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameterAuditable(auditRec, "patch", patch)
+
+	savedSearch := getSavedSearchForUser(c)
+	if c.Err != nil {
+		return
+	}
+	auditRec.AddEventPriorState(savedSearch)
+
+	patched, appErr := c.App.PatchSavedSearch(c.AppContext, savedSearch, patch)
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventResultState(patched)
+	auditRec.AddEventObjectType("saved_search")
+
+	if err := json.NewEncoder(w).Encode(patched); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func deleteSavedSearch(c *Context, w http.ResponseWriter, r *http.Request) {
+	auditRec := c.MakeAuditRecord("deleteSavedSearch", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "saved_search_id", c.Params.SavedSearchId)
+
+	savedSearch := getSavedSearchForUser(c)
+	if c.Err != nil {
+		return
+	}
+	auditRec.AddEventPriorState(savedSearch)
+
+	if appErr := c.App.DeleteSavedSearch(savedSearch.Id); appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddEventObjectType("saved_search")
+
+	ReturnStatusOK(w)
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestSavedSearches(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	client := th.Client
+	user := th.BasicUser
+
+	savedSearch, resp, err := client.CreateSavedSearch(context.Background(), model.Me, &model.SavedSearch{
+		TeamId:       th.BasicTeam.Id,
+		Name:         "My search",
+		Terms:        "hello from:" + th.BasicUser2.Username,
+		EnableAlerts: true,
+	})
+	require.NoError(t, err)
+	CheckCreatedStatus(t, resp)
+	assert.Equal(t, user.Id, savedSearch.UserId)
+	assert.NotZero(t, savedSearch.LastRunAt)
+
+	t.Run("create invalid", func(t *testing.T) {
+		_, resp, err := client.CreateSavedSearch(context.Background(), user.Id, &model.SavedSearch{Name: "Empty"})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("create in a team the user is not a member of", func(t *testing.T) {
+		_, resp, err := client.CreateSavedSearch(context.Background(), user.Id, &model.SavedSearch{
+			TeamId: model.NewId(),
+			Name:   "Other team",
+			Terms:  "hello",
+		})
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		savedSearches, _, err := client.GetSavedSearches(context.Background(), user.Id)
+		require.NoError(t, err)
+		require.Len(t, savedSearches, 1)
+		assert.Equal(t, savedSearch.Id, savedSearches[0].Id)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		fetched, _, err := client.GetSavedSearch(context.Background(), user.Id, savedSearch.Id)
+		require.NoError(t, err)
+		assert.Equal(t, savedSearch.Terms, fetched.Terms)
+
+		_, resp, err := client.GetSavedSearch(context.Background(), user.Id, model.NewId())
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		patched, _, err := client.PatchSavedSearch(context.Background(), user.Id, savedSearch.Id, &model.SavedSearchPatch{
+			Name:         model.NewPointer("Renamed"),
+			EnableAlerts: model.NewPointer(false),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed", patched.Name)
+		assert.False(t, patched.EnableAlerts)
+		assert.Equal(t, savedSearch.Terms, patched.Terms)
+	})
+
+	t.Run("other users cannot access the saved searches", func(t *testing.T) {
+		th.LoginBasic2()
+		defer th.LoginBasic()
+
+		_, resp, err := client.GetSavedSearches(context.Background(), user.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp, err = client.GetSavedSearch(context.Background(), user.Id, savedSearch.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		// The saved search of another user is not found in the own searches.
+		_, resp, err = client.GetSavedSearch(context.Background(), model.Me, savedSearch.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+
+		resp, err = client.DeleteSavedSearch(context.Background(), user.Id, savedSearch.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("system admins can access the saved searches", func(t *testing.T) {
+		savedSearches, _, err := th.SystemAdminClient.GetSavedSearches(context.Background(), user.Id)
+		require.NoError(t, err)
+		assert.Len(t, savedSearches, 1)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, err := client.DeleteSavedSearch(context.Background(), user.Id, savedSearch.Id)
+		require.NoError(t, err)
+
+		resp, err := client.DeleteSavedSearch(context.Background(), user.Id, savedSearch.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+}
@@ -50,8 +50,11 @@ func (api *API) InitSystem() {
 	api.BaseRoutes.APIRoot.Handle("/email/test", api.APISessionRequired(testEmail)).Methods(http.MethodPost)
 	api.BaseRoutes.APIRoot.Handle("/site_url/test", api.APISessionRequired(testSiteURL)).Methods(http.MethodPost)
 	api.BaseRoutes.APIRoot.Handle("/file/s3_test", api.APISessionRequired(testS3)).Methods(http.MethodPost)
+	api.BaseRoutes.APIRoot.Handle("/file/storage_tiers", api.APISessionRequired(getFileStorageTierUsage)).Methods(http.MethodGet)
 	api.BaseRoutes.APIRoot.Handle("/database/recycle", api.APISessionRequired(databaseRecycle)).Methods(http.MethodPost)
 	api.BaseRoutes.APIRoot.Handle("/caches/invalidate", api.APISessionRequired(invalidateCaches)).Methods(http.MethodPost)
+	api.BaseRoutes.APIRoot.Handle("/caches", api.APISessionRequired(getCaches)).Methods(http.MethodGet)
+	api.BaseRoutes.APIRoot.Handle("/caches/{cache_name}/purge", api.APISessionRequired(purgeCache)).Methods(http.MethodPost)
 
 	api.BaseRoutes.APIRoot.Handle("/logs", api.APISessionRequired(getLogs)).Methods(http.MethodGet)
 	api.BaseRoutes.APIRoot.Handle("/logs/download", api.APISessionRequired(downloadLogs)).Methods(http.MethodGet)
@@ -228,6 +231,12 @@ func getSystemPing(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	s["ActiveSearchBackend"] = c.App.ActiveSearchBackend()
 
+	// The node is not healthy until its caches are warmed up so that it gets no traffic in the meantime.
+	if !c.App.Srv().Platform().CachesWarmedUp() {
+		s["cache_warmup_status"] = model.StatusUnhealthy
+		s[model.STATUS] = model.StatusUnhealthy
+	}
+
 	if s[model.STATUS] != model.StatusOk && r.FormValue("use_rest_semantics") != "true" {
 		w.WriteHeader(http.StatusInternalServerError)
 	}
@@ -378,6 +387,50 @@ func invalidateCaches(c *Context, w http.ResponseWriter, r *http.Request) {
 	ReturnStatusOK(w)
 }
 
+func getCaches(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	caches := c.App.Srv().Platform().GetCaches()
+	if caches == nil {
+		caches = []*model.CacheInfo{}
+	}
+	if err := json.NewEncoder(w).Encode(caches); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func purgeCache(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireCacheName()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionInvalidateCaches) {
+		c.SetPermissionError(model.PermissionInvalidateCaches)
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("purgeCache", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	audit.AddEventParameter(auditRec, "cache_name", c.Params.CacheName)
+
+	if *c.App.Config().ExperimentalSettings.RestrictSystemAdmin {
+		c.Err = model.NewAppError("purgeCache", "api.restricted_system_admin", nil, "", http.StatusForbidden)
+		return
+	}
+
+	if appErr := c.App.Srv().Platform().PurgeCache(c.Params.CacheName); appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	auditRec.Success()
+	ReturnStatusOK(w)
+}
+
 func queryLogs(c *Context, w http.ResponseWriter, r *http.Request) {
 	auditRec := c.MakeAuditRecord("queryLogs", audit.Fail)
 	defer c.LogAuditRec(auditRec)
@@ -631,6 +684,34 @@ func testS3(c *Context, w http.ResponseWriter, r *http.Request) {
 	if *cfg.FileSettings.AmazonS3SecretAccessKey == model.FakeSetting {
 		cfg.FileSettings.AmazonS3SecretAccessKey = c.App.Config().FileSettings.AmazonS3SecretAccessKey
 	}
+	if cfg.FileSettings.EncryptionKey != nil && *cfg.FileSettings.EncryptionKey == model.FakeSetting {
+		cfg.FileSettings.EncryptionKey = c.App.Config().FileSettings.EncryptionKey
+	}
+	if len(cfg.FileSettings.EncryptionRetiredKeys) == len(c.App.Config().FileSettings.EncryptionRetiredKeys) {
+		for i, value := range cfg.FileSettings.EncryptionRetiredKeys {
+			if value == model.FakeSetting {
+				cfg.FileSettings.EncryptionRetiredKeys[i] = c.App.Config().FileSettings.EncryptionRetiredKeys[i]
+			}
+		}
+	}
+	if cfg.FileSettings.WebDAVPassword != nil && *cfg.FileSettings.WebDAVPassword == model.FakeSetting {
+		cfg.FileSettings.WebDAVPassword = c.App.Config().FileSettings.WebDAVPassword
+	}
+	if cfg.FileSettings.SFTPPassword != nil && *cfg.FileSettings.SFTPPassword == model.FakeSetting {
+		cfg.FileSettings.SFTPPassword = c.App.Config().FileSettings.SFTPPassword
+	}
+	if cfg.FileSettings.SFTPPrivateKey != nil && *cfg.FileSettings.SFTPPrivateKey == model.FakeSetting {
+		cfg.FileSettings.SFTPPrivateKey = c.App.Config().FileSettings.SFTPPrivateKey
+	}
+	if cfg.FileSettings.ExportWebDAVPassword != nil && *cfg.FileSettings.ExportWebDAVPassword == model.FakeSetting {
+		cfg.FileSettings.ExportWebDAVPassword = c.App.Config().FileSettings.ExportWebDAVPassword
+	}
+	if cfg.FileSettings.ExportSFTPPassword != nil && *cfg.FileSettings.ExportSFTPPassword == model.FakeSetting {
+		cfg.FileSettings.ExportSFTPPassword = c.App.Config().FileSettings.ExportSFTPPassword
+	}
+	if cfg.FileSettings.ExportSFTPPrivateKey != nil && *cfg.FileSettings.ExportSFTPPrivateKey == model.FakeSetting {
+		cfg.FileSettings.ExportSFTPPrivateKey = c.App.Config().FileSettings.ExportSFTPPrivateKey
+	}
 
 	appErr = c.App.TestFileStoreConnectionWithConfig(&cfg.FileSettings)
 	if appErr != nil {
@@ -641,6 +722,29 @@ func testS3(c *Context, w http.ResponseWriter, r *http.Request) {
 	ReturnStatusOK(w)
 }
 
+func getFileStorageTierUsage(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	usage, appErr := c.App.GetFileStorageTierUsage()
+	if appErr != nil {
+		c.Err = appErr
+		return
+	}
+
+	js, err := json.Marshal(usage)
+	if err != nil {
+		c.Err = model.NewAppError("getFileStorageTierUsage", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		return
+	}
+
+	if _, err := w.Write(js); err != nil {
+		c.Logger.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
 func getRedirectLocation(c *Context, w http.ResponseWriter, r *http.Request) {
 	m := make(map[string]string)
 	m["location"] = ""
@@ -21,6 +21,8 @@ func (api *API) InitSystemLocal() {
 	api.BaseRoutes.System.Handle("/support_packet", api.APILocal(generateSupportPacket)).Methods(http.MethodGet)
 	api.BaseRoutes.APIRoot.Handle("/integrity", api.APILocal(localCheckIntegrity)).Methods(http.MethodPost)
 	api.BaseRoutes.System.Handle("/schema/version", api.APILocal(getAppliedSchemaMigrations)).Methods(http.MethodGet)
+	api.BaseRoutes.APIRoot.Handle("/caches", api.APILocal(getCaches)).Methods(http.MethodGet)
+	api.BaseRoutes.APIRoot.Handle("/caches/{cache_name}/purge", api.APILocal(purgeCache)).Methods(http.MethodPost)
 }
 
 func localCheckIntegrity(c *Context, w http.ResponseWriter, r *http.Request) {
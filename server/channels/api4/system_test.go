@@ -409,6 +409,58 @@ func TestInvalidateCaches(t *testing.T) {
 	})
 }
 
+func TestGetCaches(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	t.Run("as system user", func(t *testing.T) {
+		_, resp, err := th.Client.GetCaches(context.Background())
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	th.TestForSystemAdminAndLocal(t, func(t *testing.T, c *model.Client4) {
+		caches, _, err := c.GetCaches(context.Background())
+		require.NoError(t, err)
+
+		names := make([]string, 0, len(caches))
+		for _, cache := range caches {
+			names = append(names, cache.Name)
+		}
+		assert.Contains(t, names, "Status")
+		assert.Contains(t, names, "Session")
+		assert.Contains(t, names, "ProfilesInChannel")
+	})
+}
+
+func TestPurgeCache(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	t.Run("as system user", func(t *testing.T) {
+		resp, err := th.Client.PurgeCache(context.Background(), "Status")
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	th.TestForSystemAdminAndLocal(t, func(t *testing.T, c *model.Client4) {
+		_, err := c.PurgeCache(context.Background(), "Status")
+		require.NoError(t, err)
+
+		resp, err := c.PurgeCache(context.Background(), "Unknown")
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("as restricted system admin", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ExperimentalSettings.RestrictSystemAdmin = true })
+
+		resp, err := th.SystemAdminClient.PurgeCache(context.Background(), "Status")
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+}
+
 func TestGetLogs(t *testing.T) {
 	th := Setup(t)
 	defer th.TearDown()
@@ -17,6 +17,7 @@ import (
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/mattermost/mattermost/server/v8/channels/audit"
+	"github.com/mattermost/mattermost/server/v8/platform/services/slackimport"
 )
 
 const (
@@ -1358,11 +1359,15 @@ func importTeam(c *Context, w http.ResponseWriter, r *http.Request) {
 	switch importFrom {
 	case "slack":
 		var err *model.AppError
-		if err, log = c.App.SlackImport(c.AppContext, fileData, fileSize, c.Params.TeamId); err != nil {
+		var report *slackimport.ImportReport
+		if err, log, report = c.App.SlackImport(c.AppContext, fileData, fileSize, c.Params.TeamId); err != nil {
 			c.Err = err
 			c.Err.StatusCode = http.StatusBadRequest
 		}
 		data["results"] = base64.StdEncoding.EncodeToString(log.Bytes())
+		if reportJSON, jsonErr := json.Marshal(report); jsonErr == nil {
+			data["report"] = string(reportJSON)
+		}
 	default:
 		c.Err = model.NewAppError("importTeam", "api.team.import_team.unknown_import_from.app_error", nil, "", http.StatusBadRequest)
 	}
@@ -24,8 +24,7 @@ var latestVersionCache = cache.NewLRU(&cache.CacheOptions{
 func (s *Server) GetLogs(rctx request.CTX, page, perPage int) ([]string, *model.AppError) {
 	var lines []string
 
-	license := s.License()
-	if license != nil && *license.Features.Cluster && s.platform.Cluster() != nil && *s.platform.Config().ClusterSettings.Enable {
+	if s.platform.IsClusterEnabled() {
 		if info := s.platform.Cluster().GetMyClusterInfo(); info != nil {
 			lines = append(lines, "-----------------------------------------------------------------------------------------------------------")
 			lines = append(lines, "-----------------------------------------------------------------------------------------------------------")
@@ -44,7 +43,7 @@ func (s *Server) GetLogs(rctx request.CTX, page, perPage int) ([]string, *model.
 
 	lines = append(lines, melines...)
 
-	if s.platform.Cluster() != nil && *s.platform.Config().ClusterSettings.Enable {
+	if s.platform.IsClusterEnabled() {
 		clines, err := s.platform.Cluster().GetLogs(rctx, page, perPage)
 		if err != nil {
 			return nil, err
@@ -61,8 +60,7 @@ func (s *Server) QueryLogs(rctx request.CTX, page, perPage int, logFilter *model
 
 	serverName := "default"
 
-	license := s.License()
-	if license != nil && *license.Features.Cluster && s.platform.Cluster() != nil && *s.platform.Config().ClusterSettings.Enable {
+	if s.platform.IsClusterEnabled() {
 		if info := s.platform.Cluster().GetMyClusterInfo(); info != nil {
 			serverName = info.Hostname
 		} else {
@@ -85,7 +83,7 @@ func (s *Server) QueryLogs(rctx request.CTX, page, perPage int, logFilter *model
 		return nil, appErr
 	}
 
-	if s.platform.Cluster() != nil && *s.Config().ClusterSettings.Enable {
+	if s.platform.IsClusterEnabled() {
 		clusterLogs, err := s.platform.Cluster().QueryLogs(rctx, page, perPage)
 		if err != nil {
 			return nil, err
@@ -29,6 +29,35 @@ import (
 
 const warningsFilename = "warnings.txt"
 
+// exportDelta tracks an incremental export, see model.BulkExportOpts.Since. A nil *exportDelta is a full export.
+type exportDelta struct {
+	since int64
+	// The tombstones are written at the end of the export, so that they don't split the segments of the import, and
+	// in the reverse order of their entities, so that e.g. the posts are deleted before their channel is archived.
+	tombstones []*imports.LineImportData
+}
+
+func newExportDelta(since int64) *exportDelta {
+	if since <= 0 {
+		return nil
+	}
+	return &exportDelta{since: since}
+}
+
+// changed tells whether an entity last updated at updateAt is part of the export.
+func (d *exportDelta) changed(updateAt int64) bool {
+	return d == nil || updateAt > d.since
+}
+
+// deleted tells whether an entity deleted at deleteAt needs a tombstone.
+func (d *exportDelta) deleted(deleteAt int64) bool {
+	return d != nil && deleteAt > d.since
+}
+
+func (d *exportDelta) addTombstone(line *imports.LineImportData) {
+	d.tombstones = append(d.tombstones, line)
+}
+
 // We use this map to identify the exportable preferences.
 // Here we link the preference category and name, to the name of the relevant field in the import struct.
 var exportablePreferences = map[imports.ComparablePreference]string{
@@ -135,8 +164,13 @@ func (a *App) BulkExport(ctx request.CTX, writer io.Writer, outPath string, job
 		job.Data = make(model.StringMap)
 	}
 
+	delta := newExportDelta(opts.Since)
+	if delta != nil {
+		ctx.Logger().Info("Bulk export: exporting the changes since", mlog.Int("since", opts.Since))
+	}
+
 	ctx.Logger().Info("Bulk export: exporting version")
-	if err := a.exportVersion(writer); err != nil {
+	if err := a.exportVersion(writer, opts.Since); err != nil {
 		return err
 	}
 
@@ -147,52 +181,62 @@ func (a *App) BulkExport(ctx request.CTX, writer io.Writer, outPath string, job
 	}
 
 	ctx.Logger().Info("Bulk export: exporting teams")
-	teamNames, appErr := a.exportAllTeams(ctx, job, writer)
+	teamNames, appErr := a.exportAllTeams(ctx, job, writer, delta)
 	if appErr != nil {
 		return appErr
 	}
 
 	ctx.Logger().Info("Bulk export: exporting channels")
-	if appErr = a.exportAllChannels(ctx, job, writer, teamNames, opts.IncludeArchivedChannels); appErr != nil {
+	if appErr = a.exportAllChannels(ctx, job, writer, teamNames, opts.IncludeArchivedChannels, delta); appErr != nil {
 		return appErr
 	}
 
 	ctx.Logger().Info("Bulk export: exporting users")
-	profilePictures, appErr := a.exportAllUsers(ctx, job, writer, opts.IncludeArchivedChannels, opts.IncludeProfilePictures)
+	profilePictures, appErr := a.exportAllUsers(ctx, job, writer, opts.IncludeArchivedChannels, opts.IncludeProfilePictures, delta)
 	if appErr != nil {
 		return appErr
 	}
 
 	ctx.Logger().Info("Bulk export: exporting bots")
-	botPPs, appErr := a.exportAllBots(ctx, job, writer, opts.IncludeProfilePictures)
+	botPPs, appErr := a.exportAllBots(ctx, job, writer, opts.IncludeProfilePictures, delta)
 	if appErr != nil {
 		return appErr
 	}
 	profilePictures = append(profilePictures, botPPs...)
 
 	ctx.Logger().Info("Bulk export: exporting posts")
-	attachments, appErr := a.exportAllPosts(ctx, job, writer, opts.IncludeAttachments, opts.IncludeArchivedChannels)
+	attachments, appErr := a.exportAllPosts(ctx, job, writer, opts.IncludeAttachments, opts.IncludeArchivedChannels, delta)
 	if appErr != nil {
 		return appErr
 	}
 
 	ctx.Logger().Info("Bulk export: exporting emoji")
-	emojiPaths, appErr := a.exportCustomEmoji(ctx, job, writer, outPath, "exported_emoji", !opts.CreateArchive)
+	emojiPaths, appErr := a.exportCustomEmoji(ctx, job, writer, outPath, "exported_emoji", !opts.CreateArchive, delta)
 	if appErr != nil {
 		return appErr
 	}
 
 	ctx.Logger().Info("Bulk export: exporting direct channels")
-	if appErr = a.exportAllDirectChannels(ctx, job, writer, opts.IncludeArchivedChannels); appErr != nil {
+	if appErr = a.exportAllDirectChannels(ctx, job, writer, opts.IncludeArchivedChannels, delta); appErr != nil {
 		return appErr
 	}
 
 	ctx.Logger().Info("Bulk export: exporting direct posts")
-	directAttachments, appErr := a.exportAllDirectPosts(ctx, job, writer, opts.IncludeAttachments, opts.IncludeArchivedChannels)
+	directAttachments, appErr := a.exportAllDirectPosts(ctx, job, writer, opts.IncludeAttachments, opts.IncludeArchivedChannels, delta)
 	if appErr != nil {
 		return appErr
 	}
 
+	if delta != nil {
+		ctx.Logger().Info("Bulk export: exporting tombstones")
+		for i := len(delta.tombstones) - 1; i >= 0; i-- {
+			if appErr = a.exportWriteLine(writer, delta.tombstones[i]); appErr != nil {
+				return appErr
+			}
+		}
+		updateJobProgress(ctx.Logger(), a.Srv().Store(), job, "tombstones_exported", len(delta.tombstones))
+	}
+
 	if opts.IncludeAttachments {
 		ctx.Logger().Info("Bulk export: exporting file attachments")
 		warnings, appErr := a.exportAttachments(ctx, attachments, outPath, zipWr)
@@ -281,13 +325,14 @@ func (a *App) exportWriteLine(w io.Writer, line *imports.LineImportData) *model.
 	return nil
 }
 
-func (a *App) exportVersion(writer io.Writer) *model.AppError {
+func (a *App) exportVersion(writer io.Writer, since int64) *model.AppError {
 	version := 1
 
 	info := &imports.VersionInfoImportData{
 		Generator: "mattermost-server",
 		Version:   fmt.Sprintf("%s (%s, enterprise: %s)", model.CurrentVersion, model.BuildHash, model.BuildEnterpriseReady),
 		Created:   time.Now().Format(time.RFC3339Nano),
+		Since:     since,
 	}
 
 	versionLine := &imports.LineImportData{
@@ -399,7 +444,7 @@ func (a *App) exportSchemes(ctx request.CTX, job *model.Job, writer io.Writer, s
 	}
 }
 
-func (a *App) exportAllTeams(ctx request.CTX, job *model.Job, writer io.Writer) (map[string]bool, *model.AppError) {
+func (a *App) exportAllTeams(ctx request.CTX, job *model.Job, writer io.Writer, delta *exportDelta) (map[string]bool, *model.AppError) {
 	afterId := strings.Repeat("0", 26)
 	teamNames := make(map[string]bool)
 	cnt := 0
@@ -420,10 +465,17 @@ func (a *App) exportAllTeams(ctx request.CTX, job *model.Job, writer io.Writer)
 
 			// Skip deleted.
 			if team.DeleteAt != 0 {
+				if delta.deleted(team.DeleteAt) {
+					delta.addTombstone(importLineForTeamTombstone(team))
+				}
 				continue
 			}
 			teamNames[team.Name] = true
 
+			if !delta.changed(team.UpdateAt) {
+				continue
+			}
+
 			teamLine := importLineFromTeam(team)
 			if err := a.exportWriteLine(writer, teamLine); err != nil {
 				return nil, err
@@ -434,7 +486,7 @@ func (a *App) exportAllTeams(ctx request.CTX, job *model.Job, writer io.Writer)
 	return teamNames, nil
 }
 
-func (a *App) exportAllChannels(ctx request.CTX, job *model.Job, writer io.Writer, teamNames map[string]bool, withArchived bool) *model.AppError {
+func (a *App) exportAllChannels(ctx request.CTX, job *model.Job, writer io.Writer, teamNames map[string]bool, withArchived bool, delta *exportDelta) *model.AppError {
 	afterId := strings.Repeat("0", 26)
 	cnt := 0
 	for {
@@ -452,12 +504,18 @@ func (a *App) exportAllChannels(ctx request.CTX, job *model.Job, writer io.Write
 		for _, channel := range channels {
 			afterId = channel.Id
 
+			// Skip channels on deleted teams.
+			if ok := teamNames[channel.TeamName]; !ok {
+				continue
+			}
 			// Skip deleted.
 			if channel.DeleteAt != 0 && !withArchived {
+				if delta.deleted(channel.DeleteAt) {
+					delta.addTombstone(importLineForChannelTombstone(channel))
+				}
 				continue
 			}
-			// Skip channels on deleted teams.
-			if ok := teamNames[channel.TeamName]; !ok {
+			if !delta.changed(channel.UpdateAt) {
 				continue
 			}
 
@@ -471,12 +529,19 @@ func (a *App) exportAllChannels(ctx request.CTX, job *model.Job, writer io.Write
 	return nil
 }
 
-func (a *App) exportAllUsers(ctx request.CTX, job *model.Job, writer io.Writer, includeArchivedChannels, includeProfilePictures bool) ([]string, *model.AppError) {
+func (a *App) exportAllUsers(ctx request.CTX, job *model.Job, writer io.Writer, includeArchivedChannels, includeProfilePictures bool, delta *exportDelta) ([]string, *model.AppError) {
 	afterId := strings.Repeat("0", 26)
 	cnt := 0
 	profilePictures := []string{}
 	for {
-		users, err := a.Srv().Store().User().GetAllAfter(1000, afterId)
+		var users []*model.User
+		var err error
+		if delta == nil {
+			users, err = a.Srv().Store().User().GetAllAfter(1000, afterId)
+		} else {
+			// The users are exported with their memberships, so this includes the users whose memberships changed.
+			users, err = a.Srv().Store().User().GetAllForExportSince(1000, afterId, delta.since)
+		}
 		if err != nil {
 			return profilePictures, model.NewAppError("exportAllUsers", "app.user.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 		}
@@ -555,7 +620,7 @@ func (a *App) exportAllUsers(ctx request.CTX, job *model.Job, writer io.Writer,
 			}
 
 			// Do the Team Memberships.
-			members, err := a.buildUserTeamAndChannelMemberships(ctx, user.Id, includeArchivedChannels)
+			members, err := a.buildUserTeamAndChannelMemberships(ctx, user, includeArchivedChannels, delta)
 			if err != nil {
 				return profilePictures, err
 			}
@@ -571,7 +636,7 @@ func (a *App) exportAllUsers(ctx request.CTX, job *model.Job, writer io.Writer,
 	return profilePictures, nil
 }
 
-func (a *App) exportAllBots(ctx request.CTX, job *model.Job, writer io.Writer, includeProfilePictures bool) ([]string, *model.AppError) {
+func (a *App) exportAllBots(ctx request.CTX, job *model.Job, writer io.Writer, includeProfilePictures bool, delta *exportDelta) ([]string, *model.AppError) {
 	afterId := ""
 	cnt := 0
 	profilePictures := []string{}
@@ -590,6 +655,10 @@ func (a *App) exportAllBots(ctx request.CTX, job *model.Job, writer io.Writer, i
 		for _, bot := range bots {
 			afterId = bot.UserId
 
+			if !delta.changed(bot.UpdateAt) {
+				continue
+			}
+
 			var ownerUsername string
 			owner, err := a.Srv().Store().User().Get(ctx.Context(), bot.OwnerId)
 			if err != nil {
@@ -629,19 +698,25 @@ func (a *App) exportAllBots(ctx request.CTX, job *model.Job, writer io.Writer, i
 	return profilePictures, nil
 }
 
-func (a *App) buildUserTeamAndChannelMemberships(c request.CTX, userID string, includeArchivedChannels bool) (*[]imports.UserTeamImportData, *model.AppError) {
+func (a *App) buildUserTeamAndChannelMemberships(c request.CTX, user *model.User, includeArchivedChannels bool, delta *exportDelta) (*[]imports.UserTeamImportData, *model.AppError) {
 	var memberships []imports.UserTeamImportData
+	userID := user.Id
 
 	members, err := a.Srv().Store().Team().GetTeamMembersForExport(userID)
 	if err != nil {
 		return nil, model.NewAppError("buildUserTeamAndChannelMemberships", "app.team.get_members.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 
+	teamNames := map[string]string{}
 	for _, member := range members {
 		// Skip deleted.
 		if member.DeleteAt != 0 {
+			if delta.deleted(member.DeleteAt) {
+				delta.addTombstone(importLineForTeamMemberTombstone(member.TeamName, user.Username, member.DeleteAt))
+			}
 			continue
 		}
+		teamNames[member.TeamId] = member.TeamName
 
 		memberData := importUserTeamDataFromTeamMember(member)
 
@@ -662,9 +737,43 @@ func (a *App) buildUserTeamAndChannelMemberships(c request.CTX, userID string, i
 		memberships = append(memberships, *memberData)
 	}
 
+	if delta != nil {
+		if appErr := a.addChannelMemberTombstones(user, teamNames, delta); appErr != nil {
+			return nil, appErr
+		}
+	}
+
 	return &memberships, nil
 }
 
+// addChannelMemberTombstones adds the tombstones of the channels of the teams the user left since the previous export.
+// Leaving a channel deletes the membership, so they are found in the history of the channel members.
+func (a *App) addChannelMemberTombstones(user *model.User, teamNames map[string]string, delta *exportDelta) *model.AppError {
+	channelIDs, err := a.Srv().Store().ChannelMemberHistory().GetChannelsLeftSince(user.Id, delta.since+1)
+	if err != nil {
+		return model.NewAppError("addChannelMemberTombstones", "app.channel_member_history.get_channels_left_since.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	if len(channelIDs) == 0 {
+		return nil
+	}
+
+	channels, err := a.Srv().Store().Channel().GetChannelsByIds(channelIDs, false)
+	if err != nil {
+		return model.NewAppError("addChannelMemberTombstones", "app.channel.get_channels_by_ids.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	// The history doesn't keep when the user left, only that it was after the previous export.
+	leftAt := model.GetMillis()
+	for _, channel := range channels {
+		// Leaving a team removes its channels too, and the direct and group channels are not part of a team.
+		teamName, ok := teamNames[channel.TeamId]
+		if !ok {
+			continue
+		}
+		delta.addTombstone(importLineForChannelMemberTombstone(teamName, channel.Name, user.Username, leftAt))
+	}
+	return nil
+}
+
 func (a *App) buildUserChannelMemberships(c request.CTX, userID string, teamID string, includeArchivedChannels bool) (*[]imports.UserChannelImportData, *model.AppError) {
 	members, nErr := a.Srv().Store().Channel().GetChannelMembersForExport(userID, teamID, includeArchivedChannels)
 	if nErr != nil {
@@ -704,7 +813,7 @@ func (a *App) buildUserNotifyProps(notifyProps model.StringMap) *imports.UserNot
 	}
 }
 
-func (a *App) exportAllPosts(ctx request.CTX, job *model.Job, writer io.Writer, withAttachments bool, includeArchivedChannels bool) ([]imports.AttachmentImportData, *model.AppError) {
+func (a *App) exportAllPosts(ctx request.CTX, job *model.Job, writer io.Writer, withAttachments bool, includeArchivedChannels bool, delta *exportDelta) ([]imports.AttachmentImportData, *model.AppError) {
 	var attachments []imports.AttachmentImportData
 	afterId := strings.Repeat("0", 26)
 	var postProcessCount uint64
@@ -717,7 +826,13 @@ func (a *App) exportAllPosts(ctx request.CTX, job *model.Job, writer io.Writer,
 			logCheckpoint = time.Now()
 		}
 
-		posts, nErr := a.Srv().Store().Post().GetParentsForExportAfter(1000, afterId, includeArchivedChannels)
+		var posts []*model.PostForExport
+		var nErr error
+		if delta == nil {
+			posts, nErr = a.Srv().Store().Post().GetParentsForExportAfter(1000, afterId, includeArchivedChannels)
+		} else {
+			posts, nErr = a.Srv().Store().Post().GetParentsForExportSince(1000, afterId, delta.since, includeArchivedChannels)
+		}
 		if nErr != nil {
 			return nil, model.NewAppError("exportAllPosts", "app.post.get_posts.app_error", nil, "", http.StatusInternalServerError).Wrap(nErr)
 		}
@@ -734,15 +849,21 @@ func (a *App) exportAllPosts(ctx request.CTX, job *model.Job, writer io.Writer,
 
 			// Skip deleted.
 			if post.DeleteAt != 0 {
+				if delta.deleted(post.DeleteAt) {
+					delta.addTombstone(importLineForPostTombstone(post, post.Username, post.CreateAt, post.DeleteAt))
+				}
 				continue
 			}
 
 			postLine := importLineForPost(post)
 
-			replies, replyAttachments, err := a.buildPostReplies(ctx, post.Id, withAttachments)
+			replies, deletedReplies, replyAttachments, err := a.buildPostReplies(ctx, post.Id, withAttachments, delta)
 			if err != nil {
 				return nil, err
 			}
+			for _, reply := range deletedReplies {
+				delta.addTombstone(importLineForPostTombstone(post, reply.Username, reply.CreateAt, reply.DeleteAt))
+			}
 
 			followers, err := a.buildThreadFollowers(ctx, post.Id)
 			if err != nil {
@@ -785,28 +906,42 @@ func (a *App) exportAllPosts(ctx request.CTX, job *model.Job, writer io.Writer,
 	}
 }
 
-func (a *App) buildPostReplies(ctx request.CTX, postID string, withAttachments bool) ([]imports.ReplyImportData, []imports.AttachmentImportData, *model.AppError) {
+// buildPostReplies returns the replies of the post. In an incremental export, it also returns the replies deleted
+// since the start of the export, that need a tombstone.
+func (a *App) buildPostReplies(ctx request.CTX, postID string, withAttachments bool, delta *exportDelta) ([]imports.ReplyImportData, []*model.ReplyForExport, []imports.AttachmentImportData, *model.AppError) {
 	var replies []imports.ReplyImportData
+	var deletedReplies []*model.ReplyForExport
 	var attachments []imports.AttachmentImportData
 
-	replyPosts, nErr := a.Srv().Store().Post().GetRepliesForExport(postID)
+	var replyPosts []*model.ReplyForExport
+	var nErr error
+	if delta == nil {
+		replyPosts, nErr = a.Srv().Store().Post().GetRepliesForExport(postID)
+	} else {
+		replyPosts, nErr = a.Srv().Store().Post().GetRepliesForExportSince(postID, delta.since)
+	}
 	if nErr != nil {
-		return nil, nil, model.NewAppError("buildPostReplies", "app.post.get_posts.app_error", nil, "", http.StatusInternalServerError).Wrap(nErr)
+		return nil, nil, nil, model.NewAppError("buildPostReplies", "app.post.get_posts.app_error", nil, "", http.StatusInternalServerError).Wrap(nErr)
 	}
 
 	for _, reply := range replyPosts {
+		if reply.DeleteAt != 0 {
+			deletedReplies = append(deletedReplies, reply)
+			continue
+		}
+
 		replyImportObject := importReplyFromPost(reply)
 		if reply.HasReactions {
 			var appErr *model.AppError
 			replyImportObject.Reactions, appErr = a.BuildPostReactions(ctx, reply.Id)
 			if appErr != nil {
-				return nil, nil, appErr
+				return nil, nil, nil, appErr
 			}
 		}
 		if len(reply.FileIds) > 0 {
 			postAttachments, appErr := a.buildPostAttachments(reply.Id)
 			if appErr != nil {
-				return nil, nil, appErr
+				return nil, nil, nil, appErr
 			}
 			replyImportObject.Attachments = &postAttachments
 			if withAttachments && len(postAttachments) > 0 {
@@ -817,7 +952,7 @@ func (a *App) buildPostReplies(ctx request.CTX, postID string, withAttachments b
 		replies = append(replies, *replyImportObject)
 	}
 
-	return replies, attachments, nil
+	return replies, deletedReplies, attachments, nil
 }
 
 func (a *App) buildThreadFollowers(_ request.CTX, postID string) ([]imports.ThreadFollowerImportData, *model.AppError) {
@@ -873,7 +1008,7 @@ func (a *App) buildPostAttachments(postID string) ([]imports.AttachmentImportDat
 	return attachments, nil
 }
 
-func (a *App) exportCustomEmoji(rctx request.CTX, job *model.Job, writer io.Writer, outPath, exportDir string, exportFiles bool) ([]string, *model.AppError) {
+func (a *App) exportCustomEmoji(rctx request.CTX, job *model.Job, writer io.Writer, outPath, exportDir string, exportFiles bool, delta *exportDelta) ([]string, *model.AppError) {
 	var emojiPaths []string
 	pageNumber := 0
 	cnt := 0
@@ -902,6 +1037,10 @@ func (a *App) exportCustomEmoji(rctx request.CTX, job *model.Job, writer io.Writ
 			}
 
 			for _, emoji := range customEmojiList {
+				if !delta.changed(emoji.UpdateAt) {
+					continue
+				}
+
 				emojiImagePath := filepath.Join(emojiPath, emoji.Id, "image")
 				filePath := filepath.Join(exportDir, emoji.Id, "image")
 				if exportFiles {
@@ -921,9 +1060,47 @@ func (a *App) exportCustomEmoji(rctx request.CTX, job *model.Job, writer io.Writ
 			}
 		}
 	}
+
+	if delta != nil {
+		if appErr := a.addEmojiTombstones(rctx, delta); appErr != nil {
+			return nil, appErr
+		}
+	}
 	return emojiPaths, nil
 }
 
+// addEmojiTombstones adds the tombstones of the emojis deleted since the previous export, unless an emoji of the same
+// name was created again since.
+func (a *App) addEmojiTombstones(rctx request.CTX, delta *exportDelta) *model.AppError {
+	deleted, err := a.Srv().Store().Emoji().GetDeletedSince(delta.since)
+	if err != nil {
+		return model.NewAppError("addEmojiTombstones", "app.emoji.get_list.internal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	if len(deleted) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(deleted))
+	for _, emoji := range deleted {
+		names = append(names, emoji.Name)
+	}
+	live, err := a.Srv().Store().Emoji().GetMultipleByName(rctx, names)
+	if err != nil {
+		return model.NewAppError("addEmojiTombstones", "app.emoji.get_by_name.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	liveNames := make(map[string]bool, len(live))
+	for _, emoji := range live {
+		liveNames[emoji.Name] = true
+	}
+
+	for _, emoji := range deleted {
+		if !liveNames[emoji.Name] {
+			delta.addTombstone(importLineForEmojiTombstone(emoji))
+		}
+	}
+	return nil
+}
+
 // Copies emoji files from 'data/emoji' dir to 'exported_emoji' dir
 func (a *App) copyEmojiImages(rctx request.CTX, emojiId string, emojiImagePath string, pathToDir string) error {
 	fromPath, err := os.Open(emojiImagePath)
@@ -965,7 +1142,7 @@ func (a *App) copyEmojiImages(rctx request.CTX, emojiId string, emojiImagePath s
 	return nil
 }
 
-func (a *App) exportAllDirectChannels(ctx request.CTX, job *model.Job, writer io.Writer, includeArchivedChannels bool) *model.AppError {
+func (a *App) exportAllDirectChannels(ctx request.CTX, job *model.Job, writer io.Writer, includeArchivedChannels bool, delta *exportDelta) *model.AppError {
 	afterId := strings.Repeat("0", 26)
 	cnt := 0
 	for {
@@ -1017,6 +1194,10 @@ func (a *App) exportAllDirectChannels(ctx request.CTX, job *model.Job, writer io
 				}
 			}
 
+			if !delta.changed(channel.UpdateAt) {
+				continue
+			}
+
 			favoritedBy, err := a.buildFavoritedByList(channel.Id)
 			if err != nil {
 				return err
@@ -1109,7 +1290,7 @@ func (a *App) buildShownByList(channel *model.DirectChannelForExport) ([]string,
 	return shownBy, nil
 }
 
-func (a *App) exportAllDirectPosts(ctx request.CTX, job *model.Job, writer io.Writer, withAttachments, includeArchivedChannels bool) ([]imports.AttachmentImportData, *model.AppError) {
+func (a *App) exportAllDirectPosts(ctx request.CTX, job *model.Job, writer io.Writer, withAttachments, includeArchivedChannels bool, delta *exportDelta) ([]imports.AttachmentImportData, *model.AppError) {
 	var attachments []imports.AttachmentImportData
 	afterId := strings.Repeat("0", 26)
 	var postProcessCount uint64
@@ -1122,7 +1303,13 @@ func (a *App) exportAllDirectPosts(ctx request.CTX, job *model.Job, writer io.Wr
 			logCheckpoint = time.Now()
 		}
 
-		posts, err := a.Srv().Store().Post().GetDirectPostParentsForExportAfter(1000, afterId, includeArchivedChannels)
+		var posts []*model.DirectPostForExport
+		var err error
+		if delta == nil {
+			posts, err = a.Srv().Store().Post().GetDirectPostParentsForExportAfter(1000, afterId, includeArchivedChannels)
+		} else {
+			posts, err = a.Srv().Store().Post().GetDirectPostParentsForExportSince(1000, afterId, delta.since, includeArchivedChannels)
+		}
 		if err != nil {
 			return nil, model.NewAppError("exportAllDirectPosts", "app.post.get_direct_posts.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 		}
@@ -1138,12 +1325,15 @@ func (a *App) exportAllDirectPosts(ctx request.CTX, job *model.Job, writer io.Wr
 			afterId = post.Id
 			postProcessCount++
 
-			// Skip deleted.
-			if post.DeleteAt != 0 {
+			if _, ok := channelsToSkip[post.ChannelId]; ok {
 				continue
 			}
 
-			if _, ok := channelsToSkip[post.ChannelId]; ok {
+			// Skip deleted.
+			if post.DeleteAt != 0 {
+				if delta.deleted(post.DeleteAt) {
+					delta.addTombstone(importLineForDirectPostTombstone(post, post.User, post.CreateAt, post.DeleteAt))
+				}
 				continue
 			}
 
@@ -1162,10 +1352,13 @@ func (a *App) exportAllDirectPosts(ctx request.CTX, job *model.Job, writer io.Wr
 			}
 
 			// Do the Replies.
-			replies, replyAttachments, err := a.buildPostReplies(ctx, post.Id, withAttachments)
+			replies, deletedReplies, replyAttachments, err := a.buildPostReplies(ctx, post.Id, withAttachments, delta)
 			if err != nil {
 				return nil, err
 			}
+			for _, reply := range deletedReplies {
+				delta.addTombstone(importLineForDirectPostTombstone(post, reply.Username, reply.CreateAt, reply.DeleteAt))
+			}
 
 			if withAttachments && len(replyAttachments) > 0 {
 				attachments = append(attachments, replyAttachments...)
@@ -298,6 +298,84 @@ func importLineForDirectPost(post *model.DirectPostForExport) *imports.LineImpor
 	}
 }
 
+func importLineForTeamTombstone(team *model.TeamForExport) *imports.LineImportData {
+	return importLineFromDelete(&imports.DeleteImportData{
+		Entity:   model.NewPointer(imports.DeleteEntityTeam),
+		Team:     &team.Name,
+		DeleteAt: &team.DeleteAt,
+	})
+}
+
+func importLineForChannelTombstone(channel *model.ChannelForExport) *imports.LineImportData {
+	return importLineFromDelete(&imports.DeleteImportData{
+		Entity:   model.NewPointer(imports.DeleteEntityChannel),
+		Team:     &channel.TeamName,
+		Channel:  &channel.Name,
+		DeleteAt: &channel.DeleteAt,
+	})
+}
+
+func importLineForTeamMemberTombstone(teamName, username string, deleteAt int64) *imports.LineImportData {
+	return importLineFromDelete(&imports.DeleteImportData{
+		Entity:   model.NewPointer(imports.DeleteEntityTeamMember),
+		Team:     &teamName,
+		User:     &username,
+		DeleteAt: &deleteAt,
+	})
+}
+
+func importLineForChannelMemberTombstone(teamName, channelName, username string, deleteAt int64) *imports.LineImportData {
+	return importLineFromDelete(&imports.DeleteImportData{
+		Entity:   model.NewPointer(imports.DeleteEntityChannelMember),
+		Team:     &teamName,
+		Channel:  &channelName,
+		User:     &username,
+		DeleteAt: &deleteAt,
+	})
+}
+
+func importLineForEmojiTombstone(emoji *model.Emoji) *imports.LineImportData {
+	return importLineFromDelete(&imports.DeleteImportData{
+		Entity:   model.NewPointer(imports.DeleteEntityEmoji),
+		Name:     &emoji.Name,
+		DeleteAt: &emoji.DeleteAt,
+	})
+}
+
+// importLineForPostTombstone returns the tombstone of the root post, or of one of its replies.
+func importLineForPostTombstone(root *model.PostForExport, username string, createAt, deleteAt int64) *imports.LineImportData {
+	return importLineFromDelete(&imports.DeleteImportData{
+		Entity:   model.NewPointer(imports.DeleteEntityPost),
+		Team:     &root.TeamName,
+		Channel:  &root.ChannelName,
+		User:     &username,
+		CreateAt: &createAt,
+		DeleteAt: &deleteAt,
+	})
+}
+
+// importLineForDirectPostTombstone returns the tombstone of the root post, or of one of its replies.
+func importLineForDirectPostTombstone(root *model.DirectPostForExport, username string, createAt, deleteAt int64) *imports.LineImportData {
+	channelMembers := *root.ChannelMembers
+	if len(channelMembers) == 1 {
+		channelMembers = []string{channelMembers[0], channelMembers[0]}
+	}
+	return importLineFromDelete(&imports.DeleteImportData{
+		Entity:         model.NewPointer(imports.DeleteEntityDirectPost),
+		ChannelMembers: &channelMembers,
+		User:           &username,
+		CreateAt:       &createAt,
+		DeleteAt:       &deleteAt,
+	})
+}
+
+func importLineFromDelete(data *imports.DeleteImportData) *imports.LineImportData {
+	return &imports.LineImportData{
+		Type:   "delete",
+		Delete: data,
+	}
+}
+
 func importReplyFromPost(post *model.ReplyForExport) *imports.ReplyImportData {
 	f := []string(post.FlaggedBy)
 	return &imports.ReplyImportData{
@@ -187,7 +187,7 @@ func TestExportCustomEmoji(t *testing.T) {
 	outPath, err := filepath.Abs(filePath)
 	require.NoError(t, err)
 
-	_, appErr := th.App.exportCustomEmoji(th.Context, nil, fileWriter, outPath, dirNameToExportEmoji, false)
+	_, appErr := th.App.exportCustomEmoji(th.Context, nil, fileWriter, outPath, dirNameToExportEmoji, false, nil)
 	require.Nil(t, appErr, "should not have failed")
 }
 
@@ -1064,7 +1064,7 @@ func TestBuildPostReplies(t *testing.T) {
 	}
 
 	t.Run("basic post", func(t *testing.T) {
-		data, attachments, err := th.App.buildPostReplies(th.Context, th.BasicPost.Id, true)
+		data, _, attachments, err := th.App.buildPostReplies(th.Context, th.BasicPost.Id, true, nil)
 		require.Nil(t, err)
 		require.Empty(t, data)
 		require.Empty(t, attachments)
@@ -1072,7 +1072,7 @@ func TestBuildPostReplies(t *testing.T) {
 
 	t.Run("root post with attachments and no replies", func(t *testing.T) {
 		post := createPostWithAttachments(th, 5, "")
-		data, attachments, err := th.App.buildPostReplies(th.Context, post.Id, true)
+		data, _, attachments, err := th.App.buildPostReplies(th.Context, post.Id, true, nil)
 		require.Nil(t, err)
 		require.Empty(t, data)
 		require.Empty(t, attachments)
@@ -1081,7 +1081,7 @@ func TestBuildPostReplies(t *testing.T) {
 	t.Run("root post with attachments and a reply", func(t *testing.T) {
 		post := createPostWithAttachments(th, 5, "")
 		createPostWithAttachments(th, 0, post.Id)
-		data, attachments, err := th.App.buildPostReplies(th.Context, post.Id, true)
+		data, _, attachments, err := th.App.buildPostReplies(th.Context, post.Id, true, nil)
 		require.Nil(t, err)
 		require.Len(t, data, 1)
 		require.Empty(t, attachments)
@@ -1091,7 +1091,7 @@ func TestBuildPostReplies(t *testing.T) {
 		post := createPostWithAttachments(th, 5, "")
 		reply1 := createPostWithAttachments(th, 2, post.Id)
 		reply2 := createPostWithAttachments(th, 3, post.Id)
-		data, attachments, err := th.App.buildPostReplies(th.Context, post.Id, true)
+		data, _, attachments, err := th.App.buildPostReplies(th.Context, post.Id, true, nil)
 		require.Nil(t, err)
 		require.Len(t, data, 2)
 		require.Len(t, attachments, 5)
@@ -1146,6 +1146,112 @@ func TestExportDeletedTeams(t *testing.T) {
 	}
 }
 
+func TestExportIncremental(t *testing.T) {
+	th1 := Setup(t).InitBasic()
+	defer th1.TearDown()
+
+	channel := th1.CreateChannel(th1.Context, th1.BasicTeam)
+	th1.AddUserToChannel(th1.BasicUser2, th1.BasicChannel)
+	deletedPost := th1.CreatePost(th1.BasicChannel)
+	editedPost := th1.CreatePost(th1.BasicChannel)
+	root := th1.CreatePost(th1.BasicChannel)
+	deletedReply := th1.CreatePostReply(root)
+
+	var b bytes.Buffer
+	appErr := th1.App.BulkExport(th1.Context, &b, "somePath", nil, model.BulkExportOpts{})
+	require.Nil(t, appErr)
+
+	th2 := Setup(t)
+	defer th2.TearDown()
+	appErr, i := th2.App.BulkImport(th2.Context, &b, nil, false, 5)
+	require.Nil(t, appErr)
+	require.Equal(t, 0, i)
+
+	since := model.GetMillis()
+	time.Sleep(2 * time.Millisecond)
+
+	_, appErr = th1.App.DeletePost(th1.Context, deletedPost.Id, th1.BasicUser.Id)
+	require.Nil(t, appErr)
+	_, appErr = th1.App.DeletePost(th1.Context, deletedReply.Id, th1.BasicUser.Id)
+	require.Nil(t, appErr)
+	_, appErr = th1.App.PatchPost(th1.Context, editedPost.Id, &model.PostPatch{Message: model.NewPointer("edited message")}, nil)
+	require.Nil(t, appErr)
+	newPost := th1.CreatePost(th1.BasicChannel)
+	appErr = th1.App.DeleteChannel(th1.Context, channel, th1.SystemAdminUser.Id)
+	require.Nil(t, appErr)
+	// Leaving a channel deletes the membership.
+	appErr = th1.App.LeaveChannel(th1.Context, th1.BasicChannel.Id, th1.BasicUser2.Id)
+	require.Nil(t, appErr)
+
+	b.Reset()
+	appErr = th1.App.BulkExport(th1.Context, &b, "somePath", nil, model.BulkExportOpts{Since: since})
+	require.Nil(t, appErr)
+
+	var types []string
+	var tombstones []*imports.DeleteImportData
+	scanner := bufio.NewScanner(bytes.NewReader(b.Bytes()))
+	for scanner.Scan() {
+		var line imports.LineImportData
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		types = append(types, line.Type)
+		if line.Info != nil {
+			assert.Equal(t, since, line.Info.Since)
+		}
+		if line.Delete != nil {
+			tombstones = append(tombstones, line.Delete)
+		}
+		// The posts that didn't change since the previous export are not exported again.
+		if line.Post != nil {
+			assert.NotEqual(t, th1.BasicPost.Message, *line.Post.Message)
+		}
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, "delete", types[len(types)-1], "the tombstones are the last lines")
+	require.Len(t, tombstones, 4)
+	var entities []string
+	for _, tombstone := range tombstones {
+		entities = append(entities, *tombstone.Entity)
+	}
+	assert.Equal(t, []string{imports.DeleteEntityPost, imports.DeleteEntityPost, imports.DeleteEntityChannelMember, imports.DeleteEntityChannel}, entities,
+		"the posts are deleted before the channel is archived")
+
+	appErr, i = th2.App.BulkImport(th2.Context, bytes.NewReader(b.Bytes()), nil, false, 5)
+	require.Nil(t, appErr)
+	require.Equal(t, 0, i)
+
+	team2, err := th2.App.Srv().Store().Team().GetByName(th1.BasicTeam.Name)
+	require.NoError(t, err)
+	channel2, err := th2.App.Srv().Store().Channel().GetByNameIncludeDeleted(team2.Id, channel.Name, false)
+	require.NoError(t, err)
+	assert.NotZero(t, channel2.DeleteAt)
+
+	basicChannel2, err := th2.App.Srv().Store().Channel().GetByName(team2.Id, th1.BasicChannel.Name, false)
+	require.NoError(t, err)
+	user2, err := th2.App.Srv().Store().User().GetByUsername(th1.BasicUser2.Username)
+	require.NoError(t, err)
+	_, err = th2.App.Srv().Store().Channel().GetMember(th2.Context.Context(), basicChannel2.Id, user2.Id)
+	require.Error(t, err, "the user should have left the channel")
+	livePosts := func(createAt int64) []*model.Post {
+		posts, err := th2.App.Srv().Store().Post().GetPostsCreatedAt(basicChannel2.Id, createAt)
+		require.NoError(t, err)
+		var live []*model.Post
+		for _, post := range posts {
+			if post.DeleteAt == 0 {
+				live = append(live, post)
+			}
+		}
+		return live
+	}
+
+	assert.Empty(t, livePosts(deletedPost.CreateAt))
+	assert.Empty(t, livePosts(deletedReply.CreateAt))
+	assert.Len(t, livePosts(root.CreateAt), 1)
+	edited := livePosts(editedPost.CreateAt)
+	require.Len(t, edited, 1, "the edited post should be updated, not imported again")
+	assert.Equal(t, "edited message", edited[0].Message)
+	assert.Len(t, livePosts(newPost.CreateAt), 1)
+}
+
 func TestExportArchivedChannels(t *testing.T) {
 	th1 := Setup(t).InitBasic()
 	defer th1.TearDown()
@@ -100,11 +100,14 @@ func (a *App) TestFileStoreConnectionWithConfig(cfg *model.FileSettings) *model.
 		allowInsecure := a.Config().ServiceSettings.EnableInsecureOutgoingConnections != nil && *a.Config().ServiceSettings.EnableInsecureOutgoingConnections
 		backend, err = filestore.NewFileBackend(filestore.NewExportFileBackendSettingsFromConfig(cfg, complianceEnabled && license.IsCloud(), allowInsecure))
 	} else {
-		backend, err = filestore.NewFileBackend(filestore.NewFileBackendSettingsFromConfig(cfg, complianceEnabled, insecure != nil && *insecure))
+		fileBackendSettings := filestore.NewFileBackendSettingsFromConfig(cfg, complianceEnabled, insecure != nil && *insecure)
+		fileBackendSettings.BlobRefStore = a.Srv().Store().FileBlob()
+		backend, err = filestore.NewFileBackend(fileBackendSettings)
 	}
 	if err != nil {
 		return model.NewAppError("FileAttachmentBackend", "api.file.no_driver.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
+	defer filestore.CloseFileBackend(backend)
 	nErr := backend.TestConnection()
 	if nErr != nil {
 		return connectionTestErrorToAppError(nErr)
@@ -155,6 +158,23 @@ func (a *App) FileReader(path string) (filestore.ReadCloseSeeker, *model.AppErro
 	return a.Srv().fileReader(path)
 }
 
+// FileInfoReader returns a ReadCloseSeeker for path, the path of the file,
+// thumbnail or preview of fileInfo, from the storage tier fileInfo records.
+//
+// The caller is responsible for closing the returned ReadCloseSeeker.
+func (a *App) FileInfoReader(fileInfo *model.FileInfo, path string) (filestore.ReadCloseSeeker, *model.AppError) {
+	tieredBackend, ok := a.FileBackend().(*filestore.TieredFileBackend)
+	if !ok || fileInfo.StorageTier == "" {
+		return a.FileReader(path)
+	}
+
+	result, err := tieredBackend.TierReader(path, fileInfo.StorageTier)
+	if err != nil {
+		return nil, model.NewAppError("FileInfoReader", "api.file.file_reader.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return result, nil
+}
+
 // ZipReader returns a ReadCloser for path. If deflate is true, the zip will use compression.
 //
 // The caller is responsible for closing the returned ReadCloser.
@@ -1218,7 +1238,7 @@ func (a *App) generatePreviewImage(rctx request.CTX, img image.Image, imgType, p
 // will save fileinfo with the preview added
 func (a *App) generateMiniPreview(rctx request.CTX, fi *model.FileInfo) {
 	if fi.IsImage() && !fi.IsSvg() && fi.MiniPreview == nil {
-		file, appErr := a.FileReader(fi.Path)
+		file, appErr := a.FileInfoReader(fi, fi.Path)
 		if appErr != nil {
 			rctx.Logger().Debug("Error reading image file", mlog.Err(appErr))
 			return
@@ -1446,7 +1466,7 @@ func populateZipfile(w *zip.Writer, fileDatas []model.FileData) error {
 	return nil
 }
 
-func (a *App) SearchFilesInTeamForUser(c request.CTX, terms string, userId string, teamId string, isOrSearch bool, includeDeletedChannels bool, timeZoneOffset int, page, perPage int) (*model.FileInfoList, *model.AppError) {
+func (a *App) SearchFilesInTeamForUser(c request.CTX, terms string, userId string, teamId string, isOrSearch bool, includeDeletedChannels bool, timeZoneOffset int, sort string, page, perPage int) (*model.FileInfoList, *model.AppError) {
 	paramsList := model.ParseSearchParams(strings.TrimSpace(terms), timeZoneOffset)
 	includeDeleted := includeDeletedChannels && *a.Config().TeamSettings.ExperimentalViewArchivedChannels
 
@@ -1459,6 +1479,7 @@ func (a *App) SearchFilesInTeamForUser(c request.CTX, terms string, userId strin
 	for _, params := range paramsList {
 		params.OrTerms = isOrSearch
 		params.IncludeDeletedChannels = includeDeleted
+		params.Sort = sort
 		// Don't allow users to search for "*"
 		if params.Terms != "*" {
 			// Convert channel names to channel IDs
@@ -1489,22 +1510,40 @@ func (a *App) SearchFilesInTeamForUser(c request.CTX, terms string, userId strin
 		}
 	}
 
-	return fileInfoSearchResults, a.filterInaccessibleFiles(fileInfoSearchResults, filterFileOptions{assumeSortedCreatedAt: true})
+	if appErr := a.filterInaccessibleFiles(fileInfoSearchResults, filterFileOptions{assumeSortedCreatedAt: sort != model.SearchSortRelevance}); appErr != nil {
+		return nil, appErr
+	}
+	fileInfoSearchResults.RemoveMissingHits()
+
+	return fileInfoSearchResults, nil
 }
 
 func (a *App) ExtractContentFromFileInfo(rctx request.CTX, fileInfo *model.FileInfo) error {
-	// We don't process images.
-	if fileInfo.IsImage() {
+	fileSettings := a.Config().FileSettings
+
+	// Images are only processed when their text can be extracted with OCR.
+	if fileInfo.IsImage() && (!*fileSettings.ExtractContentOCR || fileInfo.IsSvg()) {
 		return nil
 	}
 
-	file, aerr := a.FileReader(fileInfo.Path)
+	file, aerr := a.FileInfoReader(fileInfo, fileInfo.Path)
 	if aerr != nil {
 		return errors.Wrap(aerr, "failed to open file for extract file content")
 	}
 	defer file.Close()
 	text, err := docextractor.Extract(rctx.Logger(), fileInfo.Name, file, docextractor.ExtractSettings{
-		ArchiveRecursion: *a.Config().FileSettings.ArchiveRecursion,
+		ArchiveRecursion: *fileSettings.ArchiveRecursion,
+		DisableOOXML:     !*fileSettings.ExtractContentOOXML,
+		DisableODF:       !*fileSettings.ExtractContentODF,
+		DisableRTF:       !*fileSettings.ExtractContentRTF,
+		DisableEmail:     !*fileSettings.ExtractContentEmail,
+		MaxExtractSize:   int64(*fileSettings.ExtractContentMaxSizeMB) * 1024 * 1024,
+		ExtractTimeout:   time.Duration(*fileSettings.ExtractContentTimeoutSeconds) * time.Second,
+		EnableOCR:        *fileSettings.ExtractContentOCR,
+		OCRURL:           *fileSettings.ExtractContentOCRURL,
+		OCRLanguage:      *fileSettings.ExtractContentOCRLanguage,
+		OCRMaxImageSize:  int64(*fileSettings.ExtractContentOCRMaxImageSizeMB) * 1024 * 1024,
+		OCRTimeout:       time.Duration(*fileSettings.ExtractContentOCRTimeoutSeconds) * time.Second,
 	})
 	if err != nil {
 		return errors.Wrap(err, "failed to extract file content")
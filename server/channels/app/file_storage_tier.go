@@ -0,0 +1,67 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+)
+
+// MoveFileToStorageTier moves a file, along with its thumbnail and preview, to
+// the given storage tier and records the new tier on its FileInfo.
+func (a *App) MoveFileToStorageTier(rctx request.CTX, fileInfo *model.FileInfo, tier string) *model.AppError {
+	if !model.IsValidFileStorageTier(tier) {
+		return model.NewAppError("MoveFileToStorageTier", "app.file_info.storage_tier.invalid.app_error", map[string]any{"Tier": tier}, "", http.StatusBadRequest)
+	}
+
+	tieredBackend, ok := a.FileBackend().(*filestore.TieredFileBackend)
+	if !ok {
+		return model.NewAppError("MoveFileToStorageTier", "app.file_info.storage_tier.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	move := tieredBackend.MoveToCold
+	if tier == model.FileStorageTierHot {
+		move = tieredBackend.MoveToHot
+	}
+
+	if err := move(fileInfo.Path); err != nil {
+		return model.NewAppError("MoveFileToStorageTier", "app.file_info.storage_tier.move.app_error", nil, "id="+fileInfo.Id, http.StatusInternalServerError).Wrap(err)
+	}
+
+	// Thumbnails and previews are not generated for every file.
+	for _, path := range []string{fileInfo.ThumbnailPath, fileInfo.PreviewPath} {
+		if path == "" {
+			continue
+		}
+		if exists, err := tieredBackend.FileExists(path); err != nil || !exists {
+			continue
+		}
+		if err := move(path); err != nil {
+			rctx.Logger().Warn("Failed to move file thumbnail or preview to storage tier", mlog.String("file_info_id", fileInfo.Id), mlog.String("path", path), mlog.Err(err))
+		}
+	}
+
+	if err := a.Srv().Store().FileInfo().UpdateStorageTier(fileInfo.Id, tier); err != nil {
+		return model.NewAppError("MoveFileToStorageTier", "app.file_info.storage_tier.update.app_error", nil, "id="+fileInfo.Id, http.StatusInternalServerError).Wrap(err)
+	}
+	fileInfo.StorageTier = tier
+	if fileInfo.PostId != "" {
+		a.Srv().Store().FileInfo().InvalidateFileInfosForPostCache(fileInfo.PostId, false)
+		a.Srv().Store().FileInfo().InvalidateFileInfosForPostCache(fileInfo.PostId, true)
+	}
+
+	return nil
+}
+
+func (a *App) GetFileStorageTierUsage() ([]*model.FileStorageTierUsage, *model.AppError) {
+	usage, err := a.Srv().Store().FileInfo().GetStorageTierUsage()
+	if err != nil {
+		return nil, model.NewAppError("GetFileStorageTierUsage", "app.file_info.storage_tier.usage.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return usage, nil
+}
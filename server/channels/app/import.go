@@ -6,6 +6,7 @@ package app
 import (
 	"archive/zip"
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -28,17 +29,19 @@ const (
 	statusUpdateAfterLines       = 8192
 )
 
+// ignoredImportErrors are the errors the import goes on after, with the message logged for them.
+var ignoredImportErrors = map[string]string{
+	"api.file.upload_file.large_image.app_error":                            "Large image import error",
+	"app.import.validate_direct_channel_import_data.members_too_few.error":  "Invalid direct channel import data",
+	"app.import.validate_direct_channel_import_data.members_too_many.error": "Invalid direct channel import data",
+}
+
 func stopOnError(c request.CTX, err imports.LineImportWorkerError) bool {
-	switch err.Error.Id {
-	case "api.file.upload_file.large_image.app_error":
-		c.Logger().Warn("Large image import error", mlog.Err(err.Error))
-		return false
-	case "app.import.validate_direct_channel_import_data.members_too_few.error", "app.import.validate_direct_channel_import_data.members_too_many.error":
-		c.Logger().Warn("Invalid direct channel import data", mlog.Err(err.Error))
+	if msg, ok := ignoredImportErrors[err.Error.Id]; ok {
+		c.Logger().Warn(msg, mlog.Err(err.Error))
 		return false
-	default:
-		return true
 	}
+	return true
 }
 
 func processAttachmentPaths(c request.CTX, files *[]imports.AttachmentImportData, basePath string, filesMap map[string]*zip.File) error {
@@ -121,7 +124,51 @@ func processAttachments(c request.CTX, line *imports.LineImportData, basePath st
 	return nil
 }
 
-func (a *App) bulkImportWorker(c request.CTX, dryRun, extractContent bool, wg *sync.WaitGroup, lines <-chan imports.LineImportWorkerData, errors chan<- imports.LineImportWorkerError) {
+// importProgress tracks the lines committed by the workers. The workers commit the lines out of order, the last line
+// committed is the line before the first line not committed yet.
+type importProgress struct {
+	mut  sync.Mutex
+	next int
+	done map[int]bool
+}
+
+func newImportProgress(lastLine int) *importProgress {
+	return &importProgress{
+		next: lastLine + 1,
+		done: make(map[int]bool),
+	}
+}
+
+func (p *importProgress) commit(lines ...imports.LineImportWorkerData) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	for _, line := range lines {
+		if line.LineNumber >= p.next {
+			p.done[line.LineNumber] = true
+		}
+	}
+	for p.done[p.next] {
+		delete(p.done, p.next)
+		p.next++
+	}
+}
+
+// commitIgnored commits the lines if the import goes on after the error, the lines failing with the other errors are
+// imported again when resuming.
+func (p *importProgress) commitIgnored(err *model.AppError, lines ...imports.LineImportWorkerData) {
+	if _, ok := ignoredImportErrors[err.Id]; ok {
+		p.commit(lines...)
+	}
+}
+
+func (p *importProgress) lastLine() int {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	return p.next - 1
+}
+
+func (a *App) bulkImportWorker(c request.CTX, dryRun, extractContent bool, wg *sync.WaitGroup, lines <-chan imports.LineImportWorkerData, errors chan<- imports.LineImportWorkerError, progress *importProgress, dryRunReport *importDryRun) {
 	workerID := model.NewId()
 	processedLines := uint64(0)
 
@@ -131,6 +178,33 @@ func (a *App) bulkImportWorker(c request.CTX, dryRun, extractContent bool, wg *s
 		c.Logger().Info("Bulk import worker finished", mlog.String("bulk_import_worker_id", workerID), mlog.Uint("processed_lines", processedLines))
 	}()
 
+	// importPosts imports the posts or the direct posts of the lines. The lines of a dry run are imported one by one,
+	// for the report to tell the changes of each line.
+	importPosts := func(lines []imports.LineImportWorkerData, importLines func(request.CTX, []imports.LineImportWorkerData, bool, bool) (int, *model.AppError)) {
+		var errLine int
+		var err *model.AppError
+		if dryRunReport == nil {
+			errLine, err = importLines(c, lines, dryRun, extractContent)
+		} else {
+			for _, line := range lines {
+				err = dryRunReport.importLine(c, line, func(c request.CTX) *model.AppError {
+					_, appErr := importLines(c, []imports.LineImportWorkerData{line}, false, extractContent)
+					return appErr
+				})
+				if err != nil {
+					errLine = line.LineNumber
+					break
+				}
+			}
+		}
+		if err != nil {
+			errors <- imports.LineImportWorkerError{Error: err, LineNumber: errLine}
+			progress.commitIgnored(err, lines...)
+		} else {
+			progress.commit(lines...)
+		}
+	}
+
 	postLines := []imports.LineImportWorkerData{}
 	directPostLines := []imports.LineImportWorkerData{}
 	for line := range lines {
@@ -141,9 +215,7 @@ func (a *App) bulkImportWorker(c request.CTX, dryRun, extractContent bool, wg *s
 				errors <- imports.LineImportWorkerError{Error: model.NewAppError("BulkImport", "app.import.import_line.null_post.error", nil, "", http.StatusBadRequest), LineNumber: line.LineNumber}
 			}
 			if len(postLines) >= importMultiplePostsThreshold {
-				if errLine, err := a.importMultiplePostLines(c, postLines, dryRun, extractContent); err != nil {
-					errors <- imports.LineImportWorkerError{Error: err, LineNumber: errLine}
-				}
+				importPosts(postLines, a.importMultiplePostLines)
 				postLines = []imports.LineImportWorkerData{}
 			}
 		case line.LineImportData.Type == "direct_post":
@@ -152,14 +224,23 @@ func (a *App) bulkImportWorker(c request.CTX, dryRun, extractContent bool, wg *s
 				errors <- imports.LineImportWorkerError{Error: model.NewAppError("BulkImport", "app.import.import_line.null_direct_post.error", nil, "", http.StatusBadRequest), LineNumber: line.LineNumber}
 			}
 			if len(directPostLines) >= importMultiplePostsThreshold {
-				if errLine, err := a.importMultipleDirectPostLines(c, directPostLines, dryRun, extractContent); err != nil {
-					errors <- imports.LineImportWorkerError{Error: err, LineNumber: errLine}
-				}
+				importPosts(directPostLines, a.importMultipleDirectPostLines)
 				directPostLines = []imports.LineImportWorkerData{}
 			}
 		default:
-			if err := a.importLine(c, line.LineImportData, dryRun); err != nil {
+			var err *model.AppError
+			if dryRunReport == nil {
+				err = a.importLine(c, line.LineImportData, dryRun)
+			} else {
+				err = dryRunReport.importLine(c, line, func(c request.CTX) *model.AppError {
+					return a.importLine(c, line.LineImportData, false)
+				})
+			}
+			if err != nil {
 				errors <- imports.LineImportWorkerError{Error: err, LineNumber: line.LineNumber}
+				progress.commitIgnored(err, line)
+			} else {
+				progress.commit(line)
 			}
 		}
 
@@ -170,36 +251,65 @@ func (a *App) bulkImportWorker(c request.CTX, dryRun, extractContent bool, wg *s
 	}
 
 	if len(postLines) > 0 {
-		if errLine, err := a.importMultiplePostLines(c, postLines, dryRun, extractContent); err != nil {
-			errors <- imports.LineImportWorkerError{Error: err, LineNumber: errLine}
-		}
+		importPosts(postLines, a.importMultiplePostLines)
 	}
 	if len(directPostLines) > 0 {
-		if errLine, err := a.importMultipleDirectPostLines(c, directPostLines, dryRun, extractContent); err != nil {
-			errors <- imports.LineImportWorkerError{Error: err, LineNumber: errLine}
-		}
+		importPosts(directPostLines, a.importMultipleDirectPostLines)
 	}
 }
 
 func (a *App) BulkImport(c request.CTX, jsonlReader io.Reader, attachmentsReader *zip.Reader, dryRun bool, workers int) (*model.AppError, int) {
-	return a.bulkImport(c, jsonlReader, attachmentsReader, dryRun, true, workers, "")
+	return a.bulkImport(c, jsonlReader, attachmentsReader, dryRun, true, workers, "", 0, nil, nil)
 }
 
 func (a *App) BulkImportWithPath(c request.CTX, jsonlReader io.Reader, attachmentsReader *zip.Reader, dryRun, extractContent bool, workers int, importPath string) (*model.AppError, int) {
-	return a.bulkImport(c, jsonlReader, attachmentsReader, dryRun, extractContent, workers, importPath)
+	return a.bulkImport(c, jsonlReader, attachmentsReader, dryRun, extractContent, workers, importPath, 0, nil, nil)
+}
+
+// ResumeBulkImportWithPath imports the lines after the line fromLine, the lines up to it having been committed by a
+// previous import of the same file. The checkpoint function is called with the last line committed as the import
+// progresses, to resume the import from it if it is interrupted. The posts are imported once whatever the line the
+// import resumes from.
+func (a *App) ResumeBulkImportWithPath(c request.CTX, jsonlReader io.Reader, attachmentsReader *zip.Reader, extractContent bool, workers int, importPath string, fromLine int, checkpoint func(lineNumber int)) (*model.AppError, int) {
+	return a.bulkImport(c, jsonlReader, attachmentsReader, false, extractContent, workers, importPath, fromLine, checkpoint, nil)
+}
+
+// DryRunBulkImportWithPath runs the import against a store keeping its changes in memory, and reports what it would
+// create, update and delete without changing the data of the server. The report lists the changes up to the line of
+// the error if the import fails.
+func (a *App) DryRunBulkImportWithPath(c request.CTX, jsonlReader io.Reader, attachmentsReader *zip.Reader, workers int, importPath string) (*model.ImportDryRunReport, *model.AppError, int) {
+	dryRun := newImportDryRun(a.Srv().Store())
+	appErr, lineNumber := a.bulkImport(c, jsonlReader, attachmentsReader, false, false, workers, importPath, 0, nil, dryRun)
+	return dryRun.report, appErr, lineNumber
 }
 
 // bulkImport will extract attachments from attachmentsReader if it is
 // not nil. If it is nil, it will look for attachments on the
 // filesystem in the locations specified by the JSONL file according
 // to the older behavior
-func (a *App) bulkImport(c request.CTX, jsonlReader io.Reader, attachmentsReader *zip.Reader, dryRun, extractContent bool, workers int, importPath string) (*model.AppError, int) {
+func (a *App) bulkImport(c request.CTX, jsonlReader io.Reader, attachmentsReader *zip.Reader, dryRun, extractContent bool, workers int, importPath string, fromLine int, checkpoint func(lineNumber int), dryRunReport *importDryRun) (*model.AppError, int) {
 	scanner := bufio.NewScanner(jsonlReader)
 	buf := make([]byte, 0, 64*1024)
 	scanner.Buffer(buf, maxScanTokenSize)
 
 	lineNumber := 0
 
+	// The version line is read again when resuming, the lines up to fromLine are skipped.
+	progress := newImportProgress(max(fromLine, 1))
+	lastCheckpoint := fromLine
+	saveCheckpoint := func() {
+		if checkpoint == nil {
+			return
+		}
+		if lastLine := progress.lastLine(); lastLine > lastCheckpoint {
+			checkpoint(lastLine)
+			lastCheckpoint = lastLine
+		}
+	}
+	if fromLine > 0 {
+		c.Logger().Info("Resuming the import", mlog.Int("from_line", fromLine))
+	}
+
 	a.Srv().Store().LockToMaster()
 	defer a.Srv().Store().UnlockFromMaster()
 
@@ -220,6 +330,10 @@ func (a *App) bulkImport(c request.CTX, jsonlReader io.Reader, attachmentsReader
 		lineNumber++
 		if lineNumber%statusUpdateAfterLines == 0 {
 			c.Logger().Info("Reader progress", mlog.Int("processed_lines", lineNumber))
+			saveCheckpoint()
+		}
+		if lineNumber > 1 && lineNumber <= fromLine {
+			continue
 		}
 
 		var line imports.LineImportData
@@ -240,13 +354,16 @@ func (a *App) bulkImport(c request.CTX, jsonlReader io.Reader, attachmentsReader
 			if importDataFileVersion != 1 {
 				return model.NewAppError("BulkImport", "app.import.bulk_import.unsupported_version.error", nil, "", http.StatusBadRequest), lineNumber
 			}
+			if line.Info != nil && line.Info.Since > 0 {
+				c.Logger().Info("Importing an incremental export on top of the previous imports", mlog.Int("since", line.Info.Since))
+			}
 			lastLineType = line.Type
 			continue
 		}
 
 		if line.Type != lastLineType {
 			// Only clear the worker queue if is not the first data entry
-			if lineNumber != 2 {
+			if linesChan != nil {
 				c.Logger().Info(
 					"Finished parsing segment, waiting for workers to finish",
 					mlog.String("old_segment", lastLineType),
@@ -261,9 +378,11 @@ func (a *App) bulkImport(c request.CTX, jsonlReader io.Reader, attachmentsReader
 				if len(errorsChan) != 0 {
 					err := <-errorsChan
 					if stopOnError(c, err) {
+						saveCheckpoint()
 						return err.Error, err.LineNumber
 					}
 				}
+				saveCheckpoint()
 			}
 
 			c.Logger().Info(
@@ -278,7 +397,7 @@ func (a *App) bulkImport(c request.CTX, jsonlReader io.Reader, attachmentsReader
 			linesChan = make(chan imports.LineImportWorkerData, workers)
 			for i := 0; i < workers; i++ {
 				wg.Add(1)
-				go a.bulkImportWorker(c, dryRun, extractContent, &wg, linesChan, errorsChan)
+				go a.bulkImportWorker(c, dryRun, extractContent, &wg, linesChan, errorsChan, progress, dryRunReport)
 			}
 		}
 
@@ -288,6 +407,7 @@ func (a *App) bulkImport(c request.CTX, jsonlReader io.Reader, attachmentsReader
 			if stopOnError(c, err) {
 				close(linesChan)
 				wg.Wait()
+				saveCheckpoint()
 				return err.Error, err.LineNumber
 			}
 		}
@@ -298,6 +418,7 @@ func (a *App) bulkImport(c request.CTX, jsonlReader io.Reader, attachmentsReader
 		close(linesChan)
 	}
 	wg.Wait()
+	saveCheckpoint()
 
 	// Check no errors occurred while waiting for the queue to empty.
 	if len(errorsChan) != 0 {
@@ -364,6 +485,11 @@ func (a *App) importLine(c request.CTX, line imports.LineImportData, dryRun bool
 			return model.NewAppError("BulkImport", "app.import.import_line.null_emoji.error", nil, "", http.StatusBadRequest)
 		}
 		return a.importEmoji(c, line.Emoji, dryRun)
+	case line.Type == "delete":
+		if line.Delete == nil {
+			return model.NewAppError("BulkImport", "app.import.import_line.null_delete.error", nil, "", http.StatusBadRequest)
+		}
+		return a.importDelete(c, line.Delete, dryRun)
 	default:
 		return model.NewAppError("BulkImport", "app.import.import_line.unknown_line_type.error", map[string]any{"Type": line.Type}, "", http.StatusBadRequest)
 	}
@@ -385,3 +511,47 @@ func (a *App) ListImports() ([]string, *model.AppError) {
 
 	return results, nil
 }
+
+func importDryRunReportPath(jobID string) string {
+	return filepath.Join(model.ImportDryRunReportsDir, jobID+".json")
+}
+
+// SaveImportDryRunReport saves the report of the dry run import job.
+func (a *App) SaveImportDryRunReport(jobID string, report *model.ImportDryRunReport) *model.AppError {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return model.NewAppError("SaveImportDryRunReport", "app.import.save_dry_run_report.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	if _, appErr := a.WriteFile(bytes.NewReader(data), importDryRunReportPath(jobID)); appErr != nil {
+		return appErr
+	}
+	return nil
+}
+
+// GetImportDryRunReport returns the report of the dry run import job.
+func (a *App) GetImportDryRunReport(c request.CTX, jobID string) (*model.ImportDryRunReport, *model.AppError) {
+	job, appErr := a.GetJob(c, jobID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if job.Type != model.JobTypeImportProcess || job.Data["dry_run"] != "true" {
+		return nil, model.NewAppError("GetImportDryRunReport", "app.import.get_dry_run_report.not_dry_run.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	path := importDryRunReportPath(jobID)
+	if ok, appErr := a.FileExists(path); appErr != nil {
+		return nil, appErr
+	} else if !ok {
+		return nil, model.NewAppError("GetImportDryRunReport", "app.import.get_dry_run_report.not_found.app_error", nil, "", http.StatusNotFound)
+	}
+	data, appErr := a.ReadFile(path)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	var report model.ImportDryRunReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, model.NewAppError("GetImportDryRunReport", "app.import.get_dry_run_report.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &report, nil
+}
@@ -0,0 +1,848 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/app/imports"
+	"github.com/mattermost/mattermost/server/v8/channels/app/teams"
+	"github.com/mattermost/mattermost/server/v8/channels/app/users"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+// importContextKey is the type of the context keys of the import.
+type importContextKey string
+
+const importDryRunLineKey importContextKey = "importDryRunLine"
+
+// importDryRun runs the import functions against a store keeping the changes of the import in memory, so that they
+// are rolled back at the end of the dry run whatever its result. The import functions use the store returned by
+// importStore, and the import* helpers below instead of the app functions changing the data of the server.
+//
+// The named entities the import creates or changes, e.g. the teams, the channels and the users, are kept for the
+// following lines referencing them. The memberships and the posts are kept for the line changing them only.
+type importDryRun struct {
+	store store.Store
+
+	mut    sync.Mutex
+	report *model.ImportDryRunReport
+	// entities are copies of the named entities changed by the import, by id and by name keys, e.g. "team:<id>" and
+	// "team_name:<name>".
+	entities map[string]any
+	// created are the id keys of the entities created by the import.
+	created map[string]bool
+}
+
+func newImportDryRun(ss store.Store) *importDryRun {
+	return &importDryRun{
+		store:    ss,
+		report:   model.NewImportDryRunReport(),
+		entities: make(map[string]any),
+		created:  make(map[string]bool),
+	}
+}
+
+// importDryRunLine is a line imported by the dry run, with the changes of the line.
+type importDryRunLine struct {
+	dryRun *importDryRun
+	rctx   request.CTX
+	line   imports.LineImportWorkerData
+	store  *importDryRunStore
+
+	changes        []*importDryRunChange
+	teamMembers    map[string]*model.TeamMember
+	channelMembers map[string]*model.ChannelMember
+}
+
+// importDryRunChange is the change of an entity by a line, old being nil if the line creates the entity and value
+// nil if the line deletes it.
+type importDryRunChange struct {
+	entity string
+	key    string
+	name   string
+	old    any
+	value  any
+}
+
+// importLine imports the line with importLine against the store of the dry run, adding the changes of the line to
+// the report once imported.
+func (d *importDryRun) importLine(c request.CTX, line imports.LineImportWorkerData, importLine func(c request.CTX) *model.AppError) *model.AppError {
+	l := &importDryRunLine{
+		dryRun:         d,
+		line:           line,
+		teamMembers:    make(map[string]*model.TeamMember),
+		channelMembers: make(map[string]*model.ChannelMember),
+	}
+	l.rctx = c.WithContext(context.WithValue(c.Context(), importDryRunLineKey, l))
+	l.store = &importDryRunStore{Store: d.store, line: l}
+
+	if appErr := importLine(l.rctx); appErr != nil {
+		return appErr
+	}
+
+	entity, name := l.primary()
+	changes := make([]model.ImportDryRunChange, 0, len(l.changes)+1)
+	for _, change := range l.changes {
+		if change.entity == entity && change.name == name {
+			entity = ""
+		}
+		changes = append(changes, l.reportChange(change))
+	}
+	if entity != "" {
+		// The line didn't change its entity.
+		changes = append(changes, model.ImportDryRunChange{Line: line.LineNumber, Entity: entity, Name: name, Action: model.ImportDryRunActionNoOp})
+	}
+
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	for _, change := range changes {
+		d.report.Add(change)
+	}
+	return nil
+}
+
+// importDryRunLineFrom returns the line of the dry run the import functions are importing, nil if the import isn't a
+// dry run.
+func importDryRunLineFrom(rctx request.CTX) *importDryRunLine {
+	l, _ := rctx.Context().Value(importDryRunLineKey).(*importDryRunLine)
+	return l
+}
+
+func isImportDryRun(rctx request.CTX) bool {
+	return importDryRunLineFrom(rctx) != nil
+}
+
+// importStore returns the store the import functions read and write, the store of the dry run if the import is a
+// dry run.
+func (a *App) importStore(rctx request.CTX) store.Store {
+	if l := importDryRunLineFrom(rctx); l != nil {
+		return l.store
+	}
+	return a.Srv().Store()
+}
+
+// importDryRunGet returns a copy of the entity the dry run keeps with the key, nil if none.
+func importDryRunGet[T any](d *importDryRun, key string) *T {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+
+	value, ok := d.entities[key].(*T)
+	if !ok {
+		return nil
+	}
+	c := *value
+	return &c
+}
+
+// importDryRunKeep keeps the entity created along with another one, e.g. the default channels of a team, without
+// reporting it. The first key is the id key of the entity.
+func importDryRunKeep[T any](d *importDryRun, value *T, keys ...string) {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+
+	c := *value
+	d.created[keys[0]] = true
+	for _, key := range keys {
+		d.entities[key] = &c
+	}
+}
+
+// importDryRunSave keeps the entity changed by the line and records the change, old being the entity before the
+// change, nil if the line creates it. The first key is the id key of the entity. The changes of the entities created
+// by a previous line are part of their creation.
+func importDryRunSave[T any](l *importDryRunLine, entity, name string, old, value *T, keys ...string) {
+	d := l.dryRun
+	c := *value
+
+	d.mut.Lock()
+	_, known := d.entities[keys[0]]
+	createdBefore := d.created[keys[0]]
+	if old == nil && !known {
+		d.created[keys[0]] = true
+	}
+	for _, key := range keys {
+		d.entities[key] = &c
+	}
+	d.mut.Unlock()
+
+	if createdBefore && l.change(keys[0]) == nil {
+		return
+	}
+	if old == nil {
+		l.record(entity, keys[0], name, nil, &c)
+		return
+	}
+	l.record(entity, keys[0], name, old, &c)
+}
+
+func (l *importDryRunLine) change(key string) *importDryRunChange {
+	for _, change := range l.changes {
+		if change.key == key {
+			return change
+		}
+	}
+	return nil
+}
+
+// record records the change of the entity, the first change of the line keeping the entity before the line.
+func (l *importDryRunLine) record(entity, key, name string, old, value any) {
+	if change := l.change(key); change != nil {
+		change.value = value
+		return
+	}
+	l.changes = append(l.changes, &importDryRunChange{entity: entity, key: key, name: name, old: old, value: value})
+}
+
+func (l *importDryRunLine) reportChange(change *importDryRunChange) model.ImportDryRunChange {
+	reported := model.ImportDryRunChange{
+		Line:   l.line.LineNumber,
+		Entity: change.entity,
+		Name:   change.name,
+		Action: model.ImportDryRunActionNoOp,
+	}
+	switch {
+	case change.old == nil:
+		reported.Action = model.ImportDryRunActionCreate
+	case change.value == nil:
+		reported.Action = model.ImportDryRunActionDelete
+	default:
+		oldFields := l.fields(change.old)
+		for i, field := range l.fields(change.value) {
+			if field.New != oldFields[i].New {
+				reported.Fields = append(reported.Fields, model.ImportDryRunField{Name: field.Name, Old: oldFields[i].New, New: field.New})
+			}
+		}
+		if len(reported.Fields) > 0 {
+			reported.Action = model.ImportDryRunActionUpdate
+			if l.line.Type == "delete" {
+				reported.Action = model.ImportDryRunActionDelete
+				reported.Fields = nil
+			}
+		}
+	}
+	return reported
+}
+
+// fields returns the fields of the entity the report compares, by name in the order of the report.
+func (l *importDryRunLine) fields(value any) []model.ImportDryRunField {
+	var fields []model.ImportDryRunField
+	add := func(name, value string) {
+		fields = append(fields, model.ImportDryRunField{Name: name, New: value})
+	}
+	deleteAt := func(deleteAt int64) {
+		add("delete_at", strconv.FormatInt(deleteAt, 10))
+	}
+
+	switch v := value.(type) {
+	case *model.Role:
+		add("display_name", v.DisplayName)
+		add("description", v.Description)
+		add("permissions", sortedFields(strings.Join(v.Permissions, " ")))
+		add("scheme_managed", strconv.FormatBool(v.SchemeManaged))
+	case *model.Scheme:
+		add("display_name", v.DisplayName)
+		add("description", v.Description)
+	case *model.Team:
+		add("display_name", v.DisplayName)
+		add("type", v.Type)
+		add("description", v.Description)
+		add("allow_open_invite", strconv.FormatBool(v.AllowOpenInvite))
+		add("scheme", l.schemeName(v.SchemeId))
+		deleteAt(v.DeleteAt)
+	case *model.Channel:
+		add("display_name", v.DisplayName)
+		add("type", string(v.Type))
+		add("header", v.Header)
+		add("purpose", v.Purpose)
+		add("scheme", l.schemeName(v.SchemeId))
+		deleteAt(v.DeleteAt)
+	case *model.User:
+		add("email", v.Email)
+		add("auth_service", v.AuthService)
+		add("nickname", v.Nickname)
+		add("first_name", v.FirstName)
+		add("last_name", v.LastName)
+		add("position", v.Position)
+		add("locale", v.Locale)
+		add("roles", sortedFields(v.Roles))
+		deleteAt(v.DeleteAt)
+	case *model.Bot:
+		add("display_name", v.DisplayName)
+		add("description", v.Description)
+		deleteAt(v.DeleteAt)
+	case *model.TeamMember:
+		add("roles", memberRoles(v.ExplicitRoles, v.SchemeGuest, v.SchemeUser, v.SchemeAdmin, model.TeamGuestRoleId, model.TeamUserRoleId, model.TeamAdminRoleId))
+		deleteAt(v.DeleteAt)
+	case *model.ChannelMember:
+		add("roles", memberRoles(v.ExplicitRoles, v.SchemeGuest, v.SchemeUser, v.SchemeAdmin, model.ChannelGuestRoleId, model.ChannelUserRoleId, model.ChannelAdminRoleId))
+	case *model.Post:
+		add("message", v.Message)
+		add("is_pinned", strconv.FormatBool(v.IsPinned))
+		deleteAt(v.DeleteAt)
+	case *model.Emoji:
+		deleteAt(v.DeleteAt)
+	}
+	return fields
+}
+
+// sortedFields returns the space separated words sorted, to compare lists of roles and permissions whatever their
+// order.
+func sortedFields(s string) string {
+	fields := strings.Fields(s)
+	sort.Strings(fields)
+	return strings.Join(fields, " ")
+}
+
+// memberRoles returns the roles of a team or channel member, the explicit roles and the roles of the scheme.
+func memberRoles(explicitRoles string, isGuest, isUser, isAdmin bool, guestRole, userRole, adminRole string) string {
+	fields := strings.Fields(explicitRoles)
+	if isGuest {
+		fields = append(fields, guestRole)
+	}
+	if isUser {
+		fields = append(fields, userRole)
+	}
+	if isAdmin {
+		fields = append(fields, adminRole)
+	}
+	return sortedFields(strings.Join(fields, " "))
+}
+
+func (l *importDryRunLine) schemeName(schemeID *string) string {
+	if schemeID == nil || *schemeID == "" {
+		return ""
+	}
+	scheme, err := l.store.Scheme().Get(*schemeID)
+	if err != nil {
+		return *schemeID
+	}
+	return scheme.Name
+}
+
+func directChannelName(members []string) string {
+	names := make([]string, 0, len(members))
+	for _, member := range members {
+		names = append(names, strings.ToLower(member))
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+// directChannelName returns the name of the direct or group channel of the line, the usernames of its members.
+func (l *importDryRunLine) directChannelName() string {
+	var members []string
+	switch {
+	case l.line.DirectChannel != nil && l.line.DirectChannel.Participants != nil:
+		for _, member := range l.line.DirectChannel.Participants {
+			members = append(members, *member.Username)
+		}
+	case l.line.DirectChannel != nil && l.line.DirectChannel.Members != nil:
+		members = *l.line.DirectChannel.Members
+	case l.line.DirectPost != nil && l.line.DirectPost.ChannelMembers != nil:
+		members = *l.line.DirectPost.ChannelMembers
+	case l.line.Delete != nil && l.line.Delete.ChannelMembers != nil:
+		members = *l.line.Delete.ChannelMembers
+	}
+	return directChannelName(members)
+}
+
+// primary returns the entity of the line and its name, reported as unchanged if the line doesn't change it.
+func (l *importDryRunLine) primary() (string, string) {
+	line := l.line
+	switch line.Type {
+	case "role":
+		return "role", *line.Role.Name
+	case "scheme":
+		return "scheme", *line.Scheme.Name
+	case "team":
+		return "team", strings.ToLower(*line.Team.Name)
+	case "channel":
+		return "channel", strings.ToLower(*line.Channel.Team) + "/" + strings.ToLower(*line.Channel.Name)
+	case "user":
+		return "user", strings.ToLower(*line.User.Username)
+	case "bot":
+		return "bot", strings.ToLower(*line.Bot.Username)
+	case "direct_channel":
+		return "direct_channel", l.directChannelName()
+	case "post":
+		return "post", fmt.Sprintf("%s/%s/%s/%d", strings.ToLower(*line.Post.Team), strings.ToLower(*line.Post.Channel), strings.ToLower(*line.Post.User), *line.Post.CreateAt)
+	case "direct_post":
+		return "direct_post", fmt.Sprintf("%s/%s/%d", l.directChannelName(), strings.ToLower(*line.DirectPost.User), *line.DirectPost.CreateAt)
+	case "emoji":
+		return "emoji", *line.Emoji.Name
+	case "delete":
+		data := line.Delete
+		switch *data.Entity {
+		case imports.DeleteEntityTeam:
+			return *data.Entity, strings.ToLower(*data.Team)
+		case imports.DeleteEntityChannel:
+			return *data.Entity, strings.ToLower(*data.Team) + "/" + strings.ToLower(*data.Channel)
+		case imports.DeleteEntityTeamMember:
+			return *data.Entity, strings.ToLower(*data.Team) + "/" + strings.ToLower(*data.User)
+		case imports.DeleteEntityChannelMember:
+			return *data.Entity, strings.ToLower(*data.Team) + "/" + strings.ToLower(*data.Channel) + "/" + strings.ToLower(*data.User)
+		case imports.DeleteEntityEmoji:
+			return *data.Entity, *data.Name
+		case imports.DeleteEntityPost:
+			return *data.Entity, fmt.Sprintf("%s/%s/%s/%d", strings.ToLower(*data.Team), strings.ToLower(*data.Channel), strings.ToLower(*data.User), *data.CreateAt)
+		case imports.DeleteEntityDirectPost:
+			return *data.Entity, fmt.Sprintf("%s/%s/%d", l.directChannelName(), strings.ToLower(*data.User), *data.CreateAt)
+		}
+	}
+	return line.Type, ""
+}
+
+// teamName, channelName and username return the names of the entities for the report, their id if not found.
+func (l *importDryRunLine) teamName(teamID string) string {
+	team, err := l.store.Team().Get(teamID)
+	if err != nil {
+		return teamID
+	}
+	return team.Name
+}
+
+func (l *importDryRunLine) channelName(channel *model.Channel) string {
+	if channel.TeamId == "" {
+		return l.directChannelName()
+	}
+	return l.teamName(channel.TeamId) + "/" + channel.Name
+}
+
+func (l *importDryRunLine) username(userID string) string {
+	user, err := l.store.User().Get(l.rctx.Context(), userID)
+	if err != nil {
+		return userID
+	}
+	return user.Username
+}
+
+// The import* helpers below change the data of the server like the app functions the import uses, or the data of the
+// dry run if the import is a dry run. The side effects of the app functions, e.g. the websocket events, are left out
+// of the dry run.
+
+func (a *App) getImportSchemeByName(rctx request.CTX, name string) (*model.Scheme, *model.AppError) {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		return a.GetSchemeByName(name)
+	}
+
+	scheme, err := l.store.Scheme().GetByName(name)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			return nil, model.NewAppError("GetSchemeByName", "app.scheme.get.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		}
+		return nil, model.NewAppError("GetSchemeByName", "app.scheme.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return scheme, nil
+}
+
+func (a *App) importSaveScheme(rctx request.CTX, scheme *model.Scheme) (*model.Scheme, *model.AppError) {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		if scheme.Id == "" {
+			return a.CreateScheme(scheme)
+		}
+		return a.UpdateScheme(scheme)
+	}
+
+	scheme, err := l.store.Scheme().Save(scheme)
+	if err != nil {
+		return nil, model.NewAppError("importSaveScheme", "app.scheme.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return scheme, nil
+}
+
+func (a *App) getImportRoleByName(rctx request.CTX, name string) (*model.Role, *model.AppError) {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		return a.GetRoleByName(rctx.Context(), name)
+	}
+
+	role, err := l.store.Role().GetByName(rctx.Context(), name)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			return nil, model.NewAppError("GetRoleByName", "app.role.get_by_name.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		}
+		return nil, model.NewAppError("GetRoleByName", "app.role.get_by_name.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return role, nil
+}
+
+func (a *App) importSaveRole(rctx request.CTX, role *model.Role) *model.AppError {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		var appErr *model.AppError
+		if role.Id == "" {
+			_, appErr = a.CreateRole(role)
+		} else {
+			_, appErr = a.UpdateRole(role)
+		}
+		return appErr
+	}
+
+	if _, err := l.store.Role().Save(role); err != nil {
+		return model.NewAppError("importSaveRole", "app.role.save.insert.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+// importCreateTeam creates the team with its default channels.
+func (a *App) importCreateTeam(rctx request.CTX, team *model.Team) *model.AppError {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		_, appErr := a.CreateTeam(rctx, team)
+		return appErr
+	}
+
+	team, err := l.store.Team().Save(team)
+	if err != nil {
+		return model.NewAppError("importCreateTeam", "app.team.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	for _, name := range a.DefaultChannelNames(rctx) {
+		channel := &model.Channel{TeamId: team.Id, Name: name, DisplayName: name, Type: model.ChannelTypeOpen}
+		channel.PreSave()
+		importDryRunKeep(l.dryRun, channel, "channel:"+channel.Id, "channel_name:"+team.Id+"/"+name)
+	}
+	return nil
+}
+
+func (a *App) importUpdateTeam(rctx request.CTX, team *model.Team) error {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		_, err := a.ch.srv.teamService.UpdateTeam(team, teams.UpdateOptions{Imported: true})
+		return err
+	}
+
+	_, err := l.store.Team().Update(team)
+	return err
+}
+
+func (a *App) importCreateChannel(rctx request.CTX, channel *model.Channel) *model.AppError {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		_, appErr := a.CreateChannel(rctx, channel, false)
+		return appErr
+	}
+
+	if _, err := l.store.Channel().Save(rctx, channel, -1); err != nil {
+		return model.NewAppError("importCreateChannel", "app.channel.create_channel.internal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (a *App) importUpdateChannel(rctx request.CTX, channel *model.Channel) *model.AppError {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		_, appErr := a.UpdateChannel(rctx, channel)
+		return appErr
+	}
+
+	if _, err := l.store.Channel().Update(rctx, channel); err != nil {
+		return model.NewAppError("importUpdateChannel", "app.channel.update_channel.internal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (a *App) importCreateUser(rctx request.CTX, user *model.User) (*model.User, error) {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		return a.ch.srv.userService.CreateUser(rctx, user, users.UserCreateOptions{FromImport: true})
+	}
+
+	return l.store.User().Save(rctx, user)
+}
+
+func (a *App) importUpdateUser(rctx request.CTX, user *model.User) (*model.User, *model.AppError) {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		return a.UpdateUser(rctx, user, false)
+	}
+
+	if _, err := l.store.User().Update(rctx, user, false); err != nil {
+		return nil, model.NewAppError("importUpdateUser", "app.user.update.finding.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return l.store.importUser(user.Id)
+}
+
+func (a *App) importUpdateUserRoles(rctx request.CTX, userID, roles string) (*model.User, *model.AppError) {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		return a.UpdateUserRoles(rctx, userID, roles, false)
+	}
+
+	user, appErr := l.store.importUser(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	user.Roles = roles
+	if _, err := l.store.User().Update(rctx, user, true); err != nil {
+		return nil, model.NewAppError("importUpdateUserRoles", "app.user.update.finding.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return user, nil
+}
+
+func (a *App) importUpdateUserNotifyProps(rctx request.CTX, userID string, props model.StringMap) *model.AppError {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		return a.updateUserNotifyProps(userID, props)
+	}
+
+	// The notification preferences aren't part of the report.
+	return nil
+}
+
+func (a *App) getImportUser(rctx request.CTX, userID string) (*model.User, *model.AppError) {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		return a.GetUser(userID)
+	}
+
+	return l.store.importUser(userID)
+}
+
+func (a *App) importUpdatePassword(rctx request.CTX, user *model.User, password string) *model.AppError {
+	if isImportDryRun(rctx) {
+		// The passwords aren't part of the report.
+		return nil
+	}
+	return a.UpdatePassword(rctx, user, password)
+}
+
+func (a *App) importVerifyUserEmail(rctx request.CTX, userID, email string) *model.AppError {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		return a.VerifyUserEmail(userID, email)
+	}
+
+	if _, err := l.store.User().VerifyEmail(userID, email); err != nil {
+		return model.NewAppError("VerifyUserEmail", "app.user.verify_email.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (a *App) importCreateBot(rctx request.CTX, bot *model.Bot) (*model.Bot, *model.AppError) {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		return a.CreateBot(rctx, bot)
+	}
+
+	user, err := l.store.User().Save(rctx, model.UserFromBot(bot))
+	if err != nil {
+		return nil, model.NewAppError("CreateBot", "app.user.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	bot.UserId = user.Id
+	if bot, err = l.store.Bot().Save(bot); err != nil {
+		return nil, model.NewAppError("CreateBot", "app.bot.createbot.internal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return bot, nil
+}
+
+// importUpdateTeamMemberRoles sets the explicit roles of the team member. The scheme roles are set by
+// importUpdateTeamMemberSchemeRoles after it.
+func (a *App) importUpdateTeamMemberRoles(rctx request.CTX, teamID, userID, roles string) *model.AppError {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		_, appErr := a.UpdateTeamMemberRoles(rctx, teamID, userID, roles)
+		return appErr
+	}
+
+	member, err := l.store.Team().GetMember(rctx, teamID, userID)
+	if err != nil {
+		return model.NewAppError("UpdateTeamMemberRoles", "app.team.get_member.missing.app_error", nil, "", http.StatusNotFound).Wrap(err)
+	}
+	explicitRoles, appErr := a.importExplicitRoles(rctx, roles)
+	if appErr != nil {
+		return appErr
+	}
+	member.ExplicitRoles = explicitRoles
+	if _, err := l.store.Team().UpdateMember(rctx, member); err != nil {
+		return model.NewAppError("UpdateTeamMemberRoles", "app.team.save_member.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (a *App) importUpdateTeamMemberSchemeRoles(rctx request.CTX, teamID, userID string, isSchemeGuest, isSchemeUser, isSchemeAdmin bool) {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		a.UpdateTeamMemberSchemeRoles(rctx, teamID, userID, isSchemeGuest, isSchemeUser, isSchemeAdmin)
+		return
+	}
+
+	member, err := l.store.Team().GetMember(rctx, teamID, userID)
+	if err != nil || member.SchemeGuest || isSchemeGuest {
+		return
+	}
+	member.SchemeGuest = isSchemeGuest
+	member.SchemeUser = isSchemeUser
+	member.SchemeAdmin = isSchemeAdmin
+	l.store.Team().UpdateMember(rctx, member)
+}
+
+// importUpdateChannelMemberRoles sets the explicit roles of the channel member. The scheme roles are set by
+// importUpdateChannelMemberSchemeRoles after it.
+func (a *App) importUpdateChannelMemberRoles(rctx request.CTX, channelID, userID, roles string) *model.AppError {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		_, appErr := a.UpdateChannelMemberRoles(rctx, channelID, userID, roles)
+		return appErr
+	}
+
+	member, err := l.store.Channel().GetMember(rctx.Context(), channelID, userID)
+	if err != nil {
+		return model.NewAppError("UpdateChannelMemberRoles", MissingChannelMemberError, nil, "", http.StatusNotFound).Wrap(err)
+	}
+	explicitRoles, appErr := a.importExplicitRoles(rctx, roles)
+	if appErr != nil {
+		return appErr
+	}
+	member.ExplicitRoles = explicitRoles
+	if _, err := l.store.Channel().UpdateMember(rctx, member); err != nil {
+		return model.NewAppError("UpdateChannelMemberRoles", "app.channel.update_member.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (a *App) importUpdateChannelMemberSchemeRoles(rctx request.CTX, channelID, userID string, isSchemeGuest, isSchemeUser, isSchemeAdmin bool) {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		a.UpdateChannelMemberSchemeRoles(rctx, channelID, userID, isSchemeGuest, isSchemeUser, isSchemeAdmin)
+		return
+	}
+
+	member, err := l.store.Channel().GetMember(rctx.Context(), channelID, userID)
+	if err != nil || member.SchemeGuest || isSchemeGuest {
+		return
+	}
+	member.SchemeGuest = isSchemeGuest
+	member.SchemeUser = isSchemeUser
+	member.SchemeAdmin = isSchemeAdmin
+	l.store.Channel().UpdateMember(rctx, member)
+}
+
+// importExplicitRoles returns the roles of a member which aren't managed by a scheme.
+func (a *App) importExplicitRoles(rctx request.CTX, roles string) (string, *model.AppError) {
+	var explicitRoles []string
+	for _, name := range strings.Fields(roles) {
+		role, appErr := a.getImportRoleByName(rctx, name)
+		if appErr != nil {
+			appErr.StatusCode = http.StatusBadRequest
+			return "", appErr
+		}
+		if !role.SchemeManaged {
+			explicitRoles = append(explicitRoles, name)
+		}
+	}
+	return strings.Join(explicitRoles, " "), nil
+}
+
+func (a *App) importCreateDirectChannel(rctx request.CTX, userID, otherUserID string) (*model.Channel, *model.AppError) {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		return a.createDirectChannel(rctx, userID, otherUserID)
+	}
+	return l.store.importDirectChannel(rctx, []string{userID, otherUserID})
+}
+
+func (a *App) importGetOrCreateDirectChannel(rctx request.CTX, userID, otherUserID string) (*model.Channel, *model.AppError) {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		return a.GetOrCreateDirectChannel(rctx, userID, otherUserID)
+	}
+	return l.store.importDirectChannel(rctx, []string{userID, otherUserID})
+}
+
+func (a *App) importCreateGroupChannel(rctx request.CTX, userIDs []string) (*model.Channel, *model.AppError) {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		return a.createGroupChannel(rctx, userIDs)
+	}
+	return l.store.importDirectChannel(rctx, userIDs)
+}
+
+func (a *App) importSoftDeleteTeam(rctx request.CTX, team *model.Team) *model.AppError {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		return a.SoftDeleteTeam(team.Id)
+	}
+
+	team.DeleteAt = model.GetMillis()
+	if _, err := l.store.Team().Update(team); err != nil {
+		return model.NewAppError("SoftDeleteTeam", "app.team.update.updating.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (a *App) importDeleteChannel(rctx request.CTX, channel *model.Channel) *model.AppError {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		return a.DeleteChannel(rctx, channel, "")
+	}
+
+	if err := l.store.Channel().Delete(channel.Id, model.GetMillis()); err != nil {
+		return model.NewAppError("DeleteChannel", "app.channel.delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (a *App) importRemoveUserFromTeam(rctx request.CTX, member *model.TeamMember) *model.AppError {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		return a.RemoveUserFromTeam(rctx, member.TeamId, member.UserId, member.UserId)
+	}
+
+	member.DeleteAt = model.GetMillis()
+	if _, err := l.store.Team().UpdateMember(rctx, member); err != nil {
+		return model.NewAppError("RemoveUserFromTeam", "app.team.save_member.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (a *App) importRemoveUserFromChannel(rctx request.CTX, userID string, channel *model.Channel) *model.AppError {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		return a.removeUserFromChannel(rctx, userID, userID, channel)
+	}
+
+	if err := l.store.Channel().RemoveMember(rctx, channel.Id, userID); err != nil {
+		return model.NewAppError("removeUserFromChannel", "app.channel.remove_member.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (a *App) importDeleteEmoji(rctx request.CTX, emoji *model.Emoji) *model.AppError {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		return a.DeleteEmoji(rctx, emoji)
+	}
+
+	if err := l.store.Emoji().Delete(emoji, model.GetMillis()); err != nil {
+		return model.NewAppError("DeleteEmoji", "app.emoji.delete.app_error", nil, "id="+emoji.Id, http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (a *App) importDeletePost(rctx request.CTX, postID, userID string) *model.AppError {
+	l := importDryRunLineFrom(rctx)
+	if l == nil {
+		_, appErr := a.DeletePost(rctx, postID, userID)
+		return appErr
+	}
+
+	if err := l.store.Post().Delete(rctx, postID, model.GetMillis(), userID); err != nil {
+		return model.NewAppError("DeletePost", "app.post.delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,763 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+// importDryRunStore is the store of a line of a dry run import. The entities created or changed by the dry run are
+// read from it, the other ones from the store of the server, and the changes are kept in the dry run instead of
+// being saved.
+type importDryRunStore struct {
+	store.Store
+	line *importDryRunLine
+}
+
+func (s *importDryRunStore) Scheme() store.SchemeStore {
+	return &importDryRunSchemeStore{SchemeStore: s.Store.Scheme(), line: s.line}
+}
+
+func (s *importDryRunStore) Role() store.RoleStore {
+	return &importDryRunRoleStore{RoleStore: s.Store.Role(), line: s.line}
+}
+
+func (s *importDryRunStore) Team() store.TeamStore {
+	return &importDryRunTeamStore{TeamStore: s.Store.Team(), line: s.line}
+}
+
+func (s *importDryRunStore) Channel() store.ChannelStore {
+	return &importDryRunChannelStore{ChannelStore: s.Store.Channel(), line: s.line}
+}
+
+func (s *importDryRunStore) User() store.UserStore {
+	return &importDryRunUserStore{UserStore: s.Store.User(), line: s.line}
+}
+
+func (s *importDryRunStore) Bot() store.BotStore {
+	return &importDryRunBotStore{BotStore: s.Store.Bot(), line: s.line}
+}
+
+func (s *importDryRunStore) Emoji() store.EmojiStore {
+	return &importDryRunEmojiStore{EmojiStore: s.Store.Emoji(), line: s.line}
+}
+
+func (s *importDryRunStore) Post() store.PostStore {
+	return &importDryRunPostStore{PostStore: s.Store.Post(), line: s.line}
+}
+
+// The preferences, the reactions, the thread memberships and the attachments aren't part of the report.
+
+func (s *importDryRunStore) Preference() store.PreferenceStore {
+	return &importDryRunPreferenceStore{PreferenceStore: s.Store.Preference()}
+}
+
+func (s *importDryRunStore) Reaction() store.ReactionStore {
+	return &importDryRunReactionStore{ReactionStore: s.Store.Reaction()}
+}
+
+func (s *importDryRunStore) Thread() store.ThreadStore {
+	return &importDryRunThreadStore{ThreadStore: s.Store.Thread()}
+}
+
+func (s *importDryRunStore) FileInfo() store.FileInfoStore {
+	return &importDryRunFileInfoStore{FileInfoStore: s.Store.FileInfo()}
+}
+
+// importUser returns the user, as the app does.
+func (s *importDryRunStore) importUser(userID string) (*model.User, *model.AppError) {
+	user, err := s.User().Get(s.line.rctx.Context(), userID)
+	if err != nil {
+		return nil, model.NewAppError("GetUser", MissingAccountError, nil, "", http.StatusNotFound).Wrap(err)
+	}
+	return user, nil
+}
+
+// importDirectChannel returns the direct or group channel of the users, creating it if it doesn't exist.
+func (s *importDryRunStore) importDirectChannel(rctx request.CTX, userIDs []string) (*model.Channel, *model.AppError) {
+	channel := &model.Channel{Type: model.ChannelTypeGroup, Name: model.GetGroupNameFromUserIds(userIDs)}
+	if len(userIDs) == 2 {
+		channel = &model.Channel{Type: model.ChannelTypeDirect, Name: model.GetDMNameFromIds(userIDs[0], userIDs[1])}
+	}
+	if existing, err := s.Channel().GetByNameIncludeDeleted("", channel.Name, true); err == nil {
+		return existing, nil
+	}
+
+	members := make([]*model.ChannelMember, 0, len(userIDs))
+	for _, userID := range userIDs {
+		members = append(members, &model.ChannelMember{
+			UserId:      userID,
+			NotifyProps: model.GetDefaultChannelNotifyProps(),
+			SchemeUser:  true,
+		})
+	}
+
+	var err error
+	if channel.Type == model.ChannelTypeDirect {
+		channel, err = s.Channel().SaveDirectChannel(rctx, channel, members[0], members[1])
+	} else if channel, err = s.Channel().Save(rctx, channel, -1); err == nil {
+		for _, member := range members {
+			member.ChannelId = channel.Id
+		}
+		_, err = s.Channel().SaveMultipleMembers(members)
+	}
+	if err != nil {
+		return nil, model.NewAppError("importDirectChannel", "app.channel.create_direct_channel.internal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return channel, nil
+}
+
+type importDryRunSchemeStore struct {
+	store.SchemeStore
+	line *importDryRunLine
+}
+
+func (s *importDryRunSchemeStore) Get(schemeID string) (*model.Scheme, error) {
+	if scheme := importDryRunGet[model.Scheme](s.line.dryRun, "scheme:"+schemeID); scheme != nil {
+		return scheme, nil
+	}
+	return s.SchemeStore.Get(schemeID)
+}
+
+func (s *importDryRunSchemeStore) GetByName(schemeName string) (*model.Scheme, error) {
+	if scheme := importDryRunGet[model.Scheme](s.line.dryRun, "scheme_name:"+schemeName); scheme != nil {
+		return scheme, nil
+	}
+	return s.SchemeStore.GetByName(schemeName)
+}
+
+// Save saves the scheme, with its roles if it's created.
+func (s *importDryRunSchemeStore) Save(scheme *model.Scheme) (*model.Scheme, error) {
+	var old *model.Scheme
+	if scheme.Id == "" {
+		scheme.Id = model.NewId()
+		var roles []*string
+		if scheme.Scope == model.SchemeScopeTeam {
+			roles = append(roles, &scheme.DefaultTeamAdminRole, &scheme.DefaultTeamUserRole, &scheme.DefaultTeamGuestRole)
+		}
+		if scheme.Scope == model.SchemeScopeTeam || scheme.Scope == model.SchemeScopeChannel {
+			roles = append(roles, &scheme.DefaultChannelAdminRole, &scheme.DefaultChannelUserRole, &scheme.DefaultChannelGuestRole)
+		}
+		for _, name := range roles {
+			role := &model.Role{Id: model.NewId(), Name: model.NewId(), SchemeManaged: true}
+			importDryRunKeep(s.line.dryRun, role, "role:"+role.Id, "role_name:"+role.Name)
+			*name = role.Name
+		}
+	} else {
+		var err error
+		if old, err = s.Get(scheme.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	importDryRunSave(s.line, "scheme", scheme.Name, old, scheme, "scheme:"+scheme.Id, "scheme_name:"+scheme.Name)
+	return scheme, nil
+}
+
+type importDryRunRoleStore struct {
+	store.RoleStore
+	line *importDryRunLine
+}
+
+func (s *importDryRunRoleStore) Get(roleID string) (*model.Role, error) {
+	if role := importDryRunGet[model.Role](s.line.dryRun, "role:"+roleID); role != nil {
+		return role, nil
+	}
+	return s.RoleStore.Get(roleID)
+}
+
+func (s *importDryRunRoleStore) GetByName(ctx context.Context, name string) (*model.Role, error) {
+	if role := importDryRunGet[model.Role](s.line.dryRun, "role_name:"+name); role != nil {
+		return role, nil
+	}
+	return s.RoleStore.GetByName(ctx, name)
+}
+
+func (s *importDryRunRoleStore) Save(role *model.Role) (*model.Role, error) {
+	var old *model.Role
+	if role.Id == "" {
+		role.Id = model.NewId()
+	} else {
+		var err error
+		if old, err = s.Get(role.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	importDryRunSave(s.line, "role", role.Name, old, role, "role:"+role.Id, "role_name:"+role.Name)
+	return role, nil
+}
+
+type importDryRunTeamStore struct {
+	store.TeamStore
+	line *importDryRunLine
+}
+
+func (s *importDryRunTeamStore) Get(id string) (*model.Team, error) {
+	if team := importDryRunGet[model.Team](s.line.dryRun, "team:"+id); team != nil {
+		return team, nil
+	}
+	return s.TeamStore.Get(id)
+}
+
+func (s *importDryRunTeamStore) GetByName(name string) (*model.Team, error) {
+	if team := importDryRunGet[model.Team](s.line.dryRun, "team_name:"+name); team != nil {
+		return team, nil
+	}
+	return s.TeamStore.GetByName(name)
+}
+
+func (s *importDryRunTeamStore) GetByNames(names []string) ([]*model.Team, error) {
+	var teams []*model.Team
+	var remaining []string
+	for _, name := range names {
+		if team := importDryRunGet[model.Team](s.line.dryRun, "team_name:"+name); team != nil {
+			teams = append(teams, team)
+		} else {
+			remaining = append(remaining, name)
+		}
+	}
+	if len(remaining) > 0 {
+		found, err := s.TeamStore.GetByNames(remaining)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, found...)
+	}
+	return teams, nil
+}
+
+func (s *importDryRunTeamStore) Save(team *model.Team) (*model.Team, error) {
+	team.PreSave()
+	importDryRunSave(s.line, "team", team.Name, nil, team, "team:"+team.Id, "team_name:"+team.Name)
+	return team, nil
+}
+
+func (s *importDryRunTeamStore) Update(team *model.Team) (*model.Team, error) {
+	old, err := s.Get(team.Id)
+	if err != nil {
+		return nil, err
+	}
+	importDryRunSave(s.line, "team", team.Name, old, team, "team:"+team.Id, "team_name:"+team.Name)
+	return team, nil
+}
+
+func (s *importDryRunTeamStore) GetMember(rctx request.CTX, teamID, userID string) (*model.TeamMember, error) {
+	if member, ok := s.line.teamMembers[teamID+"/"+userID]; ok {
+		c := *member
+		return &c, nil
+	}
+	return s.TeamStore.GetMember(rctx, teamID, userID)
+}
+
+func (s *importDryRunTeamStore) GetTeamsForUser(rctx request.CTX, userID, excludeTeamID string, includeDeleted bool) ([]*model.TeamMember, error) {
+	members, err := s.TeamStore.GetTeamsForUser(rctx, userID, excludeTeamID, includeDeleted)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range s.line.teamMembers {
+		if member.UserId == userID && member.TeamId != excludeTeamID && (includeDeleted || member.DeleteAt == 0) {
+			c := *member
+			members = append(members, &c)
+		}
+	}
+	return members, nil
+}
+
+func (s *importDryRunTeamStore) SaveMultipleMembers(members []*model.TeamMember, maxUsersPerTeam int) ([]*model.TeamMember, error) {
+	for _, member := range members {
+		s.saveMember(member)
+	}
+	return members, nil
+}
+
+func (s *importDryRunTeamStore) UpdateMultipleMembers(members []*model.TeamMember) ([]*model.TeamMember, error) {
+	for _, member := range members {
+		s.saveMember(member)
+	}
+	return members, nil
+}
+
+func (s *importDryRunTeamStore) UpdateMember(rctx request.CTX, member *model.TeamMember) (*model.TeamMember, error) {
+	s.saveMember(member)
+	return member, nil
+}
+
+func (s *importDryRunTeamStore) saveMember(member *model.TeamMember) {
+	key := member.TeamId + "/" + member.UserId
+	var old any
+	if _, ok := s.line.teamMembers[key]; !ok {
+		if existing, err := s.TeamStore.GetMember(s.line.rctx, member.TeamId, member.UserId); err == nil {
+			old = existing
+		}
+	}
+
+	c := *member
+	s.line.teamMembers[key] = &c
+	s.line.record("team_member", "team_member:"+key, s.line.teamName(member.TeamId)+"/"+s.line.username(member.UserId), old, &c)
+}
+
+type importDryRunChannelStore struct {
+	store.ChannelStore
+	line *importDryRunLine
+}
+
+func (s *importDryRunChannelStore) Get(id string, allowFromCache bool) (*model.Channel, error) {
+	if channel := importDryRunGet[model.Channel](s.line.dryRun, "channel:"+id); channel != nil {
+		return channel, nil
+	}
+	return s.ChannelStore.Get(id, allowFromCache)
+}
+
+func (s *importDryRunChannelStore) GetByNameIncludeDeleted(teamID, name string, allowFromCache bool) (*model.Channel, error) {
+	if channel := importDryRunGet[model.Channel](s.line.dryRun, "channel_name:"+teamID+"/"+name); channel != nil {
+		return channel, nil
+	}
+	return s.ChannelStore.GetByNameIncludeDeleted(teamID, name, allowFromCache)
+}
+
+func (s *importDryRunChannelStore) GetByName(teamID, name string, allowFromCache bool) (*model.Channel, error) {
+	if channel := importDryRunGet[model.Channel](s.line.dryRun, "channel_name:"+teamID+"/"+name); channel != nil {
+		if channel.DeleteAt != 0 {
+			return nil, store.NewErrNotFound("Channel", "name="+name)
+		}
+		return channel, nil
+	}
+	return s.ChannelStore.GetByName(teamID, name, allowFromCache)
+}
+
+func (s *importDryRunChannelStore) GetByNamesIncludeDeleted(teamID string, names []string, allowFromCache bool) ([]*model.Channel, error) {
+	var channels []*model.Channel
+	var remaining []string
+	for _, name := range names {
+		if channel := importDryRunGet[model.Channel](s.line.dryRun, "channel_name:"+teamID+"/"+name); channel != nil {
+			channels = append(channels, channel)
+		} else {
+			remaining = append(remaining, name)
+		}
+	}
+	if len(remaining) > 0 {
+		found, err := s.ChannelStore.GetByNamesIncludeDeleted(teamID, remaining, allowFromCache)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, found...)
+	}
+	return channels, nil
+}
+
+func (s *importDryRunChannelStore) Save(rctx request.CTX, channel *model.Channel, maxChannelsPerTeam int64) (*model.Channel, error) {
+	channel.PreSave()
+	s.save(nil, channel)
+	return channel, nil
+}
+
+func (s *importDryRunChannelStore) SaveDirectChannel(rctx request.CTX, channel *model.Channel, member1, member2 *model.ChannelMember) (*model.Channel, error) {
+	channel.PreSave()
+	s.save(nil, channel)
+	for _, member := range []*model.ChannelMember{member1, member2} {
+		member.ChannelId = channel.Id
+		s.saveMember(member)
+	}
+	return channel, nil
+}
+
+func (s *importDryRunChannelStore) Update(rctx request.CTX, channel *model.Channel) (*model.Channel, error) {
+	old, err := s.Get(channel.Id, true)
+	if err != nil {
+		return nil, err
+	}
+	s.save(old, channel)
+	return channel, nil
+}
+
+func (s *importDryRunChannelStore) Delete(channelID string, timestamp int64) error {
+	channel, err := s.Get(channelID, true)
+	if err != nil {
+		return err
+	}
+	old := *channel
+	channel.DeleteAt = timestamp
+	s.save(&old, channel)
+	return nil
+}
+
+func (s *importDryRunChannelStore) save(old, channel *model.Channel) {
+	entity := "channel"
+	if channel.TeamId == "" {
+		entity = "direct_channel"
+	}
+	importDryRunSave(s.line, entity, s.line.channelName(channel), old, channel, "channel:"+channel.Id, "channel_name:"+channel.TeamId+"/"+channel.Name)
+}
+
+func (s *importDryRunChannelStore) GetMember(ctx context.Context, channelID, userID string) (*model.ChannelMember, error) {
+	if member, ok := s.line.channelMembers[channelID+"/"+userID]; ok {
+		if member == nil {
+			return nil, store.NewErrNotFound("ChannelMember", channelID+"/"+userID)
+		}
+		c := *member
+		return &c, nil
+	}
+	return s.ChannelStore.GetMember(ctx, channelID, userID)
+}
+
+func (s *importDryRunChannelStore) GetMembersForUser(teamID, userID string) (model.ChannelMembers, error) {
+	members, err := s.ChannelStore.GetMembersForUser(teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range s.line.channelMembers {
+		if member == nil || member.UserId != userID {
+			continue
+		}
+		if channel, err := s.Get(member.ChannelId, true); err == nil && channel.TeamId == teamID {
+			members = append(members, *member)
+		}
+	}
+	return members, nil
+}
+
+func (s *importDryRunChannelStore) GetMembers(channelID string, offset, limit int) (model.ChannelMembers, error) {
+	members, err := s.ChannelStore.GetMembers(channelID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		return members, nil
+	}
+	for key, member := range s.line.channelMembers {
+		if member == nil || member.ChannelId != channelID {
+			continue
+		}
+		if _, err := s.ChannelStore.GetMember(s.line.rctx.Context(), channelID, member.UserId); err != nil {
+			members = append(members, *s.line.channelMembers[key])
+		}
+	}
+	return members, nil
+}
+
+func (s *importDryRunChannelStore) GetMemberCount(channelID string, allowFromCache bool) (int64, error) {
+	members, err := s.GetMembers(channelID, 0, model.ChannelGroupMaxUsers)
+	return int64(len(members)), err
+}
+
+func (s *importDryRunChannelStore) SaveMultipleMembers(members []*model.ChannelMember) ([]*model.ChannelMember, error) {
+	for _, member := range members {
+		s.saveMember(member)
+	}
+	return members, nil
+}
+
+func (s *importDryRunChannelStore) UpdateMultipleMembers(members []*model.ChannelMember) ([]*model.ChannelMember, error) {
+	for _, member := range members {
+		s.saveMember(member)
+	}
+	return members, nil
+}
+
+func (s *importDryRunChannelStore) UpdateMember(rctx request.CTX, member *model.ChannelMember) (*model.ChannelMember, error) {
+	s.saveMember(member)
+	return member, nil
+}
+
+func (s *importDryRunChannelStore) RemoveMember(rctx request.CTX, channelID, userID string) error {
+	member, err := s.GetMember(rctx.Context(), channelID, userID)
+	if err != nil {
+		return err
+	}
+	s.line.channelMembers[channelID+"/"+userID] = nil
+	if name, ok := s.memberName(member); ok {
+		s.line.record("channel_member", "channel_member:"+channelID+"/"+userID, name, member, nil)
+	}
+	return nil
+}
+
+// saveMember keeps the member, the members of the direct and group channels being part of the channel in the report.
+func (s *importDryRunChannelStore) saveMember(member *model.ChannelMember) {
+	key := member.ChannelId + "/" + member.UserId
+	var old any
+	if _, ok := s.line.channelMembers[key]; !ok {
+		if existing, err := s.ChannelStore.GetMember(s.line.rctx.Context(), member.ChannelId, member.UserId); err == nil {
+			old = existing
+		}
+	}
+
+	c := *member
+	s.line.channelMembers[key] = &c
+	if name, ok := s.memberName(member); ok {
+		s.line.record("channel_member", "channel_member:"+key, name, old, &c)
+	}
+}
+
+// memberName returns the name of the member of a team channel.
+func (s *importDryRunChannelStore) memberName(member *model.ChannelMember) (string, bool) {
+	channel, err := s.Get(member.ChannelId, true)
+	if err != nil || channel.TeamId == "" {
+		return "", false
+	}
+	return s.line.channelName(channel) + "/" + s.line.username(member.UserId), true
+}
+
+type importDryRunUserStore struct {
+	store.UserStore
+	line *importDryRunLine
+}
+
+func (s *importDryRunUserStore) Get(ctx context.Context, id string) (*model.User, error) {
+	if user := importDryRunGet[model.User](s.line.dryRun, "user:"+id); user != nil {
+		return user, nil
+	}
+	return s.UserStore.Get(ctx, id)
+}
+
+func (s *importDryRunUserStore) GetByUsername(username string) (*model.User, error) {
+	if user := importDryRunGet[model.User](s.line.dryRun, "username:"+username); user != nil {
+		return user, nil
+	}
+	return s.UserStore.GetByUsername(username)
+}
+
+func (s *importDryRunUserStore) GetProfilesByUsernames(usernames []string, viewRestrictions *model.ViewUsersRestrictions) ([]*model.User, error) {
+	var users []*model.User
+	var remaining []string
+	for _, username := range usernames {
+		if user := importDryRunGet[model.User](s.line.dryRun, "username:"+username); user != nil {
+			users = append(users, user)
+		} else {
+			remaining = append(remaining, username)
+		}
+	}
+	if len(remaining) > 0 {
+		found, err := s.UserStore.GetProfilesByUsernames(remaining, viewRestrictions)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, found...)
+	}
+	return users, nil
+}
+
+func (s *importDryRunUserStore) Save(rctx request.CTX, user *model.User) (*model.User, error) {
+	if appErr := user.PreSave(); appErr != nil {
+		return nil, appErr
+	}
+	if appErr := user.IsValid(); appErr != nil {
+		return nil, appErr
+	}
+	if user.IsBot {
+		// The user of a bot is part of the bot in the report.
+		importDryRunKeep(s.line.dryRun, user, "user:"+user.Id, "username:"+user.Username)
+		return user, nil
+	}
+	importDryRunSave(s.line, "user", user.Username, nil, user, "user:"+user.Id, "username:"+user.Username)
+	return user, nil
+}
+
+func (s *importDryRunUserStore) Update(rctx request.CTX, user *model.User, allowRoleUpdate bool) (*model.UserUpdate, error) {
+	old, err := s.Get(rctx.Context(), user.Id)
+	if err != nil {
+		return nil, err
+	}
+	if !allowRoleUpdate {
+		user.Roles = old.Roles
+	}
+	s.save(old, user)
+	return &model.UserUpdate{Old: old, New: user}, nil
+}
+
+func (s *importDryRunUserStore) UpdateAuthData(userID, service string, authData *string, email string, resetMfa bool) (string, error) {
+	user, err := s.Get(s.line.rctx.Context(), userID)
+	if err != nil {
+		return "", err
+	}
+	old := *user
+	user.AuthService = service
+	user.AuthData = authData
+	if email != "" {
+		user.Email = email
+	}
+	s.save(&old, user)
+	return userID, nil
+}
+
+func (s *importDryRunUserStore) VerifyEmail(userID, email string) (string, error) {
+	user, err := s.Get(s.line.rctx.Context(), userID)
+	if err != nil {
+		return "", err
+	}
+	old := *user
+	user.EmailVerified = true
+	user.Email = email
+	s.save(&old, user)
+	return userID, nil
+}
+
+func (s *importDryRunUserStore) save(old, user *model.User) {
+	if user.IsBot {
+		importDryRunKeep(s.line.dryRun, user, "user:"+user.Id, "username:"+user.Username)
+		return
+	}
+	importDryRunSave(s.line, "user", user.Username, old, user, "user:"+user.Id, "username:"+user.Username)
+}
+
+type importDryRunBotStore struct {
+	store.BotStore
+	line *importDryRunLine
+}
+
+func (s *importDryRunBotStore) Get(userID string, includeDeleted bool) (*model.Bot, error) {
+	if bot := importDryRunGet[model.Bot](s.line.dryRun, "bot:"+userID); bot != nil {
+		return bot, nil
+	}
+	return s.BotStore.Get(userID, includeDeleted)
+}
+
+func (s *importDryRunBotStore) GetByUsername(username string) (*model.Bot, error) {
+	if bot := importDryRunGet[model.Bot](s.line.dryRun, "bot_username:"+username); bot != nil {
+		return bot, nil
+	}
+	return s.BotStore.GetByUsername(username)
+}
+
+func (s *importDryRunBotStore) Save(bot *model.Bot) (*model.Bot, error) {
+	bot.PreSave()
+	importDryRunSave(s.line, "bot", bot.Username, nil, bot, "bot:"+bot.UserId, "bot_username:"+bot.Username)
+	return bot, nil
+}
+
+func (s *importDryRunBotStore) Update(bot *model.Bot) (*model.Bot, error) {
+	old, err := s.Get(bot.UserId, true)
+	if err != nil {
+		return nil, err
+	}
+	importDryRunSave(s.line, "bot", bot.Username, old, bot, "bot:"+bot.UserId, "bot_username:"+bot.Username)
+	return bot, nil
+}
+
+type importDryRunEmojiStore struct {
+	store.EmojiStore
+	line *importDryRunLine
+}
+
+func (s *importDryRunEmojiStore) GetByName(c request.CTX, name string, allowFromCache bool) (*model.Emoji, error) {
+	if emoji := importDryRunGet[model.Emoji](s.line.dryRun, "emoji_name:"+name); emoji != nil {
+		if emoji.DeleteAt != 0 {
+			return nil, store.NewErrNotFound("Emoji", name)
+		}
+		return emoji, nil
+	}
+	return s.EmojiStore.GetByName(c, name, allowFromCache)
+}
+
+func (s *importDryRunEmojiStore) Save(emoji *model.Emoji) (*model.Emoji, error) {
+	emoji.PreSave()
+	importDryRunSave(s.line, "emoji", emoji.Name, nil, emoji, "emoji:"+emoji.Id, "emoji_name:"+emoji.Name)
+	return emoji, nil
+}
+
+func (s *importDryRunEmojiStore) Delete(emoji *model.Emoji, timestamp int64) error {
+	old := *emoji
+	emoji.DeleteAt = timestamp
+	importDryRunSave(s.line, "emoji", emoji.Name, &old, emoji, "emoji:"+emoji.Id, "emoji_name:"+emoji.Name)
+	return nil
+}
+
+// importDryRunPostStore keeps the posts changed by the line for the line only: the posts aren't referenced by the
+// other lines.
+type importDryRunPostStore struct {
+	store.PostStore
+	line *importDryRunLine
+}
+
+func (s *importDryRunPostStore) SaveMultiple(posts []*model.Post) ([]*model.Post, int, error) {
+	for _, post := range posts {
+		post.PreSave()
+		s.record(nil, post)
+	}
+	return posts, -1, nil
+}
+
+func (s *importDryRunPostStore) OverwriteMultiple(posts []*model.Post) ([]*model.Post, int, error) {
+	for _, post := range posts {
+		old, err := s.PostStore.GetSingle(s.line.rctx, post.Id, true)
+		if err != nil {
+			return nil, 0, err
+		}
+		s.record(old, post)
+	}
+	return posts, -1, nil
+}
+
+func (s *importDryRunPostStore) Delete(rctx request.CTX, postID string, timestamp int64, deleteByID string) error {
+	post, err := s.PostStore.GetSingle(rctx, postID, true)
+	if err != nil {
+		return err
+	}
+	old := post.Clone()
+	post.DeleteAt = timestamp
+	s.record(old, post)
+	return nil
+}
+
+// record records the change of the post of the line, or of a reply to it.
+func (s *importDryRunPostStore) record(old, post *model.Post) {
+	entity, name := s.line.primary()
+	if post.RootId != "" && s.line.line.Type != "delete" {
+		entity = "reply"
+		name = fmt.Sprintf("%s/%s/%d", name, s.line.username(post.UserId), post.CreateAt)
+	}
+
+	c := post.Clone()
+	if old == nil {
+		s.line.record(entity, "post:"+post.Id, name, nil, c)
+		return
+	}
+	s.line.record(entity, "post:"+post.Id, name, old, c)
+}
+
+type importDryRunPreferenceStore struct {
+	store.PreferenceStore
+}
+
+func (s *importDryRunPreferenceStore) Save(preferences model.Preferences) error {
+	return nil
+}
+
+type importDryRunReactionStore struct {
+	store.ReactionStore
+}
+
+func (s *importDryRunReactionStore) Save(reaction *model.Reaction) (*model.Reaction, error) {
+	return reaction, nil
+}
+
+type importDryRunThreadStore struct {
+	store.ThreadStore
+}
+
+func (s *importDryRunThreadStore) SaveMultipleMemberships(memberships []*model.ThreadMembership) ([]*model.ThreadMembership, error) {
+	return memberships, nil
+}
+
+func (s *importDryRunThreadStore) MaintainMultipleFromImport(memberships []*model.ThreadMembership) ([]*model.ThreadMembership, error) {
+	return memberships, nil
+}
+
+type importDryRunFileInfoStore struct {
+	store.FileInfoStore
+}
+
+func (s *importDryRunFileInfoStore) AttachToPost(c request.CTX, fileID, postID, channelID, creatorID string) error {
+	return nil
+}
+
+func (s *importDryRunFileInfoStore) PermanentDelete(c request.CTX, fileID string) error {
+	return nil
+}
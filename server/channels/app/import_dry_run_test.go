@@ -0,0 +1,125 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestImportDryRunMemberRoles(t *testing.T) {
+	assert.Equal(t, "team_admin team_user", memberRoles("", false, true, true, model.TeamGuestRoleId, model.TeamUserRoleId, model.TeamAdminRoleId))
+	assert.Equal(t, "custom team_guest", memberRoles("custom", true, false, false, model.TeamGuestRoleId, model.TeamUserRoleId, model.TeamAdminRoleId))
+}
+
+func TestImportDryRunBulkImport(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	teamName := model.NewRandomTeamName()
+	channelName := model.NewId()
+	newChannelName := model.NewId()
+	username := model.NewUsername()
+	newUsername := model.NewUsername()
+
+	data := `{"type": "version", "version": 1}
+{"type": "team", "team": {"type": "O", "display_name": "Team", "name": "` + teamName + `"}}
+{"type": "channel", "channel": {"type": "O", "display_name": "Channel", "team": "` + teamName + `", "name": "` + channelName + `"}}
+{"type": "user", "user": {"username": "` + username + `", "email": "` + username + `@example.com", "teams": [{"name": "` + teamName + `", "roles": "team_user team_admin", "channels": [{"name": "` + channelName + `", "roles": "channel_user channel_admin"}]}]}}
+{"type": "post", "post": {"team": "` + teamName + `", "channel": "` + channelName + `", "user": "` + username + `", "message": "Hello", "create_at": 123456789012}}`
+
+	err, _ := th.App.BulkImport(th.Context, strings.NewReader(data), nil, false, 2)
+	require.Nil(t, err)
+
+	changed := `{"type": "version", "version": 1}
+{"type": "team", "team": {"type": "O", "display_name": "Team", "name": "` + teamName + `"}}
+{"type": "channel", "channel": {"type": "O", "display_name": "Renamed", "team": "` + teamName + `", "name": "` + channelName + `"}}
+{"type": "channel", "channel": {"type": "O", "display_name": "New Channel", "team": "` + teamName + `", "name": "` + newChannelName + `"}}
+{"type": "user", "user": {"username": "` + username + `", "email": "` + username + `@example.com", "teams": [{"name": "` + teamName + `", "roles": "team_user team_admin", "channels": [{"name": "` + channelName + `"}]}]}}
+{"type": "user", "user": {"username": "` + newUsername + `", "email": "` + newUsername + `@example.com", "teams": [{"name": "` + teamName + `", "channels": [{"name": "` + newChannelName + `"}]}]}}
+{"type": "post", "post": {"team": "` + teamName + `", "channel": "` + channelName + `", "user": "` + username + `", "message": "Hello", "create_at": 123456789012}}
+{"type": "post", "post": {"team": "` + teamName + `", "channel": "` + newChannelName + `", "user": "` + newUsername + `", "message": "Hi", "create_at": 123456789013}}`
+
+	report, err, line := th.App.DryRunBulkImportWithPath(th.Context, strings.NewReader(changed), nil, 2, "")
+	require.Nil(t, err)
+	require.Equal(t, 0, line)
+
+	assert.Equal(t, &model.ImportDryRunCounts{NoOp: 1}, report.Entities["team"])
+	assert.Equal(t, &model.ImportDryRunCounts{Create: 1, Update: 1}, report.Entities["channel"])
+	assert.Equal(t, &model.ImportDryRunCounts{Create: 1, NoOp: 1}, report.Entities["user"])
+	assert.Equal(t, &model.ImportDryRunCounts{Create: 1, NoOp: 1}, report.Entities["team_member"])
+	assert.Equal(t, &model.ImportDryRunCounts{Create: 2, Update: 1, NoOp: 1}, report.Entities["channel_member"])
+	assert.Equal(t, &model.ImportDryRunCounts{Create: 1, NoOp: 1}, report.Entities["post"])
+	assert.Zero(t, report.TruncatedChanges)
+
+	changes := map[string]model.ImportDryRunChange{}
+	for _, change := range report.Changes {
+		changes[change.Entity+" "+change.Name] = change
+	}
+	renamed := changes["channel "+teamName+"/"+channelName]
+	assert.Equal(t, model.ImportDryRunActionUpdate, renamed.Action)
+	assert.Equal(t, 3, renamed.Line)
+	assert.Equal(t, []model.ImportDryRunField{{Name: "display_name", Old: "Channel", New: "Renamed"}}, renamed.Fields)
+	assert.Equal(t, model.ImportDryRunActionCreate, changes["channel "+teamName+"/"+newChannelName].Action)
+	demoted := changes["channel_member "+teamName+"/"+channelName+"/"+username]
+	assert.Equal(t, model.ImportDryRunActionUpdate, demoted.Action)
+	assert.Equal(t, []model.ImportDryRunField{{Name: "roles", Old: "channel_admin channel_user", New: "channel_user"}}, demoted.Fields)
+	assert.Equal(t, model.ImportDryRunActionCreate, changes["user "+newUsername].Action)
+
+	// Nothing is changed.
+	team, appErr := th.App.GetTeamByName(teamName)
+	require.Nil(t, appErr)
+	channel, appErr := th.App.GetChannelByName(th.Context, channelName, team.Id, false)
+	require.Nil(t, appErr)
+	assert.Equal(t, "Channel", channel.DisplayName)
+	_, appErr = th.App.GetChannelByName(th.Context, newChannelName, team.Id, false)
+	require.NotNil(t, appErr)
+	_, appErr = th.App.GetUserByUsername(newUsername)
+	require.NotNil(t, appErr)
+
+	t.Run("tombstones", func(t *testing.T) {
+		emoji, nErr := th.App.Srv().Store().Emoji().Save(&model.Emoji{CreatorId: th.BasicUser.Id, Name: model.NewId()})
+		require.NoError(t, nErr)
+
+		data := `{"type": "version", "version": 1}
+{"type": "delete", "delete": {"entity": "team_member", "team": "` + teamName + `", "user": "` + username + `", "delete_at": 123456789014}}
+{"type": "delete", "delete": {"entity": "channel_member", "team": "` + teamName + `", "channel": "` + channelName + `", "user": "` + username + `", "delete_at": 123456789014}}
+{"type": "delete", "delete": {"entity": "emoji", "name": "` + emoji.Name + `", "delete_at": 123456789014}}`
+
+		report, err, line := th.App.DryRunBulkImportWithPath(th.Context, strings.NewReader(data), nil, 2, "")
+		require.Nil(t, err)
+		require.Equal(t, 0, line)
+
+		assert.Equal(t, &model.ImportDryRunCounts{Delete: 1}, report.Entities["team_member"])
+		assert.Equal(t, &model.ImportDryRunCounts{Delete: 1}, report.Entities["channel_member"])
+		assert.Equal(t, &model.ImportDryRunCounts{Delete: 1}, report.Entities["emoji"])
+
+		// Nothing is deleted.
+		user, appErr := th.App.GetUserByUsername(username)
+		require.Nil(t, appErr)
+		member, appErr := th.App.GetTeamMember(th.Context, team.Id, user.Id)
+		require.Nil(t, appErr)
+		assert.Zero(t, member.DeleteAt)
+		_, appErr = th.App.GetChannelMember(th.Context, channel.Id, user.Id)
+		require.Nil(t, appErr)
+		_, nErr = th.App.Srv().Store().Emoji().GetByName(th.Context, emoji.Name, false)
+		require.NoError(t, nErr)
+	})
+
+	t.Run("invalid reference", func(t *testing.T) {
+		data := `{"type": "version", "version": 1}
+{"type": "channel", "channel": {"type": "O", "display_name": "Channel", "team": "` + model.NewRandomTeamName() + `", "name": "` + channelName + `"}}`
+
+		report, err, line := th.App.DryRunBulkImportWithPath(th.Context, strings.NewReader(data), nil, 2, "")
+		require.NotNil(t, err)
+		assert.Equal(t, "app.import.import_channel.team_not_found.error", err.Id)
+		assert.Equal(t, 2, line)
+		assert.Empty(t, report.Changes)
+	})
+}
@@ -5,7 +5,6 @@ package app
 
 import (
 	"bytes"
-	"context"
 	"crypto/sha256"
 	"errors"
 	"fmt"
@@ -20,7 +19,6 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/mattermost/mattermost/server/public/shared/request"
 	"github.com/mattermost/mattermost/server/v8/channels/app/imports"
-	"github.com/mattermost/mattermost/server/v8/channels/app/teams"
 	"github.com/mattermost/mattermost/server/v8/channels/app/users"
 	"github.com/mattermost/mattermost/server/v8/channels/store"
 	"github.com/mattermost/mattermost/server/v8/channels/utils"
@@ -47,7 +45,7 @@ func (a *App) importScheme(rctx request.CTX, data *imports.SchemeImportData, dry
 
 	rctx.Logger().Info("Importing scheme", fields...)
 
-	scheme, err := a.GetSchemeByName(*data.Name)
+	scheme, err := a.getImportSchemeByName(rctx, *data.Name)
 	if err != nil {
 		scheme = new(model.Scheme)
 	} else if scheme.Scope != *data.Scope {
@@ -62,11 +60,7 @@ func (a *App) importScheme(rctx request.CTX, data *imports.SchemeImportData, dry
 		scheme.Description = *data.Description
 	}
 
-	if scheme.Id == "" {
-		scheme, err = a.CreateScheme(scheme)
-	} else {
-		scheme, err = a.UpdateScheme(scheme)
-	}
+	scheme, err = a.importSaveScheme(rctx, scheme)
 
 	if err != nil {
 		return err
@@ -140,7 +134,7 @@ func (a *App) importRole(rctx request.CTX, data *imports.RoleImportData, dryRun
 
 	rctx.Logger().Info("Importing role", fields...)
 
-	role, err := a.GetRoleByName(context.Background(), *data.Name)
+	role, err := a.getImportRoleByName(rctx, *data.Name)
 	if err != nil {
 		role = new(model.Role)
 	}
@@ -163,13 +157,7 @@ func (a *App) importRole(rctx request.CTX, data *imports.RoleImportData, dryRun
 		role.SchemeManaged = *data.SchemeManaged
 	}
 
-	if role.Id == "" {
-		_, err = a.CreateRole(role)
-	} else {
-		_, err = a.UpdateRole(role)
-	}
-
-	return err
+	return a.importSaveRole(rctx, role)
 }
 
 func (a *App) importTeam(rctx request.CTX, data *imports.TeamImportData, dryRun bool) *model.AppError {
@@ -192,7 +180,7 @@ func (a *App) importTeam(rctx request.CTX, data *imports.TeamImportData, dryRun
 	teamName := strings.ToLower(*data.Name)
 
 	var team *model.Team
-	team, err := a.Srv().Store().Team().GetByName(teamName)
+	team, err := a.importStore(rctx).Team().GetByName(teamName)
 
 	if err != nil {
 		team = &model.Team{
@@ -212,7 +200,7 @@ func (a *App) importTeam(rctx request.CTX, data *imports.TeamImportData, dryRun
 	}
 
 	if data.Scheme != nil {
-		scheme, err := a.GetSchemeByName(*data.Scheme)
+		scheme, err := a.getImportSchemeByName(rctx, *data.Scheme)
 		if err != nil {
 			return err
 		}
@@ -229,11 +217,11 @@ func (a *App) importTeam(rctx request.CTX, data *imports.TeamImportData, dryRun
 	}
 
 	if team.Id == "" {
-		if _, err := a.CreateTeam(rctx, team); err != nil {
+		if err := a.importCreateTeam(rctx, team); err != nil {
 			return err
 		}
 	} else {
-		if _, err := a.ch.srv.teamService.UpdateTeam(team, teams.UpdateOptions{Imported: true}); err != nil {
+		if err := a.importUpdateTeam(rctx, team); err != nil {
 			var invErr *store.ErrInvalidInput
 			var nfErr *store.ErrNotFound
 			switch {
@@ -271,13 +259,13 @@ func (a *App) importChannel(rctx request.CTX, data *imports.ChannelImportData, d
 
 	rctx.Logger().Info("Importing channel", fields...)
 
-	team, err := a.Srv().Store().Team().GetByName(teamName)
+	team, err := a.importStore(rctx).Team().GetByName(teamName)
 	if err != nil {
 		return model.NewAppError("BulkImport", "app.import.import_channel.team_not_found.error", map[string]any{"TeamName": teamName}, "", http.StatusBadRequest).Wrap(err)
 	}
 
 	var channel *model.Channel
-	if result, gErr := a.Srv().Store().Channel().GetByNameIncludeDeleted(team.Id, channelName, true); gErr == nil {
+	if result, gErr := a.importStore(rctx).Channel().GetByNameIncludeDeleted(team.Id, channelName, true); gErr == nil {
 		channel = result
 	} else {
 		channel = &model.Channel{
@@ -298,7 +286,7 @@ func (a *App) importChannel(rctx request.CTX, data *imports.ChannelImportData, d
 	}
 
 	if data.Scheme != nil {
-		scheme, err := a.GetSchemeByName(*data.Scheme)
+		scheme, err := a.getImportSchemeByName(rctx, *data.Scheme)
 		if err != nil {
 			return err
 		}
@@ -316,17 +304,17 @@ func (a *App) importChannel(rctx request.CTX, data *imports.ChannelImportData, d
 
 	var chErr *model.AppError
 	if channel.Id == "" {
-		if _, chErr = a.CreateChannel(rctx, channel, false); chErr != nil {
+		if chErr = a.importCreateChannel(rctx, channel); chErr != nil {
 			return chErr
 		}
 	} else {
-		if _, chErr = a.UpdateChannel(rctx, channel); chErr != nil {
+		if chErr = a.importUpdateChannel(rctx, channel); chErr != nil {
 			return chErr
 		}
 	}
 
 	if data.DeletedAt != nil && *data.DeletedAt > 0 {
-		if err := a.Srv().Store().Channel().Delete(channel.Id, *data.DeletedAt); err != nil {
+		if err := a.importStore(rctx).Channel().Delete(channel.Id, *data.DeletedAt); err != nil {
 			return model.NewAppError("BulkImport", "app.import.import_channel.deleting.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 		}
 	}
@@ -361,7 +349,7 @@ func (a *App) importUser(rctx request.CTX, data *imports.UserImportData, dryRun
 
 	var user *model.User
 	var nErr error
-	user, nErr = a.Srv().Store().User().GetByUsername(*data.Username)
+	user, nErr = a.importStore(rctx).User().GetByUsername(*data.Username)
 	if nErr != nil {
 		user = &model.User{}
 		user.MakeNonNil()
@@ -551,7 +539,7 @@ func (a *App) importUser(rctx request.CTX, data *imports.UserImportData, dryRun
 	var savedUser *model.User
 	var err error
 	if user.Id == "" {
-		if savedUser, err = a.ch.srv.userService.CreateUser(rctx, user, users.UserCreateOptions{FromImport: true}); err != nil {
+		if savedUser, err = a.importCreateUser(rctx, user); err != nil {
 			var appErr *model.AppError
 			var invErr *store.ErrInvalidInput
 			switch {
@@ -576,36 +564,36 @@ func (a *App) importUser(rctx request.CTX, data *imports.UserImportData, dryRun
 		}
 
 		pref := model.Preference{UserId: savedUser.Id, Category: model.PreferenceCategoryTutorialSteps, Name: savedUser.Id, Value: "0"}
-		if err := a.Srv().Store().Preference().Save(model.Preferences{pref}); err != nil {
+		if err := a.importStore(rctx).Preference().Save(model.Preferences{pref}); err != nil {
 			rctx.Logger().Warn("Encountered error saving tutorial preference", mlog.Err(err))
 		}
 	} else {
 		var appErr *model.AppError
 		if hasUserChanged {
-			if savedUser, appErr = a.UpdateUser(rctx, user, false); appErr != nil {
+			if savedUser, appErr = a.importUpdateUser(rctx, user); appErr != nil {
 				return appErr
 			}
 		}
 		if hasUserRolesChanged {
-			if savedUser, appErr = a.UpdateUserRoles(rctx, user.Id, roles, false); appErr != nil {
+			if savedUser, appErr = a.importUpdateUserRoles(rctx, user.Id, roles); appErr != nil {
 				return appErr
 			}
 		}
 		if hasNotifyPropsChanged {
-			if appErr = a.updateUserNotifyProps(user.Id, user.NotifyProps); appErr != nil {
+			if appErr = a.importUpdateUserNotifyProps(rctx, user.Id, user.NotifyProps); appErr != nil {
 				return appErr
 			}
-			if savedUser, appErr = a.GetUser(user.Id); appErr != nil {
+			if savedUser, appErr = a.getImportUser(rctx, user.Id); appErr != nil {
 				return appErr
 			}
 		}
 		if password != "" {
-			if appErr = a.UpdatePassword(rctx, user, password); appErr != nil {
+			if appErr = a.importUpdatePassword(rctx, user, password); appErr != nil {
 				return appErr
 			}
 		} else {
 			if hasUserAuthDataChanged {
-				if _, nErr := a.Srv().Store().User().UpdateAuthData(user.Id, authService, authData, user.Email, false); nErr != nil {
+				if _, nErr := a.importStore(rctx).User().UpdateAuthData(user.Id, authService, authData, user.Email, false); nErr != nil {
 					var invErr *store.ErrInvalidInput
 					switch {
 					case errors.As(nErr, &invErr):
@@ -618,7 +606,7 @@ func (a *App) importUser(rctx request.CTX, data *imports.UserImportData, dryRun
 		}
 		if emailVerified {
 			if hasUserEmailVerifiedChanged {
-				if err := a.VerifyUserEmail(user.Id, user.Email); err != nil {
+				if err := a.importVerifyUserEmail(rctx, user.Id, user.Email); err != nil {
 					return err
 				}
 			}
@@ -826,7 +814,7 @@ func (a *App) importUser(rctx request.CTX, data *imports.UserImportData, dryRun
 	}
 
 	if len(preferences) > 0 {
-		if err := a.Srv().Store().Preference().Save(preferences); err != nil {
+		if err := a.importStore(rctx).Preference().Save(preferences); err != nil {
 			return model.NewAppError("BulkImport", "app.import.import_user.save_preferences.error", nil, "", http.StatusInternalServerError).Wrap(err)
 		}
 	}
@@ -857,7 +845,7 @@ func (a *App) importBot(rctx request.CTX, data *imports.BotImportData, dryRun bo
 
 	var bot *model.Bot
 	var nErr error
-	bot, nErr = a.Srv().Store().Bot().GetByUsername(*data.Username)
+	bot, nErr = a.importStore(rctx).Bot().GetByUsername(*data.Username)
 	if nErr != nil {
 		bot = &model.Bot{}
 		hasBotChanged = true
@@ -877,7 +865,7 @@ func (a *App) importBot(rctx request.CTX, data *imports.BotImportData, dryRun bo
 
 	var owner *model.User
 	if data.Owner != nil {
-		owner, nErr = a.Srv().Store().User().GetByUsername(*data.Owner)
+		owner, nErr = a.importStore(rctx).User().GetByUsername(*data.Owner)
 		if nErr != nil {
 			var nfErr *store.ErrNotFound
 			switch {
@@ -895,7 +883,7 @@ func (a *App) importBot(rctx request.CTX, data *imports.BotImportData, dryRun bo
 	var savedBot *model.Bot
 	if bot.UserId == "" {
 		var appErr *model.AppError
-		if savedBot, appErr = a.CreateBot(rctx, bot); appErr != nil {
+		if savedBot, appErr = a.importCreateBot(rctx, bot); appErr != nil {
 			var appErr *model.AppError
 			var invErr *store.ErrInvalidInput
 			switch {
@@ -912,7 +900,7 @@ func (a *App) importBot(rctx request.CTX, data *imports.BotImportData, dryRun bo
 		}
 	} else if hasBotChanged {
 		var err error
-		if savedBot, err = a.Srv().Store().Bot().Update(bot); err != nil {
+		if savedBot, err = a.importStore(rctx).Bot().Update(bot); err != nil {
 			return model.NewAppError("importBot", "app.bot.update.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 		}
 	}
@@ -963,6 +951,9 @@ func (a *App) importProfileImage(rctx request.CTX, userID string, data *imports.
 		if limitErr := checkImageLimits(file, *a.Config().FileSettings.MaxImageResolution); limitErr != nil {
 			return model.NewAppError("SetProfileImage", "api.user.upload_profile_user.check_image_limits.app_error", nil, "", http.StatusBadRequest)
 		}
+		if isImportDryRun(rctx) {
+			return nil
+		}
 		if err := a.SetProfileImageFromFile(rctx, userID, file); err != nil {
 			rctx.Logger().Warn("Unable to set the profile image from a file.", mlog.Err(err))
 		}
@@ -980,7 +971,7 @@ func (a *App) importUserTeams(rctx request.CTX, user *model.User, data *[]import
 	for _, tdata := range *data {
 		teamNames = append(teamNames, *tdata.Name)
 	}
-	allTeams, err := a.getTeamsByNames(teamNames)
+	allTeams, err := a.getTeamsByNames(rctx, teamNames)
 	if err != nil {
 		return err
 	}
@@ -998,7 +989,7 @@ func (a *App) importUserTeams(rctx request.CTX, user *model.User, data *[]import
 		isAdminByTeamID          = map[string]bool{}
 	)
 
-	existingMemberships, nErr := a.Srv().Store().Team().GetTeamsForUser(rctx, user.Id, "", true)
+	existingMemberships, nErr := a.importStore(rctx).Team().GetTeamsForUser(rctx, user.Id, "", true)
 	if nErr != nil {
 		return model.NewAppError("importUserTeams", "app.team.get_members.app_error", nil, "", http.StatusInternalServerError).Wrap(nErr)
 	}
@@ -1076,7 +1067,7 @@ func (a *App) importUserTeams(rctx request.CTX, user *model.User, data *[]import
 		}
 	}
 
-	oldMembers, nErr := a.Srv().Store().Team().UpdateMultipleMembers(oldTeamMembers)
+	oldMembers, nErr := a.importStore(rctx).Team().UpdateMultipleMembers(oldTeamMembers)
 	if nErr != nil {
 		var appErr *model.AppError
 		switch {
@@ -1090,7 +1081,7 @@ func (a *App) importUserTeams(rctx request.CTX, user *model.User, data *[]import
 	newMembers := []*model.TeamMember{}
 	if len(newTeamMembers) > 0 {
 		var nErr error
-		newMembers, nErr = a.Srv().Store().Team().SaveMultipleMembers(newTeamMembers, *a.Config().TeamSettings.MaxUsersPerTeam)
+		newMembers, nErr = a.importStore(rctx).Team().SaveMultipleMembers(newTeamMembers, *a.Config().TeamSettings.MaxUsersPerTeam)
 		if nErr != nil {
 			var appErr *model.AppError
 			var conflictErr *store.ErrConflict
@@ -1110,18 +1101,18 @@ func (a *App) importUserTeams(rctx request.CTX, user *model.User, data *[]import
 
 	for _, member := range append(newMembers, oldMembers...) {
 		if member.ExplicitRoles != rolesByTeamID[member.TeamId] {
-			if _, err = a.UpdateTeamMemberRoles(rctx, member.TeamId, user.Id, rolesByTeamID[member.TeamId]); err != nil {
+			if err = a.importUpdateTeamMemberRoles(rctx, member.TeamId, user.Id, rolesByTeamID[member.TeamId]); err != nil {
 				return err
 			}
 		}
 
-		a.UpdateTeamMemberSchemeRoles(rctx, member.TeamId, user.Id, isGuestByTeamID[member.TeamId], isUserByTeamId[member.TeamId], isAdminByTeamID[member.TeamId])
+		a.importUpdateTeamMemberSchemeRoles(rctx, member.TeamId, user.Id, isGuestByTeamID[member.TeamId], isUserByTeamId[member.TeamId], isAdminByTeamID[member.TeamId])
 	}
 
 	for _, team := range allTeams {
 		if len(teamThemePreferencesByID[team.Id]) > 0 {
 			pref := teamThemePreferencesByID[team.Id]
-			if err := a.Srv().Store().Preference().Save(pref); err != nil {
+			if err := a.importStore(rctx).Preference().Save(pref); err != nil {
 				return model.NewAppError("BulkImport", "app.import.import_user_teams.save_preferences.error", nil, "", http.StatusInternalServerError).Wrap(err)
 			}
 		}
@@ -1143,7 +1134,7 @@ func (a *App) importUserChannels(rctx request.CTX, user *model.User, team *model
 	for _, tdata := range *data {
 		channelNames = append(channelNames, *tdata.Name)
 	}
-	allChannels, err := a.getChannelsByNames(channelNames, team.Id)
+	allChannels, err := a.getChannelsByNames(rctx, channelNames, team.Id)
 	if err != nil {
 		return err
 	}
@@ -1160,7 +1151,7 @@ func (a *App) importUserChannels(rctx request.CTX, user *model.User, team *model
 		isAdminByChannelId       = map[string]bool{}
 	)
 
-	existingMemberships, nErr := a.Srv().Store().Channel().GetMembersForUser(team.Id, user.Id)
+	existingMemberships, nErr := a.importStore(rctx).Channel().GetMembersForUser(team.Id, user.Id)
 	if nErr != nil {
 		return model.NewAppError("importUserChannels", "app.channel.get_members.app_error", nil, "", http.StatusInternalServerError).Wrap(nErr)
 	}
@@ -1264,7 +1255,7 @@ func (a *App) importUserChannels(rctx request.CTX, user *model.User, team *model
 		}
 	}
 
-	oldMembers, nErr := a.Srv().Store().Channel().UpdateMultipleMembers(oldChannelMembers)
+	oldMembers, nErr := a.importStore(rctx).Channel().UpdateMultipleMembers(oldChannelMembers)
 	if nErr != nil {
 		var nfErr *store.ErrNotFound
 		var appErr *model.AppError
@@ -1280,7 +1271,7 @@ func (a *App) importUserChannels(rctx request.CTX, user *model.User, team *model
 
 	newMembers := []*model.ChannelMember{}
 	if len(newChannelMembers) > 0 {
-		newMembers, nErr = a.Srv().Store().Channel().SaveMultipleMembers(newChannelMembers)
+		newMembers, nErr = a.importStore(rctx).Channel().SaveMultipleMembers(newChannelMembers)
 		if nErr != nil {
 			var cErr *store.ErrConflict
 			var appErr *model.AppError
@@ -1300,18 +1291,18 @@ func (a *App) importUserChannels(rctx request.CTX, user *model.User, team *model
 
 	for _, member := range append(newMembers, oldMembers...) {
 		if member.ExplicitRoles != rolesByChannelId[member.ChannelId] {
-			if _, err = a.UpdateChannelMemberRoles(rctx, member.ChannelId, user.Id, rolesByChannelId[member.ChannelId]); err != nil {
+			if err = a.importUpdateChannelMemberRoles(rctx, member.ChannelId, user.Id, rolesByChannelId[member.ChannelId]); err != nil {
 				return err
 			}
 		}
 
-		a.UpdateChannelMemberSchemeRoles(rctx, member.ChannelId, user.Id, isGuestByChannelId[member.ChannelId], isUserByChannelId[member.ChannelId], isAdminByChannelId[member.ChannelId])
+		a.importUpdateChannelMemberSchemeRoles(rctx, member.ChannelId, user.Id, isGuestByChannelId[member.ChannelId], isUserByChannelId[member.ChannelId], isAdminByChannelId[member.ChannelId])
 	}
 
 	for _, channel := range allChannels {
 		if len(channelPreferencesByID[channel.Id]) > 0 {
 			pref := channelPreferencesByID[channel.Id]
-			if err := a.Srv().Store().Preference().Save(pref); err != nil {
+			if err := a.importStore(rctx).Preference().Save(pref); err != nil {
 				return model.NewAppError("BulkImport", "app.import.import_user_channels.save_preferences.error", nil, "", http.StatusInternalServerError).Wrap(err)
 			}
 		}
@@ -1320,14 +1311,14 @@ func (a *App) importUserChannels(rctx request.CTX, user *model.User, team *model
 	return nil
 }
 
-func (a *App) importReaction(data *imports.ReactionImportData, post *model.Post) *model.AppError {
+func (a *App) importReaction(rctx request.CTX, data *imports.ReactionImportData, post *model.Post) *model.AppError {
 	if err := imports.ValidateReactionImportData(data, post.CreateAt); err != nil {
 		return err
 	}
 
 	var user *model.User
 	var nErr error
-	if user, nErr = a.Srv().Store().User().GetByUsername(*data.User); nErr != nil {
+	if user, nErr = a.importStore(rctx).User().GetByUsername(*data.User); nErr != nil {
 		return model.NewAppError("BulkImport", "app.import.import_post.user_not_found.error", map[string]any{"Username": data.User}, "", http.StatusBadRequest).Wrap(nErr)
 	}
 
@@ -1337,7 +1328,7 @@ func (a *App) importReaction(data *imports.ReactionImportData, post *model.Post)
 		EmojiName: *data.EmojiName,
 		CreateAt:  *data.CreateAt,
 	}
-	if _, nErr = a.Srv().Store().Reaction().Save(reaction); nErr != nil {
+	if _, nErr = a.importStore(rctx).Reaction().Save(reaction); nErr != nil {
 		var appErr *model.AppError
 		switch {
 		case errors.As(nErr, &appErr):
@@ -1364,7 +1355,7 @@ func (a *App) importReplies(rctx request.CTX, data []imports.ReplyImportData, po
 		}
 	}
 
-	users, err := a.getUsersByUsernames(usernames)
+	users, err := a.getUsersByUsernames(rctx, usernames)
 	if err != nil {
 		return err
 	}
@@ -1387,18 +1378,13 @@ func (a *App) importReplies(rctx request.CTX, data []imports.ReplyImportData, po
 		user := users[strings.ToLower(*replyData.User)]
 
 		// Check if this post already exists.
-		replies, nErr := a.Srv().Store().Post().GetPostsCreatedAt(post.ChannelId, *replyData.CreateAt)
+		replies, nErr := a.importStore(rctx).Post().GetPostsCreatedAt(post.ChannelId, *replyData.CreateAt)
 		if nErr != nil {
 			return model.NewAppError("importReplies", "app.post.get_posts_created_at.app_error", nil, "", http.StatusInternalServerError).Wrap(nErr)
 		}
 
-		var reply *model.Post
-		for _, r := range replies {
-			if r.Message == *replyData.Message && r.RootId == post.Id {
-				reply = r
-				break
-			}
-		}
+		importID := postImportID(post.ChannelId, post.Id, user.Id, *replyData.CreateAt)
+		reply := findPostToImport(replies, importID, user.Id, post.Id, *replyData.Message, replyData.EditAt)
 
 		if reply == nil {
 			reply = &model.Post{}
@@ -1415,6 +1401,7 @@ func (a *App) importReplies(rctx request.CTX, data []imports.ReplyImportData, po
 		if replyData.Props != nil {
 			reply.Props = *replyData.Props
 		}
+		reply.AddProp(model.PostPropsImportId, importID)
 		if replyData.Type != nil {
 			reply.Type = *replyData.Type
 		}
@@ -1428,7 +1415,7 @@ func (a *App) importReplies(rctx request.CTX, data []imports.ReplyImportData, po
 		fileIDs := a.uploadAttachments(rctx, replyData.Attachments, reply, teamID, extractContent)
 		for _, fileID := range reply.FileIds {
 			if _, ok := fileIDs[fileID]; !ok {
-				a.Srv().Store().FileInfo().PermanentDelete(rctx, fileID)
+				a.importStore(rctx).FileInfo().PermanentDelete(rctx, fileID)
 			}
 		}
 		reply.FileIds = make([]string, 0)
@@ -1455,7 +1442,7 @@ func (a *App) importReplies(rctx request.CTX, data []imports.ReplyImportData, po
 	}
 
 	if len(postsForCreateList) > 0 {
-		postsCreated, _, err := a.Srv().Store().Post().SaveMultiple(postsForCreateList)
+		postsCreated, _, err := a.importStore(rctx).Post().SaveMultiple(postsForCreateList)
 		if err != nil {
 			var appErr *model.AppError
 			var invErr *store.ErrInvalidInput
@@ -1478,13 +1465,13 @@ func (a *App) importReplies(rctx request.CTX, data []imports.ReplyImportData, po
 		}
 	}
 
-	if _, _, nErr := a.Srv().Store().Post().OverwriteMultiple(postsForOverwriteList); nErr != nil {
+	if _, _, nErr := a.importStore(rctx).Post().OverwriteMultiple(postsForOverwriteList); nErr != nil {
 		return model.NewAppError("importReplies", "app.post.overwrite.app_error", nil, "", http.StatusInternalServerError).Wrap(nErr)
 	}
 
 	for _, postAndReactions := range reactionsForCreateMap {
 		for _, reaction := range *postAndReactions.reactions {
-			if err := a.importReaction(&reaction, postAndReactions.post); err != nil {
+			if err := a.importReaction(rctx, &reaction, postAndReactions.post); err != nil {
 				return err
 			}
 		}
@@ -1508,7 +1495,7 @@ func (a *App) importReplies(rctx request.CTX, data []imports.ReplyImportData, po
 			}
 
 			if len(preferences) > 0 {
-				if err := a.Srv().Store().Preference().Save(preferences); err != nil {
+				if err := a.importStore(rctx).Preference().Save(preferences); err != nil {
 					return model.NewAppError("BulkImport", "app.import.import_post.save_preferences.error", nil, "", http.StatusInternalServerError).Wrap(err)
 				}
 			}
@@ -1611,7 +1598,7 @@ func (a *App) importAttachment(rctx request.CTX, data *imports.AttachmentImportD
 
 	// Go over existing files in the post and see if there already exists a file with the same name, size and hash. If so - skip it
 	if post.Id != "" {
-		oldFiles, err := a.Srv().Store().FileInfo().GetForPost(post.Id, true, false, true)
+		oldFiles, err := a.importStore(rctx).FileInfo().GetForPost(post.Id, true, false, true)
 		if err != nil {
 			return nil, model.NewAppError("BulkImport", "app.import.attachment.file_upload.error", map[string]any{"FilePath": *data.Path}, "", http.StatusBadRequest)
 		}
@@ -1620,7 +1607,7 @@ func (a *App) importAttachment(rctx request.CTX, data *imports.AttachmentImportD
 				continue
 			}
 
-			oldFileReader, appErr := a.FileReader(oldFile.Path)
+			oldFileReader, appErr := a.FileInfoReader(oldFile, oldFile.Path)
 			if appErr != nil {
 				return nil, model.NewAppError("BulkImport", "app.import.attachment.file_upload.error", map[string]any{"FilePath": *data.Path}, "", http.StatusBadRequest)
 			}
@@ -1685,9 +1672,9 @@ type postAndData struct {
 	lineNumber     int
 }
 
-func (a *App) getUsersByUsernames(usernames []string) (map[string]*model.User, *model.AppError) {
+func (a *App) getUsersByUsernames(rctx request.CTX, usernames []string) (map[string]*model.User, *model.AppError) {
 	uniqueUsernames := utils.RemoveDuplicatesFromStringArray(usernames)
-	allUsers, err := a.Srv().Store().User().GetProfilesByUsernames(uniqueUsernames, nil)
+	allUsers, err := a.importStore(rctx).User().GetProfilesByUsernames(uniqueUsernames, nil)
 	if err != nil {
 		return nil, model.NewAppError("BulkImport", "app.import.get_users_by_username.some_users_not_found.error", nil, "", http.StatusBadRequest).Wrap(err)
 	}
@@ -1703,8 +1690,8 @@ func (a *App) getUsersByUsernames(usernames []string) (map[string]*model.User, *
 	return users, nil
 }
 
-func (a *App) getTeamsByNames(names []string) (map[string]*model.Team, *model.AppError) {
-	allTeams, err := a.Srv().Store().Team().GetByNames(names)
+func (a *App) getTeamsByNames(rctx request.CTX, names []string) (map[string]*model.Team, *model.AppError) {
+	allTeams, err := a.importStore(rctx).Team().GetByNames(names)
 	if err != nil {
 		return nil, model.NewAppError("BulkImport", "app.import.get_teams_by_names.some_teams_not_found.error", nil, "", http.StatusBadRequest).Wrap(err)
 	}
@@ -1716,8 +1703,8 @@ func (a *App) getTeamsByNames(names []string) (map[string]*model.Team, *model.Ap
 	return teams, nil
 }
 
-func (a *App) getChannelsByNames(names []string, teamID string) (map[string]*model.Channel, *model.AppError) {
-	allChannels, err := a.Srv().Store().Channel().GetByNamesIncludeDeleted(teamID, names, true)
+func (a *App) getChannelsByNames(rctx request.CTX, names []string, teamID string) (map[string]*model.Channel, *model.AppError) {
+	allChannels, err := a.importStore(rctx).Channel().GetByNamesIncludeDeleted(teamID, names, true)
 	if err != nil {
 		return nil, model.NewAppError("BulkImport", "app.import.get_teams_by_names.some_teams_not_found.error", nil, "", http.StatusBadRequest).Wrap(err)
 	}
@@ -1730,7 +1717,7 @@ func (a *App) getChannelsByNames(names []string, teamID string) (map[string]*mod
 }
 
 // getChannelsForPosts returns map[teamName]map[channelName]*model.Channel
-func (a *App) getChannelsForPosts(teams map[string]*model.Team, data []*imports.PostImportData) (map[string]map[string]*model.Channel, *model.AppError) {
+func (a *App) getChannelsForPosts(rctx request.CTX, teams map[string]*model.Team, data []*imports.PostImportData) (map[string]map[string]*model.Channel, *model.AppError) {
 	teamChannels := make(map[string]map[string]*model.Channel)
 	for _, postData := range data {
 		teamName := strings.ToLower(*postData.Team)
@@ -1740,7 +1727,7 @@ func (a *App) getChannelsForPosts(teams map[string]*model.Team, data []*imports.
 		channelName := strings.ToLower(*postData.Channel)
 		if channel, ok := teamChannels[teamName][channelName]; !ok || channel == nil {
 			var err error
-			channel, err = a.Srv().Store().Channel().GetByNameIncludeDeleted(teams[teamName].Id, *postData.Channel, true)
+			channel, err = a.importStore(rctx).Channel().GetByNameIncludeDeleted(teams[teamName].Id, *postData.Channel, true)
 			if err != nil {
 				return nil, model.NewAppError("BulkImport", "app.import.import_post.channel_not_found.error", map[string]any{"ChannelName": *postData.Channel}, "", http.StatusBadRequest).Wrap(err)
 			}
@@ -1753,7 +1740,7 @@ func (a *App) getChannelsForPosts(teams map[string]*model.Team, data []*imports.
 // getPostStrID returns a string ID composed of several post fields to
 // uniquely identify a post before it's imported, so it has no ID yet
 func getPostStrID(post *model.Post) string {
-	return fmt.Sprintf("%d%s%s", post.CreateAt, post.ChannelId, post.Message)
+	return fmt.Sprintf("%d%s%s%s", post.CreateAt, post.ChannelId, post.UserId, post.Message)
 }
 
 // importMultiplePostLines will return an error and the line that
@@ -1790,17 +1777,17 @@ func (a *App) importMultiplePostLines(rctx request.CTX, lines []imports.LineImpo
 		postsData[i] = line.Post
 	}
 
-	users, err := a.getUsersByUsernames(usernames)
+	users, err := a.getUsersByUsernames(rctx, usernames)
 	if err != nil {
 		return 0, err
 	}
 
-	teams, err := a.getTeamsByNames(teamNames)
+	teams, err := a.getTeamsByNames(rctx, teamNames)
 	if err != nil {
 		return 0, err
 	}
 
-	channels, err := a.getChannelsForPosts(teams, postsData)
+	channels, err := a.getChannelsForPosts(rctx, teams, postsData)
 	if err != nil {
 		return 0, err
 	}
@@ -1821,18 +1808,13 @@ func (a *App) importMultiplePostLines(rctx request.CTX, lines []imports.LineImpo
 		user := users[strings.ToLower(*line.Post.User)]
 
 		// Check if this post already exists.
-		posts, nErr := a.Srv().Store().Post().GetPostsCreatedAt(channel.Id, *line.Post.CreateAt)
+		posts, nErr := a.importStore(rctx).Post().GetPostsCreatedAt(channel.Id, *line.Post.CreateAt)
 		if nErr != nil {
 			return line.LineNumber, model.NewAppError("importMultiplePostLines", "app.post.get_posts_created_at.app_error", nil, "", http.StatusInternalServerError).Wrap(nErr)
 		}
 
-		var post *model.Post
-		for _, p := range posts {
-			if p.Message == *line.Post.Message {
-				post = p
-				break
-			}
-		}
+		importID := postImportID(channel.Id, "", user.Id, *line.Post.CreateAt)
+		post := findPostToImport(posts, importID, user.Id, "", *line.Post.Message, line.Post.EditAt)
 
 		if post == nil {
 			post = &model.Post{}
@@ -1853,11 +1835,12 @@ func (a *App) importMultiplePostLines(rctx request.CTX, lines []imports.LineImpo
 		if line.Post.Props != nil {
 			post.Props = *line.Post.Props
 		}
+		post.AddProp(model.PostPropsImportId, importID)
 		if line.Post.IsPinned != nil {
 			post.IsPinned = *line.Post.IsPinned
 		}
 		if line.Post.ThreadFollowers != nil {
-			threadMemberships, lineNumber, err := a.extractThreadMembers(&line, users, post)
+			threadMemberships, lineNumber, err := a.extractThreadMembers(rctx, &line, users, post)
 			if err != nil {
 				return lineNumber, err
 			}
@@ -1872,7 +1855,7 @@ func (a *App) importMultiplePostLines(rctx request.CTX, lines []imports.LineImpo
 		fileIDs := a.uploadAttachments(rctx, line.Post.Attachments, post, team.Id, extractContent)
 		for _, fileID := range post.FileIds {
 			if _, ok := fileIDs[fileID]; !ok {
-				a.Srv().Store().FileInfo().PermanentDelete(rctx, fileID)
+				a.importStore(rctx).FileInfo().PermanentDelete(rctx, fileID)
 			}
 		}
 		post.FileIds = make([]string, 0)
@@ -1892,7 +1875,7 @@ func (a *App) importMultiplePostLines(rctx request.CTX, lines []imports.LineImpo
 	}
 
 	if len(postsForCreateList) > 0 {
-		_, idx, nErr := a.Srv().Store().Post().SaveMultiple(postsForCreateList)
+		_, idx, nErr := a.importStore(rctx).Post().SaveMultiple(postsForCreateList)
 		if nErr != nil {
 			var appErr *model.AppError
 			var invErr *store.ErrInvalidInput
@@ -1938,7 +1921,7 @@ func (a *App) importMultiplePostLines(rctx request.CTX, lines []imports.LineImpo
 
 		// we have an assumption here is that all these memberships should be brand new because the corresponding posts
 		// do not exist in the target until the import.
-		if _, err := a.Srv().Store().Thread().SaveMultipleMemberships(membersToCreate); err != nil {
+		if _, err := a.importStore(rctx).Thread().SaveMultipleMemberships(membersToCreate); err != nil {
 			// we don't know the line number of the post that caused the error
 			// so we return 0. But at this stage, it's unlikely to receive an error
 			// due to the thread member itself, most likely it's due to the DB connection etc.
@@ -1946,7 +1929,7 @@ func (a *App) importMultiplePostLines(rctx request.CTX, lines []imports.LineImpo
 		}
 	}
 
-	if _, idx, err := a.Srv().Store().Post().OverwriteMultiple(postsForOverwriteList); err != nil {
+	if _, idx, err := a.importStore(rctx).Post().OverwriteMultiple(postsForOverwriteList); err != nil {
 		if idx != -1 && idx < len(postsForOverwriteList) {
 			post := postsForOverwriteList[idx]
 			if lineNumber, ok := postsForOverwriteMap[getPostStrID(post)]; ok {
@@ -1961,7 +1944,7 @@ func (a *App) importMultiplePostLines(rctx request.CTX, lines []imports.LineImpo
 	// MaintainMembership method has some logic within to handle those decisions. Unfortunately
 	// some application code leaked to the store layer here, which should be revisited when there
 	// is resource (eg. time, human or maybe AI).
-	if _, sErr := a.Srv().Store().Thread().MaintainMultipleFromImport(threadMembersToOverwriteList); sErr != nil {
+	if _, sErr := a.importStore(rctx).Thread().MaintainMultipleFromImport(threadMembersToOverwriteList); sErr != nil {
 		return 0, model.NewAppError("importMultiplePostLines", "app.post.save.thread_membership.app_error", nil, "", http.StatusInternalServerError).Wrap(sErr)
 	}
 
@@ -1982,7 +1965,7 @@ func (a *App) importMultiplePostLines(rctx request.CTX, lines []imports.LineImpo
 			}
 
 			if len(preferences) > 0 {
-				if err := a.Srv().Store().Preference().Save(preferences); err != nil {
+				if err := a.importStore(rctx).Preference().Save(preferences); err != nil {
 					return postWithData.lineNumber, model.NewAppError("BulkImport", "app.import.import_post.save_preferences.error", nil, "", http.StatusInternalServerError).Wrap(err)
 				}
 			}
@@ -1991,7 +1974,7 @@ func (a *App) importMultiplePostLines(rctx request.CTX, lines []imports.LineImpo
 		if postWithData.postData.Reactions != nil {
 			for _, reaction := range *postWithData.postData.Reactions {
 				reaction := reaction
-				if err := a.importReaction(&reaction, postWithData.post); err != nil {
+				if err := a.importReaction(rctx, &reaction, postWithData.post); err != nil {
 					return postWithData.lineNumber, err
 				}
 			}
@@ -2010,7 +1993,7 @@ func (a *App) importMultiplePostLines(rctx request.CTX, lines []imports.LineImpo
 
 // uploadAttachments imports new attachments and returns current attachments of the post as a map
 func (a *App) uploadAttachments(rctx request.CTX, attachments *[]imports.AttachmentImportData, post *model.Post, teamID string, extractContent bool) map[string]bool {
-	if attachments == nil {
+	if attachments == nil || isImportDryRun(rctx) {
 		return nil
 	}
 	fileIDs := make(map[string]bool)
@@ -2036,7 +2019,7 @@ func (a *App) uploadAttachments(rctx request.CTX, attachments *[]imports.Attachm
 
 func (a *App) updateFileInfoWithPostId(rctx request.CTX, post *model.Post) {
 	for _, fileID := range post.FileIds {
-		if err := a.Srv().Store().FileInfo().AttachToPost(rctx, fileID, post.Id, post.ChannelId, post.UserId); err != nil {
+		if err := a.importStore(rctx).FileInfo().AttachToPost(rctx, fileID, post.Id, post.ChannelId, post.UserId); err != nil {
 			rctx.Logger().Error("Error attaching files to post.", mlog.String("post_id", post.Id), mlog.Array("post_file_ids", post.FileIds), mlog.Err(err))
 		}
 	}
@@ -2066,7 +2049,7 @@ func (a *App) importDirectChannel(rctx request.CTX, data *imports.DirectChannelI
 	}
 
 	var userIDs []string
-	userMap, err := a.getUsersByUsernames(members)
+	userMap, err := a.getUsersByUsernames(rctx, members)
 	if err != nil {
 		return err
 	}
@@ -2077,29 +2060,29 @@ func (a *App) importDirectChannel(rctx request.CTX, data *imports.DirectChannelI
 	var channel *model.Channel
 
 	if len(userIDs) == 2 {
-		ch, err2 := a.createDirectChannel(rctx, userIDs[0], userIDs[1])
+		ch, err2 := a.importCreateDirectChannel(rctx, userIDs[0], userIDs[1])
 		if err2 != nil && err2.Id != store.ChannelExistsError {
 			return model.NewAppError("BulkImport", "app.import.import_direct_channel.create_direct_channel.error", nil, "", http.StatusBadRequest).Wrap(err2)
 		}
 		channel = ch
 	} else {
-		ch, err2 := a.createGroupChannel(rctx, userIDs)
+		ch, err2 := a.importCreateGroupChannel(rctx, userIDs)
 		if err2 != nil && err2.Id != store.ChannelExistsError {
 			return model.NewAppError("BulkImport", "app.import.import_direct_channel.create_group_channel.error", nil, "", http.StatusBadRequest).Wrap(err2)
 		}
 		channel = ch
 	}
 
-	totalMembers, err := a.GetChannelMemberCount(rctx, channel.Id)
-	if err != nil {
-		return model.NewAppError("BulkImport", "app.import.import_direct_channel.get_channel_members.error", nil, "", http.StatusBadRequest).Wrap(err)
+	totalMembers, nErr := a.importStore(rctx).Channel().GetMemberCount(channel.Id, true)
+	if nErr != nil {
+		return model.NewAppError("BulkImport", "app.import.import_direct_channel.get_channel_members.error", nil, "", http.StatusBadRequest).Wrap(nErr)
 	}
 
 	var ems = make([]model.ChannelMember, 0, totalMembers)
 	var page int
 
 	for int64(len(ems)) < totalMembers {
-		res, err := a.GetChannelMembersPage(rctx, channel.Id, page, 100)
+		res, err := a.importStore(rctx).Channel().GetMembers(channel.Id, page*100, 100)
 		if err != nil {
 			return model.NewAppError("BulkImport", "app.import.import_direct_channel.get_channel_members.error", nil, "", http.StatusBadRequest).Wrap(err)
 		}
@@ -2199,7 +2182,7 @@ func (a *App) importDirectChannel(rctx request.CTX, data *imports.DirectChannelI
 	// the channel memberships are already created in the channel creation
 	// we always going to update the channel memberships
 	if len(newChannelMembers) > 0 {
-		_, nErr := a.Srv().Store().Channel().UpdateMultipleMembers(newChannelMembers)
+		_, nErr := a.importStore(rctx).Channel().UpdateMultipleMembers(newChannelMembers)
 		if nErr != nil {
 			return model.NewAppError("BulkImport", "app.import.import_direct_channel.create_group_channel.error", nil, "", http.StatusBadRequest).Wrap(nErr)
 		}
@@ -2247,7 +2230,7 @@ func (a *App) importDirectChannel(rctx request.CTX, data *imports.DirectChannelI
 	}
 
 	if len(preferences) > 0 {
-		if err := a.Srv().Store().Preference().Save(preferences); err != nil {
+		if err := a.importStore(rctx).Preference().Save(preferences); err != nil {
 			var appErr *model.AppError
 			switch {
 			case errors.As(err, &appErr):
@@ -2261,7 +2244,7 @@ func (a *App) importDirectChannel(rctx request.CTX, data *imports.DirectChannelI
 
 	if data.Header != nil {
 		channel.Header = *data.Header
-		if _, appErr := a.Srv().Store().Channel().Update(rctx, channel); appErr != nil {
+		if _, appErr := a.importStore(rctx).Channel().Update(rctx, channel); appErr != nil {
 			return model.NewAppError("BulkImport", "app.import.import_direct_channel.update_header_failed.error", nil, "", http.StatusBadRequest).Wrap(appErr)
 		}
 	}
@@ -2296,7 +2279,7 @@ func (a *App) importMultipleDirectPostLines(rctx request.CTX, lines []imports.Li
 		usernames = append(usernames, *line.DirectPost.ChannelMembers...)
 	}
 
-	users, err := a.getUsersByUsernames(usernames)
+	users, err := a.getUsersByUsernames(rctx, usernames)
 	if err != nil {
 		return 0, err
 	}
@@ -2322,13 +2305,13 @@ func (a *App) importMultipleDirectPostLines(rctx request.CTX, lines []imports.Li
 		var channel *model.Channel
 		var ch *model.Channel
 		if len(userIDs) == 2 {
-			ch, err = a.GetOrCreateDirectChannel(rctx, userIDs[0], userIDs[1])
+			ch, err = a.importGetOrCreateDirectChannel(rctx, userIDs[0], userIDs[1])
 			if err != nil && err.Id != store.ChannelExistsError {
 				return line.LineNumber, model.NewAppError("BulkImport", "app.import.import_direct_post.create_direct_channel.error", nil, "", http.StatusBadRequest).Wrap(err)
 			}
 			channel = ch
 		} else if len(userIDs) > 2 {
-			ch, err = a.createGroupChannel(rctx, userIDs)
+			ch, err = a.importCreateGroupChannel(rctx, userIDs)
 			if err != nil && err.Id != store.ChannelExistsError {
 				return line.LineNumber, model.NewAppError("BulkImport", "app.import.import_direct_post.create_group_channel.error", nil, "", http.StatusBadRequest).Wrap(err)
 			}
@@ -2341,18 +2324,13 @@ func (a *App) importMultipleDirectPostLines(rctx request.CTX, lines []imports.Li
 		user := users[strings.ToLower(*line.DirectPost.User)]
 
 		// Check if this post already exists.
-		posts, nErr := a.Srv().Store().Post().GetPostsCreatedAt(channel.Id, *line.DirectPost.CreateAt)
+		posts, nErr := a.importStore(rctx).Post().GetPostsCreatedAt(channel.Id, *line.DirectPost.CreateAt)
 		if nErr != nil {
 			return line.LineNumber, model.NewAppError("BulkImport", "app.post.get_posts_created_at.app_error", nil, "", http.StatusInternalServerError).Wrap(nErr)
 		}
 
-		var post *model.Post
-		for _, p := range posts {
-			if p.Message == *line.DirectPost.Message {
-				post = p
-				break
-			}
-		}
+		importID := postImportID(channel.Id, "", user.Id, *line.DirectPost.CreateAt)
+		post := findPostToImport(posts, importID, user.Id, "", *line.DirectPost.Message, line.DirectPost.EditAt)
 
 		if post == nil {
 			post = &model.Post{}
@@ -2373,11 +2351,12 @@ func (a *App) importMultipleDirectPostLines(rctx request.CTX, lines []imports.Li
 		if line.DirectPost.Props != nil {
 			post.Props = *line.DirectPost.Props
 		}
+		post.AddProp(model.PostPropsImportId, importID)
 		if line.DirectPost.IsPinned != nil {
 			post.IsPinned = *line.DirectPost.IsPinned
 		}
 		if line.DirectPost.ThreadFollowers != nil {
-			threadMemberships, lineNumber, err := a.extractThreadMembers(&line, users, post)
+			threadMemberships, lineNumber, err := a.extractThreadMembers(rctx, &line, users, post)
 			if err != nil {
 				return lineNumber, err
 			}
@@ -2392,7 +2371,7 @@ func (a *App) importMultipleDirectPostLines(rctx request.CTX, lines []imports.Li
 		fileIDs := a.uploadAttachments(rctx, line.DirectPost.Attachments, post, "noteam", extractContent)
 		for _, fileID := range post.FileIds {
 			if _, ok := fileIDs[fileID]; !ok {
-				a.Srv().Store().FileInfo().PermanentDelete(rctx, fileID)
+				a.importStore(rctx).FileInfo().PermanentDelete(rctx, fileID)
 			}
 		}
 		post.FileIds = make([]string, 0)
@@ -2411,7 +2390,7 @@ func (a *App) importMultipleDirectPostLines(rctx request.CTX, lines []imports.Li
 	}
 
 	if len(postsForCreateList) > 0 {
-		if _, idx, err := a.Srv().Store().Post().SaveMultiple(postsForCreateList); err != nil {
+		if _, idx, err := a.importStore(rctx).Post().SaveMultiple(postsForCreateList); err != nil {
 			var appErr *model.AppError
 			var invErr *store.ErrInvalidInput
 			var retErr *model.AppError
@@ -2454,12 +2433,12 @@ func (a *App) importMultipleDirectPostLines(rctx request.CTX, lines []imports.Li
 			membersToCreate = append(membersToCreate, members...)
 		}
 
-		if _, err := a.Srv().Store().Thread().SaveMultipleMemberships(membersToCreate); err != nil {
+		if _, err := a.importStore(rctx).Thread().SaveMultipleMemberships(membersToCreate); err != nil {
 			return 0, model.NewAppError("importMultiplePostLines", "app.post.save.thread_membership.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 		}
 	}
 
-	if _, idx, err := a.Srv().Store().Post().OverwriteMultiple(postsForOverwriteList); err != nil {
+	if _, idx, err := a.importStore(rctx).Post().OverwriteMultiple(postsForOverwriteList); err != nil {
 		if idx != -1 && idx < len(postsForOverwriteList) {
 			post := postsForOverwriteList[idx]
 			if lineNumber, ok := postsForOverwriteMap[getPostStrID(post)]; ok {
@@ -2469,7 +2448,7 @@ func (a *App) importMultipleDirectPostLines(rctx request.CTX, lines []imports.Li
 		return 0, model.NewAppError("importMultiplePostLines", "app.post.overwrite.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 
-	if _, sErr := a.Srv().Store().Thread().MaintainMultipleFromImport(threadMembersToOverwriteList); sErr != nil {
+	if _, sErr := a.importStore(rctx).Thread().MaintainMultipleFromImport(threadMembersToOverwriteList); sErr != nil {
 		return 0, model.NewAppError("importMultiplePostLines", "app.post.save.thread_membership.app_error", nil, "", http.StatusInternalServerError).Wrap(sErr)
 	}
 
@@ -2489,7 +2468,7 @@ func (a *App) importMultipleDirectPostLines(rctx request.CTX, lines []imports.Li
 			}
 
 			if len(preferences) > 0 {
-				if err := a.Srv().Store().Preference().Save(preferences); err != nil {
+				if err := a.importStore(rctx).Preference().Save(preferences); err != nil {
 					return postWithData.lineNumber, model.NewAppError("BulkImport", "app.import.import_post.save_preferences.error", nil, "", http.StatusInternalServerError).Wrap(err)
 				}
 			}
@@ -2498,7 +2477,7 @@ func (a *App) importMultipleDirectPostLines(rctx request.CTX, lines []imports.Li
 		if postWithData.directPostData.Reactions != nil {
 			for _, reaction := range *postWithData.directPostData.Reactions {
 				reaction := reaction
-				if err := a.importReaction(&reaction, postWithData.post); err != nil {
+				if err := a.importReaction(rctx, &reaction, postWithData.post); err != nil {
 					return postWithData.lineNumber, err
 				}
 			}
@@ -2540,7 +2519,7 @@ func (a *App) importEmoji(rctx request.CTX, data *imports.EmojiImportData, dryRu
 
 	var emoji *model.Emoji
 
-	emoji, err := a.Srv().Store().Emoji().GetByName(rctx, *data.Name, true)
+	emoji, err := a.importStore(rctx).Emoji().GetByName(rctx, *data.Name, true)
 	if err != nil {
 		var nfErr *store.ErrNotFound
 		if !errors.As(err, &nfErr) {
@@ -2568,13 +2547,15 @@ func (a *App) importEmoji(rctx request.CTX, data *imports.EmojiImportData, dryRu
 	}
 	defer file.Close()
 
-	reader := utils.NewLimitedReaderWithError(file, MaxEmojiFileSize)
-	if _, err := a.WriteFile(reader, getEmojiImagePath(emoji.Id)); err != nil {
-		return err
+	if !isImportDryRun(rctx) {
+		reader := utils.NewLimitedReaderWithError(file, MaxEmojiFileSize)
+		if _, err := a.WriteFile(reader, getEmojiImagePath(emoji.Id)); err != nil {
+			return err
+		}
 	}
 
 	if !alreadyExists {
-		if _, err := a.Srv().Store().Emoji().Save(emoji); err != nil {
+		if _, err := a.importStore(rctx).Emoji().Save(emoji); err != nil {
 			return model.NewAppError("importEmoji", "api.emoji.create.internal_error", nil, "", http.StatusBadRequest).Wrap(err)
 		}
 	}
@@ -2582,7 +2563,164 @@ func (a *App) importEmoji(rctx request.CTX, data *imports.EmojiImportData, dryRu
 	return nil
 }
 
-func (a *App) extractThreadMembers(line *imports.LineImportWorkerData, users map[string]*model.User, post *model.Post) ([]*model.ThreadMembership, int, *model.AppError) {
+// importDelete applies the tombstone of an incremental export. The entities that don't exist, e.g. because they were
+// created and deleted after the previous export, are skipped.
+func (a *App) importDelete(rctx request.CTX, data *imports.DeleteImportData, dryRun bool) *model.AppError {
+	var fields []mlog.Field
+	if data != nil && data.Entity != nil {
+		fields = append(fields, mlog.String("entity", *data.Entity))
+	}
+	rctx.Logger().Info("Validating delete", fields...)
+
+	if err := imports.ValidateDeleteImportData(data); err != nil {
+		return err
+	}
+
+	// If this is a Dry Run, do not continue any further.
+	if dryRun {
+		return nil
+	}
+
+	rctx.Logger().Info("Importing delete", fields...)
+
+	// The entities are deleted like the users delete them, so that the caches, the search indexes and the clients
+	// are updated too.
+	switch *data.Entity {
+	case imports.DeleteEntityTeam:
+		team, err := a.importStore(rctx).Team().GetByName(strings.ToLower(*data.Team))
+		if err != nil || team.DeleteAt != 0 {
+			return nil
+		}
+		return a.importSoftDeleteTeam(rctx, team)
+	case imports.DeleteEntityChannel:
+		channel, appErr := a.getChannelForDelete(rctx, *data.Team, *data.Channel)
+		if appErr != nil || channel == nil || channel.DeleteAt != 0 {
+			return appErr
+		}
+		return a.importDeleteChannel(rctx, channel)
+	case imports.DeleteEntityTeamMember:
+		team, err := a.importStore(rctx).Team().GetByName(strings.ToLower(*data.Team))
+		if err != nil {
+			return nil
+		}
+		user, err := a.importStore(rctx).User().GetByUsername(strings.ToLower(*data.User))
+		if err != nil {
+			return nil
+		}
+		member, err := a.importStore(rctx).Team().GetMember(rctx, team.Id, user.Id)
+		if err != nil || member.DeleteAt != 0 {
+			// The user already left the team, or never joined it.
+			return nil
+		}
+		return a.importRemoveUserFromTeam(rctx, member)
+	case imports.DeleteEntityChannelMember:
+		channel, appErr := a.getChannelForDelete(rctx, *data.Team, *data.Channel)
+		if appErr != nil || channel == nil {
+			return appErr
+		}
+		user, err := a.importStore(rctx).User().GetByUsername(strings.ToLower(*data.User))
+		if err != nil {
+			return nil
+		}
+		if _, err = a.importStore(rctx).Channel().GetMember(rctx.Context(), channel.Id, user.Id); err != nil {
+			return nil
+		}
+		// The user left the channel on the exported server, where the system message of it was posted already.
+		return a.importRemoveUserFromChannel(rctx, user.Id, channel)
+	case imports.DeleteEntityEmoji:
+		emoji, err := a.importStore(rctx).Emoji().GetByName(rctx, *data.Name, true)
+		if err != nil {
+			return nil
+		}
+		return a.importDeleteEmoji(rctx, emoji)
+	case imports.DeleteEntityPost, imports.DeleteEntityDirectPost:
+		var channel *model.Channel
+		var appErr *model.AppError
+		if *data.Entity == imports.DeleteEntityPost {
+			channel, appErr = a.getChannelForDelete(rctx, *data.Team, *data.Channel)
+		} else {
+			channel, appErr = a.getDirectChannelForDelete(rctx, *data.ChannelMembers)
+		}
+		if appErr != nil || channel == nil {
+			return appErr
+		}
+		return a.deleteImportedPost(rctx, channel, *data.User, *data.CreateAt)
+	}
+
+	return nil
+}
+
+// getChannelForDelete returns the channel of a tombstone, or nil if the team or the channel don't exist.
+func (a *App) getChannelForDelete(rctx request.CTX, teamName, channelName string) (*model.Channel, *model.AppError) {
+	team, err := a.importStore(rctx).Team().GetByName(strings.ToLower(teamName))
+	if err != nil {
+		return nil, nil
+	}
+	channel, err := a.importStore(rctx).Channel().GetByNameIncludeDeleted(team.Id, strings.ToLower(channelName), true)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			return nil, nil
+		}
+		return nil, model.NewAppError("BulkImport", "app.import.import_delete.get_channel.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return channel, nil
+}
+
+// getDirectChannelForDelete returns the direct or group channel of a tombstone, without creating it, or nil if it
+// doesn't exist.
+func (a *App) getDirectChannelForDelete(rctx request.CTX, members []string) (*model.Channel, *model.AppError) {
+	users, appErr := a.getUsersByUsernames(rctx, members)
+	if appErr != nil {
+		// A channel of users that don't exist doesn't exist either.
+		return nil, nil
+	}
+	userIDs := make([]string, 0, len(members))
+	for _, member := range members {
+		userIDs = append(userIDs, users[strings.ToLower(member)].Id)
+	}
+
+	name := model.GetGroupNameFromUserIds(userIDs)
+	if len(userIDs) == 2 {
+		name = model.GetDMNameFromIds(userIDs[0], userIDs[1])
+	}
+	channel, err := a.importStore(rctx).Channel().GetByNameIncludeDeleted("", name, true)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			return nil, nil
+		}
+		return nil, model.NewAppError("BulkImport", "app.import.import_delete.get_channel.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return channel, nil
+}
+
+// deleteImportedPost deletes the post, or the reply, created by the user at the given time in the channel.
+func (a *App) deleteImportedPost(rctx request.CTX, channel *model.Channel, username string, createAt int64) *model.AppError {
+	users, appErr := a.getUsersByUsernames(rctx, []string{username})
+	if appErr != nil {
+		// A user that doesn't exist has no posts.
+		return nil
+	}
+	user := users[strings.ToLower(username)]
+
+	posts, err := a.importStore(rctx).Post().GetPostsCreatedAt(channel.Id, createAt)
+	if err != nil {
+		return model.NewAppError("BulkImport", "app.post.get_posts_created_at.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	for _, post := range posts {
+		if post.UserId != user.Id || post.OriginalId != "" || post.DeleteAt != 0 {
+			continue
+		}
+		if appErr = a.importDeletePost(rctx, post.Id, user.Id); appErr != nil {
+			return appErr
+		}
+	}
+	return nil
+}
+
+func (a *App) extractThreadMembers(rctx request.CTX, line *imports.LineImportWorkerData, users map[string]*model.User, post *model.Post) ([]*model.ThreadMembership, int, *model.AppError) {
 	threadMemberships := []*model.ThreadMembership{}
 
 	var importedFollowers []imports.ThreadFollowerImportData
@@ -2601,7 +2739,7 @@ func (a *App) extractThreadMembers(line *imports.LineImportWorkerData, users map
 			// alternatively, we can continue and discard this follower as maybe they
 			// were deleted.
 			var uErr error
-			user, uErr = a.Srv().Store().User().GetByUsername(*member.User)
+			user, uErr = a.importStore(rctx).User().GetByUsername(*member.User)
 			if uErr != nil {
 				return nil, line.LineNumber, model.NewAppError("importMultiplePostLines", "app.import.get_users_by_username.some_users_not_found.error", nil, "", http.StatusBadRequest).Wrap(uErr)
 			}
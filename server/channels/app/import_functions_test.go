@@ -5169,6 +5169,158 @@ func TestImportImportEmoji(t *testing.T) {
 	require.ErrorIs(t, appErr.Unwrap(), utils.ErrSizeLimitExceeded)
 }
 
+func TestImportImportDelete(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	deleteAt := model.GetMillis()
+
+	t.Run("invalid", func(t *testing.T) {
+		data := imports.DeleteImportData{Entity: model.NewPointer(imports.DeleteEntityTeam), DeleteAt: &deleteAt}
+		appErr := th.App.importDelete(th.Context, &data, true)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.import.validate_delete_import_data.team_missing.error", appErr.Id)
+	})
+
+	t.Run("post and reply", func(t *testing.T) {
+		root := th.CreatePost(th.BasicChannel)
+		reply := th.CreatePostReply(root)
+		other := th.CreatePost(th.BasicChannel)
+
+		data := imports.DeleteImportData{
+			Entity:   model.NewPointer(imports.DeleteEntityPost),
+			Team:     &th.BasicTeam.Name,
+			Channel:  &th.BasicChannel.Name,
+			User:     &th.BasicUser.Username,
+			CreateAt: &reply.CreateAt,
+			DeleteAt: &deleteAt,
+		}
+		require.Nil(t, th.App.importDelete(th.Context, &data, true))
+		_, err := th.App.Srv().Store().Post().GetSingle(th.Context, reply.Id, false)
+		require.NoError(t, err, "dry run should not delete the reply")
+
+		require.Nil(t, th.App.importDelete(th.Context, &data, false))
+		_, err = th.App.Srv().Store().Post().GetSingle(th.Context, reply.Id, false)
+		require.Error(t, err)
+		_, err = th.App.Srv().Store().Post().GetSingle(th.Context, root.Id, false)
+		require.NoError(t, err)
+		_, err = th.App.Srv().Store().Post().GetSingle(th.Context, other.Id, false)
+		require.NoError(t, err)
+
+		// applying the tombstone again, or the tombstone of a post that was never imported, does nothing
+		require.Nil(t, th.App.importDelete(th.Context, &data, false))
+		data.CreateAt = model.NewPointer(int64(1))
+		require.Nil(t, th.App.importDelete(th.Context, &data, false))
+	})
+
+	t.Run("direct post", func(t *testing.T) {
+		dm := th.CreateDmChannel(th.BasicUser2)
+		post := th.CreatePost(dm)
+
+		data := imports.DeleteImportData{
+			Entity:         model.NewPointer(imports.DeleteEntityDirectPost),
+			ChannelMembers: &[]string{th.BasicUser.Username, th.BasicUser2.Username},
+			User:           &th.BasicUser.Username,
+			CreateAt:       &post.CreateAt,
+			DeleteAt:       &deleteAt,
+		}
+		require.Nil(t, th.App.importDelete(th.Context, &data, false))
+		_, err := th.App.Srv().Store().Post().GetSingle(th.Context, post.Id, false)
+		require.Error(t, err)
+
+		// the direct channel of a tombstone is not created
+		user := th.CreateUser()
+		data.ChannelMembers = &[]string{th.BasicUser.Username, user.Username}
+		require.Nil(t, th.App.importDelete(th.Context, &data, false))
+		_, err = th.App.Srv().Store().Channel().GetByName("", model.GetDMNameFromIds(th.BasicUser.Id, user.Id), false)
+		require.Error(t, err)
+	})
+
+	t.Run("channel and team", func(t *testing.T) {
+		team := th.CreateTeam()
+		channel := th.CreateChannel(th.Context, team)
+
+		data := imports.DeleteImportData{
+			Entity:   model.NewPointer(imports.DeleteEntityChannel),
+			Team:     &team.Name,
+			Channel:  &channel.Name,
+			DeleteAt: &deleteAt,
+		}
+		require.Nil(t, th.App.importDelete(th.Context, &data, false))
+		channel, err := th.App.Srv().Store().Channel().Get(channel.Id, false)
+		require.NoError(t, err)
+		assert.NotZero(t, channel.DeleteAt)
+
+		data = imports.DeleteImportData{
+			Entity:   model.NewPointer(imports.DeleteEntityTeam),
+			Team:     &team.Name,
+			DeleteAt: &deleteAt,
+		}
+		require.Nil(t, th.App.importDelete(th.Context, &data, false))
+		team, err = th.App.Srv().Store().Team().Get(team.Id)
+		require.NoError(t, err)
+		assert.NotZero(t, team.DeleteAt)
+
+		data.Team = model.NewPointer("unknown-team")
+		require.Nil(t, th.App.importDelete(th.Context, &data, false))
+	})
+
+	t.Run("memberships", func(t *testing.T) {
+		team := th.CreateTeam()
+		channel := th.CreateChannel(th.Context, team)
+		user := th.CreateUser()
+		th.LinkUserToTeam(user, team)
+		th.AddUserToChannel(user, channel)
+
+		data := imports.DeleteImportData{
+			Entity:   model.NewPointer(imports.DeleteEntityChannelMember),
+			Team:     &team.Name,
+			Channel:  &channel.Name,
+			User:     &user.Username,
+			DeleteAt: &deleteAt,
+		}
+		require.Nil(t, th.App.importDelete(th.Context, &data, false))
+		_, err := th.App.Srv().Store().Channel().GetMember(th.Context.Context(), channel.Id, user.Id)
+		require.Error(t, err)
+		_, err = th.App.Srv().Store().Team().GetMember(th.Context, team.Id, user.Id)
+		require.NoError(t, err)
+
+		// applying the tombstone again does nothing
+		require.Nil(t, th.App.importDelete(th.Context, &data, false))
+
+		data = imports.DeleteImportData{
+			Entity:   model.NewPointer(imports.DeleteEntityTeamMember),
+			Team:     &team.Name,
+			User:     &user.Username,
+			DeleteAt: &deleteAt,
+		}
+		require.Nil(t, th.App.importDelete(th.Context, &data, false))
+		member, err := th.App.Srv().Store().Team().GetMember(th.Context, team.Id, user.Id)
+		require.NoError(t, err)
+		assert.NotZero(t, member.DeleteAt)
+		require.Nil(t, th.App.importDelete(th.Context, &data, false))
+	})
+
+	t.Run("emoji", func(t *testing.T) {
+		emoji, err := th.App.Srv().Store().Emoji().Save(&model.Emoji{CreatorId: th.BasicUser.Id, Name: model.NewId()})
+		require.NoError(t, err)
+
+		data := imports.DeleteImportData{
+			Entity:   model.NewPointer(imports.DeleteEntityEmoji),
+			DeleteAt: &deleteAt,
+		}
+		appErr := th.App.importDelete(th.Context, &data, false)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.import.validate_delete_import_data.name_missing.error", appErr.Id)
+
+		data.Name = &emoji.Name
+		require.Nil(t, th.App.importDelete(th.Context, &data, false))
+		_, err = th.App.Srv().Store().Emoji().GetByName(th.Context, emoji.Name, false)
+		require.Error(t, err)
+		require.Nil(t, th.App.importDelete(th.Context, &data, false))
+	})
+}
+
 func TestImportAttachment(t *testing.T) {
 	th := Setup(t)
 	defer th.TearDown()
@@ -0,0 +1,86 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// The posts are imported again when an import file is imported again, e.g. when a failed import is resumed. The
+// functions below find the posts imported before from the same lines so that they are updated instead of duplicated.
+
+// postImportID returns the id of an imported post, stored in its props to find the post when the same file is imported
+// again. The id is derived from what identifies the post in the file whatever its message, which changes when the post
+// is edited: its channel, its thread, its author and its creation time.
+func postImportID(channelID, rootID, userID string, createAt int64) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%s:%d", channelID, rootID, userID, createAt)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// findImportedPost returns the post imported with the import id among the posts created at the same time, skipping
+// the previous versions of the edited posts. The posts of an author created at the same time share their import id,
+// they are told apart by their message.
+func findImportedPost(posts []*model.Post, importID, message string) *model.Post {
+	var candidates []*model.Post
+	for _, p := range posts {
+		if id, _ := p.GetProp(model.PostPropsImportId).(string); id != importID || p.OriginalId != "" {
+			continue
+		}
+		if p.Message == message {
+			return p
+		}
+		candidates = append(candidates, p)
+	}
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	return nil
+}
+
+// withoutImportID returns the posts imported before the posts had an import id, which are found by their message.
+func withoutImportID(posts []*model.Post) []*model.Post {
+	var legacyPosts []*model.Post
+	for _, p := range posts {
+		if _, ok := p.GetProp(model.PostPropsImportId).(string); !ok {
+			legacyPosts = append(legacyPosts, p)
+		}
+	}
+	return legacyPosts
+}
+
+// findPostToImport returns the post imported before from the same line, found by its import id, or by its message if
+// it was imported before the posts had an import id, or the previous version of the post if it was edited since. It
+// returns nil if the post is new.
+func findPostToImport(posts []*model.Post, importID, userID, rootID, message string, editAt *int64) *model.Post {
+	if post := findImportedPost(posts, importID, message); post != nil {
+		return post
+	}
+
+	posts = withoutImportID(posts)
+	for _, p := range posts {
+		if p.Message == message && (rootID == "" || p.RootId == rootID) {
+			return p
+		}
+	}
+	if editAt != nil && *editAt > 0 {
+		return findEditedPost(posts, userID, rootID)
+	}
+	return nil
+}
+
+// findEditedPost returns the post of the user among the posts created at the same time, skipping the previous versions
+// of the edited posts. An incremental export has the edited posts with their new message, which doesn't match the
+// message imported before.
+func findEditedPost(posts []*model.Post, userID, rootID string) *model.Post {
+	for _, p := range posts {
+		if p.UserId == userID && p.RootId == rootID && p.OriginalId == "" && p.DeleteAt == 0 {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestFindPostToImport(t *testing.T) {
+	userID := model.NewId()
+	rootID := model.NewId()
+	importID := postImportID("channel", "", userID, 1000)
+
+	imported := func(id, message string) *model.Post {
+		post := &model.Post{Id: id, UserId: userID, Message: message}
+		post.AddProp(model.PostPropsImportId, importID)
+		return post
+	}
+
+	t.Run("post imported with the import id", func(t *testing.T) {
+		posts := []*model.Post{imported("a", "first"), imported("b", "second")}
+		assert.Equal(t, "b", findPostToImport(posts, importID, userID, "", "second", nil).Id)
+	})
+
+	t.Run("post imported with the import id and edited since", func(t *testing.T) {
+		previous := imported("previous", "old")
+		previous.OriginalId = "a"
+		posts := []*model.Post{previous, imported("a", "old")}
+		assert.Equal(t, "a", findPostToImport(posts, importID, userID, "", "new", model.NewPointer(int64(2000))).Id)
+	})
+
+	t.Run("post imported before the import ids", func(t *testing.T) {
+		posts := []*model.Post{
+			{Id: "a", UserId: userID, Message: "first"},
+			{Id: "b", UserId: userID, Message: "second"},
+		}
+		assert.Equal(t, "b", findPostToImport(posts, importID, userID, "", "second", nil).Id)
+	})
+
+	t.Run("reply imported before the import ids", func(t *testing.T) {
+		posts := []*model.Post{
+			{Id: "a", UserId: userID, RootId: model.NewId(), Message: "reply"},
+			{Id: "b", UserId: userID, RootId: rootID, Message: "reply"},
+		}
+		assert.Equal(t, "b", findPostToImport(posts, importID, userID, rootID, "reply", nil).Id)
+	})
+
+	t.Run("post imported before the import ids and edited since", func(t *testing.T) {
+		posts := []*model.Post{
+			{Id: "previous", UserId: userID, Message: "old", OriginalId: "a"},
+			{Id: "a", UserId: userID, Message: "old"},
+		}
+		assert.Nil(t, findPostToImport(posts, importID, userID, "", "new", nil))
+		assert.Equal(t, "a", findPostToImport(posts, importID, userID, "", "new", model.NewPointer(int64(2000))).Id)
+	})
+
+	t.Run("new post", func(t *testing.T) {
+		posts := []*model.Post{imported("a", "first"), imported("b", "second")}
+		assert.Nil(t, findPostToImport(posts, importID, userID, "", "third", nil))
+		assert.Nil(t, findPostToImport(nil, importID, userID, "", "first", nil))
+	})
+}
@@ -233,6 +233,106 @@ func TestImportBulkImport(t *testing.T) {
 	})
 }
 
+func TestImportResumeBulkImport(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	teamName := model.NewRandomTeamName()
+	channelName := model.NewId()
+	username := model.NewUsername()
+	username2 := model.NewUsername()
+
+	data := `{"type": "version", "version": 1}
+{"type": "team", "team": {"type": "O", "display_name": "Resumed Team", "name": "` + teamName + `"}}
+{"type": "channel", "channel": {"type": "O", "display_name": "Resumed Channel", "team": "` + teamName + `", "name": "` + channelName + `"}}
+{"type": "user", "user": {"username": "` + username + `", "email": "` + username + `@example.com", "teams": [{"name": "` + teamName + `", "channels": [{"name": "` + channelName + `"}]}]}}
+{"type": "user", "user": {"username": "` + username2 + `", "email": "` + username2 + `@example.com", "teams": [{"name": "` + teamName + `", "channels": [{"name": "` + channelName + `"}]}]}}
+{"type": "post", "post": {"team": "` + teamName + `", "channel": "` + channelName + `", "user": "` + username + `", "message": "Hello", "create_at": 123456789012, "replies": [{"user": "` + username2 + `", "message": "Hi", "create_at": 123456789013}]}}
+{"type": "post", "post": {"team": "` + teamName + `", "channel": "` + channelName + `", "user": "` + username2 + `", "message": "Hello", "create_at": 123456789012}}
+{"type": "direct_channel", "direct_channel": {"members": ["` + username + `", "` + username2 + `"]}}
+{"type": "direct_post", "direct_post": {"channel_members": ["` + username + `", "` + username2 + `"], "user": "` + username + `", "message": "Hello Direct Channel", "create_at": 123456789014}}`
+
+	var checkpoints []int
+	checkpoint := func(lineNumber int) {
+		checkpoints = append(checkpoints, lineNumber)
+	}
+
+	err, line := th.App.ResumeBulkImportWithPath(th.Context, strings.NewReader(data), nil, true, 2, "", 0, checkpoint)
+	require.Nil(t, err)
+	require.Equal(t, 0, line)
+	require.NotEmpty(t, checkpoints)
+	assert.Equal(t, 9, checkpoints[len(checkpoints)-1])
+	assert.IsIncreasing(t, checkpoints)
+
+	team, appErr := th.App.GetTeamByName(teamName)
+	require.Nil(t, appErr)
+	channel, appErr := th.App.GetChannelByName(th.Context, channelName, team.Id, false)
+	require.Nil(t, appErr)
+
+	// The posts of the two users created at the same time are both imported.
+	posts, nErr := th.App.Srv().Store().Post().GetPostsCreatedAt(channel.Id, 123456789012)
+	require.NoError(t, nErr)
+	require.Len(t, posts, 2)
+	assert.NotEmpty(t, posts[0].GetProp(model.PostPropsImportId))
+	assert.NotEqual(t, posts[0].GetProp(model.PostPropsImportId), posts[1].GetProp(model.PostPropsImportId))
+
+	t.Run("importing the same file again doesn't duplicate the posts", func(t *testing.T) {
+		err, line := th.App.BulkImport(th.Context, strings.NewReader(data), nil, false, 2)
+		require.Nil(t, err)
+		require.Equal(t, 0, line)
+
+		posts, nErr := th.App.Srv().Store().Post().GetPostsCreatedAt(channel.Id, 123456789012)
+		require.NoError(t, nErr)
+		assert.Len(t, posts, 2)
+		replies, nErr := th.App.Srv().Store().Post().GetPostsCreatedAt(channel.Id, 123456789013)
+		require.NoError(t, nErr)
+		assert.Len(t, replies, 1)
+	})
+
+	t.Run("resuming skips the committed lines", func(t *testing.T) {
+		checkpoints = nil
+		// The lines up to the checkpoint aren't parsed.
+		resumed := strings.Replace(data, `"display_name": "Resumed Team"`, `"display_name": `, 1)
+		err, line := th.App.ResumeBulkImportWithPath(th.Context, strings.NewReader(resumed), nil, true, 2, "", 7, checkpoint)
+		require.Nil(t, err)
+		require.Equal(t, 0, line)
+		assert.Equal(t, []int{8, 9}, checkpoints)
+	})
+
+	t.Run("failed import checkpoints the lines committed before the failure", func(t *testing.T) {
+		checkpoints = nil
+		failing := data + `
+{"type": "direct_post", "direct_post": {"channel_members": ["` + username + `", "` + username2 + `"], "user": "` + model.NewUsername() + `", "message": "Unknown user", "create_at": 123456789015}}`
+		err, _ := th.App.ResumeBulkImportWithPath(th.Context, strings.NewReader(failing), nil, true, 1, "", 0, checkpoint)
+		require.NotNil(t, err)
+		require.NotEmpty(t, checkpoints)
+		assert.Equal(t, 8, checkpoints[len(checkpoints)-1])
+	})
+}
+
+func TestImportProgress(t *testing.T) {
+	progress := newImportProgress(1)
+	lines := func(numbers ...int) []imports.LineImportWorkerData {
+		var lines []imports.LineImportWorkerData
+		for _, n := range numbers {
+			lines = append(lines, imports.LineImportWorkerData{LineNumber: n})
+		}
+		return lines
+	}
+
+	progress.commit(lines(3, 4)...)
+	assert.Equal(t, 1, progress.lastLine())
+
+	progress.commit(lines(2)...)
+	assert.Equal(t, 4, progress.lastLine())
+
+	progress.commitIgnored(model.NewAppError("test", "app.import.attachment.bad_file.error", nil, "", http.StatusBadRequest), lines(5)...)
+	assert.Equal(t, 4, progress.lastLine())
+
+	progress.commitIgnored(model.NewAppError("test", "api.file.upload_file.large_image.app_error", nil, "", http.StatusBadRequest), lines(5)...)
+	assert.Equal(t, 5, progress.lastLine())
+}
+
 func TestImportProcessImportDataFileVersionLine(t *testing.T) {
 	data := imports.LineImportData{
 		Type:    "version",
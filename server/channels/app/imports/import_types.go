@@ -24,6 +24,7 @@ type LineImportData struct {
 	DirectChannel *DirectChannelImportData `json:"direct_channel,omitempty"`
 	DirectPost    *DirectPostImportData    `json:"direct_post,omitempty"`
 	Emoji         *EmojiImportData         `json:"emoji,omitempty"`
+	Delete        *DeleteImportData        `json:"delete,omitempty"`
 	Version       *int                     `json:"version,omitempty"`
 	Info          *VersionInfoImportData   `json:"info,omitempty"`
 }
@@ -32,6 +33,7 @@ type VersionInfoImportData struct {
 	Generator  string          `json:"generator"`
 	Version    string          `json:"version"`
 	Created    string          `json:"created"`
+	Since      int64           `json:"since,omitempty"` // set for an incremental export, see DeleteImportData
 	Additional json.RawMessage `json:"additional,omitempty"`
 }
 
@@ -174,6 +176,31 @@ type EmojiImportData struct {
 	Data  *zip.File `json:"-"`
 }
 
+const (
+	DeleteEntityTeam       = "team"
+	DeleteEntityChannel    = "channel"
+	DeleteEntityPost       = "post"
+	DeleteEntityDirectPost = "direct_post"
+	// The memberships are removed from their user when they leave a team or a channel.
+	DeleteEntityTeamMember    = "team_member"
+	DeleteEntityChannelMember = "channel_member"
+	DeleteEntityEmoji         = "emoji"
+)
+
+// DeleteImportData is the tombstone of an entity deleted after the start of an incremental export. The entity is
+// identified as in its own lines: a team by its name, a channel by its team and name, a post or a reply by its
+// channel, user and creation time, a membership by its team or channel and user, and an emoji by its name.
+type DeleteImportData struct {
+	Entity         *string   `json:"entity"`
+	Name           *string   `json:"name,omitempty"`
+	Team           *string   `json:"team,omitempty"`
+	Channel        *string   `json:"channel,omitempty"`
+	ChannelMembers *[]string `json:"channel_members,omitempty"`
+	User           *string   `json:"user,omitempty"`
+	CreateAt       *int64    `json:"create_at,omitempty"`
+	DeleteAt       *int64    `json:"delete_at"`
+}
+
 type ReactionImportData struct {
 	User      *string `json:"user"`
 	CreateAt  *int64  `json:"create_at"`
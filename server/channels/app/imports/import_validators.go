@@ -678,6 +678,55 @@ func ValidateEmojiImportData(data *EmojiImportData) *model.AppError {
 	return nil
 }
 
+func ValidateDeleteImportData(data *DeleteImportData) *model.AppError {
+	if data == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_delete_import_data.empty.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.DeleteAt == nil || *data.DeleteAt <= 0 {
+		return model.NewAppError("BulkImport", "app.import.validate_delete_import_data.delete_at_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Entity == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_delete_import_data.entity_invalid.error", nil, "", http.StatusBadRequest)
+	}
+
+	switch *data.Entity {
+	case DeleteEntityTeam, DeleteEntityTeamMember, DeleteEntityChannel, DeleteEntityChannelMember, DeleteEntityPost:
+		if data.Team == nil || *data.Team == "" {
+			return model.NewAppError("BulkImport", "app.import.validate_delete_import_data.team_missing.error", nil, "", http.StatusBadRequest)
+		}
+		if *data.Entity != DeleteEntityTeam && *data.Entity != DeleteEntityTeamMember && (data.Channel == nil || *data.Channel == "") {
+			return model.NewAppError("BulkImport", "app.import.validate_delete_import_data.channel_missing.error", nil, "", http.StatusBadRequest)
+		}
+	case DeleteEntityDirectPost:
+		if data.ChannelMembers == nil || len(*data.ChannelMembers) == 0 {
+			return model.NewAppError("BulkImport", "app.import.validate_delete_import_data.channel_members_missing.error", nil, "", http.StatusBadRequest)
+		}
+	case DeleteEntityEmoji:
+		if data.Name == nil || *data.Name == "" {
+			return model.NewAppError("BulkImport", "app.import.validate_delete_import_data.name_missing.error", nil, "", http.StatusBadRequest)
+		}
+	default:
+		return model.NewAppError("BulkImport", "app.import.validate_delete_import_data.entity_invalid.error", nil, "", http.StatusBadRequest)
+	}
+
+	switch *data.Entity {
+	case DeleteEntityTeamMember, DeleteEntityChannelMember, DeleteEntityPost, DeleteEntityDirectPost:
+		if data.User == nil || *data.User == "" {
+			return model.NewAppError("BulkImport", "app.import.validate_delete_import_data.user_missing.error", nil, "", http.StatusBadRequest)
+		}
+	}
+
+	if *data.Entity == DeleteEntityPost || *data.Entity == DeleteEntityDirectPost {
+		if data.CreateAt == nil || *data.CreateAt <= 0 {
+			return model.NewAppError("BulkImport", "app.import.validate_delete_import_data.create_at_missing.error", nil, "", http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
 func isValidTrueOrFalseString(value string) bool {
 	return value == "true" || value == "false"
 }
@@ -1476,6 +1476,107 @@ func TestImportValidateEmojiImportData(t *testing.T) {
 	}
 }
 
+func TestImportValidateDeleteImportData(t *testing.T) {
+	post := func() *DeleteImportData {
+		return &DeleteImportData{
+			Entity:   model.NewPointer(DeleteEntityPost),
+			Team:     model.NewPointer("team"),
+			Channel:  model.NewPointer("channel"),
+			User:     model.NewPointer("user"),
+			CreateAt: model.NewPointer(int64(1000)),
+			DeleteAt: model.NewPointer(int64(2000)),
+		}
+	}
+
+	var testCases = []struct {
+		testName      string
+		data          func() *DeleteImportData
+		expectedError string
+	}{
+		{"post", post, ""},
+		{"team", func() *DeleteImportData {
+			return &DeleteImportData{Entity: model.NewPointer(DeleteEntityTeam), Team: model.NewPointer("team"), DeleteAt: model.NewPointer(int64(2000))}
+		}, ""},
+		{"channel without team", func() *DeleteImportData {
+			return &DeleteImportData{Entity: model.NewPointer(DeleteEntityChannel), Channel: model.NewPointer("channel"), DeleteAt: model.NewPointer(int64(2000))}
+		}, "app.import.validate_delete_import_data.team_missing.error"},
+		{"direct post", func() *DeleteImportData {
+			data := post()
+			data.Entity = model.NewPointer(DeleteEntityDirectPost)
+			data.Team, data.Channel = nil, nil
+			data.ChannelMembers = &[]string{"user", "other"}
+			return data
+		}, ""},
+		{"direct post without members", func() *DeleteImportData {
+			data := post()
+			data.Entity = model.NewPointer(DeleteEntityDirectPost)
+			return data
+		}, "app.import.validate_delete_import_data.channel_members_missing.error"},
+		{"team member", func() *DeleteImportData {
+			return &DeleteImportData{Entity: model.NewPointer(DeleteEntityTeamMember), Team: model.NewPointer("team"), User: model.NewPointer("user"), DeleteAt: model.NewPointer(int64(2000))}
+		}, ""},
+		{"team member without user", func() *DeleteImportData {
+			return &DeleteImportData{Entity: model.NewPointer(DeleteEntityTeamMember), Team: model.NewPointer("team"), DeleteAt: model.NewPointer(int64(2000))}
+		}, "app.import.validate_delete_import_data.user_missing.error"},
+		{"channel member", func() *DeleteImportData {
+			data := post()
+			data.Entity = model.NewPointer(DeleteEntityChannelMember)
+			data.CreateAt = nil
+			return data
+		}, ""},
+		{"channel member without channel", func() *DeleteImportData {
+			data := post()
+			data.Entity = model.NewPointer(DeleteEntityChannelMember)
+			data.Channel = nil
+			return data
+		}, "app.import.validate_delete_import_data.channel_missing.error"},
+		{"emoji", func() *DeleteImportData {
+			return &DeleteImportData{Entity: model.NewPointer(DeleteEntityEmoji), Name: model.NewPointer("emoji"), DeleteAt: model.NewPointer(int64(2000))}
+		}, ""},
+		{"emoji without name", func() *DeleteImportData {
+			return &DeleteImportData{Entity: model.NewPointer(DeleteEntityEmoji), DeleteAt: model.NewPointer(int64(2000))}
+		}, "app.import.validate_delete_import_data.name_missing.error"},
+		{"nil", func() *DeleteImportData { return nil }, "app.import.validate_delete_import_data.empty.error"},
+		{"unknown entity", func() *DeleteImportData {
+			data := post()
+			data.Entity = model.NewPointer("user")
+			return data
+		}, "app.import.validate_delete_import_data.entity_invalid.error"},
+		{"no delete time", func() *DeleteImportData {
+			data := post()
+			data.DeleteAt = nil
+			return data
+		}, "app.import.validate_delete_import_data.delete_at_missing.error"},
+		{"post without channel", func() *DeleteImportData {
+			data := post()
+			data.Channel = nil
+			return data
+		}, "app.import.validate_delete_import_data.channel_missing.error"},
+		{"post without user", func() *DeleteImportData {
+			data := post()
+			data.User = nil
+			return data
+		}, "app.import.validate_delete_import_data.user_missing.error"},
+		{"post without creation time", func() *DeleteImportData {
+			data := post()
+			data.CreateAt = nil
+			return data
+		}, "app.import.validate_delete_import_data.create_at_missing.error"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.testName, func(t *testing.T) {
+			err := ValidateDeleteImportData(tc.data())
+			if tc.expectedError == "" {
+				assert.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+				assert.Equal(t, tc.expectedError, err.Id)
+			}
+		})
+	}
+}
+
 func checkError(t *testing.T, err *model.AppError) {
 	require.NotNil(t, err, "Should have returned an error.")
 }
@@ -103,10 +103,17 @@ func (a *App) SessionHasPermissionToCreateJob(session model.Session, job *model.
 		model.JobTypeActiveUsers,
 		model.JobTypeImportProcess,
 		model.JobTypeImportDelete,
+		model.JobTypeImportMSTeams,
 		model.JobTypeExportProcess,
 		model.JobTypeExportDelete,
 		model.JobTypeCloud,
-		model.JobTypeExtractContent:
+		model.JobTypeExtractContent,
+		model.JobTypeFileDeduplication,
+		model.JobTypeFileEncryptionKeyRotation,
+		model.JobTypeFileTiering,
+		model.JobTypePostgresSearchIndexing,
+		model.JobTypeSavedSearchAlerts,
+		model.JobTypeVectorSearchIndexing:
 		return a.SessionHasPermissionTo(session, model.PermissionManageJobs), model.PermissionManageJobs
 	}
 
@@ -137,10 +144,17 @@ func (a *App) SessionHasPermissionToManageJob(session model.Session, job *model.
 		model.JobTypeActiveUsers,
 		model.JobTypeImportProcess,
 		model.JobTypeImportDelete,
+		model.JobTypeImportMSTeams,
 		model.JobTypeExportProcess,
 		model.JobTypeExportDelete,
 		model.JobTypeCloud,
-		model.JobTypeExtractContent:
+		model.JobTypeExtractContent,
+		model.JobTypeFileDeduplication,
+		model.JobTypeFileEncryptionKeyRotation,
+		model.JobTypeFileTiering,
+		model.JobTypePostgresSearchIndexing,
+		model.JobTypeSavedSearchAlerts,
+		model.JobTypeVectorSearchIndexing:
 		permission = model.PermissionManageJobs
 	}
 
@@ -172,11 +186,18 @@ func (a *App) SessionHasPermissionToReadJob(session model.Session, jobType strin
 		model.JobTypeActiveUsers,
 		model.JobTypeImportProcess,
 		model.JobTypeImportDelete,
+		model.JobTypeImportMSTeams,
 		model.JobTypeExportProcess,
 		model.JobTypeExportDelete,
 		model.JobTypeCloud,
 		model.JobTypeMobileSessionMetadata,
-		model.JobTypeExtractContent:
+		model.JobTypeExtractContent,
+		model.JobTypeFileDeduplication,
+		model.JobTypeFileEncryptionKeyRotation,
+		model.JobTypeFileTiering,
+		model.JobTypePostgresSearchIndexing,
+		model.JobTypeSavedSearchAlerts,
+		model.JobTypeVectorSearchIndexing:
 		return a.SessionHasPermissionTo(session, model.PermissionReadJobs), model.PermissionReadJobs
 	}
 
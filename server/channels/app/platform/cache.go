@@ -0,0 +1,264 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package platform
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/platform/services/cache"
+)
+
+const (
+	warmupSchemesPerPage = 100
+	warmupEmojisPerPage  = 200
+	warmupBatchSize      = 100
+)
+
+func cacheInfo(c cache.Cache, cacheType string) *model.CacheInfo {
+	stats := c.Stats()
+	info := &model.CacheInfo{
+		Name:      c.Name(),
+		Type:      cacheType,
+		Size:      stats.Size,
+		ItemCount: stats.Len,
+		Hits:      stats.Hits,
+		Misses:    stats.Misses,
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		info.HitRatio = float64(stats.Hits) / float64(total)
+	}
+	return info
+}
+
+// GetCaches returns the caches of this node along with their statistics.
+func (ps *PlatformService) GetCaches() []*model.CacheInfo {
+	var infos []*model.CacheInfo
+	for _, c := range ps.localCacheProvider.Caches() {
+		infos = append(infos, cacheInfo(c, model.CacheTypeLRU))
+	}
+	for _, c := range ps.cacheProvider.Caches() {
+		infos = append(infos, cacheInfo(c, ps.cacheProvider.Type()))
+	}
+	return infos
+}
+
+// PurgeCacheSkipClusterSend purges the named cache of this node.
+func (ps *PlatformService) PurgeCacheSkipClusterSend(name string) (shared bool, appErr *model.AppError) {
+	var toPurge []cache.Cache
+	for _, c := range ps.localCacheProvider.Caches() {
+		if c.Name() == name {
+			toPurge = append(toPurge, c)
+		}
+	}
+	for _, c := range ps.cacheProvider.Caches() {
+		if c.Name() == name {
+			toPurge = append(toPurge, c)
+			shared = ps.cacheProvider.Type() != model.CacheTypeLRU
+		}
+	}
+	if len(toPurge) == 0 {
+		return false, model.NewAppError("PurgeCache", "app.cache.not_found.app_error", map[string]any{"Name": name}, "", http.StatusNotFound)
+	}
+
+	ps.logger.Info("Purging cache", mlog.String("cache", name))
+	for _, c := range toPurge {
+		if err := c.Purge(); err != nil {
+			return shared, model.NewAppError("PurgeCache", "app.cache.purge.app_error", map[string]any{"Name": name}, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+	return shared, nil
+}
+
+// PurgeCache purges the named cache, on the other nodes of the cluster as well unless the cache is
+// shared between them.
+func (ps *PlatformService) PurgeCache(name string) *model.AppError {
+	shared, appErr := ps.PurgeCacheSkipClusterSend(name)
+	if appErr != nil {
+		return appErr
+	}
+
+	if ps.clusterIFace != nil && !shared {
+		msg := &model.ClusterMessage{
+			Event:    model.ClusterEventPurgeCache,
+			SendType: model.ClusterSendReliable,
+			Data:     []byte(name),
+		}
+		ps.clusterIFace.SendClusterMessage(msg)
+	}
+
+	return nil
+}
+
+func (ps *PlatformService) clusterPurgeCacheHandler(msg *model.ClusterMessage) {
+	if _, appErr := ps.PurgeCacheSkipClusterSend(string(msg.Data)); appErr != nil {
+		ps.logger.Warn("Failed to purge the cache", mlog.String("cache", string(msg.Data)), mlog.Err(appErr))
+	}
+}
+
+// CachesWarmedUp returns whether the startup warmup of the caches is over, always true when it is disabled.
+func (ps *PlatformService) CachesWarmedUp() bool {
+	return ps.cachesWarm.Load()
+}
+
+// warmupCaches preloads the most used data into the caches so that the first requests after a
+// restart don't all go to the database. It gives up when ctx is done, after
+// CacheSettings.WarmupTimeoutSeconds or on shutdown.
+func (ps *PlatformService) warmupCaches(ctx context.Context) {
+	defer ps.cachesWarm.Store(true)
+
+	settings := ps.Config().CacheSettings
+
+	steps := []struct {
+		name string
+		fn   func(ctx context.Context) error
+	}{
+		{"roles", ps.warmupRoles},
+		{"schemes", ps.warmupSchemes},
+		{"emojis", ps.warmupEmojis},
+		{"users", func(ctx context.Context) error { return ps.warmupUsers(ctx, *settings.WarmupActiveUsers) }},
+		{"channels", func(ctx context.Context) error { return ps.warmupChannels(ctx, *settings.WarmupActiveChannels) }},
+	}
+
+	ps.logger.Info("Warming up the caches")
+	start := time.Now()
+	for _, step := range steps {
+		stepStart := time.Now()
+		err := step.fn(ctx)
+		if ctx.Err() != nil {
+			ps.logger.Warn("Stopped warming up the caches", mlog.String("step", step.name), mlog.Err(ctx.Err()))
+			return
+		}
+		if err != nil {
+			ps.logger.Warn("Failed to warm up the caches", mlog.String("step", step.name), mlog.Err(err))
+			continue
+		}
+		ps.logger.Debug("Warmed up the caches", mlog.String("step", step.name), mlog.Duration("duration", time.Since(stepStart)))
+	}
+	ps.logger.Info("Caches warmed up", mlog.Duration("duration", time.Since(start)))
+}
+
+func (ps *PlatformService) warmupRoles(ctx context.Context) error {
+	roles, err := ps.Store.Role().GetAll()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	for _, batch := range batches(names, warmupBatchSize) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, err := ps.Store.Role().GetByNames(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ps *PlatformService) warmupSchemes(ctx context.Context) error {
+	for offset := 0; ctx.Err() == nil; offset += warmupSchemesPerPage {
+		schemes, err := ps.Store.Scheme().GetAllPage("", offset, warmupSchemesPerPage)
+		if err != nil {
+			return err
+		}
+		for _, scheme := range schemes {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if _, err := ps.Store.Scheme().Get(scheme.Id); err != nil {
+				return err
+			}
+		}
+		if len(schemes) < warmupSchemesPerPage {
+			break
+		}
+	}
+	return ctx.Err()
+}
+
+func (ps *PlatformService) warmupEmojis(ctx context.Context) error {
+	if !*ps.Config().ServiceSettings.EnableCustomEmoji {
+		return nil
+	}
+
+	rctx := request.EmptyContext(ps.logger).WithContext(ctx)
+	for offset := 0; ctx.Err() == nil; offset += warmupEmojisPerPage {
+		emojis, err := ps.Store.Emoji().GetList(offset, warmupEmojisPerPage, "")
+		if err != nil {
+			return err
+		}
+
+		names := make([]string, 0, len(emojis))
+		for _, emoji := range emojis {
+			names = append(names, emoji.Name)
+		}
+		if _, err := ps.Store.Emoji().GetMultipleByName(rctx, names); err != nil {
+			return err
+		}
+		if len(emojis) < warmupEmojisPerPage {
+			break
+		}
+	}
+	return ctx.Err()
+}
+
+func (ps *PlatformService) warmupUsers(ctx context.Context, limit int) error {
+	userIDs, err := ps.Store.Status().GetRecentlyActiveUserIds(limit)
+	if err != nil {
+		return err
+	}
+
+	for _, batch := range batches(userIDs, warmupBatchSize) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, err := ps.Store.User().GetProfileByIds(ctx, batch, nil, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ps *PlatformService) warmupChannels(ctx context.Context, limit int) error {
+	channelIDs, err := ps.Store.Channel().GetRecentlyActiveChannelIds(limit)
+	if err != nil {
+		return err
+	}
+
+	for _, batch := range batches(channelIDs, warmupBatchSize) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, err := ps.Store.Channel().GetMany(batch, true); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, err := ps.Store.Channel().GetChannelsMemberCount(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func batches(ids []string, size int) [][]string {
+	var result [][]string
+	for len(ids) > size {
+		result = append(result, ids[:size])
+		ids = ids[size:]
+	}
+	if len(ids) > 0 {
+		result = append(result, ids)
+	}
+	return result
+}
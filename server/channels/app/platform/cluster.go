@@ -28,8 +28,19 @@ func (ps *PlatformService) NewClusterDiscoveryService() *ClusterDiscoveryService
 	return ds
 }
 
+// IsClusterEnabled returns whether the node runs in a cluster, either licensed or communicating through the
+// database, which needs no license.
+func (ps *PlatformService) IsClusterEnabled() bool {
+	if ps.clusterIFace == nil || !*ps.Config().ClusterSettings.Enable {
+		return false
+	}
+
+	license := ps.License()
+	return (license != nil && *license.Features.Cluster) || ps.Config().ClusterSettings.UsesDatabaseTransport()
+}
+
 func (ps *PlatformService) IsLeader() bool {
-	if ps.License() != nil && *ps.Config().ClusterSettings.Enable && ps.clusterIFace != nil {
+	if ps.IsClusterEnabled() {
 		return ps.clusterIFace.IsLeader()
 	}
 
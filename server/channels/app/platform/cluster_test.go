@@ -0,0 +1,58 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package platform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestIsClusterEnabled(t *testing.T) {
+	th := SetupWithCluster(t, &ClusterMock{t: t})
+	defer th.TearDown()
+
+	setCluster := func(enable bool, transport string) {
+		th.Service.UpdateConfig(func(cfg *model.Config) {
+			*cfg.ClusterSettings.Enable = enable
+			*cfg.ClusterSettings.Transport = transport
+		})
+	}
+
+	t.Run("licensed cluster", func(t *testing.T) {
+		setCluster(true, model.ClusterTransportGossip)
+		assert.True(t, th.Service.IsClusterEnabled())
+		assert.False(t, th.Service.IsLeader(), "the leader is the one of the cluster")
+
+		setCluster(false, model.ClusterTransportGossip)
+		assert.False(t, th.Service.IsClusterEnabled())
+		assert.True(t, th.Service.IsLeader())
+	})
+
+	t.Run("unlicensed cluster", func(t *testing.T) {
+		license := model.NewTestLicense()
+		license.Features.Cluster = model.NewPointer(false)
+		th.Service.SetLicense(license)
+		defer th.Service.SetLicense(model.NewTestLicense())
+
+		setCluster(true, model.ClusterTransportGossip)
+		assert.False(t, th.Service.IsClusterEnabled())
+		assert.True(t, th.Service.IsLeader())
+
+		th.Service.SetLicense(nil)
+		assert.False(t, th.Service.IsClusterEnabled())
+		assert.True(t, th.Service.IsLeader())
+	})
+
+	t.Run("database transport without license", func(t *testing.T) {
+		th.Service.SetLicense(nil)
+		defer th.Service.SetLicense(model.NewTestLicense())
+
+		setCluster(true, model.ClusterTransportDatabase)
+		assert.True(t, th.Service.IsClusterEnabled())
+		assert.False(t, th.Service.IsLeader(), "the leader is the one of the cluster")
+	})
+}
@@ -91,4 +91,14 @@ func (ps *PlatformService) StopSearchEngine() {
 			ps.Log().Error("Failed to stop Bleve Engine", mlog.Err(err))
 		}
 	}
+	if ps.SearchEngine != nil && ps.SearchEngine.PostgresEngine != nil && ps.SearchEngine.PostgresEngine.IsActive() {
+		if err := ps.SearchEngine.PostgresEngine.Stop(); err != nil {
+			ps.Log().Error("Failed to stop Postgres search engine", mlog.Err(err))
+		}
+	}
+	if ps.SearchEngine != nil && ps.SearchEngine.VectorEngine != nil && ps.SearchEngine.VectorEngine.IsActive() {
+		if err := ps.SearchEngine.VectorEngine.Stop(); err != nil {
+			ps.Log().Error("Failed to stop vector search engine", mlog.Err(err))
+		}
+	}
 }
@@ -4,6 +4,7 @@
 package platform
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"errors"
 	"fmt"
@@ -29,8 +30,11 @@ import (
 	"github.com/mattermost/mattermost/server/v8/config"
 	"github.com/mattermost/mattermost/server/v8/einterfaces"
 	"github.com/mattermost/mattermost/server/v8/platform/services/cache"
+	"github.com/mattermost/mattermost/server/v8/platform/services/dbcluster"
 	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine"
 	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/bleveengine"
+	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/postgresengine"
+	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/vectorengine"
 	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
 )
 
@@ -50,8 +54,14 @@ type PlatformService struct {
 	exportFilestore filestore.FileBackend
 
 	cacheProvider cache.Provider
-	statusCache   cache.Cache
-	sessionCache  cache.Cache
+	// localCacheProvider creates the caches kept in memory whatever the cache type.
+	localCacheProvider cache.Provider
+	statusCache        cache.Cache
+	sessionCache       cache.Cache
+	// cachesWarm is set once the caches are warmed up, or right away if the warmup is disabled.
+	cachesWarm atomic.Bool
+	// cancelCachesWarmup stops the warmup of the caches on shutdown.
+	cancelCachesWarmup context.CancelFunc
 
 	asymmetricSigningKey atomic.Pointer[ecdsa.PrivateKey]
 	clientConfig         atomic.Value
@@ -175,11 +185,30 @@ func New(sc ServiceConfig, options ...Option) (*PlatformService, error) {
 				DisableCache:     *cacheConfig.DisableClientCache,
 			},
 		)
+	} else if *cacheConfig.CacheType == model.CacheTypeHybrid {
+		ps.cacheProvider, err = cache.NewHybridProvider(
+			&cache.HybridOptions{
+				RedisOptions: cache.RedisOptions{
+					RedisAddr:        *cacheConfig.RedisAddress,
+					RedisPassword:    *cacheConfig.RedisPassword,
+					RedisDB:          *cacheConfig.RedisDB,
+					RedisCachePrefix: *cacheConfig.RedisCachePrefix,
+					DisableCache:     *cacheConfig.DisableClientCache,
+				},
+				LocalCacheSizes: cacheConfig.LocalCacheSizes,
+			},
+		)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("unable to create cache provider: %w", err)
 	}
 
+	// Note: some caches are hardcoded to LRU because they lead to a lot of
+	// SCAN calls in case of Redis. We could potentially have a reverse
+	// mapping to avoid the scan, but this needs more complicated code.
+	// Leaving this for now.
+	ps.localCacheProvider = cache.NewProvider()
+
 	// The value of res is used later, after the logger is initialized.
 	// There's a certain order of steps we need to follow in the server startup phase.
 	res, err := ps.cacheProvider.Connect()
@@ -203,6 +232,16 @@ func New(sc ServiceConfig, options ...Option) (*PlatformService, error) {
 		return nil, err
 	}
 	searchEngine.RegisterBleveEngine(bleveEngine)
+	postgresEngine := postgresengine.NewPostgresEngine(ps.Config())
+	if err := postgresEngine.Start(); err != nil {
+		return nil, err
+	}
+	searchEngine.RegisterPostgresEngine(postgresEngine)
+	vectorEngine := vectorengine.NewVectorEngine(ps.Config())
+	if err := vectorEngine.Start(); err != nil {
+		return nil, err
+	}
+	searchEngine.RegisterVectorEngine(vectorEngine)
 	ps.SearchEngine = searchEngine
 
 	// Step 4: Init Enterprise
@@ -250,6 +289,7 @@ func New(sc ServiceConfig, options ...Option) (*PlatformService, error) {
 				ps.metricsIFace,
 				ps.clusterIFace,
 				ps.cacheProvider,
+				ps.localCacheProvider,
 				ps.Log(),
 			)
 			if err2 != nil {
@@ -275,12 +315,8 @@ func New(sc ServiceConfig, options ...Option) (*PlatformService, error) {
 	// We need to do this because ps.LoadLicense() called in step 8, could
 	// end up calling InvalidateAllCaches, so the status and session caches
 	// need to be initialized before that.
-
-	// Note: we hardcode the session and status cache to LRU because they lead
-	// to a lot of SCAN calls in case of Redis. We could potentially have a
-	// reverse mapping to avoid the scan, but this needs more complicated code.
-	// Leaving this for now.
-	ps.statusCache, err = cache.NewProvider().NewCache(&cache.CacheOptions{
+	// The session and status caches are created by the local cache provider.
+	ps.statusCache, err = ps.localCacheProvider.NewCache(&cache.CacheOptions{
 		Name:           "Status",
 		Size:           model.StatusCacheSize,
 		Striped:        true,
@@ -291,7 +327,7 @@ func New(sc ServiceConfig, options ...Option) (*PlatformService, error) {
 		return nil, fmt.Errorf("unable to create status cache: %w", err)
 	}
 
-	ps.sessionCache, err = cache.NewProvider().NewCache(&cache.CacheOptions{
+	ps.sessionCache, err = ps.localCacheProvider.NewCache(&cache.CacheOptions{
 		Name:           "Session",
 		Size:           model.SessionCacheSize,
 		Striped:        true,
@@ -311,14 +347,16 @@ func New(sc ServiceConfig, options ...Option) (*PlatformService, error) {
 	// if the license didn't have clustering. But there's an intricate deadlock
 	// where license cannot be loaded before store, and store cannot be loaded before
 	// cache. So loading license before loading cache is an uphill battle.
-	if (license == nil || !*license.Features.Cluster) && *cacheConfig.CacheType == model.CacheTypeRedis && !ps.forceEnableRedis {
+	if (license == nil || !*license.Features.Cluster) && cacheConfig.UsesRedis() && !ps.forceEnableRedis {
 		return nil, fmt.Errorf("Redis cannot be used in an instance without a license or a license without clustering")
 	}
 
 	// Step 9: Initialize filestore
 	if ps.filestore == nil {
 		insecure := ps.Config().ServiceSettings.EnableInsecureOutgoingConnections
-		backend, err2 := filestore.NewFileBackend(filestore.NewFileBackendSettingsFromConfig(&ps.Config().FileSettings, license != nil && *license.Features.Compliance, insecure != nil && *insecure))
+		fileBackendSettings := filestore.NewFileBackendSettingsFromConfig(&ps.Config().FileSettings, license != nil && *license.Features.Compliance, insecure != nil && *insecure)
+		fileBackendSettings.BlobRefStore = ps.Store.FileBlob()
+		backend, err2 := filestore.NewFileBackend(fileBackendSettings)
 		if err2 != nil {
 			return nil, fmt.Errorf("failed to initialize filebackend: %w", err2)
 		}
@@ -393,6 +431,17 @@ func New(sc ServiceConfig, options ...Option) (*PlatformService, error) {
 func (ps *PlatformService) Start(broadcastHooks map[string]BroadcastHook) error {
 	ps.hubStart(broadcastHooks)
 
+	if cacheSettings := ps.Config().CacheSettings; *cacheSettings.EnableWarmup {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*cacheSettings.WarmupTimeoutSeconds)*time.Second)
+		ps.cancelCachesWarmup = cancel
+		ps.Go(func() {
+			defer cancel()
+			ps.warmupCaches(ctx)
+		})
+	} else {
+		ps.cachesWarm.Store(true)
+	}
+
 	ps.configListenerId = ps.AddConfigListener(func(_, _ *model.Config) {
 		ps.regenerateClientConfig()
 
@@ -456,6 +505,11 @@ func (ps *PlatformService) SetLogger(logger *mlog.Logger) {
 }
 
 func (ps *PlatformService) initEnterprise() {
+	// The database transport is built in and, when configured, takes precedence over the enterprise cluster.
+	if ps.clusterIFace == nil && ps.Config().ClusterSettings.UsesDatabaseTransport() {
+		ps.clusterIFace = dbcluster.New(ps)
+	}
+
 	if clusterInterface != nil && ps.clusterIFace == nil {
 		ps.clusterIFace = clusterInterface(ps)
 	}
@@ -489,6 +543,10 @@ func (ps *PlatformService) Shutdown() error {
 
 	ps.RemoveLicenseListener(ps.licenseListenerId)
 
+	if ps.cancelCachesWarmup != nil {
+		ps.cancelCachesWarmup()
+	}
+
 	// we need to wait the goroutines to finish before closing the store
 	// and this needs to be called after hub stop because hub generates goroutines
 	// when it is active. If we wait first we have no mechanism to prevent adding
@@ -499,6 +557,17 @@ func (ps *PlatformService) Shutdown() error {
 		ps.Store.Close()
 	}
 
+	if ps.exportFilestore != nil && ps.exportFilestore != ps.filestore {
+		if err := filestore.CloseFileBackend(ps.exportFilestore); err != nil {
+			ps.logger.Warn("Unable to close the export file backend", mlog.Err(err))
+		}
+	}
+	if ps.filestore != nil {
+		if err := filestore.CloseFileBackend(ps.filestore); err != nil {
+			ps.logger.Warn("Unable to close the file backend", mlog.Err(err))
+		}
+	}
+
 	if ps.cacheProvider != nil {
 		if err := ps.cacheProvider.Close(); err != nil {
 			return fmt.Errorf("unable to cleanly shutdown cache: %w", err)
@@ -508,6 +577,11 @@ func (ps *PlatformService) Shutdown() error {
 	return nil
 }
 
+// GetStore returns the store, meant to be used by the services given the platform as their server.
+func (ps *PlatformService) GetStore() store.Store {
+	return ps.Store
+}
+
 func (ps *PlatformService) CacheProvider() cache.Provider {
 	return ps.cacheProvider
 }
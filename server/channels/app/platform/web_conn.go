@@ -79,6 +79,7 @@ type WebConnConfig struct {
 	activeQueue      chan model.WebSocketMessage
 	deadQueue        []*model.WebSocketEvent
 	deadQueuePointer int
+	subscription     *model.WebSocketSubscription
 }
 
 // WebConn represents a single websocket connection to a user.
@@ -134,6 +135,9 @@ type WebConn struct {
 	activeRHSThreadChannelID        atomic.Value
 	activeThreadViewThreadChannelID atomic.Value
 
+	// subscription narrows down the events sent to the connection, nil meaning every event.
+	subscription atomic.Pointer[webConnSubscription]
+
 	endWritePump chan struct{}
 	pumpFinished chan struct{}
 	pluginPosted chan pluginWSPostedHook
@@ -155,6 +159,7 @@ type CheckConnResult struct {
 	DeadQueue        []*model.WebSocketEvent
 	DeadQueuePointer int
 	ReuseCount       int
+	Subscription     *model.WebSocketSubscription
 }
 
 // PopulateWebConnConfig checks if the connection id already exists in the hub,
@@ -190,6 +195,7 @@ func (ps *PlatformService) PopulateWebConnConfig(s *model.Session, cfg *WebConnC
 		cfg.Active = false
 		cfg.ReuseCount = res.ReuseCount
 		cfg.sequence = seqNum
+		cfg.subscription = res.Subscription
 	}
 	return cfg, nil
 }
@@ -257,6 +263,7 @@ func (ps *PlatformService) NewWebConn(cfg *WebConnConfig, suite SuiteIFace, runn
 		xForwardedFor:      cfg.XForwardedFor,
 	}
 	wc.Active.Store(cfg.Active)
+	wc.setSubscription(cfg.subscription)
 
 	wc.SetSession(&cfg.Session)
 	wc.SetSessionToken(cfg.Session.Token)
@@ -853,6 +860,12 @@ func (wc *WebConn) ShouldSendEventToGuest(msg *model.WebSocketEvent) bool {
 
 // ShouldSendEvent returns whether the message should be sent or not.
 func (wc *WebConn) ShouldSendEvent(msg *model.WebSocketEvent) bool {
+	return wc.shouldSendEvent(msg, wc.Platform.channelTeamOf(msg))
+}
+
+// shouldSendEvent is ShouldSendEvent with the team of the channel of the event looked up by channelTeam,
+// shared by all the connections the hub sends the event to.
+func (wc *WebConn) shouldSendEvent(msg *model.WebSocketEvent, channelTeam func() string) bool {
 	// IMPORTANT: Do not send event if WebConn does not have a session
 	if !wc.IsAuthenticated() {
 		return false
@@ -880,6 +893,10 @@ func (wc *WebConn) ShouldSendEvent(msg *model.WebSocketEvent) bool {
 		}
 	}
 
+	if !wc.isSubscribedTo(msg, channelTeam) {
+		return false
+	}
+
 	// There are two checks here which differentiates between what to send to an admin user and what to send to a normal user.
 	// For websocket events containing sensitive data, we split that to create two events:
 	// 1. We sanitize all fields, and set ContainsSanitizedData to true. This goes to normal users.
@@ -516,6 +516,7 @@ func (h *Hub) Start() {
 						DeadQueue:        conn.deadQueue,
 						DeadQueuePointer: conn.deadQueuePointer,
 						ReuseCount:       conn.reuseCount + 1,
+						Subscription:     conn.Subscription(),
 					}
 				}
 				req.result <- res
@@ -647,12 +648,13 @@ func (h *Hub) Start() {
 				msg, broadcastHooks, broadcastHookArgs := msg.WithoutBroadcastHooks()
 
 				msg = msg.PrecomputeJSON()
+				channelTeam := h.platform.channelTeamOf(msg)
 
 				broadcast := func(webConn *WebConn) {
 					if !connIndex.Has(webConn) {
 						return
 					}
-					if webConn.ShouldSendEvent(msg) {
+					if webConn.shouldSendEvent(msg, channelTeam) {
 						select {
 						case webConn.send <- h.runBroadcastHooks(msg, webConn, broadcastHooks, broadcastHookArgs):
 						default:
@@ -0,0 +1,152 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package platform
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// webConnSubscription is the subscription of a connection indexed for the lookups of the hub.
+// It is never modified once created so that it can be read by the hub while the connection replaces it.
+type webConnSubscription struct {
+	subscription *model.WebSocketSubscription
+	events       map[model.WebsocketEventType]bool
+	channels     map[string]bool
+	teams        map[string]bool
+}
+
+func newWebConnSubscription(subscription *model.WebSocketSubscription) *webConnSubscription {
+	s := &webConnSubscription{
+		subscription: subscription,
+		events:       make(map[model.WebsocketEventType]bool, len(subscription.Events)),
+		channels:     make(map[string]bool, len(subscription.ChannelIds)),
+		teams:        make(map[string]bool, len(subscription.TeamIds)),
+	}
+	for _, event := range subscription.Events {
+		s.events[event] = true
+	}
+	for _, id := range subscription.ChannelIds {
+		s.channels[id] = true
+	}
+	for _, id := range subscription.TeamIds {
+		s.teams[id] = true
+	}
+	return s
+}
+
+// Subscription returns the subscription of the connection, or nil if it receives every event.
+func (wc *WebConn) Subscription() *model.WebSocketSubscription {
+	if s := wc.subscription.Load(); s != nil {
+		return s.subscription
+	}
+	return nil
+}
+
+func (wc *WebConn) setSubscription(subscription *model.WebSocketSubscription) {
+	if subscription == nil || subscription.IsEmpty() {
+		wc.subscription.Store(nil)
+		return
+	}
+	wc.subscription.Store(newWebConnSubscription(subscription))
+}
+
+// Subscribe adds the event types, channels and teams of the subscription to the ones the connection
+// is subscribed to, and returns the resulting subscription.
+func (wc *WebConn) Subscribe(subscription *model.WebSocketSubscription) *model.WebSocketSubscription {
+	merged := &model.WebSocketSubscription{}
+	if current := wc.Subscription(); current != nil {
+		*merged = *current
+	}
+	merged.Events = appendMissing(merged.Events, subscription.Events)
+	merged.ChannelIds = appendMissing(merged.ChannelIds, subscription.ChannelIds)
+	merged.TeamIds = appendMissing(merged.TeamIds, subscription.TeamIds)
+
+	wc.setSubscription(merged)
+	return wc.Subscription()
+}
+
+// Unsubscribe removes the event types, channels and teams of the subscription from the ones the
+// connection is subscribed to, or all of them if the subscription is empty, and returns the resulting
+// subscription. Removing all the entries of a list removes the filter it applies.
+func (wc *WebConn) Unsubscribe(subscription *model.WebSocketSubscription) *model.WebSocketSubscription {
+	current := wc.Subscription()
+	if current == nil || subscription.IsEmpty() {
+		wc.setSubscription(nil)
+		return nil
+	}
+
+	wc.setSubscription(&model.WebSocketSubscription{
+		Events:     removeAll(current.Events, subscription.Events),
+		ChannelIds: removeAll(current.ChannelIds, subscription.ChannelIds),
+		TeamIds:    removeAll(current.TeamIds, subscription.TeamIds),
+	})
+	return wc.Subscription()
+}
+
+// channelTeamOf returns a function returning the team of the channel the event is broadcast to. The team
+// is looked up the first time the function is called, so that the hub looks it up at most once per event
+// whatever the number of connections subscribed to teams.
+func (ps *PlatformService) channelTeamOf(msg *model.WebSocketEvent) func() string {
+	channelID := msg.GetBroadcast().ChannelId
+	return sync.OnceValue(func() string {
+		if channelID == "" {
+			return ""
+		}
+		channel, err := ps.Store.Channel().Get(channelID, true)
+		if err != nil {
+			ps.logger.Warn("Failed to get the channel of the websocket event", mlog.String("channel_id", channelID), mlog.Err(err))
+			return ""
+		}
+		return channel.TeamId
+	})
+}
+
+// isSubscribedTo returns whether the event matches the subscription of the connection, if any. channelTeam
+// returns the team of the channel the event is broadcast to.
+func (wc *WebConn) isSubscribedTo(msg *model.WebSocketEvent, channelTeam func() string) bool {
+	s := wc.subscription.Load()
+	if s == nil {
+		return true
+	}
+
+	if len(s.events) > 0 && !s.events[msg.EventType()] {
+		return false
+	}
+
+	if len(s.channels) == 0 && len(s.teams) == 0 {
+		return true
+	}
+
+	broadcast := msg.GetBroadcast()
+	switch {
+	case broadcast.ChannelId != "":
+		if s.channels[broadcast.ChannelId] {
+			return true
+		}
+		return len(s.teams) > 0 && s.teams[channelTeam()]
+	case broadcast.TeamId != "":
+		return s.teams[broadcast.TeamId]
+	}
+	return true
+}
+
+func appendMissing[S ~[]E, E comparable](s S, values S) S {
+	result := slices.Clone(s)
+	for _, value := range values {
+		if !slices.Contains(result, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func removeAll[S ~[]E, E comparable](s S, values S) S {
+	return slices.DeleteFunc(slices.Clone(s), func(value E) bool {
+		return slices.Contains(values, value)
+	})
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package platform
+
+import (
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestWebConnSubscribe(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	wc := th.Service.NewWebConn(&WebConnConfig{
+		WebSocket: &websocket.Conn{},
+	}, th.Suite, &hookRunner{})
+	require.Nil(t, wc.Subscription())
+
+	channelID := model.NewId()
+	otherChannelID := model.NewId()
+
+	subscription := wc.Subscribe(&model.WebSocketSubscription{
+		Events:     []model.WebsocketEventType{model.WebsocketEventPosted},
+		ChannelIds: []string{channelID},
+	})
+	assert.Equal(t, []model.WebsocketEventType{model.WebsocketEventPosted}, subscription.Events)
+	assert.Equal(t, []string{channelID}, subscription.ChannelIds)
+
+	subscription = wc.Subscribe(&model.WebSocketSubscription{
+		Events:     []model.WebsocketEventType{model.WebsocketEventPosted, model.WebsocketEventPostEdited},
+		ChannelIds: []string{otherChannelID},
+	})
+	assert.Equal(t, []model.WebsocketEventType{model.WebsocketEventPosted, model.WebsocketEventPostEdited}, subscription.Events)
+	assert.Equal(t, []string{channelID, otherChannelID}, subscription.ChannelIds)
+
+	subscription = wc.Unsubscribe(&model.WebSocketSubscription{ChannelIds: []string{channelID}})
+	assert.Equal(t, []string{otherChannelID}, subscription.ChannelIds)
+
+	subscription = wc.Unsubscribe(&model.WebSocketSubscription{})
+	assert.Nil(t, subscription)
+	assert.Nil(t, wc.Subscription())
+}
+
+func TestWebConnIsSubscribedTo(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	wc := th.Service.NewWebConn(&WebConnConfig{
+		WebSocket: &websocket.Conn{},
+	}, th.Suite, &hookRunner{})
+
+	otherTeam := th.CreateTeam()
+	otherChannel := th.CreateChannel(otherTeam)
+
+	posted := func(channelID string) *model.WebSocketEvent {
+		return model.NewWebSocketEvent(model.WebsocketEventPosted, "", channelID, "", nil, "")
+	}
+	typing := model.NewWebSocketEvent(model.WebsocketEventTyping, "", th.BasicChannel.Id, "", nil, "")
+	teamEvent := model.NewWebSocketEvent(model.WebsocketEventUpdateTeam, otherTeam.Id, "", "", nil, "")
+	userEvent := model.NewWebSocketEvent(model.WebsocketEventPreferencesChanged, "", "", th.BasicUser.Id, nil, "")
+	subscribed := func(msg *model.WebSocketEvent) bool {
+		return wc.isSubscribedTo(msg, th.Service.channelTeamOf(msg))
+	}
+
+	t.Run("without subscription", func(t *testing.T) {
+		assert.True(t, subscribed(posted(th.BasicChannel.Id)))
+		assert.True(t, subscribed(typing))
+		assert.True(t, subscribed(teamEvent))
+	})
+
+	t.Run("subscribed to event types", func(t *testing.T) {
+		wc.Subscribe(&model.WebSocketSubscription{Events: []model.WebsocketEventType{model.WebsocketEventPosted}})
+		defer wc.Unsubscribe(&model.WebSocketSubscription{})
+
+		assert.True(t, subscribed(posted(th.BasicChannel.Id)))
+		assert.False(t, subscribed(typing))
+		assert.False(t, subscribed(userEvent))
+	})
+
+	t.Run("subscribed to channels", func(t *testing.T) {
+		wc.Subscribe(&model.WebSocketSubscription{ChannelIds: []string{th.BasicChannel.Id}})
+		defer wc.Unsubscribe(&model.WebSocketSubscription{})
+
+		assert.True(t, subscribed(posted(th.BasicChannel.Id)))
+		assert.True(t, subscribed(typing))
+		assert.False(t, subscribed(posted(otherChannel.Id)))
+		assert.False(t, subscribed(teamEvent))
+		assert.True(t, subscribed(userEvent))
+	})
+
+	t.Run("subscribed to teams", func(t *testing.T) {
+		wc.Subscribe(&model.WebSocketSubscription{TeamIds: []string{otherTeam.Id}})
+		defer wc.Unsubscribe(&model.WebSocketSubscription{})
+
+		assert.False(t, subscribed(posted(th.BasicChannel.Id)))
+		assert.True(t, subscribed(posted(otherChannel.Id)))
+		assert.True(t, subscribed(teamEvent))
+		assert.True(t, subscribed(userEvent))
+	})
+
+	t.Run("subscribed to event types and channels", func(t *testing.T) {
+		wc.Subscribe(&model.WebSocketSubscription{
+			Events:     []model.WebsocketEventType{model.WebsocketEventPosted},
+			ChannelIds: []string{th.BasicChannel.Id},
+		})
+		defer wc.Unsubscribe(&model.WebSocketSubscription{})
+
+		assert.True(t, subscribed(posted(th.BasicChannel.Id)))
+		assert.False(t, subscribed(typing))
+		assert.False(t, subscribed(posted(otherChannel.Id)))
+	})
+
+	t.Run("team of the channel looked up only for the subscriptions to teams", func(t *testing.T) {
+		var lookups int
+		channelTeam := func() string {
+			lookups++
+			return otherTeam.Id
+		}
+
+		assert.True(t, wc.isSubscribedTo(posted(otherChannel.Id), channelTeam))
+		wc.Subscribe(&model.WebSocketSubscription{ChannelIds: []string{th.BasicChannel.Id}})
+		defer wc.Unsubscribe(&model.WebSocketSubscription{})
+		assert.False(t, wc.isSubscribedTo(posted(otherChannel.Id), channelTeam))
+		assert.Zero(t, lookups)
+
+		wc.Subscribe(&model.WebSocketSubscription{TeamIds: []string{otherTeam.Id}})
+		assert.True(t, wc.isSubscribedTo(posted(otherChannel.Id), channelTeam))
+		assert.Equal(t, 1, lookups)
+	})
+
+	t.Run("team of the channel", func(t *testing.T) {
+		assert.Equal(t, otherTeam.Id, th.Service.channelTeamOf(posted(otherChannel.Id))())
+		assert.Empty(t, th.Service.channelTeamOf(teamEvent)())
+		assert.Empty(t, th.Service.channelTeamOf(posted(model.NewId()))())
+	})
+}
@@ -576,7 +576,12 @@ func (api *PluginAPI) SearchPostsInTeamForUser(teamID string, userID string, sea
 		includeDeletedChannels = *searchParams.IncludeDeletedChannels
 	}
 
-	results, appErr := api.app.SearchPostsForUser(api.ctx, terms, userID, teamID, isOrSearch, includeDeletedChannels, timeZoneOffset, page, perPage)
+	sort := model.SearchSortRecent
+	if searchParams.Sort != nil && model.IsValidSearchSort(*searchParams.Sort) {
+		sort = *searchParams.Sort
+	}
+
+	results, appErr := api.app.SearchPostsForUser(api.ctx, terms, userID, teamID, isOrSearch, includeDeletedChannels, timeZoneOffset, sort, page, perPage)
 	if results != nil {
 		results = results.ForPlugin()
 	}
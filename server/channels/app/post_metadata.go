@@ -116,6 +116,11 @@ func (a *App) OverrideIconURLIfEmoji(c request.CTX, post *model.Post) {
 func (a *App) PreparePostForClient(c request.CTX, originalPost *model.Post, isNewPost, isEditPost, includePriority bool) *model.Post {
 	post := originalPost.Clone()
 
+	// The import id is only used by the bulk import.
+	if _, ok := post.GetProps()[model.PostPropsImportId]; ok {
+		post.DelProp(model.PostPropsImportId)
+	}
+
 	// Proxy image links before constructing metadata so that requests go through the proxy
 	post = a.PostWithProxyAddedToImageURLs(post)
 
@@ -164,6 +164,25 @@ func TestPreparePostForClient(t *testing.T) {
 		assert.Equal(t, clientPost, post, "shouldn't have changed any metadata")
 	})
 
+	t.Run("import id", func(t *testing.T) {
+		th := setup(t)
+		defer th.TearDown()
+
+		post := &model.Post{
+			Message: model.NewId(),
+			Props: model.StringInterface{
+				model.PostPropsImportId: model.NewId(),
+				"attachments":           "good",
+			},
+		}
+
+		clientPost := th.App.PreparePostForClient(th.Context, post, false, false, false)
+
+		assert.Nil(t, clientPost.GetProp(model.PostPropsImportId), "should've removed the import id")
+		assert.NotNil(t, clientPost.GetProp("attachments"))
+		assert.NotNil(t, post.GetProp(model.PostPropsImportId), "shouldn't have mutated post.Props")
+	})
+
 	t.Run("reactions", func(t *testing.T) {
 		th := setup(t)
 		defer th.TearDown()
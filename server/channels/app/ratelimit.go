@@ -6,6 +6,8 @@ package app
 import (
 	"math"
 	"net/http"
+	"path"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -17,30 +19,91 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/i18n"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/mattermost/mattermost/server/v8/channels/utils"
+	"github.com/mattermost/mattermost/server/v8/einterfaces"
 )
 
+// globalRateLimitStoreName is the name of the store of the counters of the global quota, which cannot be
+// the name of a policy since the names of the policies are prefixed.
+const globalRateLimitStoreName = "global"
+
 type RateLimiter struct {
 	throttledRateLimiter *throttled.GCRARateLimiter
 	useAuth              bool
 	useIP                bool
 	header               string
 	trustedProxyIPHeader []string
+	policies             []*rateLimitPolicy
+	metrics              einterfaces.MetricsInterface
 }
 
-func NewRateLimiter(settings *model.RateLimitSettings, trustedProxyIPHeader []string) (*RateLimiter, error) {
-	store, err := memstore.New(*settings.MemoryStoreSize)
-	if err != nil {
-		return nil, errors.Wrap(err, i18n.T("api.server.start_server.rate_limiting_memory_store"))
+// rateLimitPolicy is a policy of the rate limit settings along with the rate limiter enforcing its quota.
+type rateLimitPolicy struct {
+	name                 string
+	routes               []string
+	methods              []string
+	authTypes            []string
+	roles                []string
+	throttledRateLimiter *throttled.GCRARateLimiter
+}
+
+// RateLimitStoreFactory returns the store of the counters of the named quota.
+type RateLimitStoreFactory func(name string) (throttled.GCRAStore, error)
+
+type RateLimiterOption func(rl *rateLimiterOptions)
+
+type rateLimiterOptions struct {
+	newStore RateLimitStoreFactory
+	metrics  einterfaces.MetricsInterface
+}
+
+// WithRateLimitStore keeps the counters in the stores returned by the factory, e.g. to share them across
+// the nodes of a cluster, instead of in memory.
+func WithRateLimitStore(newStore RateLimitStoreFactory) RateLimiterOption {
+	return func(opts *rateLimiterOptions) {
+		opts.newStore = newStore
 	}
+}
 
-	quota := throttled.RateQuota{
-		MaxRate:  throttled.PerSec(*settings.PerSec),
-		MaxBurst: *settings.MaxBurst,
+// WithRateLimitMetrics counts the requests rejected by each policy.
+func WithRateLimitMetrics(metrics einterfaces.MetricsInterface) RateLimiterOption {
+	return func(opts *rateLimiterOptions) {
+		opts.metrics = metrics
 	}
+}
 
-	throttledRateLimiter, err := throttled.NewGCRARateLimiter(store, quota)
+func NewRateLimiter(settings *model.RateLimitSettings, trustedProxyIPHeader []string, options ...RateLimiterOption) (*RateLimiter, error) {
+	opts := rateLimiterOptions{
+		newStore: func(string) (throttled.GCRAStore, error) {
+			store, err := memstore.New(*settings.MemoryStoreSize)
+			if err != nil {
+				return nil, errors.Wrap(err, i18n.T("api.server.start_server.rate_limiting_memory_store"))
+			}
+			return store, nil
+		},
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	throttledRateLimiter, err := newThrottledRateLimiter(opts.newStore, globalRateLimitStoreName, *settings.PerSec, *settings.MaxBurst)
 	if err != nil {
-		return nil, errors.Wrap(err, i18n.T("api.server.start_server.rate_limiting_rate_limiter"))
+		return nil, err
+	}
+
+	policies := make([]*rateLimitPolicy, 0, len(settings.Policies))
+	for _, policy := range settings.Policies {
+		policyRateLimiter, err := newThrottledRateLimiter(opts.newStore, "policy:"+*policy.Name, *policy.PerSec, *policy.MaxBurst)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, &rateLimitPolicy{
+			name:                 *policy.Name,
+			routes:               policy.Routes,
+			methods:              policy.Methods,
+			authTypes:            policy.AuthTypes,
+			roles:                policy.Roles,
+			throttledRateLimiter: policyRateLimiter,
+		})
 	}
 
 	return &RateLimiter{
@@ -49,9 +112,29 @@ func NewRateLimiter(settings *model.RateLimitSettings, trustedProxyIPHeader []st
 		useIP:                *settings.VaryByRemoteAddr,
 		header:               settings.VaryByHeader,
 		trustedProxyIPHeader: trustedProxyIPHeader,
+		policies:             policies,
+		metrics:              opts.metrics,
 	}, nil
 }
 
+func newThrottledRateLimiter(newStore RateLimitStoreFactory, name string, perSec, maxBurst int) (*throttled.GCRARateLimiter, error) {
+	store, err := newStore(name)
+	if err != nil {
+		return nil, err
+	}
+
+	quota := throttled.RateQuota{
+		MaxRate:  throttled.PerSec(perSec),
+		MaxBurst: maxBurst,
+	}
+
+	throttledRateLimiter, err := throttled.NewGCRARateLimiter(store, quota)
+	if err != nil {
+		return nil, errors.Wrap(err, i18n.T("api.server.start_server.rate_limiting_rate_limiter"))
+	}
+	return throttledRateLimiter, nil
+}
+
 func (rl *RateLimiter) GenerateKey(r *http.Request) string {
 	key := ""
 
@@ -109,23 +192,136 @@ func (rl *RateLimiter) RateLimitHandler(wrappedHandler http.Handler) http.Handle
 	})
 }
 
+// RateLimitRequest describes a request to match against the rate limit policies.
+type RateLimitRequest struct {
+	// Path is the path of the request relative to the site URL.
+	Path   string
+	Method string
+	// AuthType is how the request is authenticated, one of the model.RateLimitAuthType constants.
+	AuthType string
+	// Session is the session of the request, if authenticated by one.
+	Session *model.Session
+	// HookId is the id of the webhook of the route, if the request calls a webhook.
+	HookId string
+}
+
+// PolicyRateLimit applies the quota of the first policy matching the request, keyed by the requesting user,
+// by the incoming webhook or, for unauthenticated requests, as the global quota is. It writes the headers of
+// the quota and returns whether the request was rejected, in which case the response has been written.
+func (rl *RateLimiter) PolicyRateLimit(w http.ResponseWriter, r *http.Request, rr *RateLimitRequest) bool {
+	policy := rl.matchPolicy(rr)
+	if policy == nil {
+		return false
+	}
+
+	key := rl.policyKey(r, rr)
+	limited, context, err := policy.throttledRateLimiter.RateLimit(key, 1)
+	if err != nil {
+		mlog.Error("Internal server error when rate limiting. Rate Limiting broken.", mlog.String("policy", policy.name), mlog.Err(err))
+		return false
+	}
+
+	w.Header().Set("X-RateLimit-Policy", policy.name)
+	setRateLimitHeaders(w, context)
+
+	if limited {
+		mlog.Debug("Denied due to rate limit policy code=429", mlog.String("policy", policy.name), mlog.String("key", key))
+		if rl.metrics != nil {
+			rl.metrics.IncrementRateLimitRejection(policy.name)
+		}
+		http.Error(w, "limit exceeded", http.StatusTooManyRequests)
+	}
+
+	return limited
+}
+
+func (rl *RateLimiter) matchPolicy(rr *RateLimitRequest) *rateLimitPolicy {
+	var roles []string
+	if rr.Session != nil {
+		roles = rr.Session.GetUserRoles()
+	}
+
+	for _, policy := range rl.policies {
+		if policy.matches(rr, roles) {
+			return policy
+		}
+	}
+	return nil
+}
+
+func (rl *RateLimiter) policyKey(r *http.Request, rr *RateLimitRequest) string {
+	switch {
+	case rr.AuthType == model.RateLimitAuthTypeIncomingWebhook && rr.HookId != "":
+		return "hook:" + rr.HookId
+	case rr.Session != nil && rr.Session.UserId != "":
+		return "user:" + rr.Session.UserId
+	default:
+		return "key:" + rl.GenerateKey(r)
+	}
+}
+
+func (p *rateLimitPolicy) matches(rr *RateLimitRequest, roles []string) bool {
+	if len(p.methods) > 0 && !slices.ContainsFunc(p.methods, func(method string) bool {
+		return strings.EqualFold(method, rr.Method)
+	}) {
+		return false
+	}
+
+	if len(p.authTypes) > 0 && !slices.Contains(p.authTypes, rr.AuthType) {
+		return false
+	}
+
+	if len(p.roles) > 0 && !slices.ContainsFunc(p.roles, func(role string) bool {
+		return slices.Contains(roles, role)
+	}) {
+		return false
+	}
+
+	if len(p.routes) > 0 && !slices.ContainsFunc(p.routes, func(route string) bool {
+		return matchRateLimitRoute(route, rr.Path)
+	}) {
+		return false
+	}
+
+	return true
+}
+
+// matchRateLimitRoute returns whether the path matches the route pattern of a policy, as matched by
+// path.Match, a pattern ending with "/**" matching the paths below it.
+func matchRateLimitRoute(pattern, requestPath string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		if requestPath == prefix {
+			return true
+		}
+		for dir := path.Dir(requestPath); dir != "/" && dir != "."; dir = path.Dir(dir) {
+			if matched, _ := path.Match(prefix, dir); matched {
+				return true
+			}
+		}
+		return false
+	}
+
+	matched, _ := path.Match(pattern, requestPath)
+	return matched
+}
+
 // Copied from https://github.com/throttled/throttled http.go
 func setRateLimitHeaders(w http.ResponseWriter, context throttled.RateLimitResult) {
 	if v := context.Limit; v >= 0 {
-		w.Header().Add("X-RateLimit-Limit", strconv.Itoa(v))
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(v))
 	}
 
 	if v := context.Remaining; v >= 0 {
-		w.Header().Add("X-RateLimit-Remaining", strconv.Itoa(v))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(v))
 	}
 
 	if v := context.ResetAfter; v >= 0 {
 		vi := int(math.Ceil(v.Seconds()))
-		w.Header().Add("X-RateLimit-Reset", strconv.Itoa(vi))
+		w.Header().Set("X-RateLimit-Reset", strconv.Itoa(vi))
 	}
 
 	if v := context.RetryAfter; v >= 0 {
 		vi := int(math.Ceil(v.Seconds()))
-		w.Header().Add("Retry-After", strconv.Itoa(vi))
+		w.Header().Set("Retry-After", strconv.Itoa(vi))
 	}
 }
@@ -10,8 +10,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/throttled/throttled"
+	"github.com/throttled/throttled/store/memstore"
 
 	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/einterfaces/mocks"
 )
 
 func genRateLimitSettings(useAuth, useIP bool, header string) *model.RateLimitSettings {
@@ -103,3 +106,140 @@ func TestGenerateKey_TrustedHeader(t *testing.T) {
 	key = rateLimiter.GenerateKey(req)
 	require.Equal(t, "10.10.10.5", key, "Wrong key on test without allowed trusted proxy header")
 }
+
+func TestNewRateLimiterStore(t *testing.T) {
+	settings := genRateLimitSettings(false, true, "")
+	settings.Policies = []*model.RateLimitPolicy{{Name: model.NewPointer("posts")}}
+	settings.SetDefaults()
+
+	var names []string
+	rateLimiter, err := NewRateLimiter(settings, nil, WithRateLimitStore(func(name string) (throttled.GCRAStore, error) {
+		names = append(names, name)
+		return memstore.New(0)
+	}))
+	require.NoError(t, err)
+	require.NotNil(t, rateLimiter)
+	require.Equal(t, []string{"global", "policy:posts"}, names)
+}
+
+func TestMatchRateLimitRoute(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		matches bool
+	}{
+		{"/api/v4/posts", "/api/v4/posts", true},
+		{"/api/v4/posts", "/api/v4/posts/abc", false},
+		{"/api/v4/posts/*", "/api/v4/posts/abc", true},
+		{"/api/v4/posts/*", "/api/v4/posts/abc/reactions", false},
+		{"/api/v4/posts/**", "/api/v4/posts", true},
+		{"/api/v4/posts/**", "/api/v4/posts/abc/reactions", true},
+		{"/api/v4/posts/**", "/api/v4/postsearch", false},
+		{"/api/v4/channels/*/posts/**", "/api/v4/channels/abc/posts/xyz", true},
+		{"/api/v4/channels/*/posts/**", "/api/v4/channels/abc/members", false},
+		{"/hooks/*", "/hooks/abc", true},
+	}
+
+	for _, tc := range cases {
+		require.Equal(t, tc.matches, matchRateLimitRoute(tc.pattern, tc.path), "pattern %s path %s", tc.pattern, tc.path)
+	}
+}
+
+func TestPolicyRateLimit(t *testing.T) {
+	settings := genRateLimitSettings(false, true, "")
+	settings.Policies = []*model.RateLimitPolicy{
+		{
+			Name:      model.NewPointer("bot-posts"),
+			Routes:    []string{"/api/v4/posts/**"},
+			Methods:   []string{http.MethodPost},
+			AuthTypes: []string{model.RateLimitAuthTypeBot},
+			MaxBurst:  model.NewPointer(1),
+			PerSec:    model.NewPointer(1),
+		},
+		{
+			Name:     model.NewPointer("admins"),
+			Roles:    []string{model.SystemAdminRoleId},
+			MaxBurst: model.NewPointer(50),
+		},
+		{
+			Name:      model.NewPointer("hooks"),
+			AuthTypes: []string{model.RateLimitAuthTypeIncomingWebhook},
+			MaxBurst:  model.NewPointer(1),
+			PerSec:    model.NewPointer(1),
+		},
+	}
+	settings.SetDefaults()
+
+	metricsMock := &mocks.MetricsInterface{}
+	metricsMock.On("IncrementRateLimitRejection", "bot-posts").Return().Once()
+	metricsMock.On("IncrementRateLimitRejection", "hooks").Return().Once()
+
+	rateLimiter, err := NewRateLimiter(settings, nil, WithRateLimitMetrics(metricsMock))
+	require.NoError(t, err)
+
+	bot := &model.Session{Id: model.NewId(), UserId: model.NewId(), Roles: model.SystemUserRoleId}
+	admin := &model.Session{Id: model.NewId(), UserId: model.NewId(), Roles: model.SystemUserRoleId + " " + model.SystemAdminRoleId}
+
+	send := func(rr *RateLimitRequest) (*httptest.ResponseRecorder, bool) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(rr.Method, rr.Path, nil)
+		return w, rateLimiter.PolicyRateLimit(w, r, rr)
+	}
+
+	t.Run("first matching policy", func(t *testing.T) {
+		botPost := &RateLimitRequest{Path: "/api/v4/posts", Method: http.MethodPost, AuthType: model.RateLimitAuthTypeBot, Session: bot}
+
+		w, limited := send(botPost)
+		require.False(t, limited)
+		require.Equal(t, "bot-posts", w.Header().Get("X-RateLimit-Policy"))
+		require.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+		require.Equal(t, "1", w.Header().Get("X-RateLimit-Remaining"))
+
+		_, limited = send(botPost)
+		require.False(t, limited)
+
+		w, limited = send(botPost)
+		require.True(t, limited)
+		require.Equal(t, http.StatusTooManyRequests, w.Code)
+		require.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+		require.NotEmpty(t, w.Header().Get("Retry-After"))
+
+		// Another bot has its own quota.
+		other := &model.Session{Id: model.NewId(), UserId: model.NewId(), Roles: model.SystemUserRoleId}
+		_, limited = send(&RateLimitRequest{Path: "/api/v4/posts", Method: http.MethodPost, AuthType: model.RateLimitAuthTypeBot, Session: other})
+		require.False(t, limited)
+	})
+
+	t.Run("matching by role", func(t *testing.T) {
+		w, limited := send(&RateLimitRequest{Path: "/api/v4/channels", Method: http.MethodGet, AuthType: model.RateLimitAuthTypeSession, Session: admin})
+		require.False(t, limited)
+		require.Equal(t, "admins", w.Header().Get("X-RateLimit-Policy"))
+		require.Equal(t, "51", w.Header().Get("X-RateLimit-Limit"))
+	})
+
+	t.Run("no matching policy", func(t *testing.T) {
+		user := &model.Session{Id: model.NewId(), UserId: model.NewId(), Roles: model.SystemUserRoleId}
+		for range 5 {
+			w, limited := send(&RateLimitRequest{Path: "/api/v4/posts", Method: http.MethodPost, AuthType: model.RateLimitAuthTypeSession, Session: user})
+			require.False(t, limited)
+			require.Empty(t, w.Header().Get("X-RateLimit-Policy"))
+		}
+	})
+
+	t.Run("incoming webhooks", func(t *testing.T) {
+		hookID := model.NewId()
+		hook := &RateLimitRequest{Path: "/hooks/" + hookID, Method: http.MethodPost, AuthType: model.RateLimitAuthTypeIncomingWebhook, HookId: hookID}
+		for range 2 {
+			_, limited := send(hook)
+			require.False(t, limited)
+		}
+		_, limited := send(hook)
+		require.True(t, limited)
+
+		otherHookID := model.NewId()
+		_, limited = send(&RateLimitRequest{Path: "/hooks/" + otherHookID, Method: http.MethodPost, AuthType: model.RateLimitAuthTypeIncomingWebhook, HookId: otherHookID})
+		require.False(t, limited)
+	})
+
+	metricsMock.AssertExpectations(t)
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/i18n"
@@ -16,9 +17,11 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/request"
 )
 
+// SaveReportChunk saves a chunk of the report. The chunks are saved as CSV whatever the format of the report, and
+// converted to it when compiled.
 func (a *App) SaveReportChunk(format string, prefix string, count int, reportData []model.ReportableObject) *model.AppError {
 	switch format {
-	case "csv":
+	case model.ReportExportFormatCSV, model.ReportExportFormatJSON, model.ReportExportFormatXLSX:
 		return a.saveCSVChunk(prefix, count, reportData)
 	}
 	return model.NewAppError("SaveReportChunk", "app.save_report_chunk.unsupported_format", nil, "unsupported report format", http.StatusBadRequest)
@@ -45,12 +48,50 @@ func (a *App) saveCSVChunk(prefix string, count int, reportData []model.Reportab
 
 func (a *App) CompileReportChunks(format string, prefix string, numberOfChunks int, headers []string) *model.AppError {
 	switch format {
-	case "csv":
+	case model.ReportExportFormatCSV:
 		return a.compileCSVChunks(prefix, numberOfChunks, headers)
+	case model.ReportExportFormatJSON:
+		var buf bytes.Buffer
+		return a.compileChunks(prefix, numberOfChunks, format, &buf, newJSONReportWriter(&buf, headers))
+	case model.ReportExportFormatXLSX:
+		var buf bytes.Buffer
+		w, err := newXLSXReportWriter(&buf, headers)
+		if err != nil {
+			return model.NewAppError("CompileReportChunks", "app.compile_report_chunks.write_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+		return a.compileChunks(prefix, numberOfChunks, format, &buf, w)
 	}
 	return model.NewAppError("CompileReportChunks", "app.compile_report_chunks.unsupported_format", nil, "", http.StatusBadRequest)
 }
 
+// compileChunks converts the records of the CSV chunks with the writer, writing to buf, and saves the compiled report.
+func (a *App) compileChunks(prefix string, numberOfChunks int, format string, buf *bytes.Buffer, w reportRecordWriter) *model.AppError {
+	for i := 0; i < numberOfChunks; i++ {
+		chunk, appErr := a.ReadFile(makeFilePath(prefix, i, "csv"))
+		if appErr != nil {
+			return appErr
+		}
+
+		r := csv.NewReader(bytes.NewReader(chunk))
+		r.FieldsPerRecord = -1
+		records, err := r.ReadAll()
+		if err != nil {
+			return model.NewAppError("compileChunks", "app.compile_report_chunks.read_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+		for _, record := range records {
+			if err := w.Write(record); err != nil {
+				return model.NewAppError("compileChunks", "app.compile_report_chunks.write_error", nil, "", http.StatusInternalServerError).Wrap(err)
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		return model.NewAppError("compileChunks", "app.compile_report_chunks.write_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	_, appErr := a.WriteFile(buf, makeCompiledFilePath(prefix, format))
+	return appErr
+}
+
 func (a *App) compileCSVChunks(prefix string, numberOfChunks int, headers []string) *model.AppError {
 	filePath := makeCompiledFilePath(prefix, "csv")
 
@@ -85,6 +126,38 @@ func (a *App) compileCSVChunks(prefix string, numberOfChunks int, headers []stri
 	return nil
 }
 
+// batchReport holds the messages the system bot sends for a type of batch report job.
+type batchReport struct {
+	startedMessageId  string
+	finishedMessageId string
+}
+
+var batchReports = map[string]batchReport{
+	model.JobTypeExportUsersToCSV: {
+		startedMessageId:  "app.report.start_users_batch_export.started_export",
+		finishedMessageId: "app.report.send_report_to_user.export_finished",
+	},
+	model.JobTypeExportGuestAccountsReport: {
+		startedMessageId:  "app.report.start_guest_accounts_batch_export.started_export",
+		finishedMessageId: "app.report.send_report_to_user.guest_accounts_export_finished",
+	},
+	model.JobTypeExportChannelActivityReport: {
+		startedMessageId:  "app.report.start_channel_activity_batch_export.started_export",
+		finishedMessageId: "app.report.send_report_to_user.channel_activity_export_finished",
+	},
+	model.JobTypeExportTeamMembershipReport: {
+		startedMessageId:  "app.report.start_team_membership_batch_export.started_export",
+		finishedMessageId: "app.report.send_report_to_user.team_membership_export_finished",
+	},
+}
+
+func getBatchReport(jobType string) batchReport {
+	if report, ok := batchReports[jobType]; ok {
+		return report
+	}
+	return batchReports[model.JobTypeExportUsersToCSV]
+}
+
 func (a *App) SendReportToUser(rctx request.CTX, job *model.Job, format string) *model.AppError {
 	requestingUserId := job.Data["requesting_user_id"]
 	if requestingUserId == "" {
@@ -128,8 +201,9 @@ func (a *App) SendReportToUser(rctx request.CTX, job *model.Job, format string)
 	T := i18n.GetUserTranslations(user.Locale)
 	post := &model.Post{
 		ChannelId: channel.Id,
-		Message: T("app.report.send_report_to_user.export_finished", map[string]string{
+		Message: T(getBatchReport(job.Type).finishedMessageId, map[string]string{
 			"DateRange": getTranslatedDateRange(dateRange),
+			"Format":    strings.ToUpper(format),
 		}),
 		Type:    model.PostTypeDefault,
 		UserId:  systemBot.UserId,
@@ -142,7 +216,7 @@ func (a *App) SendReportToUser(rctx request.CTX, job *model.Job, format string)
 
 func (a *App) CleanupReportChunks(format string, prefix string, numberOfChunks int) *model.AppError {
 	switch format {
-	case "csv":
+	case model.ReportExportFormatCSV, model.ReportExportFormatJSON, model.ReportExportFormatXLSX:
 		return a.cleanupCSVChunks(prefix, numberOfChunks)
 	}
 	return model.NewAppError("CompileReportChunks", "app.compile_report_chunks.unsupported_format", nil, "", http.StatusBadRequest)
@@ -198,12 +272,26 @@ func (a *App) GetUserCountForReport(filter *model.UserReportOptions) (*int64, *m
 	return &count, nil
 }
 
-func (a *App) StartUsersBatchExport(rctx request.CTX, ro *model.UserReportOptions, startAt int64, endAt int64) *model.AppError {
-	if license := a.Srv().License(); license == nil || (license.SkuShortName != model.LicenseShortSkuProfessional && license.SkuShortName != model.LicenseShortSkuEnterprise) {
-		return model.NewAppError("StartUsersBatchExport", "app.report.start_users_batch_export.license_error", nil, "", http.StatusBadRequest)
+func (a *App) GetChannelActivityForReporting(options *model.ChannelActivityReportOptions) ([]*model.ChannelActivityReport, *model.AppError) {
+	channels, err := a.Srv().Store().Channel().GetChannelActivityReport(options)
+	if err != nil {
+		return nil, model.NewAppError("GetChannelActivityForReporting", "app.report.get_channel_activity_report.store_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return channels, nil
+}
+
+func (a *App) GetTeamMembershipForReporting(options *model.TeamMembershipReportOptions) ([]*model.TeamMembershipReport, *model.AppError) {
+	members, err := a.Srv().Store().Team().GetTeamMembershipReport(options)
+	if err != nil {
+		return nil, model.NewAppError("GetTeamMembershipForReporting", "app.report.get_team_membership_report.store_error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 
-	options := map[string]string{
+	return members, nil
+}
+
+func userReportJobData(rctx request.CTX, ro *model.UserReportOptions, startAt int64, endAt int64, format string) map[string]string {
+	return map[string]string{
 		"requesting_user_id": rctx.Session().UserId,
 		"date_range":         ro.DateRange,
 		"role":               ro.Role,
@@ -212,14 +300,57 @@ func (a *App) StartUsersBatchExport(rctx request.CTX, ro *model.UserReportOption
 		"hide_inactive":      strconv.FormatBool(ro.HideInactive),
 		"start_at":           strconv.FormatInt(startAt, 10),
 		"end_at":             strconv.FormatInt(endAt, 10),
+		"format":             format,
+	}
+}
+
+func (a *App) StartUsersBatchExport(rctx request.CTX, ro *model.UserReportOptions, startAt int64, endAt int64, format string) *model.AppError {
+	return a.startBatchReport(rctx, model.JobTypeExportUsersToCSV, userReportJobData(rctx, ro, startAt, endAt, format))
+}
+
+// StartGuestAccountsBatchExport starts the export of the users report restricted to the guest accounts.
+func (a *App) StartGuestAccountsBatchExport(rctx request.CTX, ro *model.UserReportOptions, startAt int64, endAt int64, format string) *model.AppError {
+	ro.Role = model.SystemGuestRoleId
+	return a.startBatchReport(rctx, model.JobTypeExportGuestAccountsReport, userReportJobData(rctx, ro, startAt, endAt, format))
+}
+
+func (a *App) StartChannelActivityBatchExport(rctx request.CTX, teamID string, dateRange string, startAt int64, endAt int64, format string) *model.AppError {
+	return a.startBatchReport(rctx, model.JobTypeExportChannelActivityReport, map[string]string{
+		"requesting_user_id": rctx.Session().UserId,
+		"date_range":         dateRange,
+		"team":               teamID,
+		"start_at":           strconv.FormatInt(startAt, 10),
+		"end_at":             strconv.FormatInt(endAt, 10),
+		"format":             format,
+	})
+}
+
+func (a *App) StartTeamMembershipBatchExport(rctx request.CTX, teamID string, format string) *model.AppError {
+	return a.startBatchReport(rctx, model.JobTypeExportTeamMembershipReport, map[string]string{
+		"requesting_user_id": rctx.Session().UserId,
+		"date_range":         model.ReportDurationAllTime,
+		"team":               teamID,
+		"format":             format,
+	})
+}
+
+// startBatchReport creates the batch report job of the type, and lets the requesting user know in a direct message
+// from the system bot that the report will be delivered there.
+func (a *App) startBatchReport(rctx request.CTX, jobType string, options map[string]string) *model.AppError {
+	if license := a.Srv().License(); license == nil || (license.SkuShortName != model.LicenseShortSkuProfessional && license.SkuShortName != model.LicenseShortSkuEnterprise) {
+		return model.NewAppError("startBatchReport", "app.report.start_users_batch_export.license_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !model.IsValidReportExportFormat(options["format"]) {
+		return model.NewAppError("startBatchReport", "app.report.start_batch_report.invalid_format", map[string]any{"Format": options["format"]}, "", http.StatusBadRequest)
 	}
 
 	// Check for existing jobs
-	if err := a.checkForExistingJobs(rctx, options, model.JobTypeExportUsersToCSV); err != nil {
+	if err := a.checkForExistingJobs(rctx, options, jobType); err != nil {
 		return err
 	}
 
-	_, err := a.Srv().Jobs.CreateJob(rctx, model.JobTypeExportUsersToCSV, options)
+	_, err := a.Srv().Jobs.CreateJob(rctx, jobType, options)
 	if err != nil {
 		return err
 	}
@@ -245,9 +376,12 @@ func (a *App) StartUsersBatchExport(rctx request.CTX, ro *model.UserReportOption
 		T := i18n.GetUserTranslations(user.Locale)
 		post := &model.Post{
 			ChannelId: channel.Id,
-			Message:   T("app.report.start_users_batch_export.started_export", map[string]string{"DateRange": getTranslatedDateRange(ro.DateRange)}),
-			Type:      model.PostTypeDefault,
-			UserId:    systemBot.UserId,
+			Message: T(getBatchReport(jobType).startedMessageId, map[string]string{
+				"DateRange": getTranslatedDateRange(options["date_range"]),
+				"Format":    strings.ToUpper(options["format"]),
+			}),
+			Type:   model.PostTypeDefault,
+			UserId: systemBot.UserId,
 		}
 
 		if _, err := a.CreatePost(rctx, post, channel, model.CreatePostFlags{SetOnline: true}); err != nil {
@@ -260,14 +394,17 @@ func (a *App) StartUsersBatchExport(rctx request.CTX, ro *model.UserReportOption
 
 // Helper function to check for existing or pending jobs
 func (a *App) checkForExistingJobs(rctx request.CTX, options map[string]string, jobType string) *model.AppError {
+	// The start and end of the date range move with the time of the request, the date range is compared instead.
 	checkJobExists := func(jobs []*model.Job, options map[string]string) bool {
 		for _, job := range jobs {
-			if job.Data["date_range"] == options["date_range"] &&
-				job.Data["requesting_user_id"] == options["requesting_user_id"] &&
-				job.Data["role"] == options["role"] &&
-				job.Data["team"] == options["team"] &&
-				job.Data["hide_active"] == options["hide_active"] &&
-				job.Data["hide_inactive"] == options["hide_inactive"] {
+			exists := true
+			for key, value := range options {
+				if key != "start_at" && key != "end_at" && job.Data[key] != value {
+					exists = false
+					break
+				}
+			}
+			if exists {
 				return true
 			}
 		}
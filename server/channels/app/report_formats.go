@@ -0,0 +1,167 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// reportRecordWriter writes the records of a report compiled in a format other than CSV, the headers being written
+// when the writer is created.
+type reportRecordWriter interface {
+	Write(record []string) error
+	Close() error
+}
+
+// jsonReportWriter writes a report as a JSON array of objects keyed by the headers of the report.
+type jsonReportWriter struct {
+	w       io.Writer
+	headers []string
+	count   int
+}
+
+func newJSONReportWriter(w io.Writer, headers []string) *jsonReportWriter {
+	return &jsonReportWriter{w: w, headers: headers}
+}
+
+func (jw *jsonReportWriter) Write(record []string) error {
+	prefix := ",\n"
+	if jw.count == 0 {
+		prefix = "[\n"
+	}
+	if _, err := io.WriteString(jw.w, prefix+"{"); err != nil {
+		return err
+	}
+
+	for i, header := range jw.headers {
+		value := ""
+		if i < len(record) {
+			value = record[i]
+		}
+		key, err := json.Marshal(header)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			if _, err := io.WriteString(jw.w, ","); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(jw.w, "%s:%s", key, data); err != nil {
+			return err
+		}
+	}
+
+	jw.count++
+	_, err := io.WriteString(jw.w, "}")
+	return err
+}
+
+func (jw *jsonReportWriter) Close() error {
+	suffix := "\n]\n"
+	if jw.count == 0 {
+		suffix = "[]\n"
+	}
+	_, err := io.WriteString(jw.w, suffix)
+	return err
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Report" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	xlsxSheetHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetFooter = `</sheetData></worksheet>`
+)
+
+// xlsxReportWriter writes a report as a workbook of a single sheet, the cells being inline strings so that the
+// workbook needs no shared strings table.
+type xlsxReportWriter struct {
+	zw    *zip.Writer
+	sheet io.Writer
+	row   int
+}
+
+func newXLSXReportWriter(w io.Writer, headers []string) (*xlsxReportWriter, error) {
+	zw := zip.NewWriter(w)
+	for _, part := range []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	} {
+		pw, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = io.WriteString(pw, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err = io.WriteString(sheet, xlsxSheetHeader); err != nil {
+		return nil, err
+	}
+
+	xw := &xlsxReportWriter{zw: zw, sheet: sheet}
+	if err := xw.Write(headers); err != nil {
+		return nil, err
+	}
+	return xw, nil
+}
+
+func (xw *xlsxReportWriter) Write(record []string) error {
+	xw.row++
+	if _, err := fmt.Fprintf(xw.sheet, `<row r="%d">`, xw.row); err != nil {
+		return err
+	}
+	for i, value := range record {
+		if _, err := fmt.Fprintf(xw.sheet, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, xlsxColumn(i), xw.row); err != nil {
+			return err
+		}
+		if err := xml.EscapeText(xw.sheet, []byte(value)); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(xw.sheet, `</t></is></c>`); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(xw.sheet, `</row>`)
+	return err
+}
+
+func (xw *xlsxReportWriter) Close() error {
+	if _, err := io.WriteString(xw.sheet, xlsxSheetFooter); err != nil {
+		return err
+	}
+	return xw.zw.Close()
+}
+
+// xlsxColumn returns the name of the column of the index, starting at 0: A to Z, then AA, AB...
+func xlsxColumn(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
@@ -4,7 +4,10 @@
 package app
 
 import (
+	"archive/zip"
+	"bytes"
 	"fmt"
+	"io"
 	"strconv"
 	"testing"
 	"time"
@@ -98,6 +101,45 @@ some-other-other-name,600,2022-01-01
 		require.Equal(t, expected, string(bytes))
 	})
 
+	t.Run("should compile the report chunks to JSON", func(t *testing.T) {
+		compileErr := th.App.CompileReportChunks(model.ReportExportFormatJSON, prefix, 3, []string{"Name", "NumPosts", "StartDate"})
+		require.Nil(t, compileErr)
+
+		data, readErr := th.App.ReadFile(fmt.Sprintf("admin_reports/batch_report_%s.json", prefix))
+		require.Nil(t, readErr)
+
+		expected := `[
+{"Name":"some-name","NumPosts":"400","StartDate":"2024-01-01"},
+{"Name":"some-other-name","NumPosts":"500","StartDate":"2023-01-01"},
+{"Name":"some-other-other-name","NumPosts":"600","StartDate":"2022-01-01"}
+]
+`
+		require.Equal(t, expected, string(data))
+	})
+
+	t.Run("should compile the report chunks to XLSX", func(t *testing.T) {
+		compileErr := th.App.CompileReportChunks(model.ReportExportFormatXLSX, prefix, 3, []string{"Name", "NumPosts", "StartDate"})
+		require.Nil(t, compileErr)
+
+		data, readErr := th.App.ReadFile(fmt.Sprintf("admin_reports/batch_report_%s.xlsx", prefix))
+		require.Nil(t, readErr)
+
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		var sheet string
+		for _, f := range zr.File {
+			if f.Name == "xl/worksheets/sheet1.xml" {
+				r, err := f.Open()
+				require.NoError(t, err)
+				b, err := io.ReadAll(r)
+				require.NoError(t, err)
+				sheet = string(b)
+			}
+		}
+		require.Contains(t, sheet, `<row r="1"><c r="A1" t="inlineStr"><is><t xml:space="preserve">Name</t></is></c>`)
+		require.Contains(t, sheet, `<c r="B4" t="inlineStr"><is><t xml:space="preserve">600</t></is></c>`)
+	})
+
 	t.Run("should fail if the report format is not supported", func(t *testing.T) {
 		err = th.App.CompileReportChunks("zzz", prefix, 3, []string{"Name", "NumPosts", "StartDate"})
 		require.NotNil(t, err)
@@ -0,0 +1,217 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/i18n"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+const (
+	// savedSearchAlertMaxPosts is the maximum number of new matches linked
+	// in a saved search alert.
+	savedSearchAlertMaxPosts = 20
+	savedSearchAlertPerPage  = 60
+	savedSearchAlertMaxPages = 10
+)
+
+func (a *App) CreateSavedSearch(rctx request.CTX, savedSearch *model.SavedSearch) (*model.SavedSearch, *model.AppError) {
+	count, err := a.Srv().Store().SavedSearch().CountForUser(savedSearch.UserId)
+	if err != nil {
+		return nil, model.NewAppError("CreateSavedSearch", "app.saved_search.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	if count >= model.SavedSearchMaxPerUser {
+		return nil, model.NewAppError("CreateSavedSearch", "app.saved_search.save.limit_reached.app_error", map[string]any{"Max": model.SavedSearchMaxPerUser}, "", http.StatusBadRequest)
+	}
+
+	savedSearch.Id = ""
+	savedSearch.LastRunAt = 0
+	savedSearch.CreateAt = 0
+	saved, err := a.Srv().Store().SavedSearch().Save(savedSearch)
+	if err != nil {
+		var appErr *model.AppError
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		default:
+			return nil, model.NewAppError("CreateSavedSearch", "app.saved_search.save.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	return saved, nil
+}
+
+func (a *App) GetSavedSearch(id string) (*model.SavedSearch, *model.AppError) {
+	savedSearch, err := a.Srv().Store().SavedSearch().Get(id)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &nfErr):
+			return nil, model.NewAppError("GetSavedSearch", "app.saved_search.get.not_found.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		default:
+			return nil, model.NewAppError("GetSavedSearch", "app.saved_search.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	return savedSearch, nil
+}
+
+func (a *App) GetSavedSearchesForUser(userId string) ([]*model.SavedSearch, *model.AppError) {
+	savedSearches, err := a.Srv().Store().SavedSearch().GetForUser(userId)
+	if err != nil {
+		return nil, model.NewAppError("GetSavedSearchesForUser", "app.saved_search.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return savedSearches, nil
+}
+
+func (a *App) PatchSavedSearch(rctx request.CTX, savedSearch *model.SavedSearch, patch *model.SavedSearchPatch) (*model.SavedSearch, *model.AppError) {
+	patched := *savedSearch
+	patched.Patch(patch)
+
+	// Re-enabled alerts only report the posts created from now on.
+	if patched.EnableAlerts && !savedSearch.EnableAlerts {
+		patched.LastRunAt = model.GetMillis()
+	}
+
+	updated, err := a.Srv().Store().SavedSearch().Update(&patched)
+	if err != nil {
+		var appErr *model.AppError
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		case errors.As(err, &nfErr):
+			return nil, model.NewAppError("PatchSavedSearch", "app.saved_search.get.not_found.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		default:
+			return nil, model.NewAppError("PatchSavedSearch", "app.saved_search.update.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	return updated, nil
+}
+
+func (a *App) DeleteSavedSearch(id string) *model.AppError {
+	if err := a.Srv().Store().SavedSearch().Delete(id); err != nil {
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &nfErr):
+			return model.NewAppError("DeleteSavedSearch", "app.saved_search.get.not_found.app_error", nil, "", http.StatusNotFound).Wrap(err)
+		default:
+			return model.NewAppError("DeleteSavedSearch", "app.saved_search.delete.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+// findNewSavedSearchMatches re-runs the saved search as its user, so that only
+// the posts of the channels the user is a member of are matched, and returns
+// the ids of the posts created after the last run, the most recent first, and
+// the creation time of the newest post matched.
+//
+// The run is not tied to the current time: a post only becomes searchable once
+// it has been indexed, so the next run starts from the newest post matched
+// rather than from the time of this run.
+func (a *App) findNewSavedSearchMatches(rctx request.CTX, savedSearch *model.SavedSearch, ignoredUserIds []string) ([]string, int64, bool, *model.AppError) {
+	postIds := []string{}
+	lastMatchAt := savedSearch.LastRunAt
+	for page := 0; page < savedSearchAlertMaxPages; page++ {
+		results, appErr := a.SearchPostsForUser(rctx, savedSearch.Terms, savedSearch.UserId, savedSearch.TeamId, savedSearch.IsOrSearch, false, savedSearch.TimeZoneOffset, model.SearchSortRecent, page, savedSearchAlertPerPage)
+		if appErr != nil {
+			return nil, 0, false, appErr
+		}
+
+		for _, postId := range results.Order {
+			post, ok := results.Posts[postId]
+			if !ok {
+				continue
+			}
+			if post.CreateAt <= savedSearch.LastRunAt {
+				return postIds, lastMatchAt, false, nil
+			}
+			lastMatchAt = max(lastMatchAt, post.CreateAt)
+			if post.IsSystemMessage() || slices.Contains(ignoredUserIds, post.UserId) {
+				continue
+			}
+			if len(postIds) == savedSearchAlertMaxPosts {
+				return postIds, lastMatchAt, true, nil
+			}
+			postIds = append(postIds, post.Id)
+		}
+
+		if len(results.Order) < savedSearchAlertPerPage {
+			break
+		}
+	}
+
+	return postIds, lastMatchAt, false, nil
+}
+
+// RunSavedSearchAlert re-runs a saved search and sends to its user, as a
+// direct message from the system bot, the links to the posts matched since
+// the previous run.
+func (a *App) RunSavedSearchAlert(rctx request.CTX, savedSearch *model.SavedSearch) *model.AppError {
+	user, appErr := a.GetUser(savedSearch.UserId)
+	if appErr != nil {
+		return appErr
+	}
+	if user.DeleteAt != 0 || user.IsBot {
+		return nil
+	}
+
+	systemBot, appErr := a.GetSystemBot(rctx)
+	if appErr != nil {
+		return appErr
+	}
+
+	postIds, lastMatchAt, more, appErr := a.findNewSavedSearchMatches(rctx, savedSearch, []string{user.Id, systemBot.UserId})
+	if appErr != nil {
+		return appErr
+	}
+
+	if len(postIds) > 0 {
+		channel, appErr := a.GetOrCreateDirectChannel(rctx, user.Id, systemBot.UserId)
+		if appErr != nil {
+			return appErr
+		}
+
+		T := i18n.GetUserTranslations(user.Locale)
+		var message strings.Builder
+		message.WriteString(T("app.saved_search.alert.message", map[string]any{"Name": savedSearch.Name}))
+		for _, postId := range postIds {
+			message.WriteString(fmt.Sprintf("\n- %s/_redirect/pl/%s", a.GetSiteURL(), postId))
+		}
+		if more {
+			message.WriteString("\n\n" + T("app.saved_search.alert.more"))
+		}
+
+		post := &model.Post{
+			ChannelId: channel.Id,
+			Message:   message.String(),
+			Type:      model.PostTypeDefault,
+			UserId:    systemBot.UserId,
+		}
+		if _, appErr := a.CreatePost(rctx, post, channel, model.CreatePostFlags{SetOnline: true}); appErr != nil {
+			return appErr
+		}
+	}
+
+	if lastMatchAt == savedSearch.LastRunAt {
+		return nil
+	}
+	if err := a.Srv().Store().SavedSearch().UpdateLastRunAt(savedSearch.Id, lastMatchAt); err != nil {
+		return model.NewAppError("RunSavedSearchAlert", "app.saved_search.update.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return nil
+}
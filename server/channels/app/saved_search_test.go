@@ -0,0 +1,156 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestSavedSearches(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	savedSearch, appErr := th.App.CreateSavedSearch(th.Context, &model.SavedSearch{
+		UserId: th.BasicUser.Id,
+		TeamId: th.BasicTeam.Id,
+		Name:   "My search",
+		Terms:  "hello",
+	})
+	require.Nil(t, appErr)
+	assert.NotEmpty(t, savedSearch.Id)
+
+	t.Run("get", func(t *testing.T) {
+		fetched, appErr := th.App.GetSavedSearch(savedSearch.Id)
+		require.Nil(t, appErr)
+		assert.Equal(t, savedSearch, fetched)
+
+		_, appErr = th.App.GetSavedSearch(model.NewId())
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+
+		savedSearches, appErr := th.App.GetSavedSearchesForUser(th.BasicUser.Id)
+		require.Nil(t, appErr)
+		require.Len(t, savedSearches, 1)
+		assert.Equal(t, savedSearch.Id, savedSearches[0].Id)
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		patched, appErr := th.App.PatchSavedSearch(th.Context, savedSearch, &model.SavedSearchPatch{
+			Terms:        model.NewPointer("hello world"),
+			EnableAlerts: model.NewPointer(true),
+		})
+		require.Nil(t, appErr)
+		assert.Equal(t, "hello world", patched.Terms)
+		assert.True(t, patched.EnableAlerts)
+		assert.GreaterOrEqual(t, patched.LastRunAt, savedSearch.LastRunAt)
+
+		_, appErr = th.App.PatchSavedSearch(th.Context, patched, &model.SavedSearchPatch{Name: model.NewPointer("")})
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+	})
+
+	t.Run("limit per user", func(t *testing.T) {
+		userId := model.NewId()
+		for i := 0; i < model.SavedSearchMaxPerUser; i++ {
+			_, appErr := th.App.CreateSavedSearch(th.Context, &model.SavedSearch{UserId: userId, Name: "Search", Terms: "hello"})
+			require.Nil(t, appErr)
+		}
+
+		_, appErr := th.App.CreateSavedSearch(th.Context, &model.SavedSearch{UserId: userId, Name: "Search", Terms: "hello"})
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.saved_search.save.limit_reached.app_error", appErr.Id)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		appErr := th.App.DeleteSavedSearch(savedSearch.Id)
+		require.Nil(t, appErr)
+
+		appErr = th.App.DeleteSavedSearch(savedSearch.Id)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	})
+}
+
+func TestRunSavedSearchAlert(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	createPost := func(channel *model.Channel, userId, message string, createAt int64) *model.Post {
+		post, appErr := th.App.CreatePost(th.Context, &model.Post{
+			UserId:    userId,
+			ChannelId: channel.Id,
+			Message:   message,
+			CreateAt:  createAt,
+		}, channel, model.CreatePostFlags{})
+		require.Nil(t, appErr)
+		return post
+	}
+
+	lastRunAt := model.GetMillis() - 10000
+	oldPost := createPost(th.BasicChannel, th.BasicUser2.Id, "savedalert old", lastRunAt-1)
+	newPost := createPost(th.BasicChannel, th.BasicUser2.Id, "savedalert new", lastRunAt+1)
+	ownPost := createPost(th.BasicChannel, th.BasicUser.Id, "savedalert own", lastRunAt+2)
+
+	// A channel the user of the saved search is not a member of.
+	otherChannel, appErr := th.App.CreateChannel(th.Context, &model.Channel{
+		DisplayName: "Other",
+		Name:        "other-" + model.NewId(),
+		Type:        model.ChannelTypePrivate,
+		TeamId:      th.BasicTeam.Id,
+		CreatorId:   th.BasicUser2.Id,
+	}, true)
+	require.Nil(t, appErr)
+	hiddenPost := createPost(otherChannel, th.BasicUser2.Id, "savedalert hidden", lastRunAt+3)
+
+	savedSearch, appErr := th.App.CreateSavedSearch(th.Context, &model.SavedSearch{
+		UserId:       th.BasicUser.Id,
+		TeamId:       th.BasicTeam.Id,
+		Name:         "Alerts",
+		Terms:        "savedalert",
+		EnableAlerts: true,
+	})
+	require.Nil(t, appErr)
+	require.NoError(t, th.App.Srv().Store().SavedSearch().UpdateLastRunAt(savedSearch.Id, lastRunAt))
+	savedSearch.LastRunAt = lastRunAt
+
+	systemBot, appErr := th.App.GetSystemBot(th.Context)
+	require.Nil(t, appErr)
+
+	appErr = th.App.RunSavedSearchAlert(th.Context, savedSearch)
+	require.Nil(t, appErr)
+
+	channel, appErr := th.App.GetOrCreateDirectChannel(th.Context, th.BasicUser.Id, systemBot.UserId)
+	require.Nil(t, appErr)
+	posts, appErr := th.App.GetPosts(channel.Id, 0, 10)
+	require.Nil(t, appErr)
+	require.Len(t, posts.Order, 1)
+
+	alert := posts.Posts[posts.Order[0]]
+	assert.Equal(t, systemBot.UserId, alert.UserId)
+	assert.Contains(t, alert.Message, newPost.Id)
+	assert.NotContains(t, alert.Message, oldPost.Id)
+	assert.NotContains(t, alert.Message, ownPost.Id)
+	assert.NotContains(t, alert.Message, hiddenPost.Id)
+
+	updated, appErr := th.App.GetSavedSearch(savedSearch.Id)
+	require.Nil(t, appErr)
+	// The next run starts from the newest post matched, which is the own
+	// post of the user: the hidden post is not matched.
+	assert.Equal(t, ownPost.CreateAt, updated.LastRunAt)
+
+	t.Run("no alert without new matches", func(t *testing.T) {
+		appErr := th.App.RunSavedSearchAlert(th.Context, updated)
+		require.Nil(t, appErr)
+
+		posts, appErr := th.App.GetPosts(channel.Id, 0, 10)
+		require.Nil(t, appErr)
+		assert.Len(t, posts.Order, 1)
+	})
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/rs/cors"
+	"github.com/throttled/throttled"
 	"golang.org/x/crypto/acme/autocert"
 
 	"github.com/mattermost/mattermost/server/public/model"
@@ -45,12 +46,18 @@ import (
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/delete_empty_drafts_migration"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/delete_orphan_drafts_migration"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/expirynotify"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/export_channel_activity_report"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/export_delete"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/export_process"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/export_team_membership_report"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/export_users_to_csv"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/extract_content"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/file_deduplication"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/file_encryption_key_rotation"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/file_tiering"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/hosted_purchase_screening"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/import_delete"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/import_msteams"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/import_process"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/last_accessible_file"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/last_accessible_post"
@@ -63,6 +70,7 @@ import (
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/refresh_materialized_views"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/resend_invitation_email"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs/s3_path_migration"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/saved_search_alerts"
 	"github.com/mattermost/mattermost/server/v8/channels/store"
 	"github.com/mattermost/mattermost/server/v8/channels/utils"
 	"github.com/mattermost/mattermost/server/v8/config"
@@ -72,6 +80,10 @@ import (
 	"github.com/mattermost/mattermost/server/v8/platform/services/remotecluster"
 	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/bleveengine"
 	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/bleveengine/indexer"
+	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/postgresengine"
+	postgresindexer "github.com/mattermost/mattermost/server/v8/platform/services/searchengine/postgresengine/indexer"
+	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/vectorengine"
+	vectorindexer "github.com/mattermost/mattermost/server/v8/platform/services/searchengine/vectorengine/indexer"
 	"github.com/mattermost/mattermost/server/v8/platform/services/sharedchannel"
 	"github.com/mattermost/mattermost/server/v8/platform/services/telemetry"
 	"github.com/mattermost/mattermost/server/v8/platform/services/upgrader"
@@ -212,13 +224,14 @@ func NewServer(options ...Option) (*Server, error) {
 
 	// Needed to run before loading license.
 	s.userService, err = users.New(users.ServiceConfig{
-		UserStore:    s.Store().User(),
-		SessionStore: s.Store().Session(),
-		OAuthStore:   s.Store().OAuth(),
-		ConfigFn:     s.platform.Config,
-		Metrics:      s.GetMetrics(),
-		Cluster:      s.platform.Cluster(),
-		LicenseFn:    s.License,
+		UserStore:     s.Store().User(),
+		SessionStore:  s.Store().Session(),
+		OAuthStore:    s.Store().OAuth(),
+		ConfigFn:      s.platform.Config,
+		Metrics:       s.GetMetrics(),
+		Cluster:       s.platform.Cluster(),
+		LicenseFn:     s.License,
+		FileBlobStore: s.Store().FileBlob(),
 	})
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to create users service")
@@ -927,7 +940,15 @@ func (s *Server) Start() error {
 	if *s.platform.Config().RateLimitSettings.Enable {
 		mlog.Info("RateLimiter is enabled")
 
-		rateLimiter, err2 := NewRateLimiter(&s.platform.Config().RateLimitSettings, s.platform.Config().ServiceSettings.TrustedProxyIPHeader)
+		rateLimiterOptions := []RateLimiterOption{WithRateLimitMetrics(s.GetMetrics())}
+		// Keep the counters in Redis when configured so that the quotas hold across the nodes of the cluster.
+		if cacheProvider := s.platform.CacheProvider(); cacheProvider.Type() == model.CacheTypeRedis || cacheProvider.Type() == model.CacheTypeHybrid {
+			rateLimiterOptions = append(rateLimiterOptions, WithRateLimitStore(func(name string) (throttled.GCRAStore, error) {
+				return cache.NewRedisRateLimitStore(cacheProvider, name)
+			}))
+		}
+
+		rateLimiter, err2 := NewRateLimiter(&s.platform.Config().RateLimitSettings, s.platform.Config().ServiceSettings.TrustedProxyIPHeader, rateLimiterOptions...)
 		if err2 != nil {
 			return err2
 		}
@@ -1505,6 +1526,18 @@ func (s *Server) initJobs() {
 		nil,
 	)
 
+	s.Jobs.RegisterJobType(
+		model.JobTypePostgresSearchIndexing,
+		postgresindexer.MakeWorker(s.Jobs, s.platform.SearchEngine.PostgresEngine.(*postgresengine.PostgresEngine)),
+		nil,
+	)
+
+	s.Jobs.RegisterJobType(
+		model.JobTypeVectorSearchIndexing,
+		vectorindexer.MakeWorker(s.Jobs, s.platform.SearchEngine.VectorEngine.(*vectorengine.VectorEngine)),
+		nil,
+	)
+
 	s.Jobs.RegisterJobType(
 		model.JobTypeMigrations,
 		migrations.MakeWorker(s.Jobs, s.Store()),
@@ -1535,6 +1568,12 @@ func (s *Server) initJobs() {
 		nil,
 	)
 
+	s.Jobs.RegisterJobType(
+		model.JobTypeImportMSTeams,
+		import_msteams.MakeWorker(s.Jobs, New(ServerConnector(s.Channels()))),
+		nil,
+	)
+
 	s.Jobs.RegisterJobType(
 		model.JobTypeImportDelete,
 		import_delete.MakeWorker(s.Jobs, New(ServerConnector(s.Channels())), s.Store()),
@@ -1592,6 +1631,18 @@ func (s *Server) initJobs() {
 		nil,
 	)
 
+	s.Jobs.RegisterJobType(
+		model.JobTypeFileDeduplication,
+		file_deduplication.MakeWorker(s.Jobs, s.FileBackend()),
+		file_deduplication.MakeScheduler(s.Jobs),
+	)
+
+	s.Jobs.RegisterJobType(
+		model.JobTypeFileEncryptionKeyRotation,
+		file_encryption_key_rotation.MakeWorker(s.Jobs, s.FileBackend()),
+		nil,
+	)
+
 	s.Jobs.RegisterJobType(
 		model.JobTypeLastAccessiblePost,
 		last_accessible_post.MakeWorker(s.Jobs, s.License(), New(ServerConnector(s.Channels()))),
@@ -1604,6 +1655,18 @@ func (s *Server) initJobs() {
 		last_accessible_file.MakeScheduler(s.Jobs, s.License()),
 	)
 
+	s.Jobs.RegisterJobType(
+		model.JobTypeFileTiering,
+		file_tiering.MakeWorker(s.Jobs, New(ServerConnector(s.Channels()))),
+		file_tiering.MakeScheduler(s.Jobs),
+	)
+
+	s.Jobs.RegisterJobType(
+		model.JobTypeSavedSearchAlerts,
+		saved_search_alerts.MakeWorker(s.Jobs, New(ServerConnector(s.Channels()))),
+		saved_search_alerts.MakeScheduler(s.Jobs),
+	)
+
 	s.Jobs.RegisterJobType(
 		model.JobTypeUpgradeNotifyAdmin,
 		notify_admin.MakeUpgradeNotifyWorker(s.Jobs, s.License(), New(ServerConnector(s.Channels()))),
@@ -1652,6 +1715,24 @@ func (s *Server) initJobs() {
 		nil,
 	)
 
+	s.Jobs.RegisterJobType(
+		model.JobTypeExportGuestAccountsReport,
+		export_users_to_csv.MakeWorker(s.Jobs, s.Store(), New(ServerConnector(s.Channels()))),
+		nil,
+	)
+
+	s.Jobs.RegisterJobType(
+		model.JobTypeExportChannelActivityReport,
+		export_channel_activity_report.MakeWorker(s.Jobs, s.Store(), New(ServerConnector(s.Channels()))),
+		nil,
+	)
+
+	s.Jobs.RegisterJobType(
+		model.JobTypeExportTeamMembershipReport,
+		export_team_membership_report.MakeWorker(s.Jobs, s.Store(), New(ServerConnector(s.Channels()))),
+		nil,
+	)
+
 	s.Jobs.RegisterJobType(
 		model.JobTypeDeleteDmsPreferencesMigration,
 		delete_dms_preferences_migration.MakeWorker(s.Jobs, s.Store(), New(ServerConnector(s.Channels()))),
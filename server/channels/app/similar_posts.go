@@ -0,0 +1,126 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+// SimilarPostsMaxPerPage is the maximum number of posts returned by a
+// similarity search.
+const SimilarPostsMaxPerPage = 100
+
+// SearchSimilarPostsForUser returns the posts whose meaning is the closest to
+// the given text, among the channels of the team the user is a member of. An
+// empty teamID searches the channels of all the teams of the user.
+func (a *App) SearchSimilarPostsForUser(rctx request.CTX, terms string, userID string, teamID string, perPage int) (*model.PostSearchResults, *model.AppError) {
+	if !*a.Config().ServiceSettings.EnablePostSearch {
+		return nil, model.NewAppError("SearchSimilarPostsForUser", "store.sql_post.search.disabled", nil, fmt.Sprintf("teamId=%v userId=%v", teamID, userID), http.StatusNotImplemented)
+	}
+
+	return a.searchSimilarPosts(rctx, strings.TrimSpace(terms), userID, teamID, "", perPage)
+}
+
+// GetRelatedPostsForUser returns the posts whose meaning is the closest to the
+// given post, among the channels of the post's team the user is a member of.
+func (a *App) GetRelatedPostsForUser(rctx request.CTX, post *model.Post, userID string, perPage int) (*model.PostSearchResults, *model.AppError) {
+	if !*a.Config().ServiceSettings.EnablePostSearch {
+		return nil, model.NewAppError("GetRelatedPostsForUser", "store.sql_post.search.disabled", nil, fmt.Sprintf("postId=%v userId=%v", post.Id, userID), http.StatusNotImplemented)
+	}
+
+	channel, appErr := a.GetChannel(rctx, post.ChannelId)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return a.searchSimilarPosts(rctx, strings.TrimSpace(post.Message), userID, channel.TeamId, post.Id, perPage)
+}
+
+func (a *App) searchSimilarPosts(rctx request.CTX, text string, userID string, teamID string, excludedPostID string, perPage int) (*model.PostSearchResults, *model.AppError) {
+	engine := a.SearchEngine().GetActiveVectorEngine()
+	if engine == nil || !engine.IsSearchEnabled() {
+		return nil, model.NewAppError("searchSimilarPosts", "app.post.search_similar.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	if text == "" {
+		return model.MakePostSearchResults(model.NewPostList(), nil), nil
+	}
+
+	if perPage <= 0 || perPage > SimilarPostsMaxPerPage {
+		perPage = SimilarPostsMaxPerPage
+	}
+
+	channels, err := a.Srv().Store().Channel().GetChannels(teamID, userID, &model.ChannelSearchOpts{IncludeDeleted: false})
+	var nfErr *store.ErrNotFound
+	if err != nil && !errors.As(err, &nfErr) {
+		return nil, model.NewAppError("searchSimilarPosts", "app.channel.get_channels.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	if len(channels) == 0 {
+		return model.MakePostSearchResults(model.NewPostList(), nil), nil
+	}
+
+	channelIDs := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		channelIDs = append(channelIDs, channel.Id)
+	}
+
+	limit := perPage
+	if excludedPostID != "" {
+		limit++
+	}
+
+	hits, appErr := engine.SearchSimilarPosts(text, channelIDs, limit)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	postIDs := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		if hit.Id != excludedPostID {
+			postIDs = append(postIDs, hit.Id)
+		}
+	}
+
+	postList := model.NewPostList()
+	results := model.MakePostSearchResults(postList, nil)
+	results.Scores = map[string]float64{}
+	if len(postIDs) == 0 {
+		return results, nil
+	}
+
+	posts, err := a.Srv().Store().Post().GetPostsByIds(postIDs)
+	if err != nil {
+		return nil, model.NewAppError("searchSimilarPosts", "app.post.get.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	postsByID := make(map[string]*model.Post, len(posts))
+	for _, post := range posts {
+		postsByID[post.Id] = post
+	}
+
+	// The posts are ordered from the most to the least similar.
+	for _, hit := range hits {
+		post, ok := postsByID[hit.Id]
+		if !ok || post.DeleteAt != 0 || len(postList.Order) == perPage {
+			continue
+		}
+		postList.AddPost(post)
+		postList.AddOrder(post.Id)
+		results.Scores[post.Id] = hit.Score
+	}
+
+	if appErr := a.filterInaccessiblePosts(postList, filterPostOptions{}); appErr != nil {
+		return nil, appErr
+	}
+	results.RemoveMissingHits()
+
+	return results, nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// newTestEmbeddingServer returns an embedding server giving close vectors to
+// the texts mentioning the same topics.
+func newTestEmbeddingServer(t *testing.T) *httptest.Server {
+	topics := map[string]int{"car": 0, "automobile": 0, "vehicle": 0, "kitten": 1, "cat": 1}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+		type embedding struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		}
+		var response struct {
+			Data []embedding `json:"data"`
+		}
+		for i, text := range request.Input {
+			vector := []float32{0, 0, 0.1}
+			for _, word := range strings.Fields(strings.ToLower(text)) {
+				if topic, ok := topics[strings.Trim(word, ".,!?")]; ok {
+					vector[topic]++
+				}
+			}
+			response.Data = append(response.Data, embedding{Index: i, Embedding: vector})
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+}
+
+func TestSearchSimilarPosts(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	t.Run("disabled", func(t *testing.T) {
+		_, appErr := th.App.SearchSimilarPostsForUser(th.Context, "car", th.BasicUser.Id, th.BasicTeam.Id, 10)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.post.search_similar.disabled.app_error", appErr.Id)
+		assert.Equal(t, http.StatusNotImplemented, appErr.StatusCode)
+	})
+
+	server := newTestEmbeddingServer(t)
+	defer server.Close()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.VectorSearchSettings.EnableIndexing = true
+		*cfg.VectorSearchSettings.EnableSearching = true
+		*cfg.VectorSearchSettings.EmbeddingURL = server.URL
+		*cfg.VectorSearchSettings.EmbeddingDimensions = 3
+		*cfg.VectorSearchSettings.Backend = model.VectorSearchBackendHNSW
+		*cfg.VectorSearchSettings.IndexDir = t.TempDir()
+	})
+	require.NotNil(t, th.App.SearchEngine().GetActiveVectorEngine())
+
+	carPost, appErr := th.App.CreatePostAsUser(th.Context, &model.Post{UserId: th.BasicUser.Id, ChannelId: th.BasicChannel.Id, Message: "Selling my car"}, "", true)
+	require.Nil(t, appErr)
+	vehiclePost, appErr := th.App.CreatePostAsUser(th.Context, &model.Post{UserId: th.BasicUser.Id, ChannelId: th.BasicChannel.Id, Message: "Does the vehicle still drive?"}, "", true)
+	require.Nil(t, appErr)
+	_, appErr = th.App.CreatePostAsUser(th.Context, &model.Post{UserId: th.BasicUser.Id, ChannelId: th.BasicChannel.Id, Message: "Look at my kitten"}, "", true)
+	require.Nil(t, appErr)
+
+	otherChannel := th.CreatePrivateChannel(th.Context, th.BasicTeam)
+	privatePost, appErr := th.App.CreatePostAsUser(th.Context, &model.Post{UserId: th.BasicUser.Id, ChannelId: otherChannel.Id, Message: "My car is red"}, "", true)
+	require.Nil(t, appErr)
+	require.Nil(t, th.RemoveUserFromChannel(th.BasicUser, otherChannel))
+
+	t.Run("search by meaning", func(t *testing.T) {
+		var results *model.PostSearchResults
+		require.Eventually(t, func() bool {
+			var appErr *model.AppError
+			results, appErr = th.App.SearchSimilarPostsForUser(th.Context, "automobile", th.BasicUser.Id, th.BasicTeam.Id, 2)
+			require.Nil(t, appErr)
+			return len(results.Order) == 2
+		}, 5*time.Second, 100*time.Millisecond)
+
+		assert.ElementsMatch(t, []string{carPost.Id, vehiclePost.Id}, results.Order)
+		assert.NotContains(t, results.Posts, privatePost.Id)
+		assert.Len(t, results.Scores, 2)
+	})
+
+	t.Run("related posts", func(t *testing.T) {
+		results, appErr := th.App.GetRelatedPostsForUser(th.Context, carPost, th.BasicUser.Id, 1)
+		require.Nil(t, appErr)
+		require.Equal(t, []string{vehiclePost.Id}, results.Order)
+		assert.Contains(t, results.Scores, vehiclePost.Id)
+	})
+
+	t.Run("deleted posts are not returned", func(t *testing.T) {
+		_, appErr := th.App.DeletePost(th.Context, vehiclePost.Id, th.BasicUser.Id)
+		require.Nil(t, appErr)
+
+		results, appErr := th.App.GetRelatedPostsForUser(th.Context, carPost, th.BasicUser.Id, 10)
+		require.Nil(t, appErr)
+		assert.NotContains(t, results.Order, vehiclePost.Id)
+		assert.NotContains(t, results.Order, carPost.Id)
+	})
+}
@@ -19,7 +19,9 @@ import (
 	"github.com/mattermost/mattermost/server/v8/platform/services/slackimport"
 )
 
-func (a *App) SlackImport(c request.CTX, fileData multipart.File, fileSize int64, teamID string) (*model.AppError, *bytes.Buffer) {
+// SlackImport imports a Slack export into a team. It returns the log of the import, and the report listing what has
+// been imported and what has been skipped.
+func (a *App) SlackImport(c request.CTX, fileData multipart.File, fileSize int64, teamID string) (*model.AppError, *bytes.Buffer, *slackimport.ImportReport) {
 	actions := slackimport.Actions{
 		UpdateActive: func(user *model.User, active bool) (*model.User, *model.AppError) {
 			return a.UpdateActive(c, user, active)
@@ -47,10 +49,12 @@ func (a *App) SlackImport(c request.CTX, fileData multipart.File, fileSize int64
 			}
 			return img, imgType, release, err
 		},
+		CreateGroupWithUserIds: a.CreateGroupWithUserIds,
 	}
 
 	importer := slackimport.New(a.Srv().Store(), actions, a.Config())
-	return importer.SlackImport(c, fileData, fileSize, teamID)
+	appErr, log := importer.SlackImport(c, fileData, fileSize, teamID)
+	return appErr, log, importer.Report()
 }
 
 func (a *App) ProcessSlackText(text string) string {
@@ -61,7 +61,9 @@ func (us *UserService) GetProfileImage(user *model.User) ([]byte, bool, error) {
 func (us *UserService) FileBackend() (filestore.FileBackend, error) {
 	license := us.license()
 	insecure := us.config().ServiceSettings.EnableInsecureOutgoingConnections
-	backend, err := filestore.NewFileBackend(filestore.NewFileBackendSettingsFromConfig(&us.config().FileSettings, license != nil && *license.Features.Compliance, insecure != nil && *insecure))
+	settings := filestore.NewFileBackendSettingsFromConfig(&us.config().FileSettings, license != nil && *license.Features.Compliance, insecure != nil && *insecure)
+	settings.BlobRefStore = us.blobStore
+	backend, err := filestore.NewFileBackend(settings)
 	if err != nil {
 		return nil, err
 	}
@@ -73,6 +75,7 @@ func (us *UserService) ReadFile(path string) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer filestore.CloseFileBackend(backend)
 	result, nErr := backend.ReadFile(path)
 	if nErr != nil {
 		return nil, nErr
@@ -85,6 +88,7 @@ func (us *UserService) writeFile(fr io.Reader, path string) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
+	defer filestore.CloseFileBackend(backend)
 
 	result, nErr := backend.WriteFile(fr, path)
 	if nErr != nil {
@@ -15,6 +15,7 @@ type UserService struct {
 	store        store.UserStore
 	sessionStore store.SessionStore
 	oAuthStore   store.OAuthStore
+	blobStore    store.FileBlobStore
 	metrics      einterfaces.MetricsInterface
 	cluster      einterfaces.ClusterInterface
 	config       func() *model.Config
@@ -30,8 +31,9 @@ type ServiceConfig struct {
 	ConfigFn     func() *model.Config
 	LicenseFn    func() *model.License
 	// Optional fields
-	Metrics einterfaces.MetricsInterface
-	Cluster einterfaces.ClusterInterface
+	Metrics       einterfaces.MetricsInterface
+	Cluster       einterfaces.ClusterInterface
+	FileBlobStore store.FileBlobStore
 }
 
 func New(c ServiceConfig) (*UserService, error) {
@@ -43,6 +45,7 @@ func New(c ServiceConfig) (*UserService, error) {
 		store:        c.UserStore,
 		sessionStore: c.SessionStore,
 		oAuthStore:   c.OAuthStore,
+		blobStore:    c.FileBlobStore,
 		config:       c.ConfigFn,
 		license:      c.LicenseFn,
 		metrics:      c.Metrics,
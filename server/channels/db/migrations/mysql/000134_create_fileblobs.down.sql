@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS FileBlobRefs;
+DROP TABLE IF EXISTS FileBlobs;
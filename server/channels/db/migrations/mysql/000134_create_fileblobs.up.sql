@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS FileBlobs (
+    Hash varchar(64) NOT NULL,
+    Size bigint(20) NOT NULL DEFAULT 0,
+    RefCount bigint(20) NOT NULL DEFAULT 0,
+    CreateAt bigint(20) NOT NULL DEFAULT 0,
+    PRIMARY KEY (Hash)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS FileBlobRefs (
+    Path varchar(512) NOT NULL,
+    Hash varchar(64) NOT NULL,
+    Size bigint(20) NOT NULL DEFAULT 0,
+    CreateAt bigint(20) NOT NULL DEFAULT 0,
+    PRIMARY KEY (Path),
+    KEY idx_fileblobrefs_hash (Hash)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
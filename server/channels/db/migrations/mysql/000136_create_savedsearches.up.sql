@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS SavedSearches (
+    Id varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    TeamId varchar(26) NOT NULL DEFAULT '',
+    Name varchar(64) NOT NULL,
+    Terms text NOT NULL,
+    IsOrSearch boolean NOT NULL DEFAULT false,
+    TimeZoneOffset int NOT NULL DEFAULT 0,
+    EnableAlerts boolean NOT NULL DEFAULT false,
+    LastRunAt bigint(20) NOT NULL DEFAULT 0,
+    CreateAt bigint(20) NOT NULL DEFAULT 0,
+    UpdateAt bigint(20) NOT NULL DEFAULT 0,
+    PRIMARY KEY (Id),
+    KEY idx_savedsearches_userid (UserId)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,29 @@
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.STATISTICS
+        WHERE table_name = 'FileBlobs'
+        AND table_schema = DATABASE()
+        AND index_name = 'idx_fileblobs_refcount_updateat'
+    ) > 0,
+    'DROP INDEX idx_fileblobs_refcount_updateat ON FileBlobs;',
+    'SELECT 1'
+));
+
+PREPARE removeIndexIfExists FROM @preparedStatement;
+EXECUTE removeIndexIfExists;
+DEALLOCATE PREPARE removeIndexIfExists;
+
+SET @preparedStatement = (SELECT IF(
+    EXISTS(
+        SELECT 1 FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'FileBlobs'
+        AND table_schema = DATABASE()
+        AND column_name = 'UpdateAt'
+    ),
+    'ALTER TABLE FileBlobs DROP COLUMN UpdateAt;',
+    'SELECT 1;'
+));
+
+PREPARE removeColumnIfExists FROM @preparedStatement;
+EXECUTE removeColumnIfExists;
+DEALLOCATE PREPARE removeColumnIfExists;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS fileblobrefs;
+DROP TABLE IF EXISTS fileblobs;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS fileblobs (
+    hash varchar(64) PRIMARY KEY,
+    size bigint NOT NULL DEFAULT 0,
+    refcount bigint NOT NULL DEFAULT 0,
+    createat bigint NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS fileblobrefs (
+    path varchar(512) PRIMARY KEY,
+    hash varchar(64) NOT NULL,
+    size bigint NOT NULL DEFAULT 0,
+    createat bigint NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_fileblobrefs_hash ON fileblobrefs (hash);
@@ -0,0 +1 @@
+ALTER TABLE fileinfo DROP COLUMN IF EXISTS storagetier;
@@ -0,0 +1 @@
+ALTER TABLE fileinfo ADD COLUMN IF NOT EXISTS storagetier varchar(16) NOT NULL DEFAULT 'hot';
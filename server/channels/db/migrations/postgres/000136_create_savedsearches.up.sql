@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS savedsearches (
+    id varchar(26) PRIMARY KEY,
+    userid varchar(26) NOT NULL,
+    teamid varchar(26) NOT NULL DEFAULT '',
+    name varchar(64) NOT NULL,
+    terms text NOT NULL,
+    isorsearch boolean NOT NULL DEFAULT false,
+    timezoneoffset integer NOT NULL DEFAULT 0,
+    enablealerts boolean NOT NULL DEFAULT false,
+    lastrunat bigint NOT NULL DEFAULT 0,
+    createat bigint NOT NULL DEFAULT 0,
+    updateat bigint NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_savedsearches_userid ON savedsearches (userid);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS clustermessages (
+    id varchar(26) PRIMARY KEY,
+    data text NOT NULL,
+    createat bigint NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_clustermessages_createat ON clustermessages (createat);
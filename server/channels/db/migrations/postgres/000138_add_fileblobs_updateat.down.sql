@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_fileblobs_refcount_updateat;
+
+ALTER TABLE fileblobs DROP COLUMN IF EXISTS updateat;
@@ -0,0 +1,3 @@
+ALTER TABLE fileblobs ADD COLUMN IF NOT EXISTS updateat bigint NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_fileblobs_refcount_updateat ON fileblobs (refcount, updateat);
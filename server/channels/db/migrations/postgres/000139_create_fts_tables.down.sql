@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS ftsusersuggestions;
+DROP TABLE IF EXISTS ftsusers;
+DROP TABLE IF EXISTS ftschannelsuggestions;
+DROP TABLE IF EXISTS ftschannels;
+DROP TABLE IF EXISTS ftsfiles;
+DROP TABLE IF EXISTS ftsposts;
@@ -0,0 +1,68 @@
+CREATE TABLE IF NOT EXISTS ftsposts (
+    id varchar(26) PRIMARY KEY,
+    teamid varchar(26) NOT NULL,
+    channelid varchar(26) NOT NULL,
+    userid varchar(26) NOT NULL,
+    createat bigint NOT NULL,
+    type varchar(64) NOT NULL,
+    hashtags text[] NOT NULL,
+    message tsvector NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ftsposts_message ON ftsposts USING gin (message);
+CREATE INDEX IF NOT EXISTS idx_ftsposts_hashtags ON ftsposts USING gin (hashtags);
+CREATE INDEX IF NOT EXISTS idx_ftsposts_channelid_createat ON ftsposts (channelid, createat);
+CREATE INDEX IF NOT EXISTS idx_ftsposts_userid ON ftsposts (userid);
+
+CREATE TABLE IF NOT EXISTS ftsfiles (
+    id varchar(26) PRIMARY KEY,
+    creatorid varchar(26) NOT NULL,
+    channelid varchar(26) NOT NULL,
+    postid varchar(26) NOT NULL,
+    createat bigint NOT NULL,
+    extension varchar(64) NOT NULL,
+    content tsvector NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ftsfiles_content ON ftsfiles USING gin (content);
+CREATE INDEX IF NOT EXISTS idx_ftsfiles_channelid_createat ON ftsfiles (channelid, createat);
+CREATE INDEX IF NOT EXISTS idx_ftsfiles_creatorid ON ftsfiles (creatorid);
+CREATE INDEX IF NOT EXISTS idx_ftsfiles_postid ON ftsfiles (postid);
+
+CREATE TABLE IF NOT EXISTS ftschannels (
+    id varchar(26) PRIMARY KEY,
+    teamid varchar(26) NOT NULL,
+    type varchar(1) NOT NULL,
+    userids text[] NOT NULL,
+    teammemberids text[] NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ftschannels_teamid ON ftschannels (teamid);
+CREATE INDEX IF NOT EXISTS idx_ftschannels_userids ON ftschannels USING gin (userids);
+CREATE INDEX IF NOT EXISTS idx_ftschannels_teammemberids ON ftschannels USING gin (teammemberids);
+
+CREATE TABLE IF NOT EXISTS ftschannelsuggestions (
+    channelid varchar(26) NOT NULL REFERENCES ftschannels (id) ON DELETE CASCADE,
+    suggestion text NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ftschannelsuggestions_channelid ON ftschannelsuggestions (channelid);
+CREATE INDEX IF NOT EXISTS idx_ftschannelsuggestions_suggestion ON ftschannelsuggestions (suggestion text_pattern_ops);
+
+CREATE TABLE IF NOT EXISTS ftsusers (
+    id varchar(26) PRIMARY KEY,
+    teamsids text[] NOT NULL,
+    channelsids text[] NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ftsusers_teamsids ON ftsusers USING gin (teamsids);
+CREATE INDEX IF NOT EXISTS idx_ftsusers_channelsids ON ftsusers USING gin (channelsids);
+
+CREATE TABLE IF NOT EXISTS ftsusersuggestions (
+    userid varchar(26) NOT NULL REFERENCES ftsusers (id) ON DELETE CASCADE,
+    suggestion text NOT NULL,
+    fullname boolean NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ftsusersuggestions_userid ON ftsusersuggestions (userid);
+CREATE INDEX IF NOT EXISTS idx_ftsusersuggestions_suggestion ON ftsusersuggestions (suggestion text_pattern_ops);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS vecposts;
+DROP TABLE IF EXISTS vecindexinfo;
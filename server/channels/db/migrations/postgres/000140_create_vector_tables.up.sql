@@ -0,0 +1,37 @@
+-- The vector search requires the pgvector extension. The tables are only
+-- created when the extension is available on the database server, and
+-- created by a user allowed to do so, so that the other servers still
+-- migrate.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'vector') THEN
+        RETURN;
+    END IF;
+
+    BEGIN
+        CREATE EXTENSION IF NOT EXISTS vector;
+    EXCEPTION WHEN insufficient_privilege THEN
+        RAISE NOTICE 'The vector extension must be created by a superuser to enable the pgvector backend of the vector search';
+        RETURN;
+    END;
+
+    CREATE TABLE IF NOT EXISTS vecindexinfo (
+        id integer PRIMARY KEY,
+        model text NOT NULL,
+        dimensions integer NOT NULL
+    );
+
+    -- The dimensions of the vectors depend on the embedding model. The HNSW
+    -- index is built on the vectors cast to the dimensions of the model
+    -- when the vector index is rebuilt by the indexing job.
+    CREATE TABLE IF NOT EXISTS vecposts (
+        id varchar(26) PRIMARY KEY,
+        channelid varchar(26) NOT NULL,
+        userid varchar(26) NOT NULL,
+        createat bigint NOT NULL,
+        embedding vector NOT NULL
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_vecposts_channelid ON vecposts (channelid);
+    CREATE INDEX IF NOT EXISTS idx_vecposts_userid ON vecposts (userid);
+END $$;
@@ -85,6 +85,14 @@ func (worker *BatchReportWorker) doBatch(rctx *request.Context, job *model.Job)
 	return false
 }
 
+// format returns the format requested by the job, the default format of the worker if none.
+func (worker *BatchReportWorker) format(job *model.Job) string {
+	if format := job.Data["format"]; format != "" {
+		return format
+	}
+	return worker.reportFormat
+}
+
 func getFileCount(jobData model.StringMap) (int, error) {
 	if jobData["file_count"] != "" {
 		parsedFileCount, parseErr := strconv.Atoi(jobData["file_count"])
@@ -104,9 +112,9 @@ func (worker *BatchReportWorker) processChunk(job *model.Job, reportData []model
 		return err
 	}
 
-	appErr := worker.app.SaveReportChunk(worker.reportFormat, job.Id, fileCount, reportData)
+	appErr := worker.app.SaveReportChunk(worker.format(job), job.Id, fileCount, reportData)
 	if appErr != nil {
-		return err
+		return appErr
 	}
 
 	fileCount++
@@ -121,18 +129,19 @@ func (worker *BatchReportWorker) complete(rctx request.CTX, job *model.Job) erro
 		return err
 	}
 
-	appErr := worker.app.CompileReportChunks(worker.reportFormat, job.Id, fileCount, worker.headers)
+	format := worker.format(job)
+	appErr := worker.app.CompileReportChunks(format, job.Id, fileCount, worker.headers)
 	if appErr != nil {
 		return appErr
 	}
 
 	defer func() {
-		if err := worker.app.CleanupReportChunks(worker.reportFormat, job.Id, fileCount); err != nil {
+		if err := worker.app.CleanupReportChunks(format, job.Id, fileCount); err != nil {
 			worker.logger.Error("Worker: Failed to cleanup report chunks", mlog.Err(err))
 		}
 	}()
 
-	if appErr = worker.app.SendReportToUser(rctx, job, worker.reportFormat); appErr != nil {
+	if appErr = worker.app.SendReportToUser(rctx, job, format); appErr != nil {
 		return appErr
 	}
 
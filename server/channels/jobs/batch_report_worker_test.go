@@ -6,6 +6,7 @@ package jobs_test
 import (
 	"errors"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -15,7 +16,10 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-type ReportMockApp struct{}
+type ReportMockApp struct {
+	mut         sync.Mutex
+	sentFormats []string
+}
 
 func (rma *ReportMockApp) SaveReportChunk(format string, prefix string, count int, reportData []model.ReportableObject) *model.AppError {
 	return nil
@@ -24,6 +28,9 @@ func (rma *ReportMockApp) CompileReportChunks(format string, prefix string, numb
 	return nil
 }
 func (rma *ReportMockApp) SendReportToUser(rctx request.CTX, job *model.Job, format string) *model.AppError {
+	rma.mut.Lock()
+	defer rma.mut.Unlock()
+	rma.sentFormats = append(rma.sentFormats, format)
 	return nil
 }
 func (rma *ReportMockApp) CleanupReportChunks(format string, prefix string, numberOfChunks int) *model.AppError {
@@ -34,6 +41,7 @@ func TestBatchReportWorker(t *testing.T) {
 	setupBatchWorker := func(
 		t *testing.T,
 		th *TestHelper,
+		app *ReportMockApp,
 		getData func(jobData model.StringMap) ([]model.ReportableObject, model.StringMap, bool, error),
 	) (*jobs.BatchReportWorker, *model.Job) {
 		t.Helper()
@@ -41,7 +49,7 @@ func TestBatchReportWorker(t *testing.T) {
 		worker := jobs.MakeBatchReportWorker(
 			th.Server.Jobs,
 			th.Server.Store(),
-			&ReportMockApp{},
+			app,
 			1*time.Second,
 			"csv",
 			[]string{},
@@ -91,7 +99,7 @@ func TestBatchReportWorker(t *testing.T) {
 
 		iterations := 0
 
-		worker, job = setupBatchWorker(t, th, func(data model.StringMap) ([]model.ReportableObject, model.StringMap, bool, error) {
+		worker, job = setupBatchWorker(t, th, &ReportMockApp{}, func(data model.StringMap) ([]model.ReportableObject, model.StringMap, bool, error) {
 			fileCount := getFileCountFromData(t, data)
 			require.Equal(t, iterations, fileCount)
 			require.LessOrEqual(t, fileCount, 3, "only 3 batches should have run")
@@ -119,7 +127,7 @@ func TestBatchReportWorker(t *testing.T) {
 
 		var worker model.Worker
 		var job *model.Job
-		worker, job = setupBatchWorker(t, th, func(data model.StringMap) ([]model.ReportableObject, model.StringMap, bool, error) {
+		worker, job = setupBatchWorker(t, th, &ReportMockApp{}, func(data model.StringMap) ([]model.ReportableObject, model.StringMap, bool, error) {
 			go worker.Stop() // Shut down the worker right after this
 			return []model.ReportableObject{}, createData(th, data), false, errors.New("failed to fetch data")
 		})
@@ -129,4 +137,30 @@ func TestBatchReportWorker(t *testing.T) {
 
 		th.WaitForJobStatus(t, job, model.JobStatusError)
 	})
+
+	t.Run("should send the report in the format of the job", func(t *testing.T) {
+		th := Setup(t).InitBasic()
+		defer th.TearDown()
+
+		app := &ReportMockApp{}
+		var worker model.Worker
+		var job *model.Job
+		worker, job = setupBatchWorker(t, th, app, func(data model.StringMap) ([]model.ReportableObject, model.StringMap, bool, error) {
+			if getFileCountFromData(t, data) >= 1 {
+				go worker.Stop() // Shut down the worker when the job is done
+				return []model.ReportableObject{}, createData(th, data), true, nil
+			}
+
+			data["format"] = model.ReportExportFormatXLSX
+			return []model.ReportableObject{}, createData(th, data), false, nil
+		})
+
+		// Queue the work to be done
+		worker.JobChannel() <- *job
+
+		th.WaitForJobStatus(t, job, model.JobStatusSuccess)
+		app.mut.Lock()
+		defer app.mut.Unlock()
+		require.Equal(t, []string{model.ReportExportFormatXLSX}, app.sentFormats)
+	})
 }
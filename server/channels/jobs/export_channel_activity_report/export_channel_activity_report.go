@@ -0,0 +1,94 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package export_channel_activity_report
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/pkg/errors"
+)
+
+const (
+	timeBetweenBatches = 1 * time.Second
+)
+
+type ExportChannelActivityReportAppIFace interface {
+	jobs.BatchReportWorkerAppIFace
+	GetChannelActivityForReporting(options *model.ChannelActivityReportOptions) ([]*model.ChannelActivityReport, *model.AppError)
+}
+
+// MakeWorker creates a batch report worker to generate channel activity reports.
+func MakeWorker(jobServer *jobs.JobServer, store store.Store, app ExportChannelActivityReportAppIFace) model.Worker {
+	return jobs.MakeBatchReportWorker(
+		jobServer,
+		store,
+		app,
+		timeBetweenBatches,
+		model.ReportExportFormatCSV,
+		[]string{
+			"Id",
+			"Team",
+			"Name",
+			"DisplayName",
+			"Type",
+			"CreateAt",
+			"LastPostAt",
+			"MemberCount",
+			"PostCount",
+			"DeletedAt",
+		},
+		getData(app),
+	)
+}
+
+// parseJobMetadata parses the opaque job metadata to return the information needed to decide which
+// batch to process next.
+func parseJobMetadata(data model.StringMap) (*model.ChannelActivityReportOptions, error) {
+	startAt, err := strconv.ParseInt(data["start_at"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	endAt, err := strconv.ParseInt(data["end_at"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ChannelActivityReportOptions{
+		TeamId:        data["team"],
+		FromChannelId: data["last_channel_id"],
+		PageSize:      100,
+		StartAt:       startAt,
+		EndAt:         endAt,
+	}, nil
+}
+
+func getData(app ExportChannelActivityReportAppIFace) func(jobData model.StringMap) ([]model.ReportableObject, model.StringMap, bool, error) {
+	return func(jobData model.StringMap) ([]model.ReportableObject, model.StringMap, bool, error) {
+		options, err := parseJobMetadata(jobData)
+		if err != nil {
+			return nil, nil, false, errors.Wrap(err, "failed to parse job metadata")
+		}
+
+		channels, appErr := app.GetChannelActivityForReporting(options)
+		if appErr != nil {
+			return nil, nil, false, errors.Wrapf(appErr, "failed to get the next batch (channel_id=%v)", options.FromChannelId)
+		}
+
+		if len(channels) == 0 {
+			return nil, nil, true, nil
+		}
+
+		reportableObjects := make([]model.ReportableObject, 0, len(channels))
+		for _, channel := range channels {
+			reportableObjects = append(reportableObjects, channel)
+		}
+
+		jobData["last_channel_id"] = channels[len(channels)-1].ChannelId
+		return reportableObjects, jobData, false, nil
+	}
+}
@@ -5,8 +5,10 @@ package export_process
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"path/filepath"
+	"strconv"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/configservice"
@@ -53,6 +55,14 @@ func MakeWorker(jobServer *jobs.JobServer, app AppIface) *jobs.SimpleWorker {
 			opts.IncludeRolesAndSchemes = true
 		}
 
+		if since, ok := job.Data["since"]; ok && since != "" {
+			var err error
+			opts.Since, err = strconv.ParseInt(since, 10, 64)
+			if err != nil || opts.Since < 0 {
+				return fmt.Errorf("invalid since %q: the export must start at a time in milliseconds", since)
+			}
+		}
+
 		outPath := *app.Config().ExportSettings.Directory
 		exportFilename := job.Id + "_export.zip"
 
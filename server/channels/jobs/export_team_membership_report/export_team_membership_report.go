@@ -0,0 +1,75 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package export_team_membership_report
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/pkg/errors"
+)
+
+const (
+	timeBetweenBatches = 1 * time.Second
+)
+
+type ExportTeamMembershipReportAppIFace interface {
+	jobs.BatchReportWorkerAppIFace
+	GetTeamMembershipForReporting(options *model.TeamMembershipReportOptions) ([]*model.TeamMembershipReport, *model.AppError)
+}
+
+// MakeWorker creates a batch report worker to generate team membership reports.
+func MakeWorker(jobServer *jobs.JobServer, store store.Store, app ExportTeamMembershipReportAppIFace) model.Worker {
+	return jobs.MakeBatchReportWorker(
+		jobServer,
+		store,
+		app,
+		timeBetweenBatches,
+		model.ReportExportFormatCSV,
+		[]string{
+			"TeamId",
+			"Team",
+			"UserId",
+			"Username",
+			"Email",
+			"Roles",
+			"JoinedAt",
+			"LeftAt",
+			"UserDeletedAt",
+		},
+		getData(app),
+	)
+}
+
+func getData(app ExportTeamMembershipReportAppIFace) func(jobData model.StringMap) ([]model.ReportableObject, model.StringMap, bool, error) {
+	return func(jobData model.StringMap) ([]model.ReportableObject, model.StringMap, bool, error) {
+		options := &model.TeamMembershipReportOptions{
+			TeamId:     jobData["team"],
+			FromTeamId: jobData["last_team_id"],
+			FromUserId: jobData["last_user_id"],
+			PageSize:   100,
+		}
+
+		members, appErr := app.GetTeamMembershipForReporting(options)
+		if appErr != nil {
+			return nil, nil, false, errors.Wrapf(appErr, "failed to get the next batch (team_id=%v, user_id=%v)", options.FromTeamId, options.FromUserId)
+		}
+
+		if len(members) == 0 {
+			return nil, nil, true, nil
+		}
+
+		reportableObjects := make([]model.ReportableObject, 0, len(members))
+		for _, member := range members {
+			reportableObjects = append(reportableObjects, member)
+		}
+
+		last := members[len(members)-1]
+		jobData["last_team_id"] = last.TeamId
+		jobData["last_user_id"] = last.UserId
+		return reportableObjects, jobData, false, nil
+	}
+}
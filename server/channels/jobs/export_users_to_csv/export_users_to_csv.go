@@ -23,7 +23,7 @@ type ExportUsersToCSVAppIFace interface {
 	GetUsersForReporting(filter *model.UserReportOptions) ([]*model.UserReport, *model.AppError)
 }
 
-// MakeWorker creates a batch report worker to generate CSV user reports.
+// MakeWorker creates a batch report worker to generate user reports, CSV unless the job requests another format.
 func MakeWorker(jobServer *jobs.JobServer, store store.Store, app ExportUsersToCSVAppIFace) model.Worker {
 	return jobs.MakeBatchReportWorker(
 		jobServer,
@@ -112,7 +112,7 @@ func getData(app ExportUsersToCSVAppIFace) func(jobData model.StringMap) ([]mode
 
 		users, appErr := app.GetUsersForReporting(filter)
 		if appErr != nil {
-			return nil, nil, false, errors.Wrapf(err, "failed to get the next batch (column_value=%v, user_id=%v)", filter.FromColumnValue, filter.FromId)
+			return nil, nil, false, errors.Wrapf(appErr, "failed to get the next batch (column_value=%v, user_id=%v)", filter.FromColumnValue, filter.FromId)
 		}
 
 		if len(users) == 0 {
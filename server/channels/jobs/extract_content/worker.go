@@ -4,6 +4,7 @@
 package extract_content
 
 import (
+	"net/http"
 	"strconv"
 
 	"github.com/mattermost/mattermost/server/public/model"
@@ -14,10 +15,15 @@ import (
 )
 
 var ignoredFiles = map[string]bool{
-	"png": true, "jpg": true, "jpeg": true, "gif": true, "wmv": true,
-	"mpg": true, "mpeg": true, "mp3": true, "mp4": true, "ogg": true,
-	"ogv": true, "mov": true, "apk": true, "svg": true, "webm": true,
-	"mkv": true,
+	"wmv": true, "mpg": true, "mpeg": true, "mp3": true, "mp4": true,
+	"ogg": true, "ogv": true, "mov": true, "apk": true, "svg": true,
+	"webm": true, "mkv": true,
+}
+
+// imageFiles are only processed when OCR is enabled.
+var imageFiles = map[string]bool{
+	"png": true, "jpg": true, "jpeg": true, "gif": true, "bmp": true,
+	"tif": true, "tiff": true, "webp": true,
 }
 
 type AppIface interface {
@@ -49,6 +55,20 @@ func MakeWorker(jobServer *jobs.JobServer, app AppIface, store store.Store) *job
 			toTS *= 1000
 		}
 
+		// Backfilling the text of the images after enabling OCR does not
+		// require extracting the other files again.
+		ocrEnabled := *jobServer.Config().FileSettings.ExtractContentOCR
+		imagesOnly := job.Data["images_only"] == "true"
+		if imagesOnly && !ocrEnabled {
+			return model.NewAppError("ExtractContentWorker", "extract_content.worker.do_job.ocr_disabled", nil, "", http.StatusBadRequest)
+		}
+		shouldExtract := func(extension string) bool {
+			if imageFiles[extension] {
+				return ocrEnabled
+			}
+			return !imagesOnly && !ignoredFiles[extension]
+		}
+
 		var nFiles int
 		var nErrs int
 		for {
@@ -65,7 +85,7 @@ func MakeWorker(jobServer *jobs.JobServer, app AppIface, store store.Store) *job
 				break
 			}
 			for _, fileInfo := range fileInfos {
-				if !ignoredFiles[fileInfo.Extension] {
+				if shouldExtract(fileInfo.Extension) {
 					logger.Debug("Extracting file", mlog.String("filename", fileInfo.Name), mlog.String("filepath", fileInfo.Path))
 
 					err = app.ExtractContentFromFileInfo(request.EmptyContext(logger), fileInfo)
@@ -0,0 +1,86 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package file_deduplication
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+)
+
+// garbageCollectionGracePeriod is how long a blob must have been unreferenced
+// before it is removed. It must be longer than any write of the blob, which
+// may reference it again.
+const garbageCollectionGracePeriod = 24 * time.Hour
+
+// MakeWorker creates a worker that moves files written before the dedup
+// driver was enabled into the content-addressed blob store, and removes the
+// blobs no file references anymore.
+func MakeWorker(jobServer *jobs.JobServer, fileBackend filestore.FileBackend) *jobs.SimpleWorker {
+	const workerName = "FileDeduplication"
+
+	// If the type cast fails, it will be nil
+	// which is checked later.
+	dedupBackend, _ := fileBackend.(*filestore.DedupFileBackend)
+
+	isEnabled := func(cfg *model.Config) bool {
+		return *cfg.FileSettings.DriverName == model.ImageDriverDedup
+	}
+	execute := func(logger mlog.LoggerIFace, job *model.Job) error {
+		defer jobServer.HandleJobPanic(logger, job)
+
+		if dedupBackend == nil {
+			return errors.New("the file backend does not deduplicate files")
+		}
+
+		paths, err := dedupBackend.PhysicalBackend().ListDirectoryRecursively("")
+		if err != nil {
+			return err
+		}
+
+		var nFiles, nDeduped, nErrs int
+		for _, path := range paths {
+			if path == filestore.DedupBlobDirectory || strings.HasPrefix(path, filestore.DedupBlobDirectory+"/") {
+				continue
+			}
+
+			deduped, err := dedupBackend.DedupFile(path)
+			if err != nil {
+				logger.Warn("Failed to deduplicate file", mlog.String("path", path), mlog.Err(err))
+				nErrs++
+				continue
+			}
+			if deduped {
+				nDeduped++
+			}
+			nFiles++
+		}
+
+		if job.Data == nil {
+			job.Data = make(model.StringMap)
+		}
+		job.Data["processed"] = strconv.Itoa(nFiles)
+		job.Data["deduplicated"] = strconv.Itoa(nDeduped)
+		job.Data["errors"] = strconv.Itoa(nErrs)
+
+		nRemoved, err := dedupBackend.CollectGarbage(garbageCollectionGracePeriod)
+		if err != nil {
+			logger.Warn("Failed to remove unreferenced blobs", mlog.Err(err))
+		}
+		job.Data["removed_blobs"] = strconv.Itoa(nRemoved)
+
+		if err := jobServer.UpdateInProgressJobData(job); err != nil {
+			logger.Error("Worker: Failed to update job data", mlog.Err(err))
+		}
+		return nil
+	}
+	worker := jobs.NewSimpleWorker(workerName, jobServer, execute, isEnabled)
+	return worker
+}
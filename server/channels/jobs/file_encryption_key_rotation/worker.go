@@ -0,0 +1,73 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package file_encryption_key_rotation
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+)
+
+// MakeWorker creates a worker that wraps the data key of every encrypted file
+// with the active master key, so that retired keys can be removed from the
+// configuration afterwards. File contents are not rewritten.
+func MakeWorker(jobServer *jobs.JobServer, fileBackend filestore.FileBackend) *jobs.SimpleWorker {
+	const workerName = "FileEncryptionKeyRotation"
+
+	encryptedBackends := filestore.FindEncryptedFileBackends(fileBackend)
+
+	isEnabled := func(cfg *model.Config) bool {
+		return *cfg.FileSettings.EnableEncryption
+	}
+	execute := func(logger mlog.LoggerIFace, job *model.Job) error {
+		defer jobServer.HandleJobPanic(logger, job)
+
+		if len(encryptedBackends) == 0 {
+			return errors.New("the file backend does not encrypt files")
+		}
+
+		var nFiles, nRewrapped, nErrs int
+		for _, encryptedBackend := range encryptedBackends {
+			paths, err := encryptedBackend.ListEncryptedFiles()
+			if err != nil {
+				return err
+			}
+
+			for _, path := range paths {
+				rewrapped, err := encryptedBackend.RewrapFileKey(path)
+				if err != nil {
+					logger.Warn("Failed to rotate the encryption key of file", mlog.String("path", path), mlog.Err(err))
+					nErrs++
+					continue
+				}
+				if rewrapped {
+					nRewrapped++
+				}
+				nFiles++
+			}
+		}
+
+		if job.Data == nil {
+			job.Data = make(model.StringMap)
+		}
+		job.Data["processed"] = strconv.Itoa(nFiles)
+		job.Data["rewrapped"] = strconv.Itoa(nRewrapped)
+		job.Data["errors"] = strconv.Itoa(nErrs)
+
+		if err := jobServer.UpdateInProgressJobData(job); err != nil {
+			logger.Error("Worker: Failed to update job data", mlog.Err(err))
+		}
+
+		if nErrs > 0 {
+			return errors.New("failed to rotate the encryption key of " + strconv.Itoa(nErrs) + " files")
+		}
+		return nil
+	}
+	worker := jobs.NewSimpleWorker(workerName, jobServer, execute, isEnabled)
+	return worker
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package file_tiering
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+)
+
+const batchSize = 1000
+
+type AppIface interface {
+	MoveFileToStorageTier(rctx request.CTX, fileInfo *model.FileInfo, tier string) *model.AppError
+}
+
+// MakeWorker creates a worker that moves files to the cold storage tier. By
+// default, every hot file older or larger than the configured thresholds is
+// moved. A job can instead name the files to move, and the tier to move them
+// to, through the "file_ids" and "tier" data keys.
+func MakeWorker(jobServer *jobs.JobServer, app AppIface) *jobs.SimpleWorker {
+	const workerName = "FileTiering"
+
+	isEnabled := func(cfg *model.Config) bool {
+		return *cfg.FileSettings.EnableTieredStorage
+	}
+	execute := func(logger mlog.LoggerIFace, job *model.Job) error {
+		defer jobServer.HandleJobPanic(logger, job)
+
+		if job.Data == nil {
+			job.Data = make(model.StringMap)
+		}
+
+		tier := model.FileStorageTierCold
+		if t := job.Data["tier"]; t != "" {
+			tier = t
+		}
+		if !model.IsValidFileStorageTier(tier) {
+			return errors.New("invalid storage tier " + tier)
+		}
+
+		rctx := request.EmptyContext(logger)
+		var nFiles, nErrs int
+		move := func(fileInfos []*model.FileInfo) {
+			for _, fileInfo := range fileInfos {
+				if fileInfo.StorageTier == tier {
+					continue
+				}
+				if appErr := app.MoveFileToStorageTier(rctx, fileInfo, tier); appErr != nil {
+					logger.Warn("Failed to move file to storage tier", mlog.String("file_info_id", fileInfo.Id), mlog.String("tier", tier), mlog.Err(appErr))
+					nErrs++
+					continue
+				}
+				nFiles++
+			}
+		}
+
+		if fileIds := job.Data["file_ids"]; fileIds != "" {
+			fileInfos, err := jobServer.Store.FileInfo().GetByIds(strings.Split(fileIds, ","), true, false)
+			if err != nil {
+				return err
+			}
+			move(fileInfos)
+		} else {
+			cfg := jobServer.Config()
+			var createdBefore int64
+			if days := *cfg.FileSettings.TieredStorageColdAfterDays; days > 0 {
+				createdBefore = model.GetMillisForTime(time.Now().AddDate(0, 0, -days))
+			}
+			minSize := int64(*cfg.FileSettings.TieredStorageColdAboveSizeMB) * 1024 * 1024
+
+			afterId := ""
+			for {
+				fileInfos, err := jobServer.Store.FileInfo().GetForStorageTiering(createdBefore, minSize, afterId, batchSize)
+				if err != nil {
+					return err
+				}
+				if len(fileInfos) == 0 {
+					break
+				}
+				move(fileInfos)
+				afterId = fileInfos[len(fileInfos)-1].Id
+			}
+		}
+
+		job.Data["moved"] = strconv.Itoa(nFiles)
+		job.Data["errors"] = strconv.Itoa(nErrs)
+
+		if err := jobServer.UpdateInProgressJobData(job); err != nil {
+			logger.Error("Worker: Failed to update job data", mlog.Err(err))
+		}
+
+		if nErrs > 0 {
+			return errors.New("failed to move " + strconv.Itoa(nErrs) + " files between storage tiers")
+		}
+		return nil
+	}
+	worker := jobs.NewSimpleWorker(workerName, jobServer, execute, isEnabled)
+	return worker
+}
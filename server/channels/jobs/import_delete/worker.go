@@ -98,6 +98,30 @@ func MakeWorker(jobServer *jobs.JobServer, app AppIface, s store.Store) *jobs.Si
 			}
 		}
 
+		// The reports of the dry run import jobs are deleted after the same retention time.
+		reports, appErr := app.ListDirectory(model.ImportDryRunReportsDir)
+		if appErr != nil {
+			logger.Debug("Worker: Failed to list the dry run reports", mlog.Err(appErr))
+			multipleErrors.Append(appErr)
+		}
+		for _, report := range reports {
+			modTime, appErr := app.FileModTime(report)
+			if appErr != nil {
+				logger.Debug("Worker: Failed to get file modification time",
+					mlog.Err(appErr), mlog.String("report", report))
+				multipleErrors.Append(appErr)
+				continue
+			}
+
+			if time.Now().After(modTime.Add(retentionTime)) {
+				if appErr := app.RemoveFile(report); appErr != nil {
+					logger.Debug("Worker: Failed to remove file",
+						mlog.Err(appErr), mlog.String("report", report))
+					multipleErrors.Append(appErr)
+				}
+			}
+		}
+
 		if err := multipleErrors.ErrorOrNil(); err != nil {
 			logger.Warn("Worker: errors occurred", mlog.Err(err))
 		}
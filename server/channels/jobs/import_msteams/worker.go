@@ -0,0 +1,124 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package import_msteams
+
+import (
+	"archive/zip"
+	"io"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs/import_process"
+	"github.com/mattermost/mattermost/server/v8/platform/services/msteamsimport"
+)
+
+type AppIface interface {
+	import_process.ImportFileAppIface
+	BulkImportWithPath(c request.CTX, jsonlReader io.Reader, attachmentsReader *zip.Reader, dryRun, extractContent bool, workers int, importPath string) (*model.AppError, int)
+	Log() *mlog.Logger
+}
+
+// MakeWorker returns the worker converting a Microsoft Teams export to the bulk import format, and importing it once
+// validated. The counts of the conversion report are stored in the data of the job.
+func MakeWorker(jobServer *jobs.JobServer, app AppIface) *jobs.SimpleWorker {
+	const workerName = "ImportMSTeams"
+
+	appContext := request.EmptyContext(jobServer.Logger())
+	isEnabled := func(cfg *model.Config) bool {
+		return true
+	}
+	execute := func(logger mlog.LoggerIFace, job *model.Job) error {
+		defer jobServer.HandleJobPanic(logger, job)
+
+		importFile, err := import_process.OpenImportFile(appContext, app, job)
+		if err != nil {
+			return err
+		}
+		defer importFile.Close()
+
+		exportZipReader, err := zip.NewReader(importFile.ReadCloseSeeker.(io.ReaderAt), importFile.Size)
+		if err != nil {
+			return model.NewAppError("ImportMSTeamsWorker", "import_msteams.worker.do_job.open_file", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+
+		// The converted import is written to a temporary file, the attachments may not fit in memory.
+		convertedFile, err := os.CreateTemp("", "mattermost-msteams-*.zip")
+		if err != nil {
+			return model.NewAppError("ImportMSTeamsWorker", "import_msteams.worker.do_job.convert", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+		defer os.Remove(convertedFile.Name())
+		defer convertedFile.Close()
+
+		report, err := msteamsimport.Convert(exportZipReader, convertedFile, msteamsimport.Options{
+			Office365Auth: job.Data["office365_auth"] == "true",
+		})
+		if err != nil {
+			return model.NewAppError("ImportMSTeamsWorker", "import_msteams.worker.do_job.convert", nil, "", http.StatusBadRequest).Wrap(err)
+		}
+		setReport(job, report)
+		for _, warning := range report.Warnings {
+			logger.Warn("Microsoft Teams export conversion warning", mlog.String("warning", warning))
+		}
+
+		convertedFileInfo, err := convertedFile.Stat()
+		if err != nil {
+			return model.NewAppError("ImportMSTeamsWorker", "import_msteams.worker.do_job.convert", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+		importZipReader, err := zip.NewReader(convertedFile, convertedFileInfo.Size())
+		if err != nil {
+			return model.NewAppError("ImportMSTeamsWorker", "import_msteams.worker.do_job.convert", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+
+		extractContent := job.Data["extract_content"] == "true"
+		// The whole import is validated first, not to import a part of the export only.
+		for _, dryRun := range []bool{true, false} {
+			if err = bulkImport(appContext, app, job, importZipReader, dryRun, extractContent); err != nil {
+				return err
+			}
+		}
+
+		// remove import file when done.
+		return importFile.Remove(app)
+	}
+	worker := jobs.NewSimpleWorker(workerName, jobServer, execute, isEnabled)
+	return worker
+}
+
+func bulkImport(c request.CTX, app AppIface, job *model.Job, importZipReader *zip.Reader, dryRun, extractContent bool) error {
+	jsonFile, err := importZipReader.Open(msteamsimport.JSONLFilename)
+	if err != nil {
+		return model.NewAppError("ImportMSTeamsWorker", "import_msteams.worker.do_job.open_file", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	defer jsonFile.Close()
+
+	appErr, lineNumber := app.BulkImportWithPath(c, jsonFile, importZipReader, dryRun, extractContent, runtime.NumCPU(), model.ExportDataDir)
+	if appErr != nil {
+		job.Data["line_number"] = strconv.Itoa(lineNumber)
+		return appErr
+	}
+	return nil
+}
+
+func setReport(job *model.Job, report *msteamsimport.Report) {
+	for key, count := range map[string]int{
+		"teams":            report.Teams,
+		"channels":         report.Channels,
+		"users":            report.Users,
+		"posts":            report.Posts,
+		"replies":          report.Replies,
+		"direct_channels":  report.DirectChannels,
+		"direct_posts":     report.DirectPosts,
+		"attachments":      report.Attachments,
+		"skipped_messages": report.SkippedMessages,
+		"warnings":         len(report.Warnings),
+	} {
+		job.Data[key] = strconv.Itoa(count)
+	}
+}
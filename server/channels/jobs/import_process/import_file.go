@@ -0,0 +1,104 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package import_process
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/configservice"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+)
+
+// ImportFileAppIface is the part of the app the import jobs read their file with.
+type ImportFileAppIface interface {
+	configservice.ConfigService
+	RemoveFile(path string) *model.AppError
+	FileExists(path string) (bool, *model.AppError)
+	FileSize(path string) (int64, *model.AppError)
+	FileModTime(path string) (time.Time, *model.AppError)
+	FileReader(path string) (filestore.ReadCloseSeeker, *model.AppError)
+}
+
+// ImportFile is the file of an import job, read from the import directory of the file store, or from the local file
+// system in local mode.
+type ImportFile struct {
+	filestore.ReadCloseSeeker
+	Size    int64
+	ModTime time.Time
+
+	// path is the path of the file in the file store, empty in local mode.
+	path string
+}
+
+// OpenImportFile opens the file of the import job.
+func OpenImportFile(c request.CTX, app ImportFileAppIface, job *model.Job) (*ImportFile, error) {
+	importFileName, ok := job.Data["import_file"]
+	if !ok {
+		return nil, model.NewAppError("ImportProcessWorker", "import_process.worker.do_job.missing_file", nil, "", http.StatusBadRequest)
+	}
+
+	if job.Data["local_mode"] == "true" {
+		// We simply read the file from the local filesystem.
+		info, err := os.Stat(importFileName)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("file %s doesn't exist.", importFileName)
+		}
+
+		f, err := os.Open(importFileName)
+		if err != nil {
+			return nil, err
+		}
+		return &ImportFile{ReadCloseSeeker: f, Size: info.Size(), ModTime: info.ModTime()}, nil
+	}
+
+	importFilePath := filepath.Join(*app.Config().ImportSettings.Directory, importFileName)
+	if ok, err := app.FileExists(importFilePath); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, model.NewAppError("ImportProcessWorker", "import_process.worker.do_job.file_exists", nil, "", http.StatusBadRequest)
+	}
+
+	size, appErr := app.FileSize(importFilePath)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	modTime, appErr := app.FileModTime(importFilePath)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	f, appErr := app.FileReader(importFilePath)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	// The import is a long running operation, try to cancel any timeouts attached to the reader.
+	type TimeoutCanceler interface{ CancelTimeout() bool }
+	if tc, ok := f.(TimeoutCanceler); ok {
+		if !tc.CancelTimeout() {
+			c.Logger().Warn("Could not cancel the timeout for the file reader. The import may fail due to a timeout.")
+		}
+	}
+
+	return &ImportFile{ReadCloseSeeker: f, Size: size, ModTime: modTime, path: importFilePath}, nil
+}
+
+// Remove removes the file from the import directory once imported. The files of the local mode are kept.
+func (f *ImportFile) Remove(app ImportFileAppIface) error {
+	if f.path == "" {
+		return nil
+	}
+	if appErr := app.RemoveFile(f.path); appErr != nil {
+		return appErr
+	}
+	return nil
+}
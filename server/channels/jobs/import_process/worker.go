@@ -5,31 +5,33 @@ package import_process
 
 import (
 	"archive/zip"
-	"errors"
-	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
 
 	"github.com/mattermost/mattermost/server/public/model"
-	"github.com/mattermost/mattermost/server/public/shared/configservice"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/mattermost/mattermost/server/public/shared/request"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs"
-	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+)
+
+const (
+	// checkpointLineKey is the job data key of the last line of the import file committed by the job.
+	checkpointLineKey = "checkpoint_line"
+	// importFileSizeKey and importFileModTimeKey are the job data keys of the version of the import file the checkpoint
+	// was committed for. A job resumes from the checkpoint of a previous job only if it imports the same version.
+	importFileSizeKey    = "import_file_size"
+	importFileModTimeKey = "import_file_mod_time"
 )
 
 type AppIface interface {
-	configservice.ConfigService
-	RemoveFile(path string) *model.AppError
-	FileExists(path string) (bool, *model.AppError)
-	FileSize(path string) (int64, *model.AppError)
-	FileReader(path string) (filestore.ReadCloseSeeker, *model.AppError)
-	BulkImportWithPath(c request.CTX, jsonlReader io.Reader, attachmentsReader *zip.Reader, dryRun, extractContent bool, workers int, importPath string) (*model.AppError, int)
+	ImportFileAppIface
+	DryRunBulkImportWithPath(c request.CTX, jsonlReader io.Reader, attachmentsReader *zip.Reader, workers int, importPath string) (*model.ImportDryRunReport, *model.AppError, int)
+	SaveImportDryRunReport(jobID string, report *model.ImportDryRunReport) *model.AppError
+	ResumeBulkImportWithPath(c request.CTX, jsonlReader io.Reader, attachmentsReader *zip.Reader, extractContent bool, workers int, importPath string, fromLine int, checkpoint func(lineNumber int)) (*model.AppError, int)
 	Log() *mlog.Logger
 }
 
@@ -43,58 +45,13 @@ func MakeWorker(jobServer *jobs.JobServer, app AppIface) *jobs.SimpleWorker {
 	execute := func(logger mlog.LoggerIFace, job *model.Job) error {
 		defer jobServer.HandleJobPanic(logger, job)
 
-		importFileName, ok := job.Data["import_file"]
-		if !ok {
-			return model.NewAppError("ImportProcessWorker", "import_process.worker.do_job.missing_file", nil, "", http.StatusBadRequest)
-		}
-
-		var importFilePath string
-		var importFileSize int64
-		var importFile filestore.ReadCloseSeeker
-		if job.Data["local_mode"] == "true" {
-			// We simply read the file from the local filesystem.
-			info, err := os.Stat(importFileName)
-			if errors.Is(err, os.ErrNotExist) {
-				return fmt.Errorf("file %s doesn't exist.", importFileName)
-			}
-
-			importFileSize = info.Size()
-
-			importFile, err = os.Open(importFileName)
-			if err != nil {
-				return err
-			}
-			defer importFile.Close()
-		} else {
-			importFilePath = filepath.Join(*app.Config().ImportSettings.Directory, importFileName)
-			if ok, err := app.FileExists(importFilePath); err != nil {
-				return err
-			} else if !ok {
-				return model.NewAppError("ImportProcessWorker", "import_process.worker.do_job.file_exists", nil, "", http.StatusBadRequest)
-			}
-
-			var appErr *model.AppError
-			importFileSize, appErr = app.FileSize(importFilePath)
-			if appErr != nil {
-				return appErr
-			}
-
-			importFile, appErr = app.FileReader(importFilePath)
-			if appErr != nil {
-				return appErr
-			}
-			defer importFile.Close()
-
-			// The import is a long running operation, try to cancel any timeouts attached to the reader.
-			type TimeoutCanceler interface{ CancelTimeout() bool }
-			if tc, ok := importFile.(TimeoutCanceler); ok {
-				if !tc.CancelTimeout() {
-					appContext.Logger().Warn("Could not cancel the timeout for the file reader. The import may fail due to a timeout.")
-				}
-			}
+		importFile, err := OpenImportFile(appContext, app, job)
+		if err != nil {
+			return err
 		}
+		defer importFile.Close()
 
-		importZipReader, err := zip.NewReader(importFile.(io.ReaderAt), importFileSize)
+		importZipReader, err := zip.NewReader(importFile.ReadCloseSeeker.(io.ReaderAt), importFile.Size)
 		if err != nil {
 			return model.NewAppError("ImportProcessWorker", "import_process.worker.do_job.open_file", nil, "", http.StatusInternalServerError).Wrap(err)
 		}
@@ -123,23 +80,104 @@ func MakeWorker(jobServer *jobs.JobServer, app AppIface) *jobs.SimpleWorker {
 			return model.NewAppError("ImportProcessWorker", "import_process.worker.do_job.missing_jsonl", nil, "jsonFile was nil", http.StatusBadRequest)
 		}
 
+		if job.Data["dry_run"] == "true" {
+			// The import file is kept to import it after the dry run.
+			return dryRun(appContext, app, job, jsonFile, importZipReader)
+		}
+
 		extractContent := job.Data["extract_content"] == "true"
+
+		// The import resumes after the last line committed by this job, or by the last job that failed to import the
+		// same file.
+		fromLine, err := strconv.Atoi(job.Data[checkpointLineKey])
+		if err != nil || !sameImportFile(job, importFile) {
+			delete(job.Data, checkpointLineKey)
+			fromLine = previousCheckpoint(appContext, jobServer, job, importFile)
+		}
+		job.Data[importFileSizeKey] = strconv.FormatInt(importFile.Size, 10)
+		job.Data[importFileModTimeKey] = strconv.FormatInt(importFile.ModTime.UnixMilli(), 10)
+		checkpoint := func(lineNumber int) {
+			job.Data[checkpointLineKey] = strconv.Itoa(lineNumber)
+			if appErr := jobServer.UpdateInProgressJobData(job); appErr != nil {
+				logger.Warn("Failed to save the import checkpoint", mlog.Int("checkpoint_line", lineNumber), mlog.Err(appErr))
+			}
+		}
+
 		// do the actual import.
-		appErr, lineNumber := app.BulkImportWithPath(appContext, jsonFile, importZipReader, false, extractContent, runtime.NumCPU(), model.ExportDataDir)
+		appErr, lineNumber := app.ResumeBulkImportWithPath(appContext, jsonFile, importZipReader, extractContent, runtime.NumCPU(), model.ExportDataDir, fromLine, checkpoint)
 		if appErr != nil {
 			job.Data["line_number"] = strconv.Itoa(lineNumber)
 			return appErr
 		}
 
-		// No need to remove the file in local mode.
-		if job.Data["local_mode"] != "true" {
-			// remove import file when done.
-			if appErr := app.RemoveFile(importFilePath); appErr != nil {
-				return appErr
-			}
-		}
-		return nil
+		// remove import file when done.
+		return importFile.Remove(app)
 	}
 	worker := jobs.NewSimpleWorker(workerName, jobServer, execute, isEnabled)
 	return worker
 }
+
+// dryRun runs the import without changing the data of the server, saving the report of what the import would change.
+// The job data counts the changes by entity type and action, e.g. "channel_update".
+func dryRun(c request.CTX, app AppIface, job *model.Job, jsonFile io.Reader, importZipReader *zip.Reader) error {
+	report, appErr, lineNumber := app.DryRunBulkImportWithPath(c, jsonFile, importZipReader, runtime.NumCPU(), model.ExportDataDir)
+	for entity, counts := range report.Entities {
+		for action, count := range map[string]int64{
+			model.ImportDryRunActionCreate: counts.Create,
+			model.ImportDryRunActionUpdate: counts.Update,
+			model.ImportDryRunActionDelete: counts.Delete,
+			model.ImportDryRunActionNoOp:   counts.NoOp,
+		} {
+			if count > 0 {
+				job.Data[entity+"_"+action] = strconv.FormatInt(count, 10)
+			}
+		}
+	}
+	if saveErr := app.SaveImportDryRunReport(job.Id, report); saveErr != nil {
+		return saveErr
+	}
+
+	if appErr != nil {
+		job.Data["line_number"] = strconv.Itoa(lineNumber)
+		return appErr
+	}
+	return nil
+}
+
+// sameImportFile returns whether the job imported the same version of the import file, of the same size and
+// modification time.
+func sameImportFile(job *model.Job, importFile *ImportFile) bool {
+	return job.Data[importFileSizeKey] == strconv.FormatInt(importFile.Size, 10) &&
+		job.Data[importFileModTimeKey] == strconv.FormatInt(importFile.ModTime.UnixMilli(), 10)
+}
+
+// previousCheckpoint returns the last line committed by the most recent job that failed to import the same version of
+// the file, or 0 if there is none. The jobs still in progress are left out, they may be importing the file.
+func previousCheckpoint(c request.CTX, jobServer *jobs.JobServer, job *model.Job, importFile *ImportFile) int {
+	var previous *model.Job
+	for _, status := range []string{model.JobStatusError, model.JobStatusCanceled} {
+		jobs, appErr := jobServer.GetJobsByTypeAndStatus(c, model.JobTypeImportProcess, status)
+		if appErr != nil {
+			c.Logger().Warn("Failed to get the previous import jobs", mlog.String("status", status), mlog.Err(appErr))
+			continue
+		}
+		for _, j := range jobs {
+			if j.Id == job.Id || j.Data["import_file"] != job.Data["import_file"] || j.Data[checkpointLineKey] == "" || !sameImportFile(j, importFile) {
+				continue
+			}
+			if previous == nil || j.CreateAt > previous.CreateAt {
+				previous = j
+			}
+		}
+	}
+	if previous == nil {
+		return 0
+	}
+
+	fromLine, err := strconv.Atoi(previous.Data[checkpointLineKey])
+	if err != nil {
+		return 0
+	}
+	c.Logger().Info("Resuming the import of a previous job", mlog.String("previous_job_id", previous.Id), mlog.Int("checkpoint_line", fromLine))
+	return fromLine
+}
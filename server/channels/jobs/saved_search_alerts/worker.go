@@ -0,0 +1,74 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package saved_search_alerts
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+)
+
+const batchSize = 100
+
+type AppIface interface {
+	RunSavedSearchAlert(rctx request.CTX, savedSearch *model.SavedSearch) *model.AppError
+}
+
+// MakeWorker creates a worker that re-runs the saved searches with alerts
+// enabled and notifies their users of the new matches.
+func MakeWorker(jobServer *jobs.JobServer, app AppIface) *jobs.SimpleWorker {
+	const workerName = "SavedSearchAlerts"
+
+	isEnabled := func(cfg *model.Config) bool {
+		return *cfg.ServiceSettings.EnablePostSearch
+	}
+	execute := func(logger mlog.LoggerIFace, job *model.Job) error {
+		defer jobServer.HandleJobPanic(logger, job)
+
+		if job.Data == nil {
+			job.Data = make(model.StringMap)
+		}
+
+		rctx := request.EmptyContext(logger)
+		var nSearches, nErrs int
+		afterId := ""
+		for {
+			savedSearches, err := jobServer.Store.SavedSearch().GetWithAlertsEnabled(afterId, batchSize)
+			if err != nil {
+				return err
+			}
+			if len(savedSearches) == 0 {
+				break
+			}
+
+			for _, savedSearch := range savedSearches {
+				if appErr := app.RunSavedSearchAlert(rctx, savedSearch); appErr != nil {
+					logger.Warn("Failed to run saved search alert", mlog.String("saved_search_id", savedSearch.Id), mlog.String("user_id", savedSearch.UserId), mlog.Err(appErr))
+					nErrs++
+					continue
+				}
+				nSearches++
+			}
+			afterId = savedSearches[len(savedSearches)-1].Id
+		}
+
+		job.Data["searches"] = strconv.Itoa(nSearches)
+		job.Data["errors"] = strconv.Itoa(nErrs)
+
+		if err := jobServer.UpdateInProgressJobData(job); err != nil {
+			logger.Error("Worker: Failed to update job data", mlog.Err(err))
+		}
+
+		if nErrs > 0 {
+			return errors.New("failed to run " + strconv.Itoa(nErrs) + " saved search alerts")
+		}
+		return nil
+	}
+	worker := jobs.NewSimpleWorker(workerName, jobServer, execute, isEnabled)
+	return worker
+}
@@ -248,7 +248,7 @@ func (s LocalCacheChannelStore) GetMany(ids []string, allowFromCache bool) (mode
 	}
 
 	toPass := allocateCacheTargets[*model.Channel](len(ids))
-	errs := s.rootStore.doMultiReadCache(s.rootStore.roleCache, ids, toPass)
+	errs := s.rootStore.doMultiReadCache(s.rootStore.channelByIdCache, ids, toPass)
 	for i, err := range errs {
 		if err != nil {
 			if err != cache.ErrKeyNotFound {
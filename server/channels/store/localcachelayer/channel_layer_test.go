@@ -15,6 +15,7 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/request"
 	"github.com/mattermost/mattermost/server/v8/channels/store/storetest"
 	"github.com/mattermost/mattermost/server/v8/channels/store/storetest/mocks"
+	"github.com/mattermost/mattermost/server/v8/platform/services/cache"
 	cmocks "github.com/mattermost/mattermost/server/v8/platform/services/cache/mocks"
 )
 
@@ -29,7 +30,7 @@ func TestChannelStoreChannelMemberCountsCache(t *testing.T) {
 	t.Run("first call not cached, second cached and returning same data", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		count, err := cachedStore.Channel().GetMemberCount("id", true)
@@ -45,7 +46,7 @@ func TestChannelStoreChannelMemberCountsCache(t *testing.T) {
 	t.Run("first call not cached, second force not cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Channel().GetMemberCount("id", true)
@@ -57,7 +58,7 @@ func TestChannelStoreChannelMemberCountsCache(t *testing.T) {
 	t.Run("first call force not cached, second not cached, third cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Channel().GetMemberCount("id", false)
@@ -71,7 +72,7 @@ func TestChannelStoreChannelMemberCountsCache(t *testing.T) {
 	t.Run("first call with GetMemberCountFromCache not cached, second cached and returning same data", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		count := cachedStore.Channel().GetMemberCountFromCache("id")
@@ -85,7 +86,7 @@ func TestChannelStoreChannelMemberCountsCache(t *testing.T) {
 	t.Run("first call not cached, clear cache, second call not cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Channel().GetMemberCount("id", true)
@@ -98,7 +99,7 @@ func TestChannelStoreChannelMemberCountsCache(t *testing.T) {
 	t.Run("first call not cached, invalidate cache, second call not cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Channel().GetMemberCount("id", true)
@@ -119,7 +120,7 @@ func TestChannelStoreChannelsMemberCountCache(t *testing.T) {
 	t.Run("first call not cached, second cached and returning same data", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		channelsCount, err := cachedStore.Channel().GetChannelsMemberCount([]string{"channel1", "channel2"})
@@ -135,7 +136,7 @@ func TestChannelStoreChannelsMemberCountCache(t *testing.T) {
 	t.Run("first call not cached, invalidate cache, second call not cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Channel().GetChannelsMemberCount([]string{"channel1", "channel2"})
@@ -154,7 +155,7 @@ func TestChannelStoreChannelPinnedPostsCountsCache(t *testing.T) {
 	t.Run("first call not cached, second cached and returning same data", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		count, err := cachedStore.Channel().GetPinnedPostCount("id", true)
@@ -170,7 +171,7 @@ func TestChannelStoreChannelPinnedPostsCountsCache(t *testing.T) {
 	t.Run("first call not cached, second force not cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Channel().GetPinnedPostCount("id", true)
@@ -182,7 +183,7 @@ func TestChannelStoreChannelPinnedPostsCountsCache(t *testing.T) {
 	t.Run("first call force not cached, second not cached, third cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Channel().GetPinnedPostCount("id", false)
@@ -196,7 +197,7 @@ func TestChannelStoreChannelPinnedPostsCountsCache(t *testing.T) {
 	t.Run("first call not cached, clear cache, second call not cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Channel().GetPinnedPostCount("id", true)
@@ -209,7 +210,7 @@ func TestChannelStoreChannelPinnedPostsCountsCache(t *testing.T) {
 	t.Run("first call not cached, invalidate cache, second call not cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Channel().GetPinnedPostCount("id", true)
@@ -227,7 +228,7 @@ func TestChannelStoreGuestCountCache(t *testing.T) {
 	t.Run("first call not cached, second cached and returning same data", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		count, err := cachedStore.Channel().GetGuestCount("id", true)
@@ -243,7 +244,7 @@ func TestChannelStoreGuestCountCache(t *testing.T) {
 	t.Run("first call not cached, second force not cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Channel().GetGuestCount("id", true)
@@ -255,7 +256,7 @@ func TestChannelStoreGuestCountCache(t *testing.T) {
 	t.Run("first call force not cached, second not cached, third cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Channel().GetGuestCount("id", false)
@@ -269,7 +270,7 @@ func TestChannelStoreGuestCountCache(t *testing.T) {
 	t.Run("first call not cached, clear cache, second call not cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Channel().GetGuestCount("id", true)
@@ -282,7 +283,7 @@ func TestChannelStoreGuestCountCache(t *testing.T) {
 	t.Run("first call not cached, invalidate cache, second call not cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Channel().GetGuestCount("id", true)
@@ -301,7 +302,7 @@ func TestChannelStoreChannel(t *testing.T) {
 	t.Run("first call by id not cached, second cached and returning same data", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		channel, err := cachedStore.Channel().Get(channelId, true)
@@ -317,7 +318,7 @@ func TestChannelStoreChannel(t *testing.T) {
 	t.Run("first call not cached, second force no cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Channel().Get(channelId, true)
@@ -329,7 +330,7 @@ func TestChannelStoreChannel(t *testing.T) {
 	t.Run("first call force no cached, second not cached, third cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 		cachedStore.Channel().Get(channelId, false)
 		mockStore.Channel().(*mocks.ChannelStore).AssertNumberOfCalls(t, "Get", 1)
@@ -342,7 +343,7 @@ func TestChannelStoreChannel(t *testing.T) {
 	t.Run("first call not cached, clear cache, second call not cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Channel().Get(channelId, true)
@@ -355,7 +356,7 @@ func TestChannelStoreChannel(t *testing.T) {
 	t.Run("first call not cached, invalidate cache, second call not cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 		cachedStore.Channel().Get(channelId, true)
 		mockStore.Channel().(*mocks.ChannelStore).AssertNumberOfCalls(t, "Get", 1)
@@ -371,7 +372,7 @@ func TestChannelStoreGetManyCache(t *testing.T) {
 	t.Run("first call not cached, second cached and returning same data", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		fakeChannel := model.Channel{Id: "channel1", Name: "channel1-name"}
@@ -392,10 +393,26 @@ func TestChannelStoreGetManyCache(t *testing.T) {
 		mockStore.Channel().(*mocks.ChannelStore).AssertNumberOfCalls(t, "GetMany", 2)
 	})
 
+	// The mock cache provider returns the same cache for all the caches, so a real one is needed to
+	// check that the channels are read from the cache they are added to.
+	t.Run("second call read from the channel cache", func(t *testing.T) {
+		mockStore := getMockStore(t)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, cache.NewProvider(), cache.NewProvider(), logger)
+		require.NoError(t, err)
+
+		fakeChannel := model.Channel{Id: "channel1", Name: "channel1-name"}
+		_, err = cachedStore.Channel().GetMany([]string{fakeChannel.Id}, true)
+		require.NoError(t, err)
+		channels, err := cachedStore.Channel().GetMany([]string{fakeChannel.Id}, true)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, model.ChannelList{&fakeChannel}, channels)
+		mockStore.Channel().(*mocks.ChannelStore).AssertNumberOfCalls(t, "GetMany", 1)
+	})
+
 	t.Run("passing allowCache=false should bypass cache", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		fakeChannel := model.Channel{Id: "channel1", Name: "channel1-name"}
@@ -412,7 +429,7 @@ func TestChannelStoreGetByNamesCache(t *testing.T) {
 	t.Run("first call not cached, second cached and returning same data", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		fakeChannel := model.Channel{Id: "channel1", Name: "channel1-name"}
@@ -439,7 +456,7 @@ func TestChannelStoreGetAllChannelMembersForUser(t *testing.T) {
 
 	mockStore := getMockStore(t)
 	mockCacheProvider := getMockCacheProvider()
-	cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+	cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 	require.NoError(t, err)
 
 	cmock := cmocks.NewCache(t)
@@ -31,7 +31,7 @@ func TestEmojiStoreCache(t *testing.T) {
 	t.Run("first call by id not cached, second cached and returning same data", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		emoji, err := cachedStore.Emoji().Get(rctx, "123", true)
@@ -47,7 +47,7 @@ func TestEmojiStoreCache(t *testing.T) {
 	t.Run("GetByName: first call by name not cached, second cached and returning same data", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		emoji, err := cachedStore.Emoji().GetByName(rctx, "name123", true)
@@ -63,7 +63,7 @@ func TestEmojiStoreCache(t *testing.T) {
 	t.Run("GetMultipleByName: first call by name not cached, second cached and returning same data", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		emojis, err := cachedStore.Emoji().GetMultipleByName(rctx, []string{"name123"})
@@ -81,7 +81,7 @@ func TestEmojiStoreCache(t *testing.T) {
 	t.Run("GetMultipleByName: multiple elements", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		emojis, err := cachedStore.Emoji().GetMultipleByName(rctx, []string{"name123", "name321"})
@@ -100,7 +100,7 @@ func TestEmojiStoreCache(t *testing.T) {
 	t.Run("first call by id not cached, second force not cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Emoji().Get(rctx, "123", true)
@@ -112,7 +112,7 @@ func TestEmojiStoreCache(t *testing.T) {
 	t.Run("first call by name not cached, second force not cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Emoji().GetByName(rctx, "name123", true)
@@ -124,7 +124,7 @@ func TestEmojiStoreCache(t *testing.T) {
 	t.Run("first call by id force not cached, second not cached, third cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Emoji().Get(rctx, "123", false)
@@ -138,7 +138,7 @@ func TestEmojiStoreCache(t *testing.T) {
 	t.Run("first call by name force not cached, second not cached, third cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Emoji().GetByName(rctx, "name123", false)
@@ -152,7 +152,7 @@ func TestEmojiStoreCache(t *testing.T) {
 	t.Run("first call by id, second call by name and GetMultipleByName cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Emoji().Get(rctx, "123", true)
@@ -166,7 +166,7 @@ func TestEmojiStoreCache(t *testing.T) {
 	t.Run("first call by name, second call by id cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Emoji().GetByName(rctx, "name123", true)
@@ -178,7 +178,7 @@ func TestEmojiStoreCache(t *testing.T) {
 	t.Run("first call by id not cached, invalidate, and then not cached again", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Emoji().Get(rctx, "123", true)
@@ -191,7 +191,7 @@ func TestEmojiStoreCache(t *testing.T) {
 	t.Run("call by id, use master", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Emoji().Get(rctx, "master", true)
@@ -204,7 +204,7 @@ func TestEmojiStoreCache(t *testing.T) {
 	t.Run("first call by name not cached, invalidate, and then not cached again", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Emoji().GetByName(rctx, "name123", true)
@@ -217,7 +217,7 @@ func TestEmojiStoreCache(t *testing.T) {
 	t.Run("call by name, use master", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Emoji().GetByName(rctx, "master", true)
@@ -104,6 +104,21 @@ func (s LocalCacheFileInfoStore) InvalidateFileInfosForPostCache(postId string,
 	}
 }
 
+func (s LocalCacheFileInfoStore) UpdateStorageTier(fileId, tier string) error {
+	if err := s.FileInfoStore.UpdateStorageTier(fileId, tier); err != nil {
+		return err
+	}
+
+	// The FileInfos read by id are cached with and without the deleted ones.
+	for _, includeDeleted := range []bool{false, true} {
+		s.rootStore.doInvalidateCacheCluster(s.rootStore.fileInfoCache, fmt.Sprintf("%s_%t", fileId, includeDeleted), nil)
+	}
+	if s.rootStore.metrics != nil {
+		s.rootStore.metrics.IncrementMemCacheInvalidationCounter(s.rootStore.fileInfoCache.Name())
+	}
+	return nil
+}
+
 func (s LocalCacheFileInfoStore) GetStorageUsage(allowFromCache, includeDeleted bool) (int64, error) {
 	storageUsageKey := "storage_usage"
 	if includeDeleted {
@@ -26,7 +26,7 @@ func TestFileInfoStoreCache(t *testing.T) {
 	t.Run("first call not cached, second cached and returning same data", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		fileInfos, err := cachedStore.FileInfo().GetForPost("123", true, true, true)
@@ -41,7 +41,7 @@ func TestFileInfoStoreCache(t *testing.T) {
 	t.Run("first call not cached, second force no cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.FileInfo().GetForPost("123", true, true, true)
@@ -53,7 +53,7 @@ func TestFileInfoStoreCache(t *testing.T) {
 	t.Run("first call not cached, invalidate, and then not cached again", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.FileInfo().GetForPost("123", true, true, true)
@@ -66,7 +66,7 @@ func TestFileInfoStoreCache(t *testing.T) {
 	t.Run("GetByIds cache test", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		fileInfos, err := cachedStore.FileInfo().GetByIds([]string{"123"}, true, true)
@@ -77,4 +77,18 @@ func TestFileInfoStoreCache(t *testing.T) {
 		cachedStore.FileInfo().GetForPost("123", true, true, true)
 		mockStore.FileInfo().(*mocks.FileInfoStore).AssertNumberOfCalls(t, "GetForPost", 1)
 	})
+
+	t.Run("GetByIds not cached after the storage tier is updated", func(t *testing.T) {
+		mockStore := getMockStore(t)
+		mockStore.FileInfo().(*mocks.FileInfoStore).On("UpdateStorageTier", "123", model.FileStorageTierCold).Return(nil)
+		mockCacheProvider := getMockCacheProvider()
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
+		require.NoError(t, err)
+
+		cachedStore.FileInfo().GetByIds([]string{"123"}, true, true)
+		mockStore.FileInfo().(*mocks.FileInfoStore).AssertNumberOfCalls(t, "GetByIds", 1)
+		require.NoError(t, cachedStore.FileInfo().UpdateStorageTier("123", model.FileStorageTierCold))
+		cachedStore.FileInfo().GetByIds([]string{"123"}, true, true)
+		mockStore.FileInfo().(*mocks.FileInfoStore).AssertNumberOfCalls(t, "GetByIds", 2)
+	})
 }
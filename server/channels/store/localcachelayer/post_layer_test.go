@@ -33,7 +33,7 @@ func TestPostStoreLastPostTimeCache(t *testing.T) {
 	t.Run("GetEtag: first call not cached, second cached and returning same data", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		expectedResult := fmt.Sprintf("%v.%v", model.CurrentVersion, fakeLastTime)
@@ -50,7 +50,7 @@ func TestPostStoreLastPostTimeCache(t *testing.T) {
 	t.Run("GetEtag: first call not cached, second force no cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Post().GetEtag(channelId, true, false)
@@ -62,7 +62,7 @@ func TestPostStoreLastPostTimeCache(t *testing.T) {
 	t.Run("GetEtag: first call not cached, invalidate, and then not cached again", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Post().GetEtag(channelId, true, false)
@@ -75,7 +75,7 @@ func TestPostStoreLastPostTimeCache(t *testing.T) {
 	t.Run("GetEtag: first call not cached, clear caches, and then not cached again", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Post().GetEtag(channelId, true, false)
@@ -88,7 +88,7 @@ func TestPostStoreLastPostTimeCache(t *testing.T) {
 	t.Run("GetPostsSince: first call not cached, second cached and returning same data", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		expectedResult := model.NewPostList()
@@ -107,7 +107,7 @@ func TestPostStoreLastPostTimeCache(t *testing.T) {
 	t.Run("GetPostsSince: first call not cached, second force no cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Post().GetPostsSince(fakeOptions, true, map[string]bool{})
@@ -119,7 +119,7 @@ func TestPostStoreLastPostTimeCache(t *testing.T) {
 	t.Run("GetPostsSince: first call not cached, invalidate, and then not cached again", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Post().GetPostsSince(fakeOptions, true, map[string]bool{})
@@ -132,7 +132,7 @@ func TestPostStoreLastPostTimeCache(t *testing.T) {
 	t.Run("GetPostsSince: first call not cached, clear caches, and then not cached again", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Post().GetPostsSince(fakeOptions, true, map[string]bool{})
@@ -151,7 +151,7 @@ func TestPostStoreCache(t *testing.T) {
 	t.Run("first call not cached, second cached and returning same data", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		gotPosts, err := cachedStore.Post().GetPosts(fakeOptions, true, map[string]bool{})
@@ -166,7 +166,7 @@ func TestPostStoreCache(t *testing.T) {
 	t.Run("first call not cached, second force not cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		gotPosts, err := cachedStore.Post().GetPosts(fakeOptions, true, map[string]bool{})
@@ -181,7 +181,7 @@ func TestPostStoreCache(t *testing.T) {
 	t.Run("first call not cached, invalidate, and then not cached again", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		gotPosts, err := cachedStore.Post().GetPosts(fakeOptions, true, map[string]bool{})
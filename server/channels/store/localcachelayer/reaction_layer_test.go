@@ -26,7 +26,7 @@ func TestReactionStoreCache(t *testing.T) {
 	t.Run("first call not cached, second cached and returning same data", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		reaction, err := cachedStore.Reaction().GetForPost("123", true)
@@ -41,7 +41,7 @@ func TestReactionStoreCache(t *testing.T) {
 	t.Run("first call not cached, second force not cached", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Reaction().GetForPost("123", true)
@@ -53,7 +53,7 @@ func TestReactionStoreCache(t *testing.T) {
 	t.Run("first call not cached, save, and then not cached again", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Reaction().GetForPost("123", true)
@@ -66,7 +66,7 @@ func TestReactionStoreCache(t *testing.T) {
 	t.Run("first call not cached, delete, and then not cached again", func(t *testing.T) {
 		mockStore := getMockStore(t)
 		mockCacheProvider := getMockCacheProvider()
-		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, logger)
+		cachedStore, err := NewLocalCacheLayer(mockStore, nil, nil, mockCacheProvider, mockCacheProvider, logger)
 		require.NoError(t, err)
 
 		cachedStore.Reaction().GetForPost("123", true)
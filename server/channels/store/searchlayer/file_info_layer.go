@@ -200,22 +200,63 @@ func (s SearchFileInfoStore) Search(rctx request.CTX, paramsList []*model.Search
 			if nErr != nil {
 				return nil, nErr
 			}
-			fileIds, appErr := engine.SearchFiles(userChannels, paramsList, page, perPage)
-			if appErr != nil {
-				rctx.Logger().Error("Encountered error on Search.", mlog.String("search_engine", engine.GetName()), mlog.Err(appErr))
-				continue
+			if nErr = s.rootStore.setReadChannels(paramsList, userId, teamId); nErr != nil {
+				return nil, nErr
+			}
+			var hits []*model.FileSearchHit
+			if rankedEngine, ok := engine.(searchengine.RankedSearchEngineInterface); ok {
+				var appErr *model.AppError
+				hits, appErr = rankedEngine.SearchFileHits(userChannels, paramsList, page, perPage)
+				if appErr != nil {
+					rctx.Logger().Error("Encountered error on Search.", mlog.String("search_engine", engine.GetName()), mlog.Err(appErr))
+					continue
+				}
+			} else {
+				fileIds, appErr := engine.SearchFiles(userChannels, paramsList, page, perPage)
+				if appErr != nil {
+					rctx.Logger().Error("Encountered error on Search.", mlog.String("search_engine", engine.GetName()), mlog.Err(appErr))
+					continue
+				}
+				for _, fileId := range fileIds {
+					hits = append(hits, &model.FileSearchHit{Id: fileId})
+				}
 			}
 
-			// Get the files
+			// Get the files, keeping the order of the search engine
 			filesList := model.NewFileInfoList()
-			if len(fileIds) > 0 {
+			if len(hits) > 0 {
+				fileIds := make([]string, 0, len(hits))
+				for _, hit := range hits {
+					fileIds = append(fileIds, hit.Id)
+				}
 				files, nErr := s.FileInfoStore.GetByIds(fileIds, false, true)
 				if nErr != nil {
 					return nil, nErr
 				}
+				filesById := make(map[string]*model.FileInfo, len(files))
 				for _, f := range files {
+					filesById[f.Id] = f
+				}
+				for _, hit := range hits {
+					f, ok := filesById[hit.Id]
+					if !ok {
+						continue
+					}
 					filesList.AddFileInfo(f)
 					filesList.AddOrder(f.Id)
+
+					if hit.Score != 0 {
+						if filesList.Scores == nil {
+							filesList.Scores = map[string]float64{}
+						}
+						filesList.Scores[f.Id] = hit.Score
+					}
+					if len(hit.Highlights) > 0 {
+						if filesList.Highlights == nil {
+							filesList.Highlights = map[string][]string{}
+						}
+						filesList.Highlights[f.Id] = hit.Highlights
+					}
 				}
 			}
 			return filesList, nil
@@ -5,6 +5,9 @@ package searchlayer
 
 import (
 	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
@@ -67,6 +70,64 @@ func (s *SearchStore) User() store.UserStore {
 	return s.user
 }
 
+// readChannelsMaxAge is how recently a channel must have been viewed by the
+// user for its results to be boosted when sorting by relevance.
+const readChannelsMaxAge = 30 * 24 * time.Hour
+
+const readChannelsPerPage = 1000
+
+// getReadChannelIds returns the ids of the channels recently viewed by the
+// user, in the given team or in all the teams if teamId is empty.
+func (s *SearchStore) getReadChannelIds(userId, teamId string) ([]string, error) {
+	members := model.ChannelMembers{}
+	if teamId != "" {
+		teamMembers, err := s.Channel().GetMembersForUser(teamId, userId)
+		if err != nil {
+			return nil, err
+		}
+		members = teamMembers
+	} else {
+		for page := 0; ; page++ {
+			pageMembers, err := s.Channel().GetMembersForUserWithPagination(userId, page, readChannelsPerPage)
+			if err != nil {
+				return nil, err
+			}
+			for _, member := range pageMembers {
+				members = append(members, member.ChannelMember)
+			}
+			if len(pageMembers) < readChannelsPerPage {
+				break
+			}
+		}
+	}
+
+	since := model.GetMillisForTime(time.Now().Add(-readChannelsMaxAge))
+	channelIds := []string{}
+	for _, member := range members {
+		if member.LastViewedAt >= since {
+			channelIds = append(channelIds, member.ChannelId)
+		}
+	}
+	return channelIds, nil
+}
+
+// setReadChannels sets the channels read by the user on the search params
+// when the results are sorted by relevance.
+func (s *SearchStore) setReadChannels(paramsList []*model.SearchParams, userId, teamId string) error {
+	if paramsList[0].Sort != model.SearchSortRelevance || paramsList[0].SearchWithoutUserId {
+		return nil
+	}
+
+	channelIds, err := s.getReadChannelIds(userId, teamId)
+	if err != nil {
+		return errors.Wrap(err, "error getting the channels read by the user")
+	}
+	for _, params := range paramsList {
+		params.ReadChannels = channelIds
+	}
+	return nil
+}
+
 func (s *SearchStore) indexUserFromID(rctx request.CTX, userId string) {
 	user, err := s.User().Get(rctx.Context(), userId)
 	if err != nil {
@@ -124,3 +185,15 @@ func runIndexFn(rctx request.CTX, engine searchengine.SearchEngineInterface, ind
 		})(engine)
 	}
 }
+
+// Runs an indexing function of the vector engine synchronously or
+// asynchronously depending on the engine. The embedding of the posts is slow,
+// so it is done in the background unless the engine is set to index
+// synchronously.
+func runVectorIndexFn(engine searchengine.VectorEngineInterface, indexFn func(searchengine.VectorEngineInterface)) {
+	if engine.IsIndexingSync() {
+		indexFn(engine)
+	} else {
+		go indexFn(engine)
+	}
+}
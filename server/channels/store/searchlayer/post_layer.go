@@ -36,6 +36,24 @@ func (s SearchPostStore) indexPost(rctx request.CTX, post *model.Post) {
 	}
 }
 
+func (s SearchPostStore) indexPostVector(rctx request.CTX, post *model.Post) {
+	engine := s.rootStore.searchEngine.GetActiveVectorEngine()
+	if engine == nil {
+		return
+	}
+
+	runVectorIndexFn(engine, func(engineCopy searchengine.VectorEngineInterface) {
+		channel, chanErr := s.rootStore.Channel().Get(post.ChannelId, true)
+		if chanErr != nil {
+			rctx.Logger().Error("Couldn't get channel for post for vector indexing.", mlog.String("channel_id", post.ChannelId), mlog.String("post_id", post.Id), mlog.Err(chanErr))
+			return
+		}
+		if err := engineCopy.IndexPost(post, channel.TeamId); err != nil {
+			rctx.Logger().Warn("Encountered error indexing post", mlog.String("post_id", post.Id), mlog.String("search_engine", engineCopy.GetName()), mlog.Err(err))
+		}
+	})
+}
+
 func (s SearchPostStore) deletePostIndex(rctx request.CTX, post *model.Post) {
 	for _, engine := range s.rootStore.searchEngine.GetActiveEngines() {
 		if engine.IsIndexingEnabled() {
@@ -49,6 +67,19 @@ func (s SearchPostStore) deletePostIndex(rctx request.CTX, post *model.Post) {
 	}
 }
 
+func (s SearchPostStore) deletePostVector(rctx request.CTX, post *model.Post) {
+	engine := s.rootStore.searchEngine.GetActiveVectorEngine()
+	if engine == nil {
+		return
+	}
+
+	runVectorIndexFn(engine, func(engineCopy searchengine.VectorEngineInterface) {
+		if err := engineCopy.DeletePost(post); err != nil {
+			rctx.Logger().Warn("Encountered error deleting post", mlog.String("post_id", post.Id), mlog.String("search_engine", engineCopy.GetName()), mlog.Err(err))
+		}
+	})
+}
+
 func (s SearchPostStore) deleteChannelPostsIndex(rctx request.CTX, channelID string) {
 	for _, engine := range s.rootStore.searchEngine.GetActiveEngines() {
 		if engine.IsIndexingEnabled() {
@@ -63,6 +94,19 @@ func (s SearchPostStore) deleteChannelPostsIndex(rctx request.CTX, channelID str
 	}
 }
 
+func (s SearchPostStore) deleteChannelPostsVector(rctx request.CTX, channelID string) {
+	engine := s.rootStore.searchEngine.GetActiveVectorEngine()
+	if engine == nil {
+		return
+	}
+
+	runVectorIndexFn(engine, func(engineCopy searchengine.VectorEngineInterface) {
+		if err := engineCopy.DeleteChannelPosts(rctx, channelID); err != nil {
+			rctx.Logger().Warn("Encountered error deleting channel posts", mlog.String("channel_id", channelID), mlog.String("search_engine", engineCopy.GetName()), mlog.Err(err))
+		}
+	})
+}
+
 func (s SearchPostStore) deleteUserPostsIndex(rctx request.CTX, userID string) {
 	for _, engine := range s.rootStore.searchEngine.GetActiveEngines() {
 		if engine.IsIndexingEnabled() {
@@ -77,11 +121,25 @@ func (s SearchPostStore) deleteUserPostsIndex(rctx request.CTX, userID string) {
 	}
 }
 
+func (s SearchPostStore) deleteUserPostsVector(rctx request.CTX, userID string) {
+	engine := s.rootStore.searchEngine.GetActiveVectorEngine()
+	if engine == nil {
+		return
+	}
+
+	runVectorIndexFn(engine, func(engineCopy searchengine.VectorEngineInterface) {
+		if err := engineCopy.DeleteUserPosts(rctx, userID); err != nil {
+			rctx.Logger().Warn("Encountered error deleting user posts", mlog.String("user_id", userID), mlog.String("search_engine", engineCopy.GetName()), mlog.Err(err))
+		}
+	})
+}
+
 func (s SearchPostStore) Update(rctx request.CTX, newPost, oldPost *model.Post) (*model.Post, error) {
 	post, err := s.PostStore.Update(rctx, newPost, oldPost)
 
 	if err == nil {
 		s.indexPost(rctx, post)
+		s.indexPostVector(rctx, post)
 	}
 	return post, err
 }
@@ -90,6 +148,7 @@ func (s *SearchPostStore) Overwrite(rctx request.CTX, post *model.Post) (*model.
 	post, err := s.PostStore.Overwrite(rctx, post)
 	if err == nil {
 		s.indexPost(rctx, post)
+		s.indexPostVector(rctx, post)
 	}
 	return post, err
 }
@@ -99,6 +158,7 @@ func (s SearchPostStore) Save(rctx request.CTX, post *model.Post) (*model.Post,
 
 	if err == nil {
 		s.indexPost(rctx, npost)
+		s.indexPostVector(rctx, npost)
 	}
 	return npost, err
 }
@@ -113,6 +173,7 @@ func (s SearchPostStore) Delete(rctx request.CTX, postId string, date int64, del
 		return err
 	}
 	s.deletePostIndex(rctx, post)
+	s.deletePostVector(rctx, post)
 	return nil
 }
 
@@ -127,6 +188,7 @@ func (s SearchPostStore) PermanentDelete(rctx request.CTX, postID string) error
 		return err
 	}
 	s.deletePostIndex(rctx, post)
+	s.deletePostVector(rctx, post)
 	return nil
 }
 
@@ -134,6 +196,7 @@ func (s SearchPostStore) PermanentDeleteByUser(rctx request.CTX, userID string)
 	err := s.PostStore.PermanentDeleteByUser(rctx, userID)
 	if err == nil {
 		s.deleteUserPostsIndex(rctx, userID)
+		s.deleteUserPostsVector(rctx, userID)
 	}
 	return err
 }
@@ -142,6 +205,7 @@ func (s SearchPostStore) PermanentDeleteByChannel(rctx request.CTX, channelID st
 	err := s.PostStore.PermanentDeleteByChannel(rctx, channelID)
 	if err == nil {
 		s.deleteChannelPostsIndex(rctx, channelID)
+		s.deleteChannelPostsVector(rctx, channelID)
 	}
 	return err
 }
@@ -161,27 +225,70 @@ func (s SearchPostStore) searchPostsForUserByEngine(engine searchengine.SearchEn
 		return nil, errors.Wrap(err2, "error getting channel for user")
 	}
 
-	postIds, matches, err := engine.SearchPosts(userChannels, paramsList, page, perPage)
-	if err != nil {
+	if err := s.rootStore.setReadChannels(paramsList, userId, teamId); err != nil {
 		return nil, err
 	}
 
-	// Get the posts
+	var hits []*model.PostSearchHit
+	var matches model.PostSearchMatches
+	if rankedEngine, ok := engine.(searchengine.RankedSearchEngineInterface); ok {
+		var appErr *model.AppError
+		hits, matches, appErr = rankedEngine.SearchPostHits(userChannels, paramsList, page, perPage)
+		if appErr != nil {
+			return nil, appErr
+		}
+	} else {
+		postIds, engineMatches, appErr := engine.SearchPosts(userChannels, paramsList, page, perPage)
+		if appErr != nil {
+			return nil, appErr
+		}
+		for _, postId := range postIds {
+			hits = append(hits, &model.PostSearchHit{Id: postId})
+		}
+		matches = engineMatches
+	}
+
+	// Get the posts, keeping the order of the search engine
 	postList := model.NewPostList()
-	if len(postIds) > 0 {
+	results := model.MakePostSearchResults(postList, matches)
+	if len(hits) > 0 {
+		postIds := make([]string, 0, len(hits))
+		for _, hit := range hits {
+			postIds = append(postIds, hit.Id)
+		}
 		posts, err := s.PostStore.GetPostsByIds(postIds)
 		if err != nil {
 			return nil, err
 		}
+		postsById := make(map[string]*model.Post, len(posts))
 		for _, p := range posts {
-			if p.DeleteAt == 0 {
-				postList.AddPost(p)
-				postList.AddOrder(p.Id)
+			postsById[p.Id] = p
+		}
+
+		for _, hit := range hits {
+			p, ok := postsById[hit.Id]
+			if !ok || p.DeleteAt != 0 {
+				continue
+			}
+			postList.AddPost(p)
+			postList.AddOrder(p.Id)
+
+			if hit.Score != 0 {
+				if results.Scores == nil {
+					results.Scores = map[string]float64{}
+				}
+				results.Scores[p.Id] = hit.Score
+			}
+			if len(hit.Highlights) > 0 {
+				if results.Highlights == nil {
+					results.Highlights = map[string][]string{}
+				}
+				results.Highlights[p.Id] = hit.Highlights
 			}
 		}
 	}
 
-	return model.MakePostSearchResults(postList, matches), nil
+	return results, nil
 }
 
 func (s SearchPostStore) SearchPostsForUser(rctx request.CTX, paramsList []*model.SearchParams, userId, teamId string, page, perPage int) (*model.PostSearchResults, error) {
@@ -11,11 +11,12 @@ import (
 )
 
 const (
-	EngineAll           = "all"
-	EngineMySQL         = "mysql"
-	EnginePostgres      = "postgres"
-	EngineElasticSearch = "elasticsearch"
-	EngineBleve         = "bleve"
+	EngineAll            = "all"
+	EngineMySQL          = "mysql"
+	EnginePostgres       = "postgres"
+	EngineElasticSearch  = "elasticsearch"
+	EngineBleve          = "bleve"
+	EnginePostgresSearch = "postgres_search"
 )
 
 type SearchTestEngine struct {
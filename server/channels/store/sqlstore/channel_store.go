@@ -2532,6 +2532,21 @@ func (s SqlChannelStore) GetGuestCount(channelId string, allowFromCache bool) (i
 	return count, nil
 }
 
+func (s SqlChannelStore) GetRecentlyActiveChannelIds(limit int) ([]string, error) {
+	query := s.getQueryBuilder().
+		Select("Id").
+		From("Channels").
+		Where(sq.Eq{"DeleteAt": 0}).
+		OrderBy("LastPostAt DESC").
+		Limit(uint64(limit))
+
+	channelIDs := []string{}
+	if err := s.GetReplica().SelectBuilder(&channelIDs, query); err != nil {
+		return nil, errors.Wrap(err, "failed to get recently active channels")
+	}
+	return channelIDs, nil
+}
+
 func (s SqlChannelStore) RemoveMembers(rctx request.CTX, channelId string, userIds []string) error {
 	builder := s.getQueryBuilder().
 		Delete("ChannelMembers").
@@ -3962,6 +3977,50 @@ func (s SqlChannelStore) GetChannelsByScheme(schemeId string, offset int, limit
 	return channels, nil
 }
 
+// GetChannelActivityReport returns a page of the team channels ordered by id after options.FromChannelId, with the
+// count of their members and of their posts created between options.StartAt and options.EndAt.
+func (s SqlChannelStore) GetChannelActivityReport(options *model.ChannelActivityReportOptions) ([]*model.ChannelActivityReport, error) {
+	postCount := sq.And{
+		sq.Expr("Posts.ChannelId = Channels.Id"),
+		sq.Eq{"Posts.DeleteAt": 0},
+	}
+	if options.StartAt > 0 {
+		postCount = append(postCount, sq.GtOrEq{"Posts.CreateAt": options.StartAt})
+	}
+	if options.EndAt > 0 {
+		postCount = append(postCount, sq.Lt{"Posts.CreateAt": options.EndAt})
+	}
+	postCountSQL, postCountArgs, err := postCount.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "channel_activity_report_tosql")
+	}
+
+	query := s.getQueryBuilder().
+		Select("Channels.Id AS ChannelId", "Teams.Name AS TeamName", "Channels.Name AS ChannelName",
+			"Channels.DisplayName", "Channels.Type", "Channels.CreateAt", "Channels.DeleteAt", "Channels.LastPostAt",
+			"(SELECT COUNT(*) FROM ChannelMembers WHERE ChannelMembers.ChannelId = Channels.Id) AS MemberCount").
+		Column("(SELECT COUNT(*) FROM Posts WHERE "+postCountSQL+") AS PostCount", postCountArgs...).
+		From("Channels").
+		Join("Teams ON Channels.TeamId = Teams.Id").
+		OrderBy("Channels.Id")
+
+	if options.TeamId != "" {
+		query = query.Where(sq.Eq{"Channels.TeamId": options.TeamId})
+	}
+	if options.FromChannelId != "" {
+		query = query.Where(sq.Gt{"Channels.Id": options.FromChannelId})
+	}
+	if options.PageSize > 0 {
+		query = query.Limit(uint64(options.PageSize))
+	}
+
+	channels := []*model.ChannelActivityReport{}
+	if err := s.GetReplica().SelectBuilder(&channels, query); err != nil {
+		return nil, errors.Wrap(err, "failed to find Channels for the channel activity report")
+	}
+	return channels, nil
+}
+
 // This function does the Advanced Permissions Phase 2 migration for ChannelMember objects. It performs the migration
 // in batches as a single transaction per batch to ensure consistency but to also minimise execution time to avoid
 // causing unnecessary table locks. **THIS FUNCTION SHOULD NOT BE USED FOR ANY OTHER PURPOSE.** Executing this function
@@ -87,6 +87,20 @@ func (es SqlEmojiStore) GetList(offset, limit int, sort string) ([]*model.Emoji,
 	return emojis, nil
 }
 
+func (es SqlEmojiStore) GetDeletedSince(since int64) ([]*model.Emoji, error) {
+	query := es.getQueryBuilder().
+		Select("Id", "CreateAt", "UpdateAt", "DeleteAt", "CreatorId", "Name").
+		From("Emoji").
+		Where(sq.Gt{"DeleteAt": since}).
+		OrderBy("Name")
+
+	emojis := []*model.Emoji{}
+	if err := es.GetReplica().SelectBuilder(&emojis, query); err != nil {
+		return nil, errors.Wrap(err, "could not get the deleted emojis")
+	}
+	return emojis, nil
+}
+
 func (es SqlEmojiStore) Delete(emoji *model.Emoji, time int64) error {
 	if sqlResult, err := es.GetMaster().Exec(
 		`UPDATE
@@ -0,0 +1,232 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+type SqlFileBlobStore struct {
+	*SqlStore
+}
+
+func newSqlFileBlobStore(sqlStore *SqlStore) store.FileBlobStore {
+	return &SqlFileBlobStore{
+		SqlStore: sqlStore,
+	}
+}
+
+func (s *SqlFileBlobStore) refsQuery() sq.SelectBuilder {
+	return s.getQueryBuilder().
+		Select("Path", "Hash", "Size", "CreateAt").
+		From("FileBlobRefs")
+}
+
+func (s *SqlFileBlobStore) GetByPaths(paths []string) ([]*model.FileBlobRef, error) {
+	refs := []*model.FileBlobRef{}
+	if len(paths) == 0 {
+		return refs, nil
+	}
+
+	query := s.refsQuery().Where(sq.Eq{"Path": paths})
+	if err := s.GetMaster().SelectBuilder(&refs, query); err != nil {
+		return nil, errors.Wrap(err, "failed to find FileBlobRefs")
+	}
+	return refs, nil
+}
+
+func (s *SqlFileBlobStore) GetByPathPrefix(prefix string) ([]*model.FileBlobRef, error) {
+	refs := []*model.FileBlobRef{}
+	query := s.refsQuery().OrderBy("Path")
+	if prefix != "" {
+		query = query.Where("Path LIKE ? ESCAPE '*'", sanitizeSearchTerm(prefix, "*")+"%")
+	}
+
+	if err := s.GetReplica().SelectBuilder(&refs, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to find FileBlobRefs with prefix=%s", prefix)
+	}
+	return refs, nil
+}
+
+func (s *SqlFileBlobStore) Save(ref *model.FileBlobRef) (refCount int64, err error) {
+	ref.PreSave()
+	if appErr := ref.IsValid(); appErr != nil {
+		return 0, appErr
+	}
+
+	transaction, err := s.GetMaster().Beginx()
+	if err != nil {
+		return 0, errors.Wrap(err, "begin_transaction")
+	}
+	defer finalizeTransactionX(transaction, &err)
+
+	insertRef := s.getQueryBuilder().
+		Insert("FileBlobRefs").
+		Columns("Path", "Hash", "Size", "CreateAt").
+		Values(ref.Path, ref.Hash, ref.Size, ref.CreateAt)
+	if _, err = transaction.ExecBuilder(insertRef); err != nil {
+		if IsUniqueConstraintError(err, []string{"PRIMARY", "fileblobrefs_pkey"}) {
+			return 0, store.NewErrConflict("FileBlobRef", err, "path="+ref.Path)
+		}
+		return 0, errors.Wrapf(err, "failed to save FileBlobRef with path=%s", ref.Path)
+	}
+
+	upsertBlob := s.getQueryBuilder().
+		Insert("FileBlobs").
+		Columns("Hash", "Size", "RefCount", "CreateAt", "UpdateAt").
+		Values(ref.Hash, ref.Size, 1, ref.CreateAt, ref.CreateAt)
+	if s.DriverName() == model.DatabaseDriverMysql {
+		upsertBlob = upsertBlob.SuffixExpr(sq.Expr("ON DUPLICATE KEY UPDATE RefCount = RefCount + 1, UpdateAt = ?", ref.CreateAt))
+	} else {
+		upsertBlob = upsertBlob.SuffixExpr(sq.Expr("ON CONFLICT (Hash) DO UPDATE SET RefCount = FileBlobs.RefCount + 1, UpdateAt = ?", ref.CreateAt))
+	}
+	if _, err = transaction.ExecBuilder(upsertBlob); err != nil {
+		return 0, errors.Wrapf(err, "failed to increment the reference count of FileBlob with hash=%s", ref.Hash)
+	}
+
+	query := s.getQueryBuilder().
+		Select("RefCount").
+		From("FileBlobs").
+		Where(sq.Eq{"Hash": ref.Hash})
+	if err = transaction.GetBuilder(&refCount, query); err != nil {
+		return 0, errors.Wrapf(err, "failed to get the reference count of FileBlob with hash=%s", ref.Hash)
+	}
+
+	if err = transaction.Commit(); err != nil {
+		return 0, errors.Wrap(err, "commit_transaction")
+	}
+
+	return refCount, nil
+}
+
+func (s *SqlFileBlobStore) Delete(path string) (_ *model.FileBlobRef, refCount int64, err error) {
+	transaction, err := s.GetMaster().Beginx()
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "begin_transaction")
+	}
+	defer finalizeTransactionX(transaction, &err)
+
+	var ref model.FileBlobRef
+	if err = transaction.GetBuilder(&ref, s.refsQuery().Where(sq.Eq{"Path": path}).Suffix("FOR UPDATE")); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, 0, store.NewErrNotFound("FileBlobRef", path)
+		}
+		return nil, 0, errors.Wrapf(err, "failed to get FileBlobRef with path=%s", path)
+	}
+
+	deleteRef := s.getQueryBuilder().
+		Delete("FileBlobRefs").
+		Where(sq.Eq{"Path": path})
+	if _, err = transaction.ExecBuilder(deleteRef); err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to delete FileBlobRef with path=%s", path)
+	}
+
+	// The blob row is kept with a zero reference count, so that
+	// DeleteUnreferenced can remove the blob once it has been unreferenced
+	// for long enough.
+	decrement := s.getQueryBuilder().
+		Update("FileBlobs").
+		Set("RefCount", sq.Expr("RefCount - 1")).
+		Set("UpdateAt", model.GetMillis()).
+		Where(sq.Eq{"Hash": ref.Hash})
+	if _, err = transaction.ExecBuilder(decrement); err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to decrement the reference count of FileBlob with hash=%s", ref.Hash)
+	}
+
+	query := s.getQueryBuilder().
+		Select("RefCount").
+		From("FileBlobs").
+		Where(sq.Eq{"Hash": ref.Hash})
+	if err = transaction.GetBuilder(&refCount, query); err != nil && err != sql.ErrNoRows {
+		return nil, 0, errors.Wrapf(err, "failed to get the reference count of FileBlob with hash=%s", ref.Hash)
+	}
+
+	if refCount < 0 {
+		refCount = 0
+	}
+
+	if err = transaction.Commit(); err != nil {
+		return nil, 0, errors.Wrap(err, "commit_transaction")
+	}
+
+	return &ref, refCount, nil
+}
+
+func (s *SqlFileBlobStore) GetRefCount(hash string) (int64, error) {
+	var refCount int64
+	query := s.getQueryBuilder().
+		Select("RefCount").
+		From("FileBlobs").
+		Where(sq.Eq{"Hash": hash})
+	if err := s.GetReplica().GetBuilder(&refCount, query); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, errors.Wrapf(err, "failed to get the reference count of FileBlob with hash=%s", hash)
+	}
+	return refCount, nil
+}
+
+func (s *SqlFileBlobStore) GetUnreferenced(before int64, afterHash string, limit int) ([]string, error) {
+	hashes := []string{}
+	query := s.getQueryBuilder().
+		Select("Hash").
+		From("FileBlobs").
+		Where(sq.LtOrEq{"RefCount": 0}).
+		Where(sq.Lt{"UpdateAt": before}).
+		Where(sq.Gt{"Hash": afterHash}).
+		OrderBy("Hash").
+		Limit(uint64(limit))
+	if err := s.GetReplica().SelectBuilder(&hashes, query); err != nil {
+		return nil, errors.Wrap(err, "failed to find unreferenced FileBlobs")
+	}
+	return hashes, nil
+}
+
+func (s *SqlFileBlobStore) DeleteUnreferenced(hash string, before int64, remove func() error) (_ bool, err error) {
+	transaction, err := s.GetMaster().Beginx()
+	if err != nil {
+		return false, errors.Wrap(err, "begin_transaction")
+	}
+	defer finalizeTransactionX(transaction, &err)
+
+	// Locking the row makes a concurrent Save of a reference to the blob wait
+	// until the blob is gone, so that it can upload the blob again.
+	var found string
+	query := s.getQueryBuilder().
+		Select("Hash").
+		From("FileBlobs").
+		Where(sq.Eq{"Hash": hash}).
+		Where(sq.LtOrEq{"RefCount": 0}).
+		Where(sq.Lt{"UpdateAt": before}).
+		Suffix("FOR UPDATE")
+	if err = transaction.GetBuilder(&found, query); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get FileBlob with hash=%s", hash)
+	}
+
+	if err = remove(); err != nil {
+		return false, errors.Wrapf(err, "failed to remove FileBlob with hash=%s", hash)
+	}
+
+	deleteBlob := s.getQueryBuilder().
+		Delete("FileBlobs").
+		Where(sq.Eq{"Hash": hash})
+	if _, err = transaction.ExecBuilder(deleteBlob); err != nil {
+		return false, errors.Wrapf(err, "failed to delete FileBlob with hash=%s", hash)
+	}
+
+	if err = transaction.Commit(); err != nil {
+		return false, errors.Wrap(err, "commit_transaction")
+	}
+	return true, nil
+}
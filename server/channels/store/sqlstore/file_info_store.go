@@ -42,6 +42,7 @@ type fileInfoWithChannelID struct {
 	Content         string
 	RemoteId        *string
 	Archived        bool
+	StorageTier     string
 }
 
 func (fi fileInfoWithChannelID) ToModel() *model.FileInfo {
@@ -66,6 +67,7 @@ func (fi fileInfoWithChannelID) ToModel() *model.FileInfo {
 		MiniPreview:     fi.MiniPreview,
 		Content:         fi.Content,
 		RemoteId:        fi.RemoteId,
+		StorageTier:     fi.StorageTier,
 	}
 }
 
@@ -106,6 +108,7 @@ func newSqlFileInfoStore(sqlStore *SqlStore, metrics einterfaces.MetricsInterfac
 		"Coalesce(FileInfo.Content, '') AS Content",
 		"Coalesce(FileInfo.RemoteId, '') AS RemoteId",
 		"FileInfo.Archived",
+		"FileInfo.StorageTier",
 	}
 
 	return s
@@ -120,10 +123,10 @@ func (fs SqlFileInfoStore) Save(rctx request.CTX, info *model.FileInfo) (*model.
 	query := `
 		INSERT INTO FileInfo
 		(Id, CreatorId, PostId, ChannelId, CreateAt, UpdateAt, DeleteAt, Path, ThumbnailPath, PreviewPath,
-			Name, Extension, Size, MimeType, Width, Height, HasPreviewImage, MiniPreview, Content, RemoteId)
+			Name, Extension, Size, MimeType, Width, Height, HasPreviewImage, MiniPreview, Content, RemoteId, StorageTier)
 		VALUES
 		(:Id, :CreatorId, :PostId, :ChannelId, :CreateAt, :UpdateAt, :DeleteAt, :Path, :ThumbnailPath, :PreviewPath,
-			:Name, :Extension, :Size, :MimeType, :Width, :Height, :HasPreviewImage, :MiniPreview, :Content, :RemoteId)
+			:Name, :Extension, :Size, :MimeType, :Width, :Height, :HasPreviewImage, :MiniPreview, :Content, :RemoteId, :StorageTier)
 	`
 
 	if _, err := fs.GetMaster().NamedExec(query, info); err != nil {
@@ -865,3 +868,65 @@ func (fs SqlFileInfoStore) RefreshFileStats() error {
 
 	return nil
 }
+
+// GetForStorageTiering returns, ordered by Id, FileInfos still held in the hot storage tier that
+// were created before createdBefore or are at least minSize bytes large. A zero value disables
+// the respective condition.
+func (fs SqlFileInfoStore) GetForStorageTiering(createdBefore, minSize int64, afterId string, limit int) ([]*model.FileInfo, error) {
+	conditions := sq.Or{}
+	if createdBefore > 0 {
+		conditions = append(conditions, sq.Lt{"FileInfo.CreateAt": createdBefore})
+	}
+	if minSize > 0 {
+		conditions = append(conditions, sq.GtOrEq{"FileInfo.Size": minSize})
+	}
+	if len(conditions) == 0 {
+		return []*model.FileInfo{}, nil
+	}
+
+	query := fs.getQueryBuilder().
+		Select(fs.queryFields...).
+		From("FileInfo").
+		Where(sq.Eq{"FileInfo.StorageTier": model.FileStorageTierHot}).
+		Where(sq.Gt{"FileInfo.Id": afterId}).
+		Where(conditions).
+		OrderBy("FileInfo.Id ASC").
+		Limit(uint64(limit))
+
+	items := []fileInfoWithChannelID{}
+	if err := fs.GetReplica().SelectBuilder(&items, query); err != nil {
+		return nil, errors.Wrap(err, "failed to find FileInfos for storage tiering")
+	}
+
+	infos := make([]*model.FileInfo, 0, len(items))
+	for _, item := range items {
+		infos = append(infos, item.ToModel())
+	}
+	return infos, nil
+}
+
+func (fs SqlFileInfoStore) UpdateStorageTier(fileId, tier string) error {
+	query := fs.getQueryBuilder().
+		Update("FileInfo").
+		Set("StorageTier", tier).
+		Where(sq.Eq{"Id": fileId})
+
+	if _, err := fs.GetMaster().ExecBuilder(query); err != nil {
+		return errors.Wrapf(err, "failed to update the storage tier of FileInfo with id=%s", fileId)
+	}
+	return nil
+}
+
+func (fs SqlFileInfoStore) GetStorageTierUsage() ([]*model.FileStorageTierUsage, error) {
+	query := fs.getQueryBuilder().
+		Select("StorageTier AS Tier", "COUNT(*) AS FileCount", "COALESCE(SUM(Size), 0) AS TotalSize").
+		From("FileInfo").
+		GroupBy("StorageTier").
+		OrderBy("StorageTier")
+
+	usage := []*model.FileStorageTierUsage{}
+	if err := fs.GetReplica().SelectBuilder(&usage, query); err != nil {
+		return nil, errors.Wrap(err, "failed to get storage tier usage")
+	}
+	return usage, nil
+}
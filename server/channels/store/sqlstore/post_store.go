@@ -2714,20 +2714,35 @@ func (s *SqlPostStore) GetMaxPostSize() int {
 }
 
 func (s *SqlPostStore) GetParentsForExportAfter(limit int, afterId string, includeArchivedChannel bool) ([]*model.PostForExport, error) {
+	return s.getParentsForExport(limit, afterId, 0, includeArchivedChannel)
+}
+
+// GetParentsForExportSince returns the root posts after afterId that were created, updated or deleted after since,
+// including the deleted ones. As a new reply updates its root post, these are the threads that changed.
+func (s *SqlPostStore) GetParentsForExportSince(limit int, afterId string, since int64, includeArchivedChannel bool) ([]*model.PostForExport, error) {
+	return s.getParentsForExport(limit, afterId, since, includeArchivedChannel)
+}
+
+func (s *SqlPostStore) getParentsForExport(limit int, afterId string, since int64, includeArchivedChannel bool) ([]*model.PostForExport, error) {
+	changedCond := sq.Sqlizer(sq.Eq{"Posts.DeleteAt": 0})
+	if since > 0 {
+		// The previous versions of the edited posts are deleted copies, they are not tombstones.
+		changedCond = sq.And{sq.Gt{"Posts.UpdateAt": since}, sq.Eq{"Posts.OriginalId": ""}}
+	}
+
 	for {
 		rootIds := []string{}
-		err := s.GetReplica().Select(&rootIds,
-			`SELECT
-				Id
-			FROM
-				Posts
-			WHERE
-				Posts.Id > ?
-				AND Posts.RootId = ''
-				AND Posts.DeleteAt = 0
-			ORDER BY Posts.Id
-			LIMIT ?`,
-			afterId, limit)
+		rootsQuery := s.getQueryBuilder().
+			Select("Id").
+			From("Posts").
+			Where(sq.And{
+				sq.Gt{"Posts.Id": afterId},
+				sq.Eq{"Posts.RootId": ""},
+				changedCond,
+			}).
+			OrderBy("Posts.Id").
+			Limit(uint64(limit))
+		err := s.GetReplica().SelectBuilder(&rootIds, rootsQuery)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to find Posts")
 		}
@@ -2784,6 +2799,24 @@ func (s *SqlPostStore) GetParentsForExportAfter(limit int, afterId string, inclu
 }
 
 func (s *SqlPostStore) GetRepliesForExport(rootId string) ([]*model.ReplyForExport, error) {
+	return s.getRepliesForExport(rootId, 0)
+}
+
+// GetRepliesForExportSince returns the replies of the root post, including the ones deleted after since, but not the
+// previous versions of the edited replies.
+func (s *SqlPostStore) GetRepliesForExportSince(rootId string, since int64) ([]*model.ReplyForExport, error) {
+	return s.getRepliesForExport(rootId, since)
+}
+
+func (s *SqlPostStore) getRepliesForExport(rootId string, since int64) ([]*model.ReplyForExport, error) {
+	deletedCond := sq.Sqlizer(sq.Eq{"Posts.DeleteAt": 0})
+	if since > 0 {
+		deletedCond = sq.Or{
+			sq.Eq{"Posts.DeleteAt": 0},
+			sq.And{sq.Gt{"Posts.DeleteAt": since}, sq.Eq{"Posts.OriginalId": ""}},
+		}
+	}
+
 	aggFn := "COALESCE(json_agg(u1.username) FILTER (WHERE u1.username IS NOT NULL), '[]')"
 	if s.DriverName() == model.DatabaseDriverMysql {
 		aggFn = "IF (COUNT(u1.Username) = 0, JSON_ARRAY(), JSON_ARRAYAGG(u1.Username))"
@@ -2795,7 +2828,7 @@ func (s *SqlPostStore) GetRepliesForExport(rootId string) ([]*model.ReplyForExpo
 		LeftJoin("Preferences ON Posts.Id = Preferences.Name").
 		LeftJoin("Users u1 ON Preferences.UserId = u1.Id").
 		InnerJoin("Users u2 ON Posts.UserId = u2.Id").
-		Where(sq.And{sq.Eq{"Posts.RootId": rootId}, sq.Eq{"Posts.DeleteAt": 0}}).
+		Where(sq.And{sq.Eq{"Posts.RootId": rootId}, deletedCond}).
 		GroupBy("Posts.Id, u2.Username").
 		OrderBy("Posts.Id")
 
@@ -2813,6 +2846,21 @@ func (s *SqlPostStore) GetRepliesForExport(rootId string) ([]*model.ReplyForExpo
 }
 
 func (s *SqlPostStore) GetDirectPostParentsForExportAfter(limit int, afterId string, includeArchivedChannels bool) ([]*model.DirectPostForExport, error) {
+	return s.getDirectPostParentsForExport(limit, afterId, 0, includeArchivedChannels)
+}
+
+// GetDirectPostParentsForExportSince returns the root posts of the direct and group channels after afterId that were
+// created, updated or deleted after since, including the deleted ones.
+func (s *SqlPostStore) GetDirectPostParentsForExportSince(limit int, afterId string, since int64, includeArchivedChannels bool) ([]*model.DirectPostForExport, error) {
+	return s.getDirectPostParentsForExport(limit, afterId, since, includeArchivedChannels)
+}
+
+func (s *SqlPostStore) getDirectPostParentsForExport(limit int, afterId string, since int64, includeArchivedChannels bool) ([]*model.DirectPostForExport, error) {
+	changedCond := sq.Sqlizer(sq.Eq{"p.DeleteAt": 0})
+	if since > 0 {
+		changedCond = sq.And{sq.Gt{"p.UpdateAt": since}, sq.Eq{"p.OriginalId": ""}}
+	}
+
 	aggFn := "COALESCE(json_agg(u1.username) FILTER (WHERE u1.username IS NOT NULL), '[]')"
 	if s.DriverName() == model.DatabaseDriverMysql {
 		aggFn = "IF (COUNT(u1.Username) = 0, JSON_ARRAY(), JSON_ARRAYAGG(u1.Username))"
@@ -2829,7 +2877,7 @@ func (s *SqlPostStore) GetDirectPostParentsForExportAfter(limit int, afterId str
 		Where(sq.And{
 			sq.Gt{"p.Id": afterId},
 			sq.Eq{"p.RootId": ""},
-			sq.Eq{"p.DeleteAt": 0},
+			changedCond,
 			sq.Eq{"Channels.Type": []model.ChannelType{model.ChannelTypeDirect, model.ChannelTypeGroup}},
 		}).
 		GroupBy("p.Id, u2.Username").
@@ -146,6 +146,26 @@ func (s *SqlReactionStore) GetForPostSince(postId string, since int64, excludeRe
 	return reactions, nil
 }
 
+func (s *SqlReactionStore) GetForPostsSince(postIds []string, since int64) ([]*model.Reaction, error) {
+	reactions := []*model.Reaction{}
+	if len(postIds) == 0 {
+		return reactions, nil
+	}
+
+	query := s.getQueryBuilder().
+		Select("UserId", "PostId", "EmojiName", "CreateAt", "COALESCE(UpdateAt, CreateAt) As UpdateAt",
+			"COALESCE(DeleteAt, 0) As DeleteAt", "RemoteId", "ChannelId").
+		From("Reactions").
+		Where(sq.Eq{"PostId": postIds}).
+		Where(sq.Gt{"UpdateAt": since}).
+		OrderBy("PostId", "CreateAt")
+
+	if err := s.GetReplica().SelectBuilder(&reactions, query); err != nil {
+		return nil, errors.Wrap(err, "failed to find reactions")
+	}
+	return reactions, nil
+}
+
 func (s *SqlReactionStore) GetUniqueCountForPost(postId string) (int, error) {
 	query := s.getQueryBuilder().
 		Select("COUNT(DISTINCT EmojiName)").
@@ -0,0 +1,190 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/mattermost/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+type SqlSavedSearchStore struct {
+	*SqlStore
+}
+
+func newSqlSavedSearchStore(sqlStore *SqlStore) store.SavedSearchStore {
+	return &SqlSavedSearchStore{
+		SqlStore: sqlStore,
+	}
+}
+
+func (s *SqlSavedSearchStore) savedSearchesQuery() sq.SelectBuilder {
+	return s.getQueryBuilder().
+		Select(
+			"Id",
+			"UserId",
+			"TeamId",
+			"Name",
+			"Terms",
+			"IsOrSearch",
+			"TimeZoneOffset",
+			"EnableAlerts",
+			"LastRunAt",
+			"CreateAt",
+			"UpdateAt",
+		).
+		From("SavedSearches")
+}
+
+func (s *SqlSavedSearchStore) Save(savedSearch *model.SavedSearch) (*model.SavedSearch, error) {
+	if savedSearch.Id != "" {
+		return nil, store.NewErrInvalidInput("SavedSearch", "id", savedSearch.Id)
+	}
+
+	savedSearch.PreSave()
+	if err := savedSearch.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder().
+		Insert("SavedSearches").
+		Columns("Id", "UserId", "TeamId", "Name", "Terms", "IsOrSearch", "TimeZoneOffset", "EnableAlerts", "LastRunAt", "CreateAt", "UpdateAt").
+		Values(
+			savedSearch.Id,
+			savedSearch.UserId,
+			savedSearch.TeamId,
+			savedSearch.Name,
+			savedSearch.Terms,
+			savedSearch.IsOrSearch,
+			savedSearch.TimeZoneOffset,
+			savedSearch.EnableAlerts,
+			savedSearch.LastRunAt,
+			savedSearch.CreateAt,
+			savedSearch.UpdateAt,
+		)
+	if _, err := s.GetMaster().ExecBuilder(query); err != nil {
+		return nil, errors.Wrapf(err, "failed to save SavedSearch with id=%s", savedSearch.Id)
+	}
+
+	return savedSearch, nil
+}
+
+func (s *SqlSavedSearchStore) Get(id string) (*model.SavedSearch, error) {
+	var savedSearch model.SavedSearch
+	if err := s.GetReplica().GetBuilder(&savedSearch, s.savedSearchesQuery().Where(sq.Eq{"Id": id})); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("SavedSearch", id)
+		}
+		return nil, errors.Wrapf(err, "failed to get SavedSearch with id=%s", id)
+	}
+	return &savedSearch, nil
+}
+
+func (s *SqlSavedSearchStore) GetForUser(userId string) ([]*model.SavedSearch, error) {
+	savedSearches := []*model.SavedSearch{}
+	query := s.savedSearchesQuery().
+		Where(sq.Eq{"UserId": userId}).
+		OrderBy("CreateAt", "Id")
+	if err := s.GetReplica().SelectBuilder(&savedSearches, query); err != nil {
+		return nil, errors.Wrapf(err, "failed to find SavedSearches with userId=%s", userId)
+	}
+	return savedSearches, nil
+}
+
+func (s *SqlSavedSearchStore) CountForUser(userId string) (int64, error) {
+	var count int64
+	query := s.getQueryBuilder().
+		Select("COUNT(*)").
+		From("SavedSearches").
+		Where(sq.Eq{"UserId": userId})
+	if err := s.GetMaster().GetBuilder(&count, query); err != nil {
+		return 0, errors.Wrapf(err, "failed to count SavedSearches with userId=%s", userId)
+	}
+	return count, nil
+}
+
+func (s *SqlSavedSearchStore) Update(savedSearch *model.SavedSearch) (*model.SavedSearch, error) {
+	savedSearch.PreUpdate()
+	if err := savedSearch.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder().
+		Update("SavedSearches").
+		Set("Name", savedSearch.Name).
+		Set("Terms", savedSearch.Terms).
+		Set("IsOrSearch", savedSearch.IsOrSearch).
+		Set("TimeZoneOffset", savedSearch.TimeZoneOffset).
+		Set("EnableAlerts", savedSearch.EnableAlerts).
+		Set("LastRunAt", savedSearch.LastRunAt).
+		Set("UpdateAt", savedSearch.UpdateAt).
+		Where(sq.Eq{"Id": savedSearch.Id})
+	result, err := s.GetMaster().ExecBuilder(query)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update SavedSearch with id=%s", savedSearch.Id)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return nil, errors.Wrap(err, "failed to get affected rows")
+	} else if rows == 0 {
+		return nil, store.NewErrNotFound("SavedSearch", savedSearch.Id)
+	}
+
+	return savedSearch, nil
+}
+
+func (s *SqlSavedSearchStore) Delete(id string) error {
+	query := s.getQueryBuilder().
+		Delete("SavedSearches").
+		Where(sq.Eq{"Id": id})
+	result, err := s.GetMaster().ExecBuilder(query)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete SavedSearch with id=%s", id)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return errors.Wrap(err, "failed to get affected rows")
+	} else if rows == 0 {
+		return store.NewErrNotFound("SavedSearch", id)
+	}
+	return nil
+}
+
+func (s *SqlSavedSearchStore) GetWithAlertsEnabled(afterId string, limit int) ([]*model.SavedSearch, error) {
+	savedSearches := []*model.SavedSearch{}
+	query := s.savedSearchesQuery().
+		Where(sq.And{
+			sq.Eq{"EnableAlerts": true},
+			sq.Gt{"Id": afterId},
+		}).
+		OrderBy("Id").
+		Limit(uint64(limit))
+	if err := s.GetReplica().SelectBuilder(&savedSearches, query); err != nil {
+		return nil, errors.Wrap(err, "failed to find SavedSearches with alerts enabled")
+	}
+	return savedSearches, nil
+}
+
+func (s *SqlSavedSearchStore) UpdateLastRunAt(id string, lastRunAt int64) error {
+	query := s.getQueryBuilder().
+		Update("SavedSearches").
+		Set("LastRunAt", lastRunAt).
+		Where(sq.Eq{"Id": id})
+	if _, err := s.GetMaster().ExecBuilder(query); err != nil {
+		return errors.Wrapf(err, "failed to update the last run of SavedSearch with id=%s", id)
+	}
+	return nil
+}
+
+func (s *SqlSavedSearchStore) PermanentDeleteByUser(userId string) error {
+	query := s.getQueryBuilder().
+		Delete("SavedSearches").
+		Where(sq.Eq{"UserId": userId})
+	if _, err := s.GetMaster().ExecBuilder(query); err != nil {
+		return errors.Wrapf(err, "failed to delete SavedSearches with userId=%s", userId)
+	}
+	return nil
+}
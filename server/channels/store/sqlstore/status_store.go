@@ -195,6 +195,20 @@ func (s SqlStatusStore) GetTotalActiveUsersCount() (int64, error) {
 	return count, nil
 }
 
+func (s SqlStatusStore) GetRecentlyActiveUserIds(limit int) ([]string, error) {
+	query := s.getQueryBuilder().
+		Select("UserId").
+		From("Status").
+		OrderBy("LastActivityAt DESC").
+		Limit(uint64(limit))
+
+	userIDs := []string{}
+	if err := s.GetReplica().SelectBuilder(&userIDs, query); err != nil {
+		return nil, errors.Wrap(err, "failed to get recently active users")
+	}
+	return userIDs, nil
+}
+
 func (s SqlStatusStore) UpdateLastActivityAt(userId string, lastActivityAt int64) error {
 	builder := s.getQueryBuilder().
 		Update("Status").
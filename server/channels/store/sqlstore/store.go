@@ -93,6 +93,7 @@ type SqlStoreStores struct {
 	emoji                      store.EmojiStore
 	status                     store.StatusStore
 	fileInfo                   store.FileInfoStore
+	fileBlob                   store.FileBlobStore
 	uploadSession              store.UploadSessionStore
 	reaction                   store.ReactionStore
 	job                        store.JobStore
@@ -118,6 +119,7 @@ type SqlStoreStores struct {
 	propertyGroup              store.PropertyGroupStore
 	propertyField              store.PropertyFieldStore
 	propertyValue              store.PropertyValueStore
+	savedSearch                store.SavedSearchStore
 }
 
 type SqlStore struct {
@@ -237,6 +239,7 @@ func New(settings model.SqlSettings, logger mlog.LoggerIFace, metrics einterface
 	store.stores.emoji = newSqlEmojiStore(store, metrics)
 	store.stores.status = newSqlStatusStore(store)
 	store.stores.fileInfo = newSqlFileInfoStore(store, metrics)
+	store.stores.fileBlob = newSqlFileBlobStore(store)
 	store.stores.uploadSession = newSqlUploadSessionStore(store)
 	store.stores.thread = newSqlThreadStore(store)
 	store.stores.job = newSqlJobStore(store)
@@ -263,6 +266,7 @@ func New(settings model.SqlSettings, logger mlog.LoggerIFace, metrics einterface
 	store.stores.propertyGroup = newPropertyGroupStore(store)
 	store.stores.propertyField = newPropertyFieldStore(store)
 	store.stores.propertyValue = newPropertyValueStore(store)
+	store.stores.savedSearch = newSqlSavedSearchStore(store)
 
 	store.stores.preference.(*SqlPreferenceStore).deleteUnusedFeatures()
 
@@ -978,6 +982,10 @@ func (ss *SqlStore) FileInfo() store.FileInfoStore {
 	return ss.stores.fileInfo
 }
 
+func (ss *SqlStore) FileBlob() store.FileBlobStore {
+	return ss.stores.fileBlob
+}
+
 func (ss *SqlStore) UploadSession() store.UploadSessionStore {
 	return ss.stores.uploadSession
 }
@@ -1078,6 +1086,10 @@ func (ss *SqlStore) PropertyValue() store.PropertyValueStore {
 	return ss.stores.propertyValue
 }
 
+func (ss *SqlStore) SavedSearch() store.SavedSearchStore {
+	return ss.stores.savedSearch
+}
+
 func (ss *SqlStore) DropAllTables() {
 	if ss.DriverName() == model.DatabaseDriverPostgres {
 		ss.masterX.Exec(`DO
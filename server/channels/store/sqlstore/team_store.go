@@ -1570,6 +1570,44 @@ func (s SqlTeamStore) GetTeamMembersForExport(userId string) ([]*model.TeamMembe
 	return members, nil
 }
 
+// GetTeamMembershipReport returns a page of the team memberships, including the memberships the users left, ordered
+// by team and user after the membership of options.FromUserId in options.FromTeamId.
+func (s SqlTeamStore) GetTeamMembershipReport(options *model.TeamMembershipReportOptions) ([]*model.TeamMembershipReport, error) {
+	query := s.getQueryBuilder().
+		Select("TeamMembers.TeamId", "Teams.Name AS TeamName", "TeamMembers.UserId", "Users.Username", "Users.Email",
+			"TeamMembers.ExplicitRoles",
+			"(TeamMembers.SchemeGuest IS NOT NULL AND TeamMembers.SchemeGuest) AS SchemeGuest",
+			"TeamMembers.SchemeUser", "TeamMembers.SchemeAdmin", "TeamMembers.CreateAt", "TeamMembers.DeleteAt",
+			"Users.DeleteAt AS UserDeleteAt").
+		From("TeamMembers").
+		Join("Teams ON TeamMembers.TeamId = Teams.Id").
+		Join("Users ON TeamMembers.UserId = Users.Id").
+		Where(sq.Expr("Users.Id NOT IN (SELECT UserId FROM Bots)")).
+		OrderBy("TeamMembers.TeamId", "TeamMembers.UserId")
+
+	if options.TeamId != "" {
+		query = query.Where(sq.Eq{"TeamMembers.TeamId": options.TeamId})
+	}
+	if options.FromTeamId != "" {
+		query = query.Where(sq.Or{
+			sq.Gt{"TeamMembers.TeamId": options.FromTeamId},
+			sq.And{
+				sq.Eq{"TeamMembers.TeamId": options.FromTeamId},
+				sq.Gt{"TeamMembers.UserId": options.FromUserId},
+			},
+		})
+	}
+	if options.PageSize > 0 {
+		query = query.Limit(uint64(options.PageSize))
+	}
+
+	members := []*model.TeamMembershipReport{}
+	if err := s.GetReplica().SelectBuilder(&members, query); err != nil {
+		return nil, errors.Wrap(err, "failed to find TeamMembers for the team membership report")
+	}
+	return members, nil
+}
+
 // UserBelongsToTeams returns true if the user denoted by userId is a member of the teams in the teamIds string array.
 func (s SqlTeamStore) UserBelongsToTeams(userId string, teamIds []string) (bool, error) {
 	idQuery := sq.Eq{
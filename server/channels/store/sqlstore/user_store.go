@@ -572,6 +572,28 @@ func (us SqlUserStore) GetAllAfter(limit int, afterId string) ([]*model.User, er
 	return users, nil
 }
 
+// GetAllForExportSince returns the users after afterId that were created or updated after since, or whose team or
+// channel memberships were. Leaving a channel deletes the membership, so the channels left are found in their history.
+func (us SqlUserStore) GetAllForExportSince(limit int, afterId string, since int64) ([]*model.User, error) {
+	query := us.usersQuery.
+		Where("Id > ?", afterId).
+		Where(sq.Or{
+			sq.Gt{"Users.UpdateAt": since},
+			sq.Expr("EXISTS (SELECT 1 FROM ChannelMembers WHERE ChannelMembers.UserId = Users.Id AND ChannelMembers.LastUpdateAt > ?)", since),
+			sq.Expr("EXISTS (SELECT 1 FROM ChannelMemberHistory WHERE ChannelMemberHistory.UserId = Users.Id AND ChannelMemberHistory.LeaveTime > ?)", since),
+			sq.Expr("EXISTS (SELECT 1 FROM TeamMembers WHERE TeamMembers.UserId = Users.Id AND (TeamMembers.CreateAt > ? OR TeamMembers.DeleteAt > ?))", since, since),
+		}).
+		OrderBy("Id ASC").
+		Limit(uint64(limit))
+
+	users := []*model.User{}
+	if err := us.GetReplica().SelectBuilder(&users, query); err != nil {
+		return nil, errors.Wrap(err, "failed to find Users")
+	}
+
+	return users, nil
+}
+
 func (us SqlUserStore) GetEtagForAllProfiles() string {
 	var updateAt int64
 	err := us.GetReplica().Get(&updateAt, "SELECT UpdateAt FROM Users ORDER BY UpdateAt DESC LIMIT 1")
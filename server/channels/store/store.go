@@ -47,6 +47,7 @@ type Store interface {
 	Emoji() EmojiStore
 	Status() StatusStore
 	FileInfo() FileInfoStore
+	FileBlob() FileBlobStore
 	UploadSession() UploadSessionStore
 	Reaction() ReactionStore
 	Role() RoleStore
@@ -95,6 +96,7 @@ type Store interface {
 	PropertyGroup() PropertyGroupStore
 	PropertyField() PropertyFieldStore
 	PropertyValue() PropertyValueStore
+	SavedSearch() SavedSearchStore
 }
 
 type RetentionPolicyStore interface {
@@ -165,6 +167,7 @@ type TeamStore interface {
 	AnalyticsGetTeamCountForScheme(schemeID string) (int64, error)
 	GetAllForExportAfter(limit int, afterID string) ([]*model.TeamForExport, error)
 	GetTeamMembersForExport(userID string) ([]*model.TeamMemberForExport, error)
+	GetTeamMembershipReport(options *model.TeamMembershipReportOptions) ([]*model.TeamMembershipReport, error)
 	UserBelongsToTeams(userID string, teamIds []string) (bool, error)
 	GetUserTeamIds(userID string, allowFromCache bool) ([]string, error)
 	InvalidateAllTeamIdsForUser(userID string)
@@ -249,6 +252,9 @@ type ChannelStore interface {
 	GetPinnedPostCount(channelID string, allowFromCache bool) (int64, error)
 	InvalidateGuestCount(channelID string)
 	GetGuestCount(channelID string, allowFromCache bool) (int64, error)
+	// GetRecentlyActiveChannelIds returns the ids of the channels not deleted with the most recent posts,
+	// most recent first.
+	GetRecentlyActiveChannelIds(limit int) ([]string, error)
 	GetPinnedPosts(channelID string) (*model.PostList, error)
 	RemoveMember(ctx request.CTX, channelID string, userID string) error
 	RemoveMembers(ctx request.CTX, channelID string, userIds []string) error
@@ -282,6 +288,7 @@ type ChannelStore interface {
 	ClearCaches()
 	ClearMembersForUserCache()
 	GetChannelsByScheme(schemeID string, offset int, limit int) (model.ChannelList, error)
+	GetChannelActivityReport(options *model.ChannelActivityReportOptions) ([]*model.ChannelActivityReport, error)
 	MigrateChannelMembers(fromChannelID string, fromUserID string) (map[string]string, error)
 	ResetAllChannelSchemes() error
 	ClearAllCustomRoleAssignments() error
@@ -403,8 +410,11 @@ type PostStore interface {
 	GetOldest() (*model.Post, error)
 	GetMaxPostSize() int
 	GetParentsForExportAfter(limit int, afterID string, includeArchivedChannels bool) ([]*model.PostForExport, error)
+	GetParentsForExportSince(limit int, afterID string, since int64, includeArchivedChannels bool) ([]*model.PostForExport, error)
 	GetRepliesForExport(parentID string) ([]*model.ReplyForExport, error)
+	GetRepliesForExportSince(parentID string, since int64) ([]*model.ReplyForExport, error)
 	GetDirectPostParentsForExportAfter(limit int, afterID string, includeArchivedChannels bool) ([]*model.DirectPostForExport, error)
+	GetDirectPostParentsForExportSince(limit int, afterID string, since int64, includeArchivedChannels bool) ([]*model.DirectPostForExport, error)
 	SearchPostsForUser(rctx request.CTX, paramsList []*model.SearchParams, userID, teamID string, page, perPage int) (*model.PostSearchResults, error)
 	GetOldestEntityCreationTime() (int64, error)
 	HasAutoResponsePostByUserSince(options model.GetPostsSinceOptions, userID string) (bool, error)
@@ -487,6 +497,7 @@ type UserStore interface {
 	ClearAllCustomRoleAssignments() error
 	InferSystemInstallDate() (int64, error)
 	GetAllAfter(limit int, afterID string) ([]*model.User, error)
+	GetAllForExportSince(limit int, afterID string, since int64) ([]*model.User, error)
 	GetUsersBatchForIndexing(startTime int64, startFileID string, limit int) ([]*model.UserForIndexing, error)
 	Count(options model.UserCountOptions) (int64, error)
 	GetTeamGroupUsers(teamID string) ([]*model.User, error)
@@ -707,6 +718,8 @@ type EmojiStore interface {
 	GetByName(c request.CTX, name string, allowFromCache bool) (*model.Emoji, error)
 	GetMultipleByName(c request.CTX, names []string) ([]*model.Emoji, error)
 	GetList(offset, limit int, sort string) ([]*model.Emoji, error)
+	// GetDeletedSince returns the emojis deleted after the given time.
+	GetDeletedSince(since int64) ([]*model.Emoji, error)
 	Delete(emoji *model.Emoji, timestamp int64) error
 	Search(name string, prefixOnly bool, limit int) ([]*model.Emoji, error)
 }
@@ -717,6 +730,8 @@ type StatusStore interface {
 	GetByIds(userIds []string) ([]*model.Status, error)
 	ResetAll() error
 	GetTotalActiveUsersCount() (int64, error)
+	// GetRecentlyActiveUserIds returns the ids of the users most recently active, most recent first.
+	GetRecentlyActiveUserIds(limit int) ([]string, error)
 	UpdateLastActivityAt(userID string, lastActivityAt int64) error
 	UpdateExpiredDNDStatuses() ([]*model.Status, error)
 }
@@ -750,6 +765,22 @@ type FileInfoStore interface {
 	GetUptoNSizeFileTime(n int64) (int64, error)
 	// RefreshFileStats recomputes the fileinfo materialized views.
 	RefreshFileStats() error
+	// GetForStorageTiering returns hot-tier FileInfos created before createdBefore or at least minSize bytes large.
+	GetForStorageTiering(createdBefore, minSize int64, afterID string, limit int) ([]*model.FileInfo, error)
+	UpdateStorageTier(fileID, tier string) error
+	GetStorageTierUsage() ([]*model.FileStorageTierUsage, error)
+}
+
+type FileBlobStore interface {
+	GetByPaths(paths []string) ([]*model.FileBlobRef, error)
+	GetByPathPrefix(prefix string) ([]*model.FileBlobRef, error)
+	Save(ref *model.FileBlobRef) (int64, error)
+	Delete(path string) (*model.FileBlobRef, int64, error)
+	GetRefCount(hash string) (int64, error)
+	// GetUnreferenced returns the hashes of the blobs without references since before, after afterHash.
+	GetUnreferenced(before int64, afterHash string, limit int) ([]string, error)
+	// DeleteUnreferenced calls remove and deletes the blob if it is still unreferenced since before.
+	DeleteUnreferenced(hash string, before int64, remove func() error) (bool, error)
 }
 
 type UploadSessionStore interface {
@@ -765,6 +796,8 @@ type ReactionStore interface {
 	Delete(reaction *model.Reaction) (*model.Reaction, error)
 	GetForPost(postID string, allowFromCache bool) ([]*model.Reaction, error)
 	GetForPostSince(postID string, since int64, excludeRemoteID string, inclDeleted bool) ([]*model.Reaction, error)
+	// GetForPostsSince returns the reactions of the posts updated after the given time, including the deleted ones.
+	GetForPostsSince(postIDs []string, since int64) ([]*model.Reaction, error)
 	GetUniqueCountForPost(postID string) (int, error)
 	ExistsOnPost(postID string, emojiName string) (bool, error)
 	DeleteAllWithEmojiName(emojiName string) error
@@ -1081,6 +1114,20 @@ type ScheduledPostStore interface {
 	PermanentDeleteByUser(userId string) error
 }
 
+type SavedSearchStore interface {
+	Save(savedSearch *model.SavedSearch) (*model.SavedSearch, error)
+	Get(id string) (*model.SavedSearch, error)
+	GetForUser(userId string) ([]*model.SavedSearch, error)
+	CountForUser(userId string) (int64, error)
+	Update(savedSearch *model.SavedSearch) (*model.SavedSearch, error)
+	Delete(id string) error
+	// GetWithAlertsEnabled returns the saved searches with alerts enabled,
+	// ordered by id, starting after the given id.
+	GetWithAlertsEnabled(afterId string, limit int) ([]*model.SavedSearch, error)
+	UpdateLastRunAt(id string, lastRunAt int64) error
+	PermanentDeleteByUser(userId string) error
+}
+
 type PropertyGroupStore interface {
 	Register(name string) (*model.PropertyGroup, error)
 	Get(name string) (*model.PropertyGroup, error)
@@ -118,6 +118,7 @@ func TestChannelStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore
 	t.Run("GetMemberCount", func(t *testing.T) { testGetMemberCount(t, rctx, ss) })
 	t.Run("GetMemberCountsByGroup", func(t *testing.T) { testGetMemberCountsByGroup(t, rctx, ss) })
 	t.Run("GetGuestCount", func(t *testing.T) { testGetGuestCount(t, rctx, ss) })
+	t.Run("GetRecentlyActiveChannelIds", func(t *testing.T) { testGetRecentlyActiveChannelIds(t, rctx, ss) })
 	t.Run("SearchMore", func(t *testing.T) { testChannelStoreSearchMore(t, rctx, ss) })
 	t.Run("SearchInTeam", func(t *testing.T) { testChannelStoreSearchInTeam(t, rctx, ss) })
 	t.Run("Autocomplete", func(t *testing.T) { testAutocomplete(t, rctx, ss, s) })
@@ -133,6 +134,7 @@ func TestChannelStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore
 	t.Run("GetPinnedPostCount", func(t *testing.T) { testChannelStoreGetPinnedPostCount(t, rctx, ss) })
 	t.Run("MaxChannelsPerTeam", func(t *testing.T) { testChannelStoreMaxChannelsPerTeam(t, rctx, ss) })
 	t.Run("GetChannelsByScheme", func(t *testing.T) { testChannelStoreGetChannelsByScheme(t, rctx, ss) })
+	t.Run("GetChannelActivityReport", func(t *testing.T) { testChannelStoreGetChannelActivityReport(t, rctx, ss) })
 	t.Run("MigrateChannelMembers", func(t *testing.T) { testChannelStoreMigrateChannelMembers(t, rctx, ss) })
 	t.Run("ResetAllChannelSchemes", func(t *testing.T) { testResetAllChannelSchemes(t, rctx, ss) })
 	t.Run("ClearAllCustomRoleAssignments", func(t *testing.T) { testChannelStoreClearAllCustomRoleAssignments(t, rctx, ss) })
@@ -586,6 +588,26 @@ func testChannelStoreGetMany(t *testing.T, rctx request.CTX, ss store.Store, s S
 	var nfErr *store.ErrNotFound
 	require.True(t, errors.As(err, &nfErr))
 
+	t.Run("from cache", func(t *testing.T) {
+		_, err := ss.Channel().Get(o1.Id, true)
+		require.NoError(t, err)
+
+		res, err := ss.Channel().GetMany([]string{o1.Id, o2.Id}, true)
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		assert.ElementsMatch(t, []string{o1.Id, o2.Id}, []string{res[0].Id, res[1].Id})
+
+		o1.DisplayName = "Renamed"
+		_, err = ss.Channel().Update(rctx, o1)
+		require.NoError(t, err)
+		ss.Channel().InvalidateChannel(o1.Id)
+
+		res, err = ss.Channel().GetMany([]string{o1.Id}, true)
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		assert.Equal(t, "Renamed", res[0].DisplayName)
+	})
+
 	// Manually truncate Channels table until testlib can handle cleanups
 	s.GetMaster().Exec("TRUNCATE Channels")
 }
@@ -5543,6 +5565,30 @@ func testGetMemberCountsByGroup(t *testing.T, rctx request.CTX, ss store.Store)
 	})
 }
 
+func testGetRecentlyActiveChannelIds(t *testing.T, rctx request.CTX, ss store.Store) {
+	// Later than the posts of the channels saved by the other tests.
+	now := model.GetMillis() + 1000*60*60
+	teamID := model.NewId()
+
+	var channels []*model.Channel
+	for i := 0; i < 3; i++ {
+		channel, err := ss.Channel().Save(rctx, &model.Channel{
+			TeamId:      teamID,
+			DisplayName: "Channel",
+			Name:        NewTestID(),
+			Type:        model.ChannelTypeOpen,
+			LastPostAt:  now + int64(i),
+		}, -1)
+		require.NoError(t, err)
+		channels = append(channels, channel)
+	}
+	require.NoError(t, ss.Channel().Delete(channels[2].Id, model.GetMillis()))
+
+	channelIDs, err := ss.Channel().GetRecentlyActiveChannelIds(2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{channels[1].Id, channels[0].Id}, channelIDs)
+}
+
 func testGetGuestCount(t *testing.T, rctx request.CTX, ss store.Store) {
 	teamID := model.NewId()
 
@@ -7247,6 +7293,74 @@ func testChannelStoreMaxChannelsPerTeam(t *testing.T, rctx request.CTX, ss store
 	assert.NoError(t, nErr)
 }
 
+func testChannelStoreGetChannelActivityReport(t *testing.T, rctx request.CTX, ss store.Store) {
+	team, err := ss.Team().Save(&model.Team{
+		DisplayName: "Name",
+		Name:        NewTestID(),
+		Email:       MakeEmail(),
+		Type:        model.TeamOpen,
+	})
+	require.NoError(t, err)
+
+	c1, err := ss.Channel().Save(rctx, &model.Channel{TeamId: team.Id, DisplayName: "Channel 1", Name: NewTestID(), Type: model.ChannelTypeOpen}, -1)
+	require.NoError(t, err)
+	c2, err := ss.Channel().Save(rctx, &model.Channel{TeamId: team.Id, DisplayName: "Channel 2", Name: NewTestID(), Type: model.ChannelTypePrivate}, -1)
+	require.NoError(t, err)
+
+	userID := model.NewId()
+	_, err = ss.Channel().SaveMember(rctx, &model.ChannelMember{ChannelId: c1.Id, UserId: userID, NotifyProps: model.GetDefaultChannelNotifyProps()})
+	require.NoError(t, err)
+	_, err = ss.Channel().SaveMember(rctx, &model.ChannelMember{ChannelId: c1.Id, UserId: model.NewId(), NotifyProps: model.GetDefaultChannelNotifyProps()})
+	require.NoError(t, err)
+
+	for _, createAt := range []int64{1000, 2000, 3000} {
+		_, err = ss.Post().Save(rctx, &model.Post{ChannelId: c1.Id, UserId: userID, Message: "message", CreateAt: createAt})
+		require.NoError(t, err)
+	}
+
+	report, err := ss.Channel().GetChannelActivityReport(&model.ChannelActivityReportOptions{TeamId: team.Id, PageSize: 100})
+	require.NoError(t, err)
+	require.Len(t, report, 2)
+
+	activity := map[string]*model.ChannelActivityReport{}
+	for _, channel := range report {
+		assert.Equal(t, team.Name, channel.TeamName)
+		activity[channel.ChannelId] = channel
+	}
+	assert.Equal(t, c1.Name, activity[c1.Id].ChannelName)
+	assert.Equal(t, model.ChannelTypeOpen, activity[c1.Id].Type)
+	assert.Equal(t, int64(2), activity[c1.Id].MemberCount)
+	assert.Equal(t, int64(3), activity[c1.Id].PostCount)
+	assert.Equal(t, "Channel 2", activity[c2.Id].DisplayName)
+	assert.Equal(t, int64(0), activity[c2.Id].MemberCount)
+	assert.Equal(t, int64(0), activity[c2.Id].PostCount)
+
+	t.Run("date range", func(t *testing.T) {
+		report, err := ss.Channel().GetChannelActivityReport(&model.ChannelActivityReportOptions{TeamId: team.Id, PageSize: 100, StartAt: 1500, EndAt: 3000})
+		require.NoError(t, err)
+		for _, channel := range report {
+			if channel.ChannelId == c1.Id {
+				assert.Equal(t, int64(1), channel.PostCount)
+			}
+		}
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		first, err := ss.Channel().GetChannelActivityReport(&model.ChannelActivityReportOptions{TeamId: team.Id, PageSize: 1})
+		require.NoError(t, err)
+		require.Len(t, first, 1)
+
+		second, err := ss.Channel().GetChannelActivityReport(&model.ChannelActivityReportOptions{TeamId: team.Id, PageSize: 1, FromChannelId: first[0].ChannelId})
+		require.NoError(t, err)
+		require.Len(t, second, 1)
+		assert.Greater(t, second[0].ChannelId, first[0].ChannelId)
+
+		last, err := ss.Channel().GetChannelActivityReport(&model.ChannelActivityReportOptions{TeamId: team.Id, PageSize: 1, FromChannelId: second[0].ChannelId})
+		require.NoError(t, err)
+		assert.Empty(t, last)
+	})
+}
+
 func testChannelStoreGetChannelsByScheme(t *testing.T, rctx request.CTX, ss store.Store) {
 	// Create some schemes.
 	s1 := &model.Scheme{
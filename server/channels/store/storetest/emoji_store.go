@@ -21,6 +21,7 @@ func TestEmojiStore(t *testing.T, rctx request.CTX, ss store.Store) {
 	t.Run("EmojiGetByName", func(t *testing.T) { testEmojiGetByName(t, rctx, ss) })
 	t.Run("EmojiGetMultipleByName", func(t *testing.T) { testEmojiGetMultipleByName(t, rctx, ss) })
 	t.Run("EmojiGetList", func(t *testing.T) { testEmojiGetList(t, rctx, ss) })
+	t.Run("EmojiGetDeletedSince", func(t *testing.T) { testEmojiGetDeletedSince(t, rctx, ss) })
 	t.Run("EmojiSearch", func(t *testing.T) { testEmojiSearch(t, rctx, ss) })
 }
 
@@ -237,6 +238,36 @@ func testEmojiGetList(t *testing.T, rctx request.CTX, ss store.Store) {
 	assert.Equal(t, emojis[2].Name, remojis[1].Name)
 }
 
+func testEmojiGetDeletedSince(t *testing.T, rctx request.CTX, ss store.Store) {
+	var emojis []*model.Emoji
+	for i := 0; i < 3; i++ {
+		emoji, err := ss.Emoji().Save(&model.Emoji{
+			CreatorId: model.NewId(),
+			Name:      model.NewId(),
+		})
+		require.NoError(t, err)
+		emojis = append(emojis, emoji)
+	}
+
+	require.NoError(t, ss.Emoji().Delete(emojis[0], 1000))
+	require.NoError(t, ss.Emoji().Delete(emojis[1], 2000))
+	defer func() {
+		require.NoError(t, ss.Emoji().Delete(emojis[2], time.Now().Unix()))
+	}()
+
+	deleted, err := ss.Emoji().GetDeletedSince(1000)
+	require.NoError(t, err)
+
+	var ids []string
+	for _, emoji := range deleted {
+		ids = append(ids, emoji.Id)
+		assert.Greater(t, emoji.DeleteAt, int64(1000))
+	}
+	assert.NotContains(t, ids, emojis[0].Id)
+	assert.Contains(t, ids, emojis[1].Id)
+	assert.NotContains(t, ids, emojis[2].Id)
+}
+
 func testEmojiSearch(t *testing.T, rctx request.CTX, ss store.Store) {
 	emojis := []model.Emoji{
 		{
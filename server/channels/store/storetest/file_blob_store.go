@@ -0,0 +1,204 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestFileBlobStore(t *testing.T, rctx request.CTX, ss store.Store) {
+	t.Run("SaveAndGet", func(t *testing.T) { testFileBlobStoreSaveAndGet(t, rctx, ss) })
+	t.Run("RefCount", func(t *testing.T) { testFileBlobStoreRefCount(t, rctx, ss) })
+	t.Run("GetByPathPrefix", func(t *testing.T) { testFileBlobStoreGetByPathPrefix(t, rctx, ss) })
+	t.Run("DeleteUnreferenced", func(t *testing.T) { testFileBlobStoreDeleteUnreferenced(t, rctx, ss) })
+}
+
+func newTestBlobHash() string {
+	sum := sha256.Sum256([]byte(model.NewId()))
+	return hex.EncodeToString(sum[:])
+}
+
+func testFileBlobStoreSaveAndGet(t *testing.T, rctx request.CTX, ss store.Store) {
+	ref := &model.FileBlobRef{
+		Path: "blobtest/" + model.NewId() + "/file.txt",
+		Hash: newTestBlobHash(),
+		Size: 42,
+	}
+
+	refCount, err := ss.FileBlob().Save(ref)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), refCount)
+	assert.NotZero(t, ref.CreateAt)
+
+	refs, err := ss.FileBlob().GetByPaths([]string{ref.Path, "blobtest/missing"})
+	require.NoError(t, err)
+	require.Len(t, refs, 1)
+	assert.Equal(t, ref, refs[0])
+
+	t.Run("duplicate path", func(t *testing.T) {
+		_, err := ss.FileBlob().Save(&model.FileBlobRef{Path: ref.Path, Hash: newTestBlobHash()})
+		require.Error(t, err)
+		var cErr *store.ErrConflict
+		assert.ErrorAs(t, err, &cErr)
+	})
+
+	t.Run("invalid hash", func(t *testing.T) {
+		_, err := ss.FileBlob().Save(&model.FileBlobRef{Path: "blobtest/" + model.NewId(), Hash: "nothex"})
+		require.Error(t, err)
+	})
+
+	t.Run("empty paths", func(t *testing.T) {
+		refs, err := ss.FileBlob().GetByPaths(nil)
+		require.NoError(t, err)
+		assert.Empty(t, refs)
+	})
+}
+
+func testFileBlobStoreRefCount(t *testing.T, rctx request.CTX, ss store.Store) {
+	hash := newTestBlobHash()
+	dir := "blobtest/" + model.NewId()
+
+	refCount, err := ss.FileBlob().Save(&model.FileBlobRef{Path: dir + "/a", Hash: hash, Size: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), refCount)
+
+	refCount, err = ss.FileBlob().Save(&model.FileBlobRef{Path: dir + "/b", Hash: hash, Size: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), refCount)
+
+	refCount, err = ss.FileBlob().GetRefCount(hash)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), refCount)
+
+	ref, remaining, err := ss.FileBlob().Delete(dir + "/a")
+	require.NoError(t, err)
+	assert.Equal(t, hash, ref.Hash)
+	assert.Equal(t, int64(1), remaining)
+
+	ref, remaining, err = ss.FileBlob().Delete(dir + "/b")
+	require.NoError(t, err)
+	assert.Equal(t, hash, ref.Hash)
+	assert.Equal(t, int64(0), remaining)
+
+	refCount, err = ss.FileBlob().GetRefCount(hash)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), refCount)
+
+	_, _, err = ss.FileBlob().Delete(dir + "/b")
+	var nfErr *store.ErrNotFound
+	assert.ErrorAs(t, err, &nfErr)
+}
+
+func testFileBlobStoreGetByPathPrefix(t *testing.T, rctx request.CTX, ss store.Store) {
+	dir := "blobtest/" + model.NewId()
+	for _, p := range []string{dir + "/x/1", dir + "/x/2", dir + "/y", dir + "z"} {
+		_, err := ss.FileBlob().Save(&model.FileBlobRef{Path: p, Hash: newTestBlobHash()})
+		require.NoError(t, err)
+	}
+
+	refs, err := ss.FileBlob().GetByPathPrefix(dir + "/")
+	require.NoError(t, err)
+	paths := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		paths = append(paths, ref.Path)
+	}
+	assert.Equal(t, []string{dir + "/x/1", dir + "/x/2", dir + "/y"}, paths)
+
+	refs, err = ss.FileBlob().GetByPathPrefix(dir + "/x/")
+	require.NoError(t, err)
+	assert.Len(t, refs, 2)
+}
+
+func testFileBlobStoreDeleteUnreferenced(t *testing.T, rctx request.CTX, ss store.Store) {
+	dir := "blobtest/" + model.NewId()
+	unreferenced := newTestBlobHash()
+	referenced := newTestBlobHash()
+
+	_, err := ss.FileBlob().Save(&model.FileBlobRef{Path: dir + "/a", Hash: unreferenced})
+	require.NoError(t, err)
+	_, err = ss.FileBlob().Save(&model.FileBlobRef{Path: dir + "/b", Hash: referenced})
+	require.NoError(t, err)
+	_, _, err = ss.FileBlob().Delete(dir + "/a")
+	require.NoError(t, err)
+
+	t.Run("grace period", func(t *testing.T) {
+		hashes, err := ss.FileBlob().GetUnreferenced(model.GetMillis()-60*1000, "", 1000)
+		require.NoError(t, err)
+		assert.NotContains(t, hashes, unreferenced)
+
+		deleted, err := ss.FileBlob().DeleteUnreferenced(unreferenced, model.GetMillis()-60*1000, func() error {
+			require.Fail(t, "the blob should not be removed")
+			return nil
+		})
+		require.NoError(t, err)
+		assert.False(t, deleted)
+	})
+
+	before := model.GetMillis() + 1
+	hashes, err := ss.FileBlob().GetUnreferenced(before, "", 1000)
+	require.NoError(t, err)
+	assert.Contains(t, hashes, unreferenced)
+	assert.NotContains(t, hashes, referenced)
+
+	t.Run("referenced blob", func(t *testing.T) {
+		deleted, err := ss.FileBlob().DeleteUnreferenced(referenced, before, func() error {
+			require.Fail(t, "the blob should not be removed")
+			return nil
+		})
+		require.NoError(t, err)
+		assert.False(t, deleted)
+	})
+
+	t.Run("failure to remove the blob", func(t *testing.T) {
+		_, err := ss.FileBlob().DeleteUnreferenced(unreferenced, before, func() error {
+			return errors.New("failed")
+		})
+		require.Error(t, err)
+
+		hashes, err := ss.FileBlob().GetUnreferenced(before, "", 1000)
+		require.NoError(t, err)
+		assert.Contains(t, hashes, unreferenced)
+	})
+
+	removed := false
+	deleted, err := ss.FileBlob().DeleteUnreferenced(unreferenced, before, func() error {
+		removed = true
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	assert.True(t, removed)
+
+	hashes, err = ss.FileBlob().GetUnreferenced(before, "", 1000)
+	require.NoError(t, err)
+	assert.NotContains(t, hashes, unreferenced)
+
+	t.Run("new reference", func(t *testing.T) {
+		hash := newTestBlobHash()
+		_, err := ss.FileBlob().Save(&model.FileBlobRef{Path: dir + "/c", Hash: hash})
+		require.NoError(t, err)
+		_, _, err = ss.FileBlob().Delete(dir + "/c")
+		require.NoError(t, err)
+		refCount, err := ss.FileBlob().Save(&model.FileBlobRef{Path: dir + "/d", Hash: hash})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), refCount)
+
+		deleted, err := ss.FileBlob().DeleteUnreferenced(hash, model.GetMillis()+1, func() error {
+			require.Fail(t, "the blob should not be removed")
+			return nil
+		})
+		require.NoError(t, err)
+		assert.False(t, deleted)
+	})
+}
@@ -5,6 +5,7 @@ package storetest
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"testing"
 	"time"
@@ -41,6 +42,8 @@ func TestFileInfoStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStor
 	t.Run("FileInfoGetByIds", func(t *testing.T) { testGetByIds(t, rctx, ss) })
 	t.Run("FileInfoDeleteForPostByIds", func(t *testing.T) { testDeleteForPostByIds(t, rctx, ss) })
 	t.Run("FileInfoRestoreForPostByIds", func(t *testing.T) { testRestoreUndeleteForPostByIds(t, rctx, ss) })
+	t.Run("FileInfoGetForStorageTiering", func(t *testing.T) { testFileInfoGetForStorageTiering(t, rctx, ss) })
+	t.Run("FileInfoGetStorageTierUsage", func(t *testing.T) { testFileInfoGetStorageTierUsage(t, rctx, ss) })
 }
 
 func testFileInfoSaveGet(t *testing.T, rctx request.CTX, ss store.Store) {
@@ -1539,3 +1542,97 @@ func testRestoreUndeleteForPostByIds(t *testing.T, rctx request.CTX, ss store.St
 		}
 	})
 }
+
+func testFileInfoGetForStorageTiering(t *testing.T, rctx request.CTX, ss store.Store) {
+	now := model.GetMillis()
+	day := int64(24 * time.Hour / time.Millisecond)
+
+	var ids []string
+	save := func(createAt, size int64) *model.FileInfo {
+		info, err := ss.FileInfo().Save(rctx, &model.FileInfo{
+			CreatorId: model.NewId(),
+			Path:      "file.txt",
+			CreateAt:  createAt,
+			Size:      size,
+		})
+		require.NoError(t, err)
+		require.Equal(t, model.FileStorageTierHot, info.StorageTier)
+		ids = append(ids, info.Id)
+		return info
+	}
+	oldSmall := save(now-10*day, 10)
+	newLarge := save(now, 1000)
+	newSmall := save(now, 10)
+	oldMoved := save(now-10*day, 10)
+	defer func() {
+		for _, id := range ids {
+			ss.FileInfo().PermanentDelete(rctx, id)
+		}
+	}()
+
+	require.NoError(t, ss.FileInfo().UpdateStorageTier(oldMoved.Id, model.FileStorageTierCold))
+	info, err := ss.FileInfo().Get(oldMoved.Id)
+	require.NoError(t, err)
+	assert.Equal(t, model.FileStorageTierCold, info.StorageTier)
+
+	getIds := func(createdBefore, minSize int64) []string {
+		var found []string
+		afterId := ""
+		for {
+			infos, err := ss.FileInfo().GetForStorageTiering(createdBefore, minSize, afterId, 2)
+			require.NoError(t, err)
+			if len(infos) == 0 {
+				return found
+			}
+			for _, info := range infos {
+				if slices.Contains(ids, info.Id) {
+					found = append(found, info.Id)
+				}
+			}
+			afterId = infos[len(infos)-1].Id
+		}
+	}
+
+	assert.ElementsMatch(t, []string{oldSmall.Id}, getIds(now-day, 0))
+	assert.ElementsMatch(t, []string{newLarge.Id}, getIds(0, 100))
+	assert.ElementsMatch(t, []string{oldSmall.Id, newLarge.Id}, getIds(now-day, 100))
+	assert.NotContains(t, getIds(now+day, 0), oldMoved.Id)
+	assert.Contains(t, getIds(now+day, 0), newSmall.Id)
+	assert.Empty(t, getIds(0, 0))
+}
+
+func testFileInfoGetStorageTierUsage(t *testing.T, rctx request.CTX, ss store.Store) {
+	tierUsage := func() map[string]model.FileStorageTierUsage {
+		usage, err := ss.FileInfo().GetStorageTierUsage()
+		require.NoError(t, err)
+		byTier := map[string]model.FileStorageTierUsage{}
+		for _, u := range usage {
+			byTier[u.Tier] = *u
+		}
+		return byTier
+	}
+	before := tierUsage()
+
+	var ids []string
+	for _, size := range []int64{10, 20, 30} {
+		info, err := ss.FileInfo().Save(rctx, &model.FileInfo{
+			CreatorId: model.NewId(),
+			Path:      "file.txt",
+			Size:      size,
+		})
+		require.NoError(t, err)
+		ids = append(ids, info.Id)
+	}
+	defer func() {
+		for _, id := range ids {
+			ss.FileInfo().PermanentDelete(rctx, id)
+		}
+	}()
+	require.NoError(t, ss.FileInfo().UpdateStorageTier(ids[2], model.FileStorageTierCold))
+
+	after := tierUsage()
+	assert.Equal(t, before[model.FileStorageTierHot].FileCount+2, after[model.FileStorageTierHot].FileCount)
+	assert.Equal(t, before[model.FileStorageTierHot].TotalSize+30, after[model.FileStorageTierHot].TotalSize)
+	assert.Equal(t, before[model.FileStorageTierCold].FileCount+1, after[model.FileStorageTierCold].FileCount)
+	assert.Equal(t, before[model.FileStorageTierCold].TotalSize+30, after[model.FileStorageTierCold].TotalSize)
+}
@@ -862,6 +862,36 @@ func (_m *ChannelStore) GetByNamesIncludeDeleted(teamID string, names []string,
 	return r0, r1
 }
 
+// GetChannelActivityReport provides a mock function with given fields: options
+func (_m *ChannelStore) GetChannelActivityReport(options *model.ChannelActivityReportOptions) ([]*model.ChannelActivityReport, error) {
+	ret := _m.Called(options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetChannelActivityReport")
+	}
+
+	var r0 []*model.ChannelActivityReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.ChannelActivityReportOptions) ([]*model.ChannelActivityReport, error)); ok {
+		return rf(options)
+	}
+	if rf, ok := ret.Get(0).(func(*model.ChannelActivityReportOptions) []*model.ChannelActivityReport); ok {
+		r0 = rf(options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.ChannelActivityReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.ChannelActivityReportOptions) error); ok {
+		r1 = rf(options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetChannelCounts provides a mock function with given fields: teamID, userID
 func (_m *ChannelStore) GetChannelCounts(teamID string, userID string) (*model.ChannelCounts, error) {
 	ret := _m.Called(teamID, userID)
@@ -1938,6 +1968,36 @@ func (_m *ChannelStore) GetPublicChannelsForTeam(teamID string, offset int, limi
 	return r0, r1
 }
 
+// GetRecentlyActiveChannelIds provides a mock function with given fields: limit
+func (_m *ChannelStore) GetRecentlyActiveChannelIds(limit int) ([]string, error) {
+	ret := _m.Called(limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecentlyActiveChannelIds")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]string, error)); ok {
+		return rf(limit)
+	}
+	if rf, ok := ret.Get(0).(func(int) []string); ok {
+		r0 = rf(limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSidebarCategories provides a mock function with given fields: userID, opts
 func (_m *ChannelStore) GetSidebarCategories(userID string, opts *store.SidebarCategorySearchOpts) (*model.OrderedSidebarCategories, error) {
 	ret := _m.Called(userID, opts)
@@ -262,6 +262,36 @@ func (_m *FileInfoStore) GetForPost(postID string, readFromMaster bool, includeD
 	return r0, r1
 }
 
+// GetForStorageTiering provides a mock function with given fields: createdBefore, minSize, afterID, limit
+func (_m *FileInfoStore) GetForStorageTiering(createdBefore int64, minSize int64, afterID string, limit int) ([]*model.FileInfo, error) {
+	ret := _m.Called(createdBefore, minSize, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForStorageTiering")
+	}
+
+	var r0 []*model.FileInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int64, string, int) ([]*model.FileInfo, error)); ok {
+		return rf(createdBefore, minSize, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64, string, int) []*model.FileInfo); ok {
+		r0 = rf(createdBefore, minSize, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.FileInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64, string, int) error); ok {
+		r1 = rf(createdBefore, minSize, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetForUser provides a mock function with given fields: userID
 func (_m *FileInfoStore) GetForUser(userID string) ([]*model.FileInfo, error) {
 	ret := _m.Called(userID)
@@ -322,6 +352,36 @@ func (_m *FileInfoStore) GetFromMaster(id string) (*model.FileInfo, error) {
 	return r0, r1
 }
 
+// GetStorageTierUsage provides a mock function with given fields:
+func (_m *FileInfoStore) GetStorageTierUsage() ([]*model.FileStorageTierUsage, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStorageTierUsage")
+	}
+
+	var r0 []*model.FileStorageTierUsage
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*model.FileStorageTierUsage, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*model.FileStorageTierUsage); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.FileStorageTierUsage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStorageUsage provides a mock function with given fields: allowFromCache, includeDeleted
 func (_m *FileInfoStore) GetStorageUsage(allowFromCache bool, includeDeleted bool) (int64, error) {
 	ret := _m.Called(allowFromCache, includeDeleted)
@@ -619,6 +679,24 @@ func (_m *FileInfoStore) SetContent(ctx request.CTX, fileID string, content stri
 	return r0
 }
 
+// UpdateStorageTier provides a mock function with given fields: fileID, tier
+func (_m *FileInfoStore) UpdateStorageTier(fileID string, tier string) error {
+	ret := _m.Called(fileID, tier)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStorageTier")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(fileID, tier)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Upsert provides a mock function with given fields: rctx, info
 func (_m *FileInfoStore) Upsert(rctx request.CTX, info *model.FileInfo) (*model.FileInfo, error) {
 	ret := _m.Called(rctx, info)
@@ -219,6 +219,36 @@ func (_m *PostStore) GetDirectPostParentsForExportAfter(limit int, afterID strin
 	return r0, r1
 }
 
+// GetDirectPostParentsForExportSince provides a mock function with given fields: limit, afterID, since, includeArchivedChannels
+func (_m *PostStore) GetDirectPostParentsForExportSince(limit int, afterID string, since int64, includeArchivedChannels bool) ([]*model.DirectPostForExport, error) {
+	ret := _m.Called(limit, afterID, since, includeArchivedChannels)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDirectPostParentsForExportSince")
+	}
+
+	var r0 []*model.DirectPostForExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, string, int64, bool) ([]*model.DirectPostForExport, error)); ok {
+		return rf(limit, afterID, since, includeArchivedChannels)
+	}
+	if rf, ok := ret.Get(0).(func(int, string, int64, bool) []*model.DirectPostForExport); ok {
+		r0 = rf(limit, afterID, since, includeArchivedChannels)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.DirectPostForExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, string, int64, bool) error); ok {
+		r1 = rf(limit, afterID, since, includeArchivedChannels)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetEditHistoryForPost provides a mock function with given fields: postID
 func (_m *PostStore) GetEditHistoryForPost(postID string) ([]*model.Post, error) {
 	ret := _m.Called(postID)
@@ -491,6 +521,36 @@ func (_m *PostStore) GetParentsForExportAfter(limit int, afterID string, include
 	return r0, r1
 }
 
+// GetParentsForExportSince provides a mock function with given fields: limit, afterID, since, includeArchivedChannels
+func (_m *PostStore) GetParentsForExportSince(limit int, afterID string, since int64, includeArchivedChannels bool) ([]*model.PostForExport, error) {
+	ret := _m.Called(limit, afterID, since, includeArchivedChannels)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetParentsForExportSince")
+	}
+
+	var r0 []*model.PostForExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, string, int64, bool) ([]*model.PostForExport, error)); ok {
+		return rf(limit, afterID, since, includeArchivedChannels)
+	}
+	if rf, ok := ret.Get(0).(func(int, string, int64, bool) []*model.PostForExport); ok {
+		r0 = rf(limit, afterID, since, includeArchivedChannels)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.PostForExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, string, int64, bool) error); ok {
+		r1 = rf(limit, afterID, since, includeArchivedChannels)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPostAfterTime provides a mock function with given fields: channelID, timestamp, collapsedThreads
 func (_m *PostStore) GetPostAfterTime(channelID string, timestamp int64, collapsedThreads bool) (*model.Post, error) {
 	ret := _m.Called(channelID, timestamp, collapsedThreads)
@@ -944,6 +1004,36 @@ func (_m *PostStore) GetRepliesForExport(parentID string) ([]*model.ReplyForExpo
 	return r0, r1
 }
 
+// GetRepliesForExportSince provides a mock function with given fields: parentID, since
+func (_m *PostStore) GetRepliesForExportSince(parentID string, since int64) ([]*model.ReplyForExport, error) {
+	ret := _m.Called(parentID, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRepliesForExportSince")
+	}
+
+	var r0 []*model.ReplyForExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int64) ([]*model.ReplyForExport, error)); ok {
+		return rf(parentID, since)
+	}
+	if rf, ok := ret.Get(0).(func(string, int64) []*model.ReplyForExport); ok {
+		r0 = rf(parentID, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.ReplyForExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int64) error); ok {
+		r1 = rf(parentID, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSingle provides a mock function with given fields: rctx, id, inclDeleted
 func (_m *PostStore) GetSingle(rctx request.CTX, id string, inclDeleted bool) (*model.Post, error) {
 	ret := _m.Called(rctx, id, inclDeleted)
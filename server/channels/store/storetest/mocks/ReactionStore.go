@@ -208,6 +208,36 @@ func (_m *ReactionStore) GetForPostSince(postID string, since int64, excludeRemo
 	return r0, r1
 }
 
+// GetForPostsSince provides a mock function with given fields: postIDs, since
+func (_m *ReactionStore) GetForPostsSince(postIDs []string, since int64) ([]*model.Reaction, error) {
+	ret := _m.Called(postIDs, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForPostsSince")
+	}
+
+	var r0 []*model.Reaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]string, int64) ([]*model.Reaction, error)); ok {
+		return rf(postIDs, since)
+	}
+	if rf, ok := ret.Get(0).(func([]string, int64) []*model.Reaction); ok {
+		r0 = rf(postIDs, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Reaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]string, int64) error); ok {
+		r1 = rf(postIDs, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSingle provides a mock function with given fields: userID, postID, remoteID, emojiName
 func (_m *ReactionStore) GetSingle(userID string, postID string, remoteID string, emojiName string) (*model.Reaction, error) {
 	ret := _m.Called(userID, postID, remoteID, emojiName)
@@ -74,6 +74,36 @@ func (_m *StatusStore) GetByIds(userIds []string) ([]*model.Status, error) {
 	return r0, r1
 }
 
+// GetRecentlyActiveUserIds provides a mock function with given fields: limit
+func (_m *StatusStore) GetRecentlyActiveUserIds(limit int) ([]string, error) {
+	ret := _m.Called(limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecentlyActiveUserIds")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]string, error)); ok {
+		return rf(limit)
+	}
+	if rf, ok := ret.Get(0).(func(int) []string); ok {
+		r0 = rf(limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTotalActiveUsersCount provides a mock function with given fields:
 func (_m *StatusStore) GetTotalActiveUsersCount() (int64, error) {
 	ret := _m.Called()
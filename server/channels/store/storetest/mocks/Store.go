@@ -314,6 +314,26 @@ func (_m *Store) Emoji() store.EmojiStore {
 	return r0
 }
 
+// FileBlob provides a mock function with given fields:
+func (_m *Store) FileBlob() store.FileBlobStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for FileBlob")
+	}
+
+	var r0 store.FileBlobStore
+	if rf, ok := ret.Get(0).(func() store.FileBlobStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.FileBlobStore)
+		}
+	}
+
+	return r0
+}
+
 // FileInfo provides a mock function with given fields:
 func (_m *Store) FileInfo() store.FileInfoStore {
 	ret := _m.Called()
@@ -979,6 +999,26 @@ func (_m *Store) Role() store.RoleStore {
 	return r0
 }
 
+// SavedSearch provides a mock function with given fields:
+func (_m *Store) SavedSearch() store.SavedSearchStore {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for SavedSearch")
+	}
+
+	var r0 store.SavedSearchStore
+	if rf, ok := ret.Get(0).(func() store.SavedSearchStore); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(store.SavedSearchStore)
+		}
+	}
+
+	return r0
+}
+
 // ScheduledPost provides a mock function with given fields:
 func (_m *Store) ScheduledPost() store.ScheduledPostStore {
 	ret := _m.Called()
@@ -692,6 +692,36 @@ func (_m *TeamStore) GetTeamMembersForExport(userID string) ([]*model.TeamMember
 	return r0, r1
 }
 
+// GetTeamMembershipReport provides a mock function with given fields: options
+func (_m *TeamStore) GetTeamMembershipReport(options *model.TeamMembershipReportOptions) ([]*model.TeamMembershipReport, error) {
+	ret := _m.Called(options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTeamMembershipReport")
+	}
+
+	var r0 []*model.TeamMembershipReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.TeamMembershipReportOptions) ([]*model.TeamMembershipReport, error)); ok {
+		return rf(options)
+	}
+	if rf, ok := ret.Get(0).(func(*model.TeamMembershipReportOptions) []*model.TeamMembershipReport); ok {
+		r0 = rf(options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TeamMembershipReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.TeamMembershipReportOptions) error); ok {
+		r1 = rf(options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTeamsByScheme provides a mock function with given fields: schemeID, offset, limit
 func (_m *TeamStore) GetTeamsByScheme(schemeID string, offset int, limit int) ([]*model.Team, error) {
 	ret := _m.Called(schemeID, offset, limit)
@@ -419,6 +419,36 @@ func (_m *UserStore) GetAllAfter(limit int, afterID string) ([]*model.User, erro
 	return r0, r1
 }
 
+// GetAllForExportSince provides a mock function with given fields: limit, afterID, since
+func (_m *UserStore) GetAllForExportSince(limit int, afterID string, since int64) ([]*model.User, error) {
+	ret := _m.Called(limit, afterID, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllForExportSince")
+	}
+
+	var r0 []*model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, string, int64) ([]*model.User, error)); ok {
+		return rf(limit, afterID, since)
+	}
+	if rf, ok := ret.Get(0).(func(int, string, int64) []*model.User); ok {
+		r0 = rf(limit, afterID, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, string, int64) error); ok {
+		r1 = rf(limit, afterID, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAllNotInAuthService provides a mock function with given fields: authServices
 func (_m *UserStore) GetAllNotInAuthService(authServices []string) ([]*model.User, error) {
 	ret := _m.Called(authServices)
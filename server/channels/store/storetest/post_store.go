@@ -53,7 +53,9 @@ func TestPostStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
 	t.Run("GetOldest", func(t *testing.T) { testPostStoreGetOldest(t, rctx, ss) })
 	t.Run("TestGetMaxPostSize", func(t *testing.T) { testGetMaxPostSize(t, rctx, ss) })
 	t.Run("GetParentsForExportAfter", func(t *testing.T) { testPostStoreGetParentsForExportAfter(t, rctx, ss) })
+	t.Run("GetParentsForExportSince", func(t *testing.T) { testPostStoreGetParentsForExportSince(t, rctx, ss) })
 	t.Run("GetRepliesForExport", func(t *testing.T) { testPostStoreGetRepliesForExport(t, rctx, ss) })
+	t.Run("GetRepliesForExportSince", func(t *testing.T) { testPostStoreGetRepliesForExportSince(t, rctx, ss) })
 	t.Run("GetDirectPostParentsForExportAfter", func(t *testing.T) { testPostStoreGetDirectPostParentsForExportAfter(t, rctx, ss, s) })
 	t.Run("GetDirectPostParentsForExportAfterDeleted", func(t *testing.T) { testPostStoreGetDirectPostParentsForExportAfterDeleted(t, rctx, ss, s) })
 	t.Run("GetDirectPostParentsForExportAfterBatched", func(t *testing.T) { testPostStoreGetDirectPostParentsForExportAfterBatched(t, rctx, ss, s) })
@@ -4540,6 +4542,110 @@ func testPostStoreGetRepliesForExport(t *testing.T, rctx request.CTX, ss store.S
 	assert.Equal(t, reply1.Username, u1.Username)
 }
 
+func testPostStoreGetParentsForExportSince(t *testing.T, rctx request.CTX, ss store.Store) {
+	t1, err := ss.Team().Save(&model.Team{
+		DisplayName: "Name",
+		Name:        NewTestID(),
+		Email:       MakeEmail(),
+		Type:        model.TeamOpen,
+	})
+	require.NoError(t, err)
+
+	c1, err := ss.Channel().Save(rctx, &model.Channel{
+		TeamId:      t1.Id,
+		DisplayName: "Channel1",
+		Name:        NewTestID(),
+		Type:        model.ChannelTypeOpen,
+	}, -1)
+	require.NoError(t, err)
+
+	u1, err := ss.User().Save(rctx, &model.User{
+		Username: model.NewUsername(),
+		Email:    MakeEmail(),
+	})
+	require.NoError(t, err)
+
+	savePost := func(createAt int64, originalId string) *model.Post {
+		post, nErr := ss.Post().Save(rctx, &model.Post{
+			ChannelId:  c1.Id,
+			UserId:     u1.Id,
+			Message:    NewTestID(),
+			CreateAt:   createAt,
+			OriginalId: originalId,
+		})
+		require.NoError(t, nErr)
+		return post
+	}
+
+	unchanged := savePost(1000, "")
+	created := savePost(3000, "")
+	deleted := savePost(1000, "")
+	require.NoError(t, ss.Post().Delete(rctx, deleted.Id, 4000, u1.Id))
+	previousVersion := savePost(1000, unchanged.Id)
+	require.NoError(t, ss.Post().Delete(rctx, previousVersion.Id, 4000, u1.Id))
+
+	posts, err := ss.Post().GetParentsForExportSince(10000, strings.Repeat("0", 26), 2000, false)
+	require.NoError(t, err)
+
+	found := make(map[string]*model.PostForExport)
+	for _, p := range posts {
+		found[p.Id] = p
+	}
+	assert.NotContains(t, found, unchanged.Id)
+	assert.Contains(t, found, created.Id)
+	require.Contains(t, found, deleted.Id, "the deleted posts are returned as tombstones")
+	assert.Equal(t, int64(4000), found[deleted.Id].DeleteAt)
+	assert.NotContains(t, found, previousVersion.Id, "the previous versions of the edited posts are not returned")
+}
+
+func testPostStoreGetRepliesForExportSince(t *testing.T, rctx request.CTX, ss store.Store) {
+	c1, err := ss.Channel().Save(rctx, &model.Channel{
+		TeamId:      model.NewId(),
+		DisplayName: "Channel1",
+		Name:        NewTestID(),
+		Type:        model.ChannelTypeOpen,
+	}, -1)
+	require.NoError(t, err)
+
+	u1, err := ss.User().Save(rctx, &model.User{
+		Username: model.NewUsername(),
+		Email:    MakeEmail(),
+	})
+	require.NoError(t, err)
+
+	savePost := func(createAt int64, rootId string) *model.Post {
+		post, nErr := ss.Post().Save(rctx, &model.Post{
+			ChannelId: c1.Id,
+			UserId:    u1.Id,
+			Message:   NewTestID(),
+			CreateAt:  createAt,
+			RootId:    rootId,
+		})
+		require.NoError(t, nErr)
+		return post
+	}
+
+	root := savePost(1000, "")
+	reply := savePost(1001, root.Id)
+	deletedBefore := savePost(1002, root.Id)
+	require.NoError(t, ss.Post().Delete(rctx, deletedBefore.Id, 1500, u1.Id))
+	deletedAfter := savePost(1003, root.Id)
+	require.NoError(t, ss.Post().Delete(rctx, deletedAfter.Id, 4000, u1.Id))
+
+	replies, err := ss.Post().GetRepliesForExportSince(root.Id, 2000)
+	require.NoError(t, err)
+
+	found := make(map[string]*model.ReplyForExport)
+	for _, r := range replies {
+		found[r.Id] = r
+	}
+	require.Len(t, found, 2)
+	require.Contains(t, found, reply.Id)
+	assert.Equal(t, u1.Username, found[reply.Id].Username)
+	require.Contains(t, found, deletedAfter.Id)
+	assert.Equal(t, int64(4000), found[deletedAfter.Id].DeleteAt)
+}
+
 func testPostStoreGetDirectPostParentsForExportAfter(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
 	teamID := model.NewId()
 
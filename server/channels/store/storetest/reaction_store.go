@@ -24,6 +24,7 @@ func TestReactionStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStor
 	t.Run("ReactionDelete", func(t *testing.T) { testReactionDelete(t, rctx, ss) })
 	t.Run("ReactionGetForPost", func(t *testing.T) { testReactionGetForPost(t, rctx, ss) })
 	t.Run("ReactionGetForPostSince", func(t *testing.T) { testReactionGetForPostSince(t, rctx, ss, s) })
+	t.Run("ReactionGetForPostsSince", func(t *testing.T) { testReactionGetForPostsSince(t, rctx, ss) })
 	t.Run("ReactionDeleteAllWithEmojiName", func(t *testing.T) { testReactionDeleteAllWithEmojiName(t, rctx, ss, s) })
 	t.Run("PermanentDeleteByUser", func(t *testing.T) { testPermanentDeleteByUser(t, rctx, ss) })
 	t.Run("PermanentDeleteBatch", func(t *testing.T) { testReactionStorePermanentDeleteBatch(t, rctx, ss) })
@@ -307,6 +308,59 @@ func testReactionGetForPost(t *testing.T, rctx request.CTX, ss store.Store) {
 	}
 }
 
+func testReactionGetForPostsSince(t *testing.T, rctx request.CTX, ss store.Store) {
+	post1, err := ss.Post().Save(rctx, &model.Post{ChannelId: model.NewId(), UserId: model.NewId()})
+	require.NoError(t, err)
+	post2, err := ss.Post().Save(rctx, &model.Post{ChannelId: model.NewId(), UserId: model.NewId()})
+	require.NoError(t, err)
+	otherPost, err := ss.Post().Save(rctx, &model.Post{ChannelId: model.NewId(), UserId: model.NewId()})
+	require.NoError(t, err)
+
+	oldReaction, err := ss.Reaction().Save(&model.Reaction{UserId: model.NewId(), PostId: post1.Id, EmojiName: "smile"})
+	require.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+	since := model.GetMillis()
+	time.Sleep(5 * time.Millisecond)
+
+	added, err := ss.Reaction().Save(&model.Reaction{UserId: model.NewId(), PostId: post1.Id, EmojiName: "sad"})
+	require.NoError(t, err)
+	removed, err := ss.Reaction().Save(&model.Reaction{UserId: model.NewId(), PostId: post2.Id, EmojiName: "angry"})
+	require.NoError(t, err)
+	_, err = ss.Reaction().Delete(removed)
+	require.NoError(t, err)
+	_, err = ss.Reaction().Save(&model.Reaction{UserId: model.NewId(), PostId: otherPost.Id, EmojiName: "smile"})
+	require.NoError(t, err)
+
+	t.Run("reactions of the posts since", func(t *testing.T) {
+		reactions, err := ss.Reaction().GetForPostsSince([]string{post1.Id, post2.Id}, since)
+		require.NoError(t, err)
+		require.Len(t, reactions, 2)
+
+		byPost := map[string]*model.Reaction{}
+		for _, reaction := range reactions {
+			byPost[reaction.PostId] = reaction
+		}
+		require.Contains(t, byPost, post1.Id)
+		assert.Equal(t, added.EmojiName, byPost[post1.Id].EmojiName)
+		assert.Zero(t, byPost[post1.Id].DeleteAt)
+		require.Contains(t, byPost, post2.Id)
+		assert.NotZero(t, byPost[post2.Id].DeleteAt)
+	})
+
+	t.Run("all the reactions", func(t *testing.T) {
+		reactions, err := ss.Reaction().GetForPostsSince([]string{post1.Id}, oldReaction.UpdateAt-1)
+		require.NoError(t, err)
+		assert.Len(t, reactions, 2)
+	})
+
+	t.Run("no posts", func(t *testing.T) {
+		reactions, err := ss.Reaction().GetForPostsSince([]string{}, 0)
+		require.NoError(t, err)
+		assert.Empty(t, reactions)
+	})
+}
+
 func testReactionGetForPostSince(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
 	now := model.GetMillis()
 	later := now + 1800000 // add 30 minutes
@@ -0,0 +1,182 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+)
+
+func TestSavedSearchStore(t *testing.T, rctx request.CTX, ss store.Store) {
+	t.Run("SaveAndGet", func(t *testing.T) { testSavedSearchStoreSaveAndGet(t, rctx, ss) })
+	t.Run("Update", func(t *testing.T) { testSavedSearchStoreUpdate(t, rctx, ss) })
+	t.Run("Delete", func(t *testing.T) { testSavedSearchStoreDelete(t, rctx, ss) })
+	t.Run("GetWithAlertsEnabled", func(t *testing.T) { testSavedSearchStoreGetWithAlertsEnabled(t, rctx, ss) })
+}
+
+func newTestSavedSearch(userId string) *model.SavedSearch {
+	return &model.SavedSearch{
+		UserId: userId,
+		Name:   "Search " + model.NewId(),
+		Terms:  "hello world",
+	}
+}
+
+func testSavedSearchStoreSaveAndGet(t *testing.T, rctx request.CTX, ss store.Store) {
+	userId := model.NewId()
+
+	first, err := ss.SavedSearch().Save(newTestSavedSearch(userId))
+	require.NoError(t, err)
+	assert.NotEmpty(t, first.Id)
+	assert.Equal(t, first.CreateAt, first.LastRunAt)
+
+	second := newTestSavedSearch(userId)
+	second.TeamId = model.NewId()
+	second.IsOrSearch = true
+	second.EnableAlerts = true
+	second.CreateAt = first.CreateAt + 1
+	second, err = ss.SavedSearch().Save(second)
+	require.NoError(t, err)
+
+	_, err = ss.SavedSearch().Save(newTestSavedSearch(model.NewId()))
+	require.NoError(t, err)
+
+	t.Run("get", func(t *testing.T) {
+		savedSearch, err := ss.SavedSearch().Get(second.Id)
+		require.NoError(t, err)
+		assert.Equal(t, second, savedSearch)
+	})
+
+	t.Run("get missing", func(t *testing.T) {
+		_, err := ss.SavedSearch().Get(model.NewId())
+		var nfErr *store.ErrNotFound
+		assert.ErrorAs(t, err, &nfErr)
+	})
+
+	t.Run("get for user", func(t *testing.T) {
+		savedSearches, err := ss.SavedSearch().GetForUser(userId)
+		require.NoError(t, err)
+		require.Len(t, savedSearches, 2)
+		assert.Equal(t, first.Id, savedSearches[0].Id)
+		assert.Equal(t, second.Id, savedSearches[1].Id)
+
+		count, err := ss.SavedSearch().CountForUser(userId)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("save with id", func(t *testing.T) {
+		savedSearch := newTestSavedSearch(userId)
+		savedSearch.Id = model.NewId()
+		_, err := ss.SavedSearch().Save(savedSearch)
+		require.Error(t, err)
+	})
+
+	t.Run("save invalid", func(t *testing.T) {
+		savedSearch := newTestSavedSearch(userId)
+		savedSearch.Terms = ""
+		_, err := ss.SavedSearch().Save(savedSearch)
+		require.Error(t, err)
+	})
+
+	t.Run("permanent delete by user", func(t *testing.T) {
+		require.NoError(t, ss.SavedSearch().PermanentDeleteByUser(userId))
+
+		savedSearches, err := ss.SavedSearch().GetForUser(userId)
+		require.NoError(t, err)
+		assert.Empty(t, savedSearches)
+	})
+}
+
+func testSavedSearchStoreUpdate(t *testing.T, rctx request.CTX, ss store.Store) {
+	savedSearch, err := ss.SavedSearch().Save(newTestSavedSearch(model.NewId()))
+	require.NoError(t, err)
+
+	savedSearch.Name = "Renamed"
+	savedSearch.Terms = "from:someone"
+	savedSearch.EnableAlerts = true
+	_, err = ss.SavedSearch().Update(savedSearch)
+	require.NoError(t, err)
+
+	updated, err := ss.SavedSearch().Get(savedSearch.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", updated.Name)
+	assert.Equal(t, "from:someone", updated.Terms)
+	assert.True(t, updated.EnableAlerts)
+	assert.Equal(t, savedSearch.CreateAt, updated.CreateAt)
+
+	t.Run("update last run", func(t *testing.T) {
+		require.NoError(t, ss.SavedSearch().UpdateLastRunAt(savedSearch.Id, updated.LastRunAt+1000))
+
+		updated, err := ss.SavedSearch().Get(savedSearch.Id)
+		require.NoError(t, err)
+		assert.Equal(t, savedSearch.LastRunAt+1000, updated.LastRunAt)
+	})
+
+	t.Run("update missing", func(t *testing.T) {
+		missing := newTestSavedSearch(model.NewId())
+		missing.PreSave()
+		_, err := ss.SavedSearch().Update(missing)
+		var nfErr *store.ErrNotFound
+		assert.ErrorAs(t, err, &nfErr)
+	})
+}
+
+func testSavedSearchStoreDelete(t *testing.T, rctx request.CTX, ss store.Store) {
+	savedSearch, err := ss.SavedSearch().Save(newTestSavedSearch(model.NewId()))
+	require.NoError(t, err)
+
+	require.NoError(t, ss.SavedSearch().Delete(savedSearch.Id))
+
+	_, err = ss.SavedSearch().Get(savedSearch.Id)
+	var nfErr *store.ErrNotFound
+	assert.ErrorAs(t, err, &nfErr)
+
+	err = ss.SavedSearch().Delete(savedSearch.Id)
+	assert.ErrorAs(t, err, &nfErr)
+}
+
+func testSavedSearchStoreGetWithAlertsEnabled(t *testing.T, rctx request.CTX, ss store.Store) {
+	userId := model.NewId()
+	alertIds := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		savedSearch := newTestSavedSearch(userId)
+		savedSearch.EnableAlerts = true
+		savedSearch, err := ss.SavedSearch().Save(savedSearch)
+		require.NoError(t, err)
+		alertIds[savedSearch.Id] = true
+	}
+	disabled, err := ss.SavedSearch().Save(newTestSavedSearch(userId))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, ss.SavedSearch().PermanentDeleteByUser(userId))
+	}()
+
+	found := map[string]bool{}
+	afterId := ""
+	for {
+		savedSearches, err := ss.SavedSearch().GetWithAlertsEnabled(afterId, 2)
+		require.NoError(t, err)
+		for _, savedSearch := range savedSearches {
+			assert.True(t, savedSearch.EnableAlerts)
+			assert.Greater(t, savedSearch.Id, afterId)
+			found[savedSearch.Id] = true
+			afterId = savedSearch.Id
+		}
+		if len(savedSearches) < 2 {
+			break
+		}
+	}
+
+	for id := range alertIds {
+		assert.True(t, found[id])
+	}
+	assert.False(t, found[disabled.Id])
+}
@@ -19,6 +19,7 @@ import (
 func TestStatusStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
 	t.Run("", func(t *testing.T) { testStatusStore(t, rctx, ss) })
 	t.Run("ActiveUserCount", func(t *testing.T) { testActiveUserCount(t, rctx, ss) })
+	t.Run("GetRecentlyActiveUserIds", func(t *testing.T) { testGetRecentlyActiveUserIds(t, rctx, ss) })
 	t.Run("UpdateExpiredDNDStatuses", func(t *testing.T) { testUpdateExpiredDNDStatuses(t, rctx, ss) })
 	t.Run("Get", func(t *testing.T) { testStatusGet(t, rctx, ss, s) })
 	t.Run("GetByIds", func(t *testing.T) { testStatusGetByIds(t, rctx, ss, s) })
@@ -70,6 +71,23 @@ func testActiveUserCount(t *testing.T, rctx request.CTX, ss store.Store) {
 	assert.Equal(t, count1+1, count2)
 }
 
+func testGetRecentlyActiveUserIds(t *testing.T, _ request.CTX, ss store.Store) {
+	// Later than the activity of the statuses saved by the other tests.
+	now := model.GetMillis() + 1000*60*60
+	status1 := &model.Status{UserId: model.NewId(), Status: model.StatusOnline, LastActivityAt: now - 1000}
+	require.NoError(t, ss.Status().SaveOrUpdate(status1))
+	status2 := &model.Status{UserId: model.NewId(), Status: model.StatusAway, LastActivityAt: now}
+	require.NoError(t, ss.Status().SaveOrUpdate(status2))
+
+	userIDs, err := ss.Status().GetRecentlyActiveUserIds(2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{status2.UserId, status1.UserId}, userIDs)
+
+	userIDs, err = ss.Status().GetRecentlyActiveUserIds(1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{status2.UserId}, userIDs)
+}
+
 type ByUserId []*model.Status
 
 func (s ByUserId) Len() int           { return len(s) }
@@ -42,6 +42,7 @@ type Store struct {
 	ThreadStore                     mocks.ThreadStore
 	StatusStore                     mocks.StatusStore
 	FileInfoStore                   mocks.FileInfoStore
+	FileBlobStore                   mocks.FileBlobStore
 	UploadSessionStore              mocks.UploadSessionStore
 	ReactionStore                   mocks.ReactionStore
 	JobStore                        mocks.JobStore
@@ -69,6 +70,7 @@ type Store struct {
 	PropertyGroupStore              mocks.PropertyGroupStore
 	PropertyFieldStore              mocks.PropertyFieldStore
 	PropertyValueStore              mocks.PropertyValueStore
+	SavedSearchStore                mocks.SavedSearchStore
 }
 
 func (s *Store) SetContext(context context.Context)            { s.context = context }
@@ -101,6 +103,7 @@ func (s *Store) Emoji() store.EmojiStore                           { return &s.E
 func (s *Store) Thread() store.ThreadStore                         { return &s.ThreadStore }
 func (s *Store) Status() store.StatusStore                         { return &s.StatusStore }
 func (s *Store) FileInfo() store.FileInfoStore                     { return &s.FileInfoStore }
+func (s *Store) FileBlob() store.FileBlobStore                     { return &s.FileBlobStore }
 func (s *Store) UploadSession() store.UploadSessionStore           { return &s.UploadSessionStore }
 func (s *Store) Reaction() store.ReactionStore                     { return &s.ReactionStore }
 func (s *Store) Job() store.JobStore                               { return &s.JobStore }
@@ -125,6 +128,7 @@ func (s *Store) ScheduledPost() store.ScheduledPostStore     { return &s.Schedul
 func (s *Store) PropertyGroup() store.PropertyGroupStore     { return &s.PropertyGroupStore }
 func (s *Store) PropertyField() store.PropertyFieldStore     { return &s.PropertyFieldStore }
 func (s *Store) PropertyValue() store.PropertyValueStore     { return &s.PropertyValueStore }
+func (s *Store) SavedSearch() store.SavedSearchStore         { return &s.SavedSearchStore }
 func (s *Store) PostAcknowledgement() store.PostAcknowledgementStore {
 	return &s.PostAcknowledgementStore
 }
@@ -178,6 +182,7 @@ func (s *Store) AssertExpectations(t mock.TestingT) bool {
 		&s.EmojiStore,
 		&s.StatusStore,
 		&s.FileInfoStore,
+		&s.FileBlobStore,
 		&s.UploadSessionStore,
 		&s.ReactionStore,
 		&s.JobStore,
@@ -197,5 +202,6 @@ func (s *Store) AssertExpectations(t mock.TestingT) bool {
 		&s.DesktopTokensStore,
 		&s.ChannelBookmarkStore,
 		&s.ScheduledPostStore,
+		&s.SavedSearchStore,
 	)
 }
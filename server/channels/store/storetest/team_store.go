@@ -72,6 +72,7 @@ func TestTeamStore(t *testing.T, rctx request.CTX, ss store.Store) {
 	t.Run("AnalyticsGetTeamCountForScheme", func(t *testing.T) { testTeamStoreAnalyticsGetTeamCountForScheme(t, rctx, ss) })
 	t.Run("GetAllForExportAfter", func(t *testing.T) { testTeamStoreGetAllForExportAfter(t, rctx, ss) })
 	t.Run("GetTeamMembersForExport", func(t *testing.T) { testTeamStoreGetTeamMembersForExport(t, rctx, ss) })
+	t.Run("GetTeamMembershipReport", func(t *testing.T) { testTeamStoreGetTeamMembershipReport(t, rctx, ss) })
 	t.Run("GetTeamsForUserWithPagination", func(t *testing.T) { testTeamMembersWithPagination(t, rctx, ss) })
 	t.Run("GroupSyncedTeamCount", func(t *testing.T) { testGroupSyncedTeamCount(t, rctx, ss) })
 	t.Run("GetCommonTeamIDsForMultipleUsers", func(t *testing.T) { testGetCommonTeamIDsForMultipleUsers(t, rctx, ss) })
@@ -3583,6 +3584,57 @@ func testTeamStoreGetTeamMembersForExport(t *testing.T, rctx request.CTX, ss sto
 	assert.Equal(t, t1.Name, tmfe1.TeamName)
 }
 
+func testTeamStoreGetTeamMembershipReport(t *testing.T, rctx request.CTX, ss store.Store) {
+	team, err := ss.Team().Save(&model.Team{
+		DisplayName: "Name",
+		Name:        NewTestID(),
+		Email:       MakeEmail(),
+		Type:        model.TeamOpen,
+	})
+	require.NoError(t, err)
+
+	u1, err := ss.User().Save(rctx, &model.User{Email: MakeEmail(), Username: model.NewUsername()})
+	require.NoError(t, err)
+	u2, err := ss.User().Save(rctx, &model.User{Email: MakeEmail(), Username: model.NewUsername()})
+	require.NoError(t, err)
+
+	_, err = ss.Team().SaveMultipleMembers([]*model.TeamMember{
+		{TeamId: team.Id, UserId: u1.Id, SchemeUser: true, SchemeAdmin: true},
+		{TeamId: team.Id, UserId: u2.Id, SchemeGuest: true, DeleteAt: model.GetMillis()},
+	}, -1)
+	require.NoError(t, err)
+
+	report, err := ss.Team().GetTeamMembershipReport(&model.TeamMembershipReportOptions{TeamId: team.Id, PageSize: 100})
+	require.NoError(t, err)
+	require.Len(t, report, 2)
+
+	members := map[string]*model.TeamMembershipReport{}
+	for _, member := range report {
+		assert.Equal(t, team.Name, member.TeamName)
+		members[member.UserId] = member
+	}
+	assert.Equal(t, u1.Username, members[u1.Id].Username)
+	assert.Equal(t, "team_user team_admin", members[u1.Id].Roles())
+	assert.Zero(t, members[u1.Id].DeleteAt)
+	assert.Equal(t, "team_guest", members[u2.Id].Roles())
+	assert.NotZero(t, members[u2.Id].DeleteAt)
+
+	t.Run("pagination", func(t *testing.T) {
+		first, err := ss.Team().GetTeamMembershipReport(&model.TeamMembershipReportOptions{TeamId: team.Id, PageSize: 1})
+		require.NoError(t, err)
+		require.Len(t, first, 1)
+
+		second, err := ss.Team().GetTeamMembershipReport(&model.TeamMembershipReportOptions{TeamId: team.Id, PageSize: 1, FromTeamId: first[0].TeamId, FromUserId: first[0].UserId})
+		require.NoError(t, err)
+		require.Len(t, second, 1)
+		assert.Greater(t, second[0].UserId, first[0].UserId)
+
+		last, err := ss.Team().GetTeamMembershipReport(&model.TeamMembershipReportOptions{TeamId: team.Id, PageSize: 1, FromTeamId: second[0].TeamId, FromUserId: second[0].UserId})
+		require.NoError(t, err)
+		assert.Empty(t, last)
+	})
+}
+
 func testGroupSyncedTeamCount(t *testing.T, rctx request.CTX, ss store.Store) {
 	team1, err := ss.Team().Save(&model.Team{
 		DisplayName:      NewTestID(),
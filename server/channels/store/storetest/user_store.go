@@ -91,6 +91,7 @@ func TestUserStore(t *testing.T, rctx request.CTX, ss store.Store, s SqlStore) {
 	t.Run("GetProfilesNotInTeam", func(t *testing.T) { testUserStoreGetProfilesNotInTeam(t, rctx, ss) })
 	t.Run("ClearAllCustomRoleAssignments", func(t *testing.T) { testUserStoreClearAllCustomRoleAssignments(t, rctx, ss) })
 	t.Run("GetAllAfter", func(t *testing.T) { testUserStoreGetAllAfter(t, rctx, ss) })
+	t.Run("GetAllForExportSince", func(t *testing.T) { testUserStoreGetAllForExportSince(t, rctx, ss) })
 	t.Run("GetUsersBatchForIndexing", func(t *testing.T) { testUserStoreGetUsersBatchForIndexing(t, rctx, ss) })
 	t.Run("GetTeamGroupUsers", func(t *testing.T) { testUserStoreGetTeamGroupUsers(t, rctx, ss) })
 	t.Run("GetChannelGroupUsers", func(t *testing.T) { testUserStoreGetChannelGroupUsers(t, rctx, ss) })
@@ -4936,6 +4937,65 @@ func testUserStoreGetAllAfter(t *testing.T, rctx request.CTX, ss store.Store) {
 	})
 }
 
+func testUserStoreGetAllForExportSince(t *testing.T, rctx request.CTX, ss store.Store) {
+	var users []*model.User
+	for i := 0; i < 5; i++ {
+		u, err := ss.User().Save(rctx, &model.User{
+			Email:    MakeEmail(),
+			Username: model.NewUsername(),
+		})
+		require.NoError(t, err)
+		defer func() { require.NoError(t, ss.User().PermanentDelete(rctx, u.Id)) }()
+		users = append(users, u)
+	}
+
+	team, err := ss.Team().Save(&model.Team{
+		DisplayName: "Team",
+		Name:        NewTestID(),
+		Type:        model.TeamOpen,
+	})
+	require.NoError(t, err)
+	channel, err := ss.Channel().Save(rctx, &model.Channel{
+		TeamId: team.Id,
+		Name:   model.NewId(),
+		Type:   model.ChannelTypeOpen,
+	}, -1)
+	require.NoError(t, err)
+	require.NoError(t, ss.ChannelMemberHistory().LogJoinEvent(users[4].Id, channel.Id, model.GetMillis()))
+
+	time.Sleep(2 * time.Millisecond)
+	since := model.GetMillis()
+	time.Sleep(2 * time.Millisecond)
+
+	// users[0] is updated, users[1] joins a team and users[2] a channel, users[3] does not change and users[4] leaves
+	// a channel, which deletes the membership.
+	users[0].Nickname = "updated"
+	_, err = ss.User().Update(rctx, users[0], false)
+	require.NoError(t, err)
+	_, err = ss.Team().SaveMember(rctx, &model.TeamMember{TeamId: team.Id, UserId: users[1].Id}, -1)
+	require.NoError(t, err)
+	_, err = ss.Channel().SaveMember(rctx, &model.ChannelMember{
+		ChannelId:   channel.Id,
+		UserId:      users[2].Id,
+		NotifyProps: model.GetDefaultChannelNotifyProps(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, ss.ChannelMemberHistory().LogLeaveEvent(users[4].Id, channel.Id, model.GetMillis()))
+
+	actual, err := ss.User().GetAllForExportSince(10000, strings.Repeat("0", 26), since)
+	require.NoError(t, err)
+
+	var ids []string
+	for _, u := range actual {
+		ids = append(ids, u.Id)
+	}
+	assert.Contains(t, ids, users[0].Id)
+	assert.Contains(t, ids, users[1].Id)
+	assert.Contains(t, ids, users[2].Id)
+	assert.NotContains(t, ids, users[3].Id)
+	assert.Contains(t, ids, users[4].Id)
+}
+
 func testUserStoreGetUsersBatchForIndexing(t *testing.T, rctx request.CTX, ss store.Store) {
 	// Set up all the objects needed
 	t1, err := ss.Team().Save(&model.Team{
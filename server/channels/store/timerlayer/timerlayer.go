@@ -31,6 +31,7 @@ type TimerLayer struct {
 	DesktopTokensStore              store.DesktopTokensStore
 	DraftStore                      store.DraftStore
 	EmojiStore                      store.EmojiStore
+	FileBlobStore                   store.FileBlobStore
 	FileInfoStore                   store.FileInfoStore
 	GroupStore                      store.GroupStore
 	JobStore                        store.JobStore
@@ -53,6 +54,7 @@ type TimerLayer struct {
 	RemoteClusterStore              store.RemoteClusterStore
 	RetentionPolicyStore            store.RetentionPolicyStore
 	RoleStore                       store.RoleStore
+	SavedSearchStore                store.SavedSearchStore
 	ScheduledPostStore              store.ScheduledPostStore
 	SchemeStore                     store.SchemeStore
 	SessionStore                    store.SessionStore
@@ -118,6 +120,10 @@ func (s *TimerLayer) Emoji() store.EmojiStore {
 	return s.EmojiStore
 }
 
+func (s *TimerLayer) FileBlob() store.FileBlobStore {
+	return s.FileBlobStore
+}
+
 func (s *TimerLayer) FileInfo() store.FileInfoStore {
 	return s.FileInfoStore
 }
@@ -206,6 +212,10 @@ func (s *TimerLayer) Role() store.RoleStore {
 	return s.RoleStore
 }
 
+func (s *TimerLayer) SavedSearch() store.SavedSearchStore {
+	return s.SavedSearchStore
+}
+
 func (s *TimerLayer) ScheduledPost() store.ScheduledPostStore {
 	return s.ScheduledPostStore
 }
@@ -326,6 +336,11 @@ type TimerLayerEmojiStore struct {
 	Root *TimerLayer
 }
 
+type TimerLayerFileBlobStore struct {
+	store.FileBlobStore
+	Root *TimerLayer
+}
+
 type TimerLayerFileInfoStore struct {
 	store.FileInfoStore
 	Root *TimerLayer
@@ -436,6 +451,11 @@ type TimerLayerRoleStore struct {
 	Root *TimerLayer
 }
 
+type TimerLayerSavedSearchStore struct {
+	store.SavedSearchStore
+	Root *TimerLayer
+}
+
 type TimerLayerScheduledPostStore struct {
 	store.ScheduledPostStore
 	Root *TimerLayer
@@ -1181,6 +1201,22 @@ func (s *TimerLayerChannelStore) GetByNamesIncludeDeleted(teamID string, names [
 	return result, err
 }
 
+func (s *TimerLayerChannelStore) GetChannelActivityReport(options *model.ChannelActivityReportOptions) ([]*model.ChannelActivityReport, error) {
+	start := time.Now()
+
+	result, err := s.ChannelStore.GetChannelActivityReport(options)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("ChannelStore.GetChannelActivityReport", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerChannelStore) GetChannelCounts(teamID string, userID string) (*model.ChannelCounts, error) {
 	start := time.Now()
 
@@ -1757,6 +1793,22 @@ func (s *TimerLayerChannelStore) GetPublicChannelsForTeam(teamID string, offset
 	return result, err
 }
 
+func (s *TimerLayerChannelStore) GetRecentlyActiveChannelIds(limit int) ([]string, error) {
+	start := time.Now()
+
+	result, err := s.ChannelStore.GetRecentlyActiveChannelIds(limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("ChannelStore.GetRecentlyActiveChannelIds", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerChannelStore) GetSidebarCategories(userID string, opts *store.SidebarCategorySearchOpts) (*model.OrderedSidebarCategories, error) {
 	start := time.Now()
 
@@ -3509,6 +3561,22 @@ func (s *TimerLayerEmojiStore) GetByName(c request.CTX, name string, allowFromCa
 	return result, err
 }
 
+func (s *TimerLayerEmojiStore) GetDeletedSince(since int64) ([]*model.Emoji, error) {
+	start := time.Now()
+
+	result, err := s.EmojiStore.GetDeletedSince(since)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("EmojiStore.GetDeletedSince", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerEmojiStore) GetList(offset int, limit int, sort string) ([]*model.Emoji, error) {
 	start := time.Now()
 
@@ -3573,6 +3641,118 @@ func (s *TimerLayerEmojiStore) Search(name string, prefixOnly bool, limit int) (
 	return result, err
 }
 
+func (s *TimerLayerFileBlobStore) Delete(path string) (*model.FileBlobRef, int64, error) {
+	start := time.Now()
+
+	result, resultVar1, err := s.FileBlobStore.Delete(path)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileBlobStore.Delete", success, elapsed)
+	}
+	return result, resultVar1, err
+}
+
+func (s *TimerLayerFileBlobStore) DeleteUnreferenced(hash string, before int64, remove func() error) (bool, error) {
+	start := time.Now()
+
+	result, err := s.FileBlobStore.DeleteUnreferenced(hash, before, remove)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileBlobStore.DeleteUnreferenced", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerFileBlobStore) GetByPathPrefix(prefix string) ([]*model.FileBlobRef, error) {
+	start := time.Now()
+
+	result, err := s.FileBlobStore.GetByPathPrefix(prefix)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileBlobStore.GetByPathPrefix", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerFileBlobStore) GetByPaths(paths []string) ([]*model.FileBlobRef, error) {
+	start := time.Now()
+
+	result, err := s.FileBlobStore.GetByPaths(paths)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileBlobStore.GetByPaths", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerFileBlobStore) GetRefCount(hash string) (int64, error) {
+	start := time.Now()
+
+	result, err := s.FileBlobStore.GetRefCount(hash)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileBlobStore.GetRefCount", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerFileBlobStore) GetUnreferenced(before int64, afterHash string, limit int) ([]string, error) {
+	start := time.Now()
+
+	result, err := s.FileBlobStore.GetUnreferenced(before, afterHash, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileBlobStore.GetUnreferenced", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerFileBlobStore) Save(ref *model.FileBlobRef) (int64, error) {
+	start := time.Now()
+
+	result, err := s.FileBlobStore.Save(ref)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileBlobStore.Save", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerFileInfoStore) AttachToPost(c request.CTX, fileID string, postID string, channelID string, creatorID string) error {
 	start := time.Now()
 
@@ -3732,6 +3912,22 @@ func (s *TimerLayerFileInfoStore) GetForPost(postID string, readFromMaster bool,
 	return result, err
 }
 
+func (s *TimerLayerFileInfoStore) GetForStorageTiering(createdBefore int64, minSize int64, afterID string, limit int) ([]*model.FileInfo, error) {
+	start := time.Now()
+
+	result, err := s.FileInfoStore.GetForStorageTiering(createdBefore, minSize, afterID, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.GetForStorageTiering", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerFileInfoStore) GetForUser(userID string) ([]*model.FileInfo, error) {
 	start := time.Now()
 
@@ -3764,6 +3960,22 @@ func (s *TimerLayerFileInfoStore) GetFromMaster(id string) (*model.FileInfo, err
 	return result, err
 }
 
+func (s *TimerLayerFileInfoStore) GetStorageTierUsage() ([]*model.FileStorageTierUsage, error) {
+	start := time.Now()
+
+	result, err := s.FileInfoStore.GetStorageTierUsage()
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.GetStorageTierUsage", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerFileInfoStore) GetStorageUsage(allowFromCache bool, includeDeleted bool) (int64, error) {
 	start := time.Now()
 
@@ -3971,6 +4183,22 @@ func (s *TimerLayerFileInfoStore) SetContent(ctx request.CTX, fileID string, con
 	return err
 }
 
+func (s *TimerLayerFileInfoStore) UpdateStorageTier(fileID string, tier string) error {
+	start := time.Now()
+
+	err := s.FileInfoStore.UpdateStorageTier(fileID, tier)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("FileInfoStore.UpdateStorageTier", success, elapsed)
+	}
+	return err
+}
+
 func (s *TimerLayerFileInfoStore) Upsert(rctx request.CTX, info *model.FileInfo) (*model.FileInfo, error) {
 	start := time.Now()
 
@@ -5970,6 +6198,22 @@ func (s *TimerLayerPostStore) GetDirectPostParentsForExportAfter(limit int, afte
 	return result, err
 }
 
+func (s *TimerLayerPostStore) GetDirectPostParentsForExportSince(limit int, afterID string, since int64, includeArchivedChannels bool) ([]*model.DirectPostForExport, error) {
+	start := time.Now()
+
+	result, err := s.PostStore.GetDirectPostParentsForExportSince(limit, afterID, since, includeArchivedChannels)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetDirectPostParentsForExportSince", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerPostStore) GetEditHistoryForPost(postID string) ([]*model.Post, error) {
 	start := time.Now()
 
@@ -6130,6 +6374,22 @@ func (s *TimerLayerPostStore) GetParentsForExportAfter(limit int, afterID string
 	return result, err
 }
 
+func (s *TimerLayerPostStore) GetParentsForExportSince(limit int, afterID string, since int64, includeArchivedChannels bool) ([]*model.PostForExport, error) {
+	start := time.Now()
+
+	result, err := s.PostStore.GetParentsForExportSince(limit, afterID, since, includeArchivedChannels)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetParentsForExportSince", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerPostStore) GetPostAfterTime(channelID string, timestamp int64, collapsedThreads bool) (*model.Post, error) {
 	start := time.Now()
 
@@ -6370,6 +6630,22 @@ func (s *TimerLayerPostStore) GetRepliesForExport(parentID string) ([]*model.Rep
 	return result, err
 }
 
+func (s *TimerLayerPostStore) GetRepliesForExportSince(parentID string, since int64) ([]*model.ReplyForExport, error) {
+	start := time.Now()
+
+	result, err := s.PostStore.GetRepliesForExportSince(parentID, since)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("PostStore.GetRepliesForExportSince", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerPostStore) GetSingle(rctx request.CTX, id string, inclDeleted bool) (*model.Post, error) {
 	start := time.Now()
 
@@ -7505,6 +7781,22 @@ func (s *TimerLayerReactionStore) GetForPostSince(postID string, since int64, ex
 	return result, err
 }
 
+func (s *TimerLayerReactionStore) GetForPostsSince(postIDs []string, since int64) ([]*model.Reaction, error) {
+	start := time.Now()
+
+	result, err := s.ReactionStore.GetForPostsSince(postIDs, since)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("ReactionStore.GetForPostsSince", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerReactionStore) GetSingle(userID string, postID string, remoteID string, emojiName string) (*model.Reaction, error) {
 	start := time.Now()
 
@@ -8193,6 +8485,150 @@ func (s *TimerLayerRoleStore) Save(role *model.Role) (*model.Role, error) {
 	return result, err
 }
 
+func (s *TimerLayerSavedSearchStore) CountForUser(userId string) (int64, error) {
+	start := time.Now()
+
+	result, err := s.SavedSearchStore.CountForUser(userId)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.CountForUser", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerSavedSearchStore) Delete(id string) error {
+	start := time.Now()
+
+	err := s.SavedSearchStore.Delete(id)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.Delete", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerSavedSearchStore) Get(id string) (*model.SavedSearch, error) {
+	start := time.Now()
+
+	result, err := s.SavedSearchStore.Get(id)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.Get", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerSavedSearchStore) GetForUser(userId string) ([]*model.SavedSearch, error) {
+	start := time.Now()
+
+	result, err := s.SavedSearchStore.GetForUser(userId)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.GetForUser", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerSavedSearchStore) GetWithAlertsEnabled(afterId string, limit int) ([]*model.SavedSearch, error) {
+	start := time.Now()
+
+	result, err := s.SavedSearchStore.GetWithAlertsEnabled(afterId, limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.GetWithAlertsEnabled", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerSavedSearchStore) PermanentDeleteByUser(userId string) error {
+	start := time.Now()
+
+	err := s.SavedSearchStore.PermanentDeleteByUser(userId)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.PermanentDeleteByUser", success, elapsed)
+	}
+	return err
+}
+
+func (s *TimerLayerSavedSearchStore) Save(savedSearch *model.SavedSearch) (*model.SavedSearch, error) {
+	start := time.Now()
+
+	result, err := s.SavedSearchStore.Save(savedSearch)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.Save", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerSavedSearchStore) Update(savedSearch *model.SavedSearch) (*model.SavedSearch, error) {
+	start := time.Now()
+
+	result, err := s.SavedSearchStore.Update(savedSearch)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.Update", success, elapsed)
+	}
+	return result, err
+}
+
+func (s *TimerLayerSavedSearchStore) UpdateLastRunAt(id string, lastRunAt int64) error {
+	start := time.Now()
+
+	err := s.SavedSearchStore.UpdateLastRunAt(id, lastRunAt)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("SavedSearchStore.UpdateLastRunAt", success, elapsed)
+	}
+	return err
+}
+
 func (s *TimerLayerScheduledPostStore) CreateScheduledPost(scheduledPost *model.ScheduledPost) (*model.ScheduledPost, error) {
 	start := time.Now()
 
@@ -9201,6 +9637,22 @@ func (s *TimerLayerStatusStore) GetByIds(userIds []string) ([]*model.Status, err
 	return result, err
 }
 
+func (s *TimerLayerStatusStore) GetRecentlyActiveUserIds(limit int) ([]string, error) {
+	start := time.Now()
+
+	result, err := s.StatusStore.GetRecentlyActiveUserIds(limit)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("StatusStore.GetRecentlyActiveUserIds", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerStatusStore) GetTotalActiveUsersCount() (int64, error) {
 	start := time.Now()
 
@@ -9776,6 +10228,22 @@ func (s *TimerLayerTeamStore) GetTeamMembersForExport(userID string) ([]*model.T
 	return result, err
 }
 
+func (s *TimerLayerTeamStore) GetTeamMembershipReport(options *model.TeamMembershipReportOptions) ([]*model.TeamMembershipReport, error) {
+	start := time.Now()
+
+	result, err := s.TeamStore.GetTeamMembershipReport(options)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("TeamStore.GetTeamMembershipReport", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerTeamStore) GetTeamsByScheme(schemeID string, offset int, limit int) ([]*model.Team, error) {
 	start := time.Now()
 
@@ -11117,6 +11585,22 @@ func (s *TimerLayerUserStore) GetAllAfter(limit int, afterID string) ([]*model.U
 	return result, err
 }
 
+func (s *TimerLayerUserStore) GetAllForExportSince(limit int, afterID string, since int64) ([]*model.User, error) {
+	start := time.Now()
+
+	result, err := s.UserStore.GetAllForExportSince(limit, afterID, since)
+
+	elapsed := float64(time.Since(start)) / float64(time.Second)
+	if s.Root.Metrics != nil {
+		success := "false"
+		if err == nil {
+			success = "true"
+		}
+		s.Root.Metrics.ObserveStoreMethodDuration("UserStore.GetAllForExportSince", success, elapsed)
+	}
+	return result, err
+}
+
 func (s *TimerLayerUserStore) GetAllNotInAuthService(authServices []string) ([]*model.User, error) {
 	start := time.Now()
 
@@ -12894,6 +13378,7 @@ func New(childStore store.Store, metrics einterfaces.MetricsInterface) *TimerLay
 	newStore.DesktopTokensStore = &TimerLayerDesktopTokensStore{DesktopTokensStore: childStore.DesktopTokens(), Root: &newStore}
 	newStore.DraftStore = &TimerLayerDraftStore{DraftStore: childStore.Draft(), Root: &newStore}
 	newStore.EmojiStore = &TimerLayerEmojiStore{EmojiStore: childStore.Emoji(), Root: &newStore}
+	newStore.FileBlobStore = &TimerLayerFileBlobStore{FileBlobStore: childStore.FileBlob(), Root: &newStore}
 	newStore.FileInfoStore = &TimerLayerFileInfoStore{FileInfoStore: childStore.FileInfo(), Root: &newStore}
 	newStore.GroupStore = &TimerLayerGroupStore{GroupStore: childStore.Group(), Root: &newStore}
 	newStore.JobStore = &TimerLayerJobStore{JobStore: childStore.Job(), Root: &newStore}
@@ -12916,6 +13401,7 @@ func New(childStore store.Store, metrics einterfaces.MetricsInterface) *TimerLay
 	newStore.RemoteClusterStore = &TimerLayerRemoteClusterStore{RemoteClusterStore: childStore.RemoteCluster(), Root: &newStore}
 	newStore.RetentionPolicyStore = &TimerLayerRetentionPolicyStore{RetentionPolicyStore: childStore.RetentionPolicy(), Root: &newStore}
 	newStore.RoleStore = &TimerLayerRoleStore{RoleStore: childStore.Role(), Root: &newStore}
+	newStore.SavedSearchStore = &TimerLayerSavedSearchStore{SavedSearchStore: childStore.SavedSearch(), Root: &newStore}
 	newStore.ScheduledPostStore = &TimerLayerScheduledPostStore{ScheduledPostStore: childStore.ScheduledPost(), Root: &newStore}
 	newStore.SchemeStore = &TimerLayerSchemeStore{SchemeStore: childStore.Scheme(), Root: &newStore}
 	newStore.SessionStore = &TimerLayerSessionStore{SessionStore: childStore.Session(), Root: &newStore}
@@ -657,6 +657,17 @@ func (c *Context) RequireFieldId() *Context {
 	return c
 }
 
+func (c *Context) RequireSavedSearchId() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if !model.IsValidId(c.Params.SavedSearchId) {
+		c.SetInvalidURLParam("saved_search_id")
+	}
+	return c
+}
+
 func (c *Context) RequireSchemeId() *Context {
 	if c.Err != nil {
 		return c
@@ -680,6 +691,18 @@ func (c *Context) RequireRoleName() *Context {
 	return c
 }
 
+func (c *Context) RequireCacheName() *Context {
+	if c.Err != nil {
+		return c
+	}
+
+	if c.Params.CacheName == "" {
+		c.SetInvalidURLParam("cache_name")
+	}
+
+	return c
+}
+
 func (c *Context) RequireGroupId() *Context {
 	if c.Err != nil {
 		return c
@@ -15,6 +15,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/klauspost/compress/gzhttp"
 
 	"github.com/mattermost/mattermost/server/public/model"
@@ -142,6 +143,34 @@ func (h Handler) basicSecurityChecks(c *Context, w http.ResponseWriter, r *http.
 	}
 }
 
+// rateLimitRequest describes the request for the rate limit policies.
+func rateLimitRequest(c *Context, r *http.Request) *app.RateLimitRequest {
+	subpath, _ := utils.GetSubpathFromConfig(c.App.Config())
+	rr := &app.RateLimitRequest{
+		Path:     "/" + strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, strings.TrimSuffix(subpath, "/")), "/"),
+		Method:   r.Method,
+		AuthType: model.RateLimitAuthTypeNone,
+	}
+
+	session := c.AppContext.Session()
+	switch {
+	case session.UserId != "":
+		rr.Session = session
+		if session.IsBotUser() {
+			rr.AuthType = model.RateLimitAuthTypeBot
+		} else if session.IsUserAccessToken() {
+			rr.AuthType = model.RateLimitAuthTypePersonalAccessToken
+		} else {
+			rr.AuthType = model.RateLimitAuthTypeSession
+		}
+	case IsWebhookCall(c.App, r):
+		rr.AuthType = model.RateLimitAuthTypeIncomingWebhook
+		rr.HookId = mux.Vars(r)["id"]
+	}
+
+	return rr
+}
+
 func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w = newWrappedWriter(w)
 	now := time.Now()
@@ -315,6 +344,14 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	)
 	c.AppContext = c.AppContext.WithLogger(c.Logger)
 
+	// Rate limit by policy
+	if c.Err == nil && c.App.Srv().RateLimiter != nil {
+		rateLimitExceeded = c.App.Srv().RateLimiter.PolicyRateLimit(w, r, rateLimitRequest(c, r))
+		if rateLimitExceeded {
+			return
+		}
+	}
+
 	if c.Err == nil && h.RequireSession {
 		c.SessionRequired()
 	}
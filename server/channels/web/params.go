@@ -56,6 +56,7 @@ type Params struct {
 	ActionId                  string
 	RoleId                    string
 	RoleName                  string
+	CacheName                 string
 	SchemeId                  string
 	Scope                     string
 	GroupId                   string
@@ -114,6 +115,9 @@ type Params struct {
 
 	// Custom Profile Attributes
 	FieldId string
+
+	// Saved Searches
+	SavedSearchId string
 }
 
 func ParamsFromRequest(r *http.Request) *Params {
@@ -163,6 +167,7 @@ func ParamsFromRequest(r *http.Request) *Params {
 	params.ActionId = props["action_id"]
 	params.RoleId = props["role_id"]
 	params.RoleName = props["role_name"]
+	params.CacheName = props["cache_name"]
 	params.SchemeId = props["scheme_id"]
 	params.GroupId = props["group_id"]
 	params.RemoteId = props["remote_id"]
@@ -182,6 +187,7 @@ func ParamsFromRequest(r *http.Request) *Params {
 	params.ExcludeRemote, _ = strconv.ParseBool(query.Get("exclude_remote"))
 	params.ChannelBookmarkId = props["bookmark_id"]
 	params.FieldId = props["field_id"]
+	params.SavedSearchId = props["saved_search_id"]
 	params.Scope = query.Get("scope")
 
 	if val, err := strconv.Atoi(query.Get("page")); err != nil || val < 0 {
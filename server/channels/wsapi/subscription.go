@@ -0,0 +1,45 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package wsapi
+
+import (
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/app/platform"
+)
+
+func (api *API) InitSubscription() {
+	api.Router.Handle(model.WebsocketSubscribeAction, api.APIWebSocketConnHandler(subscribe))
+	api.Router.Handle(model.WebsocketUnsubscribeAction, api.APIWebSocketConnHandler(unsubscribe))
+}
+
+// The subscriptions only narrow down the events a connection already receives, so they don't need any
+// permission check: the hub still checks that the user can see each event.
+
+func subscribe(conn *platform.WebConn, req *model.WebSocketRequest) (map[string]any, *model.AppError) {
+	subscription := model.WebSocketSubscriptionFromMap(req.Data)
+	if subscription.IsEmpty() {
+		return nil, NewInvalidWebSocketParamError(req.Action, "events")
+	}
+	if appErr := subscription.IsValid(); appErr != nil {
+		return nil, appErr
+	}
+
+	return subscriptionData(conn.Subscribe(subscription)), nil
+}
+
+func unsubscribe(conn *platform.WebConn, req *model.WebSocketRequest) (map[string]any, *model.AppError) {
+	subscription := model.WebSocketSubscriptionFromMap(req.Data)
+	if appErr := subscription.IsValid(); appErr != nil {
+		return nil, appErr
+	}
+
+	return subscriptionData(conn.Unsubscribe(subscription)), nil
+}
+
+func subscriptionData(subscription *model.WebSocketSubscription) map[string]any {
+	if subscription == nil {
+		subscription = &model.WebSocketSubscription{}
+	}
+	return subscription.ToMap()
+}
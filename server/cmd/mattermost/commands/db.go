@@ -190,10 +190,11 @@ func migrateCmdF(command *cobra.Command, args []string) error {
 	}
 
 	if savePlan || recoverFlag {
-		backend, err2 := filestore.NewFileBackend(ConfigToFileBackendSettings(&config.FileSettings, false, true))
+		backend, err2 := newMigrationPlanFileBackend(&config.FileSettings)
 		if err2 != nil {
 			return fmt.Errorf("failed to initialize filebackend: %w", err2)
 		}
+		defer filestore.CloseFileBackend(backend)
 
 		b, mErr := json.MarshalIndent(plan, "", "  ")
 		if mErr != nil {
@@ -240,10 +241,11 @@ func downgradeCmdF(command *cobra.Command, args []string) error {
 	dryRun, _ := command.Flags().GetBool("dry-run")
 	recoverFlag, _ := command.Flags().GetBool("auto-recover")
 
-	backend, err2 := filestore.NewFileBackend(ConfigToFileBackendSettings(&config.FileSettings, false, true))
+	backend, err2 := newMigrationPlanFileBackend(&config.FileSettings)
 	if err2 != nil {
 		return fmt.Errorf("failed to initialize filebackend: %w", err2)
 	}
+	defer filestore.CloseFileBackend(backend)
 
 	migrator, err := sqlstore.NewMigrator(config.SqlSettings, logger, dryRun)
 	if err != nil {
@@ -322,15 +324,34 @@ func dbVersionCmdF(command *cobra.Command, args []string) error {
 	return nil
 }
 
+// newMigrationPlanFileBackend returns the file backend the migration plans
+// are saved to and read from.
+func newMigrationPlanFileBackend(s *model.FileSettings) (filestore.FileBackend, error) {
+	if *s.DriverName == model.ImageDriverDedup {
+		CommandPrettyPrintln(fmt.Sprintf("The dedup file driver is configured: the migration plan is stored with its underlying %q driver.", *s.DedupDriverName))
+	}
+	return filestore.NewFileBackend(ConfigToFileBackendSettings(s, false, true))
+}
+
+// ConfigToFileBackendSettings returns the settings of the file backend used
+// by the database commands. These can't use the dedup driver, since it keeps
+// its blob references in the database they migrate, so the settings of its
+// underlying driver are returned instead. The dedup driver serves the files
+// written that way as files written before deduplication was enabled.
 func ConfigToFileBackendSettings(s *model.FileSettings, enableComplianceFeature bool, skipVerify bool) filestore.FileBackendSettings {
-	if *s.DriverName == model.ImageDriverLocal {
+	driverName := *s.DriverName
+	if driverName == model.ImageDriverDedup {
+		driverName = *s.DedupDriverName
+	}
+
+	if driverName == model.ImageDriverLocal {
 		return filestore.FileBackendSettings{
-			DriverName: *s.DriverName,
+			DriverName: driverName,
 			Directory:  *s.Directory,
 		}
 	}
 	return filestore.FileBackendSettings{
-		DriverName:                         *s.DriverName,
+		DriverName:                         driverName,
 		AmazonS3AccessKeyId:                *s.AmazonS3AccessKeyId,
 		AmazonS3SecretAccessKey:            *s.AmazonS3SecretAccessKey,
 		AmazonS3Bucket:                     *s.AmazonS3Bucket,
@@ -4,6 +4,7 @@
 package commands
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -25,7 +26,7 @@ var SlackImportCmd = &cobra.Command{
 	Use:     "slack [team] [file]",
 	Short:   "Import a team from Slack.",
 	Long:    "Import a team from a Slack export zip file.",
-	Example: "  import slack myteam slack_export.zip",
+	Example: "  import slack myteam slack_export.zip --report report.json",
 	RunE:    slackImportCmdF,
 }
 
@@ -38,6 +39,8 @@ var BulkImportCmd = &cobra.Command{
 }
 
 func init() {
+	SlackImportCmd.Flags().String("report", "", "A path to write the JSON report of the import to, listing what has been imported and what has been skipped.")
+
 	BulkImportCmd.Flags().Bool("apply", false, "Save the import data to the database. Use with caution - this cannot be reverted.")
 	BulkImportCmd.Flags().Bool("validate", false, "Validate the import data without making any changes to the system.")
 	BulkImportCmd.Flags().Int("workers", 2, "How many workers to run whilst doing the import.")
@@ -63,6 +66,11 @@ func slackImportCmdF(command *cobra.Command, args []string) error {
 		return errors.New("Incorrect number of arguments.")
 	}
 
+	reportPath, err := command.Flags().GetString("report")
+	if err != nil {
+		return errors.New("Report flag error")
+	}
+
 	team := getTeamFromTeamArg(a, args[0])
 	if team == nil {
 		return errors.New("Unable to find team '" + args[0] + "'")
@@ -81,16 +89,28 @@ func slackImportCmdF(command *cobra.Command, args []string) error {
 
 	CommandPrettyPrintln("Running Slack Import. This may take a long time for large teams or teams with many messages.")
 
-	importErr, log := a.SlackImport(rctx, fileReader, fileInfo.Size(), team.Id)
+	importErr, log, report := a.SlackImport(rctx, fileReader, fileInfo.Size(), team.Id)
 
 	if importErr != nil {
-		return err
+		return importErr
 	}
 
 	CommandPrettyPrintln("")
 	CommandPrintln(log.String())
 	CommandPrettyPrintln("")
 
+	if reportPath != "" {
+		reportJSON, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(reportPath, reportJSON, 0600); err != nil {
+			return err
+		}
+		CommandPrettyPrintln(fmt.Sprintf("Wrote the report of the import to %s, %d items have been skipped.", reportPath, len(report.Skipped)))
+		CommandPrettyPrintln("")
+	}
+
 	CommandPrettyPrintln("Finished Slack Import.")
 	CommandPrettyPrintln("")
 
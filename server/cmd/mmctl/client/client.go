@@ -114,6 +114,8 @@ type Client interface {
 	SetServerBusy(ctx context.Context, secs int) (*model.Response, error)
 	ClearServerBusy(ctx context.Context) (*model.Response, error)
 	GetServerBusy(ctx context.Context) (*model.ServerBusyState, *model.Response, error)
+	GetCaches(ctx context.Context) ([]*model.CacheInfo, *model.Response, error)
+	PurgeCache(ctx context.Context, name string) (*model.Response, error)
 	CheckIntegrity(ctx context.Context) ([]model.IntegrityCheckResult, *model.Response, error)
 	InstallPluginFromURL(context.Context, string, bool) (*model.Manifest, *model.Response, error)
 	InstallMarketplacePlugin(context.Context, *model.InstallMarketplacePluginRequest) (*model.Manifest, *model.Response, error)
@@ -127,7 +129,13 @@ type Client interface {
 	GetUpload(ctx context.Context, uploadID string) (*model.UploadSession, *model.Response, error)
 	GetUploadsForUser(ctx context.Context, userID string) ([]*model.UploadSession, *model.Response, error)
 	UploadData(ctx context.Context, uploadID string, data io.Reader) (*model.FileInfo, *model.Response, error)
+	GetFileStorageTierUsage(ctx context.Context) ([]*model.FileStorageTierUsage, *model.Response, error)
+	CreateSavedSearch(ctx context.Context, userID string, savedSearch *model.SavedSearch) (*model.SavedSearch, *model.Response, error)
+	GetSavedSearches(ctx context.Context, userID string) ([]*model.SavedSearch, *model.Response, error)
+	PatchSavedSearch(ctx context.Context, userID, savedSearchID string, patch *model.SavedSearchPatch) (*model.SavedSearch, *model.Response, error)
+	DeleteSavedSearch(ctx context.Context, userID, savedSearchID string) (*model.Response, error)
 	ListImports(ctx context.Context) ([]string, *model.Response, error)
+	GetImportDryRunReport(ctx context.Context, jobID string) (*model.ImportDryRunReport, *model.Response, error)
 	GetJob(ctx context.Context, id string) (*model.Job, *model.Response, error)
 	GetJobs(ctx context.Context, jobType string, status string, page int, perPage int) ([]*model.Job, *model.Response, error)
 	GetJobsByType(ctx context.Context, jobType string, page int, perPage int) ([]*model.Job, *model.Response, error)
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/client"
 	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
@@ -24,8 +26,13 @@ var ExportCmd = &cobra.Command{
 var ExportCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create export file",
-	Args:  cobra.NoArgs,
-	RunE:  withClient(exportCreateCmdF),
+	Example: `  # create a full export
+  $ mmctl export create
+
+  # create an incremental export of the changes since the previous export, to import on top of it
+  $ mmctl export create --since 2024-06-01T00:00:00Z`,
+	Args: cobra.NoArgs,
+	RunE: withClient(exportCreateCmdF),
 }
 
 var ExportDownloadCmd = &cobra.Command{
@@ -103,6 +110,7 @@ func init() {
 	ExportCreateCmd.Flags().Bool("include-archived-channels", false, "Include archived channels in the export file.")
 	ExportCreateCmd.Flags().Bool("include-profile-pictures", false, "Include profile pictures in the export file.")
 	ExportCreateCmd.Flags().Bool("no-roles-and-schemes", false, "Exclude roles and custom permission schemes from the export file.")
+	ExportCreateCmd.Flags().String("since", "", "Only export the changes made after this time, as an RFC 3339 date or in milliseconds since the epoch. The deleted teams, channels, posts and emojis, and the memberships left, are exported as tombstones.")
 
 	ExportDownloadCmd.Flags().Bool("resume", false, "Set to true to resume an export download.")
 	_ = ExportDownloadCmd.Flags().MarkHidden("resume")
@@ -154,6 +162,14 @@ func exportCreateCmdF(c client.Client, command *cobra.Command, args []string) er
 		data["include_profile_pictures"] = "true"
 	}
 
+	if since, _ := command.Flags().GetString("since"); since != "" {
+		millis, err := parseExportSince(since)
+		if err != nil {
+			return err
+		}
+		data["since"] = strconv.FormatInt(millis, 10)
+	}
+
 	job, _, err := c.CreateJob(context.TODO(), &model.Job{
 		Type: model.JobTypeExportProcess,
 		Data: data,
@@ -167,6 +183,21 @@ func exportCreateCmdF(c client.Client, command *cobra.Command, args []string) er
 	return nil
 }
 
+// parseExportSince returns the time of the --since flag in milliseconds.
+func parseExportSince(since string) (int64, error) {
+	if millis, err := strconv.ParseInt(since, 10, 64); err == nil {
+		if millis < 0 {
+			return 0, fmt.Errorf("invalid since %q: the time must be positive", since)
+		}
+		return millis, nil
+	}
+	t, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return 0, fmt.Errorf("invalid since %q: expected an RFC 3339 date or milliseconds since the epoch", since)
+	}
+	return t.UnixMilli(), nil
+}
+
 func exportListCmdF(c client.Client, command *cobra.Command, args []string) error {
 	exports, _, err := c.ListExports(context.TODO())
 	if err != nil {
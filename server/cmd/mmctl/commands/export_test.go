@@ -87,6 +87,46 @@ func (s *MmctlUnitTestSuite) TestExportCreateCmdF() {
 		s.Empty(printer.GetErrorLines())
 		s.Equal(mockJob, printer.GetLines()[0].(*model.Job))
 	})
+
+	s.Run("create incremental export", func() {
+		for _, since := range []string{"1717200000000", "2024-06-01T00:00:00Z"} {
+			printer.Clean()
+			mockJob := &model.Job{
+				Type: model.JobTypeExportProcess,
+				Data: map[string]string{
+					"include_attachments":       "true",
+					"include_roles_and_schemes": "true",
+					"since":                     "1717200000000",
+				},
+			}
+
+			s.client.
+				EXPECT().
+				CreateJob(context.TODO(), mockJob).
+				Return(mockJob, &model.Response{}, nil).
+				Times(1)
+
+			cmd := &cobra.Command{}
+			cmd.Flags().String("since", since, "")
+
+			err := exportCreateCmdF(s.client, cmd, nil)
+			s.Require().Nil(err)
+			s.Len(printer.GetLines(), 1)
+			s.Equal(mockJob, printer.GetLines()[0].(*model.Job))
+		}
+	})
+
+	s.Run("create incremental export with an invalid since", func() {
+		printer.Clean()
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("since", "yesterday", "")
+
+		err := exportCreateCmdF(s.client, cmd, nil)
+		s.Require().Error(err)
+		s.Contains(err.Error(), "invalid since")
+		s.Empty(printer.GetLines())
+	})
 }
 
 func (s *MmctlUnitTestSuite) TestExportDeleteCmdF() {
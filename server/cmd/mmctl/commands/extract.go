@@ -54,6 +54,7 @@ var ExtractJobShowCmd = &cobra.Command{
 func init() {
 	ExtractRunCmd.Flags().Int64("from", 0, "The timestamp of the earliest file to extract, expressed in seconds since the unix epoch.")
 	ExtractRunCmd.Flags().Int64("to", 0, "The timestamp of the latest file to extract, expressed in seconds since the unix epoch. Defaults to the current time.")
+	ExtractRunCmd.Flags().Bool("images-only", false, "Only extract the text of images, to backfill it after enabling OCR.")
 	ExtractJobListCmd.Flags().Int("page", 0, "Page number to fetch for the list of extract jobs")
 	ExtractJobListCmd.Flags().Int("per-page", DefaultPageSize, "Number of extract jobs to be fetched")
 	ExtractJobListCmd.Flags().Bool("all", false, "Fetch all extract jobs. --page flag will be ignore if provided")
@@ -80,13 +81,22 @@ func extractRunCmdF(c client.Client, command *cobra.Command, args []string) erro
 	if to == 0 {
 		to = model.GetMillis() / 1000
 	}
+	imagesOnly, err := command.Flags().GetBool("images-only")
+	if err != nil {
+		return err
+	}
+
+	data := map[string]string{
+		"from": strconv.FormatInt(from, 10),
+		"to":   strconv.FormatInt(to, 10),
+	}
+	if imagesOnly {
+		data["images_only"] = "true"
+	}
 
 	job, _, err := c.CreateJob(context.TODO(), &model.Job{
 		Type: model.JobTypeExtractContent,
-		Data: map[string]string{
-			"from": strconv.FormatInt(from, 10),
-			"to":   strconv.FormatInt(to, 10),
-		},
+		Data: data,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create content extraction job: %w", err)
@@ -4,6 +4,7 @@
 package commands
 
 import (
+	"archive/zip"
 	"context"
 	"errors"
 	"fmt"
@@ -22,6 +23,10 @@ import (
 	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/client"
 	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/commands/importer"
 	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+	"github.com/mattermost/mattermost/server/v8/platform/services/discordimport"
+	"github.com/mattermost/mattermost/server/v8/platform/services/importconvert"
+	"github.com/mattermost/mattermost/server/v8/platform/services/msteamsimport"
+	"github.com/mattermost/mattermost/server/v8/platform/services/rocketchatimport"
 )
 
 var ImportCmd = &cobra.Command{
@@ -100,6 +105,74 @@ var ImportValidateCmd = &cobra.Command{
 	},
 }
 
+var ImportMSTeamsCmd = &cobra.Command{
+	Use:   "msteams",
+	Short: "Convert and import Microsoft Teams exports",
+	Long: `Convert and import Microsoft Teams exports.
+
+The export is a zip file of the Microsoft Graph API responses: users.json, the teams/<team id>/ directories with their team.json, members.json and channels/<channel id>/ directories, the chats/<chat id>/ directories, and the attached files in attachments/<attachment id>/.`,
+}
+
+var ImportMSTeamsConvertCmd = &cobra.Command{
+	Use:   "convert [exportpath] [importpath]",
+	Short: "Convert a Microsoft Teams export to an import file",
+	Long:  "Convert a Microsoft Teams export to an import file, and validate the import file as the import validate command does.",
+	Example: `  # convert the export, then upload and process the import file
+  $ mmctl import msteams convert msteams_export.zip import_file.zip
+  $ mmctl import upload import_file.zip
+  $ mmctl import process 35uy6cwrqfnhdx3genrhqqznxc_import_file.zip`,
+	Args: cobra.ExactArgs(2),
+	RunE: importMSTeamsConvertCmdF,
+}
+
+var ImportDiscordCmd = &cobra.Command{
+	Use:   "discord",
+	Short: "Convert Discord exports",
+	Long: `Convert Discord exports.
+
+The export is a zip file of the JSON exports of DiscordChatExporter, one file per channel, thread or direct message, exported with the --media option for the attached files to be imported.`,
+}
+
+var ImportDiscordConvertCmd = &cobra.Command{
+	Use:   "convert [exportpath] [importpath]",
+	Short: "Convert a Discord export to an import file",
+	Long:  "Convert a Discord export to an import file, and validate the import file as the import validate command does. Discord doesn't export the email addresses of the users, they must reset their password to sign in.",
+	Example: `  # convert the export, then upload and process the import file
+  $ mmctl import discord convert discord_export.zip import_file.zip --email-domain example.org
+  $ mmctl import upload import_file.zip
+  $ mmctl import process 35uy6cwrqfnhdx3genrhqqznxc_import_file.zip`,
+	Args: cobra.ExactArgs(2),
+	RunE: importDiscordConvertCmdF,
+}
+
+var ImportRocketChatCmd = &cobra.Command{
+	Use:   "rocketchat",
+	Short: "Convert Rocket.Chat exports",
+	Long: `Convert Rocket.Chat exports.
+
+The export is a zip file of the collections of the Rocket.Chat database exported with mongoexport: users.json, rocketchat_room.json, rocketchat_subscription.json and rocketchat_message.json, and the uploaded files in the uploads/ directory, named after their id.`,
+}
+
+var ImportRocketChatConvertCmd = &cobra.Command{
+	Use:   "convert [exportpath] [importpath]",
+	Short: "Convert a Rocket.Chat export to an import file",
+	Long:  "Convert a Rocket.Chat export to an import file, and validate the import file as the import validate command does. The channels are imported in a single team.",
+	Example: `  # convert the export, then upload and process the import file
+  $ mmctl import rocketchat convert rocketchat_export.zip import_file.zip --team community
+  $ mmctl import upload import_file.zip
+  $ mmctl import process 35uy6cwrqfnhdx3genrhqqznxc_import_file.zip`,
+	Args: cobra.ExactArgs(2),
+	RunE: importRocketChatConvertCmdF,
+}
+
+var ImportMSTeamsProcessCmd = &cobra.Command{
+	Use:     "process [exportname]",
+	Example: "  import msteams process 35uy6cwrqfnhdx3genrhqqznxc_msteams_export.zip",
+	Short:   "Start a job converting and importing an uploaded Microsoft Teams export",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(importMSTeamsProcessCmdF),
+}
+
 func init() {
 	ImportUploadCmd.Flags().Bool("resume", false, "Set to true to resume an incomplete import upload.")
 	ImportUploadCmd.Flags().String("upload", "", "The ID of the import upload to resume.")
@@ -115,6 +188,22 @@ func init() {
 
 	ImportProcessCmd.Flags().Bool("bypass-upload", false, "If this is set, the file is not processed from the server, but rather directly read from the filesystem. Works only in --local mode.")
 	ImportProcessCmd.Flags().Bool("extract-content", true, "If this is set, document attachments will be extracted and indexed during the import process. It is advised to disable it to improve performance.")
+	ImportProcessCmd.Flags().Bool("dry-run", false, "Report what the import would create, update and delete without changing anything. The report is shown by import job show --report.")
+
+	ImportJobShowCmd.Flags().Bool("report", false, "Show the report of a dry run import job.")
+
+	ImportMSTeamsConvertCmd.Flags().Bool("office365-auth", false, "Make the users sign in with Office 365 instead of a password.")
+
+	ImportMSTeamsProcessCmd.Flags().Bool("bypass-upload", false, "If this is set, the file is not processed from the server, but rather directly read from the filesystem. Works only in --local mode.")
+	ImportMSTeamsProcessCmd.Flags().Bool("extract-content", true, "If this is set, document attachments will be extracted and indexed during the import process. It is advised to disable it to improve performance.")
+	ImportMSTeamsProcessCmd.Flags().Bool("office365-auth", false, "Make the users sign in with Office 365 instead of a password.")
+
+	ImportDiscordConvertCmd.Flags().String("email-domain", "", "The domain of the email addresses of the users. Defaults to example.com.")
+
+	ImportRocketChatConvertCmd.Flags().String("team", "", "The name of the team of the channels.")
+	_ = ImportRocketChatConvertCmd.MarkFlagRequired("team")
+	ImportRocketChatConvertCmd.Flags().String("team-display-name", "", "The display name of the team. Defaults to the name of the team.")
+	ImportRocketChatConvertCmd.Flags().String("email-domain", "", "The domain of the email addresses of the users without one. Defaults to example.com.")
 
 	ImportListCmd.AddCommand(
 		ImportListAvailableCmd,
@@ -124,12 +213,25 @@ func init() {
 		ImportJobListCmd,
 		ImportJobShowCmd,
 	)
+	ImportMSTeamsCmd.AddCommand(
+		ImportMSTeamsConvertCmd,
+		ImportMSTeamsProcessCmd,
+	)
+	ImportDiscordCmd.AddCommand(
+		ImportDiscordConvertCmd,
+	)
+	ImportRocketChatCmd.AddCommand(
+		ImportRocketChatConvertCmd,
+	)
 	ImportCmd.AddCommand(
 		ImportUploadCmd,
 		ImportListCmd,
 		ImportProcessCmd,
 		ImportJobCmd,
 		ImportValidateCmd,
+		ImportMSTeamsCmd,
+		ImportDiscordCmd,
+		ImportRocketChatCmd,
 	)
 	RootCmd.AddCommand(ImportCmd)
 }
@@ -251,8 +353,42 @@ func importUploadCmdF(c client.Client, command *cobra.Command, args []string) er
 }
 
 func importProcessCmdF(c client.Client, command *cobra.Command, args []string) error {
-	importFile := args[0]
+	dryRun, _ := command.Flags().GetBool("dry-run")
+
+	job, err := createImportJob(c, command, model.JobTypeImportProcess, "import process", args[0], map[string]string{
+		"dry_run": strconv.FormatBool(dryRun),
+	})
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		printer.PrintT("Import dry run job successfully created, ID: {{.Id}}", job)
+		return nil
+	}
+	printer.PrintT("Import process job successfully created, ID: {{.Id}}", job)
 
+	return nil
+}
+
+func importMSTeamsProcessCmdF(c client.Client, command *cobra.Command, args []string) error {
+	office365Auth, _ := command.Flags().GetBool("office365-auth")
+
+	job, err := createImportJob(c, command, model.JobTypeImportMSTeams, "Microsoft Teams import", args[0], map[string]string{
+		"office365_auth": strconv.FormatBool(office365Auth),
+	})
+	if err != nil {
+		return err
+	}
+
+	printer.PrintT("Microsoft Teams import job successfully created, ID: {{.Id}}", job)
+
+	return nil
+}
+
+// createImportJob creates a job of the given type processing the import file, as set by the bypass-upload and
+// extract-content flags of the command.
+func createImportJob(c client.Client, command *cobra.Command, jobType, jobName, importFile string, data map[string]string) (*model.Job, error) {
 	isLocal, _ := command.Flags().GetBool("local")
 	bypassUpload, _ := command.Flags().GetBool("bypass-upload")
 	if bypassUpload {
@@ -260,28 +396,28 @@ func importProcessCmdF(c client.Client, command *cobra.Command, args []string) e
 			// First, we validate whether the server is in HA.
 			config, _, err := c.GetOldClientConfig(context.TODO(), "")
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			enableCluster, err := strconv.ParseBool(config["EnableCluster"])
 			if err != nil {
-				return fmt.Errorf("failed to parse EnableCluster: %w", err)
+				return nil, fmt.Errorf("failed to parse EnableCluster: %w", err)
 			}
 
 			if enableCluster {
-				return errors.New("--bypass-upload flag doesn't work if the server is in HA. Because the file has to be present locally on the server where the job request hits. Please disable HA and try again.")
+				return nil, errors.New("--bypass-upload flag doesn't work if the server is in HA. Because the file has to be present locally on the server where the job request hits. Please disable HA and try again.")
 			}
 
 			// in local mode, we tell the server to directly read from this file.
 			if _, err := os.Stat(importFile); errors.Is(err, os.ErrNotExist) {
-				return fmt.Errorf("file %s doesn't exist. NOTE: If this file was uploaded to the server via mmctl import upload, please omit the --bypass-upload flag to revert to old behavior.", importFile)
+				return nil, fmt.Errorf("file %s doesn't exist. NOTE: If this file was uploaded to the server via mmctl import upload, please omit the --bypass-upload flag to revert to old behavior.", importFile)
 			}
 			// If it's not an absolute path, then we make it
 			if !path.IsAbs(importFile) {
 				var err2 error
 				importFile, err2 = filepath.Abs(importFile)
 				if err2 != nil {
-					return fmt.Errorf("error is getting the absolute path to %s: %w", importFile, err2)
+					return nil, fmt.Errorf("error is getting the absolute path to %s: %w", importFile, err2)
 				}
 			}
 		} else {
@@ -291,21 +427,24 @@ func importProcessCmdF(c client.Client, command *cobra.Command, args []string) e
 
 	extractContent, _ := command.Flags().GetBool("extract-content")
 
+	jobData := map[string]string{
+		"import_file":     importFile,
+		"local_mode":      strconv.FormatBool(isLocal && bypassUpload),
+		"extract_content": strconv.FormatBool(extractContent),
+	}
+	for key, value := range data {
+		jobData[key] = value
+	}
+
 	job, _, err := c.CreateJob(context.TODO(), &model.Job{
-		Type: model.JobTypeImportProcess,
-		Data: map[string]string{
-			"import_file":     importFile,
-			"local_mode":      strconv.FormatBool(isLocal && bypassUpload),
-			"extract_content": strconv.FormatBool(extractContent),
-		},
+		Type: jobType,
+		Data: jobData,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to create import process job: %w", err)
+		return nil, fmt.Errorf("failed to create %s job: %w", jobName, err)
 	}
 
-	printer.PrintT("Import process job successfully created, ID: {{.Id}}", job)
-
-	return nil
+	return job, nil
 }
 
 func importJobShowCmdF(c client.Client, command *cobra.Command, args []string) error {
@@ -316,6 +455,20 @@ func importJobShowCmdF(c client.Client, command *cobra.Command, args []string) e
 
 	printJob(job)
 
+	if report, _ := command.Flags().GetBool("report"); report {
+		dryRunReport, _, err := c.GetImportDryRunReport(context.TODO(), job.Id)
+		if err != nil {
+			return fmt.Errorf("failed to get import dry run report: %w", err)
+		}
+		printer.PrintT(`Entities:
+{{range $entity, $counts := .Entities}}  {{$entity}}: {{$counts.Create}} to create, {{$counts.Update}} to update, {{$counts.Delete}} to delete, {{$counts.NoOp}} unchanged
+{{end}}Changes:
+{{range .Changes}}  line {{.Line}}: {{.Action}} {{.Entity}} {{.Name}}{{range .Fields}}
+    {{.Name}}: "{{.Old}}" -> "{{.New}}"{{end}}
+{{end}}{{if .TruncatedChanges}}  and {{.TruncatedChanges}} more changes
+{{end}}`, dryRunReport)
+	}
+
 	return nil
 }
 
@@ -472,6 +625,11 @@ func importValidateCmdF(c client.Client, command *cobra.Command, args []string)
 		maxPostSize,
 	)
 
+	return runImportValidation(validator, args[0], createMissingTeams)
+}
+
+// runImportValidation validates the import file, printing the errors and the statistics of the file.
+func runImportValidation(validator *importer.Validator, fileName string, createMissingTeams bool) error {
 	var errors []*importer.ImportValidationError
 	templateError := template.Must(template.New("").Parse("{{ .Error }}\n"))
 	validator.OnError(func(ive *importer.ImportValidationError) error {
@@ -480,7 +638,7 @@ func importValidateCmdF(c client.Client, command *cobra.Command, args []string)
 		return nil
 	})
 
-	err = validator.Validate()
+	err := validator.Validate()
 	if err != nil {
 		return err
 	}
@@ -515,11 +673,91 @@ func importValidateCmdF(c client.Client, command *cobra.Command, args []string)
 		}{unusedAttachments})
 	}
 
-	printer.PrintT("It took {{ .Elapsed }} to validate {{ .TotalLines }} lines in {{ .FileName }}\n", ImportValidationResult{args[0], validator.Lines(), validator.Duration(), errors})
+	printer.PrintT("It took {{ .Elapsed }} to validate {{ .TotalLines }} lines in {{ .FileName }}\n", ImportValidationResult{fileName, validator.Lines(), validator.Duration(), errors})
 
 	return nil
 }
 
+func importMSTeamsConvertCmdF(command *cobra.Command, args []string) error {
+	office365Auth, _ := command.Flags().GetBool("office365-auth")
+	return convertExport("Microsoft Teams", args, func(export *zip.Reader, w io.Writer) (*importconvert.Report, error) {
+		return msteamsimport.Convert(export, w, msteamsimport.Options{
+			Office365Auth: office365Auth,
+		})
+	})
+}
+
+func importDiscordConvertCmdF(command *cobra.Command, args []string) error {
+	emailDomain, _ := command.Flags().GetString("email-domain")
+	return convertExport("Discord", args, func(export *zip.Reader, w io.Writer) (*importconvert.Report, error) {
+		return discordimport.Convert(export, w, discordimport.Options{
+			EmailDomain: emailDomain,
+		})
+	})
+}
+
+func importRocketChatConvertCmdF(command *cobra.Command, args []string) error {
+	teamName, _ := command.Flags().GetString("team")
+	teamDisplayName, _ := command.Flags().GetString("team-display-name")
+	emailDomain, _ := command.Flags().GetString("email-domain")
+	return convertExport("Rocket.Chat", args, func(export *zip.Reader, w io.Writer) (*importconvert.Report, error) {
+		return rocketchatimport.Convert(export, w, rocketchatimport.Options{
+			TeamName:        teamName,
+			TeamDisplayName: teamDisplayName,
+			EmailDomain:     emailDomain,
+		})
+	})
+}
+
+// convertExport converts the export of another platform, at the first path of the arguments, to an import file at the
+// second one, and validates the import file as the import validate command does.
+func convertExport(platform string, args []string, convert func(export *zip.Reader, w io.Writer) (*importconvert.Report, error)) error {
+	configurePrinter()
+	defer printer.Print("Conversion complete\n")
+
+	exportFile, err := zip.OpenReader(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open the %s export: %w", platform, err)
+	}
+	defer exportFile.Close()
+
+	importFile, err := os.Create(args[1])
+	if err != nil {
+		return fmt.Errorf("failed to create the import file: %w", err)
+	}
+	defer importFile.Close()
+
+	report, err := convert(&exportFile.Reader, importFile)
+	if err != nil {
+		return fmt.Errorf("failed to convert the %s export: %w", platform, err)
+	}
+	if err = importFile.Close(); err != nil {
+		return fmt.Errorf("failed to write the import file: %w", err)
+	}
+
+	printer.PrintT("{{ range .Warnings }}Warning: {{ . }}\n{{ end }}"+
+		"Converted {{ .ExportFile }} to {{ .ImportFile }}, {{ .SkippedMessages }} messages were skipped\n", struct {
+		ExportFile string `json:"export_file"`
+		ImportFile string `json:"import_file"`
+		*importconvert.Report
+	}{args[0], args[1], report})
+
+	// The converted teams, channels and users are validated as new ones, the server may already have some of them.
+	validator := importer.NewValidator(
+		args[1], // input file
+		false,   // ignore attachments flag
+		true,    // create missing teams flag
+		false,   // check for server duplicates flag
+		map[string]*model.Team{},
+		map[importer.ChannelTeam]*model.Channel{},
+		map[string]*model.User{},
+		map[string]*model.User{},
+		model.PostMessageMaxRunesV2,
+	)
+
+	return runImportValidation(validator, args[1], false)
+}
+
 func configurePrinter() {
 	// we want to manage the newlines ourselves
 	printer.SetNoNewline(true)
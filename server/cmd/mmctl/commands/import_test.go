@@ -148,6 +148,43 @@ func (s *MmctlUnitTestSuite) TestImportJobShowCmdF() {
 		s.Empty(printer.GetErrorLines())
 		s.Equal(mockJob, printer.GetLines()[0].(*model.Job))
 	})
+
+	s.Run("dry run report", func() {
+		printer.Clean()
+		mockJob := &model.Job{
+			Id:   model.NewId(),
+			Type: model.JobTypeImportProcess,
+			Data: map[string]string{"dry_run": "true"},
+		}
+		report := model.NewImportDryRunReport()
+		report.Add(model.ImportDryRunChange{
+			Line:   3,
+			Entity: "channel",
+			Name:   "team/channel",
+			Action: model.ImportDryRunActionUpdate,
+			Fields: []model.ImportDryRunField{{Name: "display_name", Old: "Channel", New: "Renamed"}},
+		})
+
+		s.client.
+			EXPECT().
+			GetJob(context.TODO(), mockJob.Id).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetImportDryRunReport(context.TODO(), mockJob.Id).
+			Return(report, &model.Response{}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("report", true, "")
+
+		err := importJobShowCmdF(s.client, cmd, []string{mockJob.Id})
+		s.Require().Nil(err)
+		s.Len(printer.GetLines(), 2)
+		s.Empty(printer.GetErrorLines())
+		s.Equal(report, printer.GetLines()[1].(*model.ImportDryRunReport))
+	})
 }
 
 func (s *MmctlUnitTestSuite) TestImportJobListCmdF() {
@@ -219,6 +256,7 @@ func (s *MmctlUnitTestSuite) TestImportProcessCmdF() {
 			"import_file":     importFile,
 			"local_mode":      "false",
 			"extract_content": "false",
+			"dry_run":         "false",
 		},
 	}
 
@@ -233,6 +271,213 @@ func (s *MmctlUnitTestSuite) TestImportProcessCmdF() {
 	s.Len(printer.GetLines(), 1)
 	s.Empty(printer.GetErrorLines())
 	s.Equal(mockJob, printer.GetLines()[0].(*model.Job))
+
+	s.Run("dry run", func() {
+		printer.Clean()
+		mockJob := &model.Job{
+			Type: model.JobTypeImportProcess,
+			Data: map[string]string{
+				"import_file":     importFile,
+				"local_mode":      "false",
+				"extract_content": "true",
+				"dry_run":         "true",
+			},
+		}
+
+		s.client.
+			EXPECT().
+			CreateJob(context.TODO(), mockJob).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("extract-content", true, "")
+		cmd.Flags().Bool("dry-run", true, "")
+
+		err := importProcessCmdF(s.client, cmd, []string{importFile})
+		s.Require().Nil(err)
+		s.Len(printer.GetLines(), 1)
+		s.Empty(printer.GetErrorLines())
+		s.Equal(mockJob, printer.GetLines()[0].(*model.Job))
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestImportMSTeamsProcessCmdF() {
+	printer.Clean()
+	importFile := "msteams_export.zip"
+	mockJob := &model.Job{
+		Type: model.JobTypeImportMSTeams,
+		Data: map[string]string{
+			"import_file":     importFile,
+			"local_mode":      "false",
+			"extract_content": "false",
+			"office365_auth":  "true",
+		},
+	}
+
+	s.client.
+		EXPECT().
+		CreateJob(context.TODO(), mockJob).
+		Return(mockJob, &model.Response{}, nil).
+		Times(1)
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("office365-auth", true, "")
+
+	err := importMSTeamsProcessCmdF(s.client, cmd, []string{importFile})
+	s.Require().Nil(err)
+	s.Len(printer.GetLines(), 1)
+	s.Empty(printer.GetErrorLines())
+	s.Equal(mockJob, printer.GetLines()[0].(*model.Job))
+}
+
+func (s *MmctlUnitTestSuite) TestImportMSTeamsConvertCmdF() {
+	exportFilePath := filepath.Join(os.TempDir(), "msteams_export.zip")
+	importFilePath := filepath.Join(os.TempDir(), "msteams_import.zip")
+	defer os.Remove(exportFilePath)
+	defer os.Remove(importFilePath)
+
+	file, err := os.Create(exportFilePath)
+	s.Require().NoError(err)
+
+	zipWr := zip.NewWriter(file)
+	for name, content := range map[string]string{
+		"users.json": `{"value": [{"id": "u1", "displayName": "Alice", "mail": "alice@example.com"},
+			{"id": "u2", "displayName": "Bob", "mail": "bob@example.com"}]}`,
+		"teams/t1/team.json":                 `{"id": "t1", "displayName": "Sales", "visibility": "private"}`,
+		"teams/t1/members.json":              `[{"userId": "u1", "roles": ["owner"]}, {"userId": "u2"}]`,
+		"teams/t1/channels/c1/channel.json":  `{"id": "c1", "displayName": "General", "membershipType": "standard"}`,
+		"teams/t1/channels/c1/messages.json": `[{"id": "m1", "messageType": "message", "createdDateTime": "2024-01-01T10:00:00Z", "from": {"user": {"id": "u1"}}, "body": {"contentType": "html", "content": "<p>Hello</p>"}}]`,
+	} {
+		wr, wErr := zipWr.Create(name)
+		s.Require().NoError(wErr)
+		_, wErr = wr.Write([]byte(content))
+		s.Require().NoError(wErr)
+	}
+	s.Require().NoError(zipWr.Close())
+	s.Require().NoError(file.Close())
+
+	printer.Clean()
+	err = importMSTeamsConvertCmdF(ImportMSTeamsConvertCmd, []string{exportFilePath, importFilePath})
+	s.Require().Nil(err)
+	s.Empty(printer.GetErrorLines())
+
+	lines := printer.GetLines()
+	s.Require().Len(lines, 4)
+	s.Equal(Statistics{
+		Teams:    1,
+		Channels: 1,
+		Users:    2,
+		Posts:    1,
+	}, lines[1].(Statistics))
+	res := lines[2].(ImportValidationResult)
+	s.Require().Empty(res.Errors)
+	s.Equal(importFilePath, res.FileName)
+	s.Equal("Conversion complete\n", lines[3])
+}
+
+func (s *MmctlUnitTestSuite) TestImportDiscordConvertCmdF() {
+	exportFilePath := filepath.Join(os.TempDir(), "discord_export.zip")
+	importFilePath := filepath.Join(os.TempDir(), "discord_import.zip")
+	defer os.Remove(exportFilePath)
+	defer os.Remove(importFilePath)
+
+	file, err := os.Create(exportFilePath)
+	s.Require().NoError(err)
+
+	zipWr := zip.NewWriter(file)
+	wr, err := zipWr.Create("My Server - general.json")
+	s.Require().NoError(err)
+	_, err = wr.Write([]byte(`{"guild": {"id": "100", "name": "My Server"},
+		"channel": {"id": "200", "type": "GuildTextChat", "name": "general"},
+		"messages": [
+			{"id": "301", "type": "Default", "timestamp": "2024-01-01T10:00:00+00:00", "content": "Hello", "author": {"id": "1", "name": "alice"}},
+			{"id": "302", "type": "Default", "timestamp": "2024-01-01T10:01:00+00:00", "content": "Hi", "author": {"id": "2", "name": "bob"}}
+		]}`))
+	s.Require().NoError(err)
+	s.Require().NoError(zipWr.Close())
+	s.Require().NoError(file.Close())
+
+	printer.Clean()
+	cmd := &cobra.Command{}
+	cmd.Flags().String("email-domain", "example.org", "")
+	err = importDiscordConvertCmdF(cmd, []string{exportFilePath, importFilePath})
+	s.Require().Nil(err)
+	s.Empty(printer.GetErrorLines())
+
+	lines := printer.GetLines()
+	s.Require().Len(lines, 4)
+	s.Equal(Statistics{
+		Teams:    1,
+		Channels: 1,
+		Users:    2,
+		Posts:    2,
+	}, lines[1].(Statistics))
+	res := lines[2].(ImportValidationResult)
+	s.Require().Empty(res.Errors)
+	s.Equal(importFilePath, res.FileName)
+	s.Equal("Conversion complete\n", lines[3])
+}
+
+func (s *MmctlUnitTestSuite) TestImportRocketChatConvertCmdF() {
+	exportFilePath := filepath.Join(os.TempDir(), "rocketchat_export.zip")
+	importFilePath := filepath.Join(os.TempDir(), "rocketchat_import.zip")
+	defer os.Remove(exportFilePath)
+	defer os.Remove(importFilePath)
+
+	file, err := os.Create(exportFilePath)
+	s.Require().NoError(err)
+
+	zipWr := zip.NewWriter(file)
+	for name, content := range map[string]string{
+		"users.json": `{"_id": "u1", "username": "alice", "emails": [{"address": "alice@example.com"}], "active": true, "type": "user"}
+{"_id": "u2", "username": "bob", "emails": [{"address": "bob@example.com"}], "active": true, "type": "user"}`,
+		"rocketchat_room.json":    `{"_id": "GENERAL", "t": "c", "name": "general"}`,
+		"rocketchat_message.json": `{"_id": "m1", "rid": "GENERAL", "msg": "Hello", "ts": {"$date": "2024-01-01T10:00:00.000Z"}, "u": {"_id": "u1", "username": "alice"}}`,
+	} {
+		wr, wErr := zipWr.Create(name)
+		s.Require().NoError(wErr)
+		_, wErr = wr.Write([]byte(content))
+		s.Require().NoError(wErr)
+	}
+	s.Require().NoError(zipWr.Close())
+	s.Require().NoError(file.Close())
+
+	s.Run("invalid team name", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().String("team", "Community!", "")
+		cmd.Flags().String("team-display-name", "", "")
+		cmd.Flags().String("email-domain", "", "")
+
+		err := importRocketChatConvertCmdF(cmd, []string{exportFilePath, importFilePath})
+		s.Require().Error(err)
+	})
+
+	s.Run("convert", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().String("team", "community", "")
+		cmd.Flags().String("team-display-name", "Community", "")
+		cmd.Flags().String("email-domain", "", "")
+
+		err := importRocketChatConvertCmdF(cmd, []string{exportFilePath, importFilePath})
+		s.Require().Nil(err)
+		s.Empty(printer.GetErrorLines())
+
+		lines := printer.GetLines()
+		s.Require().Len(lines, 4)
+		s.Equal(Statistics{
+			Teams:    1,
+			Channels: 1,
+			Users:    2,
+			Posts:    1,
+		}, lines[1].(Statistics))
+		res := lines[2].(ImportValidationResult)
+		s.Require().Empty(res.Errors)
+		s.Equal(importFilePath, res.FileName)
+		s.Equal("Conversion complete\n", lines[3])
+	})
 }
 
 func (s *MmctlUnitTestSuite) TestImportValidateCmdF() {
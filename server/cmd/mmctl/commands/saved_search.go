@@ -0,0 +1,193 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/client"
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/spf13/cobra"
+)
+
+var SavedSearchCmd = &cobra.Command{
+	Use:     "saved-search",
+	Short:   "Management of users' saved searches",
+	Aliases: []string{"savedsearch"},
+}
+
+var SavedSearchListCmd = &cobra.Command{
+	Use:     "list [user]",
+	Example: "  saved-search list testuser",
+	Short:   "List the saved searches of a user",
+	Aliases: []string{"ls"},
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(savedSearchListCmdF),
+}
+
+var SavedSearchCreateCmd = &cobra.Command{
+	Use:     "create [user]",
+	Example: `  saved-search create testuser --name "Deploys" --terms "deploy in:town-square" --alerts`,
+	Short:   "Save a posts search for a user",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(savedSearchCreateCmdF),
+}
+
+var SavedSearchModifyCmd = &cobra.Command{
+	Use:     "modify [user] [savedSearchID]",
+	Example: "  saved-search modify testuser f3d68qkkm7n8xgsfxwuo498rah --alerts=false",
+	Short:   "Modify a saved search of a user",
+	Args:    cobra.ExactArgs(2),
+	RunE:    withClient(savedSearchModifyCmdF),
+}
+
+var SavedSearchDeleteCmd = &cobra.Command{
+	Use:     "delete [user] [savedSearchIDs]",
+	Example: "  saved-search delete testuser f3d68qkkm7n8xgsfxwuo498rah",
+	Short:   "Delete saved searches of a user",
+	Aliases: []string{"rm"},
+	Args:    cobra.MinimumNArgs(2),
+	RunE:    withClient(savedSearchDeleteCmdF),
+}
+
+func init() {
+	SavedSearchCreateCmd.Flags().String("name", "", "Name of the saved search")
+	SavedSearchCreateCmd.Flags().String("terms", "", "Terms of the search, with the same syntax as the search box")
+	SavedSearchCreateCmd.Flags().String("team", "", "Team to search in. All the teams of the user are searched if not set")
+	SavedSearchCreateCmd.Flags().Bool("or", false, "Match the posts containing any of the terms instead of all of them")
+	SavedSearchCreateCmd.Flags().Bool("alerts", false, "Send the new matches of the search to the user")
+	_ = SavedSearchCreateCmd.MarkFlagRequired("name")
+	_ = SavedSearchCreateCmd.MarkFlagRequired("terms")
+
+	SavedSearchModifyCmd.Flags().String("name", "", "Name of the saved search")
+	SavedSearchModifyCmd.Flags().String("terms", "", "Terms of the search, with the same syntax as the search box")
+	SavedSearchModifyCmd.Flags().Bool("or", false, "Match the posts containing any of the terms instead of all of them")
+	SavedSearchModifyCmd.Flags().Bool("alerts", false, "Send the new matches of the search to the user")
+
+	SavedSearchCmd.AddCommand(
+		SavedSearchListCmd,
+		SavedSearchCreateCmd,
+		SavedSearchModifyCmd,
+		SavedSearchDeleteCmd,
+	)
+	RootCmd.AddCommand(SavedSearchCmd)
+}
+
+const savedSearchTemplate = "{{.Id}}: {{.Name}} ({{.Terms}}), alerts enabled: {{.EnableAlerts}}"
+
+func savedSearchListCmdF(c client.Client, command *cobra.Command, args []string) error {
+	user := getUserFromUserArg(c, args[0])
+	if user == nil {
+		return fmt.Errorf("could not retrieve user information of %q", args[0])
+	}
+
+	savedSearches, _, err := c.GetSavedSearches(context.TODO(), user.Id)
+	if err != nil {
+		return fmt.Errorf("could not retrieve the saved searches of %q: %w", args[0], err)
+	}
+
+	if len(savedSearches) == 0 {
+		printer.Print(fmt.Sprintf("There are no saved searches for %q", args[0]))
+		return nil
+	}
+
+	for _, savedSearch := range savedSearches {
+		printer.PrintT(savedSearchTemplate, savedSearch)
+	}
+	return nil
+}
+
+func savedSearchCreateCmdF(c client.Client, command *cobra.Command, args []string) error {
+	user := getUserFromUserArg(c, args[0])
+	if user == nil {
+		return fmt.Errorf("could not retrieve user information of %q", args[0])
+	}
+
+	name, _ := command.Flags().GetString("name")
+	terms, _ := command.Flags().GetString("terms")
+	isOrSearch, _ := command.Flags().GetBool("or")
+	enableAlerts, _ := command.Flags().GetBool("alerts")
+	savedSearch := &model.SavedSearch{
+		Name:         name,
+		Terms:        terms,
+		IsOrSearch:   isOrSearch,
+		EnableAlerts: enableAlerts,
+	}
+
+	if teamArg, _ := command.Flags().GetString("team"); teamArg != "" {
+		team := getTeamFromTeamArg(c, teamArg)
+		if team == nil {
+			return fmt.Errorf("unable to find team %q", teamArg)
+		}
+		savedSearch.TeamId = team.Id
+	}
+
+	created, _, err := c.CreateSavedSearch(context.TODO(), user.Id, savedSearch)
+	if err != nil {
+		return fmt.Errorf("could not create the saved search: %w", err)
+	}
+
+	printer.PrintT(savedSearchTemplate, created)
+	return nil
+}
+
+func savedSearchModifyCmdF(c client.Client, command *cobra.Command, args []string) error {
+	user := getUserFromUserArg(c, args[0])
+	if user == nil {
+		return fmt.Errorf("could not retrieve user information of %q", args[0])
+	}
+
+	patch := &model.SavedSearchPatch{}
+	if command.Flags().Changed("name") {
+		name, _ := command.Flags().GetString("name")
+		patch.Name = &name
+	}
+	if command.Flags().Changed("terms") {
+		terms, _ := command.Flags().GetString("terms")
+		patch.Terms = &terms
+	}
+	if command.Flags().Changed("or") {
+		isOrSearch, _ := command.Flags().GetBool("or")
+		patch.IsOrSearch = &isOrSearch
+	}
+	if command.Flags().Changed("alerts") {
+		enableAlerts, _ := command.Flags().GetBool("alerts")
+		patch.EnableAlerts = &enableAlerts
+	}
+	if patch.Name == nil && patch.Terms == nil && patch.IsOrSearch == nil && patch.EnableAlerts == nil {
+		return errors.New("at least one of the name, terms, or and alerts flags must be set")
+	}
+
+	patched, _, err := c.PatchSavedSearch(context.TODO(), user.Id, args[1], patch)
+	if err != nil {
+		return fmt.Errorf("could not modify the saved search %q: %w", args[1], err)
+	}
+
+	printer.PrintT(savedSearchTemplate, patched)
+	return nil
+}
+
+func savedSearchDeleteCmdF(c client.Client, command *cobra.Command, args []string) error {
+	user := getUserFromUserArg(c, args[0])
+	if user == nil {
+		return fmt.Errorf("could not retrieve user information of %q", args[0])
+	}
+
+	var result *multierror.Error
+	for _, savedSearchID := range args[1:] {
+		if _, err := c.DeleteSavedSearch(context.TODO(), user.Id, savedSearchID); err != nil {
+			printer.PrintError(fmt.Sprintf("could not delete saved search '%v'", savedSearchID))
+			result = multierror.Append(result, fmt.Errorf("could not delete saved search %q: %w", savedSearchID, err))
+			continue
+		}
+		printer.Print(fmt.Sprintf("Saved search %q successfully deleted", savedSearchID))
+	}
+
+	return result.ErrorOrNil()
+}
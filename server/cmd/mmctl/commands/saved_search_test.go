@@ -0,0 +1,202 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/spf13/cobra"
+)
+
+func (s *MmctlUnitTestSuite) TestSavedSearchListCmdF() {
+	mockUser := &model.User{Id: model.NewId(), Username: "user1"}
+
+	s.Run("list saved searches", func() {
+		printer.Clean()
+		mockSavedSearches := []*model.SavedSearch{
+			{Id: model.NewId(), UserId: mockUser.Id, Name: "First", Terms: "hello"},
+			{Id: model.NewId(), UserId: mockUser.Id, Name: "Second", Terms: "world", EnableAlerts: true},
+		}
+
+		s.client.
+			EXPECT().
+			GetUserByUsername(context.TODO(), mockUser.Username, "").
+			Return(mockUser, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetSavedSearches(context.TODO(), mockUser.Id).
+			Return(mockSavedSearches, &model.Response{}, nil).
+			Times(1)
+
+		err := savedSearchListCmdF(s.client, &cobra.Command{}, []string{mockUser.Username})
+		s.Require().Nil(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Equal(mockSavedSearches[0], printer.GetLines()[0])
+		s.Equal(mockSavedSearches[1], printer.GetLines()[1])
+	})
+
+	s.Run("fail to list saved searches", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetUserByUsername(context.TODO(), mockUser.Username, "").
+			Return(mockUser, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetSavedSearches(context.TODO(), mockUser.Id).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := savedSearchListCmdF(s.client, &cobra.Command{}, []string{mockUser.Username})
+		s.Require().Error(err)
+		s.Empty(printer.GetLines())
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestSavedSearchCreateCmdF() {
+	mockUser := &model.User{Id: model.NewId(), Username: "user1"}
+	mockTeam := &model.Team{Id: model.NewId(), Name: "team1"}
+
+	s.Run("create a saved search in a team", func() {
+		printer.Clean()
+		mockSavedSearch := &model.SavedSearch{
+			UserId:       mockUser.Id,
+			TeamId:       mockTeam.Id,
+			Name:         "Deploys",
+			Terms:        "deploy",
+			EnableAlerts: true,
+		}
+
+		s.client.
+			EXPECT().
+			GetUserByUsername(context.TODO(), mockUser.Username, "").
+			Return(mockUser, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeam(context.TODO(), mockTeam.Name, "").
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			GetTeamByName(context.TODO(), mockTeam.Name, "").
+			Return(mockTeam, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			CreateSavedSearch(context.TODO(), mockUser.Id, &model.SavedSearch{
+				TeamId:       mockTeam.Id,
+				Name:         "Deploys",
+				Terms:        "deploy",
+				EnableAlerts: true,
+			}).
+			Return(mockSavedSearch, &model.Response{}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("name", "Deploys", "")
+		cmd.Flags().String("terms", "deploy", "")
+		cmd.Flags().String("team", mockTeam.Name, "")
+		cmd.Flags().Bool("or", false, "")
+		cmd.Flags().Bool("alerts", true, "")
+
+		err := savedSearchCreateCmdF(s.client, cmd, []string{mockUser.Username})
+		s.Require().Nil(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Equal(mockSavedSearch, printer.GetLines()[0])
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestSavedSearchModifyCmdF() {
+	mockUser := &model.User{Id: model.NewId(), Username: "user1"}
+	savedSearchID := model.NewId()
+
+	s.Run("modify the alerts of a saved search", func() {
+		printer.Clean()
+		mockSavedSearch := &model.SavedSearch{Id: savedSearchID, UserId: mockUser.Id, Name: "Deploys", Terms: "deploy"}
+
+		s.client.
+			EXPECT().
+			GetUserByUsername(context.TODO(), mockUser.Username, "").
+			Return(mockUser, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			PatchSavedSearch(context.TODO(), mockUser.Id, savedSearchID, &model.SavedSearchPatch{EnableAlerts: model.NewPointer(false)}).
+			Return(mockSavedSearch, &model.Response{}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("alerts", true, "")
+		s.Require().NoError(cmd.Flags().Set("alerts", "false"))
+
+		err := savedSearchModifyCmdF(s.client, cmd, []string{mockUser.Username, savedSearchID})
+		s.Require().Nil(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Equal(mockSavedSearch, printer.GetLines()[0])
+	})
+
+	s.Run("fail without any change", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetUserByUsername(context.TODO(), mockUser.Username, "").
+			Return(mockUser, &model.Response{}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("alerts", true, "")
+
+		err := savedSearchModifyCmdF(s.client, cmd, []string{mockUser.Username, savedSearchID})
+		s.Require().Error(err)
+		s.Empty(printer.GetLines())
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestSavedSearchDeleteCmdF() {
+	mockUser := &model.User{Id: model.NewId(), Username: "user1"}
+	savedSearchID1 := model.NewId()
+	savedSearchID2 := model.NewId()
+
+	s.Run("delete saved searches", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetUserByUsername(context.TODO(), mockUser.Username, "").
+			Return(mockUser, &model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			DeleteSavedSearch(context.TODO(), mockUser.Id, savedSearchID1).
+			Return(&model.Response{}, nil).
+			Times(1)
+
+		s.client.
+			EXPECT().
+			DeleteSavedSearch(context.TODO(), mockUser.Id, savedSearchID2).
+			Return(&model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := savedSearchDeleteCmdF(s.client, &cobra.Command{}, []string{mockUser.Username, savedSearchID1, savedSearchID2})
+		s.Require().Error(err)
+		s.Len(printer.GetLines(), 1)
+		s.Len(printer.GetErrorLines(), 1)
+	})
+}
@@ -77,12 +77,41 @@ var SystemSupportPacketCmd = &cobra.Command{
 	RunE:    withClient(systemSupportPacketCmdF),
 }
 
+var SystemCacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Management of the caches",
+	Long:  "Management of the caches of the server",
+}
+
+var SystemCacheListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List the caches",
+	Long:    "List the caches of the server along with their size, item count and hit ratio",
+	Example: `  system cache list`,
+	Args:    cobra.NoArgs,
+	RunE:    withClient(systemCacheListCmdF),
+}
+
+var SystemCachePurgeCmd = &cobra.Command{
+	Use:     "purge [cache_name]",
+	Short:   "Purge a cache",
+	Long:    "Purge the named cache on every node of the cluster",
+	Example: `  system cache purge Status`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(systemCachePurgeCmdF),
+}
+
 func init() {
 	SystemSetBusyCmd.Flags().UintP("seconds", "s", 3600, "Number of seconds until server is automatically marked as not busy.")
 	_ = SystemSetBusyCmd.MarkFlagRequired("seconds")
 
 	SystemSupportPacketCmd.Flags().StringP("output-file", "o", "", "Define the output file name")
 
+	SystemCacheCmd.AddCommand(
+		SystemCacheListCmd,
+		SystemCachePurgeCmd,
+	)
+
 	SystemCmd.AddCommand(
 		SystemGetBusyCmd,
 		SystemSetBusyCmd,
@@ -90,6 +119,7 @@ func init() {
 		SystemVersionCmd,
 		SystemStatusCmd,
 		SystemSupportPacketCmd,
+		SystemCacheCmd,
 	)
 	RootCmd.AddCommand(SystemCmd)
 }
@@ -172,6 +202,29 @@ Filestore Status: {{.filestore_status}}`, status)
 	return nil
 }
 
+func systemCacheListCmdF(c client.Client, cmd *cobra.Command, _ []string) error {
+	caches, _, err := c.GetCaches(context.TODO())
+	if err != nil {
+		return fmt.Errorf("unable to get the caches: %w", err)
+	}
+
+	for _, cache := range caches {
+		printer.PrintT("{{.Name}} ({{.Type}}) size:{{.Size}} items:{{.ItemCount}} hits:{{.Hits}} misses:{{.Misses}} hit ratio:{{printf \"%.2f\" .HitRatio}}", cache)
+	}
+	return nil
+}
+
+func systemCachePurgeCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	printer.SetSingle(true)
+
+	if _, err := c.PurgeCache(context.TODO(), args[0]); err != nil {
+		return fmt.Errorf("unable to purge the cache %q: %w", args[0], err)
+	}
+
+	printer.PrintT("Cache {{.name}} purged", map[string]string{"name": args[0]})
+	return nil
+}
+
 func systemSupportPacketCmdF(c client.Client, cmd *cobra.Command, _ []string) error {
 	printer.SetSingle(true)
 
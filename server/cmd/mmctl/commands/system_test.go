@@ -298,3 +298,71 @@ func (s *MmctlUnitTestSuite) TestSupportPacketCmdF() {
 		s.Require().Equal(printer.GetLines()[0], "Downloading Support Packet")
 	})
 }
+
+func (s *MmctlUnitTestSuite) TestSystemCacheListCmd() {
+	s.Run("List the caches", func() {
+		printer.Clean()
+		caches := []*model.CacheInfo{
+			{Name: "Status", Type: model.CacheTypeLRU, Size: 10000, ItemCount: 10, Hits: 3, Misses: 1, HitRatio: 0.75},
+			{Name: "Role", Type: model.CacheTypeLRU, Size: 20000},
+		}
+
+		s.client.
+			EXPECT().
+			GetCaches(context.TODO()).
+			Return(caches, &model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		err := systemCacheListCmdF(s.client, &cobra.Command{}, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(caches[0], printer.GetLines()[0])
+		s.Require().Equal(caches[1], printer.GetLines()[1])
+		s.Require().Len(printer.GetErrorLines(), 0)
+	})
+
+	s.Run("List the caches with error", func() {
+		printer.Clean()
+		s.client.
+			EXPECT().
+			GetCaches(context.TODO()).
+			Return(nil, &model.Response{StatusCode: http.StatusForbidden}, errors.New("mock error")).
+			Times(1)
+
+		err := systemCacheListCmdF(s.client, &cobra.Command{}, []string{})
+		s.Require().Error(err)
+		s.Require().Len(printer.GetLines(), 0)
+		s.Require().Len(printer.GetErrorLines(), 0)
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestSystemCachePurgeCmd() {
+	s.Run("Purge a cache", func() {
+		printer.Clean()
+		s.client.
+			EXPECT().
+			PurgeCache(context.TODO(), "Status").
+			Return(&model.Response{StatusCode: http.StatusOK}, nil).
+			Times(1)
+
+		err := systemCachePurgeCmdF(s.client, &cobra.Command{}, []string{"Status"})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(map[string]string{"name": "Status"}, printer.GetLines()[0])
+		s.Require().Len(printer.GetErrorLines(), 0)
+	})
+
+	s.Run("Purge an unknown cache", func() {
+		printer.Clean()
+		s.client.
+			EXPECT().
+			PurgeCache(context.TODO(), "Unknown").
+			Return(&model.Response{StatusCode: http.StatusNotFound}, errors.New("mock error")).
+			Times(1)
+
+		err := systemCachePurgeCmdF(s.client, &cobra.Command{}, []string{"Unknown"})
+		s.Require().Error(err)
+		s.Require().Len(printer.GetLines(), 0)
+		s.Require().Len(printer.GetErrorLines(), 0)
+	})
+}
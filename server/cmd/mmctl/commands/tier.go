@@ -0,0 +1,167 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/client"
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/spf13/cobra"
+)
+
+var TierCmd = &cobra.Command{
+	Use:   "tier",
+	Short: "Management of tiered file storage.",
+}
+
+var TierUsageCmd = &cobra.Command{
+	Use:     "usage",
+	Example: "  tier usage",
+	Short:   "Show how many files, and how many bytes, each storage tier holds.",
+	Args:    cobra.NoArgs,
+	RunE:    withClient(tierUsageCmdF),
+}
+
+var TierRunCmd = &cobra.Command{
+	Use:     "run",
+	Example: "  tier run",
+	Short:   "Start a job that moves files past the configured thresholds to the cold tier.",
+	Args:    cobra.NoArgs,
+	RunE:    withClient(tierRunCmdF),
+}
+
+var TierMoveCmd = &cobra.Command{
+	Use:     "move [fileIDs]",
+	Example: "  tier move f3d68qkkm7n8xgsfxwuo498rah --tier hot",
+	Short:   "Start a job that moves the given files to a storage tier.",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    withClient(tierMoveCmdF),
+}
+
+var TierJobCmd = &cobra.Command{
+	Use:   "job",
+	Short: "List and show file tiering jobs",
+}
+
+var TierJobListCmd = &cobra.Command{
+	Use:     "list",
+	Example: "  tier job list",
+	Short:   "List file tiering jobs",
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE:    withClient(tierJobListCmdF),
+}
+
+var TierJobShowCmd = &cobra.Command{
+	Use:     "show [tierJobID]",
+	Example: " tier job show f3d68qkkm7n8xgsfxwuo498rah",
+	Short:   "Show file tiering job",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(tierJobShowCmdF),
+}
+
+func init() {
+	TierMoveCmd.Flags().String("tier", model.FileStorageTierCold, "The storage tier to move the files to, either \"hot\" or \"cold\".")
+	TierJobListCmd.Flags().Int("page", 0, "Page number to fetch for the list of file tiering jobs")
+	TierJobListCmd.Flags().Int("per-page", DefaultPageSize, "Number of file tiering jobs to be fetched")
+	TierJobListCmd.Flags().Bool("all", false, "Fetch all file tiering jobs. --page flag will be ignore if provided")
+	TierJobCmd.AddCommand(
+		TierJobListCmd,
+		TierJobShowCmd,
+	)
+	TierCmd.AddCommand(
+		TierUsageCmd,
+		TierRunCmd,
+		TierMoveCmd,
+		TierJobCmd,
+	)
+	RootCmd.AddCommand(TierCmd)
+}
+
+func tierUsageCmdF(c client.Client, command *cobra.Command, args []string) error {
+	usage, _, err := c.GetFileStorageTierUsage(context.TODO())
+	if err != nil {
+		return fmt.Errorf("failed to get storage tier usage: %w", err)
+	}
+
+	for _, tierUsage := range usage {
+		printer.PrintT("{{.Tier}}: {{.FileCount}} files, {{.TotalSize}} bytes", tierUsage)
+	}
+
+	return nil
+}
+
+func tierRunCmdF(c client.Client, command *cobra.Command, args []string) error {
+	job, _, err := c.CreateJob(context.TODO(), &model.Job{
+		Type: model.JobTypeFileTiering,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create file tiering job: %w", err)
+	}
+
+	printer.PrintT("File tiering job successfully created, ID: {{.Id}}", job)
+
+	return nil
+}
+
+func tierMoveCmdF(c client.Client, command *cobra.Command, args []string) error {
+	tier, err := command.Flags().GetString("tier")
+	if err != nil {
+		return err
+	}
+	if !model.IsValidFileStorageTier(tier) {
+		return errors.New("invalid storage tier " + tier)
+	}
+
+	for _, fileID := range args {
+		if !model.IsValidId(fileID) {
+			return errors.New("invalid file ID " + fileID)
+		}
+	}
+
+	job, _, err := c.CreateJob(context.TODO(), &model.Job{
+		Type: model.JobTypeFileTiering,
+		Data: map[string]string{
+			"file_ids": strings.Join(args, ","),
+			"tier":     tier,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create file tiering job: %w", err)
+	}
+
+	printer.PrintT("File tiering job successfully created, ID: {{.Id}}", job)
+
+	return nil
+}
+
+func tierJobShowCmdF(c client.Client, command *cobra.Command, args []string) error {
+	job, _, err := c.GetJob(context.TODO(), args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get file tiering job: %w", err)
+	}
+	printTierJob(job)
+	return nil
+}
+
+func tierJobListCmdF(c client.Client, command *cobra.Command, args []string) error {
+	return jobListCmdF(c, command, model.JobTypeFileTiering, "")
+}
+
+func printTierJob(job *model.Job) {
+	if job.StartAt > 0 {
+		printer.PrintT(fmt.Sprintf("  ID: {{.Id}}\n  Status: {{.Status}}\n  Created: %s\n  Started: %s\n  Moved: %s\n  Errors: %s\n",
+			time.Unix(job.CreateAt/1000, 0), time.Unix(job.StartAt/1000, 0), job.Data["moved"], job.Data["errors"]), job)
+	} else {
+		printer.PrintT(fmt.Sprintf("  ID: {{.Id}}\n  Status: {{.Status}}\n  Created: %s\n\n",
+			time.Unix(job.CreateAt/1000, 0)), job)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mattermost/mattermost/server/v8/cmd/mmctl/printer"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/spf13/cobra"
+)
+
+func (s *MmctlUnitTestSuite) TestTierUsageCmdF() {
+	s.Run("show usage", func() {
+		printer.Clean()
+		mockUsage := []*model.FileStorageTierUsage{
+			{Tier: model.FileStorageTierCold, FileCount: 2, TotalSize: 2048},
+			{Tier: model.FileStorageTierHot, FileCount: 5, TotalSize: 1024},
+		}
+
+		s.client.
+			EXPECT().
+			GetFileStorageTierUsage(context.TODO()).
+			Return(mockUsage, &model.Response{}, nil).
+			Times(1)
+
+		err := tierUsageCmdF(s.client, &cobra.Command{}, nil)
+		s.Require().Nil(err)
+		s.Len(printer.GetLines(), 2)
+		s.Empty(printer.GetErrorLines())
+		s.Equal(mockUsage[0], printer.GetLines()[0].(*model.FileStorageTierUsage))
+		s.Equal(mockUsage[1], printer.GetLines()[1].(*model.FileStorageTierUsage))
+	})
+
+	s.Run("fail to get usage", func() {
+		printer.Clean()
+
+		s.client.
+			EXPECT().
+			GetFileStorageTierUsage(context.TODO()).
+			Return(nil, &model.Response{}, errors.New("mock error")).
+			Times(1)
+
+		err := tierUsageCmdF(s.client, &cobra.Command{}, nil)
+		s.Require().Error(err)
+		s.Empty(printer.GetLines())
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestTierMoveCmdF() {
+	fileID1 := model.NewId()
+	fileID2 := model.NewId()
+
+	s.Run("move files", func() {
+		printer.Clean()
+		mockJob := &model.Job{
+			Type: model.JobTypeFileTiering,
+			Data: map[string]string{
+				"file_ids": fileID1 + "," + fileID2,
+				"tier":     model.FileStorageTierHot,
+			},
+		}
+
+		s.client.
+			EXPECT().
+			CreateJob(context.TODO(), mockJob).
+			Return(mockJob, &model.Response{}, nil).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("tier", model.FileStorageTierHot, "")
+
+		err := tierMoveCmdF(s.client, cmd, []string{fileID1, fileID2})
+		s.Require().Nil(err)
+		s.Len(printer.GetLines(), 1)
+		s.Empty(printer.GetErrorLines())
+		s.Equal(mockJob, printer.GetLines()[0].(*model.Job))
+	})
+
+	s.Run("invalid tier", func() {
+		printer.Clean()
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("tier", "lukewarm", "")
+
+		err := tierMoveCmdF(s.client, cmd, []string{fileID1})
+		s.Require().EqualError(err, "invalid storage tier lukewarm")
+		s.Empty(printer.GetLines())
+	})
+
+	s.Run("invalid file ID", func() {
+		printer.Clean()
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("tier", model.FileStorageTierCold, "")
+
+		err := tierMoveCmdF(s.client, cmd, []string{"notanid"})
+		s.Require().EqualError(err, "invalid file ID notanid")
+		s.Empty(printer.GetLines())
+	})
+}
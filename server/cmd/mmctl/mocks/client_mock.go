@@ -278,6 +278,22 @@ func (mr *MockClientMockRecorder) CreatePost(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePost", reflect.TypeOf((*MockClient)(nil).CreatePost), arg0, arg1)
 }
 
+// CreateSavedSearch mocks base method.
+func (m *MockClient) CreateSavedSearch(arg0 context.Context, arg1 string, arg2 *model.SavedSearch) (*model.SavedSearch, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSavedSearch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.SavedSearch)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateSavedSearch indicates an expected call of CreateSavedSearch.
+func (mr *MockClientMockRecorder) CreateSavedSearch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSavedSearch", reflect.TypeOf((*MockClient)(nil).CreateSavedSearch), arg0, arg1, arg2)
+}
+
 // CreateTeam mocks base method.
 func (m *MockClient) CreateTeam(arg0 context.Context, arg1 *model.Team) (*model.Team, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -447,6 +463,21 @@ func (mr *MockClientMockRecorder) DeletePreferences(arg0, arg1, arg2 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePreferences", reflect.TypeOf((*MockClient)(nil).DeletePreferences), arg0, arg1, arg2)
 }
 
+// DeleteSavedSearch mocks base method.
+func (m *MockClient) DeleteSavedSearch(arg0 context.Context, arg1, arg2 string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSavedSearch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSavedSearch indicates an expected call of DeleteSavedSearch.
+func (mr *MockClientMockRecorder) DeleteSavedSearch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSavedSearch", reflect.TypeOf((*MockClient)(nil).DeleteSavedSearch), arg0, arg1, arg2)
+}
+
 // DemoteUserToGuest mocks base method.
 func (m *MockClient) DemoteUserToGuest(arg0 context.Context, arg1 string) (*model.Response, error) {
 	m.ctrl.T.Helper()
@@ -652,6 +683,22 @@ func (mr *MockClientMockRecorder) GetBotsOrphaned(arg0, arg1, arg2, arg3 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBotsOrphaned", reflect.TypeOf((*MockClient)(nil).GetBotsOrphaned), arg0, arg1, arg2, arg3)
 }
 
+// GetCaches mocks base method.
+func (m *MockClient) GetCaches(arg0 context.Context) ([]*model.CacheInfo, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCaches", arg0)
+	ret0, _ := ret[0].([]*model.CacheInfo)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCaches indicates an expected call of GetCaches.
+func (mr *MockClientMockRecorder) GetCaches(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCaches", reflect.TypeOf((*MockClient)(nil).GetCaches), arg0)
+}
+
 // GetChannel mocks base method.
 func (m *MockClient) GetChannel(arg0 context.Context, arg1, arg2 string) (*model.Channel, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -796,6 +843,22 @@ func (mr *MockClientMockRecorder) GetDeletedChannelsForTeam(arg0, arg1, arg2, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedChannelsForTeam", reflect.TypeOf((*MockClient)(nil).GetDeletedChannelsForTeam), arg0, arg1, arg2, arg3, arg4)
 }
 
+// GetFileStorageTierUsage mocks base method.
+func (m *MockClient) GetFileStorageTierUsage(arg0 context.Context) ([]*model.FileStorageTierUsage, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileStorageTierUsage", arg0)
+	ret0, _ := ret[0].([]*model.FileStorageTierUsage)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFileStorageTierUsage indicates an expected call of GetFileStorageTierUsage.
+func (mr *MockClientMockRecorder) GetFileStorageTierUsage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileStorageTierUsage", reflect.TypeOf((*MockClient)(nil).GetFileStorageTierUsage), arg0)
+}
+
 // GetGroupsByChannel mocks base method.
 func (m *MockClient) GetGroupsByChannel(arg0 context.Context, arg1 string, arg2 model.GroupSearchOpts) ([]*model.GroupWithSchemeAdmin, int, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -830,6 +893,22 @@ func (mr *MockClientMockRecorder) GetGroupsByTeam(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupsByTeam", reflect.TypeOf((*MockClient)(nil).GetGroupsByTeam), arg0, arg1, arg2)
 }
 
+// GetImportDryRunReport mocks base method.
+func (m *MockClient) GetImportDryRunReport(arg0 context.Context, arg1 string) (*model.ImportDryRunReport, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportDryRunReport", arg0, arg1)
+	ret0, _ := ret[0].(*model.ImportDryRunReport)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetImportDryRunReport indicates an expected call of GetImportDryRunReport.
+func (mr *MockClientMockRecorder) GetImportDryRunReport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportDryRunReport", reflect.TypeOf((*MockClient)(nil).GetImportDryRunReport), arg0, arg1)
+}
+
 // GetIncomingWebhook mocks base method.
 func (m *MockClient) GetIncomingWebhook(arg0 context.Context, arg1, arg2 string) (*model.IncomingWebhook, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1278,6 +1357,22 @@ func (mr *MockClientMockRecorder) GetRoleByName(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleByName", reflect.TypeOf((*MockClient)(nil).GetRoleByName), arg0, arg1)
 }
 
+// GetSavedSearches mocks base method.
+func (m *MockClient) GetSavedSearches(arg0 context.Context, arg1 string) ([]*model.SavedSearch, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSavedSearches", arg0, arg1)
+	ret0, _ := ret[0].([]*model.SavedSearch)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSavedSearches indicates an expected call of GetSavedSearches.
+func (mr *MockClientMockRecorder) GetSavedSearches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSavedSearches", reflect.TypeOf((*MockClient)(nil).GetSavedSearches), arg0, arg1)
+}
+
 // GetServerBusy mocks base method.
 func (m *MockClient) GetServerBusy(arg0 context.Context) (*model.ServerBusyState, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1736,6 +1831,22 @@ func (mr *MockClientMockRecorder) PatchRole(arg0, arg1, arg2 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchRole", reflect.TypeOf((*MockClient)(nil).PatchRole), arg0, arg1, arg2)
 }
 
+// PatchSavedSearch mocks base method.
+func (m *MockClient) PatchSavedSearch(arg0 context.Context, arg1, arg2 string, arg3 *model.SavedSearchPatch) (*model.SavedSearch, *model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchSavedSearch", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.SavedSearch)
+	ret1, _ := ret[1].(*model.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PatchSavedSearch indicates an expected call of PatchSavedSearch.
+func (mr *MockClientMockRecorder) PatchSavedSearch(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchSavedSearch", reflect.TypeOf((*MockClient)(nil).PatchSavedSearch), arg0, arg1, arg2, arg3)
+}
+
 // PatchTeam mocks base method.
 func (m *MockClient) PatchTeam(arg0 context.Context, arg1 string, arg2 *model.TeamPatch) (*model.Team, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -1842,6 +1953,21 @@ func (mr *MockClientMockRecorder) PromoteGuestToUser(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromoteGuestToUser", reflect.TypeOf((*MockClient)(nil).PromoteGuestToUser), arg0, arg1)
 }
 
+// PurgeCache mocks base method.
+func (m *MockClient) PurgeCache(arg0 context.Context, arg1 string) (*model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeCache", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeCache indicates an expected call of PurgeCache.
+func (mr *MockClientMockRecorder) PurgeCache(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeCache", reflect.TypeOf((*MockClient)(nil).PurgeCache), arg0, arg1)
+}
+
 // RegenOutgoingHookToken mocks base method.
 func (m *MockClient) RegenOutgoingHookToken(arg0 context.Context, arg1 string) (*model.OutgoingWebhook, *model.Response, error) {
 	m.ctrl.T.Helper()
@@ -40,6 +40,14 @@ var configSensitivePaths = map[string]bool{
 	"LdapSettings.BindPassword":                              true,
 	"FileSettings.PublicLinkSalt":                            true,
 	"FileSettings.AmazonS3SecretAccessKey":                   true,
+	"FileSettings.EncryptionKey":                             true,
+	"FileSettings.EncryptionRetiredKeys":                     true,
+	"FileSettings.WebDAVPassword":                            true,
+	"FileSettings.SFTPPassword":                              true,
+	"FileSettings.SFTPPrivateKey":                            true,
+	"FileSettings.ExportWebDAVPassword":                      true,
+	"FileSettings.ExportSFTPPassword":                        true,
+	"FileSettings.ExportSFTPPrivateKey":                      true,
 	"SqlSettings.DataSource":                                 true,
 	"SqlSettings.AtRestEncryptKey":                           true,
 	"SqlSettings.DataSourceReplicas":                         true,
@@ -53,6 +61,7 @@ var configSensitivePaths = map[string]bool{
 	"MessageExportSettings.GlobalRelaySettings.SMTPUsername": true,
 	"MessageExportSettings.GlobalRelaySettings.SMTPPassword": true,
 	"MessageExportSettings.GlobalRelaySettings.EmailAddress": true,
+	"MessageExportSettings.WebhookSettings.SigningSecret":    true,
 	"ServiceSettings.SplitKey":                               true,
 	"PluginSettings.Plugins":                                 true,
 }
@@ -33,6 +33,34 @@ func desanitize(actual, target *model.Config) {
 	if *target.FileSettings.AmazonS3SecretAccessKey == model.FakeSetting {
 		target.FileSettings.AmazonS3SecretAccessKey = actual.FileSettings.AmazonS3SecretAccessKey
 	}
+	if target.FileSettings.EncryptionKey != nil && *target.FileSettings.EncryptionKey == model.FakeSetting {
+		target.FileSettings.EncryptionKey = actual.FileSettings.EncryptionKey
+	}
+	if len(target.FileSettings.EncryptionRetiredKeys) == len(actual.FileSettings.EncryptionRetiredKeys) {
+		for i, value := range target.FileSettings.EncryptionRetiredKeys {
+			if value == model.FakeSetting {
+				target.FileSettings.EncryptionRetiredKeys[i] = actual.FileSettings.EncryptionRetiredKeys[i]
+			}
+		}
+	}
+	if target.FileSettings.WebDAVPassword != nil && *target.FileSettings.WebDAVPassword == model.FakeSetting {
+		target.FileSettings.WebDAVPassword = actual.FileSettings.WebDAVPassword
+	}
+	if target.FileSettings.SFTPPassword != nil && *target.FileSettings.SFTPPassword == model.FakeSetting {
+		target.FileSettings.SFTPPassword = actual.FileSettings.SFTPPassword
+	}
+	if target.FileSettings.SFTPPrivateKey != nil && *target.FileSettings.SFTPPrivateKey == model.FakeSetting {
+		target.FileSettings.SFTPPrivateKey = actual.FileSettings.SFTPPrivateKey
+	}
+	if target.FileSettings.ExportWebDAVPassword != nil && *target.FileSettings.ExportWebDAVPassword == model.FakeSetting {
+		target.FileSettings.ExportWebDAVPassword = actual.FileSettings.ExportWebDAVPassword
+	}
+	if target.FileSettings.ExportSFTPPassword != nil && *target.FileSettings.ExportSFTPPassword == model.FakeSetting {
+		target.FileSettings.ExportSFTPPassword = actual.FileSettings.ExportSFTPPassword
+	}
+	if target.FileSettings.ExportSFTPPrivateKey != nil && *target.FileSettings.ExportSFTPPrivateKey == model.FakeSetting {
+		target.FileSettings.ExportSFTPPrivateKey = actual.FileSettings.ExportSFTPPrivateKey
+	}
 
 	if *target.EmailSettings.SMTPPassword == model.FakeSetting {
 		target.EmailSettings.SMTPPassword = actual.EmailSettings.SMTPPassword
@@ -85,6 +113,10 @@ func desanitize(actual, target *model.Config) {
 		*target.MessageExportSettings.GlobalRelaySettings.SMTPPassword = *actual.MessageExportSettings.GlobalRelaySettings.SMTPPassword
 	}
 
+	if *target.MessageExportSettings.WebhookSettings.SigningSecret == model.FakeSetting {
+		*target.MessageExportSettings.WebhookSettings.SigningSecret = *actual.MessageExportSettings.WebhookSettings.SigningSecret
+	}
+
 	if *target.ServiceSettings.SplitKey == model.FakeSetting {
 		*target.ServiceSettings.SplitKey = *actual.ServiceSettings.SplitKey
 	}
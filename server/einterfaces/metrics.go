@@ -24,6 +24,7 @@ type MetricsInterface interface {
 
 	IncrementHTTPRequest()
 	IncrementHTTPError()
+	IncrementRateLimitRejection(policy string)
 
 	IncrementClusterRequest()
 	ObserveClusterRequestDuration(elapsed float64)
@@ -55,6 +56,8 @@ type MetricsInterface interface {
 
 	AddMemCacheHitCounter(cacheName string, amount float64)
 	AddMemCacheMissCounter(cacheName string, amount float64)
+	IncrementCacheLayerHitCounter(cacheName, layer string)
+	IncrementCacheLayerMissCounter(cacheName, layer string)
 
 	IncrementPostsSearchCounter()
 	ObservePostsSearchDuration(elapsed float64)
@@ -83,6 +83,16 @@ func (_m *MetricsInterface) IncrementClientLongTasks(platform string, agent stri
 	_m.Called(platform, agent, userID, inc)
 }
 
+// IncrementCacheLayerHitCounter provides a mock function with given fields: cacheName, layer
+func (_m *MetricsInterface) IncrementCacheLayerHitCounter(cacheName string, layer string) {
+	_m.Called(cacheName, layer)
+}
+
+// IncrementCacheLayerMissCounter provides a mock function with given fields: cacheName, layer
+func (_m *MetricsInterface) IncrementCacheLayerMissCounter(cacheName string, layer string) {
+	_m.Called(cacheName, layer)
+}
+
 // IncrementClusterEventType provides a mock function with given fields: eventType
 func (_m *MetricsInterface) IncrementClusterEventType(eventType model.ClusterEvent) {
 	_m.Called(eventType)
@@ -118,6 +128,11 @@ func (_m *MetricsInterface) IncrementHTTPError() {
 	_m.Called()
 }
 
+// IncrementRateLimitRejection provides a mock function with given fields: policy
+func (_m *MetricsInterface) IncrementRateLimitRejection(policy string) {
+	_m.Called(policy)
+}
+
 // IncrementHTTPRequest provides a mock function with given fields:
 func (_m *MetricsInterface) IncrementHTTPRequest() {
 	_m.Called()
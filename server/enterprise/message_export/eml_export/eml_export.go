@@ -0,0 +1,453 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.enterprise for license information.
+
+package eml_export
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	gomail "gopkg.in/mail.v2"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/utils"
+	"github.com/mattermost/mattermost/server/v8/enterprise/internal/file"
+	"github.com/mattermost/mattermost/server/v8/enterprise/message_export/shared"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+)
+
+const (
+	EmlWarningFilename = "warning.txt"
+
+	MsgTypeHeader      = "X-Mattermost-MsgType"
+	ChannelIDHeader    = "X-Mattermost-ChannelID"
+	ChannelNameHeader  = "X-Mattermost-ChannelName"
+	ChannelTypeHeader  = "X-Mattermost-ChannelType"
+	TeamNameHeader     = "X-Mattermost-TeamName"
+	PostIDHeader       = "X-Mattermost-PostID"
+	UpdateTypeHeader   = "X-Mattermost-UpdateType"
+	UserTypeHeader     = "X-Mattermost-UserType"
+	EditedByPostHeader = "X-Mattermost-EditedByPostID"
+	PreviewsPostHeader = "X-Mattermost-PreviewsPostID"
+
+	MsgTypePost       = "post"
+	MsgTypeMembership = "membership"
+
+	// defaultMessageDomain is the domain of the message ids and channel addresses when the site URL is not set.
+	defaultMessageDomain = "mattermost.local"
+	maxSubjectRunes      = 80
+)
+
+// Message is a post, or the summary of the membership changes of a channel during the export period, rendered as an
+// email.
+type Message struct {
+	Channel    *shared.ChannelExport
+	Post       *shared.PostExport // nil for the membership summary
+	Time       int64              // utc timestamp (milliseconds), creation time of the post or time of its update
+	Recipients []shared.MembershipMapUser
+}
+
+// EmlExport renders each post as an RFC 5322 email, addressed to the members of the channel at the time of the post.
+// The replies reference their root post, and the attachments of the posts are attached to the emails. The membership
+// changes of each channel are summarized in a separate email. The emails are exported as one .eml file per email or,
+// for the mbox export type, as one mbox file per channel.
+func EmlExport(rctx request.CTX, p shared.ExportParams) (shared.RunExportResults, error) {
+	exportData, err := shared.GetGenericExportData(p)
+	results := exportData.Results
+	if err != nil {
+		return results, err
+	}
+
+	domain := messageDomain(p.Config)
+
+	temp, err := os.CreateTemp("", "compliance-export-batch-*.zip")
+	if err != nil {
+		return results, fmt.Errorf("unable to create temporary EML export file: %w", err)
+	}
+	defer file.DeleteTemp(rctx.Logger(), temp)
+	zipFile := zip.NewWriter(temp)
+
+	channels := exportData.Exports
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].ChannelId < channels[j].ChannelId
+	})
+
+	var missingFiles []string
+	for i := range channels {
+		channel := &channels[i]
+		messages := ChannelMessages(channel, p.ChannelMemberHistories[channel.ChannelId])
+
+		if p.ExportType == model.ComplianceExportTypeMbox {
+			var mboxFile io.Writer
+			mboxFile, err = zipFile.Create(fmt.Sprintf("%s - (%s).mbox", channel.ChannelName, channel.ChannelId))
+			if err != nil {
+				return results, fmt.Errorf("unable to create the mbox file: %w", err)
+			}
+			mbox := newMboxWriter(mboxFile)
+			for _, message := range messages {
+				if err = mbox.startMessage(messageSender(message).Email, time.UnixMilli(message.Time)); err != nil {
+					return results, fmt.Errorf("unable to write the mbox file: %w", err)
+				}
+				var warnings []string
+				if warnings, err = WriteMessage(rctx, p.FileAttachmentBackend, domain, message, mbox); err != nil {
+					return results, err
+				}
+				missingFiles = append(missingFiles, warnings...)
+				if err = mbox.endMessage(); err != nil {
+					return results, fmt.Errorf("unable to write the mbox file: %w", err)
+				}
+			}
+			continue
+		}
+
+		for _, message := range messages {
+			var emlFile io.Writer
+			emlFile, err = zipFile.Create(path.Join(fmt.Sprintf("%s - (%s)", channel.ChannelName, channel.ChannelId),
+				fmt.Sprintf("%d-%s.eml", message.Time, messageLocalID(message))))
+			if err != nil {
+				return results, fmt.Errorf("unable to create the eml file: %w", err)
+			}
+			var warnings []string
+			if warnings, err = WriteMessage(rctx, p.FileAttachmentBackend, domain, message, emlFile); err != nil {
+				return results, err
+			}
+			missingFiles = append(missingFiles, warnings...)
+		}
+	}
+
+	results.NumWarnings = len(missingFiles)
+	if results.NumWarnings > 0 {
+		warningFile, _ := zipFile.Create(EmlWarningFilename)
+		for _, value := range missingFiles {
+			_, err = warningFile.Write([]byte(value + "\n"))
+			if err != nil {
+				return results, fmt.Errorf("unable to create the warning file: %w", err)
+			}
+		}
+	}
+
+	metadataFile, err := zipFile.Create("metadata.json")
+	if err != nil {
+		return results, fmt.Errorf("unable to create the zip file: %w", err)
+	}
+	data, err := json.MarshalIndent(exportData.Metadata, "", "  ")
+	if err != nil {
+		return results, fmt.Errorf("unable to convert metadata to json: %w", err)
+	}
+	_, err = metadataFile.Write(data)
+	if err != nil {
+		return results, fmt.Errorf("unable to add metadata file to the zip file: %w", err)
+	}
+	err = zipFile.Close()
+	if err != nil {
+		return results, fmt.Errorf("unable to close the zip file: %w", err)
+	}
+
+	_, err = temp.Seek(0, 0)
+	if err != nil {
+		return results, fmt.Errorf("unable to seek to start of export file: %w", err)
+	}
+
+	// Try to write the file without a timeout due to the potential size of the file.
+	_, err = filestore.TryWriteFileContext(rctx.Context(), p.ExportBackend, temp, p.BatchPath)
+	if err != nil {
+		return results, fmt.Errorf("unable to write the eml file: %w", err)
+	}
+	return results, nil
+}
+
+// ChannelMessages returns the messages of the channel ordered by time: the summary of the membership changes of the
+// channel first, if any, then the posts. Each post is addressed to the members of the channel at the time of the post,
+// computed by replaying the channel member histories.
+func ChannelMessages(channel *shared.ChannelExport, channelMemberHistories []*model.ChannelMemberHistoryResult) []*Message {
+	type membershipEvent struct {
+		time int64
+		join bool
+		user shared.MembershipMapUser
+	}
+	events := make([]membershipEvent, 0, len(channelMemberHistories)*2)
+	for _, cmh := range channelMemberHistories {
+		user := shared.MembershipMapUser{UserId: cmh.UserId, Email: cmh.UserEmail, Username: cmh.Username}
+		events = append(events, membershipEvent{time: cmh.JoinTime, join: true, user: user})
+		if cmh.LeaveTime != nil {
+			events = append(events, membershipEvent{time: *cmh.LeaveTime, join: false, user: user})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].time < events[j].time
+	})
+
+	posts := make([]*shared.PostExport, 0, len(channel.Posts))
+	for i := range channel.Posts {
+		posts = append(posts, &channel.Posts[i])
+	}
+	sort.SliceStable(posts, func(i, j int) bool {
+		return postTime(posts[i]) < postTime(posts[j])
+	})
+
+	messages := make([]*Message, 0, len(posts)+1)
+	if membershipSummary(channel) != "" {
+		var recipients []shared.MembershipMapUser
+		for _, join := range channel.JoinEvents {
+			recipients = append(recipients, shared.MembershipMapUser{UserId: join.UserId, Email: join.UserEmail, Username: join.Username})
+		}
+		messages = append(messages, &Message{Channel: channel, Time: channel.StartTime, Recipients: recipients})
+	}
+
+	members := make(shared.MembershipMap)
+	next := 0
+	for _, post := range posts {
+		t := postTime(post)
+		for ; next < len(events) && events[next].time <= t; next++ {
+			if events[next].join {
+				members.AddUserToChannel(channel.ChannelId, events[next].user)
+			} else {
+				members.RemoveUserFromChannel(channel.ChannelId, events[next].user.Email)
+			}
+		}
+
+		// The author of a post was a member of the channel, even when the history does not say so.
+		sender := shared.MembershipMapUser{
+			UserId:   model.SafeDereference(post.UserId),
+			Email:    model.SafeDereference(post.UserEmail),
+			Username: model.SafeDereference(post.Username),
+		}
+		members.AddUserToChannel(channel.ChannelId, sender)
+
+		var recipients []shared.MembershipMapUser
+		for _, member := range members.GetUsersInChannel(channel.ChannelId) {
+			if member.Email != sender.Email {
+				recipients = append(recipients, member)
+			}
+		}
+		sort.Slice(recipients, func(i, j int) bool {
+			return recipients[i].Username < recipients[j].Username
+		})
+
+		messages = append(messages, &Message{Channel: channel, Post: post, Time: t, Recipients: recipients})
+	}
+
+	return messages
+}
+
+// WriteMessage writes the message as an email. It returns a warning for each attachment which could not be read.
+func WriteMessage(rctx request.CTX, fileAttachmentBackend filestore.FileBackend, domain string, message *Message, w io.Writer) ([]string, error) {
+	var warnings []string
+	channel := message.Channel
+	channelAddress := formatAddress(channel.ChannelName+"@"+domain, channel.DisplayName)
+
+	recipients := make([]string, 0, len(message.Recipients))
+	for _, recipient := range message.Recipients {
+		recipients = append(recipients, formatAddress(recipient.Email, recipient.Username))
+	}
+
+	m := gomail.NewMessage(gomail.SetCharset("UTF-8"))
+	m.SetHeaders(map[string][]string{
+		"Auto-Submitted":  {"auto-generated"},
+		"Precedence":      {"bulk"},
+		ChannelIDHeader:   {channel.ChannelId},
+		ChannelNameHeader: {channel.DisplayName},
+		ChannelTypeHeader: {shared.ChannelTypeDisplayName(channel.ChannelType)},
+	})
+	if channel.TeamName != "" {
+		m.SetHeader(TeamNameHeader, channel.TeamName)
+	}
+	m.SetDateHeader("Date", time.UnixMilli(message.Time).UTC())
+
+	// The direct and group messages are addressed to their members, and the posts of the other channels to the
+	// channel, like a mailing list, with the members in copy.
+	isDirect := channel.ChannelType == model.ChannelTypeDirect || channel.ChannelType == model.ChannelTypeGroup
+	if isDirect && len(recipients) > 0 {
+		m.SetHeader("To", recipients...)
+	} else {
+		m.SetHeader("To", channelAddress)
+		if len(recipients) > 0 {
+			m.SetHeader("Cc", recipients...)
+		}
+	}
+
+	post := message.Post
+	if post == nil {
+		m.SetHeader("From", channelAddress)
+		m.SetHeader("Message-ID", messageID(messageLocalID(message), domain))
+		m.SetHeader("Subject", fmt.Sprintf("[%s] Membership changes", channel.DisplayName))
+		m.SetHeader(MsgTypeHeader, MsgTypeMembership)
+		m.SetBody("text/plain", membershipSummary(channel))
+		_, err := m.WriteTo(w)
+		if err != nil {
+			return warnings, fmt.Errorf("unable to generate eml file data: %w", err)
+		}
+		return warnings, nil
+	}
+
+	sender := messageSender(message)
+	m.SetHeader("From", formatAddress(sender.Email, sender.Username))
+	m.SetHeader("Message-ID", messageID(messageLocalID(message), domain))
+	m.SetHeader(MsgTypeHeader, MsgTypePost)
+	m.SetHeader(PostIDHeader, model.SafeDereference(post.PostId))
+	m.SetHeader(UserTypeHeader, string(post.UserType))
+	if post.UpdatedType != "" {
+		m.SetHeader(UpdateTypeHeader, string(post.UpdatedType))
+	}
+	if post.EditedNewMsgId != "" {
+		m.SetHeader(EditedByPostHeader, post.EditedNewMsgId)
+	}
+	if post.PreviewsPost != "" {
+		m.SetHeader(PreviewsPostHeader, post.PreviewsPost)
+	}
+
+	var references []string
+	rootID := model.SafeDereference(post.PostRootId)
+	if rootID != "" {
+		references = append(references, messageID(rootID, domain))
+	}
+	// The updates of a post reply to the post, the original message of an edited post to the edited post.
+	if post.UpdatedType == shared.EditedOriginalMsg && post.EditedNewMsgId != "" {
+		references = append(references, messageID(post.EditedNewMsgId, domain))
+	} else if post.UpdatedType != "" {
+		references = append(references, messageID(model.SafeDereference(post.PostId), domain))
+	}
+	if len(references) > 0 {
+		m.SetHeader("In-Reply-To", references[len(references)-1])
+		m.SetHeader("References", strings.Join(references, " "))
+	}
+
+	subject := fmt.Sprintf("[%s] %s", channel.DisplayName, subjectSummary(post.Message))
+	if len(references) > 0 {
+		subject = "Re: " + subject
+	}
+	m.SetHeader("Subject", subject)
+
+	body := post.Message
+	for _, deleted := range post.AttachmentDeletes {
+		body += fmt.Sprintf("\n\nDeleted file %s", deleted.FileInfo.Name)
+	}
+	m.SetBody("text/plain", body)
+
+	// The updates of a post only record the change, the attachments are exported with the post.
+	if post.UpdatedType == "" {
+		for _, upload := range post.AttachmentCreates {
+			fileInfo := upload.FileInfo
+			m.Attach(fileInfo.Name, gomail.SetCopyFunc(func(writer io.Writer) error {
+				reader, err := fileAttachmentBackend.Reader(fileInfo.Path)
+				if err != nil {
+					warnings = append(warnings, "Warning:"+shared.MissingFileMessageDuringBackendRead+" - Post: "+*post.PostId+" - "+fileInfo.Path)
+					rctx.Logger().Warn(shared.MissingFileMessageDuringBackendRead,
+						mlog.String("post_id", *post.PostId),
+						mlog.String("filename", fileInfo.Path),
+						mlog.Err(err),
+					)
+					return nil
+				}
+				defer reader.Close()
+
+				// s3 only errors _here_ if the object key wasn't found, so a copy error is a warning as well.
+				if _, err = io.Copy(writer, reader); err != nil {
+					warnings = append(warnings, "Warning:"+shared.MissingFileMessageDuringCopy+" - Post: "+*post.PostId+" - "+fileInfo.Path)
+					rctx.Logger().Warn(shared.MissingFileMessageDuringCopy,
+						mlog.String("post_id", *post.PostId),
+						mlog.String("filename", fileInfo.Path),
+						mlog.Err(err),
+					)
+				}
+				return nil
+			}))
+		}
+	}
+
+	_, err := m.WriteTo(w)
+	if err != nil {
+		return warnings, fmt.Errorf("unable to generate eml file data: %w", err)
+	}
+	return warnings, nil
+}
+
+// membershipSummary returns the joins and leaves of the channel during the export period, one per line.
+func membershipSummary(channel *shared.ChannelExport) string {
+	var lines []string
+	for _, join := range channel.JoinEvents {
+		if join.JoinTime <= channel.StartTime {
+			lines = append(lines, fmt.Sprintf("%s: User %s (%s) was already in the channel", formatTime(channel.StartTime), join.Username, join.UserEmail))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: User %s (%s) joined the channel", formatTime(join.JoinTime), join.Username, join.UserEmail))
+	}
+	for _, leave := range channel.LeaveEvents {
+		if leave.ClosedOut {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: User %s (%s) left the channel", formatTime(leave.LeaveTime), leave.Username, leave.UserEmail))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func messageSender(message *Message) shared.MembershipMapUser {
+	if message.Post == nil {
+		return shared.MembershipMapUser{}
+	}
+	return shared.MembershipMapUser{
+		UserId:   model.SafeDereference(message.Post.UserId),
+		Email:    model.SafeDereference(message.Post.UserEmail),
+		Username: model.SafeDereference(message.Post.Username),
+	}
+}
+
+// messageLocalID returns the local part of the id of the message. The updates of a post get their own id, as they are
+// exported alongside the post.
+func messageLocalID(message *Message) string {
+	if message.Post == nil {
+		return fmt.Sprintf("%s.membership.%d", message.Channel.ChannelId, message.Time)
+	}
+	postID := model.SafeDereference(message.Post.PostId)
+	if message.Post.UpdatedType == "" {
+		return postID
+	}
+	return fmt.Sprintf("%s.%s.%d", postID, strings.ToLower(string(message.Post.UpdatedType)), message.Time)
+}
+
+func messageID(localID, domain string) string {
+	return "<" + localID + "@" + domain + ">"
+}
+
+// messageDomain returns the domain of the message ids and channel addresses: the host name of the site URL.
+func messageDomain(config *model.Config) string {
+	if config != nil && config.ServiceSettings.SiteURL != nil {
+		if hostname := utils.GetHostnameFromSiteURL(*config.ServiceSettings.SiteURL); hostname != "" {
+			return hostname
+		}
+	}
+	return defaultMessageDomain
+}
+
+func postTime(post *shared.PostExport) int64 {
+	if post.UpdatedType != "" && post.UpdateAt > 0 {
+		return post.UpdateAt
+	}
+	return model.SafeDereference(post.PostCreateAt)
+}
+
+func formatAddress(address, name string) string {
+	return gomail.NewMessage().FormatAddress(address, name)
+}
+
+func formatTime(millis int64) string {
+	return time.UnixMilli(millis).UTC().Format(time.RFC3339)
+}
+
+// subjectSummary returns the first line of the message, truncated to fit in a subject.
+func subjectSummary(message string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	if runes := []rune(strings.TrimSpace(line)); len(runes) > maxSubjectRunes {
+		return string(runes[:maxSubjectRunes]) + "..."
+	}
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,356 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.enterprise for license information.
+
+package eml_export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store/storetest"
+	"github.com/mattermost/mattermost/server/v8/enterprise/message_export/shared"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+)
+
+func testPost(id, rootId, userId string, createAt int64, message string) *model.MessageExport {
+	channelType := model.ChannelTypeOpen
+	return &model.MessageExport{
+		PostId:             model.NewPointer(id),
+		PostOriginalId:     model.NewPointer(""),
+		PostRootId:         model.NewPointer(rootId),
+		TeamId:             model.NewPointer("team-id"),
+		TeamName:           model.NewPointer("team-name"),
+		TeamDisplayName:    model.NewPointer("Team"),
+		ChannelId:          model.NewPointer("channel-id"),
+		ChannelName:        model.NewPointer("town-square"),
+		ChannelDisplayName: model.NewPointer("Town Square"),
+		ChannelType:        &channelType,
+		PostCreateAt:       model.NewPointer(createAt),
+		PostUpdateAt:       model.NewPointer(createAt),
+		PostMessage:        model.NewPointer(message),
+		PostProps:          model.NewPointer("{}"),
+		UserId:             model.NewPointer(userId),
+		UserEmail:          model.NewPointer(userId + "@example.com"),
+		Username:           model.NewPointer(userId),
+	}
+}
+
+func testChannelMemberHistories() map[string][]*model.ChannelMemberHistoryResult {
+	return map[string][]*model.ChannelMemberHistoryResult{
+		"channel-id": {
+			{ChannelId: "channel-id", UserId: "alice", UserEmail: "alice@example.com", Username: "alice", JoinTime: 0},
+			{ChannelId: "channel-id", UserId: "bob", UserEmail: "bob@example.com", Username: "bob", JoinTime: 5, LeaveTime: model.NewPointer(int64(50))},
+			{ChannelId: "channel-id", UserId: "carol", UserEmail: "carol@example.com", Username: "carol", JoinTime: 60},
+		},
+	}
+}
+
+func testChannelMetadata() map[string]*shared.MetadataChannel {
+	return map[string]*shared.MetadataChannel{
+		"channel-id": {
+			TeamId:             model.NewPointer("team-id"),
+			ChannelId:          "channel-id",
+			ChannelName:        "town-square",
+			ChannelDisplayName: "Town Square",
+			ChannelType:        model.ChannelTypeOpen,
+			StartTime:          1,
+			EndTime:            100,
+		},
+	}
+}
+
+func recipientNames(message *Message) []string {
+	names := []string{}
+	for _, recipient := range message.Recipients {
+		names = append(names, recipient.Username)
+	}
+	return names
+}
+
+func TestChannelMessages(t *testing.T) {
+	channel := &shared.ChannelExport{
+		ChannelId:   "channel-id",
+		ChannelType: model.ChannelTypeOpen,
+		StartTime:   1,
+		EndTime:     100,
+		Posts: []shared.PostExport{
+			{MessageExport: *testPost("post-2", "post-1", "carol", 70, "reply")},
+			{MessageExport: *testPost("post-1", "", "alice", 10, "root")},
+		},
+		JoinEvents: []shared.JoinExport{
+			{UserId: "alice", Username: "alice", UserEmail: "alice@example.com", JoinTime: 0},
+		},
+	}
+
+	messages := ChannelMessages(channel, testChannelMemberHistories()["channel-id"])
+	require.Len(t, messages, 3)
+
+	assert.Nil(t, messages[0].Post)
+	assert.Equal(t, []string{"alice"}, recipientNames(messages[0]))
+
+	// bob is in the channel when alice posts.
+	assert.Equal(t, "post-1", *messages[1].Post.PostId)
+	assert.Equal(t, []string{"bob"}, recipientNames(messages[1]))
+
+	// bob left the channel, and carol joined it when she replies.
+	assert.Equal(t, "post-2", *messages[2].Post.PostId)
+	assert.Equal(t, []string{"alice"}, recipientNames(messages[2]))
+
+	t.Run("no membership summary without joins or leaves", func(t *testing.T) {
+		channel.JoinEvents = nil
+		messages := ChannelMessages(channel, nil)
+		require.Len(t, messages, 2)
+		assert.NotNil(t, messages[0].Post)
+
+		// The authors of the posts are members of the channel.
+		assert.Equal(t, []string{"alice"}, recipientNames(messages[1]))
+	})
+}
+
+func TestMessageLocalID(t *testing.T) {
+	channel := &shared.ChannelExport{ChannelId: "channel-id"}
+	post := shared.PostExport{MessageExport: *testPost("post-id", "", "alice", 10, "message")}
+
+	assert.Equal(t, "post-id", messageLocalID(&Message{Channel: channel, Post: &post, Time: 10}))
+
+	post.UpdatedType = shared.Deleted
+	assert.Equal(t, "post-id.deleted.20", messageLocalID(&Message{Channel: channel, Post: &post, Time: 20}))
+
+	assert.Equal(t, "channel-id.membership.1", messageLocalID(&Message{Channel: channel, Time: 1}))
+}
+
+func TestMessageDomain(t *testing.T) {
+	assert.Equal(t, defaultMessageDomain, messageDomain(nil))
+
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+	assert.Equal(t, defaultMessageDomain, messageDomain(cfg))
+
+	cfg.ServiceSettings.SiteURL = model.NewPointer("https://chat.example.com:8065/mattermost")
+	assert.Equal(t, "chat.example.com", messageDomain(cfg))
+}
+
+func TestSubjectSummary(t *testing.T) {
+	assert.Equal(t, "hello", subjectSummary("  hello  \nworld"))
+	assert.Equal(t, strings.Repeat("a", maxSubjectRunes)+"...", subjectSummary(strings.Repeat("a", maxSubjectRunes+1)))
+}
+
+func TestMboxWriter(t *testing.T) {
+	var buf bytes.Buffer
+	mbox := newMboxWriter(&buf)
+
+	require.NoError(t, mbox.startMessage("alice@example.com", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+	_, err := mbox.Write([]byte("Subject: hi\r\n\r\nFrom here\r\n>From there\r\nno"))
+	require.NoError(t, err)
+	_, err = mbox.Write([]byte(" newline"))
+	require.NoError(t, err)
+	require.NoError(t, mbox.endMessage())
+
+	require.NoError(t, mbox.startMessage("", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+	_, err = mbox.Write([]byte("Subject: bye\r\n"))
+	require.NoError(t, err)
+	require.NoError(t, mbox.endMessage())
+
+	assert.Equal(t, "From alice@example.com Tue Jan  2 03:04:05 2024\n"+
+		"Subject: hi\n"+
+		"\n"+
+		">From here\n"+
+		">>From there\n"+
+		"no newline\n"+
+		"\n"+
+		"From MAILER-DAEMON Tue Jan  2 03:04:05 2024\n"+
+		"Subject: bye\n"+
+		"\n", buf.String())
+}
+
+func readZipFiles(t *testing.T, backend filestore.FileBackend, exportFileName string) map[string]string {
+	zipBytes, err := backend.ReadFile(exportFileName)
+	require.NoError(t, err)
+
+	zipReader, err := zip.NewReader(bytes.NewReader(zipBytes), int64(len(zipBytes)))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, f := range zipReader.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		files[f.Name] = string(data)
+	}
+	return files
+}
+
+func TestEmlExport(t *testing.T) {
+	rctx := request.TestContext(t)
+
+	backend, err := filestore.NewFileBackend(filestore.FileBackendSettings{
+		DriverName: model.ImageDriverLocal,
+		Directory:  t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	_, err = backend.WriteFile(strings.NewReader("attachment content"), "data/report.txt")
+	require.NoError(t, err)
+
+	rootPost := testPost("post-1", "", "alice", 10, "Is the report ready?")
+	replyPost := testPost("post-2", "post-1", "carol", 70, "Here it is")
+	replyPost.PostFileIds = []string{"file-id"}
+	posts := []*model.MessageExport{rootPost, replyPost}
+
+	newMockStore := func(t *testing.T) *storetest.Store {
+		mockStore := &storetest.Store{}
+		t.Cleanup(func() { mockStore.AssertExpectations(t) })
+		mockStore.FileInfoStore.On("GetForPost", "post-2", true, true, false).Return([]*model.FileInfo{
+			{Id: "file-id", Name: "report.txt", Path: "data/report.txt"},
+		}, nil)
+		return mockStore
+	}
+
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+	cfg.ServiceSettings.SiteURL = model.NewPointer("https://chat.example.com")
+
+	t.Run("eml", func(t *testing.T) {
+		exportFileName := path.Join("export", "jobName", "jobName-batch001-eml.zip")
+		results, err := EmlExport(rctx, shared.ExportParams{
+			ExportType:             model.ComplianceExportTypeEml,
+			ChannelMetadata:        testChannelMetadata(),
+			Posts:                  posts,
+			ChannelMemberHistories: testChannelMemberHistories(),
+			BatchPath:              exportFileName,
+			BatchStartTime:         1,
+			BatchEndTime:           100,
+			Config:                 cfg,
+			Db:                     shared.NewMessageExportStore(newMockStore(t)),
+			FileAttachmentBackend:  backend,
+			ExportBackend:          backend,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 0, results.NumWarnings)
+		assert.Equal(t, 2, results.CreatedPosts)
+
+		files := readZipFiles(t, backend, exportFileName)
+		require.Len(t, files, 4)
+		assert.Contains(t, files, "metadata.json")
+
+		summary, err := mail.ReadMessage(strings.NewReader(files["town-square - (channel-id)/1-channel-id.membership.1.eml"]))
+		require.NoError(t, err)
+		assert.Equal(t, MsgTypeMembership, summary.Header.Get(MsgTypeHeader))
+		body, err := io.ReadAll(summary.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "User alice (alice@example.com) was already in the channel")
+		assert.Contains(t, string(body), "User bob (bob@example.com) joined the channel")
+		assert.Contains(t, string(body), "User bob (bob@example.com) left the channel")
+		assert.Contains(t, string(body), "User carol (carol@example.com) joined the channel")
+
+		root, err := mail.ReadMessage(strings.NewReader(files["town-square - (channel-id)/10-post-1.eml"]))
+		require.NoError(t, err)
+		assert.Equal(t, "<post-1@chat.example.com>", root.Header.Get("Message-ID"))
+		assert.Equal(t, `"alice" <alice@example.com>`, root.Header.Get("From"))
+		assert.Equal(t, `"Town Square" <town-square@chat.example.com>`, root.Header.Get("To"))
+		assert.Equal(t, `"bob" <bob@example.com>`, root.Header.Get("Cc"))
+		assert.Equal(t, "[Town Square] Is the report ready?", root.Header.Get("Subject"))
+		assert.Empty(t, root.Header.Get("In-Reply-To"))
+		assert.Equal(t, "post-1", root.Header.Get(PostIDHeader))
+		assert.Equal(t, "team-name", root.Header.Get(TeamNameHeader))
+		date, err := root.Header.Date()
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), date.Unix())
+
+		reply, err := mail.ReadMessage(strings.NewReader(files["town-square - (channel-id)/70-post-2.eml"]))
+		require.NoError(t, err)
+		assert.Equal(t, "<post-1@chat.example.com>", reply.Header.Get("In-Reply-To"))
+		assert.Equal(t, "<post-1@chat.example.com>", reply.Header.Get("References"))
+		assert.Equal(t, "Re: [Town Square] Here it is", reply.Header.Get("Subject"))
+		assert.Equal(t, `"alice" <alice@example.com>`, reply.Header.Get("Cc"))
+
+		mediaType, params, err := mime.ParseMediaType(reply.Header.Get("Content-Type"))
+		require.NoError(t, err)
+		require.Equal(t, "multipart/mixed", mediaType)
+		parts := multipart.NewReader(reply.Body, params["boundary"])
+		var attachments []string
+		for {
+			part, err := parts.NextPart()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			if part.FileName() != "" {
+				attachments = append(attachments, part.FileName())
+			}
+		}
+		assert.Equal(t, []string{"report.txt"}, attachments)
+	})
+
+	t.Run("mbox", func(t *testing.T) {
+		exportFileName := path.Join("export", "jobName", "jobName-batch001-mbox.zip")
+		results, err := EmlExport(rctx, shared.ExportParams{
+			ExportType:             model.ComplianceExportTypeMbox,
+			ChannelMetadata:        testChannelMetadata(),
+			Posts:                  posts,
+			ChannelMemberHistories: testChannelMemberHistories(),
+			BatchPath:              exportFileName,
+			BatchStartTime:         1,
+			BatchEndTime:           100,
+			Config:                 cfg,
+			Db:                     shared.NewMessageExportStore(newMockStore(t)),
+			FileAttachmentBackend:  backend,
+			ExportBackend:          backend,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 0, results.NumWarnings)
+
+		files := readZipFiles(t, backend, exportFileName)
+		require.Len(t, files, 2)
+		mbox, ok := files["town-square - (channel-id).mbox"]
+		require.True(t, ok)
+
+		assert.True(t, strings.HasPrefix(mbox, "From MAILER-DAEMON "))
+		assert.Contains(t, mbox, "\nFrom alice@example.com ")
+		assert.Contains(t, mbox, "\nFrom carol@example.com ")
+		assert.NotContains(t, mbox, "\r\n")
+	})
+
+	t.Run("missing attachment", func(t *testing.T) {
+		mockStore := &storetest.Store{}
+		defer mockStore.AssertExpectations(t)
+		mockStore.FileInfoStore.On("GetForPost", "post-2", true, true, false).Return([]*model.FileInfo{
+			{Id: "missing-id", Name: "missing.txt", Path: "data/missing.txt"},
+		}, nil)
+
+		exportFileName := path.Join("export", "jobName", "jobName-batch002-eml.zip")
+		results, err := EmlExport(rctx, shared.ExportParams{
+			ExportType:             model.ComplianceExportTypeEml,
+			ChannelMetadata:        testChannelMetadata(),
+			Posts:                  posts,
+			ChannelMemberHistories: testChannelMemberHistories(),
+			BatchPath:              exportFileName,
+			BatchStartTime:         1,
+			BatchEndTime:           100,
+			Config:                 cfg,
+			Db:                     shared.NewMessageExportStore(mockStore),
+			FileAttachmentBackend:  backend,
+			ExportBackend:          backend,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, results.NumWarnings)
+
+		files := readZipFiles(t, backend, exportFileName)
+		assert.Contains(t, files[EmlWarningFilename], "data/missing.txt")
+	})
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.enterprise for license information.
+
+package eml_export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+)
+
+// mboxWriter writes emails in the mboxrd format: each email starts with a "From " line and ends with an empty line,
+// the lines of the emails are terminated by LF, and the lines starting with any number of '>' followed by "From "
+// are quoted with one more '>'.
+type mboxWriter struct {
+	w    io.Writer
+	line []byte
+}
+
+func newMboxWriter(w io.Writer) *mboxWriter {
+	return &mboxWriter{w: w}
+}
+
+func (m *mboxWriter) startMessage(sender string, date time.Time) error {
+	if sender == "" {
+		sender = "MAILER-DAEMON"
+	}
+	_, err := fmt.Fprintf(m.w, "From %s %s\n", sender, date.UTC().Format(time.ANSIC))
+	return err
+}
+
+func (m *mboxWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			m.line = append(m.line, p...)
+			break
+		}
+		m.line = append(m.line, p[:i]...)
+		if err := m.writeLine(); err != nil {
+			return 0, err
+		}
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+func (m *mboxWriter) writeLine() error {
+	line := bytes.TrimSuffix(m.line, []byte("\r"))
+	m.line = m.line[:0]
+
+	if bytes.HasPrefix(bytes.TrimLeft(line, ">"), []byte("From ")) {
+		if _, err := m.w.Write([]byte(">")); err != nil {
+			return err
+		}
+	}
+	if _, err := m.w.Write(line); err != nil {
+		return err
+	}
+	_, err := m.w.Write([]byte("\n"))
+	return err
+}
+
+// endMessage terminates the last line of the email, and the email with an empty line.
+func (m *mboxWriter) endMessage() error {
+	if len(m.line) > 0 {
+		if err := m.writeLine(); err != nil {
+			return err
+		}
+	}
+	_, err := m.w.Write([]byte("\n"))
+	return err
+}
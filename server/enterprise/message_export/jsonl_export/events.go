@@ -0,0 +1,349 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.enterprise for license information.
+
+package jsonl_export
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/v8/enterprise/message_export/shared"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// SchemaVersion is the version of the schema of the events and of the manifest, documented in schema.json. It is
+// increased when a field is removed or changes meaning, but not when an optional field is added.
+const SchemaVersion = 1
+
+type EventType string
+
+const (
+	EventChannelJoined       EventType = "channel_joined"
+	EventPostCreated         EventType = "post_created"
+	EventFileUploaded        EventType = "file_uploaded"
+	EventPostPreviousVersion EventType = "post_previous_version"
+	EventPostEdited          EventType = "post_edited"
+	EventPostUpdated         EventType = "post_updated"
+	EventReactionAdded       EventType = "reaction_added"
+	EventReactionRemoved     EventType = "reaction_removed"
+	EventFileDeleted         EventType = "file_deleted"
+	EventPostDeleted         EventType = "post_deleted"
+	EventChannelLeft         EventType = "channel_left"
+)
+
+// eventTypeOrder orders the events happening at the same millisecond, e.g. a post is created before its files are
+// uploaded, and the previous version of an edited post comes before its new version.
+var eventTypeOrder = map[EventType]int{
+	EventChannelJoined:       0,
+	EventPostCreated:         1,
+	EventFileUploaded:        2,
+	EventPostPreviousVersion: 3,
+	EventPostEdited:          4,
+	EventPostUpdated:         5,
+	EventReactionAdded:       6,
+	EventReactionRemoved:     7,
+	EventFileDeleted:         8,
+	EventPostDeleted:         9,
+	EventChannelLeft:         10,
+}
+
+// Event is a line of the events.jsonl file.
+type Event struct {
+	SchemaVersion int       `json:"schema_version"`
+	Sequence      int       `json:"sequence"`  // position of the event in the batch, starting at 1
+	Type          EventType `json:"type"`      // what happened
+	Timestamp     int64     `json:"timestamp"` // utc timestamp (milliseconds) of the event
+
+	TeamId          string `json:"team_id,omitempty"` // empty for direct and group messages
+	TeamName        string `json:"team_name,omitempty"`
+	TeamDisplayName string `json:"team_display_name,omitempty"`
+
+	ChannelId          string            `json:"channel_id"`
+	ChannelName        string            `json:"channel_name"`
+	ChannelDisplayName string            `json:"channel_display_name"`
+	ChannelType        model.ChannelType `json:"channel_type"`
+
+	// The user who did the action: the author of the post or of the file, the user who reacted, joined or left.
+	UserId    string          `json:"user_id"`
+	Username  string          `json:"username,omitempty"`
+	UserEmail string          `json:"user_email,omitempty"`
+	UserType  shared.UserType `json:"user_type"`
+
+	Post     *Post     `json:"post,omitempty"`     // the post the event is about, absent for join and leave events
+	File     *File     `json:"file,omitempty"`     // for file events
+	Reaction *Reaction `json:"reaction,omitempty"` // for reaction events
+}
+
+type Post struct {
+	Id       string `json:"id"`
+	RootId   string `json:"root_id,omitempty"` // the root post of the thread, for replies
+	Type     string `json:"type,omitempty"`    // empty for the messages posted by users
+	CreateAt int64  `json:"create_at"`
+	UpdateAt int64  `json:"update_at"`
+	EditAt   int64  `json:"edit_at,omitempty"`
+	DeleteAt int64  `json:"delete_at,omitempty"`
+	Message  string `json:"message"`
+
+	// For post_previous_version events, the id of the post that has the edited message.
+	EditedPostId   string          `json:"edited_post_id,omitempty"`
+	PreviewsPostId string          `json:"previews_post_id,omitempty"` // the post shown by a permalink preview
+	FileIds        []string        `json:"file_ids,omitempty"`
+	Props          json.RawMessage `json:"props,omitempty"`
+}
+
+type File struct {
+	Id        string `json:"id"`
+	Name      string `json:"name"`
+	Extension string `json:"extension,omitempty"`
+	MimeType  string `json:"mime_type,omitempty"`
+	Size      int64  `json:"size"`
+	CreateAt  int64  `json:"create_at"`
+	DeleteAt  int64  `json:"delete_at,omitempty"`
+
+	// The path of the file in the batch and the SHA-256 of its content, empty when the file could not be read.
+	ExportPath string `json:"export_path,omitempty"`
+	Sha256     string `json:"sha256,omitempty"`
+}
+
+type Reaction struct {
+	EmojiName string `json:"emoji_name"`
+	CreateAt  int64  `json:"create_at"`
+	DeleteAt  int64  `json:"delete_at,omitempty"`
+}
+
+type exportUser struct {
+	Username string
+	Email    string
+	IsBot    bool
+}
+
+func userType(isBot bool) shared.UserType {
+	if isBot {
+		return shared.Bot
+	}
+	return shared.User
+}
+
+func newPostEvent(eventType EventType, timestamp int64, post *model.MessageExport) *Event {
+	return &Event{
+		SchemaVersion:      SchemaVersion,
+		Type:               eventType,
+		Timestamp:          timestamp,
+		TeamId:             model.SafeDereference(post.TeamId),
+		TeamName:           model.SafeDereference(post.TeamName),
+		TeamDisplayName:    model.SafeDereference(post.TeamDisplayName),
+		ChannelId:          model.SafeDereference(post.ChannelId),
+		ChannelName:        model.SafeDereference(post.ChannelName),
+		ChannelDisplayName: model.SafeDereference(post.ChannelDisplayName),
+		ChannelType:        model.SafeDereference(post.ChannelType),
+		UserId:             model.SafeDereference(post.UserId),
+		Username:           model.SafeDereference(post.Username),
+		UserEmail:          model.SafeDereference(post.UserEmail),
+		UserType:           userType(post.IsBot),
+		Post:               newPost(post),
+	}
+}
+
+func newPost(post *model.MessageExport) *Post {
+	p := &Post{
+		Id:             model.SafeDereference(post.PostId),
+		RootId:         model.SafeDereference(post.PostRootId),
+		Type:           model.SafeDereference(post.PostType),
+		CreateAt:       model.SafeDereference(post.PostCreateAt),
+		UpdateAt:       model.SafeDereference(post.PostUpdateAt),
+		EditAt:         model.SafeDereference(post.PostEditAt),
+		DeleteAt:       model.SafeDereference(post.PostDeleteAt),
+		Message:        model.SafeDereference(post.PostMessage),
+		PreviewsPostId: post.PreviewID(),
+		FileIds:        post.PostFileIds,
+	}
+	if props := model.SafeDereference(post.PostProps); props != "" && props != "{}" && json.Valid([]byte(props)) {
+		p.Props = json.RawMessage(props)
+	}
+	return p
+}
+
+// postExportToEvent returns the event recorded by an entry of the generic export data.
+func postExportToEvent(post shared.PostExport) *Event {
+	var event *Event
+	switch post.UpdatedType {
+	case shared.EditedOriginalMsg:
+		event = newPostEvent(EventPostPreviousVersion, post.UpdateAt, &post.MessageExport)
+		event.Post.EditedPostId = post.EditedNewMsgId
+	case shared.EditedNewMsg:
+		event = newPostEvent(EventPostEdited, post.UpdateAt, &post.MessageExport)
+	case shared.UpdatedNoMsgChange:
+		event = newPostEvent(EventPostUpdated, post.UpdateAt, &post.MessageExport)
+	case shared.Deleted:
+		event = newPostEvent(EventPostDeleted, post.UpdateAt, &post.MessageExport)
+	default:
+		event = newPostEvent(EventPostCreated, model.SafeDereference(post.PostCreateAt), &post.MessageExport)
+	}
+	return event
+}
+
+func newFileEvent(eventType EventType, timestamp int64, post *model.MessageExport, fileInfo *model.FileInfo) *Event {
+	event := newPostEvent(eventType, timestamp, post)
+	event.File = &File{
+		Id:        fileInfo.Id,
+		Name:      fileInfo.Name,
+		Extension: fileInfo.Extension,
+		MimeType:  fileInfo.MimeType,
+		Size:      fileInfo.Size,
+		CreateAt:  fileInfo.CreateAt,
+		DeleteAt:  fileInfo.DeleteAt,
+	}
+	return event
+}
+
+func newReactionEvent(eventType EventType, timestamp int64, post *model.MessageExport, reaction *model.Reaction, user exportUser) *Event {
+	event := newPostEvent(eventType, timestamp, post)
+	event.UserId = reaction.UserId
+	event.Username = user.Username
+	event.UserEmail = user.Email
+	event.UserType = userType(user.IsBot)
+	event.Reaction = &Reaction{
+		EmojiName: reaction.EmojiName,
+		CreateAt:  reaction.CreateAt,
+		DeleteAt:  reaction.DeleteAt,
+	}
+	return event
+}
+
+func newMembershipEvent(eventType EventType, timestamp int64, channel shared.ChannelExport, cmh *model.ChannelMemberHistoryResult) *Event {
+	return &Event{
+		SchemaVersion:      SchemaVersion,
+		Type:               eventType,
+		Timestamp:          timestamp,
+		TeamId:             channel.TeamId,
+		TeamName:           channel.TeamName,
+		TeamDisplayName:    channel.TeamDisplayName,
+		ChannelId:          channel.ChannelId,
+		ChannelName:        channel.ChannelName,
+		ChannelDisplayName: channel.DisplayName,
+		ChannelType:        channel.ChannelType,
+		UserId:             cmh.UserId,
+		Username:           cmh.Username,
+		UserEmail:          cmh.UserEmail,
+		UserType:           userType(cmh.IsBot),
+	}
+}
+
+// membershipEvents returns the joins and leaves of the channel that happened during the batch. Unlike the other
+// formats, the users who were already in the channel at the start of the batch are not reported again.
+func membershipEvents(p shared.ExportParams, channel shared.ChannelExport) []*Event {
+	var events []*Event
+	for _, cmh := range p.ChannelMemberHistories[channel.ChannelId] {
+		if inBatch(p, cmh.JoinTime) {
+			events = append(events, newMembershipEvent(EventChannelJoined, cmh.JoinTime, channel, cmh))
+		}
+		if cmh.LeaveTime != nil && inBatch(p, *cmh.LeaveTime) {
+			events = append(events, newMembershipEvent(EventChannelLeft, *cmh.LeaveTime, channel, cmh))
+		}
+	}
+	return events
+}
+
+// reactionEvents returns the reactions added and removed since the start of the job on the posts of the batch.
+//
+// Reacting to a post updates it, so the post is exported in the batch of its last update. The reactions made during
+// the earlier batches of the job are therefore exported with that batch, and the reactions made during the previous
+// jobs were exported with them.
+func reactionEvents(p shared.ExportParams, users map[string]exportUser) ([]*Event, error) {
+	posts := make(map[string]*model.MessageExport)
+	postIds := []string{}
+	for _, post := range p.Posts {
+		// Posts that were never updated have no reactions, and edited messages keep their reactions on the
+		// original post.
+		if model.SafeDereference(post.PostUpdateAt) <= model.SafeDereference(post.PostCreateAt) ||
+			model.SafeDereference(post.PostOriginalId) != "" {
+			continue
+		}
+		posts[*post.PostId] = post
+		postIds = append(postIds, *post.PostId)
+	}
+	if len(postIds) == 0 {
+		return nil, nil
+	}
+
+	reactions, err := p.Db.Reaction().GetForPostsSince(postIds, p.JobStartTime)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the reactions of the posts: %w", err)
+	}
+
+	var events []*Event
+	for _, reaction := range reactions {
+		post, ok := posts[reaction.PostId]
+		if !ok {
+			continue
+		}
+		user := users[reaction.UserId]
+		if reaction.CreateAt > p.JobStartTime && reaction.CreateAt <= p.BatchEndTime {
+			events = append(events, newReactionEvent(EventReactionAdded, reaction.CreateAt, post, reaction, user))
+		}
+		if reaction.DeleteAt > p.JobStartTime && reaction.DeleteAt <= p.BatchEndTime {
+			events = append(events, newReactionEvent(EventReactionRemoved, reaction.DeleteAt, post, reaction, user))
+		}
+	}
+	return events, nil
+}
+
+// exportUsers returns the users known to the batch, to describe the users who reacted to the posts.
+func exportUsers(p shared.ExportParams) map[string]exportUser {
+	users := make(map[string]exportUser)
+	for _, cmhs := range p.ChannelMemberHistories {
+		for _, cmh := range cmhs {
+			users[cmh.UserId] = exportUser{Username: cmh.Username, Email: cmh.UserEmail, IsBot: cmh.IsBot}
+		}
+	}
+	for _, post := range p.Posts {
+		userId := model.SafeDereference(post.UserId)
+		if _, ok := users[userId]; !ok {
+			users[userId] = exportUser{Username: model.SafeDereference(post.Username), Email: model.SafeDereference(post.UserEmail), IsBot: post.IsBot}
+		}
+	}
+	return users
+}
+
+// inBatch returns whether the time is in the batch, which starts right after the end of the previous batch.
+func inBatch(p shared.ExportParams, t int64) bool {
+	return t > p.BatchStartTime && t <= p.BatchEndTime
+}
+
+// compareEvents orders the events by time, then in a deterministic order, so that exporting the same batch twice
+// gives the same file.
+func compareEvents(a, b *Event) int {
+	return cmp.Or(
+		cmp.Compare(a.Timestamp, b.Timestamp),
+		cmp.Compare(eventTypeOrder[a.Type], eventTypeOrder[b.Type]),
+		strings.Compare(a.ChannelId, b.ChannelId),
+		strings.Compare(a.postId(), b.postId()),
+		strings.Compare(a.fileId(), b.fileId()),
+		strings.Compare(a.UserId, b.UserId),
+		strings.Compare(a.emojiName(), b.emojiName()),
+	)
+}
+
+func (e *Event) postId() string {
+	if e.Post == nil {
+		return ""
+	}
+	return e.Post.Id
+}
+
+func (e *Event) fileId() string {
+	if e.File == nil {
+		return ""
+	}
+	return e.File.Id
+}
+
+func (e *Event) emojiName() string {
+	if e.Reaction == nil {
+		return ""
+	}
+	return e.Reaction.EmojiName
+}
@@ -0,0 +1,273 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.enterprise for license information.
+
+// Package jsonl_export exports the batches as JSON Lines, one event per line, for the data pipelines.
+//
+// Each batch is a zip file containing:
+//   - events.jsonl: the events of the batch, ordered by time, described by schema.json
+//   - files/<post id>/<file id>-<file name>: the attachments of the posts
+//   - warning.txt: the attachments that could not be exported, if any
+//   - metadata.json: the channels of the batch, as in the other formats
+//   - schema.json: the JSON schema of the events and of the manifest
+//   - manifest.json: the checksums of the other files, written last
+package jsonl_export
+
+import (
+	"archive/zip"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+
+	"github.com/mattermost/mattermost/server/v8/enterprise/internal/file"
+	"github.com/mattermost/mattermost/server/v8/enterprise/message_export/shared"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+)
+
+const (
+	EventsFilename       = "events.jsonl"
+	ManifestFilename     = "manifest.json"
+	MetadataFilename     = "metadata.json"
+	SchemaFilename       = "schema.json"
+	JsonlWarningFilename = "warning.txt"
+)
+
+//go:embed schema.json
+var schema []byte
+
+func JsonlExport(rctx request.CTX, p shared.ExportParams) (shared.RunExportResults, error) {
+	exportData, err := shared.GetGenericExportData(p)
+	results := exportData.Results
+	if err != nil {
+		return results, err
+	}
+
+	events, err := Events(p, exportData)
+	if err != nil {
+		return results, err
+	}
+
+	fileInfos := make(map[string]*model.FileInfo)
+	for _, channel := range exportData.Exports {
+		for _, upload := range channel.UploadStarts {
+			fileInfos[upload.FileInfo.Id] = upload.FileInfo
+		}
+		for _, deleted := range channel.DeletedFiles {
+			fileInfos[deleted.FileInfo.Id] = deleted.FileInfo
+		}
+	}
+
+	// Write this batch to a tmp zip, then copy the zip to the export directory.
+	// Using a 2M buffer because the file backend may be s3 and this optimizes speed and
+	// memory usage, see: https://github.com/mattermost/mattermost/pull/26629
+	buf := make([]byte, 1024*1024*2)
+	temp, err := os.CreateTemp("", "compliance-export-batch-*.zip")
+	if err != nil {
+		return results, fmt.Errorf("unable to create temporary JSONL export file: %w", err)
+	}
+	defer file.DeleteTemp(rctx.Logger(), temp)
+
+	zipFile := zip.NewWriter(temp)
+	batch := newBatchWriter(zipFile)
+
+	// The attachments are written first, as the events have their checksums.
+	missingFiles, err := writeAttachments(rctx, p, batch, events, fileInfos, buf)
+	if err != nil {
+		return results, fmt.Errorf("unable to add the attachments to the zip file: %w", err)
+	}
+
+	eventsFile, err := batch.create(EventsFilename)
+	if err != nil {
+		return results, fmt.Errorf("unable to create the zip export file: %w", err)
+	}
+	encoder := json.NewEncoder(eventsFile)
+	encoder.SetEscapeHTML(false)
+	for _, event := range events {
+		if err = encoder.Encode(event); err != nil {
+			return results, fmt.Errorf("unable to export an event: %w", err)
+		}
+	}
+
+	results.NumWarnings = len(missingFiles)
+	if results.NumWarnings > 0 {
+		var warningFile io.Writer
+		warningFile, err = batch.create(JsonlWarningFilename)
+		if err != nil {
+			return results, fmt.Errorf("unable to create the warning file: %w", err)
+		}
+		for _, value := range missingFiles {
+			if _, err = warningFile.Write([]byte(value + "\n")); err != nil {
+				return results, fmt.Errorf("unable to create the warning file: %w", err)
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(exportData.Metadata, "", "  ")
+	if err != nil {
+		return results, fmt.Errorf("unable to convert metadata to json: %w", err)
+	}
+	if err = writeZipFile(batch, MetadataFilename, data); err != nil {
+		return results, fmt.Errorf("unable to add metadata file to the zip file: %w", err)
+	}
+
+	if err = writeZipFile(batch, SchemaFilename, schema); err != nil {
+		return results, fmt.Errorf("unable to add schema file to the zip file: %w", err)
+	}
+
+	data, err = json.MarshalIndent(batch.manifest(p.BatchStartTime, p.BatchEndTime, events), "", "  ")
+	if err != nil {
+		return results, fmt.Errorf("unable to convert manifest to json: %w", err)
+	}
+	manifestFile, err := zipFile.Create(ManifestFilename)
+	if err != nil {
+		return results, fmt.Errorf("unable to create the manifest file: %w", err)
+	}
+	if _, err = manifestFile.Write(data); err != nil {
+		return results, fmt.Errorf("unable to add manifest file to the zip file: %w", err)
+	}
+
+	err = zipFile.Close()
+	if err != nil {
+		return results, fmt.Errorf("unable to close the zip file: %w", err)
+	}
+
+	_, err = temp.Seek(0, 0)
+	if err != nil {
+		return results, fmt.Errorf("unable to seek to start of export file: %w", err)
+	}
+
+	// Try to write the file without a timeout due to the potential size of the file.
+	_, err = filestore.TryWriteFileContext(rctx.Context(), p.ExportBackend, temp, p.BatchPath)
+	if err != nil {
+		return results, fmt.Errorf("unable to write the jsonl file: %w", err)
+	}
+	return results, nil
+}
+
+// Events returns the events of the batch, in order. The files of the events have no path nor checksum, as their
+// content is not read.
+func Events(p shared.ExportParams, exportData shared.GenericExportData) ([]*Event, error) {
+	var events []*Event
+	for _, channel := range exportData.Exports {
+		events = append(events, membershipEvents(p, channel)...)
+		for _, post := range channel.Posts {
+			events = append(events, postExportToEvent(post))
+		}
+		for _, upload := range channel.UploadStarts {
+			events = append(events, newFileEvent(EventFileUploaded, upload.UploadStartTime, &upload.MessageExport, upload.FileInfo))
+		}
+		for _, deleted := range channel.DeletedFiles {
+			events = append(events, newFileEvent(EventFileDeleted, deleted.UpdateAt, &deleted.MessageExport, deleted.FileInfo))
+		}
+	}
+
+	reactions, err := reactionEvents(p, exportUsers(p))
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, reactions...)
+
+	slices.SortStableFunc(events, compareEvents)
+	for i, event := range events {
+		event.Sequence = i + 1
+	}
+	return events, nil
+}
+
+func writeZipFile(batch *batchWriter, name string, data []byte) error {
+	w, err := batch.create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// writeAttachments copies the files of the events to the batch, once per file, and sets their path and checksum in
+// the events. It returns the warnings about the files that could not be read.
+//
+// The files are read to a temporary file before being added to the batch, as the entries of a zip file cannot be
+// removed: a file failing in the middle of the copy would otherwise leave a truncated entry in the batch.
+func writeAttachments(rctx request.CTX, p shared.ExportParams, batch *batchWriter, events []*Event, fileInfos map[string]*model.FileInfo, buf []byte) ([]string, error) {
+	spool, err := os.CreateTemp("", "compliance-export-attachment-*")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create temporary attachment file: %w", err)
+	}
+	defer file.DeleteTemp(rctx.Logger(), spool)
+
+	var missingFiles []string
+	written := make(map[string]*File)
+	for _, event := range events {
+		if event.File == nil {
+			continue
+		}
+		if exported, ok := written[event.File.Id]; ok {
+			event.File.ExportPath = exported.ExportPath
+			event.File.Sha256 = exported.Sha256
+			continue
+		}
+		written[event.File.Id] = event.File
+
+		postId := event.postId()
+		attachment := fileInfos[event.File.Id]
+		r, err := p.FileAttachmentBackend.Reader(attachment.Path)
+		if err != nil {
+			missingFiles = append(missingFiles, "Warning:"+shared.MissingFileMessageDuringBackendRead+" - Post: "+postId+" - "+attachment.Path)
+			rctx.Logger().Warn(shared.MissingFileMessageDuringBackendRead,
+				mlog.String("post_id", postId),
+				mlog.String("filename", attachment.Path),
+				mlog.Err(err),
+			)
+			continue
+		}
+
+		err = spoolFile(spool, r, buf)
+		r.Close()
+		if err != nil {
+			// s3 only errors _here_ if the object key wasn't found, so add a warning instead of failing the export,
+			// as every future run would also fail on this file (see csv_export.go).
+			missingFiles = append(missingFiles, "Warning:"+shared.MissingFileMessageDuringCopy+" - Post: "+postId+" - "+attachment.Path)
+			rctx.Logger().Warn(shared.MissingFileMessageDuringCopy,
+				mlog.String("post_id", postId),
+				mlog.String("filename", attachment.Path),
+				mlog.Err(err),
+			)
+			continue
+		}
+
+		exportPath := path.Join("files", postId, fmt.Sprintf("%s-%s", attachment.Id, path.Base(attachment.Path)))
+		dst, err := batch.create(exportPath)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = io.CopyBuffer(dst, spool, buf); err != nil {
+			return nil, err
+		}
+
+		event.File.ExportPath = exportPath
+		event.File.Sha256 = dst.sum()
+	}
+	return missingFiles, nil
+}
+
+// spoolFile replaces the content of the spool file with the content of r, and rewinds the spool file to read it.
+func spoolFile(spool *os.File, r io.Reader, buf []byte) error {
+	if err := spool.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.CopyBuffer(spool, r, buf); err != nil {
+		return err
+	}
+	_, err := spool.Seek(0, io.SeekStart)
+	return err
+}
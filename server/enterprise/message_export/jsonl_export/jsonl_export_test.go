@@ -0,0 +1,327 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.enterprise for license information.
+
+package jsonl_export
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store/storetest"
+	"github.com/mattermost/mattermost/server/v8/enterprise/message_export/shared"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
+)
+
+func testPost(id, rootId, userId string, createAt, updateAt int64, message string) *model.MessageExport {
+	channelType := model.ChannelTypeOpen
+	return &model.MessageExport{
+		PostId:             model.NewPointer(id),
+		PostOriginalId:     model.NewPointer(""),
+		PostRootId:         model.NewPointer(rootId),
+		TeamId:             model.NewPointer("team-id"),
+		TeamName:           model.NewPointer("team-name"),
+		TeamDisplayName:    model.NewPointer("Team"),
+		ChannelId:          model.NewPointer("channel-id"),
+		ChannelName:        model.NewPointer("town-square"),
+		ChannelDisplayName: model.NewPointer("Town Square"),
+		ChannelType:        &channelType,
+		PostCreateAt:       model.NewPointer(createAt),
+		PostUpdateAt:       model.NewPointer(updateAt),
+		PostMessage:        model.NewPointer(message),
+		PostProps:          model.NewPointer("{}"),
+		UserId:             model.NewPointer(userId),
+		UserEmail:          model.NewPointer(userId + "@example.com"),
+		Username:           model.NewPointer(userId),
+	}
+}
+
+func testChannelMemberHistories() map[string][]*model.ChannelMemberHistoryResult {
+	return map[string][]*model.ChannelMemberHistoryResult{
+		"channel-id": {
+			{ChannelId: "channel-id", UserId: "alice", UserEmail: "alice@example.com", Username: "alice", JoinTime: 0},
+			{ChannelId: "channel-id", UserId: "bob", UserEmail: "bob@example.com", Username: "bob", JoinTime: 5, LeaveTime: model.NewPointer(int64(50))},
+			{ChannelId: "channel-id", UserId: "carol", UserEmail: "carol@example.com", Username: "carol", JoinTime: 60},
+		},
+	}
+}
+
+func testChannelMetadata() map[string]*shared.MetadataChannel {
+	return map[string]*shared.MetadataChannel{
+		"channel-id": {
+			TeamId:             model.NewPointer("team-id"),
+			ChannelId:          "channel-id",
+			ChannelName:        "town-square",
+			ChannelDisplayName: "Town Square",
+			ChannelType:        model.ChannelTypeOpen,
+			StartTime:          1,
+			EndTime:            100,
+		},
+	}
+}
+
+func readZipFiles(t *testing.T, backend filestore.FileBackend, exportFileName string) map[string]string {
+	zipBytes, err := backend.ReadFile(exportFileName)
+	require.NoError(t, err)
+
+	zipReader, err := zip.NewReader(bytes.NewReader(zipBytes), int64(len(zipBytes)))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, f := range zipReader.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		files[f.Name] = string(data)
+	}
+	return files
+}
+
+func readEvents(t *testing.T, data string) []*Event {
+	var events []*Event
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		var event Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, &event)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// checkManifest checks that the manifest lists every other file of the batch with its checksum.
+func checkManifest(t *testing.T, files map[string]string) Manifest {
+	var manifest Manifest
+	require.NoError(t, json.Unmarshal([]byte(files[ManifestFilename]), &manifest))
+
+	require.Len(t, manifest.Files, len(files)-1)
+	var sums strings.Builder
+	for _, f := range manifest.Files {
+		content, ok := files[f.Name]
+		require.True(t, ok, f.Name)
+		assert.Equal(t, int64(len(content)), f.Size, f.Name)
+		assert.Equal(t, sha256Hex(content), f.Sha256, f.Name)
+		fmt.Fprintf(&sums, "%s  %s\n", f.Sha256, f.Name)
+	}
+	assert.Equal(t, sha256Hex(sums.String()), manifest.Sha256)
+
+	return manifest
+}
+
+func TestJsonlExport(t *testing.T) {
+	rctx := request.TestContext(t)
+
+	backend, err := filestore.NewFileBackend(filestore.FileBackendSettings{
+		DriverName: model.ImageDriverLocal,
+		Directory:  t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	_, err = backend.WriteFile(strings.NewReader("attachment content"), "data/report.txt")
+	require.NoError(t, err)
+
+	// The root post was updated by a reaction of carol.
+	rootPost := testPost("post-1", "", "alice", 10, 80, "Is the report ready?")
+	replyPost := testPost("post-2", "post-1", "carol", 70, 70, "Here it is <b>")
+	replyPost.PostFileIds = []string{"file-id"}
+	posts := []*model.MessageExport{rootPost, replyPost}
+
+	t.Run("events", func(t *testing.T) {
+		mockStore := &storetest.Store{}
+		defer mockStore.AssertExpectations(t)
+		mockStore.FileInfoStore.On("GetForPost", "post-2", true, true, false).Return([]*model.FileInfo{
+			{Id: "file-id", Name: "report.txt", Path: "data/report.txt", Size: 18, CreateAt: 70},
+		}, nil)
+		mockStore.ReactionStore.On("GetForPostsSince", []string{"post-1"}, int64(0)).Return([]*model.Reaction{
+			{UserId: "carol", PostId: "post-1", EmojiName: "+1", CreateAt: 80, UpdateAt: 80},
+		}, nil)
+
+		exportFileName := path.Join("export", "jobName", "jobName-batch001-jsonl.zip")
+		results, err := JsonlExport(rctx, shared.ExportParams{
+			ExportType:             model.ComplianceExportTypeJsonl,
+			ChannelMetadata:        testChannelMetadata(),
+			Posts:                  posts,
+			ChannelMemberHistories: testChannelMemberHistories(),
+			BatchPath:              exportFileName,
+			BatchStartTime:         1,
+			BatchEndTime:           100,
+			Db:                     shared.NewMessageExportStore(mockStore),
+			FileAttachmentBackend:  backend,
+			ExportBackend:          backend,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 0, results.NumWarnings)
+
+		files := readZipFiles(t, backend, exportFileName)
+		require.Len(t, files, 5)
+		assert.Equal(t, "attachment content", files["files/post-2/file-id-report.txt"])
+		assert.Contains(t, files, MetadataFilename)
+		assert.Equal(t, string(schema), files[SchemaFilename])
+		assert.Contains(t, files[EventsFilename], "Here it is <b>")
+
+		events := readEvents(t, files[EventsFilename])
+		type summary struct {
+			Type      EventType
+			Timestamp int64
+			UserId    string
+			PostId    string
+		}
+		var summaries []summary
+		for i, event := range events {
+			assert.Equal(t, SchemaVersion, event.SchemaVersion)
+			assert.Equal(t, i+1, event.Sequence)
+			assert.Equal(t, "channel-id", event.ChannelId)
+			summaries = append(summaries, summary{event.Type, event.Timestamp, event.UserId, event.postId()})
+		}
+		assert.Equal(t, []summary{
+			{EventChannelJoined, 5, "bob", ""},
+			{EventChannelLeft, 50, "bob", ""},
+			{EventChannelJoined, 60, "carol", ""},
+			{EventPostCreated, 70, "carol", "post-2"},
+			{EventFileUploaded, 70, "carol", "post-2"},
+			{EventPostUpdated, 80, "alice", "post-1"},
+			{EventReactionAdded, 80, "carol", "post-1"},
+		}, summaries)
+
+		reply := events[3]
+		assert.Equal(t, "post-1", reply.Post.RootId)
+		assert.Equal(t, []string{"file-id"}, reply.Post.FileIds)
+		assert.Equal(t, "team-name", reply.TeamName)
+
+		uploaded := events[4].File
+		require.NotNil(t, uploaded)
+		assert.Equal(t, "files/post-2/file-id-report.txt", uploaded.ExportPath)
+		assert.Equal(t, sha256Hex("attachment content"), uploaded.Sha256)
+
+		reaction := events[6]
+		require.NotNil(t, reaction.Reaction)
+		assert.Equal(t, "+1", reaction.Reaction.EmojiName)
+		assert.Equal(t, "carol@example.com", reaction.UserEmail)
+
+		manifest := checkManifest(t, files)
+		assert.Equal(t, SchemaVersion, manifest.SchemaVersion)
+		assert.Equal(t, 7, manifest.EventCount)
+		assert.Equal(t, 2, manifest.EventCounts[EventChannelJoined])
+		assert.Equal(t, int64(1), manifest.BatchStartTime)
+		assert.Equal(t, int64(100), manifest.BatchEndTime)
+	})
+
+	t.Run("missing attachment", func(t *testing.T) {
+		mockStore := &storetest.Store{}
+		defer mockStore.AssertExpectations(t)
+		mockStore.FileInfoStore.On("GetForPost", "post-2", true, true, false).Return([]*model.FileInfo{
+			{Id: "missing-id", Name: "missing.txt", Path: "data/missing.txt"},
+		}, nil)
+		mockStore.ReactionStore.On("GetForPostsSince", []string{"post-1"}, int64(0)).Return([]*model.Reaction{}, nil)
+
+		exportFileName := path.Join("export", "jobName", "jobName-batch002-jsonl.zip")
+		results, err := JsonlExport(rctx, shared.ExportParams{
+			ExportType:             model.ComplianceExportTypeJsonl,
+			ChannelMetadata:        testChannelMetadata(),
+			Posts:                  posts,
+			ChannelMemberHistories: testChannelMemberHistories(),
+			BatchPath:              exportFileName,
+			BatchStartTime:         1,
+			BatchEndTime:           100,
+			Db:                     shared.NewMessageExportStore(mockStore),
+			FileAttachmentBackend:  backend,
+			ExportBackend:          backend,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, results.NumWarnings)
+
+		files := readZipFiles(t, backend, exportFileName)
+		assert.Contains(t, files[JsonlWarningFilename], "data/missing.txt")
+
+		for _, event := range readEvents(t, files[EventsFilename]) {
+			if event.Type == EventFileUploaded {
+				assert.Equal(t, "missing-id", event.File.Id)
+				assert.Empty(t, event.File.ExportPath)
+				assert.Empty(t, event.File.Sha256)
+			}
+		}
+
+		checkManifest(t, files)
+	})
+}
+
+func TestPostExportToEvent(t *testing.T) {
+	post := testPost("post-id", "", "alice", 10, 20, "new message")
+	post.PostEditAt = model.NewPointer(int64(20))
+	post.PostProps = model.NewPointer(`{"previewed_post":"other-post-id"}`)
+
+	original := testPost("original-id", "", "alice", 10, 20, "old message")
+	original.PostDeleteAt = model.NewPointer(int64(20))
+	original.PostOriginalId = model.NewPointer("post-id")
+
+	edited := postExportToEvent(shared.PostExport{MessageExport: *post, UpdatedType: shared.EditedNewMsg, UpdateAt: 20})
+	previous := postExportToEvent(shared.PostExport{MessageExport: *original, UpdatedType: shared.EditedOriginalMsg, UpdateAt: 20, EditedNewMsgId: "post-id"})
+
+	assert.Equal(t, EventPostEdited, edited.Type)
+	assert.Equal(t, int64(20), edited.Timestamp)
+	assert.Equal(t, "new message", edited.Post.Message)
+	assert.Equal(t, "other-post-id", edited.Post.PreviewsPostId)
+	assert.JSONEq(t, `{"previewed_post":"other-post-id"}`, string(edited.Post.Props))
+
+	assert.Equal(t, EventPostPreviousVersion, previous.Type)
+	assert.Equal(t, "old message", previous.Post.Message)
+	assert.Equal(t, "post-id", previous.Post.EditedPostId)
+	assert.Nil(t, previous.Post.Props)
+
+	// the previous version comes first
+	assert.Negative(t, compareEvents(previous, edited))
+
+	created := postExportToEvent(shared.PostExport{MessageExport: *testPost("new-id", "", "bob", 5, 5, "hello")})
+	assert.Equal(t, EventPostCreated, created.Type)
+	assert.Equal(t, int64(5), created.Timestamp)
+}
+
+// TestSchema checks that schema.json documents every field of the events and of the manifest.
+func TestSchema(t *testing.T) {
+	var doc struct {
+		Defs map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(schema, &doc))
+
+	for def, value := range map[string]any{
+		"event":    Event{},
+		"post":     Post{},
+		"file":     File{},
+		"reaction": Reaction{},
+		"manifest": Manifest{},
+	} {
+		typ := reflect.TypeOf(value)
+		var fields []string
+		for i := 0; i < typ.NumField(); i++ {
+			fields = append(fields, strings.Split(typ.Field(i).Tag.Get("json"), ",")[0])
+		}
+		documented := make([]string, 0, len(doc.Defs[def].Properties))
+		for name := range doc.Defs[def].Properties {
+			documented = append(documented, name)
+		}
+		assert.ElementsMatch(t, fields, documented, def)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.enterprise for license information.
+
+package jsonl_export
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+)
+
+// Manifest describes the content of a batch, so that the downstream jobs can check that they received all of it.
+type Manifest struct {
+	SchemaVersion  int               `json:"schema_version"`
+	BatchStartTime int64             `json:"batch_start_time"` // the batch has the events after this time
+	BatchEndTime   int64             `json:"batch_end_time"`   // up to this time, included
+	EventCount     int               `json:"event_count"`      // the number of lines of events.jsonl
+	EventCounts    map[EventType]int `json:"event_counts"`
+	Files          []ManifestFile    `json:"files"` // every file of the batch, except the manifest
+
+	// Sha256 is the SHA-256 of the list of the files in the format of sha256sum: a "<sha256>  <name>\n" line per file,
+	// in the order of Files.
+	Sha256 string `json:"sha256"`
+}
+
+type ManifestFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	Sha256 string `json:"sha256"`
+}
+
+// checksumWriter computes the size and the checksum of a file of the batch while it is written.
+type checksumWriter struct {
+	name string
+	w    io.Writer
+	hash hash.Hash
+	size int64
+}
+
+func (c *checksumWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.hash.Write(p[:n])
+	c.size += int64(n)
+	return n, err
+}
+
+func (c *checksumWriter) sum() string {
+	return hex.EncodeToString(c.hash.Sum(nil))
+}
+
+// batchWriter writes the files of a batch to its zip file, and lists them with their checksums for the manifest.
+type batchWriter struct {
+	zip   *zip.Writer
+	files []*checksumWriter
+}
+
+func newBatchWriter(zipFile *zip.Writer) *batchWriter {
+	return &batchWriter{zip: zipFile}
+}
+
+func (b *batchWriter) create(name string) (*checksumWriter, error) {
+	w, err := b.zip.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	file := &checksumWriter{name: name, w: w, hash: sha256.New()}
+	b.files = append(b.files, file)
+	return file, nil
+}
+
+// manifest returns the manifest of the files written so far.
+func (b *batchWriter) manifest(startTime, endTime int64, events []*Event) Manifest {
+	manifest := Manifest{
+		SchemaVersion:  SchemaVersion,
+		BatchStartTime: startTime,
+		BatchEndTime:   endTime,
+		EventCount:     len(events),
+		EventCounts:    make(map[EventType]int),
+		Files:          make([]ManifestFile, 0, len(b.files)),
+	}
+	for _, event := range events {
+		manifest.EventCounts[event.Type]++
+	}
+
+	var sums strings.Builder
+	for _, file := range b.files {
+		sum := file.sum()
+		manifest.Files = append(manifest.Files, ManifestFile{Name: file.name, Size: file.size, Sha256: sum})
+		fmt.Fprintf(&sums, "%s  %s\n", sum, file.name)
+	}
+	batchSum := sha256.Sum256([]byte(sums.String()))
+	manifest.Sha256 = hex.EncodeToString(batchSum[:])
+
+	return manifest
+}
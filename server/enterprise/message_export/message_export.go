@@ -17,8 +17,11 @@ import (
 	ejobs "github.com/mattermost/mattermost/server/v8/einterfaces/jobs"
 	"github.com/mattermost/mattermost/server/v8/enterprise/message_export/actiance_export"
 	"github.com/mattermost/mattermost/server/v8/enterprise/message_export/csv_export"
+	"github.com/mattermost/mattermost/server/v8/enterprise/message_export/eml_export"
 	"github.com/mattermost/mattermost/server/v8/enterprise/message_export/global_relay_export"
+	"github.com/mattermost/mattermost/server/v8/enterprise/message_export/jsonl_export"
 	"github.com/mattermost/mattermost/server/v8/enterprise/message_export/shared"
+	"github.com/mattermost/mattermost/server/v8/enterprise/message_export/webhook_export"
 )
 
 const GlobalRelayExportFilename = "global-relay.zip"
@@ -99,6 +102,7 @@ func RunBatch(rctx request.CTX, data shared.JobData, params shared.BackendParams
 	data.TotalBatchMs = append(data.TotalBatchMs, time.Since(start).Milliseconds())
 	data.WarningCount += res.NumWarnings
 	data.BatchStartTime = data.BatchEndTime
+	data.BatchStartId = data.Cursor.LastPostId
 
 	return res, data, err
 }
@@ -150,6 +154,7 @@ type ExportParams struct {
 	JobStartTime           int64
 	BatchPath              string
 	BatchStartTime         int64
+	BatchStartId           string
 	BatchEndTime           int64
 }
 
@@ -162,6 +167,7 @@ func DataToExportParams(data shared.JobData) ExportParams {
 		JobStartTime:           data.JobStartTime,
 		BatchPath:              data.BatchPath,
 		BatchStartTime:         data.BatchStartTime,
+		BatchStartId:           data.BatchStartId,
 		BatchEndTime:           data.BatchEndTime,
 	}
 }
@@ -174,15 +180,18 @@ func RunExportByType(rctx request.CTX, p ExportParams, b shared.BackendParams) (
 		ChannelMetadata:        p.ChannelMetadata,
 		Posts:                  p.PostsToExport,
 		ChannelMemberHistories: p.ChannelMemberHistories,
+		JobId:                  b.JobId,
 		JobStartTime:           p.JobStartTime,
 		BatchPath:              p.BatchPath,
 		BatchStartTime:         p.BatchStartTime,
+		BatchStartId:           p.BatchStartId,
 		BatchEndTime:           p.BatchEndTime,
 		Config:                 b.Config,
 		Db:                     b.Store,
 		FileAttachmentBackend:  b.FileAttachmentBackend,
 		ExportBackend:          b.ExportBackend,
 		Templates:              b.HtmlTemplates,
+		HTTPService:            b.HTTPService,
 	}
 
 	switch p.ExportType {
@@ -198,6 +207,18 @@ func RunExportByType(rctx request.CTX, p ExportParams, b shared.BackendParams) (
 		rctx.Logger().Debug("Exporting GlobalRelay")
 		return global_relay_export.GlobalRelayExport(rctx, exportParams)
 
+	case model.ComplianceExportTypeEml, model.ComplianceExportTypeMbox:
+		rctx.Logger().Debug("Exporting EML")
+		return eml_export.EmlExport(rctx, exportParams)
+
+	case model.ComplianceExportTypeJsonl:
+		rctx.Logger().Debug("Exporting JSONL")
+		return jsonl_export.JsonlExport(rctx, exportParams)
+
+	case model.ComplianceExportTypeWebhook:
+		rctx.Logger().Debug("Exporting to webhook")
+		return webhook_export.WebhookExport(rctx, exportParams)
+
 	default:
 		return results, errors.New("Unknown output format: " + p.ExportType)
 	}
@@ -35,6 +35,11 @@ func (s *MessageExportScheduler) Enabled(cfg *model.Config) bool {
 func (s *MessageExportScheduler) NextScheduleTime(cfg *model.Config, now time.Time, _ bool, _ *model.Job) *time.Time {
 	// We set the next scheduled time regardless of whether there is a running or pending job
 	// In ScheduleJob we check pending or running jobs, before actually scheduling a job
+	if *cfg.MessageExportSettings.ExportFormat == model.ComplianceExportTypeWebhook {
+		// The webhook export streams the new messages in small batches through the day.
+		next := now.Add(time.Duration(*cfg.MessageExportSettings.WebhookSettings.RunIntervalMinutes) * time.Minute)
+		return &next
+	}
 	parsedTime, err := time.Parse("15:04", *cfg.MessageExportSettings.DailyRunTime)
 	if err != nil {
 		s.jobServer.Logger().Error(
@@ -5,9 +5,11 @@ package message_export
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/v8/channels/api4"
@@ -96,3 +98,27 @@ func TestMessageExportJobPending(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Nil(t, job)
 }
+
+func TestMessageExportNextScheduleTime(t *testing.T) {
+	scheduler := NewMessageExportScheduler(nil, nil)
+	now := time.Date(2024, time.March, 1, 8, 0, 0, 0, time.Local)
+
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+	*cfg.MessageExportSettings.DailyRunTime = "10:40"
+
+	t.Run("daily run time", func(t *testing.T) {
+		next := scheduler.NextScheduleTime(cfg, now, false, nil)
+		require.NotNil(t, next)
+		assert.Equal(t, time.Date(2024, time.March, 1, 10, 40, 0, 0, time.Local), *next)
+	})
+
+	t.Run("webhook run interval", func(t *testing.T) {
+		*cfg.MessageExportSettings.ExportFormat = model.ComplianceExportTypeWebhook
+		*cfg.MessageExportSettings.WebhookSettings.RunIntervalMinutes = 15
+
+		next := scheduler.NextScheduleTime(cfg, now, false, nil)
+		require.NotNil(t, next)
+		assert.Equal(t, now.Add(15*time.Minute), *next)
+	})
+}
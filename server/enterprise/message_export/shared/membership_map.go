@@ -1,12 +1,12 @@
 // Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
 // See LICENSE.enterprise for license information.
 
-package message_export
+package shared
 
 type MembershipMapUser struct {
-	userId   string
-	email    string
-	username string
+	UserId   string
+	Email    string
+	Username string
 }
 
 // Provides a clean interface for tracking the users that are present in any number of channels by channel id and user email
@@ -23,8 +23,8 @@ func (m *MembershipMap) init(channelId string) {
 
 func (m *MembershipMap) AddUserToChannel(channelId string, user MembershipMapUser) {
 	m.init(channelId)
-	if !m.IsUserInChannel(channelId, user.email) {
-		(*m)[channelId][user.email] = user
+	if !m.IsUserInChannel(channelId, user.Email) {
+		(*m)[channelId][user.Email] = user
 	}
 }
 
@@ -1,7 +1,7 @@
 // Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
 // See LICENSE.enterprise for license information.
 
-package message_export
+package shared
 
 import (
 	"testing"
@@ -17,63 +17,63 @@ func TestMembershipMap(t *testing.T) {
 	channelId := model.NewId()
 
 	user1 := &MembershipMapUser{
-		email:    model.NewId() + "@mattermost.com",
-		username: model.NewId(),
-		userId:   model.NewId(),
+		Email:    model.NewId() + "@mattermost.com",
+		Username: model.NewId(),
+		UserId:   model.NewId(),
 	}
 	user2 := &MembershipMapUser{
-		email:    model.NewId() + "@mattermost.com",
-		username: model.NewId(),
-		userId:   model.NewId(),
+		Email:    model.NewId() + "@mattermost.com",
+		Username: model.NewId(),
+		UserId:   model.NewId(),
 	}
 
-	assert.False(t, membershipMap.IsUserInChannel(channelId, user1.email))
+	assert.False(t, membershipMap.IsUserInChannel(channelId, user1.Email))
 	membershipMap.AddUserToChannel(channelId, *user1)
-	assert.True(t, membershipMap.IsUserInChannel(channelId, user1.email))
+	assert.True(t, membershipMap.IsUserInChannel(channelId, user1.Email))
 
-	assert.False(t, membershipMap.IsUserInChannel(channelId, user2.email))
+	assert.False(t, membershipMap.IsUserInChannel(channelId, user2.Email))
 	membershipMap.AddUserToChannel(channelId, *user2)
-	assert.True(t, membershipMap.IsUserInChannel(channelId, user2.email))
+	assert.True(t, membershipMap.IsUserInChannel(channelId, user2.Email))
 
 	// ensure that the correct user emails are returned
 	emails := membershipMap.GetUserEmailsInChannel(channelId)
 	assert.Len(t, emails, 2)
-	assert.Contains(t, emails, user1.email)
-	assert.Contains(t, emails, user2.email)
+	assert.Contains(t, emails, user1.Email)
+	assert.Contains(t, emails, user2.Email)
 
 	// ensure that the correct user objects are returned
 	users := membershipMap.GetUsersInChannel(channelId)
 	assert.Len(t, users, 2)
-	if users[0].userId == user1.userId {
-		assert.Equal(t, user1.username, users[0].username)
-		assert.Equal(t, user1.email, users[0].email)
-		assert.Equal(t, user2.userId, users[1].userId)
-		assert.Equal(t, user2.username, users[1].username)
-		assert.Equal(t, user2.email, users[1].email)
-	} else if users[0].userId == user2.userId {
-		assert.Equal(t, user2.username, users[0].username)
-		assert.Equal(t, user2.email, users[0].email)
-		assert.Equal(t, user1.userId, users[1].userId)
-		assert.Equal(t, user1.username, users[1].username)
-		assert.Equal(t, user1.email, users[1].email)
+	if users[0].UserId == user1.UserId {
+		assert.Equal(t, user1.Username, users[0].Username)
+		assert.Equal(t, user1.Email, users[0].Email)
+		assert.Equal(t, user2.UserId, users[1].UserId)
+		assert.Equal(t, user2.Username, users[1].Username)
+		assert.Equal(t, user2.Email, users[1].Email)
+	} else if users[0].UserId == user2.UserId {
+		assert.Equal(t, user2.Username, users[0].Username)
+		assert.Equal(t, user2.Email, users[0].Email)
+		assert.Equal(t, user1.UserId, users[1].UserId)
+		assert.Equal(t, user1.Username, users[1].Username)
+		assert.Equal(t, user1.Email, users[1].Email)
 	} else {
 		assert.Fail(t, "First returned user is not recognized")
 	}
 
 	// remove user1 from the channel
-	membershipMap.RemoveUserFromChannel(channelId, user1.email)
-	assert.False(t, membershipMap.IsUserInChannel(channelId, user1.email))
-	assert.True(t, membershipMap.IsUserInChannel(channelId, user2.email))
+	membershipMap.RemoveUserFromChannel(channelId, user1.Email)
+	assert.False(t, membershipMap.IsUserInChannel(channelId, user1.Email))
+	assert.True(t, membershipMap.IsUserInChannel(channelId, user2.Email))
 
 	// ensure that user2's email is returned
 	emails = membershipMap.GetUserEmailsInChannel(channelId)
 	assert.Len(t, emails, 1)
-	assert.Contains(t, emails, user2.email)
+	assert.Contains(t, emails, user2.Email)
 
 	// ensure that only user2 is returned
 	users = membershipMap.GetUsersInChannel(channelId)
 	assert.Len(t, users, 1)
-	assert.Equal(t, user2.userId, users[0].userId)
-	assert.Equal(t, user2.username, users[0].username)
-	assert.Equal(t, user2.email, users[0].email)
+	assert.Equal(t, user2.UserId, users[0].UserId)
+	assert.Equal(t, user2.Username, users[0].Username)
+	assert.Equal(t, user2.Email, users[0].Email)
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/mattermost/mattermost/server/v8/platform/shared/templates"
 
 	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/httpservice"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/mattermost/mattermost/server/public/shared/request"
 )
@@ -232,6 +233,8 @@ type BackendParams struct {
 	FileAttachmentBackend filestore.FileBackend
 	ExportBackend         filestore.FileBackend
 	HtmlTemplates         *templates.Container
+	HTTPService           httpservice.HTTPService
+	JobId                 string
 }
 
 type ExportParams struct {
@@ -239,15 +242,18 @@ type ExportParams struct {
 	ChannelMetadata        map[string]*MetadataChannel
 	Posts                  []*model.MessageExport
 	ChannelMemberHistories map[string][]*model.ChannelMemberHistoryResult
+	JobId                  string
 	JobStartTime           int64
 	BatchPath              string
 	BatchStartTime         int64
+	BatchStartId           string
 	BatchEndTime           int64
 	Config                 *model.Config
 	Db                     MessageExportStore
 	FileAttachmentBackend  filestore.FileBackend
 	ExportBackend          filestore.FileBackend
 	Templates              *templates.Container
+	HTTPService            httpservice.HTTPService
 }
 
 type WriteExportResult struct {
@@ -560,7 +566,7 @@ func GetBatchPath(exportDir string, prevPostUpdateAt int64, lastPostUpdateAt int
 }
 
 // GetExportBackend returns the file backend where the export will be created.
-func GetExportBackend(rctx request.CTX, config *model.Config) (filestore.FileBackend, error) {
+func GetExportBackend(rctx request.CTX, config *model.Config, blobRefStore filestore.BlobRefStore) (filestore.FileBackend, error) {
 	insecure := config.ServiceSettings.EnableInsecureOutgoingConnections
 	skipVerify := insecure != nil && *insecure
 
@@ -574,7 +580,9 @@ func GetExportBackend(rctx request.CTX, config *model.Config) (filestore.FileBac
 		return backend, nil
 	}
 
-	backend, err := filestore.NewFileBackend(filestore.NewFileBackendSettingsFromConfig(&config.FileSettings, true, skipVerify))
+	settings := filestore.NewFileBackendSettingsFromConfig(&config.FileSettings, true, skipVerify)
+	settings.BlobRefStore = blobRefStore
+	backend, err := filestore.NewFileBackend(settings)
 	if err != nil {
 		return nil, err
 	}
@@ -584,10 +592,12 @@ func GetExportBackend(rctx request.CTX, config *model.Config) (filestore.FileBac
 // GetFileAttachmentBackend returns the file backend where file attachments are
 // located for messages that will be exported. This may be the same backend
 // where the export will be created.
-func GetFileAttachmentBackend(rctx request.CTX, config *model.Config) (filestore.FileBackend, error) {
+func GetFileAttachmentBackend(rctx request.CTX, config *model.Config, blobRefStore filestore.BlobRefStore) (filestore.FileBackend, error) {
 	insecure := config.ServiceSettings.EnableInsecureOutgoingConnections
 
-	backend, err := filestore.NewFileBackend(filestore.NewFileBackendSettingsFromConfig(&config.FileSettings, true, insecure != nil && *insecure))
+	settings := filestore.NewFileBackendSettingsFromConfig(&config.FileSettings, true, insecure != nil && *insecure)
+	settings.BlobRefStore = blobRefStore
+	backend, err := filestore.NewFileBackend(settings)
 	if err != nil {
 		return nil, err
 	}
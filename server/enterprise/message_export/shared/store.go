@@ -14,6 +14,7 @@ type MessageExportStore interface {
 	Channel() store.ChannelStore
 	Compliance() store.ComplianceStore
 	FileInfo() MEFileInfoStore
+	Reaction() store.ReactionStore
 }
 
 type MEFileInfoStore interface {
@@ -0,0 +1,228 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.enterprise for license information.
+
+// Package webhook_export streams the batches to an HTTP endpoint instead of writing them to the export file backend.
+//
+// Each batch is POSTed as JSON Lines, with the events described in jsonl_export. The request is signed with the
+// signing secret: the SignatureHeader is "sha256=" followed by the hex HMAC-SHA256 of the TimestampHeader value, a
+// '.', and the body. The receiver should check the signature and the timestamp, and acknowledge the batch with a 2xx
+// status. A batch may be delivered more than once, e.g. after a restart of the server, always with the same
+// DeliveryIdHeader: it is derived from the job and the position of the batch in the export, not from its content.
+package webhook_export
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost/server/v8/enterprise/message_export/jsonl_export"
+	"github.com/mattermost/mattermost/server/v8/enterprise/message_export/shared"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/httpservice"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+const (
+	SignatureHeader     = "X-Mattermost-Signature"
+	TimestampHeader     = "X-Mattermost-Timestamp"
+	DeliveryIdHeader    = "X-Mattermost-Delivery-Id"
+	SchemaVersionHeader = "X-Mattermost-Schema-Version"
+	BatchStartHeader    = "X-Mattermost-Batch-Start-Time"
+	BatchEndHeader      = "X-Mattermost-Batch-End-Time"
+	EventCountHeader    = "X-Mattermost-Event-Count"
+
+	ContentType = "application/x-ndjson"
+
+	maxRetryDelay = time.Minute
+
+	// maxErrorBodySize caps the part of the response body added to the delivery errors.
+	maxErrorBodySize = 1024
+)
+
+// retryBaseDelay is the delay before the first retry, doubled at each retry.
+var retryBaseDelay = time.Second
+
+type Batch struct {
+	JobId      string
+	StartTime  int64
+	StartId    string // the id of the last post of the previous batch
+	EndTime    int64
+	EventCount int
+	Body       []byte
+}
+
+// permanentError is a delivery error that retrying would not fix, e.g. the endpoint rejecting the signature.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+func WebhookExport(rctx request.CTX, p shared.ExportParams) (shared.RunExportResults, error) {
+	exportData, err := shared.GetGenericExportData(p)
+	results := exportData.Results
+	if err != nil {
+		return results, err
+	}
+
+	start := time.Now()
+	events, err := jsonl_export.Events(p, exportData)
+	if err != nil {
+		return results, err
+	}
+	if len(events) == 0 {
+		return results, nil
+	}
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	encoder.SetEscapeHTML(false)
+	for _, event := range events {
+		if err = encoder.Encode(event); err != nil {
+			return results, fmt.Errorf("unable to export an event: %w", err)
+		}
+	}
+	results.ProcessingPostsMs = time.Since(start).Milliseconds()
+
+	start = time.Now()
+	err = Deliver(rctx, p.HTTPService, p.Config.MessageExportSettings.WebhookSettings, Batch{
+		JobId:      p.JobId,
+		StartTime:  p.BatchStartTime,
+		StartId:    p.BatchStartId,
+		EndTime:    p.BatchEndTime,
+		EventCount: len(events),
+		Body:       body.Bytes(),
+	})
+	if err != nil {
+		return results, err
+	}
+	results.TransferringZipMs = time.Since(start).Milliseconds()
+
+	return results, nil
+}
+
+// Sign returns the value of the SignatureHeader of a request.
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// DeliveryId returns the value of the DeliveryIdHeader of the batch. A job exports the same batch again from the same
+// cursor, so it is the same for all the deliveries of a batch whatever its content.
+func (b Batch) DeliveryId() string {
+	id := sha256.Sum256([]byte(b.JobId + ":" + strconv.FormatInt(b.StartTime, 10) + ":" + b.StartId))
+	return hex.EncodeToString(id[:])
+}
+
+// Deliver posts the batch to the webhook, retrying with an exponential backoff until the webhook accepts it.
+func Deliver(rctx request.CTX, httpService httpservice.HTTPService, settings *model.WebhookMessageExportSettings, batch Batch) error {
+	// The webhook is configured by the system admin, so it may be hosted on the internal network.
+	client := httpService.MakeClient(true)
+	client.Timeout = time.Duration(*settings.RequestTimeoutSeconds) * time.Second
+	deliveryId := batch.DeliveryId()
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		var retryAfter time.Duration
+		retryAfter, err = post(rctx.Context(), client, settings, batch, deliveryId)
+		if err == nil {
+			return nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt >= *settings.MaxRetries {
+			break
+		}
+
+		delay := retryDelay(attempt, retryAfter)
+		rctx.Logger().Warn("Failed to deliver the message export batch to the webhook, retrying",
+			mlog.Int("attempt", attempt+1),
+			mlog.Duration("retry_in", delay),
+			mlog.Err(err),
+		)
+		select {
+		case <-rctx.Context().Done():
+			return fmt.Errorf("unable to deliver the batch to the webhook: %w", rctx.Context().Err())
+		case <-time.After(delay):
+		}
+	}
+	return fmt.Errorf("unable to deliver the batch to the webhook: %w", err)
+}
+
+// post sends the batch once. It returns the delay asked by the webhook before retrying, if any.
+func post(ctx context.Context, client *http.Client, settings *model.WebhookMessageExportSettings, batch Batch, deliveryId string) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, *settings.URL, bytes.NewReader(batch.Body))
+	if err != nil {
+		return 0, &permanentError{fmt.Errorf("unable to create the request: %w", err)}
+	}
+
+	timestamp := strconv.FormatInt(model.GetMillis(), 10)
+	req.Header.Set("Content-Type", ContentType)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(*settings.SigningSecret, timestamp, batch.Body))
+	req.Header.Set(DeliveryIdHeader, deliveryId)
+	req.Header.Set(SchemaVersionHeader, strconv.Itoa(jsonl_export.SchemaVersion))
+	req.Header.Set(BatchStartHeader, strconv.FormatInt(batch.StartTime, 10))
+	req.Header.Set(BatchEndHeader, strconv.FormatInt(batch.EndTime, 10))
+	req.Header.Set(EventCountHeader, strconv.Itoa(batch.EventCount))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return 0, nil
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	err = fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, bytes.TrimSpace(message))
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		return parseRetryAfter(resp.Header.Get("Retry-After")), err
+	case resp.StatusCode == http.StatusRequestTimeout:
+		return 0, err
+	default:
+		return 0, &permanentError{err}
+	}
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+func retryDelay(attempt int, retryAfter time.Duration) time.Duration {
+	delay := retryBaseDelay
+	for i := 0; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(max(delay, retryAfter), maxRetryDelay)
+}
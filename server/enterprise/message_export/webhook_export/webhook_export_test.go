@@ -0,0 +1,286 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.enterprise for license information.
+
+package webhook_export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/httpservice"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store/storetest"
+	"github.com/mattermost/mattermost/server/v8/channels/utils/testutils"
+	"github.com/mattermost/mattermost/server/v8/enterprise/message_export/jsonl_export"
+	"github.com/mattermost/mattermost/server/v8/enterprise/message_export/shared"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// testReceiver is a webhook answering the requests with the given statuses, then with 200.
+type testReceiver struct {
+	*httptest.Server
+
+	mut      sync.Mutex
+	statuses []int
+	requests []receivedRequest
+}
+
+func newTestReceiver(t *testing.T, statuses ...int) *testReceiver {
+	r := &testReceiver{statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		r.mut.Lock()
+		defer r.mut.Unlock()
+		r.requests = append(r.requests, receivedRequest{header: req.Header.Clone(), body: body})
+		if len(r.statuses) > 0 {
+			status := r.statuses[0]
+			r.statuses = r.statuses[1:]
+			w.WriteHeader(status)
+			return
+		}
+		// A receiver checks the signature of the batch before acknowledging it.
+		if Sign(testSecret, req.Header.Get(TimestampHeader), body) != req.Header.Get(SignatureHeader) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *testReceiver) received() []receivedRequest {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	return append([]receivedRequest(nil), r.requests...)
+}
+
+func testSettings(url string) *model.WebhookMessageExportSettings {
+	settings := &model.WebhookMessageExportSettings{
+		URL:           model.NewPointer(url),
+		SigningSecret: model.NewPointer(testSecret),
+		MaxRetries:    model.NewPointer(2),
+	}
+	settings.SetDefaults()
+	return settings
+}
+
+func testHTTPService() httpservice.HTTPService {
+	config := &model.Config{}
+	config.SetDefaults()
+	return httpservice.MakeHTTPService(&testutils.StaticConfigService{Cfg: config})
+}
+
+func setFastRetries(t *testing.T) {
+	previous := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	t.Cleanup(func() { retryBaseDelay = previous })
+}
+
+func TestDeliver(t *testing.T) {
+	rctx := request.TestContext(t)
+	setFastRetries(t)
+	batch := Batch{JobId: model.NewId(), StartTime: 1, StartId: model.NewId(), EndTime: 100, EventCount: 1, Body: []byte(`{"sequence":1}` + "\n")}
+
+	t.Run("signed delivery", func(t *testing.T) {
+		receiver := newTestReceiver(t)
+		require.NoError(t, Deliver(rctx, testHTTPService(), testSettings(receiver.URL), batch))
+
+		requests := receiver.received()
+		require.Len(t, requests, 1)
+		header := requests[0].header
+		assert.Equal(t, batch.Body, requests[0].body)
+		assert.Equal(t, ContentType, header.Get("Content-Type"))
+		assert.Equal(t, Sign(testSecret, header.Get(TimestampHeader), batch.Body), header.Get(SignatureHeader))
+		assert.NotEqual(t, Sign("another secret", header.Get(TimestampHeader), batch.Body), header.Get(SignatureHeader))
+		assert.Equal(t, "1", header.Get(BatchStartHeader))
+		assert.Equal(t, "100", header.Get(BatchEndHeader))
+		assert.Equal(t, "1", header.Get(EventCountHeader))
+		assert.Equal(t, strconv.Itoa(jsonl_export.SchemaVersion), header.Get(SchemaVersionHeader))
+		assert.Equal(t, batch.DeliveryId(), header.Get(DeliveryIdHeader))
+	})
+
+	t.Run("retries the server errors", func(t *testing.T) {
+		receiver := newTestReceiver(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+		require.NoError(t, Deliver(rctx, testHTTPService(), testSettings(receiver.URL), batch))
+
+		requests := receiver.received()
+		require.Len(t, requests, 3)
+		// the retries are the same delivery
+		assert.Equal(t, requests[0].header.Get(DeliveryIdHeader), requests[2].header.Get(DeliveryIdHeader))
+	})
+
+	t.Run("gives up after the retries", func(t *testing.T) {
+		receiver := newTestReceiver(t, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+		err := Deliver(rctx, testHTTPService(), testSettings(receiver.URL), batch)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "502")
+		assert.Len(t, receiver.received(), 3)
+	})
+
+	t.Run("does not retry a rejected batch", func(t *testing.T) {
+		receiver := newTestReceiver(t, http.StatusBadRequest)
+		require.Error(t, Deliver(rctx, testHTTPService(), testSettings(receiver.URL), batch))
+		assert.Len(t, receiver.received(), 1)
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		receiver := newTestReceiver(t)
+		settings := testSettings(receiver.URL)
+		settings.SigningSecret = model.NewPointer("another secret, another secret!!")
+		require.Error(t, Deliver(rctx, testHTTPService(), settings, batch))
+		assert.Len(t, receiver.received(), 1)
+	})
+}
+
+func TestDeliveryId(t *testing.T) {
+	batch := Batch{JobId: model.NewId(), StartTime: 1, StartId: model.NewId(), EndTime: 100, EventCount: 1, Body: []byte("{}\n")}
+	assert.Len(t, batch.DeliveryId(), 64)
+
+	// exporting the batch again gives the same delivery, even if the content changed in the meantime
+	again := batch
+	again.EventCount = 2
+	again.Body = []byte("{}\n{}\n")
+	assert.Equal(t, batch.DeliveryId(), again.DeliveryId())
+
+	otherJob := batch
+	otherJob.JobId = model.NewId()
+	assert.NotEqual(t, batch.DeliveryId(), otherJob.DeliveryId())
+
+	nextBatch := batch
+	nextBatch.StartTime = 100
+	assert.NotEqual(t, batch.DeliveryId(), nextBatch.DeliveryId())
+
+	samePage := batch
+	samePage.StartId = model.NewId()
+	assert.NotEqual(t, batch.DeliveryId(), samePage.DeliveryId())
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, retryBaseDelay, retryDelay(0, 0))
+	assert.Equal(t, 4*retryBaseDelay, retryDelay(2, 0))
+	assert.Equal(t, 30*time.Second, retryDelay(0, 30*time.Second))
+	assert.Equal(t, maxRetryDelay, retryDelay(100, 0))
+	assert.Equal(t, maxRetryDelay, retryDelay(0, time.Hour))
+
+	assert.Equal(t, 10*time.Second, parseRetryAfter("10"))
+	assert.Zero(t, parseRetryAfter(""))
+	assert.Zero(t, parseRetryAfter("soon"))
+}
+
+func TestWebhookExport(t *testing.T) {
+	rctx := request.TestContext(t)
+
+	channelType := model.ChannelTypeOpen
+	post := &model.MessageExport{
+		PostId:             model.NewPointer("post-id"),
+		PostOriginalId:     model.NewPointer(""),
+		PostRootId:         model.NewPointer(""),
+		TeamId:             model.NewPointer("team-id"),
+		TeamName:           model.NewPointer("team-name"),
+		TeamDisplayName:    model.NewPointer("Team"),
+		ChannelId:          model.NewPointer("channel-id"),
+		ChannelName:        model.NewPointer("town-square"),
+		ChannelDisplayName: model.NewPointer("Town Square"),
+		ChannelType:        &channelType,
+		PostCreateAt:       model.NewPointer(int64(10)),
+		PostUpdateAt:       model.NewPointer(int64(10)),
+		PostMessage:        model.NewPointer("hello"),
+		PostProps:          model.NewPointer("{}"),
+		UserId:             model.NewPointer("alice"),
+		UserEmail:          model.NewPointer("alice@example.com"),
+		Username:           model.NewPointer("alice"),
+	}
+
+	params := func(url string, posts []*model.MessageExport) shared.ExportParams {
+		mockStore := &storetest.Store{}
+		t.Cleanup(func() { mockStore.AssertExpectations(t) })
+
+		config := &model.Config{}
+		config.SetDefaults()
+		config.MessageExportSettings.WebhookSettings = testSettings(url)
+
+		return shared.ExportParams{
+			ExportType: model.ComplianceExportTypeWebhook,
+			ChannelMetadata: map[string]*shared.MetadataChannel{
+				"channel-id": {
+					TeamId:             model.NewPointer("team-id"),
+					ChannelId:          "channel-id",
+					ChannelName:        "town-square",
+					ChannelDisplayName: "Town Square",
+					ChannelType:        model.ChannelTypeOpen,
+					StartTime:          1,
+					EndTime:            100,
+				},
+			},
+			Posts: posts,
+			ChannelMemberHistories: map[string][]*model.ChannelMemberHistoryResult{
+				"channel-id": {
+					{ChannelId: "channel-id", UserId: "alice", UserEmail: "alice@example.com", Username: "alice", JoinTime: 0},
+				},
+			},
+			JobId:          "job-id",
+			BatchStartTime: 1,
+			BatchEndTime:   100,
+			Config:         config,
+			Db:             shared.NewMessageExportStore(mockStore),
+			HTTPService:    testHTTPService(),
+		}
+	}
+
+	t.Run("delivers the events", func(t *testing.T) {
+		receiver := newTestReceiver(t)
+		_, err := WebhookExport(rctx, params(receiver.URL, []*model.MessageExport{post}))
+		require.NoError(t, err)
+
+		requests := receiver.received()
+		require.Len(t, requests, 1)
+		assert.Equal(t, "1", requests[0].header.Get(EventCountHeader))
+		assert.Equal(t, Batch{JobId: "job-id", StartTime: 1}.DeliveryId(), requests[0].header.Get(DeliveryIdHeader))
+
+		var events []*jsonl_export.Event
+		scanner := bufio.NewScanner(bytes.NewReader(requests[0].body))
+		for scanner.Scan() {
+			var event jsonl_export.Event
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+			events = append(events, &event)
+		}
+		require.Len(t, events, 1)
+		assert.Equal(t, jsonl_export.EventPostCreated, events[0].Type)
+		assert.Equal(t, "hello", events[0].Post.Message)
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		receiver := newTestReceiver(t)
+		_, err := WebhookExport(rctx, params(receiver.URL, nil))
+		require.NoError(t, err)
+		assert.Empty(t, receiver.received())
+	})
+
+	t.Run("failed delivery fails the batch", func(t *testing.T) {
+		setFastRetries(t)
+		receiver := newTestReceiver(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+		_, err := WebhookExport(rctx, params(receiver.URL, []*model.MessageExport{post}))
+		require.Error(t, err)
+	})
+}
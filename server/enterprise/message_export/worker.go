@@ -14,11 +14,13 @@ import (
 	"time"
 
 	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/httpservice"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/mattermost/mattermost/server/public/shared/request"
 	"github.com/mattermost/mattermost/server/v8/channels/jobs"
 	"github.com/mattermost/mattermost/server/v8/channels/utils/fileutils"
 	"github.com/mattermost/mattermost/server/v8/enterprise/message_export/shared"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore"
 	"github.com/mattermost/mattermost/server/v8/platform/shared/templates"
 )
 
@@ -39,6 +41,7 @@ type MessageExportWorker struct {
 	jobServer           *jobs.JobServer
 	logger              mlog.LoggerIFace
 	htmlTemplateWatcher *templates.Container
+	httpService         httpservice.HTTPService
 	license             func() *model.License
 
 	context context.Context
@@ -69,6 +72,7 @@ func (dr *MessageExportJobInterfaceImpl) MakeWorker() model.Worker {
 		jobServer:           dr.Server.Jobs,
 		logger:              logger,
 		htmlTemplateWatcher: htmlTemplateWatcher,
+		httpService:         dr.Server.HTTPService(),
 		// It is not a best practice to store context inside a struct,
 		// however we need to cancel a SQL query during a job execution.
 		// There is no other good way.
@@ -179,17 +183,21 @@ func (w *MessageExportWorker) DoJob(job *model.Job) {
 		Config:        w.jobServer.Config(),
 		Store:         shared.NewMessageExportStore(w.jobServer.Store),
 		HtmlTemplates: w.htmlTemplateWatcher,
+		HTTPService:   w.httpService,
+		JobId:         job.Id,
 	}
-	jobParams.FileAttachmentBackend, err = shared.GetFileAttachmentBackend(rctx, w.jobServer.Config())
+	jobParams.FileAttachmentBackend, err = shared.GetFileAttachmentBackend(rctx, w.jobServer.Config(), w.jobServer.Store.FileBlob())
 	if err != nil {
 		w.setJobError(logger, job, model.NewAppError("GetFileAttachmentBackend", "api.file.no_driver.app_error", nil, "", http.StatusInternalServerError).Wrap(err))
 		return
 	}
-	jobParams.ExportBackend, err = shared.GetExportBackend(rctx, w.jobServer.Config())
+	defer filestore.CloseFileBackend(jobParams.FileAttachmentBackend)
+	jobParams.ExportBackend, err = shared.GetExportBackend(rctx, w.jobServer.Config(), w.jobServer.Store.FileBlob())
 	if err != nil {
 		w.setJobError(logger, job, model.NewAppError("GetExportBackend", "api.file.no_driver.app_error", nil, "", http.StatusInternalServerError).Wrap(err))
 		return
 	}
+	defer filestore.CloseFileBackend(jobParams.ExportBackend)
 
 	data, err = shared.GetInitialExportPeriodData(rctx, jobParams.Store, data, reportProgress)
 	if err != nil {
@@ -251,7 +259,8 @@ func (w *MessageExportWorker) finishExport(rctx request.CTX, logger *mlog.Logger
 	// we've exported everything up to the current time
 	logger.Debug("FormatExport complete")
 
-	job.Data[shared.JobDataIsDownloadable] = "true"
+	// The webhook export streams the batches to the webhook, there is nothing to download.
+	job.Data[shared.JobDataIsDownloadable] = strconv.FormatBool(job.Data[shared.JobDataExportType] != model.ComplianceExportTypeWebhook)
 
 	if totalWarningCount > 0 {
 		w.setJobWarning(logger, job)
@@ -308,7 +317,13 @@ func (w *MessageExportWorker) initJobData(logger mlog.LoggerIFace, job *model.Jo
 	}
 
 	if _, exists := job.Data[shared.JobDataBatchStartTime]; !exists {
-		previousJob, err := w.jobServer.Store.Job().GetNewestJobByStatusesAndType([]string{model.JobStatusWarning, model.JobStatusSuccess}, model.JobTypeMessageExport)
+		statuses := []string{model.JobStatusWarning, model.JobStatusSuccess}
+		if job.Data[shared.JobDataExportType] == model.ComplianceExportTypeWebhook {
+			// The batches delivered to the webhook can't be taken back, so resume after the last delivered batch even
+			// if the previous job failed or was cancelled, instead of delivering its batches again.
+			statuses = append(statuses, model.JobStatusError, model.JobStatusCanceled)
+		}
+		previousJob, err := w.jobServer.Store.Job().GetNewestJobByStatusesAndType(statuses, model.JobTypeMessageExport)
 		if err != nil {
 			exportFromTimestamp := strconv.FormatInt(*w.jobServer.Config().MessageExportSettings.ExportFromTimestamp, 10)
 			logger.Info("Worker: No previously successful job found, falling back to configured MessageExportSettings.ExportFromTimestamp", mlog.String("export_from_timestamp", exportFromTimestamp))
@@ -188,6 +188,61 @@ func TestInitJobDataPreviousJobWithJobData(t *testing.T) {
 	assert.Equal(t, expectedDir, job.Data[shared.JobDataExportDir])
 }
 
+func TestInitJobDataWebhookResumesFailedJob(t *testing.T) {
+	logger := mlog.CreateConsoleTestLogger(t)
+	mockStore := &storetest.Store{}
+	defer mockStore.AssertExpectations(t)
+
+	previousJob := &model.Job{
+		Id:       st.NewTestID(),
+		CreateAt: model.GetMillis(),
+		Status:   model.JobStatusError,
+		Type:     model.JobTypeMessageExport,
+		Data: map[string]string{
+			shared.JobDataBatchStartTime: "123",
+			shared.JobDataBatchStartId:   "post-id",
+		},
+	}
+
+	job := &model.Job{
+		Id:       st.NewTestID(),
+		CreateAt: model.GetMillis(),
+		Status:   model.JobStatusPending,
+		Type:     model.JobTypeMessageExport,
+	}
+
+	// the batches delivered by a failed job are not delivered again
+	mockStore.JobStore.On("GetNewestJobByStatusesAndType", []string{model.JobStatusWarning, model.JobStatusSuccess, model.JobStatusError, model.JobStatusCanceled}, model.JobTypeMessageExport).Return(previousJob, nil)
+
+	worker := &MessageExportWorker{
+		jobServer: &jobs.JobServer{
+			Store: mockStore,
+			ConfigService: &testutils.StaticConfigService{
+				Cfg: &model.Config{
+					// mock config
+					MessageExportSettings: model.MessageExportSettings{
+						EnableExport:            model.NewPointer(true),
+						ExportFormat:            model.NewPointer(model.ComplianceExportTypeWebhook),
+						DailyRunTime:            model.NewPointer("01:00"),
+						ExportFromTimestamp:     model.NewPointer(int64(0)),
+						BatchSize:               model.NewPointer(10000),
+						ChannelBatchSize:        model.NewPointer(100),
+						ChannelHistoryBatchSize: model.NewPointer(100),
+					},
+				},
+			},
+		},
+		logger: logger,
+	}
+
+	worker.initJobData(logger, job, time.Now())
+
+	assert.Equal(t, model.ComplianceExportTypeWebhook, job.Data[shared.JobDataExportType])
+	assert.Equal(t, "123", job.Data[shared.JobDataBatchStartTime])
+	assert.Equal(t, "123", job.Data[shared.JobDataJobStartTime])
+	assert.Equal(t, "post-id", job.Data[shared.JobDataBatchStartId])
+}
+
 func TestInitJobDataPreviousJobWithJobDataPre105(t *testing.T) {
 	logger := mlog.CreateConsoleTestLogger(t)
 	mockStore := &storetest.Store{}
@@ -74,6 +74,8 @@ type MetricsInterfaceImpl struct {
 	HTTPErrorsCounter   prometheus.Counter
 	HTTPWebsocketsGauge *prometheus.GaugeVec
 
+	HTTPRateLimitRejectionsCounter *prometheus.CounterVec
+
 	ClusterRequestsDuration prometheus.Histogram
 	ClusterRequestsCounter  prometheus.Counter
 
@@ -90,6 +92,8 @@ type MetricsInterfaceImpl struct {
 
 	MemCacheMissCounters         *prometheus.CounterVec
 	MemCacheHitCounters          *prometheus.CounterVec
+	CacheLayerHitCounters        *prometheus.CounterVec
+	CacheLayerMissCounters       *prometheus.CounterVec
 	MemCacheInvalidationCounters *prometheus.CounterVec
 
 	MemCacheHitCounterSession          prometheus.Counter
@@ -434,6 +438,18 @@ func New(ps *platform.PlatformService, driver, dataSource string) *MetricsInterf
 	})
 	m.Registry.MustRegister(m.HTTPErrorsCounter)
 
+	m.HTTPRateLimitRejectionsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace:   MetricsNamespace,
+			Subsystem:   MetricsSubsystemHTTP,
+			Name:        "rate_limit_rejections_total",
+			Help:        "The total number of http requests rejected by a rate limit policy.",
+			ConstLabels: additionalLabels,
+		},
+		[]string{"policy"},
+	)
+	m.Registry.MustRegister(m.HTTPRateLimitRejectionsCounter)
+
 	// Cluster Subsystem
 
 	m.ClusterHealthGauge = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
@@ -518,6 +534,7 @@ func New(ps *platform.PlatformService, driver, dataSource string) *MetricsInterf
 		model.ClusterEventPluginEvent,
 		model.ClusterEventInvalidateCacheForTermsOfService,
 		model.ClusterEventBusyStateChanged,
+		model.ClusterEventPurgeCache,
 	} {
 		m.ClusterEventMap[event] = m.ClusterEventTypeCounters.With(prometheus.Labels{"name": string(event)})
 	}
@@ -595,6 +612,30 @@ func New(ps *platform.PlatformService, driver, dataSource string) *MetricsInterf
 	m.Registry.MustRegister(m.MemCacheHitCounters)
 	m.MemCacheHitCounterSession = m.MemCacheHitCounters.With(prometheus.Labels{"name": "Session"})
 
+	m.CacheLayerHitCounters = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace:   MetricsNamespace,
+			Subsystem:   MetricsSubsystemCaching,
+			Name:        "layer_hit_total",
+			Help:        "Total number of cache hits by layer of the hybrid caches",
+			ConstLabels: additionalLabels,
+		},
+		[]string{"name", "layer"},
+	)
+	m.Registry.MustRegister(m.CacheLayerHitCounters)
+
+	m.CacheLayerMissCounters = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace:   MetricsNamespace,
+			Subsystem:   MetricsSubsystemCaching,
+			Name:        "layer_miss_total",
+			Help:        "Total number of cache misses by layer of the hybrid caches",
+			ConstLabels: additionalLabels,
+		},
+		[]string{"name", "layer"},
+	)
+	m.Registry.MustRegister(m.CacheLayerMissCounters)
+
 	m.MemCacheInvalidationCounters = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace:   MetricsNamespace,
@@ -1734,6 +1775,10 @@ func (mi *MetricsInterfaceImpl) IncrementHTTPError() {
 	mi.HTTPErrorsCounter.Inc()
 }
 
+func (mi *MetricsInterfaceImpl) IncrementRateLimitRejection(policy string) {
+	mi.HTTPRateLimitRejectionsCounter.With(prometheus.Labels{"policy": policy}).Inc()
+}
+
 func (mi *MetricsInterfaceImpl) IncrementClusterRequest() {
 	mi.ClusterRequestsCounter.Inc()
 }
@@ -1813,6 +1858,14 @@ func (mi *MetricsInterfaceImpl) AddMemCacheHitCounter(cacheName string, amount f
 	mi.MemCacheHitCounters.With(prometheus.Labels{"name": cacheName}).Add(amount)
 }
 
+func (mi *MetricsInterfaceImpl) IncrementCacheLayerHitCounter(cacheName, layer string) {
+	mi.CacheLayerHitCounters.With(prometheus.Labels{"name": cacheName, "layer": layer}).Inc()
+}
+
+func (mi *MetricsInterfaceImpl) IncrementCacheLayerMissCounter(cacheName, layer string) {
+	mi.CacheLayerMissCounters.With(prometheus.Labels{"name": cacheName, "layer": layer}).Inc()
+}
+
 func (mi *MetricsInterfaceImpl) IncrementWebsocketEvent(eventType model.WebsocketEventType) {
 	mi.WebsocketEventCounters.With(prometheus.Labels{"type": string(eventType)}).Inc()
 }
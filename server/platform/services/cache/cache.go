@@ -51,6 +51,9 @@ type Cache interface {
 
 	// Name returns the name of the cache
 	Name() string
+
+	// Stats returns the statistics of the cache.
+	Stats() Stats
 }
 
 // ExternalCache is a super-set of the Cache interface with
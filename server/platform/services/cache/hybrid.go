@@ -0,0 +1,462 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/rueidis"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/einterfaces"
+)
+
+const (
+	// CacheLayerLocal and CacheLayerRedis are the layers of a hybrid cache reported in the metrics.
+	CacheLayerLocal = "local"
+	CacheLayerRedis = "redis"
+
+	invalidationChannel = "cache_invalidation"
+	resubscribeInterval = 5 * time.Second
+)
+
+// HybridOptions contains the options of the hybrid cache provider.
+type HybridOptions struct {
+	RedisOptions
+	// LocalCacheSizes overrides the size of the local cache by cache name, a size of 0 disabling it.
+	// The caches not listed keep a local cache of the size they are created with.
+	LocalCacheSizes map[string]int
+}
+
+// invalidation is published to the other nodes when entries of a hybrid cache change.
+type invalidation struct {
+	Node  string   `json:"node"`
+	Cache string   `json:"cache"`
+	Keys  []string `json:"keys,omitempty"`
+	Purge bool     `json:"purge,omitempty"`
+}
+
+type hybridProvider struct {
+	*redisProvider
+	localCacheSizes map[string]int
+	nodeID          string
+	channel         string
+
+	mut    sync.RWMutex
+	caches map[string][]*Hybrid
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewHybridProvider creates a provider of caches stored in Redis, each node keeping the hot entries in a
+// local cache in front of it. The local caches are invalidated through Redis pub/sub when the entries
+// change on any node.
+func NewHybridProvider(opts *HybridOptions) (Provider, error) {
+	provider, err := NewRedisProvider(&opts.RedisOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	channel := invalidationChannel
+	if opts.RedisCachePrefix != "" {
+		channel = opts.RedisCachePrefix + ":" + channel
+	}
+
+	return &hybridProvider{
+		redisProvider:   provider.(*redisProvider),
+		localCacheSizes: opts.LocalCacheSizes,
+		nodeID:          model.NewId(),
+		channel:         channel,
+		caches:          make(map[string][]*Hybrid),
+	}, nil
+}
+
+// NewCache creates a new cache with given opts
+func (p *hybridProvider) NewCache(opts *CacheOptions) (Cache, error) {
+	size := opts.Size
+	if override, ok := p.localCacheSizes[opts.Name]; ok {
+		size = override
+	}
+
+	name := opts.Name
+	if p.cachePrefix != "" {
+		name = p.cachePrefix + ":" + name
+	}
+
+	remote, err := NewRedis(&CacheOptions{Name: name, DefaultExpiry: opts.DefaultExpiry}, p.client)
+	if err != nil {
+		return nil, err
+	}
+	remote.metrics = p.metrics
+
+	h := &Hybrid{
+		remote:        remote,
+		provider:      p,
+		defaultExpiry: opts.DefaultExpiry,
+		metrics:       p.metrics,
+	}
+
+	if size > 0 {
+		localOpts := &CacheOptions{
+			Size:          size,
+			DefaultExpiry: opts.DefaultExpiry,
+			Name:          name,
+		}
+		if buckets := max(runtime.NumCPU()-1, 1); size >= buckets {
+			localOpts.Striped = true
+			localOpts.StripedBuckets = buckets
+			if h.local, err = NewLRUStriped(localOpts); err != nil {
+				return nil, err
+			}
+		} else {
+			h.local = NewLRU(localOpts)
+		}
+	}
+
+	p.mut.Lock()
+	p.caches[name] = append(p.caches[name], h)
+	p.mut.Unlock()
+	p.register(h)
+
+	return h, nil
+}
+
+// Connect opens a new connection to the cache using specific provider parameters, and subscribes to the
+// invalidations of the other nodes.
+func (p *hybridProvider) Connect() (string, error) {
+	res, err := p.redisProvider.Connect()
+	if err != nil {
+		return "", err
+	}
+
+	if p.cancel == nil {
+		var ctx context.Context
+		ctx, p.cancel = context.WithCancel(context.Background())
+		p.wg.Add(1)
+		go p.subscribe(ctx)
+	}
+	return res, nil
+}
+
+func (p *hybridProvider) Type() string {
+	return model.CacheTypeHybrid
+}
+
+// Close releases any resources used by the cache provider.
+func (p *hybridProvider) Close() error {
+	if p.cancel != nil {
+		p.cancel()
+		p.wg.Wait()
+	}
+	return p.redisProvider.Close()
+}
+
+func (p *hybridProvider) subscribe(ctx context.Context) {
+	defer p.wg.Done()
+
+	for {
+		err := p.client.Receive(ctx, p.client.B().Subscribe().Channel(p.channel).Build(), func(msg rueidis.PubSubMessage) {
+			p.handleInvalidation([]byte(msg.Message))
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		// The invalidations published while unsubscribed are lost, so none of the local entries can be trusted.
+		mlog.Warn("Lost the subscription to the cache invalidations, purging the local caches", mlog.String("channel", p.channel), mlog.Err(err))
+		p.purgeLocal()
+
+		select {
+		case <-time.After(resubscribeInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *hybridProvider) handleInvalidation(payload []byte) {
+	var inv invalidation
+	if err := json.Unmarshal(payload, &inv); err != nil {
+		mlog.Warn("Failed to decode the cache invalidation", mlog.Err(err))
+		return
+	}
+	if inv.Node == p.nodeID {
+		return
+	}
+
+	p.mut.RLock()
+	caches := p.caches[inv.Cache]
+	p.mut.RUnlock()
+
+	for _, h := range caches {
+		h.invalidateLocal(inv.Keys, inv.Purge)
+	}
+}
+
+func (p *hybridProvider) purgeLocal() {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+
+	for _, caches := range p.caches {
+		for _, h := range caches {
+			h.invalidateLocal(nil, true)
+		}
+	}
+}
+
+func (p *hybridProvider) publish(inv *invalidation) error {
+	inv.Node = p.nodeID
+	buf, err := json.Marshal(inv)
+	if err != nil {
+		return err
+	}
+
+	return p.client.Do(context.Background(),
+		p.client.B().Publish().
+			Channel(p.channel).
+			Message(rueidis.BinaryString(buf)).
+			Build(),
+	).Error()
+}
+
+// Hybrid is a cache stored in Redis with a local cache in front of it. The writes go to Redis and
+// invalidate the local entries of every node, so that the reads of the hot entries seldom leave the node.
+//
+// Since the invalidations propagate through Redis, the cache has no cluster event.
+type Hybrid struct {
+	local         Cache // nil when the local cache is disabled
+	remote        *Redis
+	provider      *hybridProvider
+	defaultExpiry time.Duration
+	metrics       einterfaces.MetricsInterface
+	counters      counters
+
+	// generation is incremented under mut on every invalidation of the local cache, so that a value read
+	// from Redis before an invalidation is not stored in the local cache after it.
+	mut        sync.Mutex
+	generation atomic.Uint64
+}
+
+// Purge is used to completely clear the cache.
+func (h *Hybrid) Purge() error {
+	err := h.remote.Purge()
+	h.invalidate(&invalidation{Purge: true})
+	return err
+}
+
+// SetWithDefaultExpiry adds the given key and value to the store with the default expiry. If
+// the key already exists, it will overwrite the previous value
+func (h *Hybrid) SetWithDefaultExpiry(key string, value any) error {
+	return h.SetWithExpiry(key, value, h.defaultExpiry)
+}
+
+// SetWithExpiry adds the given key and value to the cache with the given expiry. If the key
+// already exists, it will overwrite the previous value
+func (h *Hybrid) SetWithExpiry(key string, value any, ttl time.Duration) error {
+	err := h.remote.SetWithExpiry(key, value, ttl)
+	h.invalidate(&invalidation{Keys: []string{key}})
+	return err
+}
+
+// Increment increments the value of the key by the value.
+func (h *Hybrid) Increment(key string, val int) error {
+	err := h.remote.Increment(key, val)
+	h.invalidate(&invalidation{Keys: []string{key}})
+	return err
+}
+
+// Decrement decrements the value of the key by the value.
+func (h *Hybrid) Decrement(key string, val int) error {
+	err := h.remote.Decrement(key, val)
+	h.invalidate(&invalidation{Keys: []string{key}})
+	return err
+}
+
+// Get the content stored in the cache for the given key, and decode it into the value interface.
+// Return ErrKeyNotFound if the key is missing from the cache
+func (h *Hybrid) Get(key string, value any) error {
+	if h.local != nil {
+		if err := h.local.Get(key, value); err == nil {
+			h.hit(CacheLayerLocal)
+			h.counters.count(nil)
+			return nil
+		}
+		h.miss(CacheLayerLocal)
+	}
+
+	generation := h.generation.Load()
+	err := h.remote.Get(key, value)
+	h.counters.count(err)
+	if err != nil {
+		if err == ErrKeyNotFound {
+			h.miss(CacheLayerRedis)
+		}
+		return err
+	}
+	h.hit(CacheLayerRedis)
+	h.populate(generation, key, value)
+	return nil
+}
+
+// GetMulti returns the values of the local cache, fetching the missing ones from Redis in a single operation.
+func (h *Hybrid) GetMulti(keys []string, values []any) []error {
+	errs := make([]error, len(keys))
+
+	var missing []int
+	if h.local != nil {
+		for i, err := range h.local.GetMulti(keys, values) {
+			if err == nil {
+				h.hit(CacheLayerLocal)
+				continue
+			}
+			h.miss(CacheLayerLocal)
+			missing = append(missing, i)
+		}
+	} else {
+		missing = make([]int, len(keys))
+		for i := range keys {
+			missing[i] = i
+		}
+	}
+	defer func() {
+		for _, err := range errs {
+			h.counters.count(err)
+		}
+	}()
+	if len(missing) == 0 {
+		return errs
+	}
+
+	generation := h.generation.Load()
+	remoteKeys := make([]string, len(missing))
+	remoteValues := make([]any, len(missing))
+	for j, i := range missing {
+		remoteKeys[j] = keys[i]
+		remoteValues[j] = values[i]
+	}
+
+	for j, err := range h.remote.GetMulti(remoteKeys, remoteValues) {
+		i := missing[j]
+		errs[i] = err
+		switch err {
+		case nil:
+			h.hit(CacheLayerRedis)
+			h.populate(generation, keys[i], values[i])
+		case ErrKeyNotFound:
+			h.miss(CacheLayerRedis)
+		}
+	}
+	return errs
+}
+
+// Remove deletes the value for a given key.
+func (h *Hybrid) Remove(key string) error {
+	err := h.remote.Remove(key)
+	h.invalidate(&invalidation{Keys: []string{key}})
+	return err
+}
+
+// RemoveMulti deletes multiple keys in a single operation.
+func (h *Hybrid) RemoveMulti(keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	err := h.remote.RemoveMulti(keys)
+	h.invalidate(&invalidation{Keys: keys})
+	return err
+}
+
+// Scan iterates over the keys stored in Redis.
+func (h *Hybrid) Scan(f func([]string) error) error {
+	return h.remote.Scan(f)
+}
+
+// GetInvalidateClusterEvent returns the cluster event configured when this cache was created.
+func (h *Hybrid) GetInvalidateClusterEvent() model.ClusterEvent {
+	return model.ClusterEventNone
+}
+
+func (h *Hybrid) Name() string {
+	return h.remote.Name()
+}
+
+// Stats returns the hits and misses of the reads of this node, along with the size and number of items of
+// its local cache.
+func (h *Hybrid) Stats() Stats {
+	stats := Stats{Len: -1}
+	if h.local != nil {
+		local := h.local.Stats()
+		stats.Size = local.Size
+		stats.Len = local.Len
+	}
+	stats.Hits = h.counters.hits.Load()
+	stats.Misses = h.counters.misses.Load()
+	return stats
+}
+
+// invalidate drops the entries from the local caches of this node and of the other nodes. The write to Redis having
+// been made, a failure to publish the invalidation is not returned: the local cache is purged instead, since the
+// subscription of this node to the invalidations of the others is likely lost too.
+func (h *Hybrid) invalidate(inv *invalidation) {
+	h.invalidateLocal(inv.Keys, inv.Purge)
+	inv.Cache = h.Name()
+	if err := h.provider.publish(inv); err != nil {
+		mlog.Warn("Failed to publish the cache invalidation, purging the local cache", mlog.String("cache_name", h.Name()), mlog.Err(err))
+		h.invalidateLocal(nil, true)
+	}
+}
+
+func (h *Hybrid) invalidateLocal(keys []string, purge bool) {
+	if h.local == nil {
+		return
+	}
+
+	h.mut.Lock()
+	defer h.mut.Unlock()
+
+	h.generation.Add(1)
+	// Errors from the local cache can be ignored as they are always nil.
+	if purge {
+		h.local.Purge()
+		return
+	}
+	h.local.RemoveMulti(keys)
+}
+
+// populate stores the value read from Redis in the local cache, unless it was invalidated since the read.
+func (h *Hybrid) populate(generation uint64, key string, value any) {
+	if h.local == nil {
+		return
+	}
+
+	h.mut.Lock()
+	defer h.mut.Unlock()
+
+	if h.generation.Load() != generation {
+		return
+	}
+	if err := h.local.SetWithDefaultExpiry(key, value); err != nil {
+		mlog.Debug("Failed to store the value in the local cache", mlog.String("cache_name", h.Name()), mlog.Err(err))
+	}
+}
+
+func (h *Hybrid) hit(layer string) {
+	if h.metrics != nil {
+		h.metrics.IncrementCacheLayerHitCounter(h.Name(), layer)
+	}
+}
+
+func (h *Hybrid) miss(layer string) {
+	if h.metrics != nil {
+		h.metrics.IncrementCacheLayerMissCounter(h.Name(), layer)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func newTestHybridProvider(localCacheSizes map[string]int) *hybridProvider {
+	return &hybridProvider{
+		redisProvider:   &redisProvider{cachePrefix: "prefix"},
+		localCacheSizes: localCacheSizes,
+		nodeID:          model.NewId(),
+		caches:          make(map[string][]*Hybrid),
+	}
+}
+
+func TestHybridNewCache(t *testing.T) {
+	p := newTestHybridProvider(map[string]int{"Disabled": 0, "Small": 1})
+
+	c, err := p.NewCache(&CacheOptions{Name: "Default", Size: 100})
+	require.NoError(t, err)
+	assert.Equal(t, "prefix:Default", c.Name())
+	assert.Equal(t, model.ClusterEventNone, c.GetInvalidateClusterEvent())
+	assert.NotNil(t, c.(*Hybrid).local)
+	assert.Equal(t, []*Hybrid{c.(*Hybrid)}, p.caches["prefix:Default"])
+
+	c, err = p.NewCache(&CacheOptions{Name: "Disabled", Size: 100})
+	require.NoError(t, err)
+	assert.Nil(t, c.(*Hybrid).local)
+
+	c, err = p.NewCache(&CacheOptions{Name: "Small", Size: 100})
+	require.NoError(t, err)
+	require.NotNil(t, c.(*Hybrid).local)
+}
+
+func TestHybridHandleInvalidation(t *testing.T) {
+	p := newTestHybridProvider(nil)
+	c, err := p.NewCache(&CacheOptions{Name: "Cache", Size: 100})
+	require.NoError(t, err)
+	h := c.(*Hybrid)
+
+	invalidate := func(inv *invalidation) {
+		buf, err := json.Marshal(inv)
+		require.NoError(t, err)
+		p.handleInvalidation(buf)
+	}
+
+	localKeys := func() []string {
+		var keys []string
+		require.NoError(t, h.local.Scan(func(batch []string) error {
+			keys = append(keys, batch...)
+			return nil
+		}))
+		return keys
+	}
+
+	for _, key := range []string{"key1", "key2", "key3"} {
+		require.NoError(t, h.local.SetWithDefaultExpiry(key, key))
+	}
+
+	t.Run("invalidations of this node or of other caches are ignored", func(t *testing.T) {
+		invalidate(&invalidation{Node: p.nodeID, Cache: h.Name(), Keys: []string{"key1"}})
+		invalidate(&invalidation{Node: model.NewId(), Cache: "prefix:Other", Keys: []string{"key1"}})
+		p.handleInvalidation([]byte("not json"))
+
+		assert.ElementsMatch(t, []string{"key1", "key2", "key3"}, localKeys())
+	})
+
+	t.Run("invalidations of other nodes remove the keys", func(t *testing.T) {
+		invalidate(&invalidation{Node: model.NewId(), Cache: h.Name(), Keys: []string{"key1", "key2"}})
+
+		assert.ElementsMatch(t, []string{"key3"}, localKeys())
+	})
+
+	t.Run("purges of other nodes clear the local cache", func(t *testing.T) {
+		invalidate(&invalidation{Node: model.NewId(), Cache: h.Name(), Purge: true})
+
+		assert.Empty(t, localKeys())
+	})
+}
+
+func TestHybridPopulate(t *testing.T) {
+	p := newTestHybridProvider(nil)
+	c, err := p.NewCache(&CacheOptions{Name: "Cache", Size: 100})
+	require.NoError(t, err)
+	h := c.(*Hybrid)
+
+	var value string
+
+	generation := h.generation.Load()
+	h.populate(generation, "key1", "value1")
+	require.NoError(t, h.local.Get("key1", &value))
+	assert.Equal(t, "value1", value)
+
+	t.Run("values read before an invalidation are not stored", func(t *testing.T) {
+		generation := h.generation.Load()
+		h.invalidateLocal([]string{"key2"}, false)
+		h.populate(generation, "key2", "stale")
+
+		assert.Equal(t, ErrKeyNotFound, h.local.Get("key2", &value))
+	})
+}
+
+// newTestHybridNode returns a cache of a node connected to the Redis server of the test environment.
+func newTestHybridNode(t *testing.T, opts *RedisOptions) *Hybrid {
+	p, err := NewHybridProvider(&HybridOptions{RedisOptions: *opts})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, p.Close())
+	})
+	_, err = p.Connect()
+	require.NoError(t, err)
+
+	c, err := p.NewCache(&CacheOptions{Name: "Cache", Size: 100})
+	require.NoError(t, err)
+	return c.(*Hybrid)
+}
+
+func TestHybridRedis(t *testing.T) {
+	opts := testRedisOptions(t)
+	a := newTestHybridNode(t, opts)
+	b := newTestHybridNode(t, opts)
+
+	// The nodes subscribe to the invalidations in the background.
+	require.Eventually(t, func() bool {
+		client := a.provider.client
+		counts, err := client.Do(context.Background(), client.B().PubsubNumsub().Channel(a.provider.channel).Build()).AsIntMap()
+		return err == nil && counts[a.provider.channel] == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// get reads the value of the key on the node, the local cache being populated by the first read.
+	get := func(h *Hybrid, key string) string {
+		var value string
+		if err := h.Get(key, &value); err != nil {
+			return err.Error()
+		}
+		return value
+	}
+	cached := func(h *Hybrid, key string) bool {
+		var value string
+		return h.local.Get(key, &value) == nil
+	}
+
+	t.Run("writes invalidate the local caches of the other nodes", func(t *testing.T) {
+		require.NoError(t, a.SetWithDefaultExpiry("set", "value1"))
+		assert.Equal(t, "value1", get(b, "set"))
+		require.True(t, cached(b, "set"))
+
+		require.NoError(t, a.SetWithDefaultExpiry("set", "value2"))
+		assert.Eventually(t, func() bool {
+			return !cached(b, "set")
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, "value2", get(b, "set"))
+	})
+
+	t.Run("removals invalidate the local caches of the other nodes", func(t *testing.T) {
+		require.NoError(t, a.SetWithDefaultExpiry("remove", "value"))
+		assert.Equal(t, "value", get(b, "remove"))
+		require.True(t, cached(b, "remove"))
+
+		require.NoError(t, a.Remove("remove"))
+		assert.Eventually(t, func() bool {
+			return !cached(b, "remove")
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, ErrKeyNotFound.Error(), get(b, "remove"))
+	})
+
+	t.Run("purges clear the local caches of the other nodes", func(t *testing.T) {
+		require.NoError(t, a.SetWithDefaultExpiry("purge", "value"))
+		assert.Equal(t, "value", get(b, "purge"))
+		require.True(t, cached(b, "purge"))
+
+		require.NoError(t, a.Purge())
+		assert.Eventually(t, func() bool {
+			return !cached(b, "purge")
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, ErrKeyNotFound.Error(), get(b, "purge"))
+	})
+
+	t.Run("the writing node invalidates its own local cache", func(t *testing.T) {
+		require.NoError(t, a.SetWithDefaultExpiry("own", "value1"))
+		assert.Equal(t, "value1", get(a, "own"))
+		require.True(t, cached(a, "own"))
+
+		require.NoError(t, a.SetWithDefaultExpiry("own", "value2"))
+		assert.False(t, cached(a, "own"))
+		assert.Equal(t, "value2", get(a, "own"))
+	})
+}
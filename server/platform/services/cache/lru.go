@@ -25,6 +25,7 @@ type LRU struct {
 	defaultExpiry          time.Duration
 	name                   string
 	invalidateClusterEvent model.ClusterEvent
+	counters               counters
 }
 
 // entry is used to hold a value in the evictList.
@@ -147,6 +148,18 @@ func (l *LRU) Name() string {
 	return l.name
 }
 
+// Stats returns the statistics of the cache.
+func (l *LRU) Stats() Stats {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return Stats{
+		Size:   l.size,
+		Len:    l.len,
+		Hits:   l.counters.hits.Load(),
+		Misses: l.counters.misses.Load(),
+	}
+}
+
 func (l *LRU) set(key string, value any, ttl time.Duration) error {
 	var expires time.Time
 	if ttl > 0 {
@@ -196,6 +209,7 @@ func (l *LRU) set(key string, value any, ttl time.Duration) error {
 
 func (l *LRU) get(key string, value any) error {
 	val, err := l.getItem(key)
+	l.counters.count(err)
 	if err != nil {
 		return err
 	}
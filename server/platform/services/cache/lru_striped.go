@@ -129,6 +129,19 @@ func (L LRUStriped) Name() string {
 	return L.name
 }
 
+// Stats sums the statistics of the buckets. As for LRUStriped.Len, this call cannot be precise.
+func (L LRUStriped) Stats() Stats {
+	var stats Stats
+	for _, lru := range L.buckets {
+		bucket := lru.Stats()
+		stats.Size += bucket.Size
+		stats.Len += bucket.Len
+		stats.Hits += bucket.Hits
+		stats.Misses += bucket.Misses
+	}
+	return stats
+}
+
 // NewLRUStriped creates a striped LRU cache using the special CacheOptions.StripedBuckets value.
 // See LRUStriped and CacheOptions for more details.
 //
@@ -5,10 +5,12 @@
 package mocks
 
 import (
-	time "time"
+	cache "github.com/mattermost/mattermost/server/v8/platform/services/cache"
 
 	model "github.com/mattermost/mattermost/server/public/model"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // Cache is an autogenerated mock type for the Cache type
@@ -198,6 +200,24 @@ func (_m *Cache) SetWithExpiry(key string, value interface{}, ttl time.Duration)
 	return r0
 }
 
+// Stats provides a mock function with given fields:
+func (_m *Cache) Stats() cache.Stats {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stats")
+	}
+
+	var r0 cache.Stats
+	if rf, ok := ret.Get(0).(func() cache.Stats); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(cache.Stats)
+	}
+
+	return r0
+}
+
 // NewCache creates a new instance of Cache. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewCache(t interface {
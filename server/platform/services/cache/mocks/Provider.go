@@ -16,6 +16,26 @@ type Provider struct {
 	mock.Mock
 }
 
+// Caches provides a mock function with given fields:
+func (_m *Provider) Caches() []cache.Cache {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Caches")
+	}
+
+	var r0 []cache.Cache
+	if rf, ok := ret.Get(0).(func() []cache.Cache); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]cache.Cache)
+		}
+	}
+
+	return r0
+}
+
 // Close provides a mock function with given fields:
 func (_m *Provider) Close() error {
 	ret := _m.Called()
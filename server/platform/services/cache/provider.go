@@ -38,9 +38,12 @@ type Provider interface {
 	Close() error
 	// Type returns what type of cache it generates.
 	Type() string
+	// Caches returns the caches created by the provider.
+	Caches() []Cache
 }
 
 type cacheProvider struct {
+	registry
 }
 
 // NewProvider creates a new CacheProvider
@@ -50,10 +53,17 @@ func NewProvider() Provider {
 
 // NewCache creates a new cache with given opts
 func (c *cacheProvider) NewCache(opts *CacheOptions) (Cache, error) {
+	var cache Cache
 	if opts.Striped {
-		return NewLRUStriped(opts)
+		var err error
+		if cache, err = NewLRUStriped(opts); err != nil {
+			return nil, err
+		}
+	} else {
+		cache = NewLRU(opts)
 	}
-	return NewLRU(opts), nil
+	c.register(cache)
+	return cache, nil
 }
 
 // Connect opens a new connection to the cache using specific provider parameters.
@@ -74,6 +84,7 @@ func (c *cacheProvider) Type() string {
 }
 
 type redisProvider struct {
+	registry
 	client      rueidis.Client
 	cachePrefix string
 	metrics     einterfaces.MetricsInterface
@@ -118,8 +129,12 @@ func (r *redisProvider) NewCache(opts *CacheOptions) (Cache, error) {
 		opts.Name = r.cachePrefix + ":" + opts.Name
 	}
 	rr, err := NewRedis(opts, r.client)
+	if err != nil {
+		return nil, err
+	}
 	rr.metrics = r.metrics
-	return rr, err
+	r.register(rr)
+	return rr, nil
 }
 
 // Connect opens a new connection to the cache using specific provider parameters.
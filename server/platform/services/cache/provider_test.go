@@ -4,9 +4,11 @@
 package cache
 
 import (
+	"os"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost/server/public/model"
@@ -162,6 +164,47 @@ func TestNewCache_Striped(t *testing.T) {
 	})
 }
 
+func TestCaches(t *testing.T) {
+	p := NewProvider()
+	require.Empty(t, p.Caches())
+
+	c1, err := p.NewCache(&CacheOptions{Name: "c1", Size: 10})
+	require.NoError(t, err)
+	c2, err := p.NewCache(&CacheOptions{Name: "c2", Size: 10, Striped: true, StripedBuckets: 2})
+	require.NoError(t, err)
+
+	caches := p.Caches()
+	require.Len(t, caches, 2)
+	assert.Equal(t, c1.Name(), caches[0].Name())
+	assert.Equal(t, c2.Name(), caches[1].Name())
+}
+
+func TestStats(t *testing.T) {
+	for name, opts := range map[string]*CacheOptions{
+		"lru":     {Size: 10},
+		"striped": {Size: 10, Striped: true, StripedBuckets: 2},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewProvider().NewCache(opts)
+			require.NoError(t, err)
+
+			require.NoError(t, c.SetWithDefaultExpiry("key1", "val1"))
+			require.NoError(t, c.SetWithDefaultExpiry("key2", "val2"))
+
+			var v string
+			require.NoError(t, c.Get("key1", &v))
+			require.NoError(t, c.Get("key2", &v))
+			require.Equal(t, ErrKeyNotFound, c.Get("key3", &v))
+
+			stats := c.Stats()
+			assert.Equal(t, 2, stats.Len)
+			assert.Equal(t, uint64(2), stats.Hits)
+			assert.Equal(t, uint64(1), stats.Misses)
+			assert.NotZero(t, stats.Size)
+		})
+	}
+}
+
 func TestConnectClose(t *testing.T) {
 	p := NewProvider()
 
@@ -171,3 +214,34 @@ func TestConnectClose(t *testing.T) {
 	err = p.Close()
 	require.NoError(t, err)
 }
+
+// testRedisOptions returns the options of the Redis server of the test environment, with a random prefix for the
+// keys of the test.
+func testRedisOptions(t *testing.T) *RedisOptions {
+	if testing.Short() {
+		t.Skip("skipping the tests requiring Redis in short mode")
+	}
+
+	redisHost := "localhost"
+	if os.Getenv("IS_CI") == "true" {
+		redisHost = "redis"
+	}
+	return &RedisOptions{
+		RedisAddr:        redisHost + ":6379",
+		RedisCachePrefix: model.NewId(),
+		DisableCache:     true,
+	}
+}
+
+// newTestRedisProvider returns a provider connected to the Redis server of the test environment.
+func newTestRedisProvider(t *testing.T) *redisProvider {
+	p, err := NewRedisProvider(testRedisOptions(t))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, p.Close())
+	})
+
+	_, err = p.Connect()
+	require.NoError(t, err)
+	return p.(*redisProvider)
+}
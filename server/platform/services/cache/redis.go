@@ -26,6 +26,7 @@ type Redis struct {
 	client        rueidis.Client
 	defaultExpiry time.Duration
 	metrics       einterfaces.MetricsInterface
+	counters      counters
 }
 
 func NewRedis(opts *CacheOptions, client rueidis.Client) (*Redis, error) {
@@ -153,10 +154,12 @@ func (r *Redis) Get(key string, value any) error {
 	}
 	if err != nil {
 		if rueidis.IsRedisNil(err) {
+			r.counters.count(ErrKeyNotFound)
 			return ErrKeyNotFound
 		}
 		return err
 	}
+	r.counters.count(nil)
 
 	if ok {
 		*vPtr = intVal
@@ -244,6 +247,9 @@ func (r *Redis) GetMulti(keys []string, values []any) []error {
 		errs[i] = msgpack.Unmarshal(bytesVal, values[i])
 	}
 
+	for _, err := range errs {
+		r.counters.count(err)
+	}
 	return errs
 }
 
@@ -330,6 +336,15 @@ func (r *Redis) Name() string {
 	return r.name
 }
 
+// Stats returns the hits and misses of the reads of this node, the number of items being unknown.
+func (r *Redis) Stats() Stats {
+	return Stats{
+		Len:    -1,
+		Hits:   r.counters.hits.Load(),
+		Misses: r.counters.misses.Load(),
+	}
+}
+
 func sliceMapper[S ~[]E, E, R any](slice S, mapper func(E) R) []R {
 	newSlice := make([]R, len(slice))
 	for i, v := range slice {
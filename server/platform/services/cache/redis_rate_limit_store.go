@@ -0,0 +1,125 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/rueidis"
+)
+
+// rateLimitCompareAndSwap sets the key to the new value only if it still holds the old value.
+var rateLimitCompareAndSwap = rueidis.NewLuaScript(`
+local v = redis.call('get', KEYS[1])
+if v == false or v ~= ARGV[1] then
+	return 0
+end
+redis.call('set', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+// RedisRateLimitStore keeps the counters of a rate limiter in Redis so that every node of a cluster
+// enforces the same quota. It implements the GCRAStore interface of github.com/throttled/throttled.
+type RedisRateLimitStore struct {
+	client rueidis.Client
+	prefix string
+}
+
+// NewRedisRateLimitStore returns a store of the counters of the named rate limiter, using the connection
+// of the provider, which must be a Redis or hybrid provider.
+func NewRedisRateLimitStore(provider Provider, name string) (*RedisRateLimitStore, error) {
+	var rp *redisProvider
+	switch p := provider.(type) {
+	case *redisProvider:
+		rp = p
+	case *hybridProvider:
+		rp = p.redisProvider
+	default:
+		return nil, errors.New("rate limit counters can only be stored in a redis cache provider")
+	}
+	if name == "" {
+		return nil, errors.New("no name specified for rate limit store")
+	}
+
+	prefix := "ratelimit:" + name + ":"
+	if rp.cachePrefix != "" {
+		prefix = rp.cachePrefix + ":" + prefix
+	}
+	return &RedisRateLimitStore{client: rp.client, prefix: prefix}, nil
+}
+
+// GetWithTime returns the value of the key, or -1 if it does not exist, and the current time of the
+// Redis server so that the nodes share the same clock.
+func (s *RedisRateLimitStore) GetWithTime(key string) (int64, time.Time, error) {
+	results := s.client.DoMulti(context.Background(),
+		s.client.B().Get().Key(s.prefix+key).Build(),
+		s.client.B().Time().Build(),
+	)
+
+	serverTime, err := results[1].AsStrSlice()
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if len(serverTime) != 2 {
+		return 0, time.Time{}, fmt.Errorf("unexpected redis time %v", serverTime)
+	}
+	seconds, err := strconv.ParseInt(serverTime[0], 10, 64)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	micros, err := strconv.ParseInt(serverTime[1], 10, 64)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	now := time.Unix(seconds, micros*int64(time.Microsecond))
+
+	value, err := results[0].AsInt64()
+	if rueidis.IsRedisNil(err) {
+		return -1, now, nil
+	} else if err != nil {
+		return 0, now, err
+	}
+	return value, now, nil
+}
+
+// SetIfNotExistsWithTTL sets the value of the key only if it does not exist, returning whether it was set.
+func (s *RedisRateLimitStore) SetIfNotExistsWithTTL(key string, value int64, ttl time.Duration) (bool, error) {
+	err := s.client.Do(context.Background(),
+		s.client.B().Arbitrary("SET").
+			Keys(s.prefix+key).
+			Args(strconv.FormatInt(value, 10), "NX", "PX", ttlMilliseconds(ttl)).
+			Build(),
+	).Error()
+	if rueidis.IsRedisNil(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CompareAndSwapWithTTL sets the value of the key to new only if it currently holds old, returning whether
+// it was set.
+func (s *RedisRateLimitStore) CompareAndSwapWithTTL(key string, old, new int64, ttl time.Duration) (bool, error) {
+	swapped, err := rateLimitCompareAndSwap.Exec(context.Background(), s.client,
+		[]string{s.prefix + key},
+		[]string{strconv.FormatInt(old, 10), strconv.FormatInt(new, 10), ttlMilliseconds(ttl)},
+	).AsInt64()
+	if err != nil {
+		return false, err
+	}
+	return swapped == 1, nil
+}
+
+func ttlMilliseconds(ttl time.Duration) string {
+	ms := ttl.Milliseconds()
+	if ms < 1 {
+		ms = 1
+	}
+	return strconv.FormatInt(ms, 10)
+}
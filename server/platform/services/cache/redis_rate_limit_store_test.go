@@ -0,0 +1,115 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRedisRateLimitStore(t *testing.T) {
+	t.Run("requires a redis provider", func(t *testing.T) {
+		_, err := NewRedisRateLimitStore(NewProvider(), "policy")
+		require.Error(t, err)
+	})
+
+	t.Run("prefixes the keys", func(t *testing.T) {
+		store, err := NewRedisRateLimitStore(&redisProvider{cachePrefix: "prefix"}, "policy")
+		require.NoError(t, err)
+		require.Equal(t, "prefix:ratelimit:policy:", store.prefix)
+
+		store, err = NewRedisRateLimitStore(&redisProvider{}, "policy")
+		require.NoError(t, err)
+		require.Equal(t, "ratelimit:policy:", store.prefix)
+	})
+}
+
+func TestRedisRateLimitStore(t *testing.T) {
+	store, err := NewRedisRateLimitStore(newTestRedisProvider(t), "policy")
+	require.NoError(t, err)
+
+	t.Run("get with time", func(t *testing.T) {
+		value, now, err := store.GetWithTime("missing")
+		require.NoError(t, err)
+		assert.Equal(t, int64(-1), value)
+		assert.WithinDuration(t, time.Now(), now, time.Minute)
+
+		set, err := store.SetIfNotExistsWithTTL("get", 42, time.Minute)
+		require.NoError(t, err)
+		require.True(t, set)
+
+		value, _, err = store.GetWithTime("get")
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), value)
+	})
+
+	t.Run("set if not exists", func(t *testing.T) {
+		set, err := store.SetIfNotExistsWithTTL("set", 1, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, set)
+
+		set, err = store.SetIfNotExistsWithTTL("set", 2, time.Minute)
+		require.NoError(t, err)
+		assert.False(t, set)
+
+		value, _, err := store.GetWithTime("set")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), value)
+	})
+
+	t.Run("set if not exists expires", func(t *testing.T) {
+		set, err := store.SetIfNotExistsWithTTL("expire", 1, 50*time.Millisecond)
+		require.NoError(t, err)
+		require.True(t, set)
+
+		require.Eventually(t, func() bool {
+			value, _, err := store.GetWithTime("expire")
+			return err == nil && value == -1
+		}, 5*time.Second, 50*time.Millisecond)
+
+		set, err = store.SetIfNotExistsWithTTL("expire", 2, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, set)
+	})
+
+	t.Run("compare and swap", func(t *testing.T) {
+		swapped, err := store.CompareAndSwapWithTTL("swap", 1, 2, time.Minute)
+		require.NoError(t, err)
+		assert.False(t, swapped, "a missing key is not swapped")
+
+		set, err := store.SetIfNotExistsWithTTL("swap", 1, time.Minute)
+		require.NoError(t, err)
+		require.True(t, set)
+
+		swapped, err = store.CompareAndSwapWithTTL("swap", 3, 4, time.Minute)
+		require.NoError(t, err)
+		assert.False(t, swapped, "a different value is not swapped")
+
+		swapped, err = store.CompareAndSwapWithTTL("swap", 1, 2, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, swapped)
+
+		value, _, err := store.GetWithTime("swap")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), value)
+	})
+
+	t.Run("compare and swap sets the ttl", func(t *testing.T) {
+		set, err := store.SetIfNotExistsWithTTL("swap_ttl", 1, time.Minute)
+		require.NoError(t, err)
+		require.True(t, set)
+
+		swapped, err := store.CompareAndSwapWithTTL("swap_ttl", 1, 2, 50*time.Millisecond)
+		require.NoError(t, err)
+		require.True(t, swapped)
+
+		require.Eventually(t, func() bool {
+			value, _, err := store.GetWithTime("swap_ttl")
+			return err == nil && value == -1
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Stats are the statistics of a cache since it was created.
+type Stats struct {
+	// Size is the maximum number of items of the cache, or 0 if it is not bounded.
+	Size int
+	// Len is the number of items in the cache, or -1 if it cannot be known cheaply.
+	Len    int
+	Hits   uint64
+	Misses uint64
+}
+
+// counters counts the hits and misses of the reads of a cache.
+type counters struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// count counts the read as a hit or a miss depending on its error, other errors counting as neither.
+func (c *counters) count(err error) {
+	switch err {
+	case nil:
+		c.hits.Add(1)
+	case ErrKeyNotFound:
+		c.misses.Add(1)
+	}
+}
+
+// registry keeps the caches created by a provider so that they can be listed.
+type registry struct {
+	mut    sync.RWMutex
+	caches []Cache
+}
+
+func (r *registry) register(c Cache) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	r.caches = append(r.caches, c)
+}
+
+// Caches returns the caches created by the provider.
+func (r *registry) Caches() []Cache {
+	r.mut.RLock()
+	defer r.mut.RUnlock()
+	return append([]Cache(nil), r.caches...)
+}
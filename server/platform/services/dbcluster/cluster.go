@@ -0,0 +1,409 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Package dbcluster implements the cluster interface over the Postgres database, so that several app nodes
+// can run behind a load balancer with no extra infrastructure: the nodes discover each other through the
+// ClusterDiscovery table, elect a leader with an advisory lock and exchange messages with LISTEN/NOTIFY,
+// the messages too large for a notification going through the ClusterMessages table.
+package dbcluster
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/einterfaces"
+)
+
+const (
+	// NotifyChannel is the channel of the notifications exchanged by the nodes.
+	NotifyChannel = "mattermost_cluster"
+
+	// MaxNotifyPayload is the size above which a message is stored in the ClusterMessages table, the
+	// notification only referencing it. Postgres limits the payload of a notification to 8000 bytes.
+	MaxNotifyPayload = 7000
+
+	PingInterval           = 15 * time.Second
+	NodeOfflineAfter       = 4 * PingInterval
+	LeaderElectionInterval = 5 * time.Second
+	CleanupInterval        = time.Minute
+	MessageRetention       = 10 * time.Minute
+	RequestTimeout         = 15 * time.Second
+
+	listenerMinReconnect = 1 * time.Second
+	listenerMaxReconnect = 30 * time.Second
+	listenerPingInterval = 90 * time.Second
+)
+
+type ServerIface interface {
+	Config() *model.Config
+	Log() mlog.LoggerIFace
+	GetStore() store.Store
+	Metrics() einterfaces.MetricsInterface
+	ReloadConfig() error
+	ClientConfigHash() string
+	DatabaseTypeAndSchemaVersion() (string, string, error)
+	InvokeClusterLeaderChangedListeners()
+	TotalWebsocketConnections() int
+	WebConnCountForUser(userID string) int
+	GetWSQueues(userID, connectionID string, seqNum int64) (*model.WSQueues, error)
+	GetPluginStatuses() (model.PluginStatuses, *model.AppError)
+	GetLogsSkipSend(rctx request.CTX, page, perPage int, logFilter *model.LogFilter) ([]string, *model.AppError)
+	GenerateSupportPacket(rctx request.CTX, options *model.SupportPacketOptions) ([]model.FileData, error)
+}
+
+// envelope is the payload of a notification.
+type envelope struct {
+	From string `json:"from"`
+	// To is the id of the node the message is sent to, all the other nodes if empty.
+	To string `json:"to,omitempty"`
+	// Ref is the id of the row of the ClusterMessages table holding the message, which is then not inlined.
+	Ref   string             `json:"ref,omitempty"`
+	Event model.ClusterEvent `json:"event,omitempty"`
+	Data  []byte             `json:"data,omitempty"`
+	Props map[string]string  `json:"props,omitempty"`
+}
+
+// Cluster implements einterfaces.ClusterInterface over the Postgres database.
+type Cluster struct {
+	server    ServerIface
+	id        string
+	discovery *model.ClusterDiscovery
+
+	handlersMut sync.RWMutex
+	handlers    map[model.ClusterEvent]einterfaces.ClusterMessageHandler
+
+	requests sync.Map // request id -> chan *envelope
+
+	listener  *pq.Listener
+	connected atomic.Bool
+	leader    atomic.Bool
+	lockConn  *sql.Conn
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+var _ einterfaces.ClusterInterface = (*Cluster)(nil)
+
+func New(server ServerIface) *Cluster {
+	return &Cluster{
+		server:   server,
+		id:       model.NewId(),
+		handlers: make(map[model.ClusterEvent]einterfaces.ClusterMessageHandler),
+	}
+}
+
+func (c *Cluster) db() *sql.DB {
+	return c.server.GetStore().GetInternalMasterDB()
+}
+
+func (c *Cluster) StartInterNodeCommunication() {
+	settings := c.server.Config().ClusterSettings
+	c.discovery = &model.ClusterDiscovery{
+		Id:          c.id,
+		Type:        model.CDSTypeApp,
+		ClusterName: *settings.ClusterName,
+		Hostname:    *settings.OverrideHostname,
+	}
+	if *settings.UseIPAddress {
+		c.discovery.AutoFillIPAddress(*settings.NetworkInterface, *settings.AdvertiseAddress)
+	} else {
+		c.discovery.AutoFillHostname()
+	}
+
+	discoveryStore := c.server.GetStore().ClusterDiscovery()
+	if _, err := discoveryStore.Delete(c.discovery); err != nil {
+		c.server.Log().Warn("Failed to remove the previous cluster discovery of the node", mlog.String("hostname", c.discovery.Hostname), mlog.Err(err))
+	}
+	if err := discoveryStore.Save(c.discovery); err != nil {
+		c.server.Log().Error("Failed to save the cluster discovery of the node", mlog.String("hostname", c.discovery.Hostname), mlog.Err(err))
+	}
+
+	c.listener = pq.NewListener(*c.server.Config().SqlSettings.DataSource, listenerMinReconnect, listenerMaxReconnect, c.onListenerEvent)
+	if err := c.listener.Listen(NotifyChannel); err != nil {
+		c.server.Log().Error("Failed to listen to the cluster notifications", mlog.String("channel", NotifyChannel), mlog.Err(err))
+	}
+
+	c.stop = make(chan struct{})
+	c.wg.Add(3)
+	go c.receive()
+	go c.ping()
+	go c.electLeader()
+
+	c.server.Log().Info("Started the database cluster", mlog.String("cluster_id", c.id), mlog.String("hostname", c.discovery.Hostname))
+}
+
+func (c *Cluster) StopInterNodeCommunication() {
+	if c.stop == nil {
+		return
+	}
+	close(c.stop)
+	c.wg.Wait()
+	c.stop = nil
+
+	c.releaseLeadership()
+
+	if err := c.listener.Close(); err != nil {
+		c.server.Log().Warn("Failed to close the cluster listener", mlog.Err(err))
+	}
+
+	if _, err := c.server.GetStore().ClusterDiscovery().Delete(c.discovery); err != nil {
+		c.server.Log().Warn("Failed to remove the cluster discovery of the node", mlog.String("hostname", c.discovery.Hostname), mlog.Err(err))
+	}
+
+	c.server.Log().Info("Stopped the database cluster", mlog.String("cluster_id", c.id))
+}
+
+func (c *Cluster) onListenerEvent(event pq.ListenerEventType, err error) {
+	switch event {
+	case pq.ListenerEventConnected:
+		c.connected.Store(true)
+	case pq.ListenerEventReconnected:
+		c.connected.Store(true)
+		// The notifications sent while disconnected are lost, so nothing cached can be trusted.
+		go c.handle(&envelope{Event: model.ClusterEventInvalidateAllCaches})
+	case pq.ListenerEventDisconnected, pq.ListenerEventConnectionAttemptFailed:
+		c.connected.Store(false)
+		c.server.Log().Warn("Lost the connection listening to the cluster notifications", mlog.Err(err))
+	}
+}
+
+func (c *Cluster) receive() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(listenerPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case notification := <-c.listener.Notify:
+			// A nil notification signals a reconnection, handled by onListenerEvent.
+			if notification != nil {
+				c.NotifyMsg([]byte(notification.Extra))
+			}
+		case <-ticker.C:
+			if err := c.listener.Ping(); err != nil {
+				c.server.Log().Warn("Failed to ping the cluster listener", mlog.Err(err))
+			}
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+func (c *Cluster) ping() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.server.GetStore().ClusterDiscovery().SetLastPingAt(c.discovery); err != nil {
+				c.server.Log().Error("Failed to ping the cluster discovery of the node", mlog.String("hostname", c.discovery.Hostname), mlog.Err(err))
+			}
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+func (c *Cluster) RegisterClusterMessageHandler(event model.ClusterEvent, crm einterfaces.ClusterMessageHandler) {
+	c.handlersMut.Lock()
+	defer c.handlersMut.Unlock()
+	c.handlers[event] = crm
+}
+
+func (c *Cluster) GetClusterId() string {
+	return c.id
+}
+
+// HealthScore is 0 while listening to the notifications of the other nodes, 1 otherwise.
+func (c *Cluster) HealthScore() int {
+	if c.connected.Load() {
+		return 0
+	}
+	return 1
+}
+
+func (c *Cluster) GetMyClusterInfo() *model.ClusterInfo {
+	info := &model.ClusterInfo{
+		Id:         c.id,
+		Version:    model.CurrentVersion,
+		ConfigHash: c.server.ClientConfigHash(),
+		IPAddress:  model.GetServerIPAddress(*c.server.Config().ClusterSettings.NetworkInterface),
+	}
+	if c.discovery != nil {
+		info.Hostname = c.discovery.Hostname
+	}
+	if _, schemaVersion, err := c.server.DatabaseTypeAndSchemaVersion(); err == nil {
+		info.SchemaVersion = schemaVersion
+	}
+	return info
+}
+
+func (c *Cluster) GetClusterInfos() []*model.ClusterInfo {
+	infos := []*model.ClusterInfo{c.GetMyClusterInfo()}
+
+	responses, err := c.request(requestClusterInfo, nil, nil)
+	if err != nil {
+		c.server.Log().Warn("Failed to get the cluster infos of every node", mlog.Err(err))
+	}
+	for _, response := range responses {
+		var info model.ClusterInfo
+		if err := json.Unmarshal(response.Data, &info); err != nil {
+			c.server.Log().Warn("Failed to decode the cluster info of a node", mlog.String("node_id", response.From), mlog.Err(err))
+			continue
+		}
+		infos = append(infos, &info)
+	}
+	return infos
+}
+
+func (c *Cluster) SendClusterMessage(msg *model.ClusterMessage) {
+	if err := c.send("", msg); err != nil {
+		c.server.Log().Error("Failed to send the cluster message", mlog.String("event", string(msg.Event)), mlog.Err(err))
+	}
+}
+
+func (c *Cluster) SendClusterMessageToNode(nodeID string, msg *model.ClusterMessage) error {
+	return c.send(nodeID, msg)
+}
+
+// ConfigChanged makes the other nodes reload the configuration from its store, the config being expected
+// to be kept in the database in a cluster.
+func (c *Cluster) ConfigChanged(previousConfig *model.Config, newConfig *model.Config, sendToOtherServer bool) *model.AppError {
+	if !sendToOtherServer {
+		return nil
+	}
+
+	if err := c.send("", &model.ClusterMessage{Event: clusterEventConfigChanged}); err != nil {
+		return model.NewAppError("ConfigChanged", "app.cluster.config_changed.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+// send notifies the message to the node, all the other nodes if empty.
+func (c *Cluster) send(to string, msg *model.ClusterMessage) error {
+	if metrics := c.server.Metrics(); metrics != nil {
+		metrics.IncrementClusterEventType(msg.Event)
+	}
+
+	payload, err := json.Marshal(&envelope{From: c.id, To: to, Event: msg.Event, Data: msg.Data, Props: msg.Props})
+	if err != nil {
+		return fmt.Errorf("failed to encode the cluster message: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+	defer cancel()
+
+	if len(payload) > MaxNotifyPayload {
+		ref := model.NewId()
+		if _, err = c.db().ExecContext(ctx, "INSERT INTO ClusterMessages (Id, Data, CreateAt) VALUES ($1, $2, $3)", ref, string(payload), model.GetMillis()); err != nil {
+			return fmt.Errorf("failed to save the cluster message: %w", err)
+		}
+		if payload, err = json.Marshal(&envelope{From: c.id, To: to, Ref: ref}); err != nil {
+			return fmt.Errorf("failed to encode the cluster message reference: %w", err)
+		}
+	}
+
+	if _, err := c.db().ExecContext(ctx, "SELECT pg_notify($1, $2)", NotifyChannel, string(payload)); err != nil {
+		return fmt.Errorf("failed to notify the cluster message: %w", err)
+	}
+	return nil
+}
+
+// NotifyMsg handles the payload of a notification.
+func (c *Cluster) NotifyMsg(buf []byte) {
+	var env envelope
+	if err := json.Unmarshal(buf, &env); err != nil {
+		c.server.Log().Warn("Failed to decode the cluster notification", mlog.Err(err))
+		return
+	}
+	if env.From == c.id || (env.To != "" && env.To != c.id) {
+		return
+	}
+
+	if ref := env.Ref; ref != "" {
+		var data string
+		if err := c.db().QueryRow("SELECT Data FROM ClusterMessages WHERE Id = $1", ref).Scan(&data); err != nil {
+			c.server.Log().Warn("Failed to get the cluster message", mlog.String("ref", ref), mlog.Err(err))
+			return
+		}
+		env = envelope{}
+		if err := json.Unmarshal([]byte(data), &env); err != nil {
+			c.server.Log().Warn("Failed to decode the cluster message", mlog.String("ref", ref), mlog.Err(err))
+			return
+		}
+	}
+
+	c.handle(&env)
+}
+
+func (c *Cluster) handle(env *envelope) {
+	switch env.Event {
+	case clusterEventRequest:
+		// Requests can be slow, e.g. generating a support packet, and must not hold the other messages.
+		go c.handleRequest(env)
+		return
+	case clusterEventResponse:
+		c.handleResponse(env)
+		return
+	case clusterEventConfigChanged:
+		if err := c.server.ReloadConfig(); err != nil {
+			c.server.Log().Error("Failed to reload the configuration changed by another node", mlog.String("node_id", env.From), mlog.Err(err))
+		}
+		return
+	}
+
+	c.handlersMut.RLock()
+	handler := c.handlers[env.Event]
+	c.handlersMut.RUnlock()
+
+	if handler == nil {
+		c.server.Log().Debug("No handler for the cluster message", mlog.String("event", string(env.Event)))
+		return
+	}
+	handler(&model.ClusterMessage{Event: env.Event, Data: env.Data, Props: env.Props})
+}
+
+// otherNodes returns the ids of the nodes of the cluster, but this one, which pinged recently.
+func (c *Cluster) otherNodes() ([]string, error) {
+	discoveries, err := c.server.GetStore().ClusterDiscovery().GetAll(model.CDSTypeApp, *c.server.Config().ClusterSettings.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	onlineSince := model.GetMillis() - NodeOfflineAfter.Milliseconds()
+	var nodes []string
+	for _, discovery := range discoveries {
+		if discovery.Id != c.id && discovery.LastPingAt > onlineSince {
+			nodes = append(nodes, discovery.Id)
+		}
+	}
+	return nodes, nil
+}
+
+// cleanup removes the messages all the nodes had the time to read and the nodes which stopped pinging.
+func (c *Cluster) cleanup() {
+	if _, err := c.db().Exec("DELETE FROM ClusterMessages WHERE CreateAt < $1", model.GetMillis()-MessageRetention.Milliseconds()); err != nil {
+		c.server.Log().Warn("Failed to remove the old cluster messages", mlog.Err(err))
+	}
+
+	if err := c.server.GetStore().ClusterDiscovery().Cleanup(); err != nil {
+		c.server.Log().Warn("Failed to remove the outdated cluster discoveries", mlog.Err(err))
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package dbcluster
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/store"
+	"github.com/mattermost/mattermost/server/v8/channels/store/sqlstore"
+	"github.com/mattermost/mattermost/server/v8/channels/store/storetest"
+	"github.com/mattermost/mattermost/server/v8/einterfaces"
+)
+
+type testServer struct {
+	ServerIface
+	logger  mlog.LoggerIFace
+	reloads int
+	config  *model.Config
+	store   store.Store
+}
+
+func (ts *testServer) Log() mlog.LoggerIFace {
+	return ts.logger
+}
+
+func (ts *testServer) ReloadConfig() error {
+	ts.reloads++
+	return nil
+}
+
+func (ts *testServer) Config() *model.Config {
+	return ts.config
+}
+
+func (ts *testServer) GetStore() store.Store {
+	return ts.store
+}
+
+func (ts *testServer) Metrics() einterfaces.MetricsInterface {
+	return nil
+}
+
+func (ts *testServer) InvokeClusterLeaderChangedListeners() {}
+
+// setupPostgres returns a store over a new Postgres database of the test environment.
+func setupPostgres(t *testing.T) (*sqlstore.SqlStore, *model.SqlSettings) {
+	if testing.Short() {
+		t.Skip("skipping the tests requiring Postgres in short mode")
+	}
+	if driverName := os.Getenv("MM_SQLSETTINGS_DRIVERNAME"); driverName != "" && driverName != model.DatabaseDriverPostgres {
+		t.Skip("The database cluster requires a Postgres database")
+	}
+
+	settings := storetest.MakeSqlSettings(model.DatabaseDriverPostgres, false)
+	ss, err := sqlstore.New(*settings, mlog.CreateConsoleTestLogger(t), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		ss.Close()
+		storetest.CleanupSqlSettings(settings)
+	})
+	return ss, settings
+}
+
+// startNode starts a node of the cluster over the database, stopped at the end of the test.
+func startNode(t *testing.T, ss store.Store, settings *model.SqlSettings) *Cluster {
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+	cfg.SqlSettings = *settings
+	cfg.ClusterSettings.Enable = model.NewPointer(true)
+	cfg.ClusterSettings.Transport = model.NewPointer(model.ClusterTransportDatabase)
+	cfg.ClusterSettings.ClusterName = model.NewPointer("test")
+
+	c := New(&testServer{logger: mlog.CreateConsoleTestLogger(t), config: cfg, store: ss})
+	c.StartInterNodeCommunication()
+	t.Cleanup(c.StopInterNodeCommunication)
+
+	require.Eventually(t, func() bool {
+		return c.HealthScore() == 0
+	}, 10*time.Second, 50*time.Millisecond, "the node listens to the notifications")
+	return c
+}
+
+func receive(t *testing.T, c *Cluster, event model.ClusterEvent) <-chan *model.ClusterMessage {
+	t.Helper()
+	received := make(chan *model.ClusterMessage, 10)
+	c.RegisterClusterMessageHandler(event, func(msg *model.ClusterMessage) {
+		received <- msg
+	})
+	return received
+}
+
+func waitMessage(t *testing.T, received <-chan *model.ClusterMessage) *model.ClusterMessage {
+	t.Helper()
+	select {
+	case msg := <-received:
+		return msg
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "no cluster message received")
+		return nil
+	}
+}
+
+func notification(t *testing.T, env *envelope) []byte {
+	t.Helper()
+	buf, err := json.Marshal(env)
+	require.NoError(t, err)
+	return buf
+}
+
+func TestLeaderLockKey(t *testing.T) {
+	assert.Equal(t, leaderLockKey("production"), leaderLockKey("production"))
+	assert.NotEqual(t, leaderLockKey("production"), leaderLockKey("staging"))
+}
+
+func TestNotifyMsg(t *testing.T) {
+	server := &testServer{logger: mlog.CreateConsoleTestLogger(t)}
+	c := New(server)
+
+	var received []*model.ClusterMessage
+	c.RegisterClusterMessageHandler(model.ClusterEventInvalidateCacheForUser, func(msg *model.ClusterMessage) {
+		received = append(received, msg)
+	})
+
+	other := model.NewId()
+
+	t.Run("messages of the other nodes are handled", func(t *testing.T) {
+		received = nil
+		c.NotifyMsg(notification(t, &envelope{From: other, Event: model.ClusterEventInvalidateCacheForUser, Data: []byte("user")}))
+		c.NotifyMsg(notification(t, &envelope{From: other, To: c.GetClusterId(), Event: model.ClusterEventInvalidateCacheForUser, Props: map[string]string{"key": "value"}}))
+
+		require.Len(t, received, 2)
+		assert.Equal(t, []byte("user"), received[0].Data)
+		assert.Equal(t, map[string]string{"key": "value"}, received[1].Props)
+	})
+
+	t.Run("messages of this node or for another node are ignored", func(t *testing.T) {
+		received = nil
+		c.NotifyMsg(notification(t, &envelope{From: c.GetClusterId(), Event: model.ClusterEventInvalidateCacheForUser}))
+		c.NotifyMsg(notification(t, &envelope{From: other, To: model.NewId(), Event: model.ClusterEventInvalidateCacheForUser}))
+		c.NotifyMsg([]byte("not json"))
+
+		assert.Empty(t, received)
+	})
+
+	t.Run("messages without handler are ignored", func(t *testing.T) {
+		received = nil
+		c.NotifyMsg(notification(t, &envelope{From: other, Event: model.ClusterEventInvalidateCacheForRoles}))
+
+		assert.Empty(t, received)
+	})
+
+	t.Run("config changes reload the config", func(t *testing.T) {
+		c.NotifyMsg(notification(t, &envelope{From: other, Event: clusterEventConfigChanged}))
+
+		assert.Equal(t, 1, server.reloads)
+	})
+
+	t.Run("responses are delivered to the pending request", func(t *testing.T) {
+		responses := make(chan *envelope, 1)
+		c.requests.Store("request", responses)
+		defer c.requests.Delete("request")
+
+		c.NotifyMsg(notification(t, &envelope{From: other, To: c.GetClusterId(), Event: clusterEventResponse, Data: []byte("3"), Props: map[string]string{propRequestID: "request"}}))
+		c.NotifyMsg(notification(t, &envelope{From: other, To: c.GetClusterId(), Event: clusterEventResponse, Props: map[string]string{propRequestID: "unknown"}}))
+
+		require.Len(t, responses, 1)
+		response := <-responses
+		assert.Equal(t, other, response.From)
+		assert.Equal(t, []byte("3"), response.Data)
+	})
+}
+
+func TestClusterPostgres(t *testing.T) {
+	ss, settings := setupPostgres(t)
+
+	t.Run("messages are notified to the other nodes", func(t *testing.T) {
+		a := startNode(t, ss, settings)
+		b := startNode(t, ss, settings)
+		received := receive(t, b, model.ClusterEventInvalidateCacheForUser)
+
+		a.SendClusterMessage(&model.ClusterMessage{Event: model.ClusterEventInvalidateCacheForUser, Data: []byte("user"), Props: map[string]string{"key": "value"}})
+
+		msg := waitMessage(t, received)
+		assert.Equal(t, []byte("user"), msg.Data)
+		assert.Equal(t, map[string]string{"key": "value"}, msg.Props)
+
+		require.NoError(t, a.SendClusterMessageToNode(b.GetClusterId(), &model.ClusterMessage{Event: model.ClusterEventInvalidateCacheForUser, Data: []byte("to b")}))
+		assert.Equal(t, []byte("to b"), waitMessage(t, received).Data)
+	})
+
+	t.Run("messages over the notification limit go through the database", func(t *testing.T) {
+		a := startNode(t, ss, settings)
+		b := startNode(t, ss, settings)
+		received := receive(t, b, model.ClusterEventInvalidateCacheForUser)
+
+		var before int
+		require.NoError(t, ss.GetInternalMasterDB().QueryRow("SELECT COUNT(*) FROM ClusterMessages").Scan(&before))
+
+		data := []byte(strings.Repeat("x", MaxNotifyPayload))
+		a.SendClusterMessage(&model.ClusterMessage{Event: model.ClusterEventInvalidateCacheForUser, Data: data})
+
+		assert.Equal(t, data, waitMessage(t, received).Data)
+
+		var after int
+		require.NoError(t, ss.GetInternalMasterDB().QueryRow("SELECT COUNT(*) FROM ClusterMessages").Scan(&after))
+		assert.Equal(t, before+1, after)
+	})
+
+	t.Run("another node takes over when the leader stops", func(t *testing.T) {
+		a := startNode(t, ss, settings)
+		b := startNode(t, ss, settings)
+
+		require.Eventually(t, func() bool {
+			return a.IsLeader() != b.IsLeader()
+		}, 3*LeaderElectionInterval, 100*time.Millisecond, "a single node leads")
+
+		leader, other := a, b
+		if b.IsLeader() {
+			leader, other = b, a
+		}
+		leader.StopInterNodeCommunication()
+		assert.False(t, leader.IsLeader())
+
+		require.Eventually(t, func() bool {
+			return other.IsLeader()
+		}, 3*LeaderElectionInterval, 100*time.Millisecond, "the other node takes over")
+	})
+
+	t.Run("the listener reconnects and invalidates the caches", func(t *testing.T) {
+		a := startNode(t, ss, settings)
+		b := startNode(t, ss, settings)
+		invalidated := receive(t, b, model.ClusterEventInvalidateAllCaches)
+		received := receive(t, b, model.ClusterEventInvalidateCacheForUser)
+
+		// The connections of the listeners are the ones which last ran LISTEN.
+		result, err := ss.GetInternalMasterDB().Exec(`SELECT pg_terminate_backend(pid) FROM pg_stat_activity
+			WHERE datname = current_database() AND query ILIKE 'LISTEN%' AND pid <> pg_backend_pid()`)
+		require.NoError(t, err)
+		terminated, err := result.RowsAffected()
+		require.NoError(t, err)
+		require.NotZero(t, terminated)
+
+		waitMessage(t, invalidated)
+		require.Eventually(t, func() bool {
+			return b.HealthScore() == 0
+		}, 10*time.Second, 50*time.Millisecond, "the node listens again")
+
+		a.SendClusterMessage(&model.ClusterMessage{Event: model.ClusterEventInvalidateCacheForUser, Data: []byte("user")})
+		assert.Equal(t, []byte("user"), waitMessage(t, received).Data)
+	})
+}
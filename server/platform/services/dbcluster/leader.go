@@ -0,0 +1,118 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package dbcluster
+
+import (
+	"context"
+	"database/sql/driver"
+	"hash/fnv"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// leaderLockKey returns the key of the advisory lock held by the leader of the cluster.
+func leaderLockKey(clusterName string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("mattermost_cluster_leader:" + clusterName))
+	return int64(h.Sum64())
+}
+
+func (c *Cluster) IsLeader() bool {
+	return c.leader.Load()
+}
+
+// electLeader competes for the advisory lock of the leader, held by a connection of its own for as long as
+// the node runs, so that another node takes over as soon as the leader or its connection goes away.
+func (c *Cluster) electLeader() {
+	defer c.wg.Done()
+
+	c.updateLeadership()
+
+	ticker := time.NewTicker(LeaderElectionInterval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Now()
+	for {
+		select {
+		case <-ticker.C:
+			c.updateLeadership()
+			if c.IsLeader() && time.Since(lastCleanup) >= CleanupInterval {
+				c.cleanup()
+				lastCleanup = time.Now()
+			}
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+func (c *Cluster) updateLeadership() {
+	leader := c.holdLeaderLock()
+	if c.leader.Swap(leader) != leader {
+		c.server.Log().Info("Cluster leadership changed", mlog.String("cluster_id", c.id), mlog.Bool("leader", leader))
+		c.server.InvokeClusterLeaderChangedListeners()
+	}
+}
+
+// holdLeaderLock returns whether the node holds the lock of the leader, trying to acquire it if not.
+func (c *Cluster) holdLeaderLock() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), LeaderElectionInterval)
+	defer cancel()
+
+	if c.lockConn == nil {
+		conn, err := c.db().Conn(ctx)
+		if err != nil {
+			c.server.Log().Warn("Failed to get a connection for the cluster leader election", mlog.Err(err))
+			return false
+		}
+		c.lockConn = conn
+	}
+
+	if c.IsLeader() {
+		// The lock is released with the session, so the leader remains while its connection is alive.
+		if err := c.lockConn.PingContext(ctx); err != nil {
+			c.server.Log().Warn("Lost the connection holding the cluster leadership", mlog.Err(err))
+			c.closeLockConn()
+			return false
+		}
+		return true
+	}
+
+	var acquired bool
+	err := c.lockConn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", leaderLockKey(*c.server.Config().ClusterSettings.ClusterName)).Scan(&acquired)
+	if err != nil {
+		c.server.Log().Warn("Failed to try the cluster leader lock", mlog.Err(err))
+		c.closeLockConn()
+		return false
+	}
+	return acquired
+}
+
+func (c *Cluster) releaseLeadership() {
+	if c.lockConn == nil {
+		return
+	}
+
+	if c.leader.Swap(false) {
+		ctx, cancel := context.WithTimeout(context.Background(), LeaderElectionInterval)
+		defer cancel()
+		if _, err := c.lockConn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", leaderLockKey(*c.server.Config().ClusterSettings.ClusterName)); err != nil {
+			c.server.Log().Warn("Failed to release the cluster leader lock", mlog.Err(err))
+		}
+	}
+	c.closeLockConn()
+}
+
+// closeLockConn discards the connection of the leader election rather than returning it to the pool, where
+// its session could keep holding the lock.
+func (c *Cluster) closeLockConn() {
+	_ = c.lockConn.Raw(func(any) error {
+		return driver.ErrBadConn
+	})
+	if err := c.lockConn.Close(); err != nil {
+		c.server.Log().Debug("Failed to close the connection of the cluster leader election", mlog.Err(err))
+	}
+	c.lockConn = nil
+}
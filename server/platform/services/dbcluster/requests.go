@@ -0,0 +1,304 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package dbcluster
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+const (
+	clusterEventRequest       model.ClusterEvent = "db_cluster_request"
+	clusterEventResponse      model.ClusterEvent = "db_cluster_response"
+	clusterEventConfigChanged model.ClusterEvent = "db_cluster_config_changed"
+
+	requestClusterInfo = "db_cluster_request_cluster_info"
+
+	propRequest   = "request"
+	propRequestID = "request_id"
+	propError     = "error"
+)
+
+var errRequestTimeout = errors.New("timed out waiting for the responses of the other nodes")
+
+// request sends the request to the other nodes and returns their responses, along with errRequestTimeout
+// if some did not respond in time.
+func (c *Cluster) request(kind string, data []byte, props map[string]string) ([]*envelope, error) {
+	nodes, err := c.otherNodes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the nodes of the cluster: %w", err)
+	}
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+
+	id := model.NewId()
+	responses := make(chan *envelope, len(nodes))
+	c.requests.Store(id, responses)
+	defer c.requests.Delete(id)
+
+	requestProps := map[string]string{propRequest: kind, propRequestID: id}
+	for key, value := range props {
+		requestProps[key] = value
+	}
+	if err = c.send("", &model.ClusterMessage{Event: clusterEventRequest, Data: data, Props: requestProps}); err != nil {
+		return nil, err
+	}
+
+	timeout := time.NewTimer(RequestTimeout)
+	defer timeout.Stop()
+
+	var received []*envelope
+	for len(received) < len(nodes) {
+		select {
+		case response := <-responses:
+			if response.Props[propError] != "" {
+				return received, fmt.Errorf("node %s failed to respond: %s", response.From, response.Props[propError])
+			}
+			received = append(received, response)
+		case <-timeout.C:
+			return received, errRequestTimeout
+		}
+	}
+	return received, nil
+}
+
+func (c *Cluster) handleResponse(env *envelope) {
+	if responses, ok := c.requests.Load(env.Props[propRequestID]); ok {
+		select {
+		case responses.(chan *envelope) <- env:
+		default:
+		}
+	}
+}
+
+func (c *Cluster) handleRequest(env *envelope) {
+	data, err := c.respond(env)
+
+	props := map[string]string{propRequestID: env.Props[propRequestID]}
+	if err != nil {
+		c.server.Log().Warn("Failed to respond to the cluster request", mlog.String("request", env.Props[propRequest]), mlog.String("node_id", env.From), mlog.Err(err))
+		props[propError] = err.Error()
+	}
+
+	if err := c.send(env.From, &model.ClusterMessage{Event: clusterEventResponse, Data: data, Props: props}); err != nil {
+		c.server.Log().Warn("Failed to send the response to the cluster request", mlog.String("request", env.Props[propRequest]), mlog.String("node_id", env.From), mlog.Err(err))
+	}
+}
+
+func (c *Cluster) respond(env *envelope) ([]byte, error) {
+	rctx := request.EmptyContext(c.server.Log())
+
+	switch env.Props[propRequest] {
+	case requestClusterInfo:
+		return json.Marshal(c.GetMyClusterInfo())
+	case model.ClusterGossipEventRequestGetClusterStats:
+		return json.Marshal(&model.ClusterStats{
+			Id:                        c.id,
+			TotalWebsocketConnections: c.server.TotalWebsocketConnections(),
+			TotalReadDbConnections:    c.server.GetStore().TotalReadDbConnections(),
+			TotalMasterDbConnections:  c.server.GetStore().TotalMasterDbConnections(),
+		})
+	case model.ClusterGossipEventRequestGetPluginStatuses:
+		statuses, appErr := c.server.GetPluginStatuses()
+		if appErr != nil {
+			return nil, appErr
+		}
+		return json.Marshal(statuses)
+	case model.ClusterGossipEventRequestGetLogs:
+		page, _ := strconv.Atoi(env.Props["page"])
+		perPage, _ := strconv.Atoi(env.Props["per_page"])
+		lines, appErr := c.server.GetLogsSkipSend(rctx, page, perPage, &model.LogFilter{})
+		if appErr != nil {
+			return nil, appErr
+		}
+		return json.Marshal(lines)
+	case model.ClusterGossipEventRequestGenerateSupportPacket:
+		var options model.SupportPacketOptions
+		if err := json.Unmarshal(env.Data, &options); err != nil {
+			return nil, err
+		}
+		files, err := c.server.GenerateSupportPacket(rctx, &options)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(files)
+	case model.ClusterGossipEventRequestWebConnCount:
+		return json.Marshal(c.server.WebConnCountForUser(env.Props["user_id"]))
+	case model.ClusterGossipEventRequestWSQueues:
+		seqNum, _ := strconv.ParseInt(env.Props["seq_num"], 10, 64)
+		queues, err := c.server.GetWSQueues(env.Props["user_id"], env.Props["connection_id"], seqNum)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(queues)
+	}
+
+	return nil, fmt.Errorf("unknown request %q", env.Props[propRequest])
+}
+
+func requestAppError(where string, err error) *model.AppError {
+	return model.NewAppError(where, "app.cluster.request.app_error", nil, "", http.StatusInternalServerError).Wrap(err)
+}
+
+func (c *Cluster) GetClusterStats(rctx request.CTX) ([]*model.ClusterStats, *model.AppError) {
+	responses, err := c.request(model.ClusterGossipEventRequestGetClusterStats, nil, nil)
+	if err != nil {
+		return nil, requestAppError("GetClusterStats", err)
+	}
+
+	stats := make([]*model.ClusterStats, 0, len(responses))
+	for _, response := range responses {
+		var stat model.ClusterStats
+		if err := json.Unmarshal(response.Data, &stat); err != nil {
+			return nil, requestAppError("GetClusterStats", err)
+		}
+		stats = append(stats, &stat)
+	}
+	return stats, nil
+}
+
+func (c *Cluster) GetPluginStatuses() (model.PluginStatuses, *model.AppError) {
+	responses, err := c.request(model.ClusterGossipEventRequestGetPluginStatuses, nil, nil)
+	if err != nil {
+		return nil, requestAppError("GetPluginStatuses", err)
+	}
+
+	var statuses model.PluginStatuses
+	for _, response := range responses {
+		var nodeStatuses model.PluginStatuses
+		if err := json.Unmarshal(response.Data, &nodeStatuses); err != nil {
+			return nil, requestAppError("GetPluginStatuses", err)
+		}
+		statuses = append(statuses, nodeStatuses...)
+	}
+	return statuses, nil
+}
+
+// nodeLogs returns the log lines of the other nodes by hostname.
+func (c *Cluster) nodeLogs(where string, page, perPage int) (map[string][]string, *model.AppError) {
+	responses, err := c.request(model.ClusterGossipEventRequestGetLogs, nil, map[string]string{
+		"page":     strconv.Itoa(page),
+		"per_page": strconv.Itoa(perPage),
+	})
+	if err != nil {
+		return nil, requestAppError(where, err)
+	}
+
+	hostnames := c.hostnames()
+	logs := make(map[string][]string, len(responses))
+	for _, response := range responses {
+		var lines []string
+		if err := json.Unmarshal(response.Data, &lines); err != nil {
+			return nil, requestAppError(where, err)
+		}
+		hostname := hostnames[response.From]
+		if hostname == "" {
+			hostname = response.From
+		}
+		logs[hostname] = lines
+	}
+	return logs, nil
+}
+
+func (c *Cluster) GetLogs(rctx request.CTX, page, perPage int) ([]string, *model.AppError) {
+	logs, appErr := c.nodeLogs("GetLogs", page, perPage)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	var lines []string
+	for hostname, nodeLines := range logs {
+		separator := "-----------------------------------------------------------------------------------------------------------"
+		lines = append(lines, separator, separator, hostname, separator, separator)
+		lines = append(lines, nodeLines...)
+	}
+	return lines, nil
+}
+
+func (c *Cluster) QueryLogs(rctx request.CTX, page, perPage int) (map[string][]string, *model.AppError) {
+	return c.nodeLogs("QueryLogs", page, perPage)
+}
+
+func (c *Cluster) GenerateSupportPacket(rctx request.CTX, options *model.SupportPacketOptions) (map[string][]model.FileData, error) {
+	data, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := c.request(model.ClusterGossipEventRequestGenerateSupportPacket, data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]model.FileData, len(responses))
+	for _, response := range responses {
+		var nodeFiles []model.FileData
+		if err := json.Unmarshal(response.Data, &nodeFiles); err != nil {
+			return nil, err
+		}
+		files[response.From] = nodeFiles
+	}
+	return files, nil
+}
+
+func (c *Cluster) WebConnCountForUser(userID string) (int, *model.AppError) {
+	responses, err := c.request(model.ClusterGossipEventRequestWebConnCount, nil, map[string]string{"user_id": userID})
+	if err != nil {
+		return 0, requestAppError("WebConnCountForUser", err)
+	}
+
+	total := 0
+	for _, response := range responses {
+		var count int
+		if err := json.Unmarshal(response.Data, &count); err != nil {
+			return 0, requestAppError("WebConnCountForUser", err)
+		}
+		total += count
+	}
+	return total, nil
+}
+
+func (c *Cluster) GetWSQueues(userID, connectionID string, seqNum int64) (map[string]*model.WSQueues, error) {
+	responses, err := c.request(model.ClusterGossipEventRequestWSQueues, nil, map[string]string{
+		"user_id":       userID,
+		"connection_id": connectionID,
+		"seq_num":       strconv.FormatInt(seqNum, 10),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	queues := make(map[string]*model.WSQueues, len(responses))
+	for _, response := range responses {
+		var nodeQueues *model.WSQueues
+		if err := json.Unmarshal(response.Data, &nodeQueues); err != nil {
+			return nil, err
+		}
+		queues[response.From] = nodeQueues
+	}
+	return queues, nil
+}
+
+// hostnames returns the hostnames of the nodes by id.
+func (c *Cluster) hostnames() map[string]string {
+	hostnames := map[string]string{}
+	discoveries, err := c.server.GetStore().ClusterDiscovery().GetAll(model.CDSTypeApp, *c.server.Config().ClusterSettings.ClusterName)
+	if err != nil {
+		c.server.Log().Warn("Failed to get the nodes of the cluster", mlog.Err(err))
+		return hostnames
+	}
+	for _, discovery := range discoveries {
+		hostnames[discovery.Id] = discovery.Hostname
+	}
+	return hostnames
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package discordimport
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	userMention    = regexp.MustCompile(`<@!?(\d+)>`)
+	channelMention = regexp.MustCompile(`<#(\d+)>`)
+	customEmoji    = regexp.MustCompile(`<a?:(\w+):\d+>`)
+	timestampTag   = regexp.MustCompile(`<t:(-?\d+)(?::[tTdDfFR])?>`)
+)
+
+// convertTime returns the time of a DiscordChatExporter timestamp in milliseconds, or 0 if it is not a timestamp.
+func convertTime(value string) int64 {
+	if value == "" {
+		return 0
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// convertContent converts the content of a message to Markdown. Discord and Mattermost share most of their Markdown,
+// the mentions of the users and channels, and the custom emojis and timestamps are converted.
+func (c *converter) convertContent(m *dceMessage) string {
+	// DiscordChatExporter writes the mentions with the names of the users, the longest names are replaced first not
+	// to replace a part of them.
+	var names []string
+	usernames := make(map[string]string)
+	for _, mentioned := range m.Mentions {
+		u, ok := c.users[mentioned.Id]
+		if !ok {
+			continue
+		}
+		for _, name := range []string{mentioned.Nickname, mentioned.Name} {
+			if name != "" && usernames["@"+name] == "" {
+				names = append(names, "@"+name)
+				usernames["@"+name] = "@" + u.Username
+			}
+		}
+	}
+	names = append(names, "@everyone")
+	usernames["@everyone"] = "@all"
+	sort.SliceStable(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	var replacements []string
+	for _, name := range names {
+		replacements = append(replacements, name, usernames[name])
+	}
+	text := strings.NewReplacer(replacements...).Replace(m.Content)
+
+	text = userMention.ReplaceAllStringFunc(text, func(s string) string {
+		if u, ok := c.users[userMention.FindStringSubmatch(s)[1]]; ok {
+			return "@" + u.Username
+		}
+		return s
+	})
+	text = channelMention.ReplaceAllStringFunc(text, func(s string) string {
+		if name, ok := c.channelNames[channelMention.FindStringSubmatch(s)[1]]; ok {
+			return "~" + name
+		}
+		return s
+	})
+	text = customEmoji.ReplaceAllString(text, ":$1:")
+	text = timestampTag.ReplaceAllStringFunc(text, func(s string) string {
+		seconds, err := strconv.ParseInt(timestampTag.FindStringSubmatch(s)[1], 10, 64)
+		if err != nil {
+			return s
+		}
+		return time.Unix(seconds, 0).UTC().Format("2006-01-02 15:04 UTC")
+	})
+
+	return text
+}
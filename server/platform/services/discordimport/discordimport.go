@@ -0,0 +1,754 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Package discordimport converts a Discord export to the bulk import format.
+//
+// The export is a zip file of the JSON exports of DiscordChatExporter, one file per channel, thread or direct message,
+// in any directory. The partitions of an export are merged by channel. The files attached to the messages are read
+// from the zip file when the export was made with the --media option, at their path relative to the JSON file, and
+// are linked otherwise.
+//
+// The servers are converted to teams, their text channels to public channels, and their threads to the replies of the
+// messages they were started from. The direct messages are converted to direct and group messages. The users are the
+// authors of the messages and the users mentioned or reacting in them. Discord doesn't export the email addresses of
+// the users, they are given an address of Options.EmailDomain and must reset their password to sign in.
+package discordimport
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/app/imports"
+	"github.com/mattermost/mattermost/server/v8/platform/services/importconvert"
+)
+
+const (
+	// attachmentsDir is the directory of the attached files in the data directory of the import.
+	attachmentsDir = "attachments"
+
+	messageTypeDefault = "Default"
+	messageTypeReply   = "Reply"
+
+	channelTypeDirect      = "DirectTextChat"
+	channelTypeDirectGroup = "DirectGroupTextChat"
+
+	// defaultEmailDomain is the domain of the email addresses of the users by default, as for the Slack import.
+	defaultEmailDomain = "example.com"
+)
+
+type dceGuild struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type dceChannel struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+	// CategoryId is the id of the parent channel of a thread.
+	CategoryId string `json:"categoryId"`
+	Category   string `json:"category"`
+	Name       string `json:"name"`
+	Topic      string `json:"topic"`
+}
+
+type dceUser struct {
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+	Nickname string `json:"nickname"`
+	IsBot    bool   `json:"isBot"`
+}
+
+type dceAttachment struct {
+	Id       string `json:"id"`
+	Url      string `json:"url"`
+	FileName string `json:"fileName"`
+}
+
+type dceEmoji struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type dceReaction struct {
+	Emoji dceEmoji   `json:"emoji"`
+	Users []*dceUser `json:"users"`
+}
+
+type dceReference struct {
+	MessageId string `json:"messageId"`
+	ChannelId string `json:"channelId"`
+}
+
+type dceMessage struct {
+	Id              string           `json:"id"`
+	Type            string           `json:"type"`
+	Timestamp       string           `json:"timestamp"`
+	TimestampEdited string           `json:"timestampEdited"`
+	IsPinned        bool             `json:"isPinned"`
+	Content         string           `json:"content"`
+	Author          *dceUser         `json:"author"`
+	Attachments     []*dceAttachment `json:"attachments"`
+	Reactions       []*dceReaction   `json:"reactions"`
+	Mentions        []*dceUser       `json:"mentions"`
+	Reference       *dceReference    `json:"reference"`
+
+	// dir is the directory of the JSON file of the message, the paths of the attachments are relative to it.
+	dir string
+}
+
+type dceExport struct {
+	Guild    dceGuild      `json:"guild"`
+	Channel  dceChannel    `json:"channel"`
+	Messages []*dceMessage `json:"messages"`
+}
+
+func (e *dceExport) isDirect() bool {
+	return e.Channel.Type == channelTypeDirect || e.Channel.Type == channelTypeDirectGroup
+}
+
+func (e *dceExport) isThread() bool {
+	return strings.HasSuffix(e.Channel.Type, "Thread")
+}
+
+// Options tune the conversion.
+type Options struct {
+	// EmailDomain is the domain of the email addresses of the users. Defaults to example.com.
+	EmailDomain string
+	// MaxPostSize is the maximum number of runes of a message, the longer messages are truncated. Defaults to
+	// model.PostMessageMaxRunesV2.
+	MaxPostSize int
+}
+
+// Report counts the converted entities, and describes what could not be converted.
+type Report = importconvert.Report
+
+// thread is a root message with its replies.
+type thread struct {
+	root    *dceMessage
+	replies []*dceMessage
+}
+
+type team struct {
+	id       string
+	name     string
+	data     *imports.TeamImportData
+	channels []*channel
+	names    map[string]bool
+}
+
+type channel struct {
+	team    string
+	name    string
+	data    *imports.ChannelImportData
+	threads []*thread
+	// threadOf is the thread of the messages, by id.
+	threadOf map[string]*thread
+}
+
+type chat struct {
+	members []string
+	data    *imports.DirectChannelImportData
+	threads []*thread
+}
+
+type converter struct {
+	opts   Options
+	report *Report
+	files  map[string]*zip.File
+	writer *importconvert.Writer
+
+	users     map[string]*importconvert.User
+	usernames map[string]bool
+	teams     []*team
+	teamNames map[string]bool
+	chats     []*chat
+
+	// channelNames are the names of the converted channels by id, for the channel mentions.
+	channelNames map[string]string
+	// unknownEmojis are the emojis of the reactions that could not be converted, warned about once.
+	unknownEmojis map[string]bool
+
+	posts importconvert.PostConverter[*dceMessage]
+}
+
+// Convert reads a Discord export and writes a bulk import zip file, with the JSONL file and the attached files in the
+// model.ExportDataDir directory, ready for the import process job.
+func Convert(export *zip.Reader, w io.Writer, opts Options) (*Report, error) {
+	if opts.EmailDomain == "" {
+		opts.EmailDomain = defaultEmailDomain
+	}
+	if opts.MaxPostSize <= 0 {
+		opts.MaxPostSize = model.PostMessageMaxRunesV2
+	}
+
+	c := &converter{
+		opts:          opts,
+		report:        &Report{},
+		files:         make(map[string]*zip.File, len(export.File)),
+		users:         make(map[string]*importconvert.User),
+		usernames:     make(map[string]bool),
+		teamNames:     make(map[string]bool),
+		channelNames:  make(map[string]string),
+		unknownEmojis: make(map[string]bool),
+	}
+	c.posts = importconvert.PostConverter[*dceMessage]{Report: c.report, ConvertMessage: c.convertMessage}
+	for _, f := range export.File {
+		// avoid "zip slip"
+		if strings.Contains(f.Name, "..") {
+			return nil, fmt.Errorf("invalid file name %q in the export", f.Name)
+		}
+		c.files[f.Name] = f
+	}
+
+	exports, err := c.readExports()
+	if err != nil {
+		return nil, err
+	}
+	if len(exports) == 0 {
+		return nil, fmt.Errorf("the export has no DiscordChatExporter JSON file")
+	}
+
+	c.readUsers(exports)
+	c.readGuilds(exports)
+	c.readDirectMessages(exports)
+
+	if c.writer, err = importconvert.NewWriter(w); err != nil {
+		return nil, err
+	}
+	if err = c.writeLines(); err != nil {
+		return nil, err
+	}
+	c.report.Attachments = c.writer.Attachments()
+	if err = c.writer.Close(); err != nil {
+		return nil, err
+	}
+	return c.report, nil
+}
+
+// readExports reads the JSON files of the export, merging the partitions of the channels. The exports are sorted by
+// file name, and their messages by time.
+func (c *converter) readExports() ([]*dceExport, error) {
+	var names []string
+	for name := range c.files {
+		if path.Ext(name) == ".json" {
+			names = append(names, name)
+		}
+	}
+	// The partitions of a channel are named after the first one, e.g. "general [part 2].json".
+	sort.Slice(names, func(i, j int) bool {
+		return strings.TrimSuffix(names[i], ".json") < strings.TrimSuffix(names[j], ".json")
+	})
+
+	var exports []*dceExport
+	byChannel := make(map[string]*dceExport)
+	for _, name := range names {
+		e, err := parseExport(c.files[name])
+		if err != nil {
+			return nil, err
+		}
+		if e.Channel.Id == "" {
+			c.report.Warn("%s is not a DiscordChatExporter JSON export and was ignored", name)
+			continue
+		}
+		for _, m := range e.Messages {
+			m.dir = path.Dir(name)
+		}
+
+		if existing, ok := byChannel[e.Channel.Id]; ok {
+			existing.Messages = append(existing.Messages, e.Messages...)
+			continue
+		}
+		byChannel[e.Channel.Id] = e
+		exports = append(exports, e)
+	}
+
+	for _, e := range exports {
+		sort.SliceStable(e.Messages, func(i, j int) bool {
+			return convertTime(e.Messages[i].Timestamp) < convertTime(e.Messages[j].Timestamp)
+		})
+	}
+	return exports, nil
+}
+
+func parseExport(f *zip.File) (*dceExport, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer r.Close()
+
+	var e dceExport
+	if err = json.NewDecoder(r).Decode(&e); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", f.Name, err)
+	}
+	return &e, nil
+}
+
+// readUsers converts the authors of the messages, and the users mentioned or reacting in them.
+func (c *converter) readUsers(exports []*dceExport) {
+	for _, e := range exports {
+		for _, m := range e.Messages {
+			c.addUser(m.Author)
+			for _, mentioned := range m.Mentions {
+				c.addUser(mentioned)
+			}
+			for _, reaction := range m.Reactions {
+				for _, reactor := range reaction.Users {
+					c.addUser(reactor)
+				}
+			}
+		}
+	}
+	c.report.Users = len(c.users)
+}
+
+func (c *converter) addUser(du *dceUser) {
+	if du == nil || du.Id == "" || c.users[du.Id] != nil {
+		return
+	}
+
+	username := importconvert.UniqueName(importconvert.ConvertUsername(du.Name, du.Id, "discord"), model.UserNameMaxLength, c.usernames)
+	email := username + "@" + c.opts.EmailDomain
+	if !model.IsValidEmail(email) {
+		email = "discord-" + du.Id + "@" + c.opts.EmailDomain
+	}
+	data := &imports.UserImportData{
+		Username: model.NewPointer(username),
+		Email:    model.NewPointer(email),
+		Roles:    model.NewPointer(model.SystemUserRoleId),
+	}
+	if du.Nickname != "" && du.Nickname != du.Name {
+		data.Nickname = model.NewPointer(importconvert.TruncateRunes(du.Nickname, model.UserNicknameMaxRunes))
+	}
+	if du.IsBot {
+		// The bots are deactivated, their messages are kept.
+		data.DeleteAt = model.NewPointer(model.GetMillis())
+	}
+	c.users[du.Id] = importconvert.NewUser(username, data)
+}
+
+func (c *converter) readGuilds(exports []*dceExport) {
+	teams := make(map[string]*team)
+	channels := make(map[string]*channel)
+	var threads []*dceExport
+	for _, e := range exports {
+		if e.isDirect() {
+			continue
+		}
+		if e.isThread() {
+			threads = append(threads, e)
+			continue
+		}
+
+		t, ok := teams[e.Guild.Id]
+		if !ok {
+			t = c.addTeam(e.Guild)
+			teams[e.Guild.Id] = t
+		}
+		channels[e.Channel.Id] = c.addChannel(t, e)
+	}
+
+	for _, e := range threads {
+		parent, ok := channels[e.Channel.CategoryId]
+		if !ok {
+			// The thread is converted to a channel of its own.
+			c.report.Warn("The parent channel of thread %q is not in the export, the thread was converted to a channel", e.Channel.Name)
+			t, ok := teams[e.Guild.Id]
+			if !ok {
+				t = c.addTeam(e.Guild)
+				teams[e.Guild.Id] = t
+			}
+			channels[e.Channel.Id] = c.addChannel(t, e)
+			continue
+		}
+		c.addThread(parent, e)
+	}
+}
+
+func (c *converter) addTeam(guild dceGuild) *team {
+	name := importconvert.UniqueName(importconvert.ConvertName(guild.Name, guild.Id, "discord", true), model.TeamNameMaxLength, c.teamNames)
+	t := &team{
+		id:   guild.Id,
+		name: name,
+		data: &imports.TeamImportData{
+			Name:        model.NewPointer(name),
+			DisplayName: model.NewPointer(importconvert.TruncateRunes(guild.Name, model.TeamDisplayNameMaxRunes)),
+			Type:        model.NewPointer(model.TeamInvite),
+		},
+		names: make(map[string]bool),
+	}
+	c.teams = append(c.teams, t)
+	c.report.Teams++
+	return t
+}
+
+func (c *converter) addChannel(t *team, e *dceExport) *channel {
+	// The general channel of a server is the default channel of the team.
+	name := model.DefaultChannelName
+	if e.Channel.Name != "general" || t.names[name] {
+		name = importconvert.UniqueName(importconvert.ConvertName(e.Channel.Name, e.Channel.Id, "discord", false), model.ChannelNameMaxLength, t.names)
+	}
+	t.names[name] = true
+	c.channelNames[e.Channel.Id] = name
+
+	ch := &channel{
+		team: t.name,
+		name: name,
+		data: &imports.ChannelImportData{
+			Team:        model.NewPointer(t.name),
+			Name:        model.NewPointer(name),
+			DisplayName: model.NewPointer(importconvert.TruncateRunes(e.Channel.Name, model.ChannelDisplayNameMaxRunes)),
+			Type:        model.NewPointer(model.ChannelTypeOpen),
+			Header:      model.NewPointer(importconvert.TruncateRunes(e.Channel.Topic, model.ChannelHeaderMaxRunes)),
+		},
+		threadOf: make(map[string]*thread),
+	}
+	for _, m := range c.threadMessages(e) {
+		ch.addMessage(m)
+		c.joinChannel(t.name, name, m)
+	}
+
+	t.channels = append(t.channels, ch)
+	c.report.Channels++
+	return ch
+}
+
+// threadMessages returns the messages of an export that are converted, the others are counted as skipped.
+func (c *converter) threadMessages(e *dceExport) []*dceMessage {
+	var messages []*dceMessage
+	for _, m := range e.Messages {
+		// e.g. the system messages, as the members joining the server
+		if m.Type != messageTypeDefault && m.Type != messageTypeReply {
+			c.report.SkippedMessages++
+			continue
+		}
+		messages = append(messages, m)
+	}
+	return messages
+}
+
+// addMessage adds a message to the thread of the message it replies to, or as the root of a new thread.
+func (ch *channel) addMessage(m *dceMessage) {
+	if m.Reference != nil {
+		if t, ok := ch.threadOf[m.Reference.MessageId]; ok {
+			t.replies = append(t.replies, m)
+			ch.threadOf[m.Id] = t
+			return
+		}
+	}
+	t := &thread{root: m}
+	ch.threads = append(ch.threads, t)
+	ch.threadOf[m.Id] = t
+}
+
+// addThread adds the messages of a thread as the replies to the message the thread was started from, or to the first
+// message of the thread if the thread wasn't started from a message.
+func (c *converter) addThread(parent *channel, e *dceExport) {
+	messages := c.threadMessages(e)
+	for _, m := range messages {
+		c.joinChannel(parent.team, parent.name, m)
+	}
+	if len(messages) == 0 {
+		return
+	}
+
+	t, ok := parent.threadOf[e.Channel.Id]
+	if !ok {
+		root := *messages[0]
+		root.Content = strings.TrimSpace("**" + e.Channel.Name + "**\n" + root.Content)
+		t = &thread{root: &root}
+		parent.threads = append(parent.threads, t)
+		sortThreads(parent.threads)
+		messages = messages[1:]
+	}
+	for _, m := range messages {
+		t.replies = append(t.replies, m)
+		parent.threadOf[m.Id] = t
+	}
+	sortMessages(t.replies)
+}
+
+// joinChannel makes the author of a message a member of the channel, and the users mentioned or reacting in it members
+// of the team.
+func (c *converter) joinChannel(teamName, channelName string, m *dceMessage) {
+	if m.Author != nil {
+		if u, ok := c.users[m.Author.Id]; ok {
+			u.JoinChannel(teamName, channelName, false)
+		}
+	}
+	for _, mentioned := range m.Mentions {
+		if u, ok := c.users[mentioned.Id]; ok {
+			u.JoinTeam(teamName, false)
+		}
+	}
+	for _, reaction := range m.Reactions {
+		for _, reactor := range reaction.Users {
+			if u, ok := c.users[reactor.Id]; ok {
+				u.JoinTeam(teamName, false)
+			}
+		}
+	}
+}
+
+func (c *converter) readDirectMessages(exports []*dceExport) {
+	for _, e := range exports {
+		if !e.isDirect() {
+			continue
+		}
+		messages := c.threadMessages(e)
+
+		// The members of a direct message are its authors, and the recipients named after the channel.
+		seen := make(map[string]bool)
+		var members []string
+		addMember := func(u *importconvert.User) {
+			if !seen[u.Username] {
+				seen[u.Username] = true
+				members = append(members, u.Username)
+			}
+		}
+		for _, m := range messages {
+			if m.Author != nil && c.users[m.Author.Id] != nil {
+				addMember(c.users[m.Author.Id])
+			}
+		}
+		recipients := make(map[string]bool)
+		for _, name := range strings.Split(e.Channel.Name, ",") {
+			recipients[strings.TrimSpace(name)] = true
+		}
+		for _, m := range messages {
+			for _, mentioned := range m.Mentions {
+				if recipients[mentioned.Name] && c.users[mentioned.Id] != nil {
+					addMember(c.users[mentioned.Id])
+				}
+			}
+		}
+
+		switch {
+		case len(members) < 2:
+			c.report.SkippedMessages += len(messages)
+			c.report.Warn("Direct message %q has less than two known members and was skipped", e.Channel.Name)
+			continue
+		case len(members) > model.ChannelGroupMaxUsers:
+			c.report.SkippedMessages += len(messages)
+			c.report.Warn("Direct message %q has %d members, more than the %d members of a group message, and was skipped", e.Channel.Name, len(members), model.ChannelGroupMaxUsers)
+			continue
+		}
+
+		sort.Strings(members)
+		ch := &channel{threadOf: make(map[string]*thread)}
+		for _, m := range messages {
+			ch.addMessage(m)
+		}
+		c.chats = append(c.chats, &chat{
+			members: members,
+			data: &imports.DirectChannelImportData{
+				Members: &members,
+				Header:  model.NewPointer(importconvert.TruncateRunes(e.Channel.Topic, model.ChannelHeaderMaxRunes)),
+			},
+			threads: ch.threads,
+		})
+	}
+	c.report.DirectChannels = len(c.chats)
+}
+
+func sortMessages(messages []*dceMessage) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		return convertTime(messages[i].Timestamp) < convertTime(messages[j].Timestamp)
+	})
+}
+
+func sortThreads(threads []*thread) {
+	sort.SliceStable(threads, func(i, j int) bool {
+		return convertTime(threads[i].root.Timestamp) < convertTime(threads[j].root.Timestamp)
+	})
+}
+
+// writeLines writes the lines in the order of the import: the teams and the channels before their members, and the
+// users before their posts.
+func (c *converter) writeLines() error {
+	write := c.writer.WriteLine
+
+	if err := write(&imports.LineImportData{Type: "version", Version: model.NewPointer(1)}); err != nil {
+		return err
+	}
+	for _, t := range c.teams {
+		if err := write(&imports.LineImportData{Type: "team", Team: t.data}); err != nil {
+			return err
+		}
+	}
+	for _, t := range c.teams {
+		for _, ch := range t.channels {
+			if err := write(&imports.LineImportData{Type: "channel", Channel: ch.data}); err != nil {
+				return err
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(c.users))
+	for id := range c.users {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return c.users[ids[i]].Username < c.users[ids[j]].Username })
+	for _, id := range ids {
+		if err := write(&imports.LineImportData{Type: "user", User: c.users[id].ImportData()}); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range c.teams {
+		for _, ch := range t.channels {
+			for _, th := range ch.threads {
+				post := c.posts.ConvertPost(th.root, th.replies, t.name, ch.name, t.name+"/"+ch.name)
+				if post == nil {
+					continue
+				}
+				if err := write(&imports.LineImportData{Type: "post", Post: post}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, ch := range c.chats {
+		if err := write(&imports.LineImportData{Type: "direct_channel", DirectChannel: ch.data}); err != nil {
+			return err
+		}
+	}
+	for _, ch := range c.chats {
+		for _, th := range ch.threads {
+			post := c.posts.ConvertDirectPost(th.root, th.replies, ch.members, "the direct message of "+strings.Join(ch.members, ", "))
+			if post == nil {
+				continue
+			}
+			if err := write(&imports.LineImportData{Type: "direct_post", DirectPost: post}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// convertMessage returns the converted message, or nil if the message is skipped.
+func (c *converter) convertMessage(dm *dceMessage, where string) *importconvert.Message {
+	if dm.Author == nil {
+		c.report.SkippedMessages++
+		return nil
+	}
+	author, ok := c.users[dm.Author.Id]
+	if !ok {
+		c.report.SkippedMessages++
+		return nil
+	}
+	createAt := convertTime(dm.Timestamp)
+	if createAt == 0 {
+		c.report.SkippedMessages++
+		c.report.Warn("Message %s in %s has an invalid timestamp %q and was skipped", dm.Id, where, dm.Timestamp)
+		return nil
+	}
+
+	m := &importconvert.Message{
+		User:     author.Username,
+		CreateAt: createAt,
+	}
+	if editAt := convertTime(dm.TimestampEdited); editAt != 0 {
+		m.EditAt = model.NewPointer(editAt)
+	}
+	if dm.IsPinned {
+		m.IsPinned = model.NewPointer(true)
+	}
+
+	text := c.convertContent(dm)
+	var attachments []imports.AttachmentImportData
+	for _, attachment := range dm.Attachments {
+		if f, ok := c.attachmentFile(dm, attachment); ok {
+			p := path.Join(attachmentsDir, attachment.Id, path.Base(attachment.FileName))
+			attachments = append(attachments, c.writer.AddAttachment(p, f))
+			continue
+		}
+		if strings.HasPrefix(attachment.Url, "http://") || strings.HasPrefix(attachment.Url, "https://") {
+			text += fmt.Sprintf("\n[%s](%s)", attachment.FileName, attachment.Url)
+			continue
+		}
+		c.report.Warn("File %q of message %s in %s is not in the export and was skipped", attachment.FileName, dm.Id, where)
+	}
+	if len(attachments) > 0 {
+		m.Attachments = &attachments
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" && len(attachments) == 0 {
+		// e.g. the messages with embeds or stickers only
+		c.report.SkippedMessages++
+		return nil
+	}
+	if truncated := importconvert.TruncateRunes(text, c.opts.MaxPostSize); truncated != text {
+		c.report.Warn("Message %s in %s is longer than %d characters and was truncated", dm.Id, where, c.opts.MaxPostSize)
+		text = truncated
+	}
+	m.Text = text
+
+	var reactions []imports.ReactionImportData
+	seen := make(map[string]bool)
+	for _, reaction := range dm.Reactions {
+		emojiName, ok := c.convertReaction(reaction.Emoji)
+		if !ok {
+			continue
+		}
+		for _, reactor := range reaction.Users {
+			u, ok := c.users[reactor.Id]
+			if !ok {
+				continue
+			}
+			if key := u.Username + ":" + emojiName; !seen[key] {
+				seen[key] = true
+				// Discord doesn't export the time of the reactions.
+				reactions = append(reactions, imports.ReactionImportData{
+					User:      model.NewPointer(u.Username),
+					EmojiName: model.NewPointer(emojiName),
+					CreateAt:  model.NewPointer(createAt),
+				})
+			}
+		}
+	}
+	if len(reactions) > 0 {
+		m.Reactions = &reactions
+	}
+
+	return m
+}
+
+// attachmentFile returns the file of an attachment in the export, at its url relative to the JSON file.
+func (c *converter) attachmentFile(dm *dceMessage, attachment *dceAttachment) (*zip.File, bool) {
+	p := strings.ReplaceAll(attachment.Url, "\\", "/")
+	if unescaped, err := url.PathUnescape(p); err == nil {
+		p = unescaped
+	}
+	for _, candidate := range []string{path.Join(dm.dir, p), path.Clean(p)} {
+		if f, ok := c.files[candidate]; ok {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// convertReaction returns the name of the emoji of a reaction, warning once about the emojis that can't be converted.
+func (c *converter) convertReaction(emoji dceEmoji) (string, bool) {
+	// The custom emojis have an id.
+	if emoji.Id == "" {
+		if name, ok := importconvert.EmojiName(emoji.Name); ok {
+			return name, true
+		}
+	}
+	if !c.unknownEmojis[emoji.Name] {
+		c.unknownEmojis[emoji.Name] = true
+		c.report.Warn("The reactions with the emoji %q are not supported and were skipped", emoji.Name)
+	}
+	return "", false
+}
@@ -0,0 +1,271 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package discordimport
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/app/imports"
+	"github.com/mattermost/mattermost/server/v8/platform/services/importconvert"
+)
+
+func createExport(t *testing.T, files map[string]string) *zip.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	return r
+}
+
+func readImport(t *testing.T, data []byte) ([]imports.LineImportData, map[string]string) {
+	t.Helper()
+
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	var lines []imports.LineImportData
+	files := make(map[string]string)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		if f.Name == importconvert.JSONLFilename {
+			scanner := bufio.NewScanner(rc)
+			for scanner.Scan() {
+				var line imports.LineImportData
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+				lines = append(lines, line)
+			}
+			require.NoError(t, scanner.Err())
+		} else {
+			content, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			files[f.Name] = string(content)
+		}
+		require.NoError(t, rc.Close())
+	}
+	return lines, files
+}
+
+const (
+	testAlice = `{"id": "1", "name": "Alice", "nickname": "Alice", "isBot": false}`
+	testBob   = `{"id": "2", "name": "bob", "nickname": "Bobby", "isBot": false}`
+	testBot   = `{"id": "3", "name": "Beep Bot", "nickname": "Beep Bot", "isBot": true}`
+	testGuild = `{"id": "100", "name": "My Server"}`
+)
+
+func TestConvert(t *testing.T) {
+	export := createExport(t, map[string]string{
+		"export/My Server - general.json": `{"guild": ` + testGuild + `,
+			"channel": {"id": "200", "type": "GuildTextChat", "categoryId": "150", "category": "Text", "name": "general", "topic": "Say hi"},
+			"messages": [
+				{"id": "301", "type": "Default", "timestamp": "2024-01-01T10:00:00+00:00", "timestampEdited": "2024-01-01T10:30:00+00:00",
+				 "isPinned": true, "content": "Hi @Bobby, see #off-topic <#201> <:party:999> <t:1704103200:R>", "author": ` + testAlice + `,
+				 "attachments": [{"id": "401", "url": "My%20Server%20-%20general.json_Files/pic-A1B2.png", "fileName": "pic.png"}],
+				 "reactions": [{"emoji": {"id": "", "name": "👍"}, "count": 2, "users": [` + testBob + `, ` + testBob + `]},
+				               {"emoji": {"id": "999", "name": "party"}, "count": 1, "users": [` + testBob + `]}],
+				 "mentions": [` + testBob + `]},
+				{"id": "302", "type": "Reply", "timestamp": "2024-01-01T10:01:00+00:00", "content": "ok", "author": ` + testBob + `,
+				 "reference": {"messageId": "301", "channelId": "200"}},
+				{"id": "303", "type": "GuildMemberJoin", "timestamp": "2024-01-01T09:00:00+00:00", "content": "Joined the server.", "author": ` + testBob + `}
+			]}`,
+		"export/My Server - general.json_Files/pic-A1B2.png": "png content",
+		"export/My Server - general [part 2].json": `{"guild": ` + testGuild + `,
+			"channel": {"id": "200", "type": "GuildTextChat", "name": "general"},
+			"messages": [
+				{"id": "304", "type": "Default", "timestamp": "2024-01-01T11:00:00+00:00", "content": "@everyone beep", "author": ` + testBot + `,
+				 "attachments": [{"id": "402", "url": "https://cdn.discordapp.com/attachments/402/log.txt", "fileName": "log.txt"}]}
+			]}`,
+		"export/My Server - off-topic.json": `{"guild": ` + testGuild + `,
+			"channel": {"id": "201", "type": "GuildTextChat", "name": "off-topic", "topic": null}, "messages": []}`,
+		"export/threads/My Server - idea.json": `{"guild": ` + testGuild + `,
+			"channel": {"id": "301", "type": "GuildPublicThread", "categoryId": "200", "category": "general", "name": "idea"},
+			"messages": [
+				{"id": "501", "type": "Default", "timestamp": "2024-01-01T10:02:00+00:00", "content": "in the thread", "author": ` + testBob + `}
+			]}`,
+		"export/Direct Messages - bob.json": `{"guild": {"id": "0", "name": "Direct Messages"},
+			"channel": {"id": "600", "type": "DirectTextChat", "name": "bob"},
+			"messages": [
+				{"id": "601", "type": "Default", "timestamp": "2024-01-02T10:00:00+00:00", "content": "hey @bob", "author": ` + testAlice + `,
+				 "mentions": [` + testBob + `]}
+			]}`,
+		"export/settings.json": `{"theme": "dark"}`,
+	})
+
+	var buf bytes.Buffer
+	report, err := Convert(export, &buf, Options{EmailDomain: "discord.example.com"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.Teams)
+	assert.Equal(t, 2, report.Channels)
+	assert.Equal(t, 3, report.Users)
+	assert.Equal(t, 2, report.Posts)
+	assert.Equal(t, 2, report.Replies)
+	assert.Equal(t, 1, report.DirectChannels)
+	assert.Equal(t, 1, report.DirectPosts)
+	assert.Equal(t, 1, report.Attachments)
+	assert.Equal(t, 1, report.SkippedMessages)
+	assert.Len(t, report.Warnings, 2)
+
+	lines, files := readImport(t, buf.Bytes())
+	assert.Equal(t, map[string]string{"data/attachments/401/pic.png": "png content"}, files)
+
+	types := make([]string, 0, len(lines))
+	for _, line := range lines {
+		types = append(types, line.Type)
+	}
+	assert.Equal(t, []string{"version", "team", "channel", "channel", "user", "user", "user", "post", "post", "direct_channel", "direct_post"}, types)
+
+	assert.Equal(t, "my-server", *lines[1].Team.Name)
+	assert.Equal(t, "My Server", *lines[1].Team.DisplayName)
+	assert.Equal(t, model.DefaultChannelName, *lines[2].Channel.Name)
+	assert.Equal(t, "Say hi", *lines[2].Channel.Header)
+	assert.Equal(t, "off-topic", *lines[3].Channel.Name)
+
+	alice := lines[4].User
+	assert.Equal(t, "alice", *alice.Username)
+	assert.Equal(t, "alice@discord.example.com", *alice.Email)
+	assert.Nil(t, alice.Nickname)
+	require.NotNil(t, alice.Teams)
+	require.Len(t, *(*alice.Teams)[0].Channels, 1)
+
+	bot := lines[5].User
+	assert.Equal(t, "beep-bot", *bot.Username)
+	assert.NotNil(t, bot.DeleteAt)
+
+	bob := lines[6].User
+	assert.Equal(t, "bob", *bob.Username)
+	assert.Equal(t, "Bobby", *bob.Nickname)
+
+	post := lines[7].Post
+	assert.Equal(t, "my-server", *post.Team)
+	assert.Equal(t, model.DefaultChannelName, *post.Channel)
+	assert.Equal(t, "alice", *post.User)
+	assert.Equal(t, "Hi @bob, see #off-topic ~off-topic :party: 2024-01-01 10:00 UTC", *post.Message)
+	assert.Equal(t, int64(1704103200000), *post.CreateAt)
+	assert.Equal(t, int64(1704105000000), *post.EditAt)
+	assert.True(t, *post.IsPinned)
+	require.NotNil(t, post.Attachments)
+	assert.Equal(t, "attachments/401/pic.png", *(*post.Attachments)[0].Path)
+	require.NotNil(t, post.Reactions)
+	require.Len(t, *post.Reactions, 1)
+	assert.Equal(t, "+1", *(*post.Reactions)[0].EmojiName)
+	assert.Equal(t, "bob", *(*post.Reactions)[0].User)
+	require.NotNil(t, post.Replies)
+	require.Len(t, *post.Replies, 2)
+	assert.Equal(t, "ok", *(*post.Replies)[0].Message)
+	assert.Equal(t, "in the thread", *(*post.Replies)[1].Message)
+
+	botPost := lines[8].Post
+	assert.Equal(t, "beep-bot", *botPost.User)
+	assert.Equal(t, "@all beep\n[log.txt](https://cdn.discordapp.com/attachments/402/log.txt)", *botPost.Message)
+
+	assert.Equal(t, []string{"alice", "bob"}, *lines[9].DirectChannel.Members)
+	directPost := lines[10].DirectPost
+	assert.Equal(t, []string{"alice", "bob"}, *directPost.ChannelMembers)
+	assert.Equal(t, "hey @bob", *directPost.Message)
+
+	for _, line := range lines {
+		var appErr *model.AppError
+		switch line.Type {
+		case "team":
+			appErr = imports.ValidateTeamImportData(line.Team)
+		case "channel":
+			appErr = imports.ValidateChannelImportData(line.Channel)
+		case "user":
+			appErr = imports.ValidateUserImportData(line.User)
+		case "post":
+			appErr = imports.ValidatePostImportData(line.Post, model.PostMessageMaxRunesV2)
+		case "direct_channel":
+			appErr = imports.ValidateDirectChannelImportData(line.DirectChannel)
+		case "direct_post":
+			appErr = imports.ValidateDirectPostImportData(line.DirectPost, model.PostMessageMaxRunesV2)
+		}
+		require.Nil(t, appErr, line.Type)
+	}
+}
+
+func TestConvertThread(t *testing.T) {
+	export := createExport(t, map[string]string{
+		"general.json": `{"guild": ` + testGuild + `,
+			"channel": {"id": "200", "type": "GuildTextChat", "name": "general"},
+			"messages": [
+				{"id": "301", "type": "Default", "timestamp": "2024-01-01T10:00:00+00:00", "content": "first", "author": ` + testAlice + `}
+			]}`,
+		"thread.json": `{"guild": ` + testGuild + `,
+			"channel": {"id": "700", "type": "GuildPublicThread", "categoryId": "200", "name": "Planning"},
+			"messages": [
+				{"id": "701", "type": "Default", "timestamp": "2024-01-01T09:00:00+00:00", "content": "let's plan", "author": ` + testBob + `},
+				{"id": "702", "type": "Default", "timestamp": "2024-01-01T09:05:00+00:00", "content": "sure", "author": ` + testAlice + `}
+			]}`,
+		"orphan.json": `{"guild": ` + testGuild + `,
+			"channel": {"id": "800", "type": "GuildPrivateThread", "categoryId": "999", "name": "Lost"},
+			"messages": [
+				{"id": "801", "type": "Default", "timestamp": "2024-01-01T09:00:00+00:00", "content": "anyone?", "author": ` + testBob + `}
+			]}`,
+	})
+
+	var buf bytes.Buffer
+	report, err := Convert(export, &buf, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Channels)
+	assert.Equal(t, 3, report.Posts)
+	assert.Equal(t, 1, report.Replies)
+	assert.Len(t, report.Warnings, 1)
+
+	lines, _ := readImport(t, buf.Bytes())
+	var posts []*imports.PostImportData
+	for _, line := range lines {
+		if line.Type == "post" {
+			posts = append(posts, line.Post)
+		}
+	}
+	require.Len(t, posts, 3)
+
+	// The thread wasn't started from a message, its first message is the root.
+	assert.Equal(t, "**Planning**\nlet's plan", *posts[0].Message)
+	require.NotNil(t, posts[0].Replies)
+	assert.Equal(t, "sure", *(*posts[0].Replies)[0].Message)
+	assert.Equal(t, "first", *posts[1].Message)
+	assert.Nil(t, posts[1].Replies)
+
+	// The thread whose channel is not in the export is a channel.
+	assert.Equal(t, "lost", *posts[2].Channel)
+	assert.Equal(t, "anyone?", *posts[2].Message)
+}
+
+func TestConvertInvalidExport(t *testing.T) {
+	t.Run("no export", func(t *testing.T) {
+		_, err := Convert(createExport(t, map[string]string{"readme.txt": "hi"}), io.Discard, Options{})
+		require.Error(t, err)
+	})
+
+	t.Run("invalid file name", func(t *testing.T) {
+		_, err := Convert(createExport(t, map[string]string{"../general.json": "{}"}), io.Discard, Options{})
+		require.Error(t, err)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := Convert(createExport(t, map[string]string{"general.json": "{"}), io.Discard, Options{})
+		require.Error(t, err)
+	})
+}
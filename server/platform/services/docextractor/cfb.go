@@ -0,0 +1,236 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"unicode/utf16"
+)
+
+// This file implements a reader for the Compound File Binary format
+// ([MS-CFB]) used by Outlook .msg files. The whole file is kept in memory.
+
+const (
+	cfbHeaderSize     = 512
+	cfbDirEntrySize   = 128
+	cfbEndOfChain     = 0xFFFFFFFE
+	cfbFreeSect       = 0xFFFFFFFF
+	cfbNoStream       = 0xFFFFFFFF
+	cfbTypeStorage    = 1
+	cfbTypeStream     = 2
+	cfbTypeRoot       = 5
+	cfbHeaderDIFATLen = 109
+)
+
+var cfbSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+var errCFBCorrupted = errors.New("corrupted compound file")
+
+type cfbEntry struct {
+	Name        string
+	Type        byte
+	left        uint32
+	right       uint32
+	child       uint32
+	startSector uint32
+	Size        uint64
+}
+
+type cfbFile struct {
+	data            []byte
+	sectorSize      int
+	miniSectorSize  int
+	miniCutoff      uint64
+	fat             []uint32
+	miniFAT         []uint32
+	entries         []cfbEntry
+	miniStream      []byte
+	maxChainLength  int
+	maxSectorNumber uint32
+}
+
+func openCFB(data []byte) (*cfbFile, error) {
+	if len(data) < cfbHeaderSize || !bytes.Equal(data[:8], cfbSignature) {
+		return nil, errors.New("not a compound file")
+	}
+
+	le := binary.LittleEndian
+	sectorShift := le.Uint16(data[0x1E:])
+	miniSectorShift := le.Uint16(data[0x20:])
+	if (sectorShift != 9 && sectorShift != 12) || miniSectorShift != 6 {
+		return nil, errCFBCorrupted
+	}
+
+	f := &cfbFile{
+		data:           data,
+		sectorSize:     1 << sectorShift,
+		miniSectorSize: 1 << miniSectorShift,
+		miniCutoff:     uint64(le.Uint32(data[0x38:])),
+	}
+	f.maxSectorNumber = uint32((len(data) - 1) / f.sectorSize)
+	f.maxChainLength = len(data)/f.sectorSize + 1
+
+	numFATSectors := le.Uint32(data[0x2C:])
+	firstDirSector := le.Uint32(data[0x30:])
+	firstMiniFATSector := le.Uint32(data[0x3C:])
+	firstDIFATSector := le.Uint32(data[0x44:])
+
+	// The first 109 FAT sectors are listed in the header, the others in
+	// the DIFAT chain.
+	var fatSectors []uint32
+	for i := 0; i < cfbHeaderDIFATLen && uint32(len(fatSectors)) < numFATSectors; i++ {
+		fatSectors = append(fatSectors, le.Uint32(data[0x4C+4*i:]))
+	}
+	perSector := f.sectorSize/4 - 1
+	for sector, visited := firstDIFATSector, 0; sector != cfbEndOfChain && sector != cfbFreeSect && uint32(len(fatSectors)) < numFATSectors; visited++ {
+		block, err := f.sector(sector)
+		if err != nil || visited > f.maxChainLength {
+			return nil, errCFBCorrupted
+		}
+		for i := 0; i < perSector && uint32(len(fatSectors)) < numFATSectors; i++ {
+			fatSectors = append(fatSectors, le.Uint32(block[4*i:]))
+		}
+		sector = le.Uint32(block[4*perSector:])
+	}
+
+	for _, sector := range fatSectors {
+		block, err := f.sector(sector)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < f.sectorSize; i += 4 {
+			f.fat = append(f.fat, le.Uint32(block[i:]))
+		}
+	}
+
+	dir, err := f.chain(firstDirSector, f.fat, f.sectorSize, f.sector, 0)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i+cfbDirEntrySize <= len(dir); i += cfbDirEntrySize {
+		raw := dir[i : i+cfbDirEntrySize]
+		nameLength := int(le.Uint16(raw[0x40:]))
+		if nameLength > 64 {
+			return nil, errCFBCorrupted
+		}
+		name := make([]uint16, 0, 32)
+		for j := 0; j+1 < nameLength-2; j += 2 {
+			name = append(name, le.Uint16(raw[j:]))
+		}
+		f.entries = append(f.entries, cfbEntry{
+			Name:        string(utf16.Decode(name)),
+			Type:        raw[0x42],
+			left:        le.Uint32(raw[0x44:]),
+			right:       le.Uint32(raw[0x48:]),
+			child:       le.Uint32(raw[0x4C:]),
+			startSector: le.Uint32(raw[0x74:]),
+			Size:        le.Uint64(raw[0x78:]) & 0xFFFFFFFF,
+		})
+	}
+	if len(f.entries) == 0 || f.entries[0].Type != cfbTypeRoot {
+		return nil, errCFBCorrupted
+	}
+
+	if firstMiniFATSector != cfbEndOfChain {
+		miniFAT, err := f.chain(firstMiniFATSector, f.fat, f.sectorSize, f.sector, 0)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i+4 <= len(miniFAT); i += 4 {
+			f.miniFAT = append(f.miniFAT, le.Uint32(miniFAT[i:]))
+		}
+	}
+
+	root := f.entries[0]
+	if root.startSector != cfbEndOfChain {
+		f.miniStream, err = f.chain(root.startSector, f.fat, f.sectorSize, f.sector, root.Size)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+func (f *cfbFile) sector(n uint32) ([]byte, error) {
+	if n >= f.maxSectorNumber {
+		return nil, errCFBCorrupted
+	}
+	offset := (int(n) + 1) * f.sectorSize
+	return f.data[offset : offset+f.sectorSize], nil
+}
+
+func (f *cfbFile) miniSector(n uint32) ([]byte, error) {
+	offset := int(n) * f.miniSectorSize
+	if offset+f.miniSectorSize > len(f.miniStream) {
+		return nil, errCFBCorrupted
+	}
+	return f.miniStream[offset : offset+f.miniSectorSize], nil
+}
+
+// chain reads the sectors chained in the allocation table from start. The
+// result is truncated to size when it is not zero.
+func (f *cfbFile) chain(start uint32, table []uint32, sectorSize int, read func(uint32) ([]byte, error), size uint64) ([]byte, error) {
+	var out []byte
+	for sector, visited := start, 0; sector != cfbEndOfChain; visited++ {
+		if visited > f.maxChainLength || int(sector) >= len(table) {
+			return nil, errCFBCorrupted
+		}
+		block, err := read(sector)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, block...)
+		if size > 0 && uint64(len(out)) >= size {
+			break
+		}
+		sector = table[sector]
+	}
+	if size > 0 {
+		if uint64(len(out)) < size {
+			return nil, errCFBCorrupted
+		}
+		out = out[:size]
+	}
+	return out, nil
+}
+
+// stream returns the content of a stream entry.
+func (f *cfbFile) stream(entry cfbEntry) ([]byte, error) {
+	if entry.Type != cfbTypeStream {
+		return nil, errors.New("not a stream")
+	}
+	if entry.Size == 0 {
+		return []byte{}, nil
+	}
+	if entry.Size < f.miniCutoff {
+		return f.chain(entry.startSector, f.miniFAT, f.miniSectorSize, f.miniSector, entry.Size)
+	}
+	return f.chain(entry.startSector, f.fat, f.sectorSize, f.sector, entry.Size)
+}
+
+// children returns the entries of a storage, walking its red-black tree.
+func (f *cfbFile) children(storage cfbEntry) []cfbEntry {
+	var children []cfbEntry
+	visited := map[uint32]bool{}
+	var walk func(id uint32)
+	walk = func(id uint32) {
+		if id == cfbNoStream || int(id) >= len(f.entries) || visited[id] {
+			return
+		}
+		visited[id] = true
+		entry := f.entries[id]
+		walk(entry.left)
+		children = append(children, entry)
+		walk(entry.right)
+	}
+	walk(storage.child)
+	return children
+}
+
+func (f *cfbFile) root() cfbEntry {
+	return f.entries[0]
+}
@@ -5,6 +5,7 @@ package docextractor
 
 import (
 	"io"
+	"time"
 
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
@@ -14,6 +15,27 @@ type ExtractSettings struct {
 	ArchiveRecursion bool
 	MMPreviewURL     string
 	MMPreviewSecret  string
+	// The Office Open XML, OpenDocument, RTF and email extractors are
+	// enabled unless disabled here.
+	DisableOOXML bool
+	DisableODF   bool
+	DisableRTF   bool
+	DisableEmail bool
+	// MaxExtractSize and ExtractTimeout bound the bytes read and the time
+	// spent by these extractors on a single document. The defaults are
+	// used when they are zero.
+	MaxExtractSize int64
+	ExtractTimeout time.Duration
+	// EnableOCR extracts the text of images with the OCRCommand tesseract
+	// executable or, when OCRURL is set, with an OCR service. Images larger
+	// than OCRMaxImageSize are skipped. The defaults are used for the zero
+	// values.
+	EnableOCR       bool
+	OCRCommand      string
+	OCRURL          string
+	OCRLanguage     string
+	OCRMaxImageSize int64
+	OCRTimeout      time.Duration
 }
 
 // Extract extract the text from a document using the system default extractors
@@ -29,6 +51,27 @@ func ExtractWithExtraExtractors(logger mlog.LoggerIFace, filename string, r io.R
 	for _, extraExtractor := range extraExtractors {
 		enabledExtractors.Add(extraExtractor)
 	}
+
+	// These come before the documentExtractor, which remains as a fallback
+	// for the formats they share.
+	limits := newExtractLimits(settings)
+	if !settings.DisableOOXML {
+		enabledExtractors.Add(&ooxmlExtractor{limits: limits})
+	}
+	if !settings.DisableODF {
+		enabledExtractors.Add(&odfExtractor{limits: limits})
+	}
+	if !settings.DisableRTF {
+		enabledExtractors.Add(&rtfExtractor{limits: limits})
+	}
+	if !settings.DisableEmail {
+		enabledExtractors.Add(&emailExtractor{limits: limits, SubExtractor: enabledExtractors})
+	}
+
+	if settings.EnableOCR {
+		enabledExtractors.Add(newOCRExtractor(settings))
+	}
+
 	enabledExtractors.Add(&documentExtractor{})
 	enabledExtractors.Add(&pdfExtractor{})
 
@@ -124,6 +124,30 @@ func TestExtract(t *testing.T) {
 			[]string{},
 			false,
 		},
+		{
+			"Pptx file with the fallback extractor",
+			"sample-doc.pptx",
+			ExtractSettings{DisableOOXML: true},
+			[]string{"simple", "document", "contains"},
+			[]string{},
+			false,
+		},
+		{
+			"Odp file",
+			"sample-doc.odp",
+			ExtractSettings{},
+			[]string{"Title", "simple", "document", "contains"},
+			[]string{},
+			false,
+		},
+		{
+			"Rtf file",
+			"sample-doc.rtf",
+			ExtractSettings{},
+			[]string{"simple", "document", "contains"},
+			[]string{"rtf1", "fonttbl"},
+			false,
+		},
 	}
 
 	for _, tc := range testCases {
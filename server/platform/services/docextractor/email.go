@@ -0,0 +1,266 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"path"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// emailExtractor extracts the headers, body and attachments of email
+// messages, either RFC 5322 .eml files or Outlook .msg files. Attachments
+// are extracted with SubExtractor when it is set.
+type emailExtractor struct {
+	limits       extractLimits
+	SubExtractor Extractor
+}
+
+// emailMaxDepth bounds the nesting of multipart bodies and attached
+// messages.
+const emailMaxDepth = 10
+
+var emailHeaders = []string{"From", "To", "Cc", "Subject"}
+
+func (ee *emailExtractor) Name() string {
+	return "emailExtractor"
+}
+
+func (ee *emailExtractor) Match(filename string) bool {
+	extension := strings.ToLower(path.Ext(filename))
+	return extension == ".eml" || extension == ".msg"
+}
+
+func (ee *emailExtractor) Extract(filename string, r io.ReadSeeker) (out string, outErr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			out = ""
+			outErr = errors.New("error extracting email text")
+		}
+	}()
+
+	b := ee.limits.start()
+	data, err := b.readAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	if strings.ToLower(path.Ext(filename)) == ".msg" {
+		err = ee.writeMSG(&text, data, b, 0)
+	} else {
+		err = ee.writeEML(&text, data, b, 0)
+	}
+	if err != nil {
+		return "", err
+	}
+	return text.String(), nil
+}
+
+func (ee *emailExtractor) writeEML(text *strings.Builder, data []byte, b *budget, depth int) error {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	decoder := mime.WordDecoder{CharsetReader: charset.NewReaderLabel}
+	for _, name := range emailHeaders {
+		value := msg.Header.Get(name)
+		if value == "" {
+			continue
+		}
+		if decoded, err := decoder.DecodeHeader(value); err == nil {
+			value = decoded
+		}
+		text.WriteString(name + ": " + value + "\n")
+	}
+	text.WriteString("\n")
+
+	return ee.writeMIMEPart(text, msg.Header, msg.Body, b, depth)
+}
+
+func (ee *emailExtractor) writeMIMEPart(text *strings.Builder, header map[string][]string, body io.Reader, b *budget, depth int) error {
+	if err := b.check(); err != nil {
+		return err
+	}
+	if depth > emailMaxDepth {
+		return nil
+	}
+
+	get := func(key string) string {
+		if values := header[key]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	mediaType, params, err := mime.ParseMediaType(get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	switch strings.ToLower(get("Content-Transfer-Encoding")) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(get("Content-Disposition"))
+	filename := dispositionParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	if disposition == "attachment" || (filename != "" && !strings.HasPrefix(mediaType, "multipart/")) {
+		return ee.writeAttachment(text, filename, body, b, depth)
+	}
+
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		return ee.writeMultipart(text, mediaType, params["boundary"], body, b, depth)
+	case mediaType == "message/rfc822":
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		return ee.writeEML(text, data, b, depth+1)
+	case mediaType == "text/plain" || mediaType == "text/html":
+		content, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		if cs := params["charset"]; cs != "" {
+			if decodedReader, err := charset.NewReaderLabel(cs, bytes.NewReader(content)); err == nil {
+				if decoded, err := io.ReadAll(decodedReader); err == nil {
+					content = decoded
+				}
+			}
+		}
+		if mediaType == "text/html" {
+			text.WriteString(htmlToText(content))
+		} else {
+			text.Write(content)
+		}
+		text.WriteString("\n")
+	}
+	return nil
+}
+
+func (ee *emailExtractor) writeMultipart(text *strings.Builder, mediaType, boundary string, body io.Reader, b *budget, depth int) error {
+	if boundary == "" {
+		return errors.New("missing multipart boundary")
+	}
+
+	reader := multipart.NewReader(body, boundary)
+	type alternative struct {
+		header map[string][]string
+		data   []byte
+	}
+	var alternatives []alternative
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		// Keep a single version of the body, preferring plain text.
+		if mediaType == "multipart/alternative" {
+			data, err := io.ReadAll(part)
+			if err != nil {
+				return err
+			}
+			alternatives = append(alternatives, alternative{header: part.Header, data: data})
+			continue
+		}
+
+		if err := ee.writeMIMEPart(text, part.Header, part, b, depth+1); err != nil {
+			return err
+		}
+	}
+
+	if len(alternatives) == 0 {
+		return nil
+	}
+	chosen := alternatives[len(alternatives)-1]
+	for _, alt := range alternatives {
+		if mt, _, _ := mime.ParseMediaType(strings.Join(alt.header["Content-Type"], "")); mt == "text/plain" {
+			chosen = alt
+			break
+		}
+	}
+	return ee.writeMIMEPart(text, chosen.header, bytes.NewReader(chosen.data), b, depth+1)
+}
+
+func (ee *emailExtractor) writeAttachment(text *strings.Builder, filename string, body io.Reader, b *budget, depth int) error {
+	if filename != "" {
+		text.WriteString(filename + "\n")
+	}
+	if ee.SubExtractor == nil {
+		return nil
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	// Attached messages are extracted here to bound their nesting.
+	if ext := strings.ToLower(path.Ext(filename)); ext == ".eml" {
+		return ee.writeEML(text, data, b, depth+1)
+	} else if ext == ".msg" {
+		return ee.writeMSG(text, data, b, depth+1)
+	}
+
+	subtext, err := ee.SubExtractor.Extract(filename, bytes.NewReader(data))
+	if err == nil && subtext != "" {
+		text.WriteString(subtext + "\n")
+	}
+	return nil
+}
+
+// htmlToText returns the text content of an HTML document.
+func htmlToText(content []byte) string {
+	var text strings.Builder
+	tokenizer := html.NewTokenizer(bytes.NewReader(content))
+	skip := 0
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return text.String()
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "script", "style", "head":
+				skip++
+			case "br", "p", "div", "tr", "li":
+				text.WriteString("\n")
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "script", "style", "head":
+				if skip > 0 {
+					skip--
+				}
+			}
+		case html.TextToken:
+			if skip == 0 {
+				text.Write(tokenizer.Text())
+			}
+		}
+	}
+}
@@ -0,0 +1,319 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSubExtractor struct{}
+
+func (testSubExtractor) Name() string { return "testSubExtractor" }
+
+func (testSubExtractor) Match(filename string) bool { return strings.HasSuffix(filename, ".txt") }
+
+func (testSubExtractor) Extract(filename string, r io.ReadSeeker) (string, error) {
+	data, err := io.ReadAll(r)
+	return "extracted " + string(data), err
+}
+
+const testEML = "From: =?UTF-8?Q?Ren=C3=A9e?= <renee@example.com>\r\n" +
+	"To: team@example.com\r\n" +
+	"Subject: =?ISO-8859-1?Q?R=E9union?= planning\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=\"outer\"\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=\"inner\"\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<html><body><p>HTML version</p></body></html>\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=iso-8859-1\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"Let's meet on the caf=E9 terrace.\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: text/plain; name=\"agenda.txt\"\r\n" +
+	"Content-Disposition: attachment; filename=\"agenda.txt\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"QWdlbmRhIGl0ZW1z\r\n" +
+	"--outer\r\n" +
+	"Content-Type: message/rfc822\r\n" +
+	"\r\n" +
+	"From: boss@example.com\r\n" +
+	"Subject: Forwarded budget\r\n" +
+	"\r\n" +
+	"Budget is approved.\r\n" +
+	"--outer--\r\n"
+
+func TestEmailExtractor(t *testing.T) {
+	extractor := &emailExtractor{limits: newExtractLimits(ExtractSettings{}), SubExtractor: testSubExtractor{}}
+
+	t.Run("match", func(t *testing.T) {
+		assert.True(t, extractor.Match("message.eml"))
+		assert.True(t, extractor.Match("message.MSG"))
+		assert.False(t, extractor.Match("message.txt"))
+	})
+
+	t.Run("eml", func(t *testing.T) {
+		text, err := extractor.Extract("message.eml", strings.NewReader(testEML))
+		require.NoError(t, err)
+		assert.Contains(t, text, "From: Renée <renee@example.com>\n")
+		assert.Contains(t, text, "To: team@example.com\n")
+		assert.Contains(t, text, "Subject: Réunion planning\n")
+		assert.Contains(t, text, "Let's meet on the café terrace.")
+		assert.NotContains(t, text, "HTML version")
+		assert.Contains(t, text, "agenda.txt\nextracted Agenda items")
+		assert.Contains(t, text, "Subject: Forwarded budget")
+		assert.Contains(t, text, "Budget is approved.")
+	})
+
+	t.Run("eml attachments without sub extractor", func(t *testing.T) {
+		plain := &emailExtractor{limits: newExtractLimits(ExtractSettings{})}
+		text, err := plain.Extract("message.eml", strings.NewReader(testEML))
+		require.NoError(t, err)
+		assert.Contains(t, text, "agenda.txt")
+		assert.NotContains(t, text, "Agenda items")
+	})
+
+	t.Run("html only eml", func(t *testing.T) {
+		eml := "Subject: Newsletter\r\nContent-Type: text/html\r\n\r\n<html><head><style>p {}</style></head><body><p>Read <b>this</b></p><script>alert(1)</script></body></html>"
+		text, err := extractor.Extract("news.eml", strings.NewReader(eml))
+		require.NoError(t, err)
+		assert.Contains(t, text, "Read this")
+		assert.NotContains(t, text, "alert")
+		assert.NotContains(t, text, "p {}")
+	})
+
+	t.Run("msg", func(t *testing.T) {
+		data := createTestCFB([]testCFBNode{
+			testMSGString("0037", "Quarterly review"),
+			testMSGString("0C1A", "Alice"),
+			testMSGString("0E04", "Bob; Carol"),
+			testMSGString("1000", "Please find the numbers attached."),
+			{name: "__attach_version1.0_#00000000", children: []testCFBNode{
+				testMSGString("3707", "numbers.txt"),
+				{name: "__substg1.0_37010102", data: []byte("42 and 17")},
+			}},
+		})
+
+		text, err := extractor.Extract("review.msg", bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Contains(t, text, "From: Alice\n")
+		assert.Contains(t, text, "To: Bob; Carol\n")
+		assert.Contains(t, text, "Subject: Quarterly review\n")
+		assert.Contains(t, text, "Please find the numbers attached.")
+		assert.Contains(t, text, "numbers.txt\nextracted 42 and 17")
+	})
+
+	t.Run("msg with html body", func(t *testing.T) {
+		data := createTestCFB([]testCFBNode{
+			{name: "__substg1.0_0037001E", data: []byte("Legacy subject\x00")},
+			{name: "__substg1.0_10130102", data: []byte("<p>Rich body</p>")},
+		})
+
+		text, err := extractor.Extract("review.msg", bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Contains(t, text, "Subject: Legacy subject\n")
+		assert.Contains(t, text, "Rich body")
+	})
+
+	t.Run("invalid msg", func(t *testing.T) {
+		_, err := extractor.Extract("review.msg", strings.NewReader("not a compound file"))
+		require.Error(t, err)
+
+		data := createTestCFB([]testCFBNode{testMSGString("0037", "Subject")})
+		_, err = extractor.Extract("review.msg", bytes.NewReader(data[:len(data)-600]))
+		require.Error(t, err)
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		limited := &emailExtractor{limits: newExtractLimits(ExtractSettings{MaxExtractSize: 100})}
+		_, err := limited.Extract("message.eml", strings.NewReader(testEML))
+		require.ErrorIs(t, err, errExtractTooLarge)
+	})
+}
+
+type testCFBNode struct {
+	name     string
+	data     []byte
+	children []testCFBNode
+}
+
+func testMSGString(id, value string) testCFBNode {
+	var data []byte
+	for _, unit := range utf16.Encode([]rune(value)) {
+		data = binary.LittleEndian.AppendUint16(data, unit)
+	}
+	return testCFBNode{name: "__substg1.0_" + id + "001F", data: data}
+}
+
+// createTestCFB builds a compound file with 512 bytes sectors holding the
+// given nodes in its root storage. Every stream is stored in the mini stream,
+// so it must be smaller than 4096 bytes.
+func createTestCFB(nodes []testCFBNode) []byte {
+	const sectorSize = 512
+	le := binary.LittleEndian
+
+	type dirEntry struct {
+		name        string
+		entryType   byte
+		right       uint32
+		child       uint32
+		startSector uint32
+		size        uint64
+	}
+
+	var entries []dirEntry
+	var miniStream []byte
+	var miniFAT []uint32
+
+	var add func(nodes []testCFBNode) uint32
+	add = func(nodes []testCFBNode) uint32 {
+		first := uint32(cfbNoStream)
+		var previous int = -1
+		for _, node := range nodes {
+			id := len(entries)
+			entries = append(entries, dirEntry{name: node.name, right: cfbNoStream, child: cfbNoStream, startSector: cfbEndOfChain})
+			if node.children != nil {
+				entries[id].entryType = cfbTypeStorage
+				entries[id].child = add(node.children)
+			} else {
+				entries[id].entryType = cfbTypeStream
+				entries[id].size = uint64(len(node.data))
+				if len(node.data) > 0 {
+					start := len(miniStream) / 64
+					entries[id].startSector = uint32(start)
+					miniStream = append(miniStream, node.data...)
+					for len(miniStream)%64 != 0 {
+						miniStream = append(miniStream, 0)
+					}
+					for i := start; i < len(miniStream)/64; i++ {
+						miniFAT = append(miniFAT, uint32(i+1))
+					}
+					miniFAT[len(miniFAT)-1] = cfbEndOfChain
+				}
+			}
+			if previous >= 0 {
+				entries[previous].right = uint32(id)
+			} else {
+				first = uint32(id)
+			}
+			previous = id
+		}
+		return first
+	}
+
+	entries = append(entries, dirEntry{name: "Root Entry", entryType: cfbTypeRoot, right: cfbNoStream, child: cfbNoStream})
+	entries[0].child = add(nodes)
+	entries[0].size = uint64(len(miniStream))
+
+	sectors := func(n int) int { return (n + sectorSize - 1) / sectorSize }
+	dirSectors := sectors(len(entries) * cfbDirEntrySize)
+	miniFATSectors := sectors(len(miniFAT) * 4)
+	miniStreamSectors := sectors(len(miniStream))
+
+	// Sector 0 holds the FAT, followed by the directory, the mini FAT and
+	// the mini stream.
+	fat := []uint32{0xFFFFFFFD}
+	chain := func(count int) uint32 {
+		if count == 0 {
+			return cfbEndOfChain
+		}
+		start := len(fat)
+		for i := 1; i < count; i++ {
+			fat = append(fat, uint32(start+i))
+		}
+		fat = append(fat, cfbEndOfChain)
+		return uint32(start)
+	}
+	dirStart := chain(dirSectors)
+	miniFATStart := chain(miniFATSectors)
+	miniStreamStart := chain(miniStreamSectors)
+	if miniStreamSectors > 0 {
+		entries[0].startSector = miniStreamStart
+	}
+
+	out := make([]byte, sectorSize*(1+len(fat)))
+	header := out[:sectorSize]
+	copy(header, cfbSignature)
+	le.PutUint16(header[0x18:], 0x3E)
+	le.PutUint16(header[0x1A:], 3)
+	le.PutUint16(header[0x1C:], 0xFFFE)
+	le.PutUint16(header[0x1E:], 9)
+	le.PutUint16(header[0x20:], 6)
+	le.PutUint32(header[0x2C:], 1)
+	le.PutUint32(header[0x30:], dirStart)
+	le.PutUint32(header[0x38:], 4096)
+	le.PutUint32(header[0x3C:], miniFATStart)
+	le.PutUint32(header[0x40:], uint32(miniFATSectors))
+	le.PutUint32(header[0x44:], cfbEndOfChain)
+	for i := 0; i < cfbHeaderDIFATLen; i++ {
+		le.PutUint32(header[0x4C+4*i:], cfbFreeSect)
+	}
+	le.PutUint32(header[0x4C:], 0)
+
+	sector := func(n uint32) []byte {
+		offset := (int(n) + 1) * sectorSize
+		return out[offset:]
+	}
+
+	fatSector := sector(0)
+	for i := 0; i < sectorSize/4; i++ {
+		value := uint32(cfbFreeSect)
+		if i < len(fat) {
+			value = fat[i]
+		}
+		le.PutUint32(fatSector[4*i:], value)
+	}
+
+	dir := sector(dirStart)
+	for i := 0; i < dirSectors*sectorSize/cfbDirEntrySize; i++ {
+		raw := dir[i*cfbDirEntrySize : (i+1)*cfbDirEntrySize]
+		le.PutUint32(raw[0x44:], cfbNoStream)
+		le.PutUint32(raw[0x48:], cfbNoStream)
+		le.PutUint32(raw[0x4C:], cfbNoStream)
+		if i >= len(entries) {
+			continue
+		}
+		entry := entries[i]
+		name := utf16.Encode([]rune(entry.name))
+		for j, unit := range name {
+			le.PutUint16(raw[2*j:], unit)
+		}
+		le.PutUint16(raw[0x40:], uint16(2*len(name)+2))
+		raw[0x42] = entry.entryType
+		le.PutUint32(raw[0x48:], entry.right)
+		le.PutUint32(raw[0x4C:], entry.child)
+		le.PutUint32(raw[0x74:], entry.startSector)
+		le.PutUint64(raw[0x78:], entry.size)
+	}
+
+	if miniFATSectors > 0 {
+		table := sector(miniFATStart)
+		for i := 0; i < miniFATSectors*sectorSize/4; i++ {
+			value := uint32(cfbFreeSect)
+			if i < len(miniFAT) {
+				value = miniFAT[i]
+			}
+			le.PutUint32(table[4*i:], value)
+		}
+		copy(sector(miniStreamStart), miniStream)
+	}
+
+	return out
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+const (
+	// DefaultMaxExtractSize is the default limit of uncompressed bytes an
+	// extractor reads from a single document.
+	DefaultMaxExtractSize = 50 * 1024 * 1024
+	// DefaultExtractTimeout is the default time an extractor may spend on a
+	// single document.
+	DefaultExtractTimeout = 30 * time.Second
+)
+
+var (
+	errExtractTooLarge = errors.New("document exceeds the maximum extraction size")
+	errExtractTimeout  = errors.New("document extraction timed out")
+)
+
+// extractLimits guards the extractors that decompress or parse documents
+// themselves against oversized inputs, such as zip bombs, and against
+// documents that take too long to process.
+type extractLimits struct {
+	maxSize int64
+	timeout time.Duration
+}
+
+func newExtractLimits(settings ExtractSettings) extractLimits {
+	limits := extractLimits{
+		maxSize: settings.MaxExtractSize,
+		timeout: settings.ExtractTimeout,
+	}
+	if limits.maxSize <= 0 {
+		limits.maxSize = DefaultMaxExtractSize
+	}
+	if limits.timeout <= 0 {
+		limits.timeout = DefaultExtractTimeout
+	}
+	return limits
+}
+
+// budget tracks the bytes read and the time spent extracting one document.
+type budget struct {
+	remaining int64
+	deadline  time.Time
+}
+
+func (l extractLimits) start() *budget {
+	return &budget{
+		remaining: l.maxSize,
+		deadline:  time.Now().Add(l.timeout),
+	}
+}
+
+// check returns an error once the deadline has passed.
+func (b *budget) check() error {
+	if time.Now().After(b.deadline) {
+		return errExtractTimeout
+	}
+	return nil
+}
+
+// reader wraps r so that reading fails once the budget is exhausted.
+func (b *budget) reader(r io.Reader) io.Reader {
+	return &budgetReader{r: r, budget: b}
+}
+
+// readAll reads r fully within the budget.
+func (b *budget) readAll(r io.Reader) ([]byte, error) {
+	return io.ReadAll(b.reader(r))
+}
+
+type budgetReader struct {
+	r      io.Reader
+	budget *budget
+}
+
+func (br *budgetReader) Read(p []byte) (int, error) {
+	if err := br.budget.check(); err != nil {
+		return 0, err
+	}
+	if br.budget.remaining <= 0 {
+		return 0, errExtractTooLarge
+	}
+	if int64(len(p)) > br.budget.remaining {
+		p = p[:br.budget.remaining]
+	}
+	n, err := br.r.Read(p)
+	br.budget.remaining -= int64(n)
+	return n, err
+}
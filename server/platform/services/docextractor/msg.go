@@ -0,0 +1,116 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"unicode/utf16"
+)
+
+// Outlook .msg files ([MS-OXMSG]) store each MAPI property of the message in
+// a stream named after the property tag.
+const (
+	msgPropertyPrefix   = "__substg1.0_"
+	msgAttachmentPrefix = "__attach_version1.0_"
+
+	msgTypeString8 = "001E"
+	msgTypeUnicode = "001F"
+	msgTypeBinary  = "0102"
+
+	msgPropSubject            = "0037"
+	msgPropSenderName         = "0C1A"
+	msgPropDisplayTo          = "0E04"
+	msgPropDisplayCc          = "0E03"
+	msgPropBody               = "1000"
+	msgPropHTMLBody           = "1013"
+	msgPropAttachData         = "3701"
+	msgPropAttachFilename     = "3704"
+	msgPropAttachLongFilename = "3707"
+)
+
+// msgProperties reads the properties stored as streams in a storage.
+func msgProperties(f *cfbFile, storage cfbEntry) (map[string][]byte, []cfbEntry) {
+	properties := map[string][]byte{}
+	var attachments []cfbEntry
+	for _, entry := range f.children(storage) {
+		switch {
+		case entry.Type == cfbTypeStream && strings.HasPrefix(entry.Name, msgPropertyPrefix):
+			data, err := f.stream(entry)
+			if err == nil {
+				properties[strings.ToUpper(strings.TrimPrefix(entry.Name, msgPropertyPrefix))] = data
+			}
+		case entry.Type == cfbTypeStorage && strings.HasPrefix(entry.Name, msgAttachmentPrefix):
+			attachments = append(attachments, entry)
+		}
+	}
+	return properties, attachments
+}
+
+// msgString returns a string property, stored either as UTF-16 or in the
+// message code page.
+func msgString(properties map[string][]byte, id string) string {
+	if data, ok := properties[id+msgTypeUnicode]; ok {
+		units := make([]uint16, 0, len(data)/2)
+		for i := 0; i+1 < len(data); i += 2 {
+			units = append(units, binary.LittleEndian.Uint16(data[i:]))
+		}
+		return strings.TrimRight(string(utf16.Decode(units)), "\x00")
+	}
+	if data, ok := properties[id+msgTypeString8]; ok {
+		return strings.TrimRight(string(data), "\x00")
+	}
+	return ""
+}
+
+func (ee *emailExtractor) writeMSG(text *strings.Builder, data []byte, b *budget, depth int) error {
+	if depth > emailMaxDepth {
+		return nil
+	}
+	f, err := openCFB(data)
+	if err != nil {
+		return err
+	}
+
+	properties, attachments := msgProperties(f, f.root())
+	for _, header := range []struct {
+		name string
+		id   string
+	}{
+		{"From", msgPropSenderName},
+		{"To", msgPropDisplayTo},
+		{"Cc", msgPropDisplayCc},
+		{"Subject", msgPropSubject},
+	} {
+		if value := msgString(properties, header.id); value != "" {
+			text.WriteString(header.name + ": " + value + "\n")
+		}
+	}
+	text.WriteString("\n")
+
+	if body := msgString(properties, msgPropBody); body != "" {
+		text.WriteString(body + "\n")
+	} else if htmlBody, ok := properties[msgPropHTMLBody+msgTypeBinary]; ok {
+		text.WriteString(htmlToText(htmlBody) + "\n")
+	}
+
+	for _, attachment := range attachments {
+		if err := b.check(); err != nil {
+			return err
+		}
+		attachmentProperties, _ := msgProperties(f, attachment)
+		filename := msgString(attachmentProperties, msgPropAttachLongFilename)
+		if filename == "" {
+			filename = msgString(attachmentProperties, msgPropAttachFilename)
+		}
+		// Embedded messages are stored as a storage rather than as
+		// binary data, and are not extracted.
+		content := attachmentProperties[msgPropAttachData+msgTypeBinary]
+		if err := ee.writeAttachment(text, filename, bytes.NewReader(content), b, depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
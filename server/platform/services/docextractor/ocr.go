@@ -0,0 +1,177 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultOCRCommand is the tesseract executable used when no OCR
+	// service URL is configured.
+	DefaultOCRCommand = "tesseract"
+	// DefaultOCRLanguage is the tesseract language used by default.
+	DefaultOCRLanguage = "eng"
+	// DefaultOCRMaxImageSize is the default size above which images are not
+	// sent to the OCR engine.
+	DefaultOCRMaxImageSize = 10 * 1024 * 1024
+	// DefaultOCRTimeout is the default time the OCR engine may spend on a
+	// single image.
+	DefaultOCRTimeout = 60 * time.Second
+
+	// ocrMaxTextSize bounds the text read back from the OCR engine.
+	ocrMaxTextSize = 1024 * 1024
+)
+
+var errImageTooLarge = errors.New("image exceeds the maximum OCR size")
+
+var ocrSupportedExtensions = map[string]bool{
+	"png":  true,
+	"jpg":  true,
+	"jpeg": true,
+	"gif":  true,
+	"bmp":  true,
+	"tif":  true,
+	"tiff": true,
+	"webp": true,
+}
+
+// ocrExtractor extracts the text of images with an external OCR engine,
+// either the tesseract command line or, when url is set, an HTTP service
+// receiving the image as the "file" field of a multipart form, and the
+// tesseract language in the Accept-Language header, and replying with the
+// plain text.
+type ocrExtractor struct {
+	command  string
+	url      string
+	language string
+	maxSize  int64
+	timeout  time.Duration
+}
+
+func newOCRExtractor(settings ExtractSettings) *ocrExtractor {
+	oe := &ocrExtractor{
+		command:  settings.OCRCommand,
+		url:      strings.TrimSuffix(settings.OCRURL, "/"),
+		language: settings.OCRLanguage,
+		maxSize:  settings.OCRMaxImageSize,
+		timeout:  settings.OCRTimeout,
+	}
+	if oe.command == "" {
+		oe.command = DefaultOCRCommand
+	}
+	if oe.language == "" {
+		oe.language = DefaultOCRLanguage
+	}
+	if oe.maxSize <= 0 {
+		oe.maxSize = DefaultOCRMaxImageSize
+	}
+	if oe.timeout <= 0 {
+		oe.timeout = DefaultOCRTimeout
+	}
+	return oe
+}
+
+func (oe *ocrExtractor) Name() string {
+	return "ocrExtractor"
+}
+
+func (oe *ocrExtractor) Match(filename string) bool {
+	extension := strings.ToLower(strings.TrimPrefix(path.Ext(filename), "."))
+	return ocrSupportedExtensions[extension]
+}
+
+func (oe *ocrExtractor) Extract(filename string, r io.ReadSeeker) (string, error) {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return "", err
+	}
+	if size > oe.maxSize {
+		return "", errImageTooLarge
+	}
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), oe.timeout)
+	defer cancel()
+
+	var text string
+	if oe.url != "" {
+		text, err = oe.extractWithService(ctx, filename, r)
+	} else {
+		text, err = oe.extractWithCommand(ctx, r)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", errExtractTimeout
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(text), nil
+}
+
+func (oe *ocrExtractor) extractWithCommand(ctx context.Context, r io.Reader) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, oe.command, "stdin", "stdout", "-l", oe.language)
+	cmd.Stdin = r
+	cmd.Stdout = &limitedBuffer{buf: &stdout, remaining: ocrMaxTextSize}
+	cmd.Stderr = &limitedBuffer{buf: &stderr, remaining: 4096}
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("ocr command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+func (oe *ocrExtractor) extractWithService(ctx context.Context, filename string, r io.ReadSeeker) (string, error) {
+	b, w, err := createMultipartFormData("file", filename, r)
+	if err != nil {
+		return "", fmt.Errorf("unable to create the ocr request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, oe.url, &b)
+	if err != nil {
+		return "", fmt.Errorf("unable to create the ocr request: %w", err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("Accept-Language", oe.language)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to reach the ocr service: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("the ocr service replied with status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, ocrMaxTextSize))
+	if err != nil {
+		return "", fmt.Errorf("unable to read the response from the ocr service: %w", err)
+	}
+	return string(data), nil
+}
+
+// limitedBuffer discards what is written past its capacity, so that a
+// misbehaving command cannot exhaust the memory.
+type limitedBuffer struct {
+	buf       *bytes.Buffer
+	remaining int
+}
+
+func (lb *limitedBuffer) Write(p []byte) (int, error) {
+	if lb.remaining > 0 {
+		n := min(len(p), lb.remaining)
+		lb.buf.Write(p[:n])
+		lb.remaining -= n
+	}
+	return len(p), nil
+}
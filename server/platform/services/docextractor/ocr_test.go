@@ -0,0 +1,141 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// createFakeTesseract writes a script standing in for the tesseract command.
+func createFakeTesseract(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake tesseract command requires a shell")
+	}
+	command := filepath.Join(t.TempDir(), "tesseract")
+	require.NoError(t, os.WriteFile(command, []byte("#!/bin/sh\n"+script), 0700))
+	return command
+}
+
+func TestOCRExtractor(t *testing.T) {
+	image := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+	t.Run("match", func(t *testing.T) {
+		extractor := newOCRExtractor(ExtractSettings{})
+		assert.True(t, extractor.Match("screenshot.png"))
+		assert.True(t, extractor.Match("photo.JPG"))
+		assert.True(t, extractor.Match("scan.tiff"))
+		assert.False(t, extractor.Match("drawing.svg"))
+		assert.False(t, extractor.Match("document.pdf"))
+	})
+
+	t.Run("tesseract command", func(t *testing.T) {
+		command := createFakeTesseract(t, `[ "$1 $2 $3 $4" = "stdin stdout -l deu+eng" ] || exit 1
+cat > /dev/null
+echo "  Error: connection refused  "`)
+		extractor := newOCRExtractor(ExtractSettings{OCRCommand: command, OCRLanguage: "deu+eng"})
+
+		text, err := extractor.Extract("screenshot.png", bytes.NewReader(image))
+		require.NoError(t, err)
+		assert.Equal(t, "Error: connection refused", text)
+	})
+
+	t.Run("tesseract failure", func(t *testing.T) {
+		command := createFakeTesseract(t, `echo "Error opening data file" >&2
+exit 1`)
+		extractor := newOCRExtractor(ExtractSettings{OCRCommand: command})
+
+		_, err := extractor.Extract("screenshot.png", bytes.NewReader(image))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Error opening data file")
+	})
+
+	t.Run("tesseract timeout", func(t *testing.T) {
+		command := createFakeTesseract(t, `exec sleep 5`)
+		extractor := newOCRExtractor(ExtractSettings{OCRCommand: command, OCRTimeout: 100 * time.Millisecond})
+
+		_, err := extractor.Extract("screenshot.png", bytes.NewReader(image))
+		require.ErrorIs(t, err, errExtractTimeout)
+	})
+
+	t.Run("ocr service", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "eng", r.Header.Get("Accept-Language"))
+			file, header, err := r.FormFile("file")
+			require.NoError(t, err)
+			defer file.Close()
+			assert.Equal(t, "screenshot.png", header.Filename)
+			data, err := io.ReadAll(file)
+			require.NoError(t, err)
+			assert.Equal(t, image, data)
+			w.Write([]byte("Disk quota exceeded\n"))
+		}))
+		defer server.Close()
+		extractor := newOCRExtractor(ExtractSettings{OCRURL: server.URL})
+
+		text, err := extractor.Extract("screenshot.png", bytes.NewReader(image))
+		require.NoError(t, err)
+		assert.Equal(t, "Disk quota exceeded", text)
+	})
+
+	t.Run("ocr service error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+		extractor := newOCRExtractor(ExtractSettings{OCRURL: server.URL})
+
+		_, err := extractor.Extract("screenshot.png", bytes.NewReader(image))
+		require.Error(t, err)
+	})
+
+	t.Run("ocr service timeout", func(t *testing.T) {
+		done := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-done
+		}))
+		defer server.Close()
+		defer close(done)
+		extractor := newOCRExtractor(ExtractSettings{OCRURL: server.URL, OCRTimeout: 100 * time.Millisecond})
+
+		_, err := extractor.Extract("screenshot.png", bytes.NewReader(image))
+		require.ErrorIs(t, err, errExtractTimeout)
+	})
+
+	t.Run("image too large", func(t *testing.T) {
+		extractor := newOCRExtractor(ExtractSettings{OCRCommand: "/nonexistent/tesseract", OCRMaxImageSize: 10})
+
+		_, err := extractor.Extract("screenshot.png", bytes.NewReader(image))
+		require.ErrorIs(t, err, errImageTooLarge)
+	})
+}
+
+func TestExtractOCR(t *testing.T) {
+	logger := mlog.CreateConsoleTestLogger(t)
+	command := createFakeTesseract(t, `cat > /dev/null
+echo "Screenshot text"`)
+	image := bytes.NewReader([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"))
+
+	text, err := Extract(logger, "screenshot.png", image, ExtractSettings{})
+	require.NoError(t, err)
+	assert.Empty(t, text)
+
+	text, err = Extract(logger, "screenshot.png", image, ExtractSettings{EnableOCR: true, OCRCommand: command})
+	require.NoError(t, err)
+	assert.Equal(t, "Screenshot text", text)
+}
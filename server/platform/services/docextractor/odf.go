@@ -0,0 +1,68 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+// odfExtractor extracts the text of OpenDocument text documents,
+// spreadsheets and presentations, including the speaker notes.
+type odfExtractor struct {
+	limits extractLimits
+}
+
+var odfExtensions = map[string]bool{
+	"odt": true,
+	"ods": true,
+	"odp": true,
+	"odg": true,
+}
+
+var odfRules = xmlTextRules{
+	text:      newSet("p", "h"),
+	paragraph: newSet("p", "h"),
+	space:     newSet("s", "tab", "line-break"),
+	// Annotations and tracked deletions are not part of the visible text.
+	skip: newSet("tracked-changes", "annotation"),
+}
+
+func (oe *odfExtractor) Name() string {
+	return "odfExtractor"
+}
+
+func (oe *odfExtractor) Match(filename string) bool {
+	extension := strings.ToLower(strings.TrimPrefix(path.Ext(filename), "."))
+	return odfExtensions[extension]
+}
+
+func (oe *odfExtractor) Extract(filename string, r io.ReadSeeker) (out string, outErr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			out = ""
+			outErr = errors.New("error extracting opendocument text")
+		}
+	}()
+
+	archive, err := openZip(r)
+	if err != nil {
+		return "", err
+	}
+
+	b := oe.limits.start()
+	var text strings.Builder
+	for _, f := range archive.File {
+		if f.Name != "content.xml" {
+			continue
+		}
+		if err := writeZipXMLText(&text, f, odfRules, b); err != nil {
+			return "", err
+		}
+		return text.String(), nil
+	}
+	return "", errors.New("no opendocument content found")
+}
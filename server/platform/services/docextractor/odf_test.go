@@ -0,0 +1,67 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestODFExtractor(t *testing.T) {
+	extractor := &odfExtractor{limits: newExtractLimits(ExtractSettings{})}
+
+	t.Run("match", func(t *testing.T) {
+		assert.True(t, extractor.Match("letter.odt"))
+		assert.True(t, extractor.Match("numbers.ODS"))
+		assert.True(t, extractor.Match("talk.odp"))
+		assert.False(t, extractor.Match("letter.docx"))
+	})
+
+	t.Run("odt", func(t *testing.T) {
+		data := createTestZip(t, map[string]string{
+			"mimetype": "application/vnd.oasis.opendocument.text",
+			"content.xml": `<office:document-content xmlns:office="office" xmlns:text="text"><office:body><office:text>
+				<text:h>Meeting notes</text:h>
+				<text:p>Action<text:s/>items<text:tab/>due<text:line-break/>Friday</text:p>
+				<text:p>Reviewed<office:annotation><text:p>internal remark</text:p></office:annotation> by legal</text:p>
+				<text:tracked-changes><text:changed-region><text:p>deleted text</text:p></text:changed-region></text:tracked-changes>
+			</office:text></office:body></office:document-content>`,
+			"styles.xml": `<office:document-styles xmlns:office="office" xmlns:text="text"><text:p>Page header style</text:p></office:document-styles>`,
+		})
+
+		text, err := extractor.Extract("letter.odt", bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Contains(t, text, "Meeting notes\n")
+		assert.Contains(t, text, "Action items due Friday")
+		assert.Contains(t, text, "Reviewed by legal")
+		assert.NotContains(t, text, "internal remark")
+		assert.NotContains(t, text, "deleted text")
+		assert.NotContains(t, text, "Page header style")
+	})
+
+	t.Run("ods", func(t *testing.T) {
+		data := createTestZip(t, map[string]string{
+			"content.xml": `<office:document-content xmlns:office="office" xmlns:table="table" xmlns:text="text"><office:body><office:spreadsheet>
+				<table:table table:name="Inventory"><table:table-row>
+					<table:table-cell><text:p>Apples</text:p></table:table-cell>
+					<table:table-cell><text:p>42</text:p></table:table-cell>
+				</table:table-row></table:table>
+			</office:spreadsheet></office:body></office:document-content>`,
+		})
+
+		text, err := extractor.Extract("numbers.ods", bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Contains(t, text, "Apples")
+		assert.Contains(t, text, "42")
+	})
+
+	t.Run("missing content", func(t *testing.T) {
+		data := createTestZip(t, map[string]string{"mimetype": "application/vnd.oasis.opendocument.text"})
+		_, err := extractor.Extract("letter.odt", bytes.NewReader(data))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ooxmlExtractor extracts the text of Office Open XML documents: Word body,
+// headers, footers, footnotes and comments, PowerPoint slides and speaker
+// notes, and Excel sheet names and cell text.
+type ooxmlExtractor struct {
+	limits extractLimits
+}
+
+var ooxmlRules = xmlTextRules{
+	text:      newSet("t"),
+	paragraph: newSet("p", "si", "row"),
+	space:     newSet("tab", "br", "cr"),
+	// Fallback holds a copy of the content of text boxes and shapes for
+	// older readers.
+	skip: newSet("Fallback", "rPh"),
+}
+
+var ooxmlPartsByExtension = map[string][]*regexp.Regexp{
+	"docx": {
+		regexp.MustCompile(`^word/document\.xml$`),
+		regexp.MustCompile(`^word/(header|footer)\d*\.xml$`),
+		regexp.MustCompile(`^word/(footnotes|endnotes|comments)\.xml$`),
+	},
+	"pptx": {
+		regexp.MustCompile(`^ppt/slides/slide\d+\.xml$`),
+		regexp.MustCompile(`^ppt/notesSlides/notesSlide\d+\.xml$`),
+		regexp.MustCompile(`^ppt/comments/comment\d+\.xml$`),
+	},
+	"xlsx": {
+		regexp.MustCompile(`^xl/sharedStrings\.xml$`),
+		regexp.MustCompile(`^xl/worksheets/sheet\d+\.xml$`),
+	},
+}
+
+var partNumberRegexp = regexp.MustCompile(`(\d+)\.xml$`)
+
+func (oe *ooxmlExtractor) Name() string {
+	return "ooxmlExtractor"
+}
+
+func (oe *ooxmlExtractor) Match(filename string) bool {
+	extension := strings.ToLower(strings.TrimPrefix(path.Ext(filename), "."))
+	_, ok := ooxmlPartsByExtension[extension]
+	return ok
+}
+
+func (oe *ooxmlExtractor) Extract(filename string, r io.ReadSeeker) (out string, outErr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			out = ""
+			outErr = errors.New("error extracting office document text")
+		}
+	}()
+
+	extension := strings.ToLower(strings.TrimPrefix(path.Ext(filename), "."))
+	patterns, ok := ooxmlPartsByExtension[extension]
+	if !ok {
+		return "", errors.New("unknown office document type")
+	}
+
+	archive, err := openZip(r)
+	if err != nil {
+		return "", err
+	}
+
+	b := oe.limits.start()
+	var text strings.Builder
+
+	if extension == "xlsx" {
+		if err := writeWorkbookSheetNames(&text, archive, b); err != nil {
+			return "", err
+		}
+	}
+
+	found := false
+	for _, pattern := range patterns {
+		for _, f := range sortedParts(archive, pattern) {
+			found = true
+			if err := writeZipXMLText(&text, f, ooxmlRules, b); err != nil {
+				return "", err
+			}
+		}
+	}
+	if !found {
+		return "", errors.New("no office document content found")
+	}
+
+	return text.String(), nil
+}
+
+// writeWorkbookSheetNames writes the names of the sheets of a workbook.
+func writeWorkbookSheetNames(text *strings.Builder, archive *zip.Reader, b *budget) error {
+	for _, f := range archive.File {
+		if f.Name != "xl/workbook.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+
+		var workbook struct {
+			Sheets []struct {
+				Name string `xml:"name,attr"`
+			} `xml:"sheets>sheet"`
+		}
+		data, err := b.readAll(rc)
+		if err != nil {
+			return err
+		}
+		if err := xml.Unmarshal(data, &workbook); err != nil {
+			return err
+		}
+		for _, sheet := range workbook.Sheets {
+			text.WriteString(sheet.Name + "\n")
+		}
+		return nil
+	}
+	return nil
+}
+
+// sortedParts returns the entries matching pattern, ordered by the number in
+// their name so that slide10 comes after slide9.
+func sortedParts(archive *zip.Reader, pattern *regexp.Regexp) []*zip.File {
+	var parts []*zip.File
+	for _, f := range archive.File {
+		if pattern.MatchString(f.Name) {
+			parts = append(parts, f)
+		}
+	}
+	partNumber := func(name string) int {
+		match := partNumberRegexp.FindStringSubmatch(name)
+		if match == nil {
+			return 0
+		}
+		n, _ := strconv.Atoi(match[1])
+		return n
+	}
+	sort.SliceStable(parts, func(i, j int) bool {
+		ni, nj := partNumber(parts[i].Name), partNumber(parts[j].Name)
+		if ni != nj {
+			return ni < nj
+		}
+		return parts[i].Name < parts[j].Name
+	})
+	return parts
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestOOXMLExtractor(t *testing.T) {
+	extractor := &ooxmlExtractor{limits: newExtractLimits(ExtractSettings{})}
+
+	t.Run("match", func(t *testing.T) {
+		assert.True(t, extractor.Match("report.docx"))
+		assert.True(t, extractor.Match("slides.PPTX"))
+		assert.True(t, extractor.Match("budget.xlsx"))
+		assert.False(t, extractor.Match("report.doc"))
+		assert.False(t, extractor.Match("report.odt"))
+	})
+
+	t.Run("docx", func(t *testing.T) {
+		data := createTestZip(t, map[string]string{
+			"word/document.xml": `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006"><w:body>
+				<w:p><w:r><w:t>Quarterly</w:t></w:r><w:r><w:tab/><w:t xml:space="preserve">results </w:t></w:r></w:p>
+				<w:p><mc:AlternateContent><mc:Choice><w:t>textbox</w:t></mc:Choice><mc:Fallback><w:t>duplicate</w:t></mc:Fallback></mc:AlternateContent></w:p>
+			</w:body></w:document>`,
+			"word/header1.xml":   `<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:p><w:r><w:t>Confidential</w:t></w:r></w:p></w:hdr>`,
+			"word/footnotes.xml": `<w:footnotes xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:footnote><w:p><w:r><w:t>Footnote</w:t></w:r></w:p></w:footnote></w:footnotes>`,
+			"word/styles.xml":    `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:t>style</w:t></w:styles>`,
+		})
+
+		text, err := extractor.Extract("report.docx", bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Contains(t, text, "Quarterly results")
+		assert.Contains(t, text, "textbox")
+		assert.Contains(t, text, "Confidential")
+		assert.Contains(t, text, "Footnote")
+		assert.NotContains(t, text, "duplicate")
+		assert.NotContains(t, text, "style")
+	})
+
+	t.Run("pptx with speaker notes in slide order", func(t *testing.T) {
+		data := createTestZip(t, map[string]string{
+			"ppt/slides/slide1.xml":               `<p:sld xmlns:p="p" xmlns:a="a"><a:p><a:r><a:t>First slide</a:t></a:r></a:p></p:sld>`,
+			"ppt/slides/slide2.xml":               `<p:sld xmlns:p="p" xmlns:a="a"><a:p><a:r><a:t>Second slide</a:t></a:r></a:p></p:sld>`,
+			"ppt/slides/slide10.xml":              `<p:sld xmlns:p="p" xmlns:a="a"><a:p><a:r><a:t>Tenth slide</a:t></a:r></a:p></p:sld>`,
+			"ppt/notesSlides/notesSlide1.xml":     `<p:notes xmlns:p="p" xmlns:a="a"><a:p><a:r><a:t>Remember to smile</a:t></a:r></a:p></p:notes>`,
+			"ppt/slideLayouts/slideLayout1.xml":   `<p:sldLayout xmlns:p="p" xmlns:a="a"><a:t>Layout placeholder</a:t></p:sldLayout>`,
+			"ppt/slides/_rels/slide1.xml.rels":    `<Relationships/>`,
+			"ppt/notesSlides/_rels/notes.xml.rel": `<Relationships/>`,
+		})
+
+		text, err := extractor.Extract("slides.pptx", bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Contains(t, text, "Remember to smile")
+		assert.NotContains(t, text, "Layout placeholder")
+		assert.Less(t, strings.Index(text, "Second slide"), strings.Index(text, "Tenth slide"))
+		assert.Less(t, strings.Index(text, "First slide"), strings.Index(text, "Second slide"))
+	})
+
+	t.Run("xlsx", func(t *testing.T) {
+		data := createTestZip(t, map[string]string{
+			"xl/workbook.xml":          `<workbook xmlns="main"><sheets><sheet name="Revenue" sheetId="1"/><sheet name="Costs" sheetId="2"/></sheets></workbook>`,
+			"xl/sharedStrings.xml":     `<sst xmlns="main"><si><t>Widgets</t></si><si><r><t>Gad</t></r><r><t>gets</t></r></si></sst>`,
+			"xl/worksheets/sheet1.xml": `<worksheet xmlns="main"><sheetData><row><c t="s"><v>0</v></c><c t="inlineStr"><is><t>Inline note</t></is></c></row></sheetData></worksheet>`,
+		})
+
+		text, err := extractor.Extract("budget.xlsx", bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Contains(t, text, "Revenue")
+		assert.Contains(t, text, "Costs")
+		assert.Contains(t, text, "Widgets")
+		assert.Contains(t, text, "Gadgets")
+		assert.Contains(t, text, "Inline note")
+	})
+
+	t.Run("not an office document", func(t *testing.T) {
+		_, err := extractor.Extract("report.docx", bytes.NewReader([]byte("plain text")))
+		require.Error(t, err)
+
+		data := createTestZip(t, map[string]string{"readme.txt": "hello"})
+		_, err = extractor.Extract("report.docx", bytes.NewReader(data))
+		require.Error(t, err)
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		data := createTestZip(t, map[string]string{
+			"word/document.xml": `<w:document xmlns:w="w"><w:body><w:p><w:r><w:t>` + strings.Repeat("a", 10000) + `</w:t></w:r></w:p></w:body></w:document>`,
+		})
+
+		limited := &ooxmlExtractor{limits: newExtractLimits(ExtractSettings{MaxExtractSize: 1000})}
+		_, err := limited.Extract("large.docx", bytes.NewReader(data))
+		require.ErrorIs(t, err, errExtractTooLarge)
+	})
+
+	t.Run("time limit", func(t *testing.T) {
+		data := createTestZip(t, map[string]string{
+			"word/document.xml": `<w:document xmlns:w="w"><w:body><w:p><w:r><w:t>slow</w:t></w:r></w:p></w:body></w:document>`,
+		})
+
+		limited := &ooxmlExtractor{limits: extractLimits{maxSize: DefaultMaxExtractSize, timeout: -time.Second}}
+		_, err := limited.Extract("slow.docx", bytes.NewReader(data))
+		require.ErrorIs(t, err, errExtractTimeout)
+	})
+}
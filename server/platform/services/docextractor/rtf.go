@@ -0,0 +1,259 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"errors"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// rtfExtractor extracts the text of Rich Text Format documents.
+type rtfExtractor struct {
+	limits extractLimits
+}
+
+// rtfSkippedDestinations are the groups that hold no document text.
+var rtfSkippedDestinations = newSet(
+	"fonttbl", "colortbl", "stylesheet", "info", "pict", "object", "listtable",
+	"listoverridetable", "rsidtbl", "generator", "xmlnstbl", "themedata",
+	"colorschememapping", "datastore", "latentstyles", "pgdsctbl", "revtbl",
+	"fldinst", "filetbl", "mmathPr", "defchp", "defpap", "nonshppict", "bkmkstart",
+	"bkmkend", "operator", "author", "title", "subject", "keywords", "comment",
+)
+
+// rtfCodePages maps the non Windows code pages to their encoding name.
+var rtfCodePages = map[int]string{
+	437:   "ibm437",
+	850:   "ibm850",
+	932:   "shift_jis",
+	936:   "gbk",
+	949:   "euc-kr",
+	950:   "big5",
+	10000: "macintosh",
+	65001: "utf-8",
+}
+
+func (re *rtfExtractor) Name() string {
+	return "rtfExtractor"
+}
+
+func (re *rtfExtractor) Match(filename string) bool {
+	return strings.ToLower(path.Ext(filename)) == ".rtf"
+}
+
+func (re *rtfExtractor) Extract(filename string, r io.ReadSeeker) (string, error) {
+	b := re.limits.start()
+	data, err := b.readAll(r)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(string(data[:min(len(data), 5)]), `{\rtf`) {
+		return "", errors.New("not a rtf document")
+	}
+	return parseRTF(data, b)
+}
+
+type rtfState struct {
+	skip bool
+	uc   int
+}
+
+type rtfParser struct {
+	text     strings.Builder
+	pending  []byte
+	decode   func([]byte) ([]byte, error)
+	state    rtfState
+	stack    []rtfState
+	fallback int
+}
+
+func parseRTF(data []byte, b *budget) (string, error) {
+	p := &rtfParser{state: rtfState{uc: 1}}
+	p.setCodePage(1252)
+
+	for i, n := 0, 0; i < len(data); n++ {
+		if n%65536 == 0 {
+			if err := b.check(); err != nil {
+				return "", err
+			}
+		}
+
+		c := data[i]
+		switch c {
+		case '{':
+			p.stack = append(p.stack, p.state)
+			i++
+		case '}':
+			if len(p.stack) > 0 {
+				p.state = p.stack[len(p.stack)-1]
+				p.stack = p.stack[:len(p.stack)-1]
+			}
+			i++
+		case '\\':
+			i = p.control(data, i+1)
+		case '\r', '\n':
+			i++
+		default:
+			p.writeByte(c)
+			i++
+		}
+	}
+	p.flush()
+	return p.text.String(), nil
+}
+
+func (p *rtfParser) setCodePage(codePage int) {
+	name, ok := rtfCodePages[codePage]
+	if !ok {
+		name = "windows-" + strconv.Itoa(codePage)
+	}
+	if enc, _ := charset.Lookup(name); enc != nil {
+		p.decode = enc.NewDecoder().Bytes
+	}
+}
+
+// writeByte writes a character of the document code page, unless it is the
+// fallback of a preceding unicode character.
+func (p *rtfParser) writeByte(c byte) {
+	if p.fallback > 0 {
+		p.fallback--
+		return
+	}
+	if !p.state.skip {
+		p.pending = append(p.pending, c)
+	}
+}
+
+func (p *rtfParser) writeString(s string) {
+	p.fallback = 0
+	if !p.state.skip {
+		p.flush()
+		p.text.WriteString(s)
+	}
+}
+
+func (p *rtfParser) flush() {
+	if len(p.pending) == 0 {
+		return
+	}
+	if p.decode != nil {
+		if decoded, err := p.decode(p.pending); err == nil {
+			p.text.Write(decoded)
+			p.pending = p.pending[:0]
+			return
+		}
+	}
+	p.text.Write(p.pending)
+	p.pending = p.pending[:0]
+}
+
+// control parses the control word or symbol starting at data[i], just after
+// the backslash, and returns the position following it.
+func (p *rtfParser) control(data []byte, i int) int {
+	if i >= len(data) {
+		return i
+	}
+
+	if !isASCIILetter(data[i]) {
+		switch data[i] {
+		case '\'':
+			if i+2 < len(data) {
+				if v, err := strconv.ParseUint(string(data[i+1:i+3]), 16, 8); err == nil {
+					p.writeByte(byte(v))
+				}
+			}
+			return i + 3
+		case '*':
+			p.state.skip = true
+		case '\\', '{', '}':
+			p.writeByte(data[i])
+		case '~':
+			p.writeString(" ")
+		case '_':
+			p.writeString("-")
+		case '\r', '\n':
+			p.writeString("\n")
+		}
+		return i + 1
+	}
+
+	start := i
+	for i < len(data) && isASCIILetter(data[i]) {
+		i++
+	}
+	word := string(data[start:i])
+
+	param, hasParam := 0, false
+	paramStart := i
+	if i < len(data) && data[i] == '-' {
+		i++
+	}
+	for i < len(data) && data[i] >= '0' && data[i] <= '9' {
+		i++
+	}
+	if i > paramStart {
+		if v, err := strconv.Atoi(string(data[paramStart:i])); err == nil {
+			param, hasParam = v, true
+		}
+	}
+	if i < len(data) && data[i] == ' ' {
+		i++
+	}
+
+	switch word {
+	case "par", "line", "row", "sect", "page":
+		p.writeString("\n")
+	case "tab", "cell":
+		p.writeString("\t")
+	case "emdash":
+		p.writeString("—")
+	case "endash":
+		p.writeString("–")
+	case "bullet":
+		p.writeString("•")
+	case "lquote":
+		p.writeString("‘")
+	case "rquote":
+		p.writeString("’")
+	case "ldblquote":
+		p.writeString("“")
+	case "rdblquote":
+		p.writeString("”")
+	case "u":
+		if hasParam {
+			if param < 0 {
+				param += 65536
+			}
+			p.writeString(string(rune(param)))
+			p.fallback = p.state.uc
+		}
+	case "uc":
+		if hasParam {
+			p.state.uc = param
+		}
+	case "ansicpg":
+		if hasParam {
+			p.flush()
+			p.setCodePage(param)
+		}
+	case "bin":
+		if hasParam && param > 0 {
+			i = min(i+param, len(data))
+		}
+	default:
+		if rtfSkippedDestinations[word] {
+			p.state.skip = true
+		}
+	}
+	return i
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
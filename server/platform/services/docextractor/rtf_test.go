@@ -0,0 +1,68 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRTFExtractor(t *testing.T) {
+	extractor := &rtfExtractor{limits: newExtractLimits(ExtractSettings{})}
+
+	extract := func(t *testing.T, document string) string {
+		t.Helper()
+		text, err := extractor.Extract("document.rtf", strings.NewReader(document))
+		require.NoError(t, err)
+		return text
+	}
+
+	t.Run("match", func(t *testing.T) {
+		assert.True(t, extractor.Match("document.rtf"))
+		assert.True(t, extractor.Match("DOCUMENT.RTF"))
+		assert.False(t, extractor.Match("document.txt"))
+	})
+
+	t.Run("plain paragraphs", func(t *testing.T) {
+		text := extract(t, `{\rtf1\ansi\deff0{\fonttbl{\f0 Times New Roman;}}{\colortbl;\red0\green0\blue0;}
+{\info{\title Hidden title}{\author Someone}}
+\f0\fs24 Hello {\b bold} world.\par
+Second\tab line\line end.\par}`)
+		assert.Equal(t, "Hello bold world.\nSecond\tline\nend.\n", text)
+	})
+
+	t.Run("skipped destinations", func(t *testing.T) {
+		text := extract(t, `{\rtf1{\*\generator Writer;}{\*\unknowndest ignored}{\field{\*\fldinst HYPERLINK "http://example.com"}{\fldrslt link}} text}`)
+		assert.Equal(t, "link text", text)
+	})
+
+	t.Run("escapes and code pages", func(t *testing.T) {
+		assert.Equal(t, `caf`+"é"+` {braces} \`, extract(t, `{\rtf1\ansi\ansicpg1252 caf\'e9 \{braces\} \\}`))
+		assert.Equal(t, "Ж", extract(t, `{\rtf1\ansi\ansicpg1251 \'c6}`))
+	})
+
+	t.Run("unicode with fallback", func(t *testing.T) {
+		assert.Equal(t, "你好", extract(t, `{\rtf1\uc1\u20320?\u22909?}`))
+		assert.Equal(t, "été", extract(t, `{\rtf1{\uc2\u233\'3f\'3ft\u233??}}`))
+		assert.Equal(t, "￩", extract(t, `{\rtf1\u-23?}`))
+	})
+
+	t.Run("binary data", func(t *testing.T) {
+		assert.Equal(t, "before after", extract(t, "{\\rtf1 before {\\pict\\bin4 }{\\}}after}"))
+	})
+
+	t.Run("not a rtf document", func(t *testing.T) {
+		_, err := extractor.Extract("document.rtf", strings.NewReader("hello"))
+		require.Error(t, err)
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		limited := &rtfExtractor{limits: newExtractLimits(ExtractSettings{MaxExtractSize: 100})}
+		_, err := limited.Extract("document.rtf", strings.NewReader(`{\rtf1 `+strings.Repeat("a", 1000)+`}`))
+		require.ErrorIs(t, err, errExtractTooLarge)
+	})
+}
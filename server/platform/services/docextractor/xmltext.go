@@ -0,0 +1,114 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package docextractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// xmlTextRules describe where the text lives in an XML document. Elements
+// are matched by their local name, ignoring the namespace.
+type xmlTextRules struct {
+	// text elements hold character data to extract.
+	text map[string]bool
+	// paragraph elements are followed by a line break.
+	paragraph map[string]bool
+	// space elements stand for a space, tab or line break.
+	space map[string]bool
+	// skip elements are ignored along with their children.
+	skip map[string]bool
+}
+
+func newSet(names ...string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+// writeXMLText streams the text of an XML document into text.
+func writeXMLText(text *strings.Builder, r io.Reader, rules xmlTextRules, b *budget) error {
+	decoder := xml.NewDecoder(b.reader(r))
+	decoder.Strict = false
+
+	inText := 0
+	skipping := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			name := t.Name.Local
+			switch {
+			case skipping > 0 || rules.skip[name]:
+				skipping++
+			case rules.text[name]:
+				inText++
+			case rules.space[name]:
+				text.WriteString(" ")
+			}
+		case xml.EndElement:
+			name := t.Name.Local
+			if skipping > 0 {
+				skipping--
+				continue
+			}
+			if rules.text[name] {
+				inText--
+			}
+			if rules.paragraph[name] {
+				text.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText > 0 && skipping == 0 {
+				text.Write(t)
+			}
+		}
+	}
+}
+
+// writeZipXMLText extracts the text of the XML document stored in the zip
+// entry f.
+func writeZipXMLText(text *strings.Builder, f *zip.File, rules xmlTextRules, b *budget) error {
+	if err := b.check(); err != nil {
+		return err
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return writeXMLText(text, rc, rules, b)
+}
+
+// openZip opens r as a zip archive.
+func openZip(r io.ReadSeeker) (*zip.Reader, error) {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	readerAt, ok := r.(io.ReaderAt)
+	if !ok {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		return zip.NewReader(bytes.NewReader(data), size)
+	}
+	return zip.NewReader(readerAt, size)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Package importconvert provides what the converters of the exports of other chat platforms to the bulk import format
+// have in common: the conversion of the names, the emojis, and the messages, and the writing of the import file.
+package importconvert
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+var (
+	invalidNameChars     = regexp.MustCompile(`[^a-z0-9]+`)
+	invalidUsernameChars = regexp.MustCompile(`[^a-z0-9.\-_]+`)
+)
+
+// ShortHash returns a name derived from an id, for the entities whose names can't be converted.
+func ShortHash(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:6])
+}
+
+// ConvertName returns a team or channel name from its display name, or the prefix followed by a hash of the id if
+// the display name has too few valid characters.
+func ConvertName(displayName, id, prefix string, isTeam bool) string {
+	name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(displayName), "-"), "-")
+	if len(name) < model.TeamNameMinLength || (isTeam && model.IsReservedTeamName(name)) {
+		return prefix + "-" + ShortHash(id)
+	}
+	return name
+}
+
+// ConvertUsername returns a username from a name, e.g. the local part of an email address, or the prefix followed by
+// a hash of the id if the name is not a valid username.
+func ConvertUsername(name, id, prefix string) string {
+	username := strings.Trim(invalidUsernameChars.ReplaceAllString(strings.ToLower(name), "-"), "-._")
+	if len(username) > model.UserNameMaxLength {
+		username = strings.TrimRight(username[:model.UserNameMaxLength], "-._")
+	}
+	if !model.IsValidUsername(username) {
+		return prefix + "-" + ShortHash(id)
+	}
+	return username
+}
+
+// UniqueName truncates the name and appends a number to it if it is already used.
+func UniqueName(name string, maxLength int, used map[string]bool) string {
+	if len(name) > maxLength {
+		name = strings.TrimRight(name[:maxLength], "-._")
+	}
+	unique := name
+	for i := 2; used[unique]; i++ {
+		suffix := "-" + strconv.Itoa(i)
+		unique = name
+		if len(unique)+len(suffix) > maxLength {
+			unique = unique[:maxLength-len(suffix)]
+		}
+		unique += suffix
+	}
+	used[unique] = true
+	return unique
+}
+
+// TruncateRunes returns the first n runes of s.
+func TruncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// EmojiName returns the name of the system emoji of an emoji character, trying with and without the variation
+// selector.
+func EmojiName(emoji string) (string, bool) {
+	var codepoints []string
+	for _, r := range emoji {
+		codepoints = append(codepoints, model.RuneToHexadecimalString(r))
+	}
+	if len(codepoints) == 0 {
+		return "", false
+	}
+	unicode := strings.Join(codepoints, "-")
+	for _, candidate := range []string{unicode, strings.TrimSuffix(unicode, "-fe0f"), unicode + "-fe0f"} {
+		if name, count := model.GetEmojiNameFromUnicode(candidate); count > 0 {
+			return name, true
+		}
+	}
+	return "", false
+}
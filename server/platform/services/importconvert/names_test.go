@@ -0,0 +1,65 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importconvert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertName(t *testing.T) {
+	for _, tc := range []struct {
+		displayName string
+		isTeam      bool
+		expected    string
+	}{
+		{"Product Team", true, "product-team"},
+		{"--General--", false, "general"},
+		{"a", false, "prefix-" + ShortHash("id")},
+		{"日本", true, "prefix-" + ShortHash("id")},
+		{"Signup", true, "prefix-" + ShortHash("id")},
+		{"Signup", false, "signup"},
+	} {
+		assert.Equal(t, tc.expected, ConvertName(tc.displayName, "id", "prefix", tc.isTeam), tc.displayName)
+	}
+}
+
+func TestConvertUsername(t *testing.T) {
+	assert.Equal(t, "john.doe", ConvertUsername("John.Doe", "id", "prefix"))
+	assert.Equal(t, "john-doe", ConvertUsername("john+doe", "id", "prefix"))
+	assert.Equal(t, strings.Repeat("a", 64), ConvertUsername(strings.Repeat("a", 70), "id", "prefix"))
+	assert.Equal(t, "prefix-"+ShortHash("id"), ConvertUsername("+", "id", "prefix"))
+	assert.Equal(t, "prefix-"+ShortHash("id"), ConvertUsername("all", "id", "prefix"))
+}
+
+func TestUniqueName(t *testing.T) {
+	used := map[string]bool{}
+	assert.Equal(t, "name", UniqueName("name", 64, used))
+	assert.Equal(t, "name-2", UniqueName("name", 64, used))
+	assert.Equal(t, "name-3", UniqueName("name", 64, used))
+	assert.Equal(t, "abcd", UniqueName("abcde", 4, used))
+	assert.Equal(t, "ab-2", UniqueName("abcd", 4, used))
+}
+
+func TestEmojiName(t *testing.T) {
+	for _, tc := range []struct {
+		emoji    string
+		expected string
+	}{
+		{"👍", "+1"},
+		{"❤️", "heart"},
+		{"❤", "heart"},
+	} {
+		name, ok := EmojiName(tc.emoji)
+		assert.True(t, ok, tc.emoji)
+		assert.Equal(t, tc.expected, name, tc.emoji)
+	}
+
+	_, ok := EmojiName("unknown")
+	assert.False(t, ok)
+	_, ok = EmojiName("")
+	assert.False(t, ok)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importconvert
+
+import (
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/app/imports"
+)
+
+// Message is a message converted to the fields shared by the posts, the direct posts and the replies.
+type Message struct {
+	User        string
+	Text        string
+	CreateAt    int64
+	EditAt      *int64
+	IsPinned    *bool
+	Reactions   *[]imports.ReactionImportData
+	Attachments *[]imports.AttachmentImportData
+}
+
+// PostConverter converts the threads of an export, a root message of type M with its replies, to posts and direct
+// posts, counting them in the report.
+type PostConverter[M any] struct {
+	Report *Report
+	// ConvertMessage returns the converted message, or nil if the message is skipped, in which case it is counted in
+	// the report. where describes the channel of the message in the warnings.
+	ConvertMessage func(m M, where string) *Message
+}
+
+// ConvertPost returns the post of a thread of the channel, or nil if the root message is skipped, along with its
+// replies.
+func (c *PostConverter[M]) ConvertPost(root M, replies []M, teamName, channelName, where string) *imports.PostImportData {
+	m := c.ConvertMessage(root, where)
+	if m == nil {
+		c.Report.SkippedMessages += len(replies)
+		return nil
+	}
+	c.Report.Posts++
+	return &imports.PostImportData{
+		Team:        model.NewPointer(teamName),
+		Channel:     model.NewPointer(channelName),
+		User:        model.NewPointer(m.User),
+		Message:     model.NewPointer(m.Text),
+		CreateAt:    model.NewPointer(m.CreateAt),
+		EditAt:      m.EditAt,
+		IsPinned:    m.IsPinned,
+		Reactions:   m.Reactions,
+		Attachments: m.Attachments,
+		Replies:     c.convertReplies(replies, where),
+	}
+}
+
+// ConvertDirectPost returns the direct post of a thread of the direct channel of the members, or nil if the root
+// message is skipped, along with its replies.
+func (c *PostConverter[M]) ConvertDirectPost(root M, replies []M, members []string, where string) *imports.DirectPostImportData {
+	m := c.ConvertMessage(root, where)
+	if m == nil {
+		c.Report.SkippedMessages += len(replies)
+		return nil
+	}
+	c.Report.DirectPosts++
+	return &imports.DirectPostImportData{
+		ChannelMembers: &members,
+		User:           model.NewPointer(m.User),
+		Message:        model.NewPointer(m.Text),
+		CreateAt:       model.NewPointer(m.CreateAt),
+		EditAt:         m.EditAt,
+		IsPinned:       m.IsPinned,
+		Reactions:      m.Reactions,
+		Attachments:    m.Attachments,
+		Replies:        c.convertReplies(replies, where),
+	}
+}
+
+func (c *PostConverter[M]) convertReplies(messages []M, where string) *[]imports.ReplyImportData {
+	var replies []imports.ReplyImportData
+	for _, reply := range messages {
+		m := c.ConvertMessage(reply, where)
+		if m == nil {
+			continue
+		}
+		replies = append(replies, imports.ReplyImportData{
+			User:        model.NewPointer(m.User),
+			Message:     model.NewPointer(m.Text),
+			CreateAt:    model.NewPointer(m.CreateAt),
+			EditAt:      m.EditAt,
+			IsPinned:    m.IsPinned,
+			Reactions:   m.Reactions,
+			Attachments: m.Attachments,
+		})
+	}
+	c.Report.Replies += len(replies)
+	if len(replies) == 0 {
+		return nil
+	}
+	return &replies
+}
@@ -0,0 +1,25 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importconvert
+
+import "fmt"
+
+// Report counts the converted entities, and describes what could not be converted.
+type Report struct {
+	Teams           int      `json:"teams"`
+	Channels        int      `json:"channels"`
+	Users           int      `json:"users"`
+	Posts           int      `json:"posts"`
+	Replies         int      `json:"replies"`
+	DirectChannels  int      `json:"direct_channels"`
+	DirectPosts     int      `json:"direct_posts"`
+	Attachments     int      `json:"attachments"`
+	SkippedMessages int      `json:"skipped_messages"`
+	Warnings        []string `json:"warnings"`
+}
+
+// Warn adds a warning to the report.
+func (r *Report) Warn(format string, args ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importconvert
+
+import (
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/app/imports"
+)
+
+// User is a converted user, with their team and channel memberships.
+type User struct {
+	Username string
+	Data     *imports.UserImportData
+
+	teams    []*imports.UserTeamImportData
+	channels map[string]bool
+}
+
+// NewUser returns a user without memberships.
+func NewUser(username string, data *imports.UserImportData) *User {
+	return &User{
+		Username: username,
+		Data:     data,
+		channels: make(map[string]bool),
+	}
+}
+
+func (u *User) team(name string) *imports.UserTeamImportData {
+	for _, membership := range u.teams {
+		if *membership.Name == name {
+			return membership
+		}
+	}
+	return nil
+}
+
+// JoinTeam makes the user a member of the team, or an admin of the team if admin is true.
+func (u *User) JoinTeam(teamName string, admin bool) {
+	membership := u.team(teamName)
+	if membership == nil {
+		membership = &imports.UserTeamImportData{
+			Name:     model.NewPointer(teamName),
+			Roles:    model.NewPointer(model.TeamUserRoleId),
+			Channels: &[]imports.UserChannelImportData{},
+		}
+		u.teams = append(u.teams, membership)
+	}
+	if admin {
+		membership.Roles = model.NewPointer(model.TeamUserRoleId + " " + model.TeamAdminRoleId)
+	}
+}
+
+// JoinChannel makes the user a member of the channel, and of its team.
+func (u *User) JoinChannel(teamName, channelName string, admin bool) {
+	key := teamName + "/" + channelName
+	if u.channels[key] {
+		return
+	}
+	u.channels[key] = true
+
+	u.JoinTeam(teamName, false)
+	roles := model.ChannelUserRoleId
+	if admin {
+		roles += " " + model.ChannelAdminRoleId
+	}
+	membership := u.team(teamName)
+	*membership.Channels = append(*membership.Channels, imports.UserChannelImportData{
+		Name:  model.NewPointer(channelName),
+		Roles: model.NewPointer(roles),
+	})
+}
+
+// ImportData returns the data of the user line, with the memberships of the user. The members of a team without any
+// of its channels are made members of its default channel, which every team member joins, as the import takes a team
+// membership without channels for a guest one.
+func (u *User) ImportData() *imports.UserImportData {
+	for _, membership := range u.teams {
+		if len(*membership.Channels) == 0 {
+			u.JoinChannel(*membership.Name, model.DefaultChannelName, false)
+		}
+	}
+	if len(u.teams) > 0 {
+		teams := make([]imports.UserTeamImportData, 0, len(u.teams))
+		for _, membership := range u.teams {
+			teams = append(teams, *membership)
+		}
+		u.Data.Teams = &teams
+	}
+	return u.Data
+}
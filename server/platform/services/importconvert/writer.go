@@ -0,0 +1,99 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package importconvert
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/app/imports"
+)
+
+// JSONLFilename is the name of the bulk import file in the converted zip file.
+const JSONLFilename = "import.jsonl"
+
+// Writer writes a bulk import zip file, with the JSONLFilename file and the attached files in the
+// model.ExportDataDir directory, ready for the import process job.
+type Writer struct {
+	zipWriter *zip.Writer
+	encoder   *json.Encoder
+	// attachments are the files to copy to the data directory of the import, by path.
+	attachments map[string]*zip.File
+}
+
+// NewWriter starts writing a bulk import zip file to w.
+func NewWriter(w io.Writer) (*Writer, error) {
+	zipWriter := zip.NewWriter(w)
+	jsonl, err := zipWriter.Create(JSONLFilename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the import file: %w", err)
+	}
+	return &Writer{
+		zipWriter:   zipWriter,
+		encoder:     json.NewEncoder(jsonl),
+		attachments: make(map[string]*zip.File),
+	}, nil
+}
+
+// WriteLine writes a line of the import file. The lines must be written in the order of the import: the teams and the
+// channels before their members, and the users before their posts.
+func (w *Writer) WriteLine(line *imports.LineImportData) error {
+	if err := w.encoder.Encode(line); err != nil {
+		return fmt.Errorf("failed to write a %s line: %w", line.Type, err)
+	}
+	return nil
+}
+
+// AddAttachment adds a file of the export to copy to the data directory of the import, at the given path, and returns
+// the data of the attachment.
+func (w *Writer) AddAttachment(p string, f *zip.File) imports.AttachmentImportData {
+	w.attachments[p] = f
+	return imports.AttachmentImportData{Path: model.NewPointer(p)}
+}
+
+// Attachments returns the number of attached files.
+func (w *Writer) Attachments() int {
+	return len(w.attachments)
+}
+
+// Close copies the attached files, once all the lines have been written, and finishes the zip file.
+func (w *Writer) Close() error {
+	paths := make([]string, 0, len(w.attachments))
+	for p := range w.attachments {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		if err := copyFile(w.zipWriter, path.Join(model.ExportDataDir, p), w.attachments[p]); err != nil {
+			return err
+		}
+	}
+
+	if err := w.zipWriter.Close(); err != nil {
+		return fmt.Errorf("failed to write the import file: %w", err)
+	}
+	return nil
+}
+
+func copyFile(zipWriter *zip.Writer, name string, f *zip.File) error {
+	r, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer r.Close()
+
+	w, err := zipWriter.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	if _, err = io.Copy(w, r); err != nil {
+		return fmt.Errorf("failed to copy %s: %w", f.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msteamsimport
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"github.com/mattermost/mattermost/server/v8/platform/services/importconvert"
+)
+
+var (
+	spaces         = regexp.MustCompile(`[ \t\r\n\x{00a0}]+`)
+	blankLines     = regexp.MustCompile(`\n{3,}`)
+	trailingSpaces = regexp.MustCompile(`(?m)[ \t]+$`)
+)
+
+// reactionEmojis are the emojis of the reactions of Teams before the reactions could be any emoji.
+var reactionEmojis = map[string]string{
+	"like":      "+1",
+	"heart":     "heart",
+	"laugh":     "laughing",
+	"surprised": "open_mouth",
+	"sad":       "cry",
+	"angry":     "angry",
+}
+
+// convertTime returns the time of a Graph API date in milliseconds, or 0 if it is not a date.
+func convertTime(value string) int64 {
+	if value == "" {
+		return 0
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// convertName returns a team or channel name from its display name.
+func convertName(displayName, id string, isTeam bool) string {
+	return importconvert.ConvertName(displayName, id, "msteams", isTeam)
+}
+
+// convertUsername returns a username from the local part of an email address.
+func convertUsername(email, id string) string {
+	local, _, _ := strings.Cut(email, "@")
+	return importconvert.ConvertUsername(local, id, "msteams")
+}
+
+// convertReaction returns the emoji of a reaction, either one of the original reactions or an emoji character.
+func convertReaction(reactionType string) (string, bool) {
+	if name, ok := reactionEmojis[reactionType]; ok {
+		return name, true
+	}
+	return importconvert.EmojiName(reactionType)
+}
+
+// markdownWriter writes the Markdown of a message, prefixing the lines of the quotes.
+type markdownWriter struct {
+	buf    bytes.Buffer
+	quotes int
+}
+
+func (w *markdownWriter) write(s string) {
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			w.buf.WriteString("\n" + strings.Repeat("> ", w.quotes))
+		}
+		w.buf.WriteString(line)
+	}
+}
+
+// newLine starts a new line, unless the message is already at the start of a line.
+func (w *markdownWriter) newLine() {
+	if w.buf.Len() > 0 && !bytes.HasSuffix(bytes.TrimRight(w.buf.Bytes(), "> "), []byte("\n")) {
+		w.write("\n")
+	}
+}
+
+type htmlList struct {
+	ordered bool
+	items   int
+}
+
+type htmlLink struct {
+	href  string
+	start int
+}
+
+// convertHTML converts the HTML body of a message to Markdown. The mentions are the texts of the <at> elements, by id.
+func convertHTML(content string, mentions map[int]string) string {
+	w := &markdownWriter{}
+	z := html.NewTokenizer(strings.NewReader(content))
+
+	var lists []*htmlList
+	var links []*htmlLink
+	pre := 0
+	// skip is the depth of the element whose content is replaced, e.g. a mention.
+	skip := 0
+	lastMention, lastMentionEnd := "", -1
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		token := z.Token()
+
+		if skip > 0 {
+			switch tt {
+			case html.StartTagToken:
+				skip++
+			case html.EndTagToken:
+				skip--
+			}
+			continue
+		}
+
+		switch tt {
+		case html.TextToken:
+			text := token.Data
+			if pre == 0 {
+				text = spaces.ReplaceAllString(text, " ")
+			}
+			w.write(text)
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			switch token.Data {
+			case "b", "strong":
+				w.write("**")
+			case "i", "em":
+				w.write("_")
+			case "s", "strike", "del":
+				w.write("~~")
+			case "code":
+				if pre == 0 {
+					w.write("`")
+				}
+			case "pre":
+				w.newLine()
+				w.write("```\n")
+				pre++
+			case "br":
+				w.write("\n")
+			case "p", "div":
+				w.newLine()
+			case "h1", "h2", "h3", "h4", "h5", "h6":
+				w.newLine()
+				level, _ := strconv.Atoi(token.Data[1:])
+				w.write(strings.Repeat("#", level) + " ")
+			case "hr":
+				// after a blank line, not to make a heading of the previous line
+				w.newLine()
+				w.write("\n---\n")
+			case "blockquote":
+				w.newLine()
+				w.quotes++
+				w.write("> ")
+			case "ul", "ol":
+				w.newLine()
+				lists = append(lists, &htmlList{ordered: token.Data == "ol"})
+			case "li":
+				w.newLine()
+				indent := ""
+				if len(lists) > 1 {
+					indent = strings.Repeat("  ", len(lists)-1)
+				}
+				if len(lists) > 0 && lists[len(lists)-1].ordered {
+					list := lists[len(lists)-1]
+					list.items++
+					w.write(indent + strconv.Itoa(list.items) + ". ")
+				} else {
+					w.write(indent + "- ")
+				}
+			case "a":
+				links = append(links, &htmlLink{href: attr(token, "href"), start: w.buf.Len()})
+			case "img":
+				if alt := attr(token, "alt"); alt != "" {
+					w.write(alt)
+				}
+			case "emoji":
+				// e.g. <emoji id="smile" alt="😄" title="Smile"></emoji>
+				w.write(attr(token, "alt"))
+				if tt == html.StartTagToken {
+					skip = 1
+				}
+			case "at":
+				id, err := strconv.Atoi(attr(token, "id"))
+				mention, ok := mentions[id]
+				if err != nil || !ok {
+					// keep the text of the unknown mentions
+					break
+				}
+				// The multi-word names are split in several mentions of the same user.
+				between := w.buf.Bytes()[min(max(lastMentionEnd, 0), w.buf.Len()):]
+				if mention != lastMention || lastMentionEnd < 0 || len(bytes.TrimSpace(between)) > 0 {
+					w.write(mention)
+				} else {
+					w.buf.Truncate(lastMentionEnd)
+				}
+				lastMention, lastMentionEnd = mention, w.buf.Len()
+				if tt == html.StartTagToken {
+					skip = 1
+				}
+			case "attachment", "script", "style":
+				if tt == html.StartTagToken {
+					skip = 1
+				}
+			}
+
+		case html.EndTagToken:
+			switch token.Data {
+			case "b", "strong":
+				w.write("**")
+			case "i", "em":
+				w.write("_")
+			case "s", "strike", "del":
+				w.write("~~")
+			case "code":
+				if pre == 0 {
+					w.write("`")
+				}
+			case "pre":
+				if pre > 0 {
+					pre--
+					w.newLine()
+					w.write("```\n")
+				}
+			case "p", "div", "h1", "h2", "h3", "h4", "h5", "h6", "li", "tr":
+				w.newLine()
+			case "blockquote":
+				if w.quotes > 0 {
+					w.quotes--
+				}
+				w.newLine()
+				w.write("\n")
+			case "ul", "ol":
+				if len(lists) > 0 {
+					lists = lists[:len(lists)-1]
+				}
+				w.newLine()
+			case "td", "th":
+				w.write(" ")
+			case "a":
+				if len(links) == 0 {
+					break
+				}
+				link := links[len(links)-1]
+				links = links[:len(links)-1]
+				text := string(w.buf.Bytes()[link.start:])
+				if link.href != "" && strings.TrimSpace(text) != link.href {
+					w.buf.Truncate(link.start)
+					w.write("[" + text + "](" + link.href + ")")
+				}
+			}
+		}
+	}
+
+	markdown := strings.ReplaceAll(w.buf.String(), "\u00a0", " ")
+	markdown = trailingSpaces.ReplaceAllString(markdown, "")
+	markdown = blankLines.ReplaceAllString(markdown, "\n\n")
+	return strings.TrimSpace(markdown)
+}
+
+func attr(token html.Token, name string) string {
+	for _, a := range token.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
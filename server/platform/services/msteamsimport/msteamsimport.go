@@ -0,0 +1,740 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Package msteamsimport converts a Microsoft Teams export to the bulk import format.
+//
+// The export is a zip file of the Microsoft Graph API responses, laid out as follows:
+//
+//	users.json                                           GET /users
+//	teams/<team id>/team.json                            GET /teams/{team-id}
+//	teams/<team id>/members.json                         GET /teams/{team-id}/members
+//	teams/<team id>/channels/<channel id>/channel.json   GET /teams/{team-id}/channels/{channel-id}
+//	teams/<team id>/channels/<channel id>/members.json   GET /teams/{team-id}/channels/{channel-id}/members
+//	teams/<team id>/channels/<channel id>/messages*.json GET /teams/{team-id}/channels/{channel-id}/messages?$expand=replies
+//	chats/<chat id>/chat.json                            GET /chats/{chat-id}?$expand=members
+//	chats/<chat id>/messages*.json                       GET /chats/{chat-id}/messages
+//	attachments/<attachment id>/<file name>              the content of the files attached to the messages
+//
+// The JSON files hold either the Graph API response, with the entities in "value", or the entities themselves. The
+// messages may be split in several files, e.g. one per page of the responses, and the replies may be expanded in their
+// message or listed with the messages, with their replyToId. The members of the standard channels are the members of
+// their team, so their members.json is optional.
+package msteamsimport
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/app/imports"
+	"github.com/mattermost/mattermost/server/v8/platform/services/importconvert"
+)
+
+// JSONLFilename is the name of the bulk import file in the converted zip file.
+const JSONLFilename = importconvert.JSONLFilename
+
+const (
+	// attachmentsDir is the directory of the attached files, in the export and in the data directory of the import.
+	attachmentsDir = "attachments"
+
+	channelTypeStandard = "standard"
+
+	chatTypeOneOnOne = "oneOnOne"
+)
+
+type graphUser struct {
+	Id                string `json:"id"`
+	DisplayName       string `json:"displayName"`
+	GivenName         string `json:"givenName"`
+	Surname           string `json:"surname"`
+	Mail              string `json:"mail"`
+	UserPrincipalName string `json:"userPrincipalName"`
+	JobTitle          string `json:"jobTitle"`
+	AccountEnabled    *bool  `json:"accountEnabled"`
+}
+
+type graphTeam struct {
+	Id          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	Description string `json:"description"`
+	Visibility  string `json:"visibility"`
+}
+
+type graphChannel struct {
+	Id             string `json:"id"`
+	DisplayName    string `json:"displayName"`
+	Description    string `json:"description"`
+	MembershipType string `json:"membershipType"`
+}
+
+type graphMember struct {
+	UserId      string   `json:"userId"`
+	DisplayName string   `json:"displayName"`
+	Roles       []string `json:"roles"`
+}
+
+func (m *graphMember) isOwner() bool {
+	for _, role := range m.Roles {
+		if role == "owner" {
+			return true
+		}
+	}
+	return false
+}
+
+type graphChat struct {
+	Id       string         `json:"id"`
+	Topic    string         `json:"topic"`
+	ChatType string         `json:"chatType"`
+	Members  []*graphMember `json:"members"`
+}
+
+type graphIdentity struct {
+	Id                       string `json:"id"`
+	DisplayName              string `json:"displayName"`
+	ConversationIdentityType string `json:"conversationIdentityType"`
+}
+
+type graphIdentitySet struct {
+	User         *graphIdentity `json:"user"`
+	Application  *graphIdentity `json:"application"`
+	Conversation *graphIdentity `json:"conversation"`
+}
+
+type graphItemBody struct {
+	ContentType string `json:"contentType"`
+	Content     string `json:"content"`
+}
+
+type graphAttachment struct {
+	Id          string `json:"id"`
+	ContentType string `json:"contentType"`
+	ContentUrl  string `json:"contentUrl"`
+	Name        string `json:"name"`
+}
+
+type graphMention struct {
+	Id          int               `json:"id"`
+	MentionText string            `json:"mentionText"`
+	Mentioned   *graphIdentitySet `json:"mentioned"`
+}
+
+type graphReaction struct {
+	ReactionType    string            `json:"reactionType"`
+	CreatedDateTime string            `json:"createdDateTime"`
+	User            *graphIdentitySet `json:"user"`
+}
+
+type graphMessage struct {
+	Id                 string             `json:"id"`
+	ReplyToId          string             `json:"replyToId"`
+	MessageType        string             `json:"messageType"`
+	CreatedDateTime    string             `json:"createdDateTime"`
+	LastEditedDateTime string             `json:"lastEditedDateTime"`
+	DeletedDateTime    string             `json:"deletedDateTime"`
+	Subject            string             `json:"subject"`
+	From               *graphIdentitySet  `json:"from"`
+	Body               graphItemBody      `json:"body"`
+	Attachments        []*graphAttachment `json:"attachments"`
+	Mentions           []*graphMention    `json:"mentions"`
+	Reactions          []*graphReaction   `json:"reactions"`
+	Replies            []*graphMessage    `json:"replies"`
+}
+
+// Options tune the conversion.
+type Options struct {
+	// Office365Auth makes the users sign in with Office 365, with their Microsoft Entra ID, instead of a password.
+	Office365Auth bool
+	// MaxPostSize is the maximum number of runes of a message, the longer messages are truncated. Defaults to
+	// model.PostMessageMaxRunesV2.
+	MaxPostSize int
+}
+
+// Report counts the converted entities, and describes what could not be converted.
+type Report = importconvert.Report
+
+type team struct {
+	dir      string
+	name     string
+	data     *imports.TeamImportData
+	members  []*graphMember
+	channels []*channel
+	names    map[string]bool
+}
+
+type channel struct {
+	dir  string
+	name string
+	data *imports.ChannelImportData
+}
+
+type chat struct {
+	dir     string
+	members []string
+	data    *imports.DirectChannelImportData
+}
+
+type converter struct {
+	opts   Options
+	report *Report
+	files  map[string]*zip.File
+	writer *importconvert.Writer
+
+	users     map[string]*importconvert.User
+	usernames map[string]bool
+	teams     []*team
+	teamNames map[string]bool
+	chats     []*chat
+
+	// unknownUsers are the authors of messages missing from users.json, warned about once.
+	unknownUsers map[string]bool
+
+	posts importconvert.PostConverter[*graphMessage]
+}
+
+// Convert reads a Microsoft Teams export and writes a bulk import zip file, with the JSONLFilename file and the attached
+// files in the model.ExportDataDir directory, ready for the import process job.
+func Convert(export *zip.Reader, w io.Writer, opts Options) (*Report, error) {
+	if opts.MaxPostSize <= 0 {
+		opts.MaxPostSize = model.PostMessageMaxRunesV2
+	}
+
+	c := &converter{
+		opts:         opts,
+		report:       &Report{},
+		files:        make(map[string]*zip.File, len(export.File)),
+		users:        make(map[string]*importconvert.User),
+		usernames:    make(map[string]bool),
+		teamNames:    make(map[string]bool),
+		unknownUsers: make(map[string]bool),
+	}
+	c.posts = importconvert.PostConverter[*graphMessage]{Report: c.report, ConvertMessage: c.convertMessage}
+	for _, f := range export.File {
+		// avoid "zip slip"
+		if strings.Contains(f.Name, "..") {
+			return nil, fmt.Errorf("invalid file name %q in the export", f.Name)
+		}
+		c.files[f.Name] = f
+	}
+
+	if err := c.readUsers(); err != nil {
+		return nil, err
+	}
+	if err := c.readTeams(); err != nil {
+		return nil, err
+	}
+	if err := c.readChats(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if c.writer, err = importconvert.NewWriter(w); err != nil {
+		return nil, err
+	}
+	if err = c.writeLines(); err != nil {
+		return nil, err
+	}
+	c.report.Attachments = c.writer.Attachments()
+	if err = c.writer.Close(); err != nil {
+		return nil, err
+	}
+	return c.report, nil
+}
+
+// subDirs returns the directories of dir holding the given file, sorted.
+func (c *converter) subDirs(dir, file string) []string {
+	var dirs []string
+	for name := range c.files {
+		rest, ok := strings.CutPrefix(name, dir)
+		if !ok {
+			continue
+		}
+		if sub, f, ok := strings.Cut(rest, "/"); ok && f == file {
+			dirs = append(dirs, dir+sub+"/")
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+func (c *converter) readUsers() error {
+	f, ok := c.files["users.json"]
+	if !ok {
+		return fmt.Errorf("users.json is missing from the export")
+	}
+	graphUsers, err := parseGraphValues[graphUser](f)
+	if err != nil {
+		return err
+	}
+
+	for _, gu := range graphUsers {
+		email := gu.Mail
+		if email == "" {
+			email = gu.UserPrincipalName
+		}
+		email = strings.ToLower(email)
+		if !model.IsValidEmail(email) {
+			c.report.Warn("User %q (%s) has no valid email address and was skipped", gu.DisplayName, gu.Id)
+			continue
+		}
+
+		username := importconvert.UniqueName(convertUsername(email, gu.Id), model.UserNameMaxLength, c.usernames)
+		data := &imports.UserImportData{
+			Username:  model.NewPointer(username),
+			Email:     model.NewPointer(email),
+			FirstName: model.NewPointer(gu.GivenName),
+			LastName:  model.NewPointer(gu.Surname),
+			Position:  model.NewPointer(gu.JobTitle),
+			Roles:     model.NewPointer(model.SystemUserRoleId),
+		}
+		if gu.GivenName == "" && gu.Surname == "" {
+			data.Nickname = model.NewPointer(gu.DisplayName)
+		}
+		if c.opts.Office365Auth {
+			data.AuthService = model.NewPointer(model.ServiceOffice365)
+			data.AuthData = model.NewPointer(gu.Id)
+		}
+		if gu.AccountEnabled != nil && !*gu.AccountEnabled {
+			data.DeleteAt = model.NewPointer(model.GetMillis())
+		}
+
+		c.users[gu.Id] = importconvert.NewUser(username, data)
+
+	}
+	c.report.Users = len(c.users)
+	return nil
+}
+
+func (c *converter) readTeams() error {
+	for _, dir := range c.subDirs("teams/", "team.json") {
+		gt, err := parseGraphEntity[graphTeam](c.files[dir+"team.json"])
+		if err != nil {
+			return err
+		}
+
+		t := &team{
+			dir:   dir,
+			name:  importconvert.UniqueName(convertName(gt.DisplayName, gt.Id, true), model.TeamNameMaxLength, c.teamNames),
+			names: make(map[string]bool),
+		}
+		teamType := model.TeamInvite
+		if gt.Visibility == "public" {
+			teamType = model.TeamOpen
+		}
+		t.data = &imports.TeamImportData{
+			Name:        model.NewPointer(t.name),
+			DisplayName: model.NewPointer(importconvert.TruncateRunes(gt.DisplayName, model.TeamDisplayNameMaxRunes)),
+			Type:        model.NewPointer(teamType),
+			Description: model.NewPointer(importconvert.TruncateRunes(gt.Description, model.TeamDescriptionMaxLength)),
+		}
+
+		if f, ok := c.files[dir+"members.json"]; ok {
+			if t.members, err = parseGraphValues[graphMember](f); err != nil {
+				return err
+			}
+		}
+		for _, member := range t.members {
+			if u, ok := c.users[member.UserId]; ok {
+				u.JoinTeam(t.name, member.isOwner())
+			}
+		}
+
+		for _, channelDir := range c.subDirs(dir+"channels/", "channel.json") {
+			if err = c.readChannel(t, channelDir); err != nil {
+				return err
+			}
+		}
+
+		c.teams = append(c.teams, t)
+	}
+	c.report.Teams = len(c.teams)
+	return nil
+}
+
+func (c *converter) readChannel(t *team, dir string) error {
+	gc, err := parseGraphEntity[graphChannel](c.files[dir+"channel.json"])
+	if err != nil {
+		return err
+	}
+
+	// The General channel of a team is the default channel of the team.
+	name := model.DefaultChannelName
+	if gc.MembershipType != channelTypeStandard || gc.DisplayName != "General" || t.names[name] {
+		name = importconvert.UniqueName(convertName(gc.DisplayName, gc.Id, false), model.ChannelNameMaxLength, t.names)
+	}
+	t.names[name] = true
+
+	channelType := model.ChannelTypePrivate
+	if gc.MembershipType == channelTypeStandard {
+		channelType = model.ChannelTypeOpen
+	}
+	ch := &channel{
+		dir:  dir,
+		name: name,
+		data: &imports.ChannelImportData{
+			Team:        model.NewPointer(t.name),
+			Name:        model.NewPointer(name),
+			DisplayName: model.NewPointer(importconvert.TruncateRunes(gc.DisplayName, model.ChannelDisplayNameMaxRunes)),
+			Type:        &channelType,
+			Purpose:     model.NewPointer(importconvert.TruncateRunes(gc.Description, model.ChannelPurposeMaxRunes)),
+		},
+	}
+
+	members := t.members
+	if f, ok := c.files[dir+"members.json"]; ok {
+		if members, err = parseGraphValues[graphMember](f); err != nil {
+			return err
+		}
+	} else if channelType == model.ChannelTypePrivate {
+		c.report.Warn("Channel %q of team %q has no members.json, its members are the members of the team", gc.DisplayName, t.name)
+	}
+	for _, member := range members {
+		if u, ok := c.users[member.UserId]; ok {
+			// The members of a shared channel may not be members of the team.
+			u.JoinChannel(t.name, name, member.isOwner())
+		}
+	}
+
+	t.channels = append(t.channels, ch)
+	c.report.Channels++
+	return nil
+}
+
+func (c *converter) readChats() error {
+	for _, dir := range c.subDirs("chats/", "chat.json") {
+		gc, err := parseGraphEntity[graphChat](c.files[dir+"chat.json"])
+		if err != nil {
+			return err
+		}
+
+		seen := make(map[string]bool)
+		var members []string
+		for _, member := range gc.Members {
+			u, ok := c.users[member.UserId]
+			if !ok {
+				c.warnUnknownUser(member.UserId, member.DisplayName)
+				continue
+			}
+			if !seen[u.Username] {
+				seen[u.Username] = true
+				members = append(members, u.Username)
+			}
+		}
+
+		switch {
+		case len(members) < 2:
+			c.report.Warn("Chat %s has less than two known members and was skipped", gc.Id)
+			continue
+		case len(members) > model.ChannelGroupMaxUsers:
+			c.report.Warn("Chat %q has %d members, more than the %d members of a group message, and was skipped", gc.Topic, len(members), model.ChannelGroupMaxUsers)
+			continue
+		case gc.ChatType == chatTypeOneOnOne && len(members) != 2:
+			c.report.Warn("One on one chat %s has %d members and was converted to a group message", gc.Id, len(members))
+		}
+
+		sort.Strings(members)
+		c.chats = append(c.chats, &chat{
+			dir:     dir,
+			members: members,
+			data: &imports.DirectChannelImportData{
+				Members: &members,
+				Header:  model.NewPointer(importconvert.TruncateRunes(gc.Topic, model.ChannelHeaderMaxRunes)),
+			},
+		})
+	}
+	c.report.DirectChannels = len(c.chats)
+	return nil
+}
+
+func (c *converter) warnUnknownUser(id, displayName string) {
+	if id == "" || c.unknownUsers[id] {
+		return
+	}
+	c.unknownUsers[id] = true
+	c.report.Warn("User %q (%s) is not in users.json, their messages and memberships were skipped", displayName, id)
+}
+
+// writeLines writes the lines in the order of the import: the teams and the channels before their members, and the
+// users before their posts.
+func (c *converter) writeLines() error {
+	write := c.writer.WriteLine
+
+	if err := write(&imports.LineImportData{Type: "version", Version: model.NewPointer(1)}); err != nil {
+		return err
+	}
+	for _, t := range c.teams {
+		if err := write(&imports.LineImportData{Type: "team", Team: t.data}); err != nil {
+			return err
+		}
+	}
+	for _, t := range c.teams {
+		for _, ch := range t.channels {
+			if err := write(&imports.LineImportData{Type: "channel", Channel: ch.data}); err != nil {
+				return err
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(c.users))
+	for id := range c.users {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return c.users[ids[i]].Username < c.users[ids[j]].Username })
+	for _, id := range ids {
+		if err := write(&imports.LineImportData{Type: "user", User: c.users[id].ImportData()}); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range c.teams {
+		for _, ch := range t.channels {
+			roots, err := c.readMessages(ch.dir)
+			if err != nil {
+				return err
+			}
+			for _, root := range roots {
+				post := c.posts.ConvertPost(root, root.Replies, t.name, ch.name, t.name+"/"+ch.name)
+				if post == nil {
+					continue
+				}
+				if err = write(&imports.LineImportData{Type: "post", Post: post}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, ch := range c.chats {
+		if err := write(&imports.LineImportData{Type: "direct_channel", DirectChannel: ch.data}); err != nil {
+			return err
+		}
+	}
+	for _, ch := range c.chats {
+		roots, err := c.readMessages(ch.dir)
+		if err != nil {
+			return err
+		}
+		for _, root := range roots {
+			post := c.posts.ConvertDirectPost(root, root.Replies, ch.members, "the chat of "+strings.Join(ch.members, ", "))
+			if post == nil {
+				continue
+			}
+			if err = write(&imports.LineImportData{Type: "direct_post", DirectPost: post}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readMessages returns the root messages of the directory, with their replies, sorted by creation time.
+func (c *converter) readMessages(dir string) ([]*graphMessage, error) {
+	var names []string
+	for name := range c.files {
+		if rest, ok := strings.CutPrefix(name, dir); ok && strings.HasPrefix(rest, "messages") && path.Ext(rest) == ".json" && !strings.Contains(rest, "/") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var messages []*graphMessage
+	for _, name := range names {
+		page, err := parseGraphValues[graphMessage](c.files[name])
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, page...)
+	}
+
+	roots := make(map[string]*graphMessage)
+	var replies []*graphMessage
+	for _, message := range messages {
+		if message.ReplyToId != "" {
+			replies = append(replies, message)
+			continue
+		}
+		if root, ok := roots[message.Id]; ok {
+			// The same message is in several pages, keep its replies.
+			message.Replies = append(message.Replies, root.Replies...)
+		}
+		roots[message.Id] = message
+	}
+	for _, reply := range replies {
+		root, ok := roots[reply.ReplyToId]
+		if !ok {
+			c.report.SkippedMessages++
+			c.report.Warn("Reply %s of the missing message %s in %s was skipped", reply.Id, reply.ReplyToId, strings.TrimSuffix(dir, "/"))
+			continue
+		}
+		root.Replies = append(root.Replies, reply)
+	}
+
+	sorted := make([]*graphMessage, 0, len(roots))
+	for _, root := range roots {
+		root.Replies = uniqueMessages(root.Replies)
+		sortMessages(root.Replies)
+		sorted = append(sorted, root)
+	}
+	sortMessages(sorted)
+	return sorted, nil
+}
+
+func uniqueMessages(messages []*graphMessage) []*graphMessage {
+	seen := make(map[string]bool, len(messages))
+	unique := messages[:0]
+	for _, message := range messages {
+		if !seen[message.Id] {
+			seen[message.Id] = true
+			unique = append(unique, message)
+		}
+	}
+	return unique
+}
+
+func sortMessages(messages []*graphMessage) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		if messages[i].CreatedDateTime != messages[j].CreatedDateTime {
+			return convertTime(messages[i].CreatedDateTime) < convertTime(messages[j].CreatedDateTime)
+		}
+		return messages[i].Id < messages[j].Id
+	})
+}
+
+// convertMessage returns the converted message, or nil if the message is skipped.
+func (c *converter) convertMessage(gm *graphMessage, where string) *importconvert.Message {
+	if gm.MessageType != "" && gm.MessageType != "message" {
+		// e.g. the system events, as members joining the channel
+		c.report.SkippedMessages++
+		return nil
+	}
+	if gm.DeletedDateTime != "" {
+		c.report.SkippedMessages++
+		return nil
+	}
+	if gm.From == nil || gm.From.User == nil {
+		c.report.SkippedMessages++
+		c.report.Warn("Message %s in %s was not sent by a user, e.g. by a bot, and was skipped", gm.Id, where)
+		return nil
+	}
+	author, ok := c.users[gm.From.User.Id]
+	if !ok {
+		c.report.SkippedMessages++
+		c.warnUnknownUser(gm.From.User.Id, gm.From.User.DisplayName)
+		return nil
+	}
+
+	createAt := convertTime(gm.CreatedDateTime)
+	if createAt == 0 {
+		c.report.SkippedMessages++
+		c.report.Warn("Message %s in %s has an invalid creation time %q and was skipped", gm.Id, where, gm.CreatedDateTime)
+		return nil
+	}
+
+	text := gm.Body.Content
+	if strings.EqualFold(gm.Body.ContentType, "html") {
+		text = convertHTML(text, c.mentions(gm))
+	}
+	if gm.Subject != "" {
+		text = strings.TrimSpace("**" + gm.Subject + "**\n" + text)
+	}
+
+	m := &importconvert.Message{
+		User:     author.Username,
+		CreateAt: createAt,
+	}
+	if editAt := convertTime(gm.LastEditedDateTime); editAt != 0 {
+		m.EditAt = model.NewPointer(editAt)
+	}
+
+	var attachments []imports.AttachmentImportData
+	for _, attachment := range gm.Attachments {
+		switch {
+		case attachment.ContentType == "reference":
+			p := path.Join(attachmentsDir, attachment.Id, path.Base(attachment.Name))
+			if f, ok := c.files[p]; ok {
+				attachments = append(attachments, c.writer.AddAttachment(p, f))
+				continue
+			}
+			c.report.Warn("File %q of message %s in %s is not in the export, it was replaced by a link", attachment.Name, gm.Id, where)
+			text += fmt.Sprintf("\n[%s](%s)", attachment.Name, attachment.ContentUrl)
+		case attachment.ContentType == "messageReference":
+			// A quoted message, already in the conversation.
+		default:
+			c.report.Warn("Attachment %q of type %s of message %s in %s is not supported and was skipped", attachment.Name, attachment.ContentType, gm.Id, where)
+		}
+	}
+	if len(attachments) > 0 {
+		m.Attachments = &attachments
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" && len(attachments) == 0 {
+		c.report.SkippedMessages++
+		return nil
+	}
+	if truncated := importconvert.TruncateRunes(text, c.opts.MaxPostSize); truncated != text {
+		c.report.Warn("Message %s in %s is longer than %d characters and was truncated", gm.Id, where, c.opts.MaxPostSize)
+		text = truncated
+	}
+	m.Text = text
+
+	var reactions []imports.ReactionImportData
+	seen := make(map[string]bool)
+	for _, reaction := range gm.Reactions {
+		if reaction.User == nil || reaction.User.User == nil {
+			continue
+		}
+		reactor, ok := c.users[reaction.User.User.Id]
+		if !ok {
+			continue
+		}
+		emojiName, ok := convertReaction(reaction.ReactionType)
+		if !ok {
+			c.report.Warn("Reaction %q of message %s in %s is not supported and was skipped", reaction.ReactionType, gm.Id, where)
+			continue
+		}
+		if key := reactor.Username + ":" + emojiName; !seen[key] {
+			seen[key] = true
+			// The reactions can't be older than their message.
+			reactionCreateAt := max(convertTime(reaction.CreatedDateTime), createAt)
+			reactions = append(reactions, imports.ReactionImportData{
+				User:      model.NewPointer(reactor.Username),
+				EmojiName: model.NewPointer(emojiName),
+				CreateAt:  model.NewPointer(reactionCreateAt),
+			})
+		}
+	}
+	if len(reactions) > 0 {
+		m.Reactions = &reactions
+	}
+
+	return m
+}
+
+// mentions returns the mentions of the message, by id, as their text in Mattermost.
+func (c *converter) mentions(gm *graphMessage) map[int]string {
+	mentions := make(map[int]string, len(gm.Mentions))
+	for _, mention := range gm.Mentions {
+		if mention.Mentioned == nil {
+			continue
+		}
+		switch {
+		case mention.Mentioned.User != nil:
+			if u, ok := c.users[mention.Mentioned.User.Id]; ok {
+				mentions[mention.Id] = "@" + u.Username
+			}
+		case mention.Mentioned.Conversation != nil:
+			switch mention.Mentioned.Conversation.ConversationIdentityType {
+			case "channel":
+				mentions[mention.Id] = "@channel"
+			case "team":
+				mentions[mention.Id] = "@all"
+			}
+		}
+	}
+	return mentions
+}
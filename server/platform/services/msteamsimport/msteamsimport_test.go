@@ -0,0 +1,308 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msteamsimport
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/app/imports"
+	"github.com/mattermost/mattermost/server/v8/platform/services/importconvert"
+)
+
+func createExport(t *testing.T, files map[string]string) *zip.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	return r
+}
+
+func readImport(t *testing.T, data []byte) ([]imports.LineImportData, map[string]string) {
+	t.Helper()
+
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	var lines []imports.LineImportData
+	files := make(map[string]string)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		if f.Name == JSONLFilename {
+			scanner := bufio.NewScanner(rc)
+			for scanner.Scan() {
+				var line imports.LineImportData
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+				lines = append(lines, line)
+			}
+			require.NoError(t, scanner.Err())
+		} else {
+			content, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			files[f.Name] = string(content)
+		}
+		require.NoError(t, rc.Close())
+	}
+	return lines, files
+}
+
+const testUsers = `{"value": [
+	{"id": "u1", "displayName": "Alice Smith", "givenName": "Alice", "surname": "Smith", "mail": "Alice.Smith@example.com"},
+	{"id": "u2", "displayName": "Bob", "userPrincipalName": "bob@example.com"},
+	{"id": "u3", "displayName": "Carol", "mail": "alice.smith@example.org", "accountEnabled": false},
+	{"id": "u4", "displayName": "No Mail"}
+]}`
+
+func TestConvert(t *testing.T) {
+	export := createExport(t, map[string]string{
+		"users.json":                        testUsers,
+		"teams/t1/team.json":                `{"id": "t1", "displayName": "Sales & Marketing", "description": "The sales team", "visibility": "public"}`,
+		"teams/t1/members.json":             `[{"userId": "u1", "roles": ["owner"]}, {"userId": "u2", "roles": []}]`,
+		"teams/t1/channels/c1/channel.json": `{"id": "c1", "displayName": "General", "membershipType": "standard"}`,
+		"teams/t1/channels/c1/messages.json": `{"value": [
+			{"id": "m1", "messageType": "message", "createdDateTime": "2024-01-01T10:00:00Z", "subject": "Hello",
+			 "from": {"user": {"id": "u1", "displayName": "Alice Smith"}},
+			 "body": {"contentType": "html", "content": "<p>Hi <at id=\"0\">Bob</at>, see <b>this</b></p>"},
+			 "mentions": [{"id": 0, "mentionText": "Bob", "mentioned": {"user": {"id": "u2"}}}],
+			 "attachments": [{"id": "a1", "contentType": "reference", "name": "report.pdf", "contentUrl": "https://example.com/report.pdf"},
+			                 {"id": "a2", "contentType": "reference", "name": "missing.pdf", "contentUrl": "https://example.com/missing.pdf"}],
+			 "reactions": [{"reactionType": "like", "createdDateTime": "2024-01-01T10:05:00Z", "user": {"user": {"id": "u2"}}},
+			               {"reactionType": "like", "createdDateTime": "2024-01-01T10:06:00Z", "user": {"user": {"id": "u2"}}}],
+			 "replies": [{"id": "m2", "replyToId": "m1", "messageType": "message", "createdDateTime": "2024-01-01T10:10:00Z",
+			              "from": {"user": {"id": "u2"}}, "body": {"contentType": "text", "content": "Thanks"}}]},
+			{"id": "m3", "messageType": "systemEventMessage", "createdDateTime": "2024-01-01T09:00:00Z"},
+			{"id": "m4", "messageType": "message", "createdDateTime": "2024-01-01T11:00:00Z", "deletedDateTime": "2024-01-01T12:00:00Z",
+			 "from": {"user": {"id": "u1"}}, "body": {"contentType": "text", "content": "deleted"}}
+		]}`,
+		"teams/t1/channels/c1/messages-2.json": `[
+			{"id": "m5", "replyToId": "m1", "messageType": "message", "createdDateTime": "2024-01-01T10:20:00Z",
+			 "from": {"user": {"id": "u1"}}, "body": {"contentType": "text", "content": "You're welcome"}}
+		]`,
+		"teams/t1/channels/c2/channel.json":  `{"id": "c2", "displayName": "Secret Project", "description": "Hush", "membershipType": "private"}`,
+		"teams/t1/channels/c2/members.json":  `[{"userId": "u2", "roles": ["owner"]}]`,
+		"teams/t1/channels/c2/messages.json": `[]`,
+		"chats/ch1/chat.json":                `{"id": "ch1", "chatType": "oneOnOne", "members": [{"userId": "u2"}, {"userId": "u1"}]}`,
+		"chats/ch1/messages.json": `[
+			{"id": "d1", "messageType": "message", "createdDateTime": "2024-01-02T10:00:00Z",
+			 "from": {"user": {"id": "u2"}}, "body": {"contentType": "text", "content": "Lunch?"},
+			 "reactions": [{"reactionType": "😂", "user": {"user": {"id": "u1"}}}]},
+			{"id": "d2", "messageType": "message", "createdDateTime": "2024-01-02T10:01:00Z",
+			 "from": {"user": {"id": "u9", "displayName": "Stranger"}}, "body": {"contentType": "text", "content": "Hi"}}
+		]`,
+		"chats/ch2/chat.json":       `{"id": "ch2", "chatType": "group", "members": [{"userId": "u1"}]}`,
+		"attachments/a1/report.pdf": "pdf content",
+	})
+
+	var buf bytes.Buffer
+	report, err := Convert(export, &buf, Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.Teams)
+	assert.Equal(t, 2, report.Channels)
+	assert.Equal(t, 3, report.Users)
+	assert.Equal(t, 1, report.Posts)
+	assert.Equal(t, 2, report.Replies)
+	assert.Equal(t, 1, report.DirectChannels)
+	assert.Equal(t, 1, report.DirectPosts)
+	assert.Equal(t, 1, report.Attachments)
+	assert.Equal(t, 3, report.SkippedMessages)
+	assert.Len(t, report.Warnings, 4)
+
+	lines, files := readImport(t, buf.Bytes())
+	assert.Equal(t, map[string]string{"data/attachments/a1/report.pdf": "pdf content"}, files)
+
+	types := make([]string, 0, len(lines))
+	for _, line := range lines {
+		types = append(types, line.Type)
+	}
+	assert.Equal(t, []string{"version", "team", "channel", "channel", "user", "user", "user", "post", "direct_channel", "direct_post"}, types)
+
+	team := lines[1].Team
+	assert.Equal(t, "sales-marketing", *team.Name)
+	assert.Equal(t, model.TeamOpen, *team.Type)
+
+	assert.Equal(t, model.DefaultChannelName, *lines[2].Channel.Name)
+	assert.Equal(t, model.ChannelTypeOpen, *lines[2].Channel.Type)
+	assert.Equal(t, "secret-project", *lines[3].Channel.Name)
+	assert.Equal(t, model.ChannelTypePrivate, *lines[3].Channel.Type)
+
+	alice := lines[4].User
+	assert.Equal(t, "alice.smith", *alice.Username)
+	assert.Equal(t, "alice.smith@example.com", *alice.Email)
+	require.NotNil(t, alice.Teams)
+	require.Len(t, *alice.Teams, 1)
+	assert.Equal(t, model.TeamUserRoleId+" "+model.TeamAdminRoleId, *(*alice.Teams)[0].Roles)
+	require.Len(t, *(*alice.Teams)[0].Channels, 1)
+	assert.Equal(t, model.DefaultChannelName, *(*(*alice.Teams)[0].Channels)[0].Name)
+
+	carol := lines[5].User
+	assert.Equal(t, "alice.smith-2", *carol.Username)
+	assert.NotNil(t, carol.DeleteAt)
+	assert.Nil(t, carol.Teams)
+
+	bob := lines[6].User
+	assert.Equal(t, "bob", *bob.Username)
+	require.Len(t, *(*bob.Teams)[0].Channels, 2)
+	assert.Equal(t, model.ChannelUserRoleId+" "+model.ChannelAdminRoleId, *(*(*bob.Teams)[0].Channels)[1].Roles)
+
+	post := lines[7].Post
+	assert.Equal(t, "sales-marketing", *post.Team)
+	assert.Equal(t, model.DefaultChannelName, *post.Channel)
+	assert.Equal(t, "alice.smith", *post.User)
+	assert.Equal(t, "**Hello**\nHi @bob, see **this**\n[missing.pdf](https://example.com/missing.pdf)", *post.Message)
+	assert.Equal(t, int64(1704103200000), *post.CreateAt)
+	require.NotNil(t, post.Attachments)
+	assert.Equal(t, "attachments/a1/report.pdf", *(*post.Attachments)[0].Path)
+	require.NotNil(t, post.Reactions)
+	require.Len(t, *post.Reactions, 1)
+	assert.Equal(t, "+1", *(*post.Reactions)[0].EmojiName)
+	require.NotNil(t, post.Replies)
+	require.Len(t, *post.Replies, 2)
+	assert.Equal(t, "Thanks", *(*post.Replies)[0].Message)
+	assert.Equal(t, "You're welcome", *(*post.Replies)[1].Message)
+
+	assert.Equal(t, []string{"alice.smith", "bob"}, *lines[8].DirectChannel.Members)
+	directPost := lines[9].DirectPost
+	assert.Equal(t, []string{"alice.smith", "bob"}, *directPost.ChannelMembers)
+	assert.Equal(t, "bob", *directPost.User)
+	require.NotNil(t, directPost.Reactions)
+	assert.Equal(t, "joy", *(*directPost.Reactions)[0].EmojiName)
+	assert.Equal(t, *directPost.CreateAt, *(*directPost.Reactions)[0].CreateAt)
+
+	for _, line := range lines {
+		var appErr *model.AppError
+		switch line.Type {
+		case "team":
+			appErr = imports.ValidateTeamImportData(line.Team)
+		case "channel":
+			appErr = imports.ValidateChannelImportData(line.Channel)
+		case "user":
+			appErr = imports.ValidateUserImportData(line.User)
+		case "post":
+			appErr = imports.ValidatePostImportData(line.Post, model.PostMessageMaxRunesV2)
+		case "direct_channel":
+			appErr = imports.ValidateDirectChannelImportData(line.DirectChannel)
+		case "direct_post":
+			appErr = imports.ValidateDirectPostImportData(line.DirectPost, model.PostMessageMaxRunesV2)
+		}
+		require.Nil(t, appErr, line.Type)
+	}
+}
+
+func TestConvertOffice365Auth(t *testing.T) {
+	export := createExport(t, map[string]string{"users.json": testUsers})
+
+	var buf bytes.Buffer
+	_, err := Convert(export, &buf, Options{Office365Auth: true})
+	require.NoError(t, err)
+
+	lines, _ := readImport(t, buf.Bytes())
+	require.Len(t, lines, 4)
+	assert.Equal(t, model.ServiceOffice365, *lines[1].User.AuthService)
+	assert.Equal(t, "u1", *lines[1].User.AuthData)
+}
+
+func TestConvertInvalidExport(t *testing.T) {
+	t.Run("missing users", func(t *testing.T) {
+		_, err := Convert(createExport(t, map[string]string{}), io.Discard, Options{})
+		require.Error(t, err)
+	})
+
+	t.Run("invalid file name", func(t *testing.T) {
+		_, err := Convert(createExport(t, map[string]string{"users.json": testUsers, "../users.json": "[]"}), io.Discard, Options{})
+		require.Error(t, err)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := Convert(createExport(t, map[string]string{"users.json": testUsers, "teams/t1/team.json": "{"}), io.Discard, Options{})
+		require.Error(t, err)
+	})
+}
+
+func TestConvertHTML(t *testing.T) {
+	mentions := map[int]string{0: "@john.doe", 1: "@john.doe", 2: "@channel"}
+
+	for _, tc := range []struct {
+		input    string
+		expected string
+	}{
+		{"<p>Hello <b>world</b>&nbsp;!</p><p>Second <i>line</i></p>", "Hello **world** !\nSecond _line_"},
+		{`<div><at id="0">John</at>&nbsp;<at id="1">Doe</at> and <at id="2">General</at></div>`, "@john.doe and @channel"},
+		{`<at id="5">Unknown</at>`, "Unknown"},
+		{`<a href="https://example.com">a link</a> and <a href="https://example.com">https://example.com</a>`, "[a link](https://example.com) and https://example.com"},
+		{"<ul><li>one</li><li>two<ol><li>a</li><li>b</li></ol></li></ul><p>after</p>", "- one\n- two\n  1. a\n  2. b\nafter"},
+		{"<blockquote>quoted<br>text</blockquote><p>reply</p>", "> quoted\n> text\n\nreply"},
+		{"<pre><code>x := 1\ny  := 2</code></pre>", "```\nx := 1\ny  := 2\n```"},
+		{`<p>hi <emoji id="smile" alt="😄" title="Smile"></emoji><attachment id="a1"></attachment></p>`, "hi 😄"},
+		{"<p>text</p><hr><h2>Title</h2>", "text\n\n---\n## Title"},
+		{"<s>old</s> <code>new</code>", "~~old~~ `new`"},
+	} {
+		assert.Equal(t, tc.expected, convertHTML(tc.input, mentions), tc.input)
+	}
+}
+
+func TestConvertName(t *testing.T) {
+	for _, tc := range []struct {
+		displayName string
+		isTeam      bool
+		expected    string
+	}{
+		{"Sales & Marketing", true, "sales-marketing"},
+		{"  --Project X--  ", false, "project-x"},
+		{"a", false, "msteams-" + importconvert.ShortHash("id")},
+		{"Équipe", false, "quipe"},
+		{"日本", true, "msteams-" + importconvert.ShortHash("id")},
+		{"Signup", true, "msteams-" + importconvert.ShortHash("id")},
+		{"Signup", false, "signup"},
+	} {
+		assert.Equal(t, tc.expected, convertName(tc.displayName, "id", tc.isTeam), tc.displayName)
+	}
+}
+
+func TestConvertUsername(t *testing.T) {
+	assert.Equal(t, "john.doe", convertUsername("John.Doe@example.com", "id"))
+	assert.Equal(t, "john-doe", convertUsername("john+doe@example.com", "id"))
+	assert.Equal(t, "msteams-"+importconvert.ShortHash("id"), convertUsername("+@example.com", "id"))
+	assert.Equal(t, "msteams-"+importconvert.ShortHash("id"), convertUsername("all@example.com", "id"))
+}
+
+func TestConvertReaction(t *testing.T) {
+	for _, tc := range []struct {
+		reactionType string
+		expected     string
+	}{
+		{"like", "+1"},
+		{"heart", "heart"},
+		{"laugh", "laughing"},
+		{"👍", "+1"},
+		{"❤️", "heart"},
+		{"❤", "heart"},
+	} {
+		emojiName, ok := convertReaction(tc.reactionType)
+		assert.True(t, ok, tc.reactionType)
+		assert.Equal(t, tc.expected, emojiName, tc.reactionType)
+	}
+
+	_, ok := convertReaction("unknown")
+	assert.False(t, ok)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msteamsimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// graphCollection is a response of the Graph API listing entities.
+type graphCollection[T any] struct {
+	Value []*T `json:"value"`
+}
+
+func readFile(f *zip.File) ([]byte, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	// Some tools write the files with a byte order mark.
+	return bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), nil
+}
+
+// parseGraphValues parses a file holding either a Graph API collection, or an array of entities.
+func parseGraphValues[T any](f *zip.File) ([]*T, error) {
+	data, err := readFile(f)
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		var values []*T
+		if err = json.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", f.Name, err)
+		}
+		return values, nil
+	}
+
+	var collection graphCollection[T]
+	if err = json.Unmarshal(data, &collection); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", f.Name, err)
+	}
+	return collection.Value, nil
+}
+
+// parseGraphEntity parses a file holding a single entity.
+func parseGraphEntity[T any](f *zip.File) (*T, error) {
+	data, err := readFile(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var entity T
+	if err = json.Unmarshal(data, &entity); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", f.Name, err)
+	}
+	return &entity, nil
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package rocketchatimport
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// code matches the code blocks and spans, whose text is not converted.
+	code           = regexp.MustCompile("(?s)```.*?```|`[^`\n]*`")
+	bold           = regexp.MustCompile(`(^|[\s(>])\*([^*\s](?:[^*\n]*[^*\s])?)\*($|[\s).,!?:;])`)
+	strikethrough  = regexp.MustCompile(`(^|[\s(>])~([^~\s](?:[^~\n]*[^~\s])?)~($|[\s).,!?:;])`)
+	userMention    = regexp.MustCompile(`(^|[^\w@])@([\w.\-]+)`)
+	channelMention = regexp.MustCompile(`(^|[^\w#])#([\w.\-]+)`)
+)
+
+// convertText converts the Markdown of a message. Rocket.Chat marks the bold text with single asterisks and the
+// struck text with single tildes, and the mentions of the users and channels with their Rocket.Chat names.
+func (c *converter) convertText(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range code.FindAllStringIndex(text, -1) {
+		b.WriteString(c.convertMarkdown(text[last:loc[0]]))
+		b.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(c.convertMarkdown(text[last:]))
+	return b.String()
+}
+
+func (c *converter) convertMarkdown(text string) string {
+	// The adjacent marks share the space between them, they are replaced until none is left.
+	for _, mark := range []struct {
+		re   *regexp.Regexp
+		repl string
+	}{{bold, "$1**$2**$3"}, {strikethrough, "$1~~$2~~$3"}} {
+		for replaced := mark.re.ReplaceAllString(text, mark.repl); replaced != text; replaced = mark.re.ReplaceAllString(text, mark.repl) {
+			text = replaced
+		}
+	}
+
+	text = userMention.ReplaceAllStringFunc(text, func(s string) string {
+		match := userMention.FindStringSubmatch(s)
+		// The mention may be followed by a period ending the sentence.
+		name := match[2]
+		suffix := ""
+		for strings.HasSuffix(name, ".") {
+			name, suffix = name[:len(name)-1], suffix+"."
+		}
+		if username, ok := c.usernames[name]; ok {
+			return match[1] + "@" + username + suffix
+		}
+		return s
+	})
+	text = channelMention.ReplaceAllStringFunc(text, func(s string) string {
+		match := channelMention.FindStringSubmatch(s)
+		name := match[2]
+		suffix := ""
+		for strings.HasSuffix(name, ".") {
+			name, suffix = name[:len(name)-1], suffix+"."
+		}
+		if channelName, ok := c.roomNames[name]; ok {
+			return match[1] + "~" + channelName + suffix
+		}
+		return s
+	})
+	return text
+}
@@ -0,0 +1,663 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Package rocketchatimport converts a Rocket.Chat export to the bulk import format.
+//
+// The export is a zip file of the collections of the Rocket.Chat MongoDB database exported with mongoexport, in any
+// directory: users.json, rocketchat_room.json and rocketchat_message.json, and rocketchat_subscription.json for the
+// members of the channels. The collections are read one document per line, or as a JSON array when exported with
+// --jsonArray. The uploaded files are read from the uploads directory, named after their id as in the FileSystem
+// storage of Rocket.Chat.
+//
+// Rocket.Chat has no teams, the channels are converted to the channels of a single team. The public and private
+// channels and the discussions are converted to channels, the direct messages to direct and group messages, and the
+// threads to replies. The omnichannel rooms are not converted.
+package rocketchatimport
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/app/imports"
+	"github.com/mattermost/mattermost/server/v8/platform/services/importconvert"
+)
+
+const (
+	// attachmentsDir is the directory of the attached files in the data directory of the import.
+	attachmentsDir = "attachments"
+	// uploadsDir is the directory of the uploaded files in the export.
+	uploadsDir = "uploads"
+
+	usersFile         = "users.json"
+	roomsFile         = "rocketchat_room.json"
+	subscriptionsFile = "rocketchat_subscription.json"
+	messagesFile      = "rocketchat_message.json"
+
+	roomTypeChannel = "c"
+	roomTypePrivate = "p"
+	roomTypeDirect  = "d"
+
+	// generalRoomId is the id of the default channel of Rocket.Chat.
+	generalRoomId = "GENERAL"
+
+	// defaultEmailDomain is the domain of the email addresses of the users without one by default, as for the Slack
+	// import.
+	defaultEmailDomain = "example.com"
+)
+
+// mongoDate is a date of the MongoDB Extended JSON written by mongoexport, in milliseconds. The dates are written in
+// the relaxed or canonical mode, e.g. {"$date": "2024-01-01T10:00:00.000Z"} or {"$date": {"$numberLong": "1704103200000"}}.
+type mongoDate int64
+
+func (d *mongoDate) UnmarshalJSON(data []byte) error {
+	var v struct {
+		Date json.RawMessage `json:"$date"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if len(v.Date) == 0 {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(v.Date, &s); err == nil {
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return fmt.Errorf("invalid date %s: %w", s, err)
+		}
+		*d = mongoDate(t.UnixMilli())
+		return nil
+	}
+	var canonical struct {
+		NumberLong string `json:"$numberLong"`
+	}
+	if err := json.Unmarshal(v.Date, &canonical); err == nil && canonical.NumberLong != "" {
+		ms, err := strconv.ParseInt(canonical.NumberLong, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid date %s: %w", canonical.NumberLong, err)
+		}
+		*d = mongoDate(ms)
+		return nil
+	}
+	var ms int64
+	if err := json.Unmarshal(v.Date, &ms); err != nil {
+		return fmt.Errorf("invalid date %s", v.Date)
+	}
+	*d = mongoDate(ms)
+	return nil
+}
+
+type rcUserRef struct {
+	Id       string `json:"_id"`
+	Username string `json:"username"`
+}
+
+type rcEmail struct {
+	Address string `json:"address"`
+}
+
+type rcUser struct {
+	Id       string    `json:"_id"`
+	Username string    `json:"username"`
+	Name     string    `json:"name"`
+	Emails   []rcEmail `json:"emails"`
+	Active   *bool     `json:"active"`
+	// Type is user, bot or app.
+	Type string `json:"type"`
+}
+
+type rcRoom struct {
+	Id          string `json:"_id"`
+	Type        string `json:"t"`
+	Name        string `json:"name"`
+	Fname       string `json:"fname"`
+	Topic       string `json:"topic"`
+	Description string `json:"description"`
+	// Usernames are the members of the direct messages.
+	Usernames []string `json:"usernames"`
+	Archived  bool     `json:"archived"`
+}
+
+type rcSubscription struct {
+	RoomId string    `json:"rid"`
+	User   rcUserRef `json:"u"`
+	Roles  []string  `json:"roles"`
+}
+
+type rcFile struct {
+	Id   string `json:"_id"`
+	Name string `json:"name"`
+}
+
+type rcReaction struct {
+	Usernames []string `json:"usernames"`
+}
+
+type rcMessage struct {
+	Id        string                `json:"_id"`
+	RoomId    string                `json:"rid"`
+	Msg       string                `json:"msg"`
+	Ts        mongoDate             `json:"ts"`
+	User      rcUserRef             `json:"u"`
+	Type      string                `json:"t"`
+	ThreadId  string                `json:"tmid"`
+	EditedAt  mongoDate             `json:"editedAt"`
+	Pinned    bool                  `json:"pinned"`
+	Reactions map[string]rcReaction `json:"reactions"`
+	File      *rcFile               `json:"file"`
+	Files     []rcFile              `json:"files"`
+	Hidden    bool                  `json:"_hidden"`
+}
+
+// Options tune the conversion.
+type Options struct {
+	// TeamName is the name of the team of the channels, required.
+	TeamName string
+	// TeamDisplayName is the display name of the team. Defaults to the name of the team.
+	TeamDisplayName string
+	// EmailDomain is the domain of the email addresses of the users without one. Defaults to example.com.
+	EmailDomain string
+	// MaxPostSize is the maximum number of runes of a message, the longer messages are truncated. Defaults to
+	// model.PostMessageMaxRunesV2.
+	MaxPostSize int
+}
+
+// Report counts the converted entities, and describes what could not be converted.
+type Report = importconvert.Report
+
+// thread is a root message with its replies.
+type thread struct {
+	root    *rcMessage
+	replies []*rcMessage
+}
+
+// room is a converted channel, or direct message when it has members.
+type room struct {
+	name        string
+	members     []string
+	channelData *imports.ChannelImportData
+	directData  *imports.DirectChannelImportData
+	threads     []*thread
+	// threadOf is the thread of the messages, by id.
+	threadOf map[string]*thread
+}
+
+type converter struct {
+	opts    Options
+	report  *Report
+	files   map[string]*zip.File
+	uploads map[string]*zip.File
+	writer  *importconvert.Writer
+
+	users map[string]*importconvert.User
+	// usernames are the converted usernames by Rocket.Chat username, for the mentions, reactions and direct messages.
+	usernames map[string]string
+	// used are the converted usernames already taken.
+	used map[string]bool
+
+	rooms        map[string]*room
+	channels     []*room
+	chats        []*room
+	channelNames map[string]bool
+	// roomNames are the converted channel names by Rocket.Chat room name, for the channel mentions.
+	roomNames map[string]string
+
+	// unknownEmojis are the emojis of the reactions that could not be converted, warned about once.
+	unknownEmojis map[string]bool
+
+	posts importconvert.PostConverter[*rcMessage]
+}
+
+// Convert reads a Rocket.Chat export and writes a bulk import zip file, with the JSONL file and the attached files in
+// the model.ExportDataDir directory, ready for the import process job.
+func Convert(export *zip.Reader, w io.Writer, opts Options) (*Report, error) {
+	if !model.IsValidTeamName(opts.TeamName) {
+		return nil, fmt.Errorf("invalid team name %q", opts.TeamName)
+	}
+	if opts.TeamDisplayName == "" {
+		opts.TeamDisplayName = opts.TeamName
+	}
+	if opts.EmailDomain == "" {
+		opts.EmailDomain = defaultEmailDomain
+	}
+	if opts.MaxPostSize <= 0 {
+		opts.MaxPostSize = model.PostMessageMaxRunesV2
+	}
+
+	c := &converter{
+		opts:          opts,
+		report:        &Report{},
+		files:         make(map[string]*zip.File),
+		uploads:       make(map[string]*zip.File),
+		users:         make(map[string]*importconvert.User),
+		usernames:     make(map[string]string),
+		used:          make(map[string]bool),
+		rooms:         make(map[string]*room),
+		channelNames:  make(map[string]bool),
+		roomNames:     make(map[string]string),
+		unknownEmojis: make(map[string]bool),
+	}
+	c.posts = importconvert.PostConverter[*rcMessage]{Report: c.report, ConvertMessage: c.convertMessage}
+	for _, f := range export.File {
+		// avoid "zip slip"
+		if strings.Contains(f.Name, "..") {
+			return nil, fmt.Errorf("invalid file name %q in the export", f.Name)
+		}
+		if path.Base(path.Dir(f.Name)) == uploadsDir {
+			c.uploads[path.Base(f.Name)] = f
+		} else if _, ok := c.files[path.Base(f.Name)]; !ok {
+			c.files[path.Base(f.Name)] = f
+		}
+	}
+
+	users, err := readCollection[rcUser](c.files[usersFile], usersFile, true)
+	if err != nil {
+		return nil, err
+	}
+	rooms, err := readCollection[rcRoom](c.files[roomsFile], roomsFile, true)
+	if err != nil {
+		return nil, err
+	}
+	subscriptions, err := readCollection[rcSubscription](c.files[subscriptionsFile], subscriptionsFile, false)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := readCollection[rcMessage](c.files[messagesFile], messagesFile, true)
+	if err != nil {
+		return nil, err
+	}
+
+	c.readUsers(users)
+	c.readRooms(rooms, subscriptions)
+	c.readMessages(messages, subscriptions == nil)
+
+	if c.writer, err = importconvert.NewWriter(w); err != nil {
+		return nil, err
+	}
+	if err = c.writeLines(); err != nil {
+		return nil, err
+	}
+	c.report.Attachments = c.writer.Attachments()
+	if err = c.writer.Close(); err != nil {
+		return nil, err
+	}
+	return c.report, nil
+}
+
+// readCollection reads the documents of a collection exported by mongoexport, one document per line or as a JSON
+// array.
+func readCollection[T any](f *zip.File, name string, required bool) ([]*T, error) {
+	if f == nil {
+		if required {
+			return nil, fmt.Errorf("the export has no %s file", name)
+		}
+		return nil, nil
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	r := bufio.NewReader(rc)
+	var first byte
+	for {
+		if first, err = r.ReadByte(); err == io.EOF {
+			return []*T{}, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+		if !unicode.IsSpace(rune(first)) {
+			break
+		}
+	}
+	if err = r.UnreadByte(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+
+	documents := []*T{}
+	decoder := json.NewDecoder(r)
+	if first == '[' {
+		if err = decoder.Decode(&documents); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", f.Name, err)
+		}
+		return documents, nil
+	}
+	for decoder.More() {
+		var document T
+		if err = decoder.Decode(&document); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", f.Name, err)
+		}
+		documents = append(documents, &document)
+	}
+	return documents, nil
+}
+
+func (c *converter) readUsers(users []*rcUser) {
+	for _, ru := range users {
+		if ru.Id == "" || ru.Username == "" {
+			continue
+		}
+
+		username := importconvert.UniqueName(importconvert.ConvertUsername(ru.Username, ru.Id, "rocketchat"), model.UserNameMaxLength, c.used)
+		c.usernames[ru.Username] = username
+
+		email := ""
+		if len(ru.Emails) > 0 {
+			email = strings.ToLower(ru.Emails[0].Address)
+		}
+		if !model.IsValidEmail(email) {
+			email = username + "@" + c.opts.EmailDomain
+		}
+		data := &imports.UserImportData{
+			Username: model.NewPointer(username),
+			Email:    model.NewPointer(email),
+			Roles:    model.NewPointer(model.SystemUserRoleId),
+		}
+		if firstName, lastName, _ := strings.Cut(strings.TrimSpace(ru.Name), " "); firstName != "" {
+			data.FirstName = model.NewPointer(importconvert.TruncateRunes(firstName, model.UserFirstNameMaxRunes))
+			data.LastName = model.NewPointer(importconvert.TruncateRunes(strings.TrimSpace(lastName), model.UserLastNameMaxRunes))
+		}
+		if (ru.Active != nil && !*ru.Active) || ru.Type == "bot" || ru.Type == "app" {
+			// The deactivated users and the bots are deactivated, their messages are kept.
+			data.DeleteAt = model.NewPointer(model.GetMillis())
+		}
+
+		u := importconvert.NewUser(username, data)
+		u.JoinTeam(c.opts.TeamName, false)
+		c.users[ru.Id] = u
+	}
+	c.report.Users = len(c.users)
+}
+
+func (c *converter) readRooms(rooms []*rcRoom, subscriptions []*rcSubscription) {
+	// The general channel is the default channel of the team.
+	c.channelNames[model.DefaultChannelName] = true
+	sort.SliceStable(rooms, func(i, j int) bool { return rooms[i].Id == generalRoomId && rooms[j].Id != generalRoomId })
+
+	for _, rr := range rooms {
+		switch rr.Type {
+		case roomTypeChannel, roomTypePrivate:
+			c.addChannel(rr)
+		case roomTypeDirect:
+			c.addChat(rr)
+		default:
+			c.report.Warn("Room %s of type %q is not supported and was skipped", rr.Id, rr.Type)
+		}
+	}
+
+	for _, s := range subscriptions {
+		r, ok := c.rooms[s.RoomId]
+		if !ok || r.channelData == nil {
+			continue
+		}
+		u, ok := c.users[s.User.Id]
+		if !ok {
+			continue
+		}
+		admin := false
+		for _, role := range s.Roles {
+			admin = admin || role == "owner" || role == "moderator"
+		}
+		u.JoinChannel(c.opts.TeamName, r.name, admin)
+	}
+
+	c.report.Channels = len(c.channels)
+	c.report.DirectChannels = len(c.chats)
+}
+
+func (c *converter) addChannel(rr *rcRoom) {
+	name := model.DefaultChannelName
+	if rr.Id != generalRoomId {
+		name = importconvert.UniqueName(importconvert.ConvertName(rr.Name, rr.Id, "rocketchat", false), model.ChannelNameMaxLength, c.channelNames)
+	}
+	c.roomNames[rr.Name] = name
+
+	displayName := rr.Fname
+	if displayName == "" {
+		displayName = rr.Name
+	}
+	channelType := model.ChannelTypeOpen
+	if rr.Type == roomTypePrivate {
+		channelType = model.ChannelTypePrivate
+	}
+	data := &imports.ChannelImportData{
+		Team:        model.NewPointer(c.opts.TeamName),
+		Name:        model.NewPointer(name),
+		DisplayName: model.NewPointer(importconvert.TruncateRunes(displayName, model.ChannelDisplayNameMaxRunes)),
+		Type:        &channelType,
+		Header:      model.NewPointer(importconvert.TruncateRunes(rr.Topic, model.ChannelHeaderMaxRunes)),
+		Purpose:     model.NewPointer(importconvert.TruncateRunes(rr.Description, model.ChannelPurposeMaxRunes)),
+	}
+	if rr.Archived {
+		data.DeletedAt = model.NewPointer(model.GetMillis())
+	}
+
+	r := &room{name: name, channelData: data, threadOf: make(map[string]*thread)}
+	c.rooms[rr.Id] = r
+	c.channels = append(c.channels, r)
+}
+
+func (c *converter) addChat(rr *rcRoom) {
+	seen := make(map[string]bool)
+	var members []string
+	for _, rcUsername := range rr.Usernames {
+		if username, ok := c.usernames[rcUsername]; ok && !seen[username] {
+			seen[username] = true
+			members = append(members, username)
+		}
+	}
+	switch {
+	case len(members) < 2:
+		c.report.Warn("Direct message %s has less than two known members and was skipped", rr.Id)
+		return
+	case len(members) > model.ChannelGroupMaxUsers:
+		c.report.Warn("Direct message %s has %d members, more than the %d members of a group message, and was skipped", rr.Id, len(members), model.ChannelGroupMaxUsers)
+		return
+	}
+	sort.Strings(members)
+
+	r := &room{
+		members: members,
+		directData: &imports.DirectChannelImportData{
+			Members: &members,
+			Header:  model.NewPointer(importconvert.TruncateRunes(rr.Topic, model.ChannelHeaderMaxRunes)),
+		},
+		threadOf: make(map[string]*thread),
+	}
+	c.rooms[rr.Id] = r
+	c.chats = append(c.chats, r)
+}
+
+// readMessages adds the messages to the threads of their rooms. Without the subscriptions, the members of the
+// channels are the authors of their messages.
+func (c *converter) readMessages(messages []*rcMessage, joinAuthors bool) {
+	sort.SliceStable(messages, func(i, j int) bool { return messages[i].Ts < messages[j].Ts })
+
+	for _, m := range messages {
+		r, ok := c.rooms[m.RoomId]
+		// The system messages have a type, as the users joining, and the previous versions of the edited messages
+		// are hidden.
+		if !ok || m.Type != "" || m.Hidden {
+			c.report.SkippedMessages++
+			continue
+		}
+
+		if t, ok := r.threadOf[m.ThreadId]; ok && m.ThreadId != "" {
+			t.replies = append(t.replies, m)
+		} else {
+			t = &thread{root: m}
+			r.threads = append(r.threads, t)
+			r.threadOf[m.Id] = t
+		}
+
+		if u, ok := c.users[m.User.Id]; ok && joinAuthors && r.channelData != nil {
+			u.JoinChannel(c.opts.TeamName, r.name, false)
+		}
+	}
+}
+
+// writeLines writes the lines in the order of the import: the team and the channels before their members, and the
+// users before their posts.
+func (c *converter) writeLines() error {
+	write := c.writer.WriteLine
+
+	if err := write(&imports.LineImportData{Type: "version", Version: model.NewPointer(1)}); err != nil {
+		return err
+	}
+	team := &imports.TeamImportData{
+		Name:        model.NewPointer(c.opts.TeamName),
+		DisplayName: model.NewPointer(importconvert.TruncateRunes(c.opts.TeamDisplayName, model.TeamDisplayNameMaxRunes)),
+		Type:        model.NewPointer(model.TeamInvite),
+	}
+	if err := write(&imports.LineImportData{Type: "team", Team: team}); err != nil {
+		return err
+	}
+	c.report.Teams = 1
+	for _, r := range c.channels {
+		if err := write(&imports.LineImportData{Type: "channel", Channel: r.channelData}); err != nil {
+			return err
+		}
+	}
+
+	ids := make([]string, 0, len(c.users))
+	for id := range c.users {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return c.users[ids[i]].Username < c.users[ids[j]].Username })
+	for _, id := range ids {
+		if err := write(&imports.LineImportData{Type: "user", User: c.users[id].ImportData()}); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range c.channels {
+		for _, t := range r.threads {
+			post := c.posts.ConvertPost(t.root, t.replies, c.opts.TeamName, r.name, r.name)
+			if post == nil {
+				continue
+			}
+			if err := write(&imports.LineImportData{Type: "post", Post: post}); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, r := range c.chats {
+		if err := write(&imports.LineImportData{Type: "direct_channel", DirectChannel: r.directData}); err != nil {
+			return err
+		}
+	}
+	for _, r := range c.chats {
+		for _, t := range r.threads {
+			post := c.posts.ConvertDirectPost(t.root, t.replies, r.members, "the direct message of "+strings.Join(r.members, ", "))
+			if post == nil {
+				continue
+			}
+			if err := write(&imports.LineImportData{Type: "direct_post", DirectPost: post}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// convertMessage returns the converted message, or nil if the message is skipped.
+func (c *converter) convertMessage(rm *rcMessage, where string) *importconvert.Message {
+	author, ok := c.users[rm.User.Id]
+	if !ok {
+		c.report.SkippedMessages++
+		return nil
+	}
+	if rm.Ts == 0 {
+		c.report.SkippedMessages++
+		c.report.Warn("Message %s in %s has no timestamp and was skipped", rm.Id, where)
+		return nil
+	}
+
+	m := &importconvert.Message{
+		User:     author.Username,
+		CreateAt: int64(rm.Ts),
+	}
+	if rm.EditedAt != 0 {
+		m.EditAt = model.NewPointer(int64(rm.EditedAt))
+	}
+	if rm.Pinned {
+		m.IsPinned = model.NewPointer(true)
+	}
+
+	files := rm.Files
+	if len(files) == 0 && rm.File != nil {
+		files = []rcFile{*rm.File}
+	}
+	var attachments []imports.AttachmentImportData
+	for _, file := range files {
+		f, ok := c.uploads[file.Id]
+		if !ok {
+			c.report.Warn("File %q of message %s in %s is not in the export and was skipped", file.Name, rm.Id, where)
+			continue
+		}
+		p := path.Join(attachmentsDir, file.Id, path.Base(file.Name))
+		attachments = append(attachments, c.writer.AddAttachment(p, f))
+	}
+	if len(attachments) > 0 {
+		m.Attachments = &attachments
+	}
+
+	text := strings.TrimSpace(c.convertText(rm.Msg))
+	if text == "" && len(attachments) == 0 {
+		c.report.SkippedMessages++
+		return nil
+	}
+	if truncated := importconvert.TruncateRunes(text, c.opts.MaxPostSize); truncated != text {
+		c.report.Warn("Message %s in %s is longer than %d characters and was truncated", rm.Id, where, c.opts.MaxPostSize)
+		text = truncated
+	}
+	m.Text = text
+
+	emojis := make([]string, 0, len(rm.Reactions))
+	for emoji := range rm.Reactions {
+		emojis = append(emojis, emoji)
+	}
+	sort.Strings(emojis)
+	var reactions []imports.ReactionImportData
+	for _, emoji := range emojis {
+		emojiName := strings.Trim(emoji, ":")
+		if !model.IsSystemEmojiName(emojiName) {
+			if !c.unknownEmojis[emojiName] {
+				c.unknownEmojis[emojiName] = true
+				c.report.Warn("The reactions with the emoji %q are not supported and were skipped", emojiName)
+			}
+			continue
+		}
+		for _, rcUsername := range rm.Reactions[emoji].Usernames {
+			username, ok := c.usernames[rcUsername]
+			if !ok {
+				continue
+			}
+			// Rocket.Chat doesn't store the time of the reactions.
+			reactions = append(reactions, imports.ReactionImportData{
+				User:      model.NewPointer(username),
+				EmojiName: model.NewPointer(emojiName),
+				CreateAt:  model.NewPointer(m.CreateAt),
+			})
+		}
+	}
+	if len(reactions) > 0 {
+		m.Reactions = &reactions
+	}
+
+	return m
+}
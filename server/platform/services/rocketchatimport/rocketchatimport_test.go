@@ -0,0 +1,253 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package rocketchatimport
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/channels/app/imports"
+	"github.com/mattermost/mattermost/server/v8/platform/services/importconvert"
+)
+
+func createExport(t *testing.T, files map[string]string) *zip.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	return r
+}
+
+func readImport(t *testing.T, data []byte) ([]imports.LineImportData, map[string]string) {
+	t.Helper()
+
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	var lines []imports.LineImportData
+	files := make(map[string]string)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		if f.Name == importconvert.JSONLFilename {
+			scanner := bufio.NewScanner(rc)
+			for scanner.Scan() {
+				var line imports.LineImportData
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+				lines = append(lines, line)
+			}
+			require.NoError(t, scanner.Err())
+		} else {
+			content, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			files[f.Name] = string(content)
+		}
+		require.NoError(t, rc.Close())
+	}
+	return lines, files
+}
+
+const (
+	testUsers = `{"_id":"u1","username":"Alice","name":"Alice Smith","emails":[{"address":"Alice@Example.com","verified":true}],"active":true,"type":"user"}
+{"_id":"u2","username":"bob","name":"Bob","active":true,"type":"user"}
+{"_id":"rocket.cat","username":"rocket.cat","name":"Rocket.Cat","active":true,"type":"bot"}
+`
+	testRooms = `[
+		{"_id":"r1","t":"p","name":"secret","fname":"Secret Project","topic":"Hush","description":"The project"},
+		{"_id":"GENERAL","t":"c","name":"general","default":true},
+		{"_id":"u1u2","t":"d","usernames":["bob","Alice"]},
+		{"_id":"l1","t":"l","name":"visitor"}
+	]`
+)
+
+func TestConvert(t *testing.T) {
+	export := createExport(t, map[string]string{
+		"dump/users.json":                   testUsers,
+		"dump/rocketchat_room.json":         testRooms,
+		"dump/rocketchat_subscription.json": `{"rid":"r1","u":{"_id":"u2","username":"bob"},"roles":["owner"]}` + "\n" + `{"rid":"GENERAL","u":{"_id":"u1","username":"Alice"}}`,
+		"dump/rocketchat_message.json": `
+{"_id":"m1","rid":"GENERAL","msg":"Hi @Alice, see #secret: *this* and *that* ~not~ ` + "`*code*`" + `","ts":{"$date":"2024-01-01T10:00:00.000Z"},"u":{"_id":"u2","username":"bob"},"editedAt":{"$date":{"$numberLong":"1704105000000"}},"pinned":true,"reactions":{":thumbsup:":{"usernames":["Alice","bob"]},":partyparrot:":{"usernames":["Alice"]}}}
+{"_id":"m2","rid":"GENERAL","msg":"thanks","tmid":"m1","ts":{"$date":1704103260000},"u":{"_id":"u1","username":"Alice"}}
+{"_id":"m3","rid":"GENERAL","msg":"Alice","t":"uj","ts":{"$date":"2024-01-01T09:00:00.000Z"},"u":{"_id":"u1","username":"Alice"}}
+{"_id":"m4","rid":"r1","msg":"","file":{"_id":"f1","name":"plan.pdf"},"files":[{"_id":"f1","name":"plan.pdf"}],"ts":{"$date":"2024-01-01T11:00:00.000Z"},"u":{"_id":"u2","username":"bob"}}
+{"_id":"m5","rid":"r1","msg":"missing file","file":{"_id":"f2","name":"gone.pdf"},"ts":{"$date":"2024-01-01T11:01:00.000Z"},"u":{"_id":"u2","username":"bob"}}
+{"_id":"m6","rid":"u1u2","msg":"Lunch?","ts":{"$date":"2024-01-02T10:00:00.000Z"},"u":{"_id":"u1","username":"Alice"}}
+{"_id":"m7","rid":"l1","msg":"help","ts":{"$date":"2024-01-02T10:00:00.000Z"},"u":{"_id":"v1","username":"visitor"}}
+{"_id":"m8","rid":"GENERAL","msg":"old","_hidden":true,"ts":{"$date":"2024-01-01T10:00:00.000Z"},"u":{"_id":"u2","username":"bob"}}
+`,
+		"dump/uploads/f1": "pdf content",
+	})
+
+	var buf bytes.Buffer
+	report, err := Convert(export, &buf, Options{TeamName: "community", TeamDisplayName: "Community"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.Teams)
+	assert.Equal(t, 2, report.Channels)
+	assert.Equal(t, 3, report.Users)
+	assert.Equal(t, 3, report.Posts)
+	assert.Equal(t, 1, report.Replies)
+	assert.Equal(t, 1, report.DirectChannels)
+	assert.Equal(t, 1, report.DirectPosts)
+	assert.Equal(t, 1, report.Attachments)
+	assert.Equal(t, 3, report.SkippedMessages)
+	assert.Len(t, report.Warnings, 3)
+
+	lines, files := readImport(t, buf.Bytes())
+	assert.Equal(t, map[string]string{"data/attachments/f1/plan.pdf": "pdf content"}, files)
+
+	types := make([]string, 0, len(lines))
+	for _, line := range lines {
+		types = append(types, line.Type)
+	}
+	assert.Equal(t, []string{"version", "team", "channel", "channel", "user", "user", "user", "post", "post", "post", "direct_channel", "direct_post"}, types)
+
+	assert.Equal(t, "community", *lines[1].Team.Name)
+	assert.Equal(t, "Community", *lines[1].Team.DisplayName)
+
+	general := lines[2].Channel
+	assert.Equal(t, model.DefaultChannelName, *general.Name)
+	assert.Equal(t, model.ChannelTypeOpen, *general.Type)
+	secret := lines[3].Channel
+	assert.Equal(t, "secret", *secret.Name)
+	assert.Equal(t, "Secret Project", *secret.DisplayName)
+	assert.Equal(t, model.ChannelTypePrivate, *secret.Type)
+	assert.Equal(t, "Hush", *secret.Header)
+	assert.Equal(t, "The project", *secret.Purpose)
+
+	alice := lines[4].User
+	assert.Equal(t, "alice", *alice.Username)
+	assert.Equal(t, "alice@example.com", *alice.Email)
+	assert.Equal(t, "Alice", *alice.FirstName)
+	assert.Equal(t, "Smith", *alice.LastName)
+	require.NotNil(t, alice.Teams)
+	require.Len(t, *(*alice.Teams)[0].Channels, 1)
+	assert.Equal(t, model.DefaultChannelName, *(*(*alice.Teams)[0].Channels)[0].Name)
+
+	bob := lines[5].User
+	assert.Equal(t, "bob", *bob.Username)
+	assert.Equal(t, "bob@example.com", *bob.Email)
+	require.Len(t, *(*bob.Teams)[0].Channels, 1)
+	assert.Equal(t, model.ChannelUserRoleId+" "+model.ChannelAdminRoleId, *(*(*bob.Teams)[0].Channels)[0].Roles)
+
+	bot := lines[6].User
+	assert.Equal(t, "rocket.cat", *bot.Username)
+	assert.NotNil(t, bot.DeleteAt)
+
+	post := lines[7].Post
+	assert.Equal(t, "community", *post.Team)
+	assert.Equal(t, model.DefaultChannelName, *post.Channel)
+	assert.Equal(t, "bob", *post.User)
+	assert.Equal(t, "Hi @alice, see ~secret: **this** and **that** ~~not~~ `*code*`", *post.Message)
+	assert.Equal(t, int64(1704103200000), *post.CreateAt)
+	assert.Equal(t, int64(1704105000000), *post.EditAt)
+	assert.True(t, *post.IsPinned)
+	require.NotNil(t, post.Reactions)
+	require.Len(t, *post.Reactions, 2)
+	assert.Equal(t, "thumbsup", *(*post.Reactions)[0].EmojiName)
+	assert.Equal(t, "alice", *(*post.Reactions)[0].User)
+	require.NotNil(t, post.Replies)
+	require.Len(t, *post.Replies, 1)
+	assert.Equal(t, "thanks", *(*post.Replies)[0].Message)
+	assert.Equal(t, "alice", *(*post.Replies)[0].User)
+
+	filePost := lines[8].Post
+	assert.Equal(t, "secret", *filePost.Channel)
+	assert.Equal(t, "", *filePost.Message)
+	require.NotNil(t, filePost.Attachments)
+	assert.Equal(t, "attachments/f1/plan.pdf", *(*filePost.Attachments)[0].Path)
+	assert.Equal(t, "missing file", *lines[9].Post.Message)
+
+	assert.Equal(t, []string{"alice", "bob"}, *lines[10].DirectChannel.Members)
+	directPost := lines[11].DirectPost
+	assert.Equal(t, []string{"alice", "bob"}, *directPost.ChannelMembers)
+	assert.Equal(t, "alice", *directPost.User)
+	assert.Equal(t, "Lunch?", *directPost.Message)
+
+	for _, line := range lines {
+		var appErr *model.AppError
+		switch line.Type {
+		case "team":
+			appErr = imports.ValidateTeamImportData(line.Team)
+		case "channel":
+			appErr = imports.ValidateChannelImportData(line.Channel)
+		case "user":
+			appErr = imports.ValidateUserImportData(line.User)
+		case "post":
+			appErr = imports.ValidatePostImportData(line.Post, model.PostMessageMaxRunesV2)
+		case "direct_channel":
+			appErr = imports.ValidateDirectChannelImportData(line.DirectChannel)
+		case "direct_post":
+			appErr = imports.ValidateDirectPostImportData(line.DirectPost, model.PostMessageMaxRunesV2)
+		}
+		require.Nil(t, appErr, line.Type)
+	}
+}
+
+func TestConvertWithoutSubscriptions(t *testing.T) {
+	export := createExport(t, map[string]string{
+		"users.json":           testUsers,
+		"rocketchat_room.json": testRooms,
+		"rocketchat_message.json": `[
+			{"_id":"m1","rid":"r1","msg":"hi","ts":{"$date":"2024-01-01T10:00:00Z"},"u":{"_id":"u1","username":"Alice"}}
+		]`,
+	})
+
+	var buf bytes.Buffer
+	_, err := Convert(export, &buf, Options{TeamName: "community"})
+	require.NoError(t, err)
+
+	lines, _ := readImport(t, buf.Bytes())
+	alice := lines[4].User
+	require.Equal(t, "alice", *alice.Username)
+	require.Len(t, *(*alice.Teams)[0].Channels, 1)
+	assert.Equal(t, "secret", *(*(*alice.Teams)[0].Channels)[0].Name)
+	// The users without messages are only members of the default channel.
+	require.Len(t, *(*lines[5].User.Teams)[0].Channels, 1)
+	assert.Equal(t, model.DefaultChannelName, *(*(*lines[5].User.Teams)[0].Channels)[0].Name)
+}
+
+func TestConvertInvalidExport(t *testing.T) {
+	t.Run("invalid team name", func(t *testing.T) {
+		_, err := Convert(createExport(t, map[string]string{}), io.Discard, Options{TeamName: "Community!"})
+		require.Error(t, err)
+	})
+
+	t.Run("missing users", func(t *testing.T) {
+		_, err := Convert(createExport(t, map[string]string{"rocketchat_room.json": testRooms, "rocketchat_message.json": ""}), io.Discard, Options{TeamName: "community"})
+		require.Error(t, err)
+	})
+
+	t.Run("invalid file name", func(t *testing.T) {
+		_, err := Convert(createExport(t, map[string]string{"../users.json": testUsers}), io.Discard, Options{TeamName: "community"})
+		require.Error(t, err)
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		_, err := Convert(createExport(t, map[string]string{
+			"users.json":              testUsers,
+			"rocketchat_room.json":    testRooms,
+			"rocketchat_message.json": `{"_id":"m1","rid":"GENERAL","ts":{"$date":"yesterday"}}`,
+		}), io.Discard, Options{TeamName: "community"})
+		require.Error(t, err)
+	})
+}
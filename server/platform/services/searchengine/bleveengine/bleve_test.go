@@ -233,3 +233,103 @@ func (s *BleveEngineTestSuite) TestDeletePosts() {
 	require.NoError(s.T(), err)
 	require.Equal(s.T(), 1, int(numberDocs))
 }
+
+func (s *BleveEngineTestSuite) TestSearchPostHits() {
+	s.BleveEngine.PurgeIndexes(s.Context)
+	teamID := model.NewId()
+	userID := model.NewId()
+	channel := &model.Channel{Id: model.NewId()}
+	readChannel := &model.Channel{Id: model.NewId()}
+	createAt := model.GetMillis() - 60*24*60*60*1000
+
+	indexPost := func(channelID, message string, createAt int64) *model.Post {
+		post := createPost(userID, channelID)
+		post.Message = message
+		post.CreateAt = createAt
+		require.Nil(s.T(), s.BleveEngine.IndexPost(post, teamID))
+		return post
+	}
+	phrasePost := indexPost(channel.Id, "the deploy release finished", createAt)
+	wordsPost := indexPost(channel.Id, "the release of the deploy", createAt+1)
+
+	search := func(params *model.SearchParams, channels model.ChannelList) []*model.PostSearchHit {
+		hits, _, appErr := s.BleveEngine.SearchPostHits(channels, []*model.SearchParams{params}, 0, 20)
+		require.Nil(s.T(), appErr)
+		return hits
+	}
+
+	s.Run("Should sort by creation date by default", func() {
+		hits := search(&model.SearchParams{Terms: "deploy release"}, model.ChannelList{channel})
+		require.Len(s.T(), hits, 2)
+		s.Assert().Equal(wordsPost.Id, hits[0].Id)
+		s.Assert().Equal(phrasePost.Id, hits[1].Id)
+	})
+
+	s.Run("Should boost the exact phrases when sorting by relevance", func() {
+		hits := search(&model.SearchParams{Terms: "deploy release", Sort: model.SearchSortRelevance}, model.ChannelList{channel})
+		require.Len(s.T(), hits, 2)
+		s.Assert().Equal(phrasePost.Id, hits[0].Id)
+		s.Assert().Equal(wordsPost.Id, hits[1].Id)
+		s.Assert().Greater(hits[0].Score, hits[1].Score)
+	})
+
+	s.Run("Should highlight the matches", func() {
+		hits := search(&model.SearchParams{Terms: "finished", Sort: model.SearchSortRelevance}, model.ChannelList{channel})
+		require.Len(s.T(), hits, 1)
+		require.NotEmpty(s.T(), hits[0].Highlights)
+		s.Assert().Contains(hits[0].Highlights[0], "<mark>finished</mark>")
+	})
+
+	s.Run("Should escape the messages around the highlights", func() {
+		scriptPost := indexPost(channel.Id, "<script>alert('deploy')</script>", createAt)
+		hits := search(&model.SearchParams{Terms: "alert", Sort: model.SearchSortRelevance}, model.ChannelList{channel})
+		require.Len(s.T(), hits, 1)
+		s.Assert().Equal(scriptPost.Id, hits[0].Id)
+		require.NotEmpty(s.T(), hits[0].Highlights)
+		s.Assert().Equal("&lt;script&gt;<mark>alert</mark>(&#39;deploy&#39;)&lt;/script&gt;", hits[0].Highlights[0])
+		s.Assert().NotContains(hits[0].Highlights[0], "<script>")
+	})
+
+	s.Run("Should not return the pages beyond the rescore window when sorting by relevance", func() {
+		hits, _, appErr := s.BleveEngine.SearchPostHits(model.ChannelList{channel}, []*model.SearchParams{{Terms: "finished", Sort: model.SearchSortRelevance}}, RelevanceRescoreWindow/20, 20)
+		require.Nil(s.T(), appErr)
+		s.Assert().Empty(hits)
+	})
+
+	s.Run("Should boost the channels read by the user", func() {
+		readPost := indexPost(readChannel.Id, "the deploy release finished", createAt)
+		params := &model.SearchParams{
+			Terms:        "finished",
+			Sort:         model.SearchSortRelevance,
+			ReadChannels: []string{readChannel.Id},
+		}
+		hits := search(params, model.ChannelList{channel, readChannel})
+		require.Len(s.T(), hits, 2)
+		s.Assert().Equal(readPost.Id, hits[0].Id)
+		s.Assert().Equal(phrasePost.Id, hits[1].Id)
+	})
+}
+
+func (s *BleveEngineTestSuite) TestSearchFileHits() {
+	s.BleveEngine.PurgeIndexes(s.Context)
+	channel := &model.Channel{Id: model.NewId()}
+
+	file := &model.FileInfo{
+		Id:        model.NewId(),
+		CreatorId: model.NewId(),
+		CreateAt:  model.GetMillis(),
+		Name:      "release-notes.txt",
+		Extension: "txt",
+		Content:   "the deploy <b>release</b> finished",
+	}
+	require.Nil(s.T(), s.BleveEngine.IndexFile(file, channel.Id))
+
+	hits, appErr := s.BleveEngine.SearchFileHits(model.ChannelList{channel}, []*model.SearchParams{{Terms: "release", Sort: model.SearchSortRelevance}}, 0, 20)
+	require.Nil(s.T(), appErr)
+	require.Len(s.T(), hits, 1)
+	s.Assert().Equal(file.Id, hits[0].Id)
+	s.Assert().NotZero(hits[0].Score)
+	require.Len(s.T(), hits[0].Highlights, 2)
+	s.Assert().Contains(hits[0].Highlights[0], "<mark>release</mark>")
+	s.Assert().Equal("the deploy &lt;b&gt;<mark>release</mark>&lt;/b&gt; finished", hits[0].Highlights[1])
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bleveengine
+
+import (
+	"html"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2/registry"
+	"github.com/blevesearch/bleve/v2/search/highlight"
+	simplefragmenter "github.com/blevesearch/bleve/v2/search/highlight/fragmenter/simple"
+	simplehighlighter "github.com/blevesearch/bleve/v2/search/highlight/highlighter/simple"
+)
+
+const (
+	// escapedHTMLHighlighter is the name of the highlighter wrapping the
+	// matches in <mark> tags. Unlike the html highlighter of Bleve, it
+	// escapes the text of the fragments, which comes from the messages and
+	// files of the users.
+	escapedHTMLHighlighter = "mattermost_escaped_html"
+
+	highlightBefore = "<mark>"
+	highlightAfter  = "</mark>"
+)
+
+type escapedHTMLFormatter struct{}
+
+func (escapedHTMLFormatter) Format(f *highlight.Fragment, orderedTermLocations highlight.TermLocations) string {
+	var sb strings.Builder
+	curr := f.Start
+	for _, termLocation := range orderedTermLocations {
+		if termLocation == nil || !termLocation.ArrayPositions.Equals(f.ArrayPositions) {
+			continue
+		}
+		if termLocation.Start < curr {
+			continue
+		}
+		if termLocation.End > f.End {
+			break
+		}
+		sb.WriteString(html.EscapeString(string(f.Orig[curr:termLocation.Start])))
+		sb.WriteString(highlightBefore)
+		sb.WriteString(html.EscapeString(string(f.Orig[termLocation.Start:termLocation.End])))
+		sb.WriteString(highlightAfter)
+		curr = termLocation.End
+	}
+	sb.WriteString(html.EscapeString(string(f.Orig[curr:f.End])))
+	return sb.String()
+}
+
+func init() {
+	registry.RegisterHighlighter(escapedHTMLHighlighter, func(config map[string]any, cache *registry.Cache) (highlight.Highlighter, error) {
+		fragmenter, err := cache.FragmenterNamed(simplefragmenter.Name)
+		if err != nil {
+			return nil, err
+		}
+		return simplehighlighter.NewHighlighter(fragmenter, escapedHTMLFormatter{}, simplehighlighter.DefaultSeparator), nil
+	})
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bleveengine
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/query"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	// RelevanceRescoreWindow is the number of best matches whose score is
+	// adjusted with the recency and read channel boosts. Sorting by
+	// relevance only returns the matches within it, so that every page is
+	// ranked the same way.
+	RelevanceRescoreWindow = 500
+
+	phraseBoost      = 2.0
+	recencyBoost     = 1.0
+	recencyHalfLife  = 7 * 24 * time.Hour
+	readChannelBoost = 1.5
+)
+
+type searchHit struct {
+	id        string
+	channelId string
+	createAt  int64
+	score     float64
+	fragments []string
+}
+
+// searchPhrases returns the phrases whose exact matches are boosted when
+// sorting by relevance: the quoted phrases, and all the words of the terms
+// when there are more than one.
+func searchPhrases(terms string) []string {
+	phrases := []string{}
+	words := []string{}
+	for i, part := range strings.Split(terms, `"`) {
+		fields := strings.Fields(part)
+		if i%2 == 1 {
+			if len(fields) > 1 {
+				phrases = append(phrases, strings.Join(fields, " "))
+			}
+			words = append(words, fields...)
+			continue
+		}
+
+		for _, field := range fields {
+			if !strings.HasSuffix(field, "*") {
+				words = append(words, field)
+			}
+		}
+	}
+
+	if len(words) > 1 {
+		allWords := strings.Join(words, " ")
+		if len(phrases) != 1 || phrases[0] != allWords {
+			phrases = append(phrases, allWords)
+		}
+	}
+
+	return phrases
+}
+
+// phraseQueries returns the optional queries boosting the documents which
+// contain the phrases of the search in any of the fields.
+func phraseQueries(searchParams []*model.SearchParams, fields ...string) []query.Query {
+	queries := []query.Query{}
+	for _, params := range searchParams {
+		if params.IsHashtag {
+			continue
+		}
+
+		for _, phrase := range searchPhrases(params.Terms) {
+			for _, field := range fields {
+				phraseQ := bleve.NewMatchPhraseQuery(phrase)
+				phraseQ.SetField(field)
+				phraseQ.SetBoost(phraseBoost)
+				queries = append(queries, phraseQ)
+			}
+		}
+	}
+	return queries
+}
+
+// relevanceScore boosts the score of the recent documents, decaying with
+// the age of the document, and of the documents in the channels read by the
+// user.
+func relevanceScore(hit *searchHit, readChannels map[string]bool, now int64) float64 {
+	age := math.Max(0, float64(now-hit.createAt))
+	halfLife := float64(recencyHalfLife.Milliseconds())
+	score := hit.score * (1 + recencyBoost*math.Pow(0.5, age/halfLife))
+	if readChannels[hit.channelId] {
+		score *= readChannelBoost
+	}
+	return score
+}
+
+// rankHits sorts the hits by their relevance score, the most recent first
+// for equal scores.
+func rankHits(hits []*searchHit, readChannelIds []string, now int64) {
+	readChannels := make(map[string]bool, len(readChannelIds))
+	for _, channelId := range readChannelIds {
+		readChannels[channelId] = true
+	}
+
+	for _, hit := range hits {
+		hit.score = relevanceScore(hit, readChannels, now)
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return hits[i].createAt > hits[j].createAt
+	})
+}
+
+func newSearchRequest(q query.Query, size, from int, sortBy []string, highlightFields []string) *bleve.SearchRequest {
+	request := bleve.NewSearchRequestOptions(q, size, from, false)
+	request.SortBy(sortBy)
+	if len(highlightFields) > 0 {
+		request.Highlight = bleve.NewHighlightWithStyle(escapedHTMLHighlighter)
+		for _, field := range highlightFields {
+			request.Highlight.AddField(field)
+		}
+	}
+	return request
+}
+
+// fragmentsOf returns the highlighted fragments of the fields of a match, in
+// the order of the fields.
+func fragmentsOf(fragments search.FieldFragmentMap, highlightFields []string) []string {
+	var result []string
+	for _, field := range highlightFields {
+		result = append(result, fragments[field]...)
+	}
+	return result
+}
+
+func searchHitsFromResults(results *bleve.SearchResult, highlightFields []string) []*searchHit {
+	hits := make([]*searchHit, 0, len(results.Hits))
+	for _, r := range results.Hits {
+		hit := &searchHit{
+			id:    r.ID,
+			score: r.Score,
+		}
+		if channelId, ok := r.Fields["ChannelId"].(string); ok {
+			hit.channelId = channelId
+		}
+		if createAt, ok := r.Fields["CreateAt"].(float64); ok {
+			hit.createAt = int64(createAt)
+		}
+		hit.fragments = fragmentsOf(r.Fragments, highlightFields)
+		hits = append(hits, hit)
+	}
+	return hits
+}
+
+// runSearch runs the query against the index and returns the requested page
+// of hits, sorted as requested by the search params. When sorting by
+// relevance, the best matches are ranked with the recency and read channel
+// boosts, the pages end with the RelevanceRescoreWindow, and only the
+// returned page is highlighted.
+func runSearch(index bleve.Index, q query.Query, params *model.SearchParams, highlightFields []string, page, perPage int) ([]*searchHit, error) {
+	from := page * perPage
+	if params.Sort != model.SearchSortRelevance {
+		results, err := index.Search(newSearchRequest(q, perPage, from, []string{"-CreateAt"}, highlightFields))
+		if err != nil {
+			return nil, err
+		}
+		return searchHitsFromResults(results, highlightFields), nil
+	}
+
+	if from >= RelevanceRescoreWindow {
+		return []*searchHit{}, nil
+	}
+
+	request := newSearchRequest(q, RelevanceRescoreWindow, 0, []string{"-_score", "-CreateAt"}, nil)
+	request.Fields = []string{"ChannelId", "CreateAt"}
+	results, err := index.Search(request)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := searchHitsFromResults(results, nil)
+	rankHits(hits, params.ReadChannels, model.GetMillis())
+	if from >= len(hits) {
+		return []*searchHit{}, nil
+	}
+	hits = hits[from:min(from+perPage, len(hits))]
+
+	if len(highlightFields) == 0 {
+		return hits, nil
+	}
+
+	ids := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.id)
+	}
+	highlightQ := bleve.NewConjunctionQuery(q, bleve.NewDocIDQuery(ids))
+	highlightResults, err := index.Search(newSearchRequest(highlightQ, len(ids), 0, []string{"-_score"}, highlightFields))
+	if err != nil {
+		return nil, err
+	}
+
+	fragments := make(map[string][]string, len(highlightResults.Hits))
+	for _, r := range highlightResults.Hits {
+		fragments[r.ID] = fragmentsOf(r.Fragments, highlightFields)
+	}
+	for _, hit := range hits {
+		hit.fragments = fragments[hit.id]
+	}
+
+	return hits, nil
+}
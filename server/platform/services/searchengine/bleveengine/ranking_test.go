@@ -0,0 +1,86 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bleveengine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchPhrases(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Terms    string
+		Expected []string
+	}{
+		{
+			Name:     "single word",
+			Terms:    "hello",
+			Expected: []string{},
+		},
+		{
+			Name:     "several words",
+			Terms:    "hello  big world",
+			Expected: []string{"hello big world"},
+		},
+		{
+			Name:     "wildcards are ignored",
+			Terms:    "hello wor*",
+			Expected: []string{},
+		},
+		{
+			Name:     "quoted phrase only",
+			Terms:    `"hello world"`,
+			Expected: []string{"hello world"},
+		},
+		{
+			Name:     "quoted phrase and words",
+			Terms:    `"hello world" again`,
+			Expected: []string{"hello world", "hello world again"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, searchPhrases(tc.Terms))
+		})
+	}
+}
+
+func TestRankHits(t *testing.T) {
+	day := int64(24 * 60 * 60 * 1000)
+	now := 100 * day
+
+	t.Run("recent hits are boosted", func(t *testing.T) {
+		hits := []*searchHit{
+			{id: "old", score: 1.2, createAt: now - 60*day},
+			{id: "new", score: 1, createAt: now - day},
+		}
+		rankHits(hits, nil, now)
+		require.Equal(t, "new", hits[0].id)
+		require.Equal(t, "old", hits[1].id)
+		assert.InDelta(t, 1.2, hits[1].score, 0.01)
+	})
+
+	t.Run("hits in read channels are boosted", func(t *testing.T) {
+		hits := []*searchHit{
+			{id: "unread", channelId: "channel1", score: 1.2, createAt: now},
+			{id: "read", channelId: "channel2", score: 1, createAt: now},
+		}
+		rankHits(hits, []string{"channel2"}, now)
+		require.Equal(t, "read", hits[0].id)
+		assert.InDelta(t, 3, hits[0].score, 0.001)
+	})
+
+	t.Run("equal scores are sorted by recency", func(t *testing.T) {
+		hits := []*searchHit{
+			{id: "future1", score: 1, createAt: now + 1},
+			{id: "future2", score: 1, createAt: now + 2},
+		}
+		rankHits(hits, nil, now)
+		require.Equal(t, "future2", hits[0].id)
+	})
+}
@@ -30,6 +30,20 @@ func (b *BleveEngine) IndexPost(post *model.Post, teamId string) *model.AppError
 }
 
 func (b *BleveEngine) SearchPosts(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]string, model.PostSearchMatches, *model.AppError) {
+	hits, matches, appErr := b.SearchPostHits(channels, searchParams, page, perPage)
+	if appErr != nil {
+		return nil, nil, appErr
+	}
+
+	postIds := []string{}
+	for _, hit := range hits {
+		postIds = append(postIds, hit.Id)
+	}
+
+	return postIds, matches, nil
+}
+
+func (b *BleveEngine) SearchPostHits(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]*model.PostSearchHit, model.PostSearchMatches, *model.AppError) {
 	channelQueries := []query.Query{}
 	for _, channel := range channels {
 		channelIdQ := bleve.NewTermQuery(channel.Id)
@@ -211,21 +225,29 @@ func (b *BleveEngine) SearchPosts(channels model.ChannelList, searchParams []*mo
 		query.AddMustNot(notFilters...)
 	}
 
-	search := bleve.NewSearchRequestOptions(query, perPage, page*perPage, false)
-	search.SortBy([]string{"-CreateAt"})
-	results, err := b.PostIndex.Search(search)
+	if searchParams[0].Sort == model.SearchSortRelevance {
+		if boostQueries := phraseQueries(searchParams, "Message"); len(boostQueries) > 0 {
+			query.AddShould(boostQueries...)
+		}
+	}
+
+	results, err := runSearch(b.PostIndex, query, searchParams[0], []string{"Message"}, page, perPage)
 	if err != nil {
 		return nil, nil, model.NewAppError("Bleveengine.SearchPosts", "bleveengine.search_posts.error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 
-	postIds := []string{}
+	hits := []*model.PostSearchHit{}
 	matches := model.PostSearchMatches{}
 
-	for _, r := range results.Hits {
-		postIds = append(postIds, r.ID)
+	for _, r := range results {
+		hits = append(hits, &model.PostSearchHit{
+			Id:         r.id,
+			Score:      r.score,
+			Highlights: r.fragments,
+		})
 	}
 
-	return postIds, matches, nil
+	return hits, matches, nil
 }
 
 func (b *BleveEngine) deletePosts(searchRequest *bleve.SearchRequest, batchSize int) (int64, error) {
@@ -574,6 +596,20 @@ func (b *BleveEngine) IndexFile(file *model.FileInfo, channelId string) *model.A
 }
 
 func (b *BleveEngine) SearchFiles(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]string, *model.AppError) {
+	hits, appErr := b.SearchFileHits(channels, searchParams, page, perPage)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	fileIds := []string{}
+	for _, hit := range hits {
+		fileIds = append(fileIds, hit.Id)
+	}
+
+	return fileIds, nil
+}
+
+func (b *BleveEngine) SearchFileHits(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]*model.FileSearchHit, *model.AppError) {
 	channelQueries := []query.Query{}
 	for _, channel := range channels {
 		channelIdQ := bleve.NewTermQuery(channel.Id)
@@ -765,20 +801,28 @@ func (b *BleveEngine) SearchFiles(channels model.ChannelList, searchParams []*mo
 		query.AddMustNot(notFilters...)
 	}
 
-	search := bleve.NewSearchRequestOptions(query, perPage, page*perPage, false)
-	search.SortBy([]string{"-CreateAt"})
-	results, err := b.FileIndex.Search(search)
+	if searchParams[0].Sort == model.SearchSortRelevance {
+		if boostQueries := phraseQueries(searchParams, "Name", "Content"); len(boostQueries) > 0 {
+			query.AddShould(boostQueries...)
+		}
+	}
+
+	results, err := runSearch(b.FileIndex, query, searchParams[0], []string{"Name", "Content"}, page, perPage)
 	if err != nil {
 		return nil, model.NewAppError("Bleveengine.SearchFiles", "bleveengine.search_files.error", nil, "", http.StatusInternalServerError).Wrap(err)
 	}
 
-	fileIds := []string{}
+	hits := []*model.FileSearchHit{}
 
-	for _, r := range results.Hits {
-		fileIds = append(fileIds, r.ID)
+	for _, r := range results {
+		hits = append(hits, &model.FileSearchHit{
+			Id:         r.id,
+			Score:      r.score,
+			Highlights: r.fragments,
+		})
 	}
 
-	return fileIds, nil
+	return hits, nil
 }
 
 func (b *BleveEngine) DeleteFile(fileID string) *model.AppError {
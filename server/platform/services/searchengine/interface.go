@@ -51,3 +51,35 @@ type SearchEngineInterface interface {
 	RefreshIndexes(rctx request.CTX) *model.AppError
 	DataRetentionDeleteIndexes(rctx request.CTX, cutoff time.Time) *model.AppError
 }
+
+// RankedSearchEngineInterface is implemented by the search engines able to
+// return the relevance score and the highlighted fragments of the posts and
+// files they find. The hits are returned in the order requested by the Sort
+// of the search params.
+type RankedSearchEngineInterface interface {
+	SearchPostHits(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]*model.PostSearchHit, model.PostSearchMatches, *model.AppError)
+	SearchFileHits(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]*model.FileSearchHit, *model.AppError)
+}
+
+// VectorEngineInterface is implemented by the engines indexing the posts as
+// embedding vectors, to find the posts closest in meaning to a text rather
+// than the posts containing its words.
+type VectorEngineInterface interface {
+	Start() *model.AppError
+	Stop() *model.AppError
+	UpdateConfig(cfg *model.Config)
+	GetName() string
+	IsActive() bool
+	IsIndexingEnabled() bool
+	IsSearchEnabled() bool
+	IsIndexingSync() bool
+	IndexPost(post *model.Post, teamId string) *model.AppError
+	// SearchSimilarPosts returns the posts of the given channels closest in
+	// meaning to the text, the most similar first. The score of the hits is
+	// their cosine similarity with the text.
+	SearchSimilarPosts(text string, channelIds []string, limit int) ([]*model.PostSearchHit, *model.AppError)
+	DeletePost(post *model.Post) *model.AppError
+	DeleteChannelPosts(rctx request.CTX, channelID string) *model.AppError
+	DeleteUserPosts(rctx request.CTX, userID string) *model.AppError
+	PurgeIndexes(rctx request.CTX) *model.AppError
+}
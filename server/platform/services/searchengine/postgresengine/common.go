@@ -0,0 +1,192 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package postgresengine
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine"
+)
+
+type PGChannel struct {
+	Id            string
+	Type          model.ChannelType
+	TeamId        string
+	UserIDs       []string
+	TeamMemberIDs []string
+	NameSuggest   []string
+}
+
+type PGUser struct {
+	Id                         string
+	SuggestionsWithFullname    []string
+	SuggestionsWithoutFullname []string
+	TeamsIds                   []string
+	ChannelsIds                []string
+}
+
+type PGPost struct {
+	Id        string
+	TeamId    string
+	ChannelId string
+	UserId    string
+	CreateAt  int64
+	Message   string
+	Type      string
+	Hashtags  []string
+}
+
+type PGFile struct {
+	Id        string
+	CreatorId string
+	ChannelId string
+	PostId    string
+	CreateAt  int64
+	Name      string
+	Content   string
+	Extension string
+}
+
+func PGChannelFromChannel(channel *model.Channel, userIDs, teamMemberIDs []string) *PGChannel {
+	displayNameInputs := searchengine.GetSuggestionInputsSplitBy(channel.DisplayName, " ")
+	nameInputs := searchengine.GetSuggestionInputsSplitByMultiple(channel.Name, []string{"-", "_"})
+
+	return &PGChannel{
+		Id:            channel.Id,
+		Type:          channel.Type,
+		TeamId:        channel.TeamId,
+		NameSuggest:   lowerStrings(append(displayNameInputs, nameInputs...)),
+		UserIDs:       nonNilStrings(userIDs),
+		TeamMemberIDs: nonNilStrings(teamMemberIDs),
+	}
+}
+
+func PGUserFromUserAndTeams(user *model.User, teamsIds, channelsIds []string) *PGUser {
+	usernameSuggestions := searchengine.GetSuggestionInputsSplitByMultiple(user.Username, []string{".", "-", "_"})
+
+	fullnameStrings := []string{}
+	if user.FirstName != "" {
+		fullnameStrings = append(fullnameStrings, user.FirstName)
+	}
+	if user.LastName != "" {
+		fullnameStrings = append(fullnameStrings, user.LastName)
+	}
+
+	fullnameSuggestions := []string{}
+	if len(fullnameStrings) > 0 {
+		fullname := strings.Join(fullnameStrings, " ")
+		fullnameSuggestions = searchengine.GetSuggestionInputsSplitBy(fullname, " ")
+	}
+
+	nicknameSuggestions := []string{}
+	if user.Nickname != "" {
+		nicknameSuggestions = searchengine.GetSuggestionInputsSplitBy(user.Nickname, " ")
+	}
+
+	usernameAndNicknameSuggestions := append(usernameSuggestions, nicknameSuggestions...)
+
+	return &PGUser{
+		Id:                         user.Id,
+		SuggestionsWithFullname:    lowerStrings(append(usernameAndNicknameSuggestions, fullnameSuggestions...)),
+		SuggestionsWithoutFullname: lowerStrings(usernameAndNicknameSuggestions),
+		TeamsIds:                   nonNilStrings(teamsIds),
+		ChannelsIds:                nonNilStrings(channelsIds),
+	}
+}
+
+func PGUserFromUserForIndexing(userForIndexing *model.UserForIndexing) *PGUser {
+	user := &model.User{
+		Id:        userForIndexing.Id,
+		Username:  userForIndexing.Username,
+		Nickname:  userForIndexing.Nickname,
+		FirstName: userForIndexing.FirstName,
+		LastName:  userForIndexing.LastName,
+		CreateAt:  userForIndexing.CreateAt,
+		DeleteAt:  userForIndexing.DeleteAt,
+	}
+
+	return PGUserFromUserAndTeams(user, userForIndexing.TeamsIds, userForIndexing.ChannelsIds)
+}
+
+func PGPostFromPost(post *model.Post, teamId string) *PGPost {
+	p := &model.PostForIndexing{
+		TeamId: teamId,
+	}
+	post.ShallowCopy(&p.Post)
+	return PGPostFromPostForIndexing(p)
+}
+
+func PGPostFromPostForIndexing(post *model.PostForIndexing) *PGPost {
+	hashtags := []string{}
+	for _, hashtag := range strings.Fields(post.Hashtags) {
+		hashtags = append(hashtags, normalizeHashtag(hashtag))
+	}
+
+	return &PGPost{
+		Id:        post.Id,
+		TeamId:    post.TeamId,
+		ChannelId: post.ChannelId,
+		UserId:    post.UserId,
+		CreateAt:  post.CreateAt,
+		Message:   post.Message,
+		Type:      post.Type,
+		Hashtags:  hashtags,
+	}
+}
+
+func splitFilenameWords(name string) string {
+	result := name
+	result = strings.ReplaceAll(result, "-", " ")
+	result = strings.ReplaceAll(result, ".", " ")
+	return result
+}
+
+func PGFileFromFileInfo(fileInfo *model.FileInfo, channelId string) *PGFile {
+	return &PGFile{
+		Id:        fileInfo.Id,
+		ChannelId: channelId,
+		CreatorId: fileInfo.CreatorId,
+		PostId:    fileInfo.PostId,
+		CreateAt:  fileInfo.CreateAt,
+		Content:   fileInfo.Content,
+		Extension: fileInfo.Extension,
+		Name:      fileInfo.Name + " " + splitFilenameWords(fileInfo.Name),
+	}
+}
+
+func PGFileFromFileForIndexing(file *model.FileForIndexing) *PGFile {
+	return &PGFile{
+		Id:        file.Id,
+		ChannelId: file.ChannelId,
+		CreatorId: file.CreatorId,
+		PostId:    file.PostId,
+		CreateAt:  file.CreateAt,
+		Content:   file.Content,
+		Extension: file.Extension,
+		Name:      file.Name + " " + splitFilenameWords(file.Name),
+	}
+}
+
+// normalizeHashtag makes the hashtags case insensitive, and lets them be
+// searched with or without their leading #.
+func normalizeHashtag(hashtag string) string {
+	return strings.ToLower(strings.TrimPrefix(hashtag, "#"))
+}
+
+func lowerStrings(values []string) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = strings.ToLower(value)
+	}
+	return result
+}
+
+// nonNilStrings avoids storing NULL in the not null array columns.
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
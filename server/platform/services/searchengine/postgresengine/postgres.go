@@ -0,0 +1,275 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package postgresengine
+
+import (
+	"database/sql"
+	"net/http"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sq "github.com/mattermost/squirrel"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+const (
+	EngineName   = "postgres"
+	PostIndex    = "posts"
+	FileIndex    = "files"
+	UserIndex    = "users"
+	ChannelIndex = "channels"
+
+	// The index tables live next to the Mattermost tables, their names are
+	// prefixed to not clash with them.
+	PostTable    = "ftsposts"
+	FileTable    = "ftsfiles"
+	UserTable    = "ftsusers"
+	ChannelTable = "ftschannels"
+
+	// The suggestions used to autocomplete channels and users are stored one
+	// per row, for the prefix searches to use a btree index.
+	ChannelSuggestionTable = "ftschannelsuggestions"
+	UserSuggestionTable    = "ftsusersuggestions"
+)
+
+var indexTables = map[string]string{
+	PostIndex:    PostTable,
+	FileIndex:    FileTable,
+	UserIndex:    UserTable,
+	ChannelIndex: ChannelTable,
+}
+
+const (
+	// The engine opens its own connection pool, as the search engines are
+	// started before the store. It is kept small so that the engine doesn't
+	// double the connections the server opens to the database.
+	dbMaxOpenConns = 8
+	dbMaxIdleConns = 2
+)
+
+// PostgresEngine is a search engine storing its indexes in tables of the
+// Mattermost Postgres database, created by the database migrations. The
+// documents are stored as tsvector columns computed with the text search
+// configuration in use when they were indexed, and searched through GIN
+// indexes.
+type PostgresEngine struct {
+	DB        *sql.DB
+	Mutex     sync.RWMutex
+	ready     int32
+	cfg       *model.Config
+	indexSync bool
+}
+
+func NewPostgresEngine(cfg *model.Config) *PostgresEngine {
+	return &PostgresEngine{
+		cfg: cfg,
+	}
+}
+
+func (pe *PostgresEngine) getQueryBuilder() sq.StatementBuilderType {
+	return sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+}
+
+func (pe *PostgresEngine) textSearchConfig() string {
+	return *pe.cfg.PostgresSearchSettings.TextSearchConfig
+}
+
+func (pe *PostgresEngine) openDB() *model.AppError {
+	if atomic.LoadInt32(&pe.ready) != 0 {
+		return model.NewAppError("Postgresengine.Start", "postgresengine.already_started.error", nil, "", http.StatusInternalServerError)
+	}
+
+	if *pe.cfg.SqlSettings.DriverName != model.DatabaseDriverPostgres {
+		return model.NewAppError("Postgresengine.Start", "postgresengine.unsupported_driver.error", nil, "", http.StatusInternalServerError)
+	}
+
+	db, err := sql.Open(model.DatabaseDriverPostgres, *pe.cfg.SqlSettings.DataSource)
+	if err != nil {
+		return model.NewAppError("Postgresengine.Start", "postgresengine.open_db.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	db.SetMaxIdleConns(min(*pe.cfg.SqlSettings.MaxIdleConns, dbMaxIdleConns))
+	db.SetMaxOpenConns(min(*pe.cfg.SqlSettings.MaxOpenConns, dbMaxOpenConns))
+	db.SetConnMaxLifetime(time.Duration(*pe.cfg.SqlSettings.ConnMaxLifetimeMilliseconds) * time.Millisecond)
+	db.SetConnMaxIdleTime(time.Duration(*pe.cfg.SqlSettings.ConnMaxIdleTimeMilliseconds) * time.Millisecond)
+
+	pe.DB = db
+	atomic.StoreInt32(&pe.ready, 1)
+	return nil
+}
+
+func (pe *PostgresEngine) Start() *model.AppError {
+	if !*pe.cfg.PostgresSearchSettings.EnableIndexing {
+		return nil
+	}
+
+	pe.Mutex.Lock()
+	defer pe.Mutex.Unlock()
+
+	mlog.Info("Starting Postgres search engine")
+
+	return pe.openDB()
+}
+
+func (pe *PostgresEngine) closeDB() *model.AppError {
+	if pe.IsActive() {
+		if err := pe.DB.Close(); err != nil {
+			return model.NewAppError("Postgresengine.Stop", "postgresengine.close_db.error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+		pe.DB = nil
+	}
+
+	atomic.StoreInt32(&pe.ready, 0)
+	return nil
+}
+
+func (pe *PostgresEngine) Stop() *model.AppError {
+	pe.Mutex.Lock()
+	defer pe.Mutex.Unlock()
+
+	mlog.Info("Stopping Postgres search engine")
+
+	return pe.closeDB()
+}
+
+func (pe *PostgresEngine) IsEnabled() bool {
+	return pe.IsIndexingEnabled()
+}
+
+func (pe *PostgresEngine) IsActive() bool {
+	return atomic.LoadInt32(&pe.ready) == 1
+}
+
+func (pe *PostgresEngine) IsIndexingSync() bool {
+	return pe.indexSync
+}
+
+func (pe *PostgresEngine) RefreshIndexes(_ request.CTX) *model.AppError {
+	return nil
+}
+
+func (pe *PostgresEngine) GetVersion() int {
+	return 0
+}
+
+func (pe *PostgresEngine) GetFullVersion() string {
+	return "0"
+}
+
+func (pe *PostgresEngine) GetPlugins() []string {
+	return []string{}
+}
+
+func (pe *PostgresEngine) GetName() string {
+	return EngineName
+}
+
+func (pe *PostgresEngine) TestConfig(rctx request.CTX, cfg *model.Config) *model.AppError {
+	return nil
+}
+
+func (pe *PostgresEngine) truncateTables(tables []string) *model.AppError {
+	for _, table := range tables {
+		// The suggestions reference the channels and users.
+		if _, err := pe.DB.Exec("TRUNCATE TABLE " + table + " CASCADE"); err != nil {
+			return model.NewAppError("Postgresengine.PurgeIndexes", "postgresengine.purge_index.error", map[string]any{"Index": table}, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+	return nil
+}
+
+func (pe *PostgresEngine) PurgeIndexes(rctx request.CTX) *model.AppError {
+	if !pe.IsActive() {
+		return nil
+	}
+
+	pe.Mutex.Lock()
+	defer pe.Mutex.Unlock()
+
+	rctx.Logger().Info("PurgeIndexes Postgres")
+
+	return pe.truncateTables([]string{PostTable, FileTable, UserTable, ChannelTable})
+}
+
+func (pe *PostgresEngine) PurgeIndexList(rctx request.CTX, indexes []string) *model.AppError {
+	if !pe.IsActive() {
+		return nil
+	}
+
+	tables := make([]string, 0, len(indexes))
+	for _, index := range indexes {
+		table, ok := indexTables[index]
+		if !ok {
+			return model.NewAppError("Postgresengine.PurgeIndexList", "postgresengine.purge_list.unknown_index.error", map[string]any{"Index": index}, "", http.StatusBadRequest)
+		}
+		tables = append(tables, table)
+	}
+
+	pe.Mutex.Lock()
+	defer pe.Mutex.Unlock()
+
+	rctx.Logger().Info("PurgeIndexList Postgres", mlog.Array("indexes", indexes))
+
+	return pe.truncateTables(tables)
+}
+
+// DataRetentionDeleteIndexes removes the posts and files created before the
+// cutoff from the indexes.
+func (pe *PostgresEngine) DataRetentionDeleteIndexes(rctx request.CTX, cutoff time.Time) *model.AppError {
+	if !pe.IsActive() {
+		return nil
+	}
+
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	for _, table := range []string{PostTable, FileTable} {
+		if _, err := pe.DB.Exec("DELETE FROM "+table+" WHERE createat < $1", model.GetMillisForTime(cutoff)); err != nil {
+			return model.NewAppError("Postgresengine.DataRetentionDeleteIndexes", "postgresengine.data_retention_delete_indexes.error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+	}
+	return nil
+}
+
+func (pe *PostgresEngine) IsAutocompletionEnabled() bool {
+	return *pe.cfg.PostgresSearchSettings.EnableAutocomplete
+}
+
+func (pe *PostgresEngine) IsIndexingEnabled() bool {
+	return *pe.cfg.PostgresSearchSettings.EnableIndexing
+}
+
+func (pe *PostgresEngine) IsSearchEnabled() bool {
+	return *pe.cfg.PostgresSearchSettings.EnableSearching
+}
+
+func (pe *PostgresEngine) UpdateConfig(cfg *model.Config) {
+	pe.Mutex.Lock()
+	defer pe.Mutex.Unlock()
+
+	if reflect.DeepEqual(cfg.PostgresSearchSettings, pe.cfg.PostgresSearchSettings) && *cfg.SqlSettings.DataSource == *pe.cfg.SqlSettings.DataSource {
+		return
+	}
+
+	mlog.Info("UpdateConf Postgres search engine")
+
+	if *cfg.PostgresSearchSettings.EnableIndexing != *pe.cfg.PostgresSearchSettings.EnableIndexing || *cfg.SqlSettings.DataSource != *pe.cfg.SqlSettings.DataSource {
+		if err := pe.closeDB(); err != nil {
+			mlog.Error("Error closing the Postgres search engine database to update the config", mlog.Err(err))
+			return
+		}
+		pe.cfg = cfg
+		if *cfg.PostgresSearchSettings.EnableIndexing {
+			if err := pe.openDB(); err != nil {
+				mlog.Error("Error opening the Postgres search engine database after updating the config", mlog.Err(err))
+			}
+		}
+		return
+	}
+	pe.cfg = cfg
+}
@@ -0,0 +1,279 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package postgresengine
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+	"github.com/mattermost/mattermost/server/v8/channels/store/searchlayer"
+	"github.com/mattermost/mattermost/server/v8/channels/store/searchtest"
+	"github.com/mattermost/mattermost/server/v8/channels/store/sqlstore"
+	"github.com/mattermost/mattermost/server/v8/channels/store/storetest"
+	"github.com/mattermost/mattermost/server/v8/channels/testlib"
+	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine"
+)
+
+type PostgresEngineTestSuite struct {
+	suite.Suite
+
+	SQLSettings    *model.SqlSettings
+	SQLStore       *sqlstore.SqlStore
+	SearchEngine   *searchengine.Broker
+	Store          *searchlayer.SearchStore
+	PostgresEngine *PostgresEngine
+	Context        request.CTX
+}
+
+func TestPostgresEngineTestSuite(t *testing.T) {
+	if driverName := os.Getenv("MM_SQLSETTINGS_DRIVERNAME"); driverName != "" && driverName != model.DatabaseDriverPostgres {
+		t.Skip("The Postgres search engine requires a Postgres database")
+	}
+
+	suite.Run(t, &PostgresEngineTestSuite{
+		Context: request.TestContext(t),
+	})
+}
+
+func (s *PostgresEngineTestSuite) SetupSuite() {
+	s.SQLSettings = storetest.MakeSqlSettings(model.DatabaseDriverPostgres, false)
+
+	var err error
+	s.SQLStore, err = sqlstore.New(*s.SQLSettings, s.Context.Logger(), nil)
+	if err != nil {
+		s.Require().FailNow("Cannot initialize store: %s", err.Error())
+	}
+
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+	cfg.SqlSettings = *s.SQLSettings
+	cfg.PostgresSearchSettings.EnableIndexing = model.NewPointer(true)
+	cfg.PostgresSearchSettings.EnableSearching = model.NewPointer(true)
+	cfg.PostgresSearchSettings.EnableAutocomplete = model.NewPointer(true)
+	cfg.SqlSettings.DisableDatabaseSearch = model.NewPointer(true)
+
+	s.SearchEngine = searchengine.NewBroker(cfg)
+	s.Store = searchlayer.NewSearchLayer(&testlib.TestStore{Store: s.SQLStore}, s.SearchEngine, cfg)
+
+	s.PostgresEngine = NewPostgresEngine(cfg)
+	s.PostgresEngine.indexSync = true
+	s.SearchEngine.RegisterPostgresEngine(s.PostgresEngine)
+	if err := s.PostgresEngine.Start(); err != nil {
+		s.Require().FailNow("Cannot start postgresengine: %s", err.Error())
+	}
+}
+
+func (s *PostgresEngineTestSuite) TearDownSuite() {
+	s.PostgresEngine.Stop()
+	s.SQLStore.Close()
+	storetest.CleanupSqlSettings(s.SQLSettings)
+}
+
+func createPost(userId string, channelId string) *model.Post {
+	post := &model.Post{
+		Message:       model.NewRandomString(15),
+		ChannelId:     channelId,
+		PendingPostId: model.NewId() + ":" + strconv.FormatInt(model.GetMillis(), 10),
+		UserId:        userId,
+		CreateAt:      1000000,
+	}
+	post.PreSave()
+
+	return post
+}
+
+func (s *PostgresEngineTestSuite) countRows(table string) int {
+	var count int
+	err := s.PostgresEngine.DB.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&count)
+	s.Require().NoError(err)
+	return count
+}
+
+func (s *PostgresEngineTestSuite) TestPostgresSearchStoreTests() {
+	searchTestEngine := &searchtest.SearchTestEngine{
+		Driver: searchtest.EnginePostgresSearch,
+	}
+
+	s.Run("TestSearchChannelStore", func() {
+		searchtest.TestSearchChannelStore(s.T(), s.Store, searchTestEngine)
+	})
+
+	s.Run("TestSearchUserStore", func() {
+		searchtest.TestSearchUserStore(s.T(), s.Store, searchTestEngine)
+	})
+
+	s.Run("TestSearchPostStore", func() {
+		searchtest.TestSearchPostStore(s.T(), s.Store, searchTestEngine)
+	})
+
+	s.Run("TestSearchFileInfoStore", func() {
+		searchtest.TestSearchFileInfoStore(s.T(), s.Store, searchTestEngine)
+	})
+}
+
+func (s *PostgresEngineTestSuite) TestSearchPosts() {
+	s.Require().Nil(s.PostgresEngine.PurgeIndexes(s.Context))
+	teamID := model.NewId()
+	userID := model.NewId()
+	channel := &model.Channel{Id: model.NewId()}
+
+	indexPost := func(message string, createAt int64) *model.Post {
+		post := createPost(userID, channel.Id)
+		post.Message = message
+		post.CreateAt = createAt
+		require.Nil(s.T(), s.PostgresEngine.IndexPost(post, teamID))
+		return post
+	}
+	running := indexPost("The servers are running out of disk space", 1000)
+	restarted := indexPost("We restarted the database servers", 2000)
+	hashtag := indexPost("Deployment finished #Release", 3000)
+	hashtag.Hashtags = "#Release"
+	require.Nil(s.T(), s.PostgresEngine.IndexPost(hashtag, teamID))
+
+	search := func(params *model.SearchParams) []string {
+		ids, _, appErr := s.PostgresEngine.SearchPosts(model.ChannelList{channel}, []*model.SearchParams{params}, 0, 20)
+		require.Nil(s.T(), appErr)
+		return ids
+	}
+
+	s.Run("Should match the stemmed terms", func() {
+		s.Assert().Equal([]string{running.Id}, search(&model.SearchParams{Terms: "run"}))
+		s.Assert().Equal([]string{restarted.Id, running.Id}, search(&model.SearchParams{Terms: "server"}))
+	})
+
+	s.Run("Should match phrases", func() {
+		s.Assert().Equal([]string{restarted.Id}, search(&model.SearchParams{Terms: `"database servers"`}))
+		s.Assert().Empty(search(&model.SearchParams{Terms: `"servers database"`}))
+	})
+
+	s.Run("Should match prefixes", func() {
+		s.Assert().Equal([]string{restarted.Id}, search(&model.SearchParams{Terms: "datab*"}))
+	})
+
+	s.Run("Should exclude terms", func() {
+		s.Assert().Equal([]string{running.Id}, search(&model.SearchParams{Terms: "servers", ExcludedTerms: "database"}))
+	})
+
+	s.Run("Should match any of the terms", func() {
+		s.Assert().Equal([]string{hashtag.Id, running.Id}, search(&model.SearchParams{Terms: "deployment disk", OrTerms: true}))
+	})
+
+	s.Run("Should match hashtags case insensitively", func() {
+		s.Assert().Equal([]string{hashtag.Id}, search(&model.SearchParams{Terms: "#release", IsHashtag: true}))
+	})
+
+	s.Run("Should filter by user and date", func() {
+		s.Assert().Empty(search(&model.SearchParams{Terms: "servers", FromUsers: []string{model.NewId()}}))
+		s.Assert().Equal([]string{restarted.Id, running.Id}, search(&model.SearchParams{Terms: "servers", FromUsers: []string{userID}}))
+		s.Assert().Empty(search(&model.SearchParams{Terms: "servers", ExcludedUsers: []string{userID}}))
+	})
+
+	s.Run("Should not break on the tsquery syntax", func() {
+		s.Assert().Empty(search(&model.SearchParams{Terms: `it's & (broken) | !! 'quotes'*`}))
+	})
+}
+
+func (s *PostgresEngineTestSuite) TestDeleteChannelPosts() {
+	s.Run("Should remove all the posts that belongs to a channel", func() {
+		s.Require().Nil(s.PostgresEngine.PurgeIndexes(s.Context))
+		teamID := model.NewId()
+		userID := model.NewId()
+		channelID := model.NewId()
+		channelToAvoidID := model.NewId()
+		for i := 0; i < 10; i++ {
+			post := createPost(userID, channelID)
+			appErr := s.SearchEngine.PostgresEngine.IndexPost(post, teamID)
+			require.Nil(s.T(), appErr)
+		}
+		postToAvoid := createPost(userID, channelToAvoidID)
+		appErr := s.SearchEngine.PostgresEngine.IndexPost(postToAvoid, teamID)
+		require.Nil(s.T(), appErr)
+
+		appErr = s.SearchEngine.PostgresEngine.DeleteChannelPosts(s.Context, channelID)
+		require.Nil(s.T(), appErr)
+
+		require.Equal(s.T(), 1, s.countRows(PostTable))
+	})
+
+	s.Run("Shouldn't do anything if there is not posts for the selected channel", func() {
+		s.Require().Nil(s.PostgresEngine.PurgeIndexes(s.Context))
+		teamID := model.NewId()
+		userID := model.NewId()
+		channelID := model.NewId()
+		channelToDeleteID := model.NewId()
+		post := createPost(userID, channelID)
+		appErr := s.SearchEngine.PostgresEngine.IndexPost(post, teamID)
+		require.Nil(s.T(), appErr)
+
+		appErr = s.SearchEngine.PostgresEngine.DeleteChannelPosts(s.Context, channelToDeleteID)
+		require.Nil(s.T(), appErr)
+
+		require.Equal(s.T(), 1, s.countRows(PostTable))
+	})
+}
+
+func (s *PostgresEngineTestSuite) TestDeleteUserPosts() {
+	s.Require().Nil(s.PostgresEngine.PurgeIndexes(s.Context))
+	teamID := model.NewId()
+	userID := model.NewId()
+	userToAvoidID := model.NewId()
+	channelID := model.NewId()
+	for i := 0; i < 10; i++ {
+		post := createPost(userID, channelID)
+		appErr := s.SearchEngine.PostgresEngine.IndexPost(post, teamID)
+		require.Nil(s.T(), appErr)
+	}
+	postToAvoid := createPost(userToAvoidID, channelID)
+	appErr := s.SearchEngine.PostgresEngine.IndexPost(postToAvoid, teamID)
+	require.Nil(s.T(), appErr)
+
+	appErr = s.SearchEngine.PostgresEngine.DeleteUserPosts(s.Context, userID)
+	require.Nil(s.T(), appErr)
+
+	require.Equal(s.T(), 1, s.countRows(PostTable))
+}
+
+func (s *PostgresEngineTestSuite) TestPurgeIndexList() {
+	s.Require().Nil(s.PostgresEngine.PurgeIndexes(s.Context))
+	post := createPost(model.NewId(), model.NewId())
+	require.Nil(s.T(), s.PostgresEngine.IndexPost(post, model.NewId()))
+	channel := &model.Channel{Id: model.NewId(), TeamId: model.NewId(), Name: "town-square", DisplayName: "Town Square", Type: model.ChannelTypeOpen}
+	require.Nil(s.T(), s.PostgresEngine.IndexChannel(s.Context, channel, nil, nil))
+
+	require.Nil(s.T(), s.PostgresEngine.PurgeIndexList(s.Context, []string{PostIndex}))
+	require.Equal(s.T(), 0, s.countRows(PostTable))
+	require.Equal(s.T(), 1, s.countRows(ChannelTable))
+
+	require.NotNil(s.T(), s.PostgresEngine.PurgeIndexList(s.Context, []string{"unknown"}))
+}
+
+func (s *PostgresEngineTestSuite) TestIndexUserReplacesSuggestions() {
+	s.Require().Nil(s.PostgresEngine.PurgeIndexes(s.Context))
+	teamID := model.NewId()
+	user := &model.User{Id: model.NewId(), Username: "john.doe", FirstName: "Johnny"}
+	require.Nil(s.T(), s.PostgresEngine.IndexUser(s.Context, user, []string{teamID}, nil))
+
+	search := func(term string, allowFullNames bool) []string {
+		ids, appErr := s.PostgresEngine.SearchUsersInTeam(teamID, nil, term, &model.UserSearchOptions{AllowFullNames: allowFullNames, Limit: 10})
+		require.Nil(s.T(), appErr)
+		return ids
+	}
+	s.Assert().Equal([]string{user.Id}, search("do", false))
+	s.Assert().Empty(search("johnny", false))
+	s.Assert().Equal([]string{user.Id}, search("johnny", true))
+
+	user.Username = "jane.roe"
+	require.Nil(s.T(), s.PostgresEngine.IndexUser(s.Context, user, []string{teamID}, nil))
+	s.Assert().Empty(search("do", false))
+	s.Assert().Equal([]string{user.Id}, search("ro", false))
+
+	require.Nil(s.T(), s.PostgresEngine.DeleteUser(user))
+	require.Equal(s.T(), 0, s.countRows(UserSuggestionTable))
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package postgresengine
+
+import (
+	"strings"
+)
+
+type termKind int
+
+const (
+	wordTerm termKind = iota
+	prefixTerm
+	phraseTerm
+)
+
+type searchTerm struct {
+	kind termKind
+	text string
+}
+
+// condition is a SQL boolean expression using ? placeholders.
+type condition struct {
+	sql  string
+	args []any
+}
+
+var (
+	tsQueryLexemeEscaper = strings.NewReplacer(`\`, `\\`, `'`, `''`)
+	likeEscaper          = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+)
+
+// parseSearchTerms splits the terms of a search in quoted phrases, prefix
+// terms ending with a *, and plain words.
+func parseSearchTerms(terms string) []searchTerm {
+	result := []searchTerm{}
+	for i, part := range strings.Split(terms, `"`) {
+		if i%2 == 1 {
+			if phrase := strings.Join(strings.Fields(part), " "); phrase != "" {
+				result = append(result, searchTerm{kind: phraseTerm, text: phrase})
+			}
+			continue
+		}
+
+		for _, word := range strings.Fields(part) {
+			if strings.HasSuffix(word, "*") {
+				if word = strings.TrimRight(word, "*"); word != "" {
+					result = append(result, searchTerm{kind: prefixTerm, text: word})
+				}
+				continue
+			}
+			result = append(result, searchTerm{kind: wordTerm, text: word})
+		}
+	}
+	return result
+}
+
+// buildTSQuery returns the text search query matching the terms, combined
+// with the given tsquery operator, && or ||. The terms are always passed as
+// arguments and normalized with the text search configuration, so that the
+// stemming matches the one of the indexed documents and the user input
+// cannot break the tsquery syntax.
+func buildTSQuery(textSearchConfig, terms, operator string) (condition, bool) {
+	parsedTerms := parseSearchTerms(terms)
+	if len(parsedTerms) == 0 {
+		return condition{}, false
+	}
+
+	parts := make([]string, 0, len(parsedTerms))
+	args := make([]any, 0, 2*len(parsedTerms))
+	for _, term := range parsedTerms {
+		switch term.kind {
+		case phraseTerm:
+			parts = append(parts, "phraseto_tsquery(?::regconfig, ?)")
+			args = append(args, textSearchConfig, term.text)
+		case prefixTerm:
+			parts = append(parts, "to_tsquery(?::regconfig, ?)")
+			args = append(args, textSearchConfig, "'"+tsQueryLexemeEscaper.Replace(term.text)+"':*")
+		default:
+			parts = append(parts, "plainto_tsquery(?::regconfig, ?)")
+			args = append(args, textSearchConfig, term.text)
+		}
+	}
+
+	return condition{
+		sql:  "(" + strings.Join(parts, " "+operator+" ") + ")",
+		args: args,
+	}, true
+}
+
+// joinConditions combines the conditions with the given SQL operator, AND
+// or OR.
+func joinConditions(conditions []condition, operator string) condition {
+	parts := make([]string, 0, len(conditions))
+	args := []any{}
+	for _, c := range conditions {
+		parts = append(parts, c.sql)
+		args = append(args, c.args...)
+	}
+
+	return condition{
+		sql:  "(" + strings.Join(parts, " "+operator+" ") + ")",
+		args: args,
+	}
+}
+
+// suggestionPrefixCondition matches the documents having a suggestion
+// starting with the term in the given suggestion table, idColumn referencing
+// the documents. The suggestions are lowercased when indexed, and the LIKE
+// prefix match is served by their text_pattern_ops index. The filters are
+// extra conditions on the suggestions.
+func suggestionPrefixCondition(table, idColumn, term string, filters ...string) condition {
+	where := append([]string{"suggestion LIKE ?"}, filters...)
+	return condition{
+		sql:  "id IN (SELECT " + idColumn + " FROM " + table + " WHERE " + strings.Join(where, " AND ") + ")",
+		args: []any{likeEscaper.Replace(strings.ToLower(term)) + "%"},
+	}
+}
+
+func hashtagsFromTerms(terms string) []string {
+	hashtags := []string{}
+	for _, term := range strings.Fields(terms) {
+		if hashtag := normalizeHashtag(term); hashtag != "" {
+			hashtags = append(hashtags, hashtag)
+		}
+	}
+	return hashtags
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package postgresengine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSearchTerms(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Terms    string
+		Expected []searchTerm
+	}{
+		{
+			Name:     "empty terms",
+			Terms:    "   ",
+			Expected: []searchTerm{},
+		},
+		{
+			Name:  "plain words",
+			Terms: "hello  world",
+			Expected: []searchTerm{
+				{kind: wordTerm, text: "hello"},
+				{kind: wordTerm, text: "world"},
+			},
+		},
+		{
+			Name:  "prefix terms",
+			Terms: "data* * serv**",
+			Expected: []searchTerm{
+				{kind: prefixTerm, text: "data"},
+				{kind: prefixTerm, text: "serv"},
+			},
+		},
+		{
+			Name:  "phrases",
+			Terms: `before "database   servers" after ""`,
+			Expected: []searchTerm{
+				{kind: wordTerm, text: "before"},
+				{kind: phraseTerm, text: "database servers"},
+				{kind: wordTerm, text: "after"},
+			},
+		},
+		{
+			Name:  "unterminated phrase",
+			Terms: `"database servers`,
+			Expected: []searchTerm{
+				{kind: phraseTerm, text: "database servers"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, parseSearchTerms(tc.Terms))
+		})
+	}
+}
+
+func TestBuildTSQuery(t *testing.T) {
+	t.Run("no terms", func(t *testing.T) {
+		_, ok := buildTSQuery("english", " ", "&&")
+		require.False(t, ok)
+	})
+
+	t.Run("mixed terms", func(t *testing.T) {
+		c, ok := buildTSQuery("english", `hello "big world" it's*`, "||")
+		require.True(t, ok)
+		assert.Equal(t, "(plainto_tsquery(?::regconfig, ?) || phraseto_tsquery(?::regconfig, ?) || to_tsquery(?::regconfig, ?))", c.sql)
+		assert.Equal(t, []any{"english", "hello", "english", "big world", "english", `'it''s':*`}, c.args)
+	})
+
+	t.Run("escape the prefix lexemes", func(t *testing.T) {
+		c, ok := buildTSQuery("simple", `a\b*`, "&&")
+		require.True(t, ok)
+		assert.Equal(t, []any{"simple", `'a\\b':*`}, c.args)
+	})
+}
+
+func TestJoinConditions(t *testing.T) {
+	c := joinConditions([]condition{
+		{sql: "a = ?", args: []any{1}},
+		{sql: "b = ?", args: []any{2}},
+	}, "OR")
+	assert.Equal(t, "(a = ? OR b = ?)", c.sql)
+	assert.Equal(t, []any{1, 2}, c.args)
+}
+
+func TestSuggestionPrefixCondition(t *testing.T) {
+	c := suggestionPrefixCondition(ChannelSuggestionTable, "channelid", `Off_Topic%\`)
+	assert.Equal(t, `id IN (SELECT channelid FROM ftschannelsuggestions WHERE suggestion LIKE ?)`, c.sql)
+	assert.Equal(t, []any{`off\_topic\%\\%`}, c.args)
+
+	c = suggestionPrefixCondition(UserSuggestionTable, "userid", "john", "NOT fullname")
+	assert.Equal(t, `id IN (SELECT userid FROM ftsusersuggestions WHERE suggestion LIKE ? AND NOT fullname)`, c.sql)
+	assert.Equal(t, []any{"john%"}, c.args)
+}
+
+func TestHashtagsFromTerms(t *testing.T) {
+	assert.Equal(t, []string{"release", "deploy"}, hashtagsFromTerms("#Release  #deploy #"))
+}
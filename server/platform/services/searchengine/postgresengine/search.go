@@ -0,0 +1,672 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package postgresengine
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/lib/pq"
+	sq "github.com/mattermost/squirrel"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+const (
+	upsertPostQuery = `INSERT INTO ftsposts (id, teamid, channelid, userid, createat, type, hashtags, message)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, to_tsvector($8::regconfig, $9::text))
+		ON CONFLICT (id) DO UPDATE SET teamid = EXCLUDED.teamid, channelid = EXCLUDED.channelid, userid = EXCLUDED.userid,
+			createat = EXCLUDED.createat, type = EXCLUDED.type, hashtags = EXCLUDED.hashtags, message = EXCLUDED.message`
+
+	upsertFileQuery = `INSERT INTO ftsfiles (id, creatorid, channelid, postid, createat, extension, content)
+		VALUES ($1, $2, $3, $4, $5, $6, to_tsvector($7::regconfig, $8::text) || to_tsvector($7::regconfig, $9::text))
+		ON CONFLICT (id) DO UPDATE SET creatorid = EXCLUDED.creatorid, channelid = EXCLUDED.channelid, postid = EXCLUDED.postid,
+			createat = EXCLUDED.createat, extension = EXCLUDED.extension, content = EXCLUDED.content`
+
+	// The channel and user upserts replace the suggestions of the document
+	// in the same statement. The statements of the WITH clause all see the
+	// rows as they were before it, so the removed suggestions are only the
+	// previous ones.
+	upsertChannelQuery = `WITH channel AS (
+			INSERT INTO ftschannels (id, teamid, type, userids, teammemberids)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (id) DO UPDATE SET teamid = EXCLUDED.teamid, type = EXCLUDED.type, userids = EXCLUDED.userids,
+				teammemberids = EXCLUDED.teammemberids
+			RETURNING id
+		), removed AS (
+			DELETE FROM ftschannelsuggestions WHERE channelid = $1
+		)
+		INSERT INTO ftschannelsuggestions (channelid, suggestion)
+		SELECT DISTINCT channel.id, suggestion FROM channel, unnest($6::text[]) AS suggestion`
+
+	upsertUserQuery = `WITH u AS (
+			INSERT INTO ftsusers (id, teamsids, channelsids)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (id) DO UPDATE SET teamsids = EXCLUDED.teamsids, channelsids = EXCLUDED.channelsids
+			RETURNING id
+		), removed AS (
+			DELETE FROM ftsusersuggestions WHERE userid = $1
+		)
+		INSERT INTO ftsusersuggestions (userid, suggestion, fullname)
+		SELECT DISTINCT u.id, suggestion, suggestion <> ALL($5::text[]) FROM u, unnest($4::text[]) AS suggestion`
+)
+
+// execer is implemented by both sql.DB and sql.Tx, so that the documents
+// can be indexed one by one or in batches.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func (pe *PostgresEngine) indexPost(db execer, post *PGPost) error {
+	_, err := db.Exec(upsertPostQuery, post.Id, post.TeamId, post.ChannelId, post.UserId, post.CreateAt, post.Type, pq.Array(post.Hashtags), pe.textSearchConfig(), post.Message)
+	return err
+}
+
+func (pe *PostgresEngine) indexFile(db execer, file *PGFile) error {
+	_, err := db.Exec(upsertFileQuery, file.Id, file.CreatorId, file.ChannelId, file.PostId, file.CreateAt, file.Extension, pe.textSearchConfig(), file.Name, file.Content)
+	return err
+}
+
+func (pe *PostgresEngine) indexChannel(db execer, channel *PGChannel) error {
+	_, err := db.Exec(upsertChannelQuery, channel.Id, channel.TeamId, string(channel.Type), pq.Array(channel.UserIDs), pq.Array(channel.TeamMemberIDs), pq.Array(channel.NameSuggest))
+	return err
+}
+
+func (pe *PostgresEngine) indexUser(db execer, user *PGUser) error {
+	_, err := db.Exec(upsertUserQuery, user.Id, pq.Array(user.TeamsIds), pq.Array(user.ChannelsIds), pq.Array(user.SuggestionsWithFullname), pq.Array(user.SuggestionsWithoutFullname))
+	return err
+}
+
+func deleteDocument(db execer, table, id string) error {
+	_, err := db.Exec("DELETE FROM "+table+" WHERE id = $1", id)
+	return err
+}
+
+func (pe *PostgresEngine) deleteDocuments(table, column, value string) (int64, error) {
+	result, err := pe.DB.Exec("DELETE FROM "+table+" WHERE "+column+" = $1", value)
+	if err != nil {
+		return -1, err
+	}
+	return result.RowsAffected()
+}
+
+func (pe *PostgresEngine) inTransaction(f func(tx *sql.Tx) error) error {
+	tx, err := pe.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := f(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (pe *PostgresEngine) selectIds(builder sq.SelectBuilder) ([]string, error) {
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := pe.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// BulkIndexPosts indexes a batch of posts in a single transaction, removing
+// the deleted ones from the index.
+func (pe *PostgresEngine) BulkIndexPosts(posts []*model.PostForIndexing) error {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	return pe.inTransaction(func(tx *sql.Tx) error {
+		for _, post := range posts {
+			var err error
+			if post.DeleteAt == 0 {
+				err = pe.indexPost(tx, PGPostFromPostForIndexing(post))
+			} else {
+				err = deleteDocument(tx, PostTable, post.Id)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// BulkIndexFiles indexes a batch of files in a single transaction, removing
+// the ones that should not be indexed anymore.
+func (pe *PostgresEngine) BulkIndexFiles(files []*model.FileForIndexing) error {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	return pe.inTransaction(func(tx *sql.Tx) error {
+		for _, file := range files {
+			var err error
+			if file.ShouldIndex() {
+				err = pe.indexFile(tx, PGFileFromFileForIndexing(file))
+			} else {
+				err = deleteDocument(tx, FileTable, file.Id)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// BulkIndexChannels indexes a batch of channels in a single transaction,
+// and removes the deleted channels from the index.
+func (pe *PostgresEngine) BulkIndexChannels(channels []*PGChannel, deletedChannelIds []string) error {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	return pe.inTransaction(func(tx *sql.Tx) error {
+		for _, channel := range channels {
+			if err := pe.indexChannel(tx, channel); err != nil {
+				return err
+			}
+		}
+		for _, id := range deletedChannelIds {
+			if err := deleteDocument(tx, ChannelTable, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// BulkIndexUsers indexes a batch of users in a single transaction, removing
+// the deleted ones from the index.
+func (pe *PostgresEngine) BulkIndexUsers(users []*model.UserForIndexing) error {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	return pe.inTransaction(func(tx *sql.Tx) error {
+		for _, user := range users {
+			var err error
+			if user.DeleteAt == 0 {
+				err = pe.indexUser(tx, PGUserFromUserForIndexing(user))
+			} else {
+				err = deleteDocument(tx, UserTable, user.Id)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// buildSearchFilters applies the channel, user and date modifiers of a
+// search. userColumn is the column holding the author of the documents.
+func buildSearchFilters(builder sq.SelectBuilder, params *model.SearchParams, userColumn string) sq.SelectBuilder {
+	if len(params.InChannels) > 0 {
+		builder = builder.Where("channelid = ANY(?)", pq.Array(params.InChannels))
+	}
+
+	if len(params.ExcludedChannels) > 0 {
+		builder = builder.Where("channelid <> ALL(?)", pq.Array(params.ExcludedChannels))
+	}
+
+	if len(params.FromUsers) > 0 {
+		builder = builder.Where(userColumn+" = ANY(?)", pq.Array(params.FromUsers))
+	}
+
+	if len(params.ExcludedUsers) > 0 {
+		builder = builder.Where(userColumn+" <> ALL(?)", pq.Array(params.ExcludedUsers))
+	}
+
+	if params.OnDate != "" {
+		onDateStart, onDateEnd := params.GetOnDateMillis()
+		return builder.Where("createat BETWEEN ? AND ?", onDateStart, onDateEnd)
+	}
+
+	if params.ExcludedDate != "" {
+		excludedDateStart, excludedDateEnd := params.GetExcludedDateMillis()
+		builder = builder.Where("createat NOT BETWEEN ? AND ?", excludedDateStart, excludedDateEnd)
+	}
+
+	if params.AfterDate != "" {
+		builder = builder.Where("createat >= ?", params.GetAfterDateMillis())
+	}
+
+	if params.BeforeDate != "" {
+		builder = builder.Where("createat <= ?", params.GetBeforeDateMillis())
+	}
+
+	if params.ExcludedAfterDate != "" {
+		builder = builder.Where("createat < ?", params.GetExcludedAfterDateMillis())
+	}
+
+	if params.ExcludedBeforeDate != "" {
+		builder = builder.Where("createat > ?", params.GetExcludedBeforeDateMillis())
+	}
+
+	return builder
+}
+
+// buildTermsFilter requires the documents to match the terms, all of them or
+// any of them depending on orTerms, and none of the excluded terms.
+func buildTermsFilter(builder sq.SelectBuilder, terms, excludedTerms []condition, orTerms bool) sq.SelectBuilder {
+	if len(terms) > 0 {
+		operator := "AND"
+		if orTerms {
+			operator = "OR"
+		}
+		c := joinConditions(terms, operator)
+		builder = builder.Where(c.sql, c.args...)
+	}
+
+	if len(excludedTerms) > 0 {
+		c := joinConditions(excludedTerms, "OR")
+		builder = builder.Where("NOT "+c.sql, c.args...)
+	}
+
+	return builder
+}
+
+func channelIds(channels model.ChannelList) []string {
+	ids := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		ids = append(ids, channel.Id)
+	}
+	return ids
+}
+
+func (pe *PostgresEngine) IndexPost(post *model.Post, teamId string) *model.AppError {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	if err := pe.indexPost(pe.DB, PGPostFromPost(post, teamId)); err != nil {
+		return model.NewAppError("Postgresengine.IndexPost", "postgresengine.index_post.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (pe *PostgresEngine) SearchPosts(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]string, model.PostSearchMatches, *model.AppError) {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	query := pe.getQueryBuilder().
+		Select("id").
+		From(PostTable).
+		Where("channelid = ANY(?)", pq.Array(channelIds(channels))).
+		Where("type NOT LIKE ?", model.PostSystemMessagePrefix+"%")
+
+	tsQueryOperator := "&&"
+	if searchParams[0].OrTerms {
+		tsQueryOperator = "||"
+	}
+
+	var terms, excludedTerms []condition
+	for i, params := range searchParams {
+		// Date, channels and FromUsers filters come in all
+		// searchParams iteration, and as they are global to the
+		// query, we only need to process them once
+		if i == 0 {
+			query = buildSearchFilters(query, params, "userid")
+		}
+
+		if params.IsHashtag {
+			if params.Terms != "" {
+				arrayOperator := "@>"
+				if searchParams[0].OrTerms {
+					arrayOperator = "&&"
+				}
+				terms = append(terms, condition{sql: "hashtags " + arrayOperator + " ?", args: []any{pq.Array(hashtagsFromTerms(params.Terms))}})
+			} else if params.ExcludedTerms != "" {
+				excludedTerms = append(excludedTerms, condition{sql: "hashtags && ?", args: []any{pq.Array(hashtagsFromTerms(params.ExcludedTerms))}})
+			}
+			continue
+		}
+
+		if tsQuery, ok := buildTSQuery(pe.textSearchConfig(), params.Terms, tsQueryOperator); ok {
+			terms = append(terms, condition{sql: "message @@ " + tsQuery.sql, args: tsQuery.args})
+		}
+
+		if tsQuery, ok := buildTSQuery(pe.textSearchConfig(), params.ExcludedTerms, "||"); ok {
+			excludedTerms = append(excludedTerms, condition{sql: "message @@ " + tsQuery.sql, args: tsQuery.args})
+		}
+	}
+
+	query = buildTermsFilter(query, terms, excludedTerms, searchParams[0].OrTerms).
+		OrderBy("createat DESC").
+		Limit(uint64(perPage)).
+		Offset(uint64(page * perPage))
+
+	postIds, err := pe.selectIds(query)
+	if err != nil {
+		return nil, nil, model.NewAppError("Postgresengine.SearchPosts", "postgresengine.search_posts.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return postIds, model.PostSearchMatches{}, nil
+}
+
+func (pe *PostgresEngine) DeleteChannelPosts(rctx request.CTX, channelID string) *model.AppError {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	deleted, err := pe.deleteDocuments(PostTable, "channelid", channelID)
+	if err != nil {
+		return model.NewAppError("Postgresengine.DeleteChannelPosts", "postgresengine.delete_channel_posts.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	rctx.Logger().Info("Posts for channel deleted", mlog.String("channel_id", channelID), mlog.Int("deleted", deleted))
+
+	return nil
+}
+
+func (pe *PostgresEngine) DeleteUserPosts(rctx request.CTX, userID string) *model.AppError {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	deleted, err := pe.deleteDocuments(PostTable, "userid", userID)
+	if err != nil {
+		return model.NewAppError("Postgresengine.DeleteUserPosts", "postgresengine.delete_user_posts.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	rctx.Logger().Info("Posts for user deleted", mlog.String("user_id", userID), mlog.Int("deleted", deleted))
+
+	return nil
+}
+
+func (pe *PostgresEngine) DeletePost(post *model.Post) *model.AppError {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	if err := deleteDocument(pe.DB, PostTable, post.Id); err != nil {
+		return model.NewAppError("Postgresengine.DeletePost", "postgresengine.delete_post.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (pe *PostgresEngine) IndexChannel(_ request.CTX, channel *model.Channel, userIDs, teamMemberIDs []string) *model.AppError {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	if err := pe.indexChannel(pe.DB, PGChannelFromChannel(channel, userIDs, teamMemberIDs)); err != nil {
+		return model.NewAppError("Postgresengine.IndexChannel", "postgresengine.index_channel.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (pe *PostgresEngine) SearchChannels(teamId, userID, term string, isGuest, _ bool) ([]string, *model.AppError) {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	query := pe.getQueryBuilder().
+		Select("id").
+		From(ChannelTable).
+		Limit(model.ChannelSearchDefaultLimit)
+
+	if teamId != "" {
+		query = query.Where("teamid = ?", teamId)
+	} else {
+		query = query.Where("teammemberids @> ?", pq.Array([]string{userID}))
+	}
+
+	if isGuest {
+		query = query.Where("userids @> ?", pq.Array([]string{userID}))
+	} else {
+		query = query.Where("(type <> ? OR userids @> ?)", string(model.ChannelTypePrivate), pq.Array([]string{userID}))
+	}
+
+	if term != "" {
+		c := suggestionPrefixCondition(ChannelSuggestionTable, "channelid", term)
+		query = query.Where(c.sql, c.args...)
+	}
+
+	channelIds, err := pe.selectIds(query)
+	if err != nil {
+		return nil, model.NewAppError("Postgresengine.SearchChannels", "postgresengine.search_channels.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return channelIds, nil
+}
+
+func (pe *PostgresEngine) DeleteChannel(channel *model.Channel) *model.AppError {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	if err := deleteDocument(pe.DB, ChannelTable, channel.Id); err != nil {
+		return model.NewAppError("Postgresengine.DeleteChannel", "postgresengine.delete_channel.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (pe *PostgresEngine) IndexUser(_ request.CTX, user *model.User, teamsIds, channelsIds []string) *model.AppError {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	if err := pe.indexUser(pe.DB, PGUserFromUserAndTeams(user, teamsIds, channelsIds)); err != nil {
+		return model.NewAppError("Postgresengine.IndexUser", "postgresengine.index_user.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (pe *PostgresEngine) buildUsersQuery(term string, options *model.UserSearchOptions) sq.SelectBuilder {
+	query := pe.getQueryBuilder().
+		Select("id").
+		From(UserTable)
+
+	if options.Limit > 0 {
+		query = query.Limit(uint64(options.Limit))
+	}
+
+	if term != "" {
+		var c condition
+		if options.AllowFullNames {
+			c = suggestionPrefixCondition(UserSuggestionTable, "userid", term)
+		} else {
+			c = suggestionPrefixCondition(UserSuggestionTable, "userid", term, "NOT fullname")
+		}
+		query = query.Where(c.sql, c.args...)
+	}
+
+	return query
+}
+
+func (pe *PostgresEngine) SearchUsersInChannel(teamId, channelId string, restrictedToChannels []string, term string, options *model.UserSearchOptions) ([]string, []string, *model.AppError) {
+	if restrictedToChannels != nil && len(restrictedToChannels) == 0 {
+		return []string{}, []string{}, nil
+	}
+
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	// users in channel
+	uchanQuery := pe.buildUsersQuery(term, options).
+		Where("channelsids @> ?", pq.Array([]string{channelId}))
+
+	uchanIds, err := pe.selectIds(uchanQuery)
+	if err != nil {
+		return nil, nil, model.NewAppError("Postgresengine.SearchUsersInChannel", "postgresengine.search_users_in_channel.uchan.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	// users not in channel
+	nuchanQuery := pe.buildUsersQuery(term, options).
+		Where("teamsids @> ?", pq.Array([]string{teamId})).
+		Where("NOT channelsids @> ?", pq.Array([]string{channelId}))
+
+	if len(restrictedToChannels) > 0 {
+		nuchanQuery = nuchanQuery.Where("channelsids && ?", pq.Array(restrictedToChannels))
+	}
+
+	nuchanIds, err := pe.selectIds(nuchanQuery)
+	if err != nil {
+		return nil, nil, model.NewAppError("Postgresengine.SearchUsersInChannel", "postgresengine.search_users_in_channel.nuchan.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return uchanIds, nuchanIds, nil
+}
+
+func (pe *PostgresEngine) SearchUsersInTeam(teamId string, restrictedToChannels []string, term string, options *model.UserSearchOptions) ([]string, *model.AppError) {
+	if restrictedToChannels != nil && len(restrictedToChannels) == 0 {
+		return []string{}, nil
+	}
+
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	query := pe.buildUsersQuery(term, options)
+	if len(restrictedToChannels) > 0 {
+		// restricted channels are already filtered by team, so we
+		// can search only those matches
+		query = query.Where("channelsids && ?", pq.Array(restrictedToChannels))
+	} else if teamId != "" {
+		// this means that we only need to restrict by team
+		query = query.Where("teamsids @> ?", pq.Array([]string{teamId}))
+	}
+
+	usersIds, err := pe.selectIds(query)
+	if err != nil {
+		return nil, model.NewAppError("Postgresengine.SearchUsersInTeam", "postgresengine.search_users_in_team.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return usersIds, nil
+}
+
+func (pe *PostgresEngine) DeleteUser(user *model.User) *model.AppError {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	if err := deleteDocument(pe.DB, UserTable, user.Id); err != nil {
+		return model.NewAppError("Postgresengine.DeleteUser", "postgresengine.delete_user.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (pe *PostgresEngine) IndexFile(file *model.FileInfo, channelId string) *model.AppError {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	if err := pe.indexFile(pe.DB, PGFileFromFileInfo(file, channelId)); err != nil {
+		return model.NewAppError("Postgresengine.IndexFile", "postgresengine.index_file.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (pe *PostgresEngine) SearchFiles(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]string, *model.AppError) {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	query := pe.getQueryBuilder().
+		Select("id").
+		From(FileTable).
+		Where("channelid = ANY(?)", pq.Array(channelIds(channels)))
+
+	tsQueryOperator := "&&"
+	if searchParams[0].OrTerms {
+		tsQueryOperator = "||"
+	}
+
+	var terms, excludedTerms []condition
+	for i, params := range searchParams {
+		// Date, channels and FromUsers filters come in all
+		// searchParams iteration, and as they are global to the
+		// query, we only need to process them once
+		if i == 0 {
+			query = buildSearchFilters(query, params, "creatorid")
+
+			if len(params.Extensions) > 0 {
+				query = query.Where("extension = ANY(?)", pq.Array(params.Extensions))
+			}
+
+			if len(params.ExcludedExtensions) > 0 {
+				query = query.Where("extension <> ALL(?)", pq.Array(params.ExcludedExtensions))
+			}
+		}
+
+		if tsQuery, ok := buildTSQuery(pe.textSearchConfig(), params.Terms, tsQueryOperator); ok {
+			terms = append(terms, condition{sql: "content @@ " + tsQuery.sql, args: tsQuery.args})
+		}
+
+		if tsQuery, ok := buildTSQuery(pe.textSearchConfig(), params.ExcludedTerms, "||"); ok {
+			excludedTerms = append(excludedTerms, condition{sql: "content @@ " + tsQuery.sql, args: tsQuery.args})
+		}
+	}
+
+	query = buildTermsFilter(query, terms, excludedTerms, searchParams[0].OrTerms).
+		OrderBy("createat DESC").
+		Limit(uint64(perPage)).
+		Offset(uint64(page * perPage))
+
+	fileIds, err := pe.selectIds(query)
+	if err != nil {
+		return nil, model.NewAppError("Postgresengine.SearchFiles", "postgresengine.search_files.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	return fileIds, nil
+}
+
+func (pe *PostgresEngine) DeleteFile(fileID string) *model.AppError {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	if err := deleteDocument(pe.DB, FileTable, fileID); err != nil {
+		return model.NewAppError("Postgresengine.DeleteFile", "postgresengine.delete_file.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (pe *PostgresEngine) DeleteUserFiles(rctx request.CTX, userID string) *model.AppError {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	deleted, err := pe.deleteDocuments(FileTable, "creatorid", userID)
+	if err != nil {
+		return model.NewAppError("Postgresengine.DeleteUserFiles", "postgresengine.delete_user_files.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	rctx.Logger().Info("Files for user deleted", mlog.String("user_id", userID), mlog.Int("deleted", deleted))
+
+	return nil
+}
+
+func (pe *PostgresEngine) DeletePostFiles(rctx request.CTX, postID string) *model.AppError {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	deleted, err := pe.deleteDocuments(FileTable, "postid", postID)
+	if err != nil {
+		return model.NewAppError("Postgresengine.DeletePostFiles", "postgresengine.delete_post_files.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	rctx.Logger().Info("Files for post deleted", mlog.String("post_id", postID), mlog.Int("deleted", deleted))
+
+	return nil
+}
+
+func (pe *PostgresEngine) DeleteFilesBatch(rctx request.CTX, endTime, limit int64) *model.AppError {
+	pe.Mutex.RLock()
+	defer pe.Mutex.RUnlock()
+
+	result, err := pe.DB.Exec("DELETE FROM ftsfiles WHERE id IN (SELECT id FROM ftsfiles WHERE createat < $1 ORDER BY createat DESC LIMIT $2)", endTime, limit)
+	if err != nil {
+		return model.NewAppError("Postgresengine.DeleteFilesBatch", "postgresengine.delete_files_batch.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	deleted, _ := result.RowsAffected()
+
+	rctx.Logger().Info("Files in batch deleted", mlog.Int("endTime", endTime), mlog.Int("limit", limit), mlog.Int("deleted", deleted))
+
+	return nil
+}
@@ -21,10 +21,20 @@ func (seb *Broker) RegisterBleveEngine(be SearchEngineInterface) {
 	seb.BleveEngine = be
 }
 
+func (seb *Broker) RegisterPostgresEngine(pe SearchEngineInterface) {
+	seb.PostgresEngine = pe
+}
+
+func (seb *Broker) RegisterVectorEngine(ve VectorEngineInterface) {
+	seb.VectorEngine = ve
+}
+
 type Broker struct {
 	cfg                 *model.Config
 	ElasticsearchEngine SearchEngineInterface
 	BleveEngine         SearchEngineInterface
+	PostgresEngine      SearchEngineInterface
+	VectorEngine        VectorEngineInterface
 }
 
 func (seb *Broker) UpdateConfig(cfg *model.Config) *model.AppError {
@@ -37,6 +47,14 @@ func (seb *Broker) UpdateConfig(cfg *model.Config) *model.AppError {
 		seb.BleveEngine.UpdateConfig(cfg)
 	}
 
+	if seb.PostgresEngine != nil {
+		seb.PostgresEngine.UpdateConfig(cfg)
+	}
+
+	if seb.VectorEngine != nil {
+		seb.VectorEngine.UpdateConfig(cfg)
+	}
+
 	return nil
 }
 
@@ -48,9 +66,22 @@ func (seb *Broker) GetActiveEngines() []SearchEngineInterface {
 	if seb.BleveEngine != nil && seb.BleveEngine.IsActive() && seb.BleveEngine.IsIndexingEnabled() {
 		engines = append(engines, seb.BleveEngine)
 	}
+	if seb.PostgresEngine != nil && seb.PostgresEngine.IsActive() && seb.PostgresEngine.IsIndexingEnabled() {
+		engines = append(engines, seb.PostgresEngine)
+	}
 	return engines
 }
 
+// GetActiveVectorEngine returns the vector engine if it is started and
+// indexing the posts, nil otherwise. The vector engine is not one of the
+// active engines, as it only complements their keyword searches.
+func (seb *Broker) GetActiveVectorEngine() VectorEngineInterface {
+	if seb.VectorEngine != nil && seb.VectorEngine.IsActive() && seb.VectorEngine.IsIndexingEnabled() {
+		return seb.VectorEngine
+	}
+	return nil
+}
+
 func (seb *Broker) ActiveEngine() string {
 	activeEngines := seb.GetActiveEngines()
 	if len(activeEngines) > 0 {
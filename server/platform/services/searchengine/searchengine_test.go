@@ -40,3 +40,32 @@ func TestActiveEngine(t *testing.T) {
 
 	assert.Equal(t, "none", b.ActiveEngine())
 }
+
+func TestGetActiveVectorEngine(t *testing.T) {
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+
+	b := NewBroker(cfg)
+	assert.Nil(t, b.GetActiveVectorEngine())
+
+	inactiveMock := &mocks.VectorEngineInterface{}
+	inactiveMock.On("IsActive").Return(false)
+	b.RegisterVectorEngine(inactiveMock)
+	assert.Nil(t, b.GetActiveVectorEngine())
+
+	notIndexingMock := &mocks.VectorEngineInterface{}
+	notIndexingMock.On("IsActive").Return(true)
+	notIndexingMock.On("IsIndexingEnabled").Return(false)
+	b.RegisterVectorEngine(notIndexingMock)
+	assert.Nil(t, b.GetActiveVectorEngine())
+
+	vectorMock := &mocks.VectorEngineInterface{}
+	vectorMock.On("IsActive").Return(true)
+	vectorMock.On("IsIndexingEnabled").Return(true)
+	b.RegisterVectorEngine(vectorMock)
+	assert.Equal(t, vectorMock, b.GetActiveVectorEngine())
+
+	// The vector engine does not replace the keyword search engines.
+	assert.Empty(t, b.GetActiveEngines())
+	assert.Equal(t, "database", b.ActiveEngine())
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package vectorengine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// maxErrorBodyBytes caps how much of an error response of the embedding
+// endpoint is kept in the error.
+const maxErrorBodyBytes = 1024
+
+// embeddingRequest and embeddingResponse follow the embeddings API of
+// OpenAI, which most of the embedding servers able to run locally implement,
+// such as Text Embeddings Inference, Ollama or the llama.cpp server.
+type embeddingRequest struct {
+	Model string   `json:"model,omitempty"`
+	Input []string `json:"input"`
+}
+
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// embeddingClient computes the embedding vectors of texts with the
+// configured embedding endpoint.
+type embeddingClient struct {
+	httpClient *http.Client
+	url        string
+	model      string
+	dimensions int
+}
+
+func newEmbeddingClient(settings model.VectorSearchSettings) *embeddingClient {
+	return &embeddingClient{
+		httpClient: &http.Client{Timeout: time.Duration(*settings.RequestTimeoutSeconds) * time.Second},
+		url:        *settings.EmbeddingURL,
+		model:      *settings.EmbeddingModel,
+		dimensions: *settings.EmbeddingDimensions,
+	}
+}
+
+// embed returns the embedding vectors of the texts, in the same order.
+func (c *embeddingClient) embed(texts []string) ([][]float32, error) {
+	body, err := json.Marshal(embeddingRequest{Model: c.model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the embedding request: %w", err)
+	}
+
+	resp, err := c.httpClient.Post(c.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to request the embedding endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return nil, fmt.Errorf("the embedding endpoint returned status %d: %s", resp.StatusCode, bytes.TrimSpace(message))
+	}
+
+	var response embeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode the embedding response: %w", err)
+	}
+
+	if len(response.Data) != len(texts) {
+		return nil, fmt.Errorf("the embedding endpoint returned %d embeddings for %d texts", len(response.Data), len(texts))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, data := range response.Data {
+		if data.Index < 0 || data.Index >= len(texts) || vectors[data.Index] != nil {
+			return nil, fmt.Errorf("the embedding endpoint returned an invalid index %d", data.Index)
+		}
+		if len(data.Embedding) != c.dimensions {
+			return nil, fmt.Errorf("the embedding endpoint returned %d dimensions instead of %d", len(data.Embedding), c.dimensions)
+		}
+		vectors[data.Index] = data.Embedding
+	}
+
+	return vectors, nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package vectorengine
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddingClient(t *testing.T) {
+	var response string
+	status := http.StatusOK
+	var request embeddingRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	client := &embeddingClient{
+		httpClient: server.Client(),
+		url:        server.URL,
+		model:      "all-minilm",
+		dimensions: 2,
+	}
+
+	t.Run("embed texts", func(t *testing.T) {
+		response = `{"data": [{"index": 1, "embedding": [0.3, 0.4]}, {"index": 0, "embedding": [0.1, 0.2]}]}`
+		vectors, err := client.embed([]string{"hello", "world"})
+		require.NoError(t, err)
+		assert.Equal(t, [][]float32{{0.1, 0.2}, {0.3, 0.4}}, vectors)
+		assert.Equal(t, embeddingRequest{Model: "all-minilm", Input: []string{"hello", "world"}}, request)
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		status = http.StatusServiceUnavailable
+		defer func() { status = http.StatusOK }()
+		response = "model is loading"
+		_, err := client.embed([]string{"hello"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "503")
+		assert.Contains(t, err.Error(), "model is loading")
+	})
+
+	t.Run("wrong number of embeddings", func(t *testing.T) {
+		response = `{"data": [{"index": 0, "embedding": [0.1, 0.2]}]}`
+		_, err := client.embed([]string{"hello", "world"})
+		require.Error(t, err)
+	})
+
+	t.Run("wrong number of dimensions", func(t *testing.T) {
+		response = `{"data": [{"index": 0, "embedding": [0.1, 0.2, 0.3]}]}`
+		_, err := client.embed([]string{"hello"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "3 dimensions instead of 2")
+	})
+
+	t.Run("duplicated index", func(t *testing.T) {
+		response = `{"data": [{"index": 0, "embedding": [0.1, 0.2]}, {"index": 0, "embedding": [0.1, 0.2]}]}`
+		_, err := client.embed([]string{"hello", "world"})
+		require.Error(t, err)
+	})
+}
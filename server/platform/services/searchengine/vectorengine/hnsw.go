@@ -0,0 +1,364 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package vectorengine
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"time"
+)
+
+const (
+	// hnswM is the number of neighbors of the nodes on the upper levels of
+	// the graph, twice as many are kept on the bottom level.
+	hnswM = 16
+	// hnswEfConstruction is the number of candidate neighbors considered
+	// when inserting a node.
+	hnswEfConstruction = 200
+	// hnswEfSearch is the minimum number of candidates considered when
+	// searching the graph.
+	hnswEfSearch = 64
+)
+
+// hnswNode is a document of an HNSW graph. The deleted nodes are kept in the
+// graph to navigate through it, until the graph is compacted.
+type hnswNode struct {
+	Doc     vectorDocument
+	Level   int
+	Deleted bool
+	// Neighbors holds the neighbors of the node on each of its levels.
+	Neighbors [][]int32
+}
+
+// hnswGraph is a Hierarchical Navigable Small World graph, indexing the
+// vectors for approximate nearest neighbor searches (Malkov and Yashunin,
+// 2016). The vectors are normalized when added, so that their cosine
+// distance is computed with a dot product.
+//
+// The graph is not safe for concurrent use. Its exported fields are encoded
+// when the graph is saved.
+type hnswGraph struct {
+	Model        string
+	Dimensions   int
+	Nodes        []*hnswNode
+	EntryPoint   int32
+	MaxLevel     int
+	DeletedCount int
+
+	ids       map[string]int32
+	rng       *rand.Rand
+	levelMult float64
+}
+
+func newHNSWGraph(embeddingModel string, dimensions int) *hnswGraph {
+	g := &hnswGraph{
+		Model:      embeddingModel,
+		Dimensions: dimensions,
+		EntryPoint: -1,
+	}
+	g.init()
+	return g
+}
+
+// init sets the unexported fields of the graph, after it is created or
+// decoded.
+func (g *hnswGraph) init() {
+	g.ids = make(map[string]int32, len(g.Nodes))
+	for i, node := range g.Nodes {
+		if !node.Deleted {
+			g.ids[node.Doc.Id] = int32(i)
+		}
+	}
+	g.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	g.levelMult = 1 / math.Log(hnswM)
+}
+
+// Len returns the number of documents in the graph.
+func (g *hnswGraph) Len() int {
+	return len(g.ids)
+}
+
+type hnswCandidate struct {
+	node     int32
+	distance float32
+}
+
+// candidateHeap is a min-heap of candidates by distance, or a max-heap if
+// farthest is set.
+type candidateHeap struct {
+	items    []hnswCandidate
+	farthest bool
+}
+
+func (h *candidateHeap) Len() int { return len(h.items) }
+func (h *candidateHeap) Less(i, j int) bool {
+	if h.farthest {
+		return h.items[i].distance > h.items[j].distance
+	}
+	return h.items[i].distance < h.items[j].distance
+}
+func (h *candidateHeap) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *candidateHeap) Push(x any)    { h.items = append(h.items, x.(hnswCandidate)) }
+func (h *candidateHeap) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
+
+func normalize(vector []float32) []float32 {
+	var norm float64
+	for _, value := range vector {
+		norm += float64(value) * float64(value)
+	}
+	normalized := make([]float32, len(vector))
+	if norm == 0 {
+		return normalized
+	}
+	norm = math.Sqrt(norm)
+	for i, value := range vector {
+		normalized[i] = float32(float64(value) / norm)
+	}
+	return normalized
+}
+
+// distance returns the cosine distance between the normalized vector and the
+// vector of the node.
+func (g *hnswGraph) distance(vector []float32, node int32) float32 {
+	var dot float32
+	for i, value := range g.Nodes[node].Doc.Vector {
+		dot += value * vector[i]
+	}
+	return 1 - dot
+}
+
+func (g *hnswGraph) maxNeighbors(level int) int {
+	if level == 0 {
+		return 2 * hnswM
+	}
+	return hnswM
+}
+
+func (g *hnswGraph) randomLevel() int {
+	return int(-math.Log(1-g.rng.Float64()) * g.levelMult)
+}
+
+// searchLayer returns the ef nodes of the level closest to the vector found
+// from the entry points, the closest first.
+func (g *hnswGraph) searchLayer(vector []float32, entryPoints []hnswCandidate, ef, level int) []hnswCandidate {
+	visited := make(map[int32]struct{}, ef*4)
+	candidates := &candidateHeap{}
+	results := &candidateHeap{farthest: true}
+	for _, entryPoint := range entryPoints {
+		visited[entryPoint.node] = struct{}{}
+		heap.Push(candidates, entryPoint)
+		heap.Push(results, entryPoint)
+		if results.Len() > ef {
+			heap.Pop(results)
+		}
+	}
+
+	for candidates.Len() > 0 {
+		candidate := heap.Pop(candidates).(hnswCandidate)
+		if results.Len() >= ef && candidate.distance > results.items[0].distance {
+			break
+		}
+
+		node := g.Nodes[candidate.node]
+		if level >= len(node.Neighbors) {
+			continue
+		}
+		for _, neighbor := range node.Neighbors[level] {
+			if _, ok := visited[neighbor]; ok {
+				continue
+			}
+			visited[neighbor] = struct{}{}
+
+			distance := g.distance(vector, neighbor)
+			if results.Len() < ef || distance < results.items[0].distance {
+				heap.Push(candidates, hnswCandidate{node: neighbor, distance: distance})
+				heap.Push(results, hnswCandidate{node: neighbor, distance: distance})
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	closest := results.items
+	sort.Slice(closest, func(i, j int) bool { return closest[i].distance < closest[j].distance })
+	return closest
+}
+
+// descend returns the node closest to the vector on the given level, found
+// greedily from the entry point of the graph.
+func (g *hnswGraph) descend(vector []float32, level int) []hnswCandidate {
+	entryPoints := []hnswCandidate{{node: g.EntryPoint, distance: g.distance(vector, g.EntryPoint)}}
+	for l := g.MaxLevel; l > level; l-- {
+		entryPoints = g.searchLayer(vector, entryPoints, 1, l)[:1]
+	}
+	return entryPoints
+}
+
+// connect adds the neighbor to the node on the level, dropping its farthest
+// neighbor if it has too many.
+func (g *hnswGraph) connect(node, neighbor int32, level int) {
+	n := g.Nodes[node]
+	n.Neighbors[level] = append(n.Neighbors[level], neighbor)
+	if len(n.Neighbors[level]) <= g.maxNeighbors(level) {
+		return
+	}
+
+	candidates := make([]hnswCandidate, 0, len(n.Neighbors[level]))
+	for _, id := range n.Neighbors[level] {
+		candidates = append(candidates, hnswCandidate{node: id, distance: g.distance(n.Doc.Vector, id)})
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+
+	neighbors := make([]int32, 0, g.maxNeighbors(level))
+	for _, candidate := range candidates[:g.maxNeighbors(level)] {
+		neighbors = append(neighbors, candidate.node)
+	}
+	n.Neighbors[level] = neighbors
+}
+
+// Add adds the document to the graph, replacing the document with the same
+// id if any.
+func (g *hnswGraph) Add(doc vectorDocument) {
+	g.Delete(doc.Id)
+
+	doc.Vector = normalize(doc.Vector)
+	level := g.randomLevel()
+	id := int32(len(g.Nodes))
+	g.Nodes = append(g.Nodes, &hnswNode{
+		Doc:       doc,
+		Level:     level,
+		Neighbors: make([][]int32, level+1),
+	})
+	g.ids[doc.Id] = id
+
+	if g.EntryPoint < 0 {
+		g.EntryPoint = id
+		g.MaxLevel = level
+		return
+	}
+
+	entryPoints := g.descend(doc.Vector, level)
+	for l := min(level, g.MaxLevel); l >= 0; l-- {
+		candidates := g.searchLayer(doc.Vector, entryPoints, hnswEfConstruction, l)
+		neighbors := candidates[:min(len(candidates), g.maxNeighbors(l))]
+		for _, neighbor := range neighbors {
+			g.Nodes[id].Neighbors[l] = append(g.Nodes[id].Neighbors[l], neighbor.node)
+			g.connect(neighbor.node, id, l)
+		}
+		entryPoints = candidates
+	}
+
+	if level > g.MaxLevel {
+		g.MaxLevel = level
+		g.EntryPoint = id
+	}
+}
+
+// Delete marks the document as deleted, and returns whether it was found.
+func (g *hnswGraph) Delete(docId string) bool {
+	id, ok := g.ids[docId]
+	if !ok {
+		return false
+	}
+	g.Nodes[id].Deleted = true
+	delete(g.ids, docId)
+	g.DeletedCount++
+	return true
+}
+
+// DeleteWhere deletes the documents matching the filter, and returns how
+// many were deleted.
+func (g *hnswGraph) DeleteWhere(filter func(doc *vectorDocument) bool) int64 {
+	var deleted int64
+	for _, node := range g.Nodes {
+		if !node.Deleted && filter(&node.Doc) {
+			g.Delete(node.Doc.Id)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// Search returns up to limit documents matching the filter, the closest to
+// the vector first. The search widens until enough documents match the
+// filter or the whole graph was explored.
+func (g *hnswGraph) Search(vector []float32, limit int, filter func(doc *vectorDocument) bool) []hnswCandidate {
+	if g.EntryPoint < 0 || limit <= 0 {
+		return nil
+	}
+
+	vector = normalize(vector)
+	entryPoints := g.descend(vector, 0)
+	for ef := max(limit, hnswEfSearch); ; ef *= 4 {
+		candidates := g.searchLayer(vector, entryPoints, ef, 0)
+
+		results := make([]hnswCandidate, 0, limit)
+		for _, candidate := range candidates {
+			node := g.Nodes[candidate.node]
+			if node.Deleted || !filter(&node.Doc) {
+				continue
+			}
+			results = append(results, candidate)
+			if len(results) == limit {
+				return results
+			}
+		}
+
+		if len(candidates) < ef || ef >= len(g.Nodes) {
+			return results
+		}
+	}
+}
+
+// Document returns the document of the node.
+func (g *hnswGraph) Document(node int32) *vectorDocument {
+	return &g.Nodes[node].Doc
+}
+
+// NeedsCompaction returns whether enough documents were deleted for the
+// graph to be rebuilt without them.
+func (g *hnswGraph) NeedsCompaction() bool {
+	return g.DeletedCount > 0 && g.DeletedCount*4 > len(g.Nodes)
+}
+
+// Snapshot returns a copy of the graph sharing the vectors, which are never
+// modified, to be encoded while the graph keeps changing.
+func (g *hnswGraph) Snapshot() *hnswGraph {
+	snapshot := &hnswGraph{
+		Model:        g.Model,
+		Dimensions:   g.Dimensions,
+		Nodes:        make([]*hnswNode, len(g.Nodes)),
+		EntryPoint:   g.EntryPoint,
+		MaxLevel:     g.MaxLevel,
+		DeletedCount: g.DeletedCount,
+	}
+	for i, node := range g.Nodes {
+		copied := *node
+		copied.Neighbors = make([][]int32, len(node.Neighbors))
+		for level, neighbors := range node.Neighbors {
+			copied.Neighbors[level] = slices.Clone(neighbors)
+		}
+		snapshot.Nodes[i] = &copied
+	}
+	return snapshot
+}
+
+// Compact returns a new graph without the deleted documents.
+func (g *hnswGraph) Compact() *hnswGraph {
+	compacted := newHNSWGraph(g.Model, g.Dimensions)
+	for _, node := range g.Nodes {
+		if !node.Deleted {
+			compacted.Add(node.Doc)
+		}
+	}
+	return compacted
+}
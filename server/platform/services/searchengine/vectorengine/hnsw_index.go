@@ -0,0 +1,252 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package vectorengine
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	hnswIndexFile = "posts.hnsw"
+
+	// hnswSaveInterval is how often the changes of the index are saved to
+	// its file. The index is also saved when it is closed.
+	hnswSaveInterval = time.Minute
+)
+
+// hnswIndex stores the vectors in an HNSW graph kept in memory, and saved to
+// a file of the index directory.
+type hnswIndex struct {
+	mu           sync.RWMutex
+	path         string
+	model        string
+	dimensions   int
+	graph        *hnswGraph
+	changes      uint64
+	savedChanges uint64
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+func newHNSWIndex(dir, embeddingModel string, dimensions int) *hnswIndex {
+	return &hnswIndex{
+		path:       filepath.Join(dir, hnswIndexFile),
+		model:      embeddingModel,
+		dimensions: dimensions,
+	}
+}
+
+func (hi *hnswIndex) open() error {
+	if err := os.MkdirAll(filepath.Dir(hi.path), 0700); err != nil {
+		return fmt.Errorf("failed to create the index directory: %w", err)
+	}
+
+	graph, err := loadHNSWGraph(hi.path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// An outdated graph is replaced by an empty one, which is not saved
+	// until the index is reset, so that the file keeps the outdated graph.
+	var outdatedErr error
+	if graph != nil && (graph.Model != hi.model || graph.Dimensions != hi.dimensions) {
+		outdatedErr = fmt.Errorf("%w: the index was built with the model %q of %d dimensions", errOutdatedIndex, graph.Model, graph.Dimensions)
+		graph = nil
+	}
+	if graph == nil {
+		graph = newHNSWGraph(hi.model, hi.dimensions)
+	}
+	hi.graph = graph
+
+	hi.stop = make(chan struct{})
+	hi.stopped = make(chan struct{})
+	go hi.saveLoop()
+
+	return outdatedErr
+}
+
+func (hi *hnswIndex) reset() error {
+	hi.mu.Lock()
+	defer hi.mu.Unlock()
+
+	hi.graph = newHNSWGraph(hi.model, hi.dimensions)
+	hi.changes++
+	return nil
+}
+
+func loadHNSWGraph(path string) (*hnswGraph, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var graph hnswGraph
+	if err := gob.NewDecoder(bufio.NewReader(file)).Decode(&graph); err != nil {
+		return nil, fmt.Errorf("failed to decode the vector index %s: %w", path, err)
+	}
+	graph.init()
+	return &graph, nil
+}
+
+func (hi *hnswIndex) saveLoop() {
+	defer close(hi.stopped)
+
+	ticker := time.NewTicker(hnswSaveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-hi.stop:
+			return
+		case <-ticker.C:
+			if err := hi.save(); err != nil {
+				mlog.Error("Failed to save the vector index", mlog.String("path", hi.path), mlog.Err(err))
+			}
+		}
+	}
+}
+
+// save writes the graph to the index file if it changed since it was last
+// saved. The file is replaced atomically, so that a crash while saving
+// keeps the previous version of the index.
+func (hi *hnswIndex) save() error {
+	hi.mu.Lock()
+	if hi.changes == hi.savedChanges {
+		hi.mu.Unlock()
+		return nil
+	}
+	if hi.graph.NeedsCompaction() {
+		hi.graph = hi.graph.Compact()
+	}
+	hi.mu.Unlock()
+
+	// The graph is encoded from a snapshot, so that the index is not locked
+	// while the file is written.
+	hi.mu.RLock()
+	changes := hi.changes
+	snapshot := hi.graph.Snapshot()
+	hi.mu.RUnlock()
+
+	if err := writeHNSWGraph(hi.path, snapshot); err != nil {
+		return err
+	}
+
+	hi.mu.Lock()
+	hi.savedChanges = changes
+	hi.mu.Unlock()
+	return nil
+}
+
+func writeHNSWGraph(path string, graph *hnswGraph) error {
+	tmpPath := path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	if err := gob.NewEncoder(writer).Encode(graph); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to encode the vector index: %w", err)
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+func (hi *hnswIndex) close() error {
+	close(hi.stop)
+	<-hi.stopped
+	return hi.save()
+}
+
+func (hi *hnswIndex) upsert(docs []*vectorDocument) error {
+	hi.mu.Lock()
+	defer hi.mu.Unlock()
+
+	for _, doc := range docs {
+		hi.graph.Add(*doc)
+	}
+	hi.changes++
+	return nil
+}
+
+func (hi *hnswIndex) delete(postIds []string) error {
+	hi.mu.Lock()
+	defer hi.mu.Unlock()
+
+	for _, postId := range postIds {
+		if hi.graph.Delete(postId) {
+			hi.changes++
+		}
+	}
+	return nil
+}
+
+func (hi *hnswIndex) deleteWhere(filter func(doc *vectorDocument) bool) (int64, error) {
+	hi.mu.Lock()
+	defer hi.mu.Unlock()
+
+	deleted := hi.graph.DeleteWhere(filter)
+	if deleted > 0 {
+		hi.changes++
+	}
+	return deleted, nil
+}
+
+func (hi *hnswIndex) deleteChannelPosts(channelId string) (int64, error) {
+	return hi.deleteWhere(func(doc *vectorDocument) bool { return doc.ChannelId == channelId })
+}
+
+func (hi *hnswIndex) deleteUserPosts(userId string) (int64, error) {
+	return hi.deleteWhere(func(doc *vectorDocument) bool { return doc.UserId == userId })
+}
+
+func (hi *hnswIndex) search(vector []float32, channelIds []string, limit int) ([]*model.PostSearchHit, error) {
+	hi.mu.RLock()
+	defer hi.mu.RUnlock()
+
+	channels := make(map[string]bool, len(channelIds))
+	for _, channelId := range channelIds {
+		channels[channelId] = true
+	}
+
+	results := hi.graph.Search(vector, limit, func(doc *vectorDocument) bool { return channels[doc.ChannelId] })
+
+	hits := make([]*model.PostSearchHit, 0, len(results))
+	for _, result := range results {
+		hits = append(hits, &model.PostSearchHit{
+			Id:    hi.graph.Document(result.node).Id,
+			Score: float64(1 - result.distance),
+		})
+	}
+	return hits, nil
+}
+
+func (hi *hnswIndex) purge() error {
+	hi.mu.Lock()
+	defer hi.mu.Unlock()
+
+	hi.graph = newHNSWGraph(hi.model, hi.dimensions)
+	hi.changes++
+	return nil
+}
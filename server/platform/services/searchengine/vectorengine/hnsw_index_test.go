@@ -0,0 +1,100 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package vectorengine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHNSWIndex(t *testing.T) {
+	dir := t.TempDir()
+
+	index := newHNSWIndex(dir, "test-model", 3)
+	require.NoError(t, index.open())
+
+	require.NoError(t, index.upsert([]*vectorDocument{
+		{Id: "post1", ChannelId: "channel1", UserId: "user1", Vector: []float32{1, 0, 0}},
+		{Id: "post2", ChannelId: "channel1", UserId: "user2", Vector: []float32{0.9, 0.1, 0}},
+		{Id: "post3", ChannelId: "channel2", UserId: "user1", Vector: []float32{0, 1, 0}},
+	}))
+
+	hits, err := index.search([]float32{1, 0, 0}, []string{"channel1", "channel2"}, 2)
+	require.NoError(t, err)
+	require.Len(t, hits, 2)
+	assert.Equal(t, "post1", hits[0].Id)
+	assert.InDelta(t, 1, hits[0].Score, 1e-5)
+	assert.Equal(t, "post2", hits[1].Id)
+
+	hits, err = index.search([]float32{1, 0, 0}, []string{"channel2"}, 2)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, "post3", hits[0].Id)
+
+	t.Run("the index is saved when closed", func(t *testing.T) {
+		require.NoError(t, index.close())
+
+		index = newHNSWIndex(dir, "test-model", 3)
+		require.NoError(t, index.open())
+		hits, err := index.search([]float32{1, 0, 0}, []string{"channel1", "channel2"}, 3)
+		require.NoError(t, err)
+		assert.Len(t, hits, 3)
+	})
+
+	t.Run("delete posts", func(t *testing.T) {
+		require.NoError(t, index.delete([]string{"post1"}))
+		deleted, err := index.deleteUserPosts("user1")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		hits, err := index.search([]float32{1, 0, 0}, []string{"channel1", "channel2"}, 3)
+		require.NoError(t, err)
+		require.Len(t, hits, 1)
+		assert.Equal(t, "post2", hits[0].Id)
+
+		deleted, err = index.deleteChannelPosts("channel1")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+	})
+
+	t.Run("purge the index", func(t *testing.T) {
+		require.NoError(t, index.upsert([]*vectorDocument{{Id: "post4", ChannelId: "channel1", Vector: []float32{0, 0, 1}}}))
+		require.NoError(t, index.purge())
+
+		hits, err := index.search([]float32{0, 0, 1}, []string{"channel1"}, 3)
+		require.NoError(t, err)
+		assert.Empty(t, hits)
+	})
+
+	t.Run("the index is outdated when the model changes", func(t *testing.T) {
+		require.NoError(t, index.upsert([]*vectorDocument{{Id: "post5", ChannelId: "channel1", Vector: []float32{0, 0, 1}}}))
+		require.NoError(t, index.close())
+
+		index = newHNSWIndex(dir, "other-model", 3)
+		require.ErrorIs(t, index.open(), errOutdatedIndex)
+		require.NoError(t, index.close())
+
+		// The outdated index is kept until it is reset.
+		index = newHNSWIndex(dir, "test-model", 3)
+		require.NoError(t, index.open())
+		hits, err := index.search([]float32{0, 0, 1}, []string{"channel1"}, 3)
+		require.NoError(t, err)
+		assert.Len(t, hits, 1)
+		require.NoError(t, index.close())
+
+		index = newHNSWIndex(dir, "other-model", 3)
+		require.ErrorIs(t, index.open(), errOutdatedIndex)
+		require.NoError(t, index.reset())
+		require.NoError(t, index.close())
+
+		index = newHNSWIndex(dir, "other-model", 3)
+		require.NoError(t, index.open())
+		defer index.close()
+		hits, err = index.search([]float32{0, 0, 1}, []string{"channel1"}, 3)
+		require.NoError(t, err)
+		assert.Empty(t, hits)
+	})
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package vectorengine
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func randomVector(rng *rand.Rand, dimensions int) []float32 {
+	vector := make([]float32, dimensions)
+	for i := range vector {
+		vector[i] = rng.Float32()*2 - 1
+	}
+	return vector
+}
+
+func exactNeighbors(docs []vectorDocument, vector []float32, limit int, filter func(doc *vectorDocument) bool) []string {
+	type scored struct {
+		id       string
+		distance float32
+	}
+	query := normalize(vector)
+	var all []scored
+	for i := range docs {
+		if !filter(&docs[i]) {
+			continue
+		}
+		var dot float32
+		for j, value := range normalize(docs[i].Vector) {
+			dot += value * query[j]
+		}
+		all = append(all, scored{id: docs[i].Id, distance: 1 - dot})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].distance < all[j].distance })
+
+	ids := []string{}
+	for _, s := range all[:min(limit, len(all))] {
+		ids = append(ids, s.id)
+	}
+	return ids
+}
+
+func searchIds(g *hnswGraph, vector []float32, limit int, filter func(doc *vectorDocument) bool) []string {
+	ids := []string{}
+	for _, result := range g.Search(vector, limit, filter) {
+		ids = append(ids, g.Document(result.node).Id)
+	}
+	return ids
+}
+
+func recall(expected, actual []string) float64 {
+	found := 0
+	for _, id := range expected {
+		for _, other := range actual {
+			if id == other {
+				found++
+				break
+			}
+		}
+	}
+	return float64(found) / float64(len(expected))
+}
+
+func TestHNSWGraph(t *testing.T) {
+	const dimensions = 32
+	rng := rand.New(rand.NewSource(42))
+	all := func(doc *vectorDocument) bool { return true }
+
+	g := newHNSWGraph("test-model", dimensions)
+	assert.Empty(t, g.Search(randomVector(rng, dimensions), 10, all))
+
+	docs := make([]vectorDocument, 2000)
+	for i := range docs {
+		docs[i] = vectorDocument{
+			Id:        strconv.Itoa(i),
+			ChannelId: "channel" + strconv.Itoa(i%4),
+			UserId:    "user" + strconv.Itoa(i%7),
+			Vector:    randomVector(rng, dimensions),
+		}
+		g.Add(docs[i])
+	}
+	require.Equal(t, len(docs), g.Len())
+
+	t.Run("find the nearest neighbors", func(t *testing.T) {
+		var total float64
+		for i := 0; i < 20; i++ {
+			query := randomVector(rng, dimensions)
+			total += recall(exactNeighbors(docs, query, 10, all), searchIds(g, query, 10, all))
+		}
+		assert.GreaterOrEqual(t, total/20, 0.9)
+	})
+
+	t.Run("find a document by its own vector", func(t *testing.T) {
+		results := g.Search(docs[123].Vector, 1, all)
+		require.Len(t, results, 1)
+		assert.Equal(t, "123", g.Document(results[0].node).Id)
+		assert.InDelta(t, 0, results[0].distance, 1e-5)
+	})
+
+	t.Run("filter the documents", func(t *testing.T) {
+		inChannel := func(doc *vectorDocument) bool { return doc.ChannelId == "channel1" }
+		query := randomVector(rng, dimensions)
+		ids := searchIds(g, query, 10, inChannel)
+		require.Len(t, ids, 10)
+		for _, id := range ids {
+			n, _ := strconv.Atoi(id)
+			assert.Equal(t, 1, n%4)
+		}
+		assert.GreaterOrEqual(t, recall(exactNeighbors(docs, query, 10, inChannel), ids), 0.8)
+
+		none := func(doc *vectorDocument) bool { return false }
+		assert.Empty(t, g.Search(query, 10, none))
+	})
+
+	t.Run("replace a document", func(t *testing.T) {
+		docs[5].Vector = randomVector(rng, dimensions)
+		g.Add(docs[5])
+		assert.Equal(t, len(docs), g.Len())
+
+		results := g.Search(docs[5].Vector, 1, all)
+		require.Len(t, results, 1)
+		assert.Equal(t, "5", g.Document(results[0].node).Id)
+	})
+
+	t.Run("delete documents", func(t *testing.T) {
+		assert.True(t, g.Delete("10"))
+		assert.False(t, g.Delete("10"))
+		assert.NotContains(t, searchIds(g, docs[10].Vector, 5, all), "10")
+
+		deleted := g.DeleteWhere(func(doc *vectorDocument) bool { return doc.UserId == "user3" })
+		assert.NotZero(t, deleted)
+		for _, id := range searchIds(g, randomVector(rng, dimensions), 50, all) {
+			n, _ := strconv.Atoi(id)
+			assert.NotEqual(t, 3, n%7)
+		}
+	})
+
+	t.Run("snapshot the graph", func(t *testing.T) {
+		snapshot := g.Snapshot()
+		require.Len(t, snapshot.Nodes, len(g.Nodes))
+
+		g.Add(vectorDocument{Id: "new", Vector: randomVector(rng, dimensions)})
+		assert.Len(t, snapshot.Nodes, len(g.Nodes)-1)
+		for i, node := range snapshot.Nodes {
+			assert.NotSame(t, g.Nodes[i], node)
+		}
+		assert.True(t, g.Delete("new"))
+	})
+
+	t.Run("compact the graph", func(t *testing.T) {
+		assert.False(t, g.NeedsCompaction())
+		g.DeleteWhere(func(doc *vectorDocument) bool { return doc.ChannelId == "channel0" })
+		require.True(t, g.NeedsCompaction())
+
+		compacted := g.Compact()
+		assert.Equal(t, g.Len(), compacted.Len())
+		assert.Equal(t, g.Len(), len(compacted.Nodes))
+		assert.Zero(t, compacted.DeletedCount)
+
+		results := compacted.Search(docs[2].Vector, 1, all)
+		require.Len(t, results, 1)
+		assert.Equal(t, "2", compacted.Document(results[0].node).Id)
+	})
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package indexer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/v8/channels/jobs"
+	"github.com/mattermost/mattermost/server/v8/channels/store/storetest"
+	"github.com/mattermost/mattermost/server/v8/channels/utils/testutils"
+	"github.com/mattermost/mattermost/server/v8/platform/services/searchengine/vectorengine"
+)
+
+func TestVectorIndexer(t *testing.T) {
+	mockStore := &storetest.Store{}
+	defer mockStore.AssertExpectations(t)
+
+	t.Run("Fail the job when the engine is not active", func(t *testing.T) {
+		job := &model.Job{
+			Id:       model.NewId(),
+			CreateAt: model.GetMillis(),
+			Status:   model.JobStatusPending,
+			Type:     model.JobTypeVectorSearchIndexing,
+		}
+
+		mockStore.JobStore.On("UpdateStatusOptimistically", job.Id, model.JobStatusPending, model.JobStatusInProgress).Return(true, nil)
+		mockStore.JobStore.On("UpdateOptimistically", job, model.JobStatusInProgress).Return(true, nil)
+
+		cfg := &model.Config{}
+		cfg.SetDefaults()
+
+		jobServer := &jobs.JobServer{
+			Store: mockStore,
+			ConfigService: &testutils.StaticConfigService{
+				Cfg: cfg,
+			},
+		}
+
+		worker := &VectorIndexerWorker{
+			jobServer: jobServer,
+			engine:    vectorengine.NewVectorEngine(cfg),
+			logger:    mlog.CreateConsoleTestLogger(t),
+		}
+
+		worker.DoJob(job)
+
+		require.Equal(t, model.JobStatusError, job.Status)
+		require.Contains(t, job.Data["error"], "vectorengine.indexer.do_job.engine_inactive")
+	})
+}
+
+func TestIndexingProgress(t *testing.T) {
+	progress := IndexingProgress{}
+	require.Equal(t, int64(0), progress.CurrentProgress())
+
+	progress.TotalPostsCount = 200
+	progress.DonePostsCount = 50
+	require.Equal(t, int64(25), progress.CurrentProgress())
+
+	// The posts created while the job runs may be indexed too.
+	progress.DonePostsCount = 250
+	require.Equal(t, int64(100), progress.CurrentProgress())
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package vectorengine
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	// The tables are created by the database migrations when the pgvector
+	// extension is available. vecindexinfo records the embedding model the
+	// vectors of vecposts were computed with.
+	PostTable      = "vecposts"
+	IndexInfoTable = "vecindexinfo"
+
+	// The index opens its own connection pool, as the search engines are
+	// started before the store. Its queries are short, so the pool is kept
+	// small rather than sized like the pool of the store.
+	dbMaxOpenConns = 4
+	dbMaxIdleConns = 1
+
+	upsertVectorQuery = `INSERT INTO vecposts (id, channelid, userid, createat, embedding)
+		VALUES ($1, $2, $3, $4, $5::vector)
+		ON CONFLICT (id) DO UPDATE SET channelid = EXCLUDED.channelid, userid = EXCLUDED.userid,
+			createat = EXCLUDED.createat, embedding = EXCLUDED.embedding`
+
+	// The vectors are compared cast to the dimensions of the model, the
+	// expression the HNSW index is built on.
+	searchVectorQuery = `SELECT id, 1 - (embedding::vector(%[1]d) <=> $1::vector(%[1]d)) FROM vecposts
+		WHERE channelid = ANY($2)
+		ORDER BY embedding::vector(%[1]d) <=> $1::vector(%[1]d)
+		LIMIT $3`
+)
+
+// pgvectorIndex stores the vectors in the vecposts table of the Mattermost
+// Postgres database, searched through an HNSW index of the pgvector
+// extension. As the dimensions of the vectors depend on the embedding model,
+// the HNSW index is only created when the index is reset for the model.
+type pgvectorIndex struct {
+	db          *sql.DB
+	sqlSettings model.SqlSettings
+	model       string
+	dimensions  int
+}
+
+func newPgvectorIndex(sqlSettings model.SqlSettings, embeddingModel string, dimensions int) *pgvectorIndex {
+	return &pgvectorIndex{
+		sqlSettings: sqlSettings,
+		model:       embeddingModel,
+		dimensions:  dimensions,
+	}
+}
+
+// formatVector formats the vector as a pgvector literal.
+func formatVector(vector []float32) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, value := range vector {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(value), 'f', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+func (pi *pgvectorIndex) open() error {
+	db, err := sql.Open(model.DatabaseDriverPostgres, *pi.sqlSettings.DataSource)
+	if err != nil {
+		return err
+	}
+	db.SetMaxIdleConns(min(*pi.sqlSettings.MaxIdleConns, dbMaxIdleConns))
+	db.SetMaxOpenConns(min(*pi.sqlSettings.MaxOpenConns, dbMaxOpenConns))
+	db.SetConnMaxLifetime(time.Duration(*pi.sqlSettings.ConnMaxLifetimeMilliseconds) * time.Millisecond)
+	db.SetConnMaxIdleTime(time.Duration(*pi.sqlSettings.ConnMaxIdleTimeMilliseconds) * time.Millisecond)
+
+	var indexModel string
+	var indexDimensions int
+	err = db.QueryRow(`SELECT model, dimensions FROM vecindexinfo WHERE id = 1`).Scan(&indexModel, &indexDimensions)
+	if err == sql.ErrNoRows {
+		pi.db = db
+		return fmt.Errorf("%w: the index has not been built", errOutdatedIndex)
+	}
+	if err != nil {
+		db.Close()
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "42P01" {
+			return fmt.Errorf("the vector tables are missing, the vector extension must be installed before migrating the database: %w", err)
+		}
+		return fmt.Errorf("failed to read the vector index information: %w", err)
+	}
+
+	pi.db = db
+	if indexModel != pi.model || indexDimensions != pi.dimensions {
+		return fmt.Errorf("%w: the index was built with the model %q of %d dimensions", errOutdatedIndex, indexModel, indexDimensions)
+	}
+	return nil
+}
+
+// reset empties the index and rebuilds the HNSW index for the dimensions of
+// the embedding model.
+func (pi *pgvectorIndex) reset() error {
+	tx, err := pi.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		`TRUNCATE TABLE vecposts`,
+		`DROP INDEX IF EXISTS idx_vecposts_embedding`,
+		fmt.Sprintf(`CREATE INDEX idx_vecposts_embedding ON vecposts USING hnsw ((embedding::vector(%d)) vector_cosine_ops)`, pi.dimensions),
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("failed to reset the vector index: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(`INSERT INTO vecindexinfo (id, model, dimensions) VALUES (1, $1, $2)
+		ON CONFLICT (id) DO UPDATE SET model = EXCLUDED.model, dimensions = EXCLUDED.dimensions`, pi.model, pi.dimensions)
+	if err != nil {
+		return fmt.Errorf("failed to save the vector index information: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (pi *pgvectorIndex) close() error {
+	return pi.db.Close()
+}
+
+func (pi *pgvectorIndex) upsert(docs []*vectorDocument) error {
+	tx, err := pi.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, doc := range docs {
+		if _, err := tx.Exec(upsertVectorQuery, doc.Id, doc.ChannelId, doc.UserId, doc.CreateAt, formatVector(doc.Vector)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (pi *pgvectorIndex) delete(postIds []string) error {
+	_, err := pi.db.Exec(`DELETE FROM vecposts WHERE id = ANY($1)`, pq.Array(postIds))
+	return err
+}
+
+func (pi *pgvectorIndex) deleteWhere(column, value string) (int64, error) {
+	result, err := pi.db.Exec("DELETE FROM vecposts WHERE "+column+" = $1", value)
+	if err != nil {
+		return -1, err
+	}
+	return result.RowsAffected()
+}
+
+func (pi *pgvectorIndex) deleteChannelPosts(channelId string) (int64, error) {
+	return pi.deleteWhere("channelid", channelId)
+}
+
+func (pi *pgvectorIndex) deleteUserPosts(userId string) (int64, error) {
+	return pi.deleteWhere("userid", userId)
+}
+
+func (pi *pgvectorIndex) search(vector []float32, channelIds []string, limit int) ([]*model.PostSearchHit, error) {
+	rows, err := pi.db.Query(fmt.Sprintf(searchVectorQuery, pi.dimensions), formatVector(vector), pq.Array(channelIds), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hits := []*model.PostSearchHit{}
+	for rows.Next() {
+		hit := &model.PostSearchHit{}
+		if err := rows.Scan(&hit.Id, &hit.Score); err != nil {
+			return nil, err
+		}
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
+
+func (pi *pgvectorIndex) purge() error {
+	_, err := pi.db.Exec(`TRUNCATE TABLE vecposts`)
+	return err
+}
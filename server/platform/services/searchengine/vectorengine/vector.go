@@ -0,0 +1,419 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package vectorengine
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+const (
+	EngineName = "vector"
+
+	// maxEmbeddedRunes caps the text of the posts sent to the embedding
+	// endpoint. The models only embed the beginning of the longer texts.
+	maxEmbeddedRunes = 8000
+)
+
+// errOutdatedIndex is returned when opening an index built with another
+// embedding model or number of dimensions, or not built yet.
+var errOutdatedIndex = errors.New("the vector index must be rebuilt for the embedding model")
+
+// vectorDocument is a post indexed by its embedding vector.
+type vectorDocument struct {
+	Id        string
+	ChannelId string
+	UserId    string
+	CreateAt  int64
+	Vector    []float32
+}
+
+// vectorIndex stores the embedding vectors of the posts.
+type vectorIndex interface {
+	// open loads the index. It returns errOutdatedIndex, leaving the index
+	// open, when the vectors of the index cannot be compared with the ones
+	// of the embedding model: the index is then unused until it is reset.
+	open() error
+	// reset empties the index and prepares it for the embedding model.
+	reset() error
+	close() error
+	upsert(docs []*vectorDocument) error
+	delete(postIds []string) error
+	deleteChannelPosts(channelId string) (int64, error)
+	deleteUserPosts(userId string) (int64, error)
+	// search returns the posts of the channels closest to the vector, the
+	// closest first, scored by their cosine similarity with the vector.
+	search(vector []float32, channelIds []string, limit int) ([]*model.PostSearchHit, error)
+	purge() error
+}
+
+// VectorEngine indexes the posts by the embedding vectors of their message,
+// computed by an HTTP embedding endpoint, to search the posts by meaning.
+type VectorEngine struct {
+	Mutex     sync.RWMutex
+	ready     int32
+	cfg       *model.Config
+	index     vectorIndex
+	embedder  *embeddingClient
+	indexSync bool
+	// outdated is set when the index must be rebuilt by the indexing job
+	// before being used.
+	outdated bool
+}
+
+func NewVectorEngine(cfg *model.Config) *VectorEngine {
+	return &VectorEngine{
+		cfg: cfg,
+	}
+}
+
+func (ve *VectorEngine) openIndex() *model.AppError {
+	if atomic.LoadInt32(&ve.ready) != 0 {
+		return model.NewAppError("Vectorengine.Start", "vectorengine.already_started.error", nil, "", http.StatusInternalServerError)
+	}
+
+	settings := ve.cfg.VectorSearchSettings
+	var index vectorIndex
+	switch *settings.Backend {
+	case model.VectorSearchBackendHNSW:
+		if *ve.cfg.ClusterSettings.Enable {
+			return model.NewAppError("Vectorengine.Start", "vectorengine.hnsw_cluster.error", nil, "", http.StatusInternalServerError)
+		}
+		index = newHNSWIndex(*settings.IndexDir, *settings.EmbeddingModel, *settings.EmbeddingDimensions)
+	default:
+		if *ve.cfg.SqlSettings.DriverName != model.DatabaseDriverPostgres {
+			return model.NewAppError("Vectorengine.Start", "vectorengine.unsupported_driver.error", nil, "", http.StatusInternalServerError)
+		}
+		index = newPgvectorIndex(ve.cfg.SqlSettings, *settings.EmbeddingModel, *settings.EmbeddingDimensions)
+	}
+
+	err := index.open()
+	ve.outdated = errors.Is(err, errOutdatedIndex)
+	if ve.outdated {
+		mlog.Warn("The vector index is not used until it is rebuilt by running the vector search indexing job", mlog.Err(err),
+			mlog.String("model", *settings.EmbeddingModel), mlog.Int("dimensions", *settings.EmbeddingDimensions))
+	} else if err != nil {
+		return model.NewAppError("Vectorengine.Start", "vectorengine.open_index.error", map[string]any{"Backend": *settings.Backend}, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	ve.index = index
+	ve.embedder = newEmbeddingClient(settings)
+	atomic.StoreInt32(&ve.ready, 1)
+	return nil
+}
+
+func (ve *VectorEngine) Start() *model.AppError {
+	if !*ve.cfg.VectorSearchSettings.EnableIndexing {
+		return nil
+	}
+
+	ve.Mutex.Lock()
+	defer ve.Mutex.Unlock()
+
+	mlog.Info("Starting vector search engine", mlog.String("backend", *ve.cfg.VectorSearchSettings.Backend))
+
+	return ve.openIndex()
+}
+
+func (ve *VectorEngine) closeIndex() *model.AppError {
+	if ve.IsActive() {
+		if err := ve.index.close(); err != nil {
+			return model.NewAppError("Vectorengine.Stop", "vectorengine.close_index.error", nil, "", http.StatusInternalServerError).Wrap(err)
+		}
+		ve.index = nil
+	}
+
+	atomic.StoreInt32(&ve.ready, 0)
+	return nil
+}
+
+func (ve *VectorEngine) Stop() *model.AppError {
+	ve.Mutex.Lock()
+	defer ve.Mutex.Unlock()
+
+	mlog.Info("Stopping vector search engine")
+
+	return ve.closeIndex()
+}
+
+func (ve *VectorEngine) GetName() string {
+	return EngineName
+}
+
+func (ve *VectorEngine) IsActive() bool {
+	return atomic.LoadInt32(&ve.ready) == 1
+}
+
+func (ve *VectorEngine) IsIndexingEnabled() bool {
+	return *ve.cfg.VectorSearchSettings.EnableIndexing
+}
+
+func (ve *VectorEngine) IsSearchEnabled() bool {
+	return *ve.cfg.VectorSearchSettings.EnableSearching
+}
+
+func (ve *VectorEngine) IsIndexingSync() bool {
+	return ve.indexSync
+}
+
+// IsIndexOutdated returns whether the index must be rebuilt for the embedding
+// model. An outdated index is neither updated nor searched.
+func (ve *VectorEngine) IsIndexOutdated() bool {
+	ve.Mutex.RLock()
+	defer ve.Mutex.RUnlock()
+
+	return ve.outdated
+}
+
+// ResetIndex empties the index and prepares it for the embedding model, for
+// the indexing job to rebuild it.
+func (ve *VectorEngine) ResetIndex(rctx request.CTX) *model.AppError {
+	ve.Mutex.Lock()
+	defer ve.Mutex.Unlock()
+
+	if !ve.IsActive() {
+		return nil
+	}
+
+	rctx.Logger().Info("Resetting the vector index", mlog.String("model", *ve.cfg.VectorSearchSettings.EmbeddingModel))
+
+	if err := ve.index.reset(); err != nil {
+		return model.NewAppError("Vectorengine.ResetIndex", "vectorengine.reset_index.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	ve.outdated = false
+	return nil
+}
+
+// requiresReopen returns whether the index must be reopened to apply the
+// settings, as they change where and how the vectors are stored.
+func requiresReopen(oldCfg, newCfg *model.Config) bool {
+	oldSettings, newSettings := oldCfg.VectorSearchSettings, newCfg.VectorSearchSettings
+	return *oldSettings.EnableIndexing != *newSettings.EnableIndexing ||
+		*oldSettings.Backend != *newSettings.Backend ||
+		*oldSettings.IndexDir != *newSettings.IndexDir ||
+		*oldSettings.EmbeddingModel != *newSettings.EmbeddingModel ||
+		*oldSettings.EmbeddingDimensions != *newSettings.EmbeddingDimensions ||
+		*oldCfg.SqlSettings.DataSource != *newCfg.SqlSettings.DataSource
+}
+
+func (ve *VectorEngine) UpdateConfig(cfg *model.Config) {
+	ve.Mutex.Lock()
+	defer ve.Mutex.Unlock()
+
+	if reflect.DeepEqual(cfg.VectorSearchSettings, ve.cfg.VectorSearchSettings) && *cfg.SqlSettings.DataSource == *ve.cfg.SqlSettings.DataSource {
+		return
+	}
+
+	mlog.Info("UpdateConf vector search engine")
+
+	if requiresReopen(ve.cfg, cfg) {
+		if err := ve.closeIndex(); err != nil {
+			mlog.Error("Error closing the vector search index to update the config", mlog.Err(err))
+			return
+		}
+		ve.cfg = cfg
+		if *cfg.VectorSearchSettings.EnableIndexing {
+			if err := ve.openIndex(); err != nil {
+				mlog.Error("Error opening the vector search index after updating the config", mlog.Err(err))
+			}
+		}
+		return
+	}
+
+	ve.cfg = cfg
+	if ve.IsActive() {
+		ve.embedder = newEmbeddingClient(cfg.VectorSearchSettings)
+	}
+}
+
+// embeddedText returns the text of the post to embed, and false if the post
+// is not indexed.
+func embeddedText(post *model.Post) (string, bool) {
+	if post.DeleteAt != 0 || post.IsSystemMessage() {
+		return "", false
+	}
+
+	text := strings.TrimSpace(post.Message)
+	if text == "" {
+		return "", false
+	}
+
+	if runes := []rune(text); len(runes) > maxEmbeddedRunes {
+		text = string(runes[:maxEmbeddedRunes])
+	}
+	return text, true
+}
+
+// indexPosts embeds and stores the indexed posts, and removes the others
+// from the index, such as the deleted posts or the posts whose message was
+// cleared.
+func (ve *VectorEngine) indexPosts(posts []*model.Post) error {
+	var texts []string
+	var docs []*vectorDocument
+	var removedIds []string
+	for _, post := range posts {
+		text, ok := embeddedText(post)
+		if !ok {
+			removedIds = append(removedIds, post.Id)
+			continue
+		}
+		texts = append(texts, text)
+		docs = append(docs, &vectorDocument{
+			Id:        post.Id,
+			ChannelId: post.ChannelId,
+			UserId:    post.UserId,
+			CreateAt:  post.CreateAt,
+		})
+	}
+
+	if len(docs) > 0 {
+		vectors, err := ve.embedder.embed(texts)
+		if err != nil {
+			return err
+		}
+		for i, doc := range docs {
+			doc.Vector = vectors[i]
+		}
+		if err := ve.index.upsert(docs); err != nil {
+			return err
+		}
+	}
+
+	if len(removedIds) > 0 {
+		return ve.index.delete(removedIds)
+	}
+	return nil
+}
+
+func (ve *VectorEngine) IndexPost(post *model.Post, teamId string) *model.AppError {
+	ve.Mutex.RLock()
+	defer ve.Mutex.RUnlock()
+
+	if !ve.IsActive() || ve.outdated {
+		return nil
+	}
+
+	if err := ve.indexPosts([]*model.Post{post}); err != nil {
+		return model.NewAppError("Vectorengine.IndexPost", "vectorengine.index_post.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+// BulkIndexPosts indexes a batch of posts with a single request to the
+// embedding endpoint.
+func (ve *VectorEngine) BulkIndexPosts(posts []*model.PostForIndexing) error {
+	ve.Mutex.RLock()
+	defer ve.Mutex.RUnlock()
+
+	if ve.outdated {
+		return errOutdatedIndex
+	}
+
+	batch := make([]*model.Post, 0, len(posts))
+	for _, post := range posts {
+		batch = append(batch, &post.Post)
+	}
+	return ve.indexPosts(batch)
+}
+
+func (ve *VectorEngine) SearchSimilarPosts(text string, channelIds []string, limit int) ([]*model.PostSearchHit, *model.AppError) {
+	ve.Mutex.RLock()
+	defer ve.Mutex.RUnlock()
+
+	if !ve.IsActive() || len(channelIds) == 0 || limit <= 0 {
+		return []*model.PostSearchHit{}, nil
+	}
+	if ve.outdated {
+		return nil, model.NewAppError("Vectorengine.SearchSimilarPosts", "vectorengine.outdated_index.error", nil, "", http.StatusServiceUnavailable)
+	}
+
+	if runes := []rune(text); len(runes) > maxEmbeddedRunes {
+		text = string(runes[:maxEmbeddedRunes])
+	}
+
+	vectors, err := ve.embedder.embed([]string{text})
+	if err != nil {
+		return nil, model.NewAppError("Vectorengine.SearchSimilarPosts", "vectorengine.embed.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	hits, err := ve.index.search(vectors[0], channelIds, limit)
+	if err != nil {
+		return nil, model.NewAppError("Vectorengine.SearchSimilarPosts", "vectorengine.search_posts.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return hits, nil
+}
+
+func (ve *VectorEngine) DeletePost(post *model.Post) *model.AppError {
+	ve.Mutex.RLock()
+	defer ve.Mutex.RUnlock()
+
+	if !ve.IsActive() || ve.outdated {
+		return nil
+	}
+
+	if err := ve.index.delete([]string{post.Id}); err != nil {
+		return model.NewAppError("Vectorengine.DeletePost", "vectorengine.delete_post.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
+
+func (ve *VectorEngine) DeleteChannelPosts(rctx request.CTX, channelID string) *model.AppError {
+	ve.Mutex.RLock()
+	defer ve.Mutex.RUnlock()
+
+	if !ve.IsActive() || ve.outdated {
+		return nil
+	}
+
+	deleted, err := ve.index.deleteChannelPosts(channelID)
+	if err != nil {
+		return model.NewAppError("Vectorengine.DeleteChannelPosts", "vectorengine.delete_channel_posts.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	rctx.Logger().Info("Posts for channel deleted from the vector index", mlog.String("channel_id", channelID), mlog.Int("deleted", deleted))
+
+	return nil
+}
+
+func (ve *VectorEngine) DeleteUserPosts(rctx request.CTX, userID string) *model.AppError {
+	ve.Mutex.RLock()
+	defer ve.Mutex.RUnlock()
+
+	if !ve.IsActive() || ve.outdated {
+		return nil
+	}
+
+	deleted, err := ve.index.deleteUserPosts(userID)
+	if err != nil {
+		return model.NewAppError("Vectorengine.DeleteUserPosts", "vectorengine.delete_user_posts.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	rctx.Logger().Info("Posts for user deleted from the vector index", mlog.String("user_id", userID), mlog.Int("deleted", deleted))
+
+	return nil
+}
+
+func (ve *VectorEngine) PurgeIndexes(rctx request.CTX) *model.AppError {
+	if !ve.IsActive() {
+		return nil
+	}
+
+	ve.Mutex.Lock()
+	defer ve.Mutex.Unlock()
+
+	rctx.Logger().Info("PurgeIndexes vector search engine")
+
+	if err := ve.index.purge(); err != nil {
+		return model.NewAppError("Vectorengine.PurgeIndexes", "vectorengine.purge_index.error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package vectorengine
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/request"
+)
+
+// testConcepts maps the words known by the test embedding server to the
+// dimension of their meaning.
+var testConcepts = map[string]int{
+	"car": 0, "automobile": 0, "vehicle": 0, "drive": 0,
+	"cat": 1, "kitten": 1, "pet": 1,
+	"deploy": 2, "release": 2, "rollout": 2,
+	"lunch": 3, "food": 3, "pizza": 3,
+}
+
+// newTestEmbeddingServer returns an embedding server embedding the texts by
+// the concepts of their words, so that synonyms have close vectors.
+func newTestEmbeddingServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request embeddingRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+		var response embeddingResponse
+		response.Data = make([]struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		}, len(request.Input))
+		for i, text := range request.Input {
+			embedding := make([]float32, 5)
+			// The last dimension avoids null vectors for unknown words.
+			embedding[4] = 0.1
+			for _, word := range strings.Fields(strings.ToLower(text)) {
+				if concept, ok := testConcepts[strings.Trim(word, ".,!?")]; ok {
+					embedding[concept]++
+				}
+			}
+			response.Data[i].Index = i
+			response.Data[i].Embedding = embedding
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+}
+
+func newTestVectorEngine(t *testing.T, embeddingURL string) *VectorEngine {
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+	cfg.VectorSearchSettings.EnableIndexing = model.NewPointer(true)
+	cfg.VectorSearchSettings.EnableSearching = model.NewPointer(true)
+	cfg.VectorSearchSettings.EmbeddingURL = model.NewPointer(embeddingURL)
+	cfg.VectorSearchSettings.EmbeddingModel = model.NewPointer("test-model")
+	cfg.VectorSearchSettings.EmbeddingDimensions = model.NewPointer(5)
+	cfg.VectorSearchSettings.Backend = model.NewPointer(model.VectorSearchBackendHNSW)
+	cfg.VectorSearchSettings.IndexDir = model.NewPointer(t.TempDir())
+
+	engine := NewVectorEngine(cfg)
+	require.Nil(t, engine.Start())
+	return engine
+}
+
+func hitIds(hits []*model.PostSearchHit) []string {
+	ids := []string{}
+	for _, hit := range hits {
+		ids = append(ids, hit.Id)
+	}
+	return ids
+}
+
+func TestVectorEngine(t *testing.T) {
+	server := newTestEmbeddingServer(t)
+	defer server.Close()
+
+	rctx := request.TestContext(t)
+	engine := newTestVectorEngine(t, server.URL)
+	defer engine.Stop()
+	require.True(t, engine.IsActive())
+
+	channelId := model.NewId()
+	otherChannelId := model.NewId()
+	userId := model.NewId()
+	newPost := func(channelId, message string) *model.Post {
+		return &model.Post{Id: model.NewId(), ChannelId: channelId, UserId: userId, Message: message, CreateAt: model.GetMillis()}
+	}
+
+	carPost := newPost(channelId, "I bought a new car yesterday")
+	catPost := newPost(channelId, "Look at my kitten")
+	deployPost := newPost(channelId, "The release is scheduled for Friday")
+	otherPost := newPost(otherChannelId, "Who wants to drive my vehicle?")
+	systemPost := newPost(channelId, "automobile joined the channel")
+	systemPost.Type = model.PostTypeJoinChannel
+
+	for _, post := range []*model.Post{carPost, catPost, deployPost, otherPost, systemPost} {
+		require.Nil(t, engine.IndexPost(post, model.NewId()))
+	}
+
+	t.Run("find the posts by meaning", func(t *testing.T) {
+		hits, appErr := engine.SearchSimilarPosts("any automobile for sale?", []string{channelId}, 1)
+		require.Nil(t, appErr)
+		require.Len(t, hits, 1)
+		assert.Equal(t, carPost.Id, hits[0].Id)
+		assert.Greater(t, hits[0].Score, 0.9)
+
+		hits, appErr = engine.SearchSimilarPosts("when is the rollout?", []string{channelId}, 3)
+		require.Nil(t, appErr)
+		require.Len(t, hits, 3)
+		assert.Equal(t, deployPost.Id, hits[0].Id)
+		assert.Greater(t, hits[0].Score, hits[1].Score)
+	})
+
+	t.Run("only search the given channels", func(t *testing.T) {
+		hits, appErr := engine.SearchSimilarPosts("automobile", []string{channelId, otherChannelId}, 2)
+		require.Nil(t, appErr)
+		assert.ElementsMatch(t, []string{carPost.Id, otherPost.Id}, hitIds(hits))
+
+		hits, appErr = engine.SearchSimilarPosts("automobile", []string{}, 2)
+		require.Nil(t, appErr)
+		assert.Empty(t, hits)
+	})
+
+	t.Run("system messages are not indexed", func(t *testing.T) {
+		hits, appErr := engine.SearchSimilarPosts("automobile", []string{channelId}, 10)
+		require.Nil(t, appErr)
+		assert.NotContains(t, hitIds(hits), systemPost.Id)
+	})
+
+	t.Run("edited posts are reindexed", func(t *testing.T) {
+		catPost.Message = "Pizza for lunch"
+		require.Nil(t, engine.IndexPost(catPost, model.NewId()))
+
+		hits, appErr := engine.SearchSimilarPosts("food", []string{channelId}, 1)
+		require.Nil(t, appErr)
+		require.Len(t, hits, 1)
+		assert.Equal(t, catPost.Id, hits[0].Id)
+	})
+
+	t.Run("delete posts", func(t *testing.T) {
+		require.Nil(t, engine.DeletePost(carPost))
+		hits, appErr := engine.SearchSimilarPosts("automobile", []string{channelId}, 10)
+		require.Nil(t, appErr)
+		assert.NotContains(t, hitIds(hits), carPost.Id)
+
+		require.Nil(t, engine.DeleteChannelPosts(rctx, otherChannelId))
+		hits, appErr = engine.SearchSimilarPosts("automobile", []string{otherChannelId}, 10)
+		require.Nil(t, appErr)
+		assert.Empty(t, hits)
+
+		require.Nil(t, engine.DeleteUserPosts(rctx, userId))
+		hits, appErr = engine.SearchSimilarPosts("automobile", []string{channelId}, 10)
+		require.Nil(t, appErr)
+		assert.Empty(t, hits)
+	})
+
+	t.Run("bulk index posts", func(t *testing.T) {
+		deletedPost := newPost(channelId, "My pet cat")
+		deletedPost.DeleteAt = model.GetMillis()
+		forIndexing := func(post *model.Post) *model.PostForIndexing {
+			p := &model.PostForIndexing{}
+			post.ShallowCopy(&p.Post)
+			return p
+		}
+		posts := []*model.PostForIndexing{
+			forIndexing(newPost(channelId, "Drive safely")),
+			forIndexing(newPost(channelId, "Release notes")),
+			forIndexing(deletedPost),
+		}
+		require.NoError(t, engine.BulkIndexPosts(posts))
+
+		hits, appErr := engine.SearchSimilarPosts("kitten", []string{channelId}, 10)
+		require.Nil(t, appErr)
+		assert.ElementsMatch(t, []string{posts[0].Id, posts[1].Id}, hitIds(hits))
+
+		require.Nil(t, engine.PurgeIndexes(rctx))
+		hits, appErr = engine.SearchSimilarPosts("kitten", []string{channelId}, 10)
+		require.Nil(t, appErr)
+		assert.Empty(t, hits)
+	})
+
+	t.Run("fail when the embedding endpoint fails", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer failing.Close()
+
+		cfg := engine.cfg.Clone()
+		cfg.VectorSearchSettings.EmbeddingURL = model.NewPointer(failing.URL)
+		engine.UpdateConfig(cfg)
+		defer func() {
+			cfg := engine.cfg.Clone()
+			cfg.VectorSearchSettings.EmbeddingURL = model.NewPointer(server.URL)
+			engine.UpdateConfig(cfg)
+		}()
+
+		_, appErr := engine.SearchSimilarPosts("automobile", []string{channelId}, 10)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "vectorengine.embed.error", appErr.Id)
+
+		appErr = engine.IndexPost(newPost(channelId, "A car"), model.NewId())
+		require.NotNil(t, appErr)
+		assert.Equal(t, "vectorengine.index_post.error", appErr.Id)
+	})
+
+	t.Run("disable the indexing", func(t *testing.T) {
+		cfg := engine.cfg.Clone()
+		cfg.VectorSearchSettings.EnableIndexing = model.NewPointer(false)
+		cfg.VectorSearchSettings.EnableSearching = model.NewPointer(false)
+		engine.UpdateConfig(cfg)
+		assert.False(t, engine.IsActive())
+
+		cfg = engine.cfg.Clone()
+		cfg.VectorSearchSettings.EnableIndexing = model.NewPointer(true)
+		engine.UpdateConfig(cfg)
+		assert.True(t, engine.IsActive())
+	})
+}
+
+func TestVectorEngineOutdatedIndex(t *testing.T) {
+	server := newTestEmbeddingServer(t)
+	defer server.Close()
+
+	rctx := request.TestContext(t)
+	engine := newTestVectorEngine(t, server.URL)
+	defer engine.Stop()
+
+	channelId := model.NewId()
+	post := &model.Post{Id: model.NewId(), ChannelId: channelId, UserId: model.NewId(), Message: "I bought a new car", CreateAt: model.GetMillis()}
+	require.Nil(t, engine.IndexPost(post, model.NewId()))
+	assert.False(t, engine.IsIndexOutdated())
+
+	cfg := engine.cfg.Clone()
+	cfg.VectorSearchSettings.EmbeddingModel = model.NewPointer("other-model")
+	engine.UpdateConfig(cfg)
+	require.True(t, engine.IsActive())
+	require.True(t, engine.IsIndexOutdated())
+
+	// The outdated index is neither updated nor searched.
+	require.Nil(t, engine.IndexPost(post, model.NewId()))
+	_, appErr := engine.SearchSimilarPosts("automobile", []string{channelId}, 10)
+	require.NotNil(t, appErr)
+	assert.Equal(t, "vectorengine.outdated_index.error", appErr.Id)
+
+	require.Nil(t, engine.ResetIndex(rctx))
+	assert.False(t, engine.IsIndexOutdated())
+	hits, appErr := engine.SearchSimilarPosts("automobile", []string{channelId}, 10)
+	require.Nil(t, appErr)
+	assert.Empty(t, hits)
+}
+
+func TestVectorEngineRejectsHNSWInCluster(t *testing.T) {
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+	cfg.ClusterSettings.Enable = model.NewPointer(true)
+	cfg.VectorSearchSettings.EnableIndexing = model.NewPointer(true)
+	cfg.VectorSearchSettings.Backend = model.NewPointer(model.VectorSearchBackendHNSW)
+	cfg.VectorSearchSettings.IndexDir = model.NewPointer(t.TempDir())
+
+	engine := NewVectorEngine(cfg)
+	appErr := engine.Start()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "vectorengine.hnsw_cluster.error", appErr.Id)
+	assert.False(t, engine.IsActive())
+}
+
+func TestVectorEngineRequiresPostgresForPgvector(t *testing.T) {
+	cfg := &model.Config{}
+	cfg.SetDefaults()
+	cfg.SqlSettings.DriverName = model.NewPointer(model.DatabaseDriverMysql)
+	cfg.VectorSearchSettings.EnableIndexing = model.NewPointer(true)
+	cfg.VectorSearchSettings.Backend = model.NewPointer(model.VectorSearchBackendPgvector)
+
+	engine := NewVectorEngine(cfg)
+	appErr := engine.Start()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "vectorengine.unsupported_driver.error", appErr.Id)
+	assert.False(t, engine.IsActive())
+}
+
+func TestFormatVector(t *testing.T) {
+	assert.Equal(t, "[]", formatVector([]float32{}))
+	assert.Equal(t, "[0.5,-1,0.25]", formatVector([]float32{0.5, -1, 0.25}))
+}
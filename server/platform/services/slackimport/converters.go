@@ -8,9 +8,21 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
+var invalidUserGroupNameCharacters = regexp.MustCompile(`[^a-z0-9.\-_]+`)
+
+// slackSkinTones are the suffixes of the names of the emojis with a skin tone, by Slack skin tone modifier.
+var slackSkinTones = map[string]string{
+	"skin-tone-2": "_light_skin_tone",
+	"skin-tone-3": "_medium_light_skin_tone",
+	"skin-tone-4": "_medium_skin_tone",
+	"skin-tone-5": "_medium_dark_skin_tone",
+	"skin-tone-6": "_dark_skin_tone",
+}
+
 func slackConvertTimeStamp(ts string) int64 {
 	timeString := strings.SplitN(ts, ".", 2)[0]
 
@@ -34,6 +46,37 @@ func slackConvertChannelName(channelName string, channelId string) string {
 	return strings.ToLower(channelId)
 }
 
+// slackConvertEmojiName converts the name of the emoji of a reaction, e.g. thumbsup::skin-tone-2, keeping the skin
+// tone if Mattermost has the emoji with this skin tone.
+func slackConvertEmojiName(name string) string {
+	emojiName, skinTone, _ := strings.Cut(name, "::")
+	if suffix, ok := slackSkinTones[skinTone]; ok && model.IsSystemEmojiName(emojiName+suffix) {
+		return emojiName + suffix
+	}
+	return emojiName
+}
+
+func slackConvertUserGroupName(handle string, groupId string) string {
+	newName := strings.Trim(invalidUserGroupNameCharacters.ReplaceAllString(strings.ToLower(handle), "-"), "-._")
+	if len(newName) > model.GroupNameMaxLength {
+		newName = newName[:model.GroupNameMaxLength]
+	}
+	if newName == "" {
+		return "slack-group-" + strings.ToLower(groupId)
+	}
+	return newName
+}
+
+// slackConvertExternalUsername returns the name of a user of another organization of a Slack Connect channel.
+func slackConvertExternalUsername(userId string, profile *slackUserProfile) string {
+	for _, name := range []string{profile.DisplayName, profile.RealName, profile.Name} {
+		if name != "" {
+			return name
+		}
+	}
+	return userId
+}
+
 func slackConvertUserMentions(users []slackUser, posts map[string][]slackPost) map[string][]slackPost {
 	var regexes = make(map[string]*regexp.Regexp, len(users))
 	for _, user := range users {
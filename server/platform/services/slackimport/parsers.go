@@ -42,9 +42,27 @@ func slackParsePosts(data io.Reader) ([]slackPost, error) {
 	decoder := json.NewDecoder(data)
 
 	var posts []slackPost
-	if err := decoder.Decode(&posts); err != nil {
+	err := decoder.Decode(&posts)
+	if err != nil {
 		mlog.Warn("Slack Import: Error occurred when parsing some Slack posts. Import may work anyway.", mlog.Err(err))
-		return posts, err
 	}
-	return posts, nil
+
+	// The text of the edits is converted along with the text of the other messages.
+	for i := range posts {
+		if posts[i].SubType == "message_changed" && posts[i].Message != nil {
+			posts[i].Text = posts[i].Message.Text
+		}
+	}
+	return posts, err
+}
+
+func slackParseUserGroups(data io.Reader) ([]slackUserGroup, error) {
+	decoder := json.NewDecoder(data)
+
+	var userGroups []slackUserGroup
+	if err := decoder.Decode(&userGroups); err != nil {
+		mlog.Warn("Slack Import: Error occurred when parsing some Slack user groups. Import may work anyway.", mlog.Err(err))
+		return userGroups, err
+	}
+	return userGroups, nil
 }
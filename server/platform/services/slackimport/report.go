@@ -0,0 +1,77 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package slackimport
+
+// The types of the items of the export in the import report.
+const (
+	ReportItemUser      = "user"
+	ReportItemUserGroup = "user_group"
+	ReportItemChannel   = "channel"
+	ReportItemPost      = "post"
+	ReportItemEdit      = "edit"
+	ReportItemFile      = "file"
+	ReportItemReaction  = "reaction"
+	ReportItemBookmark  = "bookmark"
+)
+
+// The reasons why the items of the export are skipped.
+const (
+	ReportReasonMissingUser     = "missing_user"
+	ReportReasonUnknownUser     = "unknown_user"
+	ReportReasonMissingBotUser  = "missing_bot_user"
+	ReportReasonMissingPost     = "missing_post"
+	ReportReasonMissingFile     = "missing_file"
+	ReportReasonUnknownEmoji    = "unknown_emoji"
+	ReportReasonUnsupportedType = "unsupported_type"
+	ReportReasonDeleted         = "deleted"
+	ReportReasonNameTaken       = "name_taken"
+	ReportReasonInvalid         = "invalid"
+	ReportReasonImportFailed    = "import_failed"
+)
+
+// ImportReport is the machine-readable summary of a Slack import: the counts of what has been imported, and the items
+// of the export that have been skipped, with the reason why.
+type ImportReport struct {
+	Users              int                 `json:"users"`
+	UserGroups         int                 `json:"user_groups"`
+	Channels           int                 `json:"channels"`
+	SharedChannels     int                 `json:"shared_channels"`
+	Posts              int                 `json:"posts"`
+	Replies            int                 `json:"replies"`
+	OrphanedReplies    int                 `json:"orphaned_replies"`
+	SharedChannelPosts int                 `json:"shared_channel_posts"`
+	PinnedPosts        int                 `json:"pinned_posts"`
+	EditedPosts        int                 `json:"edited_posts"`
+	Edits              int                 `json:"edits"`
+	Files              int                 `json:"files"`
+	Reactions          int                 `json:"reactions"`
+	Bookmarks          int                 `json:"bookmarks"`
+	Skipped            []*ImportReportItem `json:"skipped"`
+}
+
+// ImportReportItem is an item of the export skipped by the import. The id is the Slack id of the item, or the
+// timestamp for the messages.
+type ImportReportItem struct {
+	Type    string `json:"type"`
+	Id      string `json:"id,omitempty"`
+	Channel string `json:"channel,omitempty"`
+	Reason  string `json:"reason"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+func newImportReport() *ImportReport {
+	return &ImportReport{
+		Skipped: []*ImportReportItem{},
+	}
+}
+
+func (r *ImportReport) skip(itemType, id, channel, reason, detail string) {
+	r.Skipped = append(r.Skipped, &ImportReportItem{
+		Type:    itemType,
+		Id:      id,
+		Channel: channel,
+		Reason:  reason,
+		Detail:  detail,
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -27,19 +28,36 @@ import (
 )
 
 type slackChannel struct {
-	Id      string          `json:"id"`
-	Name    string          `json:"name"`
-	Creator string          `json:"creator"`
-	Members []string        `json:"members"`
-	Purpose slackChannelSub `json:"purpose"`
-	Topic   slackChannelSub `json:"topic"`
-	Type    model.ChannelType
+	Id          string          `json:"id"`
+	Name        string          `json:"name"`
+	Creator     string          `json:"creator"`
+	Members     []string        `json:"members"`
+	Purpose     slackChannelSub `json:"purpose"`
+	Topic       slackChannelSub `json:"topic"`
+	Pins        []slackPin      `json:"pins"`
+	Bookmarks   []slackBookmark `json:"bookmarks"`
+	IsShared    bool            `json:"is_shared"`
+	IsExtShared bool            `json:"is_ext_shared"`
+	Type        model.ChannelType
 }
 
 type slackChannelSub struct {
 	Value string `json:"value"`
 }
 
+// slackPin is a pinned message of a channel, its id is the timestamp of the message.
+type slackPin struct {
+	Id string `json:"id"`
+}
+
+type slackBookmark struct {
+	Id    string `json:"id"`
+	Title string `json:"title"`
+	Link  string `json:"link"`
+	Emoji string `json:"emoji"`
+	Type  string `json:"type"`
+}
+
 type slackProfile struct {
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
@@ -52,6 +70,15 @@ type slackUser struct {
 	Profile  slackProfile `json:"profile"`
 }
 
+type slackUserGroup struct {
+	Id          string   `json:"id"`
+	Name        string   `json:"name"`
+	Handle      string   `json:"handle"`
+	Description string   `json:"description"`
+	Users       []string `json:"users"`
+	DateDelete  int64    `json:"date_delete"`
+}
+
 type slackFile struct {
 	Id    string `json:"id"`
 	Title string `json:"title"`
@@ -71,6 +98,31 @@ type slackPost struct {
 	File        *slackFile               `json:"file"`
 	Files       []*slackFile             `json:"files"`
 	Attachments []*model.SlackAttachment `json:"attachments"`
+	Reactions   []slackReaction          `json:"reactions"`
+	PinnedTo    []string                 `json:"pinned_to"`
+	Edited      *slackEdited             `json:"edited"`
+	UserTeam    string                   `json:"user_team"`
+	UserProfile *slackUserProfile        `json:"user_profile"`
+	// Message is the edited message of a message_changed event.
+	Message *slackPost `json:"message"`
+}
+
+type slackReaction struct {
+	Name  string   `json:"name"`
+	Users []string `json:"users"`
+}
+
+type slackEdited struct {
+	User      string `json:"user"`
+	TimeStamp string `json:"ts"`
+}
+
+// slackUserProfile is the profile embedded in the messages, the only information about the users of the other
+// organizations of a Slack Connect channel.
+type slackUserProfile struct {
+	Name        string `json:"name"`
+	RealName    string `json:"real_name"`
+	DisplayName string `json:"display_name"`
 }
 
 var isValidChannelNameCharacters = regexp.MustCompile(`^[a-zA-Z0-9\-_]+$`).MatchString
@@ -96,6 +148,7 @@ type Actions struct {
 	InvalidateAllCaches    func()
 	MaxPostSize            func() int
 	PrepareImage           func(fileData []byte) (image.Image, string, func(), error)
+	CreateGroupWithUserIds func(*model.GroupWithUserIds) (*model.Group, *model.AppError)
 }
 
 // SlackImporter is a service that allows to import slack dumps into mattermost
@@ -103,6 +156,7 @@ type SlackImporter struct {
 	store   store.Store
 	actions Actions
 	config  *model.Config
+	report  *ImportReport
 }
 
 // New creates a new SlackImporter service instance. It receive a store, a set of actions and the current config.
@@ -112,9 +166,15 @@ func New(store store.Store, actions Actions, config *model.Config) *SlackImporte
 		store:   store,
 		actions: actions,
 		config:  config,
+		report:  newImportReport(),
 	}
 }
 
+// Report returns the report of the import, listing what has been imported and what has been skipped.
+func (si *SlackImporter) Report() *ImportReport {
+	return si.report
+}
+
 func (si *SlackImporter) SlackImport(rctx request.CTX, fileData multipart.File, fileSize int64, teamID string) (*model.AppError, *bytes.Buffer) {
 	// Create log file
 	log := bytes.NewBufferString(i18n.T("api.slackimport.slack_import.log"))
@@ -132,6 +192,7 @@ func (si *SlackImporter) SlackImport(rctx request.CTX, fileData multipart.File,
 	var directChannels []slackChannel
 
 	var users []slackUser
+	var userGroups []slackUserGroup
 	posts := make(map[string][]slackPost)
 	uploads := make(map[string]*zip.File)
 	for _, file := range zipreader.File {
@@ -177,6 +238,12 @@ func (si *SlackImporter) SlackImport(rctx request.CTX, fileData multipart.File,
 				log.WriteString(i18n.T("api.slackimport.slack_import.zip.file_too_large", map[string]any{"Filename": file.Name}))
 				continue
 			}
+		} else if file.Name == "usergroups.json" {
+			userGroups, err = slackParseUserGroups(reader)
+			if errors.Is(err, utils.ErrSizeLimitExceeded) {
+				log.WriteString(i18n.T("api.slackimport.slack_import.zip.file_too_large", map[string]any{"Filename": file.Name}))
+				continue
+			}
 		} else {
 			spl := strings.Split(file.Name, "/")
 			if len(spl) == 2 && strings.HasSuffix(spl[1], ".json") {
@@ -204,6 +271,10 @@ func (si *SlackImporter) SlackImport(rctx request.CTX, fileData multipart.File,
 	addedUsers := si.slackAddUsers(rctx, teamID, users, log)
 	botUser := si.slackAddBotUser(rctx, teamID, log)
 
+	if len(userGroups) > 0 {
+		si.slackAddUserGroups(rctx, userGroups, addedUsers, log)
+	}
+
 	si.slackAddChannels(rctx, teamID, channels, posts, addedUsers, uploads, botUser, log)
 
 	if botUser != nil {
@@ -264,6 +335,7 @@ func (si *SlackImporter) slackAddUsers(rctx request.CTX, teamId string, slackuse
 			} else {
 				importerLog.WriteString(i18n.T("api.slackimport.slack_add_users.merge_existing", map[string]any{"Email": existingUser.Email, "Username": existingUser.Username}))
 			}
+			si.report.Users++
 			continue
 		}
 
@@ -279,9 +351,11 @@ func (si *SlackImporter) slackAddUsers(rctx request.CTX, teamId string, slackuse
 		mUser := si.oldImportUser(rctx, team, &newUser)
 		if mUser == nil {
 			importerLog.WriteString(i18n.T("api.slackimport.slack_add_users.unable_import", map[string]any{"Username": sUser.Username}))
+			si.report.skip(ReportItemUser, sUser.Id, "", ReportReasonImportFailed, "")
 			continue
 		}
 		addedUsers[sUser.Id] = mUser
+		si.report.Users++
 		importerLog.WriteString(i18n.T("api.slackimport.slack_add_users.email_pwd", map[string]any{"Email": newUser.Email, "Password": password}))
 	}
 
@@ -317,24 +391,122 @@ func (si *SlackImporter) slackAddBotUser(rctx request.CTX, teamId string, log *b
 	return mUser
 }
 
-func (si *SlackImporter) slackAddPosts(rctx request.CTX, teamId string, channel *model.Channel, posts []slackPost, users map[string]*model.User, uploads map[string]*zip.File, botUser *model.User) {
+// slackAddUserGroups adds the user groups as custom groups, merging them with the existing custom groups of the
+// same name.
+func (si *SlackImporter) slackAddUserGroups(rctx request.CTX, slackGroups []slackUserGroup, users map[string]*model.User, importerLog *bytes.Buffer) {
+	// Write Header
+	importerLog.WriteString(i18n.T("api.slackimport.slack_add_user_groups.added"))
+	importerLog.WriteString("==================\r\n\r\n")
+
+	for _, sGroup := range slackGroups {
+		if sGroup.DateDelete > 0 {
+			si.report.skip(ReportItemUserGroup, sGroup.Id, "", ReportReasonDeleted, "")
+			continue
+		}
+
+		name := slackConvertUserGroupName(sGroup.Handle, sGroup.Id)
+		displayName := sGroup.Name
+		if displayName == "" {
+			displayName = sGroup.Handle
+		}
+		var userIds []string
+		for _, member := range sGroup.Users {
+			if user := users[member]; user != nil {
+				userIds = append(userIds, user.Id)
+			}
+		}
+
+		if group, err := si.store.Group().GetByName(name, model.GroupSearchOpts{}); err == nil {
+			if group.Source != model.GroupSourceCustom {
+				importerLog.WriteString(i18n.T("api.slackimport.slack_add_user_groups.import_failed", map[string]any{"DisplayName": displayName}))
+				si.report.skip(ReportItemUserGroup, sGroup.Id, "", ReportReasonNameTaken, name)
+				continue
+			}
+			// The group already exists. Merge with the existing one.
+			if len(userIds) > 0 {
+				if _, err := si.store.Group().UpsertMembers(group.Id, userIds); err != nil {
+					rctx.Logger().Warn("Slack Import: Unable to add the members of the user group.", mlog.String("group_name", name), mlog.Err(err))
+					importerLog.WriteString(i18n.T("api.slackimport.slack_add_user_groups.import_failed", map[string]any{"DisplayName": displayName}))
+					si.report.skip(ReportItemUserGroup, sGroup.Id, "", ReportReasonImportFailed, err.Error())
+					continue
+				}
+			}
+			importerLog.WriteString(i18n.T("api.slackimport.slack_add_user_groups.merge", map[string]any{"DisplayName": group.DisplayName}))
+			si.report.UserGroups++
+			continue
+		}
+
+		group := &model.GroupWithUserIds{
+			Group: model.Group{
+				Name:           model.NewPointer(name),
+				DisplayName:    truncateRunes(displayName, model.GroupDisplayNameMaxLength),
+				Description:    truncateRunes(sGroup.Description, model.GroupDescriptionMaxLength),
+				Source:         model.GroupSourceCustom,
+				AllowReference: true,
+			},
+			UserIds: userIds,
+		}
+		if appErr := group.IsValidForCreate(); appErr != nil {
+			importerLog.WriteString(i18n.T("api.slackimport.slack_add_user_groups.import_failed", map[string]any{"DisplayName": displayName}))
+			si.report.skip(ReportItemUserGroup, sGroup.Id, "", ReportReasonInvalid, appErr.Id)
+			continue
+		}
+		if _, appErr := si.actions.CreateGroupWithUserIds(group); appErr != nil {
+			rctx.Logger().Warn("Slack Import: Unable to create the user group.", mlog.String("group_name", name), mlog.Err(appErr))
+			importerLog.WriteString(i18n.T("api.slackimport.slack_add_user_groups.import_failed", map[string]any{"DisplayName": displayName}))
+			si.report.skip(ReportItemUserGroup, sGroup.Id, "", ReportReasonImportFailed, appErr.Id)
+			continue
+		}
+		importerLog.WriteString(group.DisplayName + "\r\n")
+		si.report.UserGroups++
+	}
+}
+
+func (si *SlackImporter) slackAddPosts(rctx request.CTX, teamId string, channel *model.Channel, sChannel slackChannel, posts []slackPost, users map[string]*model.User, uploads map[string]*zip.File, botUser *model.User) {
 	sort.Slice(posts, func(i, j int) bool {
 		return slackConvertTimeStamp(posts[i].TimeStamp) < slackConvertTimeStamp(posts[j].TimeStamp)
 	})
-	threads := make(map[string]string)
+	// The ids of the imported posts by timestamp, to find the roots of the threads and the posts of the edits.
+	postIds := make(map[string]string)
+	pinned := make(map[string]bool)
+	for _, pin := range sChannel.Pins {
+		pinned[pin.Id] = true
+	}
 	for _, sPost := range posts {
+		var newPost *model.Post
+		// The props of the posts imported as incoming webhook posts.
+		var webhookProps model.StringInterface
+		external := false
+
 		switch {
-		case sPost.Type == "message" && (sPost.SubType == "" || sPost.SubType == "file_share"):
-			if sPost.User == "" {
-				rctx.Logger().Debug("Slack Import: Unable to import the message as the user field is missing.")
-				continue
+		case sPost.Type == "message" && (sPost.SubType == "" || sPost.SubType == "file_share" || sPost.SubType == "thread_broadcast"):
+			if users[sPost.User] == nil && sPost.UserProfile != nil {
+				// The users of the other organizations of a Slack Connect channel are not in the export, their
+				// messages are posted by the bot user under their name.
+				if botUser == nil {
+					rctx.Logger().Warn("Slack Import: Unable to import the message of the external user as the bot user does not exist.", mlog.String("user", sPost.User))
+					si.report.skip(ReportItemPost, sPost.TimeStamp, sChannel.Name, ReportReasonMissingBotUser, sPost.User)
+					continue
+				}
+				newPost = &model.Post{
+					UserId:    botUser.Id,
+					ChannelId: channel.Id,
+					Message:   sPost.Text,
+					CreateAt:  slackConvertTimeStamp(sPost.TimeStamp),
+				}
+				webhookProps = model.StringInterface{"override_username": slackConvertExternalUsername(sPost.User, sPost.UserProfile)}
+				if len(sPost.Attachments) > 0 {
+					webhookProps["attachments"] = sPost.Attachments
+				}
+				external = true
+				break
 			}
-			if users[sPost.User] == nil {
-				rctx.Logger().Debug("Slack Import: Unable to add the message as the Slack user does not exist in Mattermost.", mlog.String("user", sPost.User))
+			user := si.slackPostUser(rctx, sChannel, sPost, sPost.User, users)
+			if user == nil {
 				continue
 			}
-			newPost := model.Post{
-				UserId:    users[sPost.User].Id,
+			newPost = &model.Post{
+				UserId:    user.Id,
 				ChannelId: channel.Id,
 				Message:   sPost.Text,
 				CreateAt:  slackConvertTimeStamp(sPost.TimeStamp),
@@ -352,71 +524,50 @@ func (si *SlackImporter) slackAddPosts(rctx request.CTX, teamId string, channel
 					}
 				}
 			}
-			// If post in thread
-			if sPost.ThreadTS != "" && sPost.ThreadTS != sPost.TimeStamp {
-				newPost.RootId = threads[sPost.ThreadTS]
-			}
-			postId := si.oldImportPost(rctx, &newPost)
-			// If post is thread starter
-			if sPost.ThreadTS == sPost.TimeStamp {
-				threads[sPost.ThreadTS] = postId
-			}
 		case sPost.Type == "message" && sPost.SubType == "file_comment":
 			if sPost.Comment == nil {
 				rctx.Logger().Debug("Slack Import: Unable to import the message as it has no comments.")
+				si.report.skip(ReportItemPost, sPost.TimeStamp, sChannel.Name, ReportReasonInvalid, "missing comment")
 				continue
 			}
-			if sPost.Comment.User == "" {
-				rctx.Logger().Debug("Slack Import: Unable to import the message as the user field is missing.")
+			user := si.slackPostUser(rctx, sChannel, sPost, sPost.Comment.User, users)
+			if user == nil {
 				continue
 			}
-			if users[sPost.Comment.User] == nil {
-				rctx.Logger().Debug("Slack Import: Unable to add the message as the Slack user does not exist in Mattermost.", mlog.String("user", sPost.User))
-				continue
-			}
-			newPost := model.Post{
-				UserId:    users[sPost.Comment.User].Id,
+			newPost = &model.Post{
+				UserId:    user.Id,
 				ChannelId: channel.Id,
 				Message:   sPost.Comment.Comment,
 				CreateAt:  slackConvertTimeStamp(sPost.TimeStamp),
 			}
-			si.oldImportPost(rctx, &newPost)
 		case sPost.Type == "message" && sPost.SubType == "bot_message":
 			if botUser == nil {
 				rctx.Logger().Warn("Slack Import: Unable to import the bot message as the bot user does not exist.")
+				si.report.skip(ReportItemPost, sPost.TimeStamp, sChannel.Name, ReportReasonMissingBotUser, "")
 				continue
 			}
 			if sPost.BotId == "" {
 				rctx.Logger().Warn("Slack Import: Unable to import bot message as the BotId field is missing.")
+				si.report.skip(ReportItemPost, sPost.TimeStamp, sChannel.Name, ReportReasonInvalid, "missing bot_id")
 				continue
 			}
 
-			props := make(model.StringInterface)
-			props["override_username"] = sPost.BotUsername
+			webhookProps = make(model.StringInterface)
+			webhookProps["override_username"] = sPost.BotUsername
 			if len(sPost.Attachments) > 0 {
-				props["attachments"] = sPost.Attachments
+				webhookProps["attachments"] = sPost.Attachments
 			}
 
-			post := &model.Post{
+			newPost = &model.Post{
 				UserId:    botUser.Id,
 				ChannelId: channel.Id,
 				CreateAt:  slackConvertTimeStamp(sPost.TimeStamp),
 				Message:   sPost.Text,
 				Type:      model.PostTypeSlackAttachment,
 			}
-
-			postId := si.oldImportIncomingWebhookPost(rctx, post, props)
-			// If post is thread starter
-			if sPost.ThreadTS == sPost.TimeStamp {
-				threads[sPost.ThreadTS] = postId
-			}
 		case sPost.Type == "message" && (sPost.SubType == "channel_join" || sPost.SubType == "channel_leave"):
-			if sPost.User == "" {
-				rctx.Logger().Debug("Slack Import: Unable to import the message as the user field is missing.")
-				continue
-			}
-			if users[sPost.User] == nil {
-				rctx.Logger().Debug("Slack Import: Unable to add the message as the Slack user does not exist in Mattermost.", mlog.String("user", sPost.User))
+			user := si.slackPostUser(rctx, sChannel, sPost, sPost.User, users)
+			if user == nil {
 				continue
 			}
 
@@ -427,111 +578,229 @@ func (si *SlackImporter) slackAddPosts(rctx request.CTX, teamId string, channel
 				postType = model.PostTypeLeaveChannel
 			}
 
-			newPost := model.Post{
-				UserId:    users[sPost.User].Id,
+			newPost = &model.Post{
+				UserId:    user.Id,
 				ChannelId: channel.Id,
 				Message:   sPost.Text,
 				CreateAt:  slackConvertTimeStamp(sPost.TimeStamp),
 				Type:      postType,
 				Props: model.StringInterface{
-					"username": users[sPost.User].Username,
+					"username": user.Username,
 				},
 			}
-			si.oldImportPost(rctx, &newPost)
 		case sPost.Type == "message" && sPost.SubType == "me_message":
-			if sPost.User == "" {
-				rctx.Logger().Debug("Slack Import: Unable to import the message as the user field is missing.")
-				continue
-			}
-			if users[sPost.User] == nil {
-				rctx.Logger().Debug("Slack Import: Unable to add the message as the Slack user does not exist in Mattermost.", mlog.String("user", sPost.User))
+			user := si.slackPostUser(rctx, sChannel, sPost, sPost.User, users)
+			if user == nil {
 				continue
 			}
-			newPost := model.Post{
-				UserId:    users[sPost.User].Id,
+			newPost = &model.Post{
+				UserId:    user.Id,
 				ChannelId: channel.Id,
 				Message:   "*" + sPost.Text + "*",
 				CreateAt:  slackConvertTimeStamp(sPost.TimeStamp),
 			}
-			postId := si.oldImportPost(rctx, &newPost)
-			// If post is thread starter
-			if sPost.ThreadTS == sPost.TimeStamp {
-				threads[sPost.ThreadTS] = postId
-			}
 		case sPost.Type == "message" && sPost.SubType == "channel_topic":
-			if sPost.User == "" {
-				rctx.Logger().Debug("Slack Import: Unable to import the message as the user field is missing.")
+			user := si.slackPostUser(rctx, sChannel, sPost, sPost.User, users)
+			if user == nil {
 				continue
 			}
-			if users[sPost.User] == nil {
-				rctx.Logger().Debug("Slack Import: Unable to add the message as the Slack user does not exist in Mattermost.", mlog.String("user", sPost.User))
-				continue
-			}
-			newPost := model.Post{
-				UserId:    users[sPost.User].Id,
+			newPost = &model.Post{
+				UserId:    user.Id,
 				ChannelId: channel.Id,
 				Message:   sPost.Text,
 				CreateAt:  slackConvertTimeStamp(sPost.TimeStamp),
 				Type:      model.PostTypeHeaderChange,
 			}
-			si.oldImportPost(rctx, &newPost)
 		case sPost.Type == "message" && sPost.SubType == "channel_purpose":
-			if sPost.User == "" {
-				rctx.Logger().Debug("Slack Import: Unable to import the message as the user field is missing.")
-				continue
-			}
-			if users[sPost.User] == nil {
-				rctx.Logger().Debug("Slack Import: Unable to add the message as the Slack user does not exist in Mattermost.", mlog.String("user", sPost.User))
+			user := si.slackPostUser(rctx, sChannel, sPost, sPost.User, users)
+			if user == nil {
 				continue
 			}
-			newPost := model.Post{
-				UserId:    users[sPost.User].Id,
+			newPost = &model.Post{
+				UserId:    user.Id,
 				ChannelId: channel.Id,
 				Message:   sPost.Text,
 				CreateAt:  slackConvertTimeStamp(sPost.TimeStamp),
 				Type:      model.PostTypePurposeChange,
 			}
-			si.oldImportPost(rctx, &newPost)
 		case sPost.Type == "message" && sPost.SubType == "channel_name":
-			if sPost.User == "" {
-				rctx.Logger().Debug("Slack Import: Unable to import the message as the user field is missing.")
+			user := si.slackPostUser(rctx, sChannel, sPost, sPost.User, users)
+			if user == nil {
 				continue
 			}
-			if users[sPost.User] == nil {
-				rctx.Logger().Debug("Slack Import: Unable to add the message as the Slack user does not exist in Mattermost.", mlog.String("user", sPost.User))
-				continue
-			}
-			newPost := model.Post{
-				UserId:    users[sPost.User].Id,
+			newPost = &model.Post{
+				UserId:    user.Id,
 				ChannelId: channel.Id,
 				Message:   sPost.Text,
 				CreateAt:  slackConvertTimeStamp(sPost.TimeStamp),
 				Type:      model.PostTypeDisplaynameChange,
 			}
-			si.oldImportPost(rctx, &newPost)
+		case sPost.Type == "message" && sPost.SubType == "message_changed":
+			si.slackEditPost(rctx, sChannel, sPost, postIds)
+			continue
 		default:
 			rctx.Logger().Warn(
 				"Slack Import: Unable to import the message as its type is not supported",
 				mlog.String("post_type", sPost.Type),
 				mlog.String("post_subtype", sPost.SubType),
 			)
+			si.report.skip(ReportItemPost, sPost.TimeStamp, sChannel.Name, ReportReasonUnsupportedType, strings.TrimSuffix(sPost.Type+"/"+sPost.SubType, "/"))
+			continue
+		}
+
+		// If post in thread
+		if sPost.ThreadTS != "" && sPost.ThreadTS != sPost.TimeStamp {
+			newPost.RootId = postIds[sPost.ThreadTS]
+			if newPost.RootId == "" {
+				rctx.Logger().Debug("Slack Import: Unable to find the root of the thread, the reply is imported as a root post.", mlog.String("thread_ts", sPost.ThreadTS))
+				si.report.OrphanedReplies++
+			}
+		}
+		newPost.IsPinned = pinned[sPost.TimeStamp] || slices.Contains(sPost.PinnedTo, sChannel.Id)
+		if sPost.Edited != nil {
+			newPost.EditAt = slackConvertTimeStamp(sPost.Edited.TimeStamp)
+		}
+		isReply := newPost.RootId != ""
+
+		var postId string
+		if webhookProps != nil {
+			postId = si.oldImportIncomingWebhookPost(rctx, newPost, webhookProps)
+		} else {
+			postId = si.oldImportPost(rctx, newPost)
+		}
+		if postId == "" {
+			si.report.skip(ReportItemPost, sPost.TimeStamp, sChannel.Name, ReportReasonImportFailed, "")
+			continue
+		}
+		postIds[sPost.TimeStamp] = postId
+
+		if isReply {
+			si.report.Replies++
+		} else {
+			si.report.Posts++
+		}
+		if external {
+			si.report.SharedChannelPosts++
+		}
+		if newPost.IsPinned {
+			si.report.PinnedPosts++
+		}
+		if newPost.EditAt > 0 {
+			si.report.EditedPosts++
+		}
+		si.slackAddReactions(rctx, channel, sChannel, sPost, postId, users)
+	}
+}
+
+// slackPostUser returns the Mattermost user of the author of a message, or nil if the message can't be imported.
+func (si *SlackImporter) slackPostUser(rctx request.CTX, sChannel slackChannel, sPost slackPost, userId string, users map[string]*model.User) *model.User {
+	if userId == "" {
+		rctx.Logger().Debug("Slack Import: Unable to import the message as the user field is missing.")
+		si.report.skip(ReportItemPost, sPost.TimeStamp, sChannel.Name, ReportReasonMissingUser, "")
+		return nil
+	}
+	user := users[userId]
+	if user == nil {
+		rctx.Logger().Debug("Slack Import: Unable to add the message as the Slack user does not exist in Mattermost.", mlog.String("user", userId))
+		si.report.skip(ReportItemPost, sPost.TimeStamp, sChannel.Name, ReportReasonUnknownUser, userId)
+	}
+	return user
+}
+
+// slackEditPost applies a message_changed event to the post imported from the edited message. The previous message is
+// kept in the edit history of the post.
+func (si *SlackImporter) slackEditPost(rctx request.CTX, sChannel slackChannel, sPost slackPost, postIds map[string]string) {
+	if sPost.Message == nil {
+		rctx.Logger().Debug("Slack Import: Unable to import the edit as it has no message.")
+		si.report.skip(ReportItemEdit, sPost.TimeStamp, sChannel.Name, ReportReasonInvalid, "missing message")
+		return
+	}
+	postId, ok := postIds[sPost.Message.TimeStamp]
+	if !ok {
+		rctx.Logger().Debug("Slack Import: Unable to import the edit as the edited message has not been imported.", mlog.String("ts", sPost.Message.TimeStamp))
+		si.report.skip(ReportItemEdit, sPost.TimeStamp, sChannel.Name, ReportReasonMissingPost, sPost.Message.TimeStamp)
+		return
+	}
+
+	oldPost, err := si.store.Post().GetSingle(rctx, postId, false)
+	if err != nil {
+		rctx.Logger().Warn("Slack Import: Unable to get the edited post.", mlog.String("post_id", postId), mlog.Err(err))
+		si.report.skip(ReportItemEdit, sPost.TimeStamp, sChannel.Name, ReportReasonImportFailed, err.Error())
+		return
+	}
+
+	newPost := oldPost.Clone()
+	newPost.Message = truncateRunes(sPost.Text, si.actions.MaxPostSize())
+	newPost.Hashtags, _ = model.ParseHashtags(newPost.Message)
+	newPost.EditAt = slackConvertTimeStamp(sPost.TimeStamp)
+	if sPost.Message.Edited != nil {
+		newPost.EditAt = slackConvertTimeStamp(sPost.Message.Edited.TimeStamp)
+	}
+	if _, err := si.store.Post().Update(rctx, newPost, oldPost); err != nil {
+		rctx.Logger().Warn("Slack Import: Unable to edit the post.", mlog.String("post_id", postId), mlog.Err(err))
+		si.report.skip(ReportItemEdit, sPost.TimeStamp, sChannel.Name, ReportReasonImportFailed, err.Error())
+		return
+	}
+	si.report.Edits++
+}
+
+func (si *SlackImporter) slackAddReactions(rctx request.CTX, channel *model.Channel, sChannel slackChannel, sPost slackPost, postId string, users map[string]*model.User) {
+	for _, sReaction := range sPost.Reactions {
+		emojiName := slackConvertEmojiName(sReaction.Name)
+		if !si.slackEmojiExists(rctx, emojiName) {
+			rctx.Logger().Debug("Slack Import: Unable to import the reaction as the emoji does not exist in Mattermost.", mlog.String("emoji_name", sReaction.Name))
+			si.report.skip(ReportItemReaction, sPost.TimeStamp, sChannel.Name, ReportReasonUnknownEmoji, sReaction.Name)
+			continue
 		}
+
+		for _, userId := range sReaction.Users {
+			user := users[userId]
+			if user == nil {
+				si.report.skip(ReportItemReaction, sPost.TimeStamp, sChannel.Name, ReportReasonUnknownUser, userId)
+				continue
+			}
+			reaction := &model.Reaction{
+				UserId:    user.Id,
+				PostId:    postId,
+				EmojiName: emojiName,
+				ChannelId: channel.Id,
+				// Slack doesn't export the time of the reactions.
+				CreateAt: slackConvertTimeStamp(sPost.TimeStamp),
+			}
+			if _, err := si.store.Reaction().Save(reaction); err != nil {
+				rctx.Logger().Warn("Slack Import: Unable to save the reaction.", mlog.String("post_id", postId), mlog.String("emoji_name", emojiName), mlog.Err(err))
+				si.report.skip(ReportItemReaction, sPost.TimeStamp, sChannel.Name, ReportReasonImportFailed, emojiName)
+				continue
+			}
+			si.report.Reactions++
+		}
+	}
+}
+
+func (si *SlackImporter) slackEmojiExists(rctx request.CTX, emojiName string) bool {
+	if model.IsSystemEmojiName(emojiName) {
+		return true
 	}
+	_, err := si.store.Emoji().GetByName(rctx, emojiName, true)
+	return err == nil
 }
 
 func (si *SlackImporter) slackUploadFile(rctx request.CTX, slackPostFile *slackFile, uploads map[string]*zip.File, teamId string, channelId string, userId string, slackTimestamp string) (*model.FileInfo, bool) {
 	if slackPostFile == nil {
 		rctx.Logger().Warn("Slack Import: Unable to attach the file to the post as the latter has no file section present in Slack export.")
+		si.report.skip(ReportItemFile, "", "", ReportReasonInvalid, "missing file section")
 		return nil, false
 	}
 	file, ok := uploads[slackPostFile.Id]
 	if !ok {
 		rctx.Logger().Warn("Slack Import: Unable to import file as the file is missing from the Slack export zip file.", mlog.String("file_id", slackPostFile.Id))
+		si.report.skip(ReportItemFile, slackPostFile.Id, "", ReportReasonMissingFile, "")
 		return nil, false
 	}
 	openFile, err := file.Open()
 	if err != nil {
 		rctx.Logger().Warn("Slack Import: Unable to open the file from the Slack export.", mlog.String("file_id", slackPostFile.Id), mlog.Err(err))
+		si.report.skip(ReportItemFile, slackPostFile.Id, "", ReportReasonImportFailed, err.Error())
 		return nil, false
 	}
 	defer openFile.Close()
@@ -542,8 +811,10 @@ func (si *SlackImporter) slackUploadFile(rctx request.CTX, slackPostFile *slackF
 	uploadedFile, err := si.oldImportFile(rctx, timestamp, reader, teamId, channelId, userId, filepath.Base(file.Name))
 	if err != nil {
 		rctx.Logger().Warn("Slack Import: An error occurred when uploading file.", mlog.String("file_id", slackPostFile.Id), mlog.Err(err))
+		si.report.skip(ReportItemFile, slackPostFile.Id, "", ReportReasonImportFailed, err.Error())
 		return nil, false
 	}
+	si.report.Files++
 
 	return uploadedFile, true
 }
@@ -631,6 +902,7 @@ func (si *SlackImporter) slackAddChannels(rctx request.CTX, teamId string, slack
 			if mChannel == nil {
 				rctx.Logger().Warn("Slack Import: Unable to import Slack channel.", mlog.String("channel_display_name", newChannel.DisplayName))
 				importerLog.WriteString(i18n.T("api.slackimport.slack_add_channels.import_failed", map[string]any{"DisplayName": newChannel.DisplayName}))
+				si.report.skip(ReportItemChannel, sChannel.Id, sChannel.Name, ReportReasonImportFailed, "")
 				continue
 			}
 		}
@@ -641,25 +913,70 @@ func (si *SlackImporter) slackAddChannels(rctx request.CTX, teamId string, slack
 		}
 		importerLog.WriteString(newChannel.DisplayName + "\r\n")
 		addedChannels[sChannel.Id] = mChannel
-		si.slackAddPosts(rctx, teamId, mChannel, posts[sChannel.Name], users, uploads, botUser)
+		si.report.Channels++
+		if sChannel.IsShared || sChannel.IsExtShared {
+			si.report.SharedChannels++
+		}
+		si.slackAddPosts(rctx, teamId, mChannel, sChannel, posts[sChannel.Name], users, uploads, botUser)
+		si.slackAddBookmarks(rctx, mChannel, sChannel, users, botUser)
 	}
 
 	return addedChannels
 }
 
+// slackAddBookmarks adds the link bookmarks of a channel, owned by the creator of the channel or else by the bot user.
+func (si *SlackImporter) slackAddBookmarks(rctx request.CTX, channel *model.Channel, sChannel slackChannel, users map[string]*model.User, botUser *model.User) {
+	owner := users[sChannel.Creator]
+	if owner == nil {
+		owner = botUser
+	}
+
+	for _, sBookmark := range sChannel.Bookmarks {
+		if sBookmark.Type != "link" {
+			rctx.Logger().Debug("Slack Import: Unable to import the bookmark as its type is not supported.", mlog.String("bookmark_type", sBookmark.Type))
+			si.report.skip(ReportItemBookmark, sBookmark.Id, sChannel.Name, ReportReasonUnsupportedType, sBookmark.Type)
+			continue
+		}
+		if owner == nil {
+			rctx.Logger().Warn("Slack Import: Unable to import the bookmark as the bot user does not exist.")
+			si.report.skip(ReportItemBookmark, sBookmark.Id, sChannel.Name, ReportReasonMissingBotUser, "")
+			continue
+		}
+
+		displayName := sBookmark.Title
+		if displayName == "" {
+			displayName = sBookmark.Link
+		}
+		bookmark := &model.ChannelBookmark{
+			ChannelId:   channel.Id,
+			OwnerId:     owner.Id,
+			DisplayName: truncateRunes(displayName, model.DisplayNameMaxRunes),
+			LinkUrl:     sBookmark.Link,
+			Emoji:       strings.Trim(sBookmark.Emoji, ":"),
+			Type:        model.ChannelBookmarkLink,
+		}
+		if _, err := si.store.ChannelBookmark().Save(bookmark, true); err != nil {
+			rctx.Logger().Warn("Slack Import: Unable to save the bookmark.", mlog.String("bookmark_id", sBookmark.Id), mlog.Err(err))
+			si.report.skip(ReportItemBookmark, sBookmark.Id, sChannel.Name, ReportReasonImportFailed, err.Error())
+			continue
+		}
+		si.report.Bookmarks++
+	}
+}
+
 //
 // -- Old SlackImport Functions --
 // Import functions are suitable for entering posts and users into the database without
 // some of the usual checks. (IsValid is still run)
 //
 
+// oldImportPost saves a post, split in several posts if the message is too long, and returns the id of the first post.
 func (si *SlackImporter) oldImportPost(rctx request.CTX, post *model.Post) string {
 	// Workaround for empty messages, which may be the case if they are webhook posts.
 	firstIteration := true
 	firstPostId := ""
-	if post.RootId != "" {
-		firstPostId = post.RootId
-	}
+	// The rest of a long message is posted as replies to the first post, or to the root of its thread.
+	rootId := post.RootId
 	maxPostSize := si.actions.MaxPostSize()
 	for messageRuneCount := utf8.RuneCountInString(post.Message); messageRuneCount > 0 || firstIteration; messageRuneCount = utf8.RuneCountInString(post.Message) {
 		var remainder string
@@ -672,7 +989,7 @@ func (si *SlackImporter) oldImportPost(rctx request.CTX, post *model.Post) strin
 
 		post.Hashtags, _ = model.ParseHashtags(post.Message)
 
-		post.RootId = firstPostId
+		post.RootId = rootId
 
 		_, err := si.store.Post().Save(rctx, post)
 		if err != nil {
@@ -680,8 +997,11 @@ func (si *SlackImporter) oldImportPost(rctx request.CTX, post *model.Post) strin
 		}
 
 		if firstIteration {
-			if firstPostId == "" {
+			if err == nil {
 				firstPostId = post.Id
+				if rootId == "" {
+					rootId = post.Id
+				}
 			}
 			for _, fileId := range post.FileIds {
 				if err := si.store.FileInfo().AttachToPost(rctx, fileId, post.Id, post.ChannelId, post.UserId); err != nil {
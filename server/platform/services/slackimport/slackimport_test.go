@@ -6,6 +6,7 @@ package slackimport
 import (
 	"archive/zip"
 	"bytes"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,11 +14,13 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/request"
 	"github.com/mattermost/mattermost/server/v8/channels/store/storetest/mocks"
+	"github.com/mattermost/mattermost/server/v8/channels/utils"
 )
 
 func TestSlackConvertTimeStamp(t *testing.T) {
@@ -459,3 +462,240 @@ func TestSlackUploadFile(t *testing.T) {
 		require.False(t, ok)
 	})
 }
+
+func TestSlackConvertEmojiName(t *testing.T) {
+	for _, tc := range []struct {
+		input  string
+		output string
+	}{
+		{"thumbsup", "thumbsup"},
+		{"thumbsup::skin-tone-2", "thumbsup_light_skin_tone"},
+		{"thumbsup::skin-tone-6", "thumbsup_dark_skin_tone"},
+		{"smile::skin-tone-3", "smile"},
+		{"custom-emoji", "custom-emoji"},
+	} {
+		assert.Equal(t, tc.output, slackConvertEmojiName(tc.input), "input = %v", tc.input)
+	}
+}
+
+func TestSlackConvertUserGroupName(t *testing.T) {
+	for _, tc := range []struct {
+		handle string
+		id     string
+		output string
+	}{
+		{"design-team", "S0G08DLQH", "design-team"},
+		{"Design Team", "S0G08DLQH", "design-team"},
+		{"_on.call_", "S0G08DLQH", "on.call"},
+		{"дизайн", "S0G08DLQH", "slack-group-s0g08dlqh"},
+		{strings.Repeat("a", 70), "S0G08DLQH", strings.Repeat("a", model.GroupNameMaxLength)},
+	} {
+		assert.Equal(t, tc.output, slackConvertUserGroupName(tc.handle, tc.id), "handle = %v", tc.handle)
+	}
+}
+
+func TestSlackParsePostsEdits(t *testing.T) {
+	posts, err := slackParsePosts(strings.NewReader(`[
+		{"type": "message", "user": "U1", "text": "first", "ts": "1469785419.000033", "edited": {"user": "U1", "ts": "1469785500.000000"}},
+		{"type": "message", "subtype": "message_changed", "ts": "1469785600.000000", "message": {"type": "message", "user": "U1", "text": "second", "ts": "1469785419.000033"}}
+	]`))
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	require.NotNil(t, posts[0].Edited)
+	assert.Equal(t, "1469785500.000000", posts[0].Edited.TimeStamp)
+	assert.Equal(t, "second", posts[1].Text)
+	assert.Equal(t, "1469785419.000033", posts[1].Message.TimeStamp)
+}
+
+func TestSlackAddPosts(t *testing.T) {
+	config := &model.Config{}
+	config.SetDefaults()
+	rctx := request.TestContext(t)
+
+	user := &model.User{Id: model.NewId(), Username: "user"}
+	botUser := &model.User{Id: model.NewId(), Username: "bot"}
+	users := map[string]*model.User{"U1": user}
+	channel := &model.Channel{Id: model.NewId()}
+	sChannel := slackChannel{Id: "C1", Name: "general", Pins: []slackPin{{Id: "1469785420.000000"}}}
+
+	posts, err := slackParsePosts(strings.NewReader(`[
+		{"type": "message", "user": "U1", "text": "root", "ts": "1469785419.000000", "thread_ts": "1469785419.000000",
+			"pinned_to": ["C1"], "reactions": [{"name": "thumbsup::skin-tone-2", "users": ["U1", "U2"]}, {"name": "unknown", "users": ["U1"]}]},
+		{"type": "message", "user": "U1", "text": "reply", "ts": "1469785420.000000", "thread_ts": "1469785419.000000", "edited": {"user": "U1", "ts": "1469785430.000000"}},
+		{"type": "message", "user": "U1", "text": "orphan", "ts": "1469785421.000000", "thread_ts": "1469785000.000000"},
+		{"type": "message", "user": "U3", "text": "external", "ts": "1469785422.000000", "user_team": "T2", "user_profile": {"real_name": "External User"}},
+		{"type": "message", "user": "U2", "text": "unknown", "ts": "1469785423.000000"},
+		{"type": "message", "subtype": "message_changed", "ts": "1469785424.000000", "message": {"type": "message", "user": "U1", "text": "edited root", "ts": "1469785419.000000"}},
+		{"type": "message", "subtype": "tombstone", "ts": "1469785425.000000"}
+	]`))
+	require.NoError(t, err)
+
+	var savedPosts []*model.Post
+	postStore := &mocks.PostStore{}
+	postStore.On("Save", mock.Anything, mock.AnythingOfType("*model.Post")).Return(func(_ request.CTX, post *model.Post) (*model.Post, error) {
+		post.Id = model.NewId()
+		savedPosts = append(savedPosts, post.Clone())
+		return post, nil
+	})
+	postStore.On("GetSingle", mock.Anything, mock.Anything, false).Return(func(_ request.CTX, id string, _ bool) (*model.Post, error) {
+		for _, post := range savedPosts {
+			if post.Id == id {
+				return post.Clone(), nil
+			}
+		}
+		return nil, errors.New("not found")
+	})
+	var editedPost *model.Post
+	postStore.On("Update", mock.Anything, mock.AnythingOfType("*model.Post"), mock.AnythingOfType("*model.Post")).Return(func(_ request.CTX, newPost, _ *model.Post) (*model.Post, error) {
+		editedPost = newPost
+		return newPost, nil
+	})
+	var reactions []*model.Reaction
+	reactionStore := &mocks.ReactionStore{}
+	reactionStore.On("Save", mock.AnythingOfType("*model.Reaction")).Return(func(reaction *model.Reaction) (*model.Reaction, error) {
+		reactions = append(reactions, reaction)
+		return reaction, nil
+	})
+	emojiStore := &mocks.EmojiStore{}
+	emojiStore.On("GetByName", mock.Anything, "unknown", true).Return(nil, errors.New("not found"))
+
+	store := &mocks.Store{}
+	store.On("Post").Return(postStore)
+	store.On("Reaction").Return(reactionStore)
+	store.On("Emoji").Return(emojiStore)
+
+	importer := New(store, Actions{
+		MaxPostSize: func() int { return model.PostMessageMaxRunesV2 },
+	}, config)
+	importer.slackAddPosts(rctx, "team-id", channel, sChannel, posts, users, nil, botUser)
+
+	require.Len(t, savedPosts, 4)
+	root, reply, orphan, external := savedPosts[0], savedPosts[1], savedPosts[2], savedPosts[3]
+	assert.Empty(t, root.RootId)
+	assert.True(t, root.IsPinned)
+	assert.Equal(t, root.Id, reply.RootId)
+	assert.True(t, reply.IsPinned)
+	assert.EqualValues(t, 1469785430000, reply.EditAt)
+	assert.Empty(t, orphan.RootId)
+	assert.Equal(t, botUser.Id, external.UserId)
+	assert.Equal(t, "External User", external.GetProp("override_username"))
+
+	require.Len(t, reactions, 1)
+	assert.Equal(t, "thumbsup_light_skin_tone", reactions[0].EmojiName)
+	assert.Equal(t, root.Id, reactions[0].PostId)
+	assert.EqualValues(t, 1469785419000, reactions[0].CreateAt)
+
+	require.NotNil(t, editedPost)
+	assert.Equal(t, root.Id, editedPost.Id)
+	assert.Equal(t, "edited root", editedPost.Message)
+	assert.EqualValues(t, 1469785424000, editedPost.EditAt)
+
+	report := importer.Report()
+	assert.Equal(t, 3, report.Posts)
+	assert.Equal(t, 1, report.Replies)
+	assert.Equal(t, 1, report.OrphanedReplies)
+	assert.Equal(t, 1, report.SharedChannelPosts)
+	assert.Equal(t, 2, report.PinnedPosts)
+	assert.Equal(t, 1, report.EditedPosts)
+	assert.Equal(t, 1, report.Edits)
+	assert.Equal(t, 1, report.Reactions)
+	assert.ElementsMatch(t, []*ImportReportItem{
+		{Type: ReportItemReaction, Id: "1469785419.000000", Channel: "general", Reason: ReportReasonUnknownUser, Detail: "U2"},
+		{Type: ReportItemReaction, Id: "1469785419.000000", Channel: "general", Reason: ReportReasonUnknownEmoji, Detail: "unknown"},
+		{Type: ReportItemPost, Id: "1469785423.000000", Channel: "general", Reason: ReportReasonUnknownUser, Detail: "U2"},
+		{Type: ReportItemPost, Id: "1469785425.000000", Channel: "general", Reason: ReportReasonUnsupportedType, Detail: "message/tombstone"},
+	}, report.Skipped)
+}
+
+func TestSlackAddBookmarks(t *testing.T) {
+	config := &model.Config{}
+	config.SetDefaults()
+	rctx := request.TestContext(t)
+
+	creator := &model.User{Id: model.NewId()}
+	channel := &model.Channel{Id: model.NewId()}
+	sChannel := slackChannel{
+		Id:      "C1",
+		Name:    "general",
+		Creator: "U1",
+		Bookmarks: []slackBookmark{
+			{Id: "Bk1", Title: "Docs", Link: "https://example.com/docs", Emoji: ":books:", Type: "link"},
+			{Id: "Bk2", Title: "Canvas", Type: "canvas"},
+		},
+	}
+
+	var bookmarks []*model.ChannelBookmark
+	bookmarkStore := &mocks.ChannelBookmarkStore{}
+	bookmarkStore.On("Save", mock.AnythingOfType("*model.ChannelBookmark"), true).Return(func(bookmark *model.ChannelBookmark, _ bool) (*model.ChannelBookmarkWithFileInfo, error) {
+		bookmarks = append(bookmarks, bookmark)
+		return &model.ChannelBookmarkWithFileInfo{ChannelBookmark: bookmark}, nil
+	})
+	store := &mocks.Store{}
+	store.On("ChannelBookmark").Return(bookmarkStore)
+
+	importer := New(store, Actions{}, config)
+	importer.slackAddBookmarks(rctx, channel, sChannel, map[string]*model.User{"U1": creator}, nil)
+
+	require.Len(t, bookmarks, 1)
+	assert.Equal(t, channel.Id, bookmarks[0].ChannelId)
+	assert.Equal(t, creator.Id, bookmarks[0].OwnerId)
+	assert.Equal(t, "Docs", bookmarks[0].DisplayName)
+	assert.Equal(t, "books", bookmarks[0].Emoji)
+	assert.Equal(t, model.ChannelBookmarkLink, bookmarks[0].Type)
+
+	report := importer.Report()
+	assert.Equal(t, 1, report.Bookmarks)
+	assert.Equal(t, []*ImportReportItem{
+		{Type: ReportItemBookmark, Id: "Bk2", Channel: "general", Reason: ReportReasonUnsupportedType, Detail: "canvas"},
+	}, report.Skipped)
+}
+
+func TestSlackAddUserGroups(t *testing.T) {
+	require.NoError(t, utils.TranslationsPreInit())
+	config := &model.Config{}
+	config.SetDefaults()
+	rctx := request.TestContext(t)
+
+	user := &model.User{Id: model.NewId()}
+	users := map[string]*model.User{"U1": user}
+	existingGroup := &model.Group{Id: model.NewId(), Name: model.NewPointer("support"), DisplayName: "Support", Source: model.GroupSourceCustom}
+
+	groupStore := &mocks.GroupStore{}
+	groupStore.On("GetByName", "support", mock.Anything).Return(existingGroup, nil)
+	groupStore.On("GetByName", "ldap-group", mock.Anything).Return(&model.Group{Source: model.GroupSourceLdap}, nil)
+	groupStore.On("GetByName", mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
+	groupStore.On("UpsertMembers", existingGroup.Id, []string{user.Id}).Return(nil, nil)
+	store := &mocks.Store{}
+	store.On("Group").Return(groupStore)
+
+	var createdGroups []*model.GroupWithUserIds
+	importer := New(store, Actions{
+		CreateGroupWithUserIds: func(group *model.GroupWithUserIds) (*model.Group, *model.AppError) {
+			createdGroups = append(createdGroups, group)
+			return &group.Group, nil
+		},
+	}, config)
+
+	log := bytes.NewBuffer(nil)
+	importer.slackAddUserGroups(rctx, []slackUserGroup{
+		{Id: "S1", Name: "Design Team", Handle: "design", Description: "The designers", Users: []string{"U1", "U2"}},
+		{Id: "S2", Name: "Support", Handle: "support", Users: []string{"U1"}},
+		{Id: "S3", Name: "LDAP", Handle: "ldap-group", Users: []string{"U1"}},
+		{Id: "S4", Name: "Old", Handle: "old", DateDelete: 1469785419},
+	}, users, log)
+
+	require.Len(t, createdGroups, 1)
+	assert.Equal(t, "design", createdGroups[0].GetName())
+	assert.Equal(t, "Design Team", createdGroups[0].DisplayName)
+	assert.Equal(t, model.GroupSourceCustom, createdGroups[0].Source)
+	assert.True(t, createdGroups[0].AllowReference)
+	assert.Equal(t, []string{user.Id}, createdGroups[0].UserIds)
+	groupStore.AssertCalled(t, "UpsertMembers", existingGroup.Id, []string{user.Id})
+
+	report := importer.Report()
+	assert.Equal(t, 2, report.UserGroups)
+	assert.Equal(t, []*ImportReportItem{
+		{Type: ReportItemUserGroup, Id: "S3", Reason: ReportReasonNameTaken, Detail: "ldap-group"},
+		{Type: ReportItemUserGroup, Id: "S4", Reason: ReportReasonDeleted},
+	}, report.Skipped)
+}
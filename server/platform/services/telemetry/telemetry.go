@@ -75,6 +75,8 @@ const (
 	TrackConfigGuestAccounts       = "config_guest_accounts"
 	TrackConfigImageProxy          = "config_image_proxy"
 	TrackConfigBleve               = "config_bleve"
+	TrackConfigPostgresSearch      = "config_postgres_search"
+	TrackConfigVectorSearch        = "config_vector_search"
 	TrackConfigExport              = "config_export"
 	TrackConfigWrangler            = "config_wrangler"
 	TrackConfigConnectedWorkspaces = "config_connected_workspaces"
@@ -659,10 +661,25 @@ func (ts *TelemetryService) trackConfig() {
 	configs[TrackConfigFile] = map[string]any{
 		"enable_public_links":           cfg.FileSettings.EnablePublicLink,
 		"driver_name":                   *cfg.FileSettings.DriverName,
+		"dedup_driver_name":             *cfg.FileSettings.DedupDriverName,
+		"enable_encryption":             *cfg.FileSettings.EnableEncryption,
+		"enable_tiered_storage":         *cfg.FileSettings.EnableTieredStorage,
+		"tiered_cold_after_days":        *cfg.FileSettings.TieredStorageColdAfterDays,
+		"tiered_cold_above_size_mb":     *cfg.FileSettings.TieredStorageColdAboveSizeMB,
 		"isdefault_directory":           isDefault(*cfg.FileSettings.Directory, model.FileSettingsDefaultDirectory),
 		"isabsolute_directory":          filepath.IsAbs(*cfg.FileSettings.Directory),
 		"extract_content":               *cfg.FileSettings.ExtractContent,
 		"archive_recursion":             *cfg.FileSettings.ArchiveRecursion,
+		"extract_content_ooxml":         *cfg.FileSettings.ExtractContentOOXML,
+		"extract_content_odf":           *cfg.FileSettings.ExtractContentODF,
+		"extract_content_rtf":           *cfg.FileSettings.ExtractContentRTF,
+		"extract_content_email":         *cfg.FileSettings.ExtractContentEmail,
+		"extract_content_max_size_mb":   *cfg.FileSettings.ExtractContentMaxSizeMB,
+		"extract_content_timeout_secs":  *cfg.FileSettings.ExtractContentTimeoutSeconds,
+		"extract_content_ocr":           *cfg.FileSettings.ExtractContentOCR,
+		"ocr_language":                  *cfg.FileSettings.ExtractContentOCRLanguage,
+		"ocr_max_image_size_mb":         *cfg.FileSettings.ExtractContentOCRMaxImageSizeMB,
+		"ocr_timeout_secs":              *cfg.FileSettings.ExtractContentOCRTimeoutSeconds,
 		"amazon_s3_ssl":                 *cfg.FileSettings.AmazonS3SSL,
 		"amazon_s3_sse":                 *cfg.FileSettings.AmazonS3SSE,
 		"amazon_s3_signv2":              *cfg.FileSettings.AmazonS3SignV2,
@@ -826,6 +843,7 @@ func (ts *TelemetryService) trackConfig() {
 		"enable_experimental_gossip_encryption": *cfg.ClusterSettings.EnableExperimentalGossipEncryption,
 		"enable_gossip_compression":             *cfg.ClusterSettings.EnableGossipCompression,
 		"read_only_config":                      *cfg.ClusterSettings.ReadOnlyConfig,
+		"transport":                             *cfg.ClusterSettings.Transport,
 	}
 
 	configs[TrackConfigMetrics] = map[string]any{
@@ -919,6 +937,9 @@ func (ts *TelemetryService) trackConfig() {
 		"is_default_global_relay_email_address": isDefault(*cfg.MessageExportSettings.GlobalRelaySettings.EmailAddress, ""),
 		"global_relay_smtp_server_timeout":      *cfg.MessageExportSettings.GlobalRelaySettings.SMTPServerTimeout,
 		"download_export_results":               *cfg.MessageExportSettings.DownloadExportResults,
+		"webhook_max_retries":                   *cfg.MessageExportSettings.WebhookSettings.MaxRetries,
+		"webhook_request_timeout_seconds":       *cfg.MessageExportSettings.WebhookSettings.RequestTimeoutSeconds,
+		"webhook_run_interval_minutes":          *cfg.MessageExportSettings.WebhookSettings.RunIntervalMinutes,
 	}
 
 	configs[TrackConfigDisplay] = map[string]any{
@@ -948,6 +969,24 @@ func (ts *TelemetryService) trackConfig() {
 		"bulk_indexing_batch_size": *cfg.BleveSettings.BatchSize,
 	}
 
+	configs[TrackConfigPostgresSearch] = map[string]any{
+		"enable_indexing":          *cfg.PostgresSearchSettings.EnableIndexing,
+		"enable_searching":         *cfg.PostgresSearchSettings.EnableSearching,
+		"enable_autocomplete":      *cfg.PostgresSearchSettings.EnableAutocomplete,
+		"text_search_config":       *cfg.PostgresSearchSettings.TextSearchConfig,
+		"bulk_indexing_batch_size": *cfg.PostgresSearchSettings.BatchSize,
+	}
+
+	configs[TrackConfigVectorSearch] = map[string]any{
+		"enable_indexing":           *cfg.VectorSearchSettings.EnableIndexing,
+		"enable_searching":          *cfg.VectorSearchSettings.EnableSearching,
+		"isdefault_embedding_model": isDefault(*cfg.VectorSearchSettings.EmbeddingModel, ""),
+		"embedding_dimensions":      *cfg.VectorSearchSettings.EmbeddingDimensions,
+		"request_timeout_seconds":   *cfg.VectorSearchSettings.RequestTimeoutSeconds,
+		"backend":                   *cfg.VectorSearchSettings.Backend,
+		"bulk_indexing_batch_size":  *cfg.VectorSearchSettings.BatchSize,
+	}
+
 	configs[TrackConfigExport] = map[string]any{
 		"retention_days": *cfg.ExportSettings.RetentionDays,
 	}
@@ -0,0 +1,546 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	// DedupBlobDirectory is the directory of the physical backend that holds
+	// the content-addressed blobs. It is hidden from directory listings.
+	DedupBlobDirectory = "blobs"
+)
+
+// BlobRefStore persists which content-addressed blob backs each logical
+// path, along with the number of paths referencing every blob.
+type BlobRefStore interface {
+	// GetByPaths returns the references for the given paths. Paths without a
+	// reference are omitted from the result.
+	GetByPaths(paths []string) ([]*model.FileBlobRef, error)
+	// GetByPathPrefix returns every reference whose path starts with prefix.
+	GetByPathPrefix(prefix string) ([]*model.FileBlobRef, error)
+	// Save stores a new reference and returns the reference count of its blob.
+	Save(ref *model.FileBlobRef) (int64, error)
+	// Delete removes the reference for path and returns it together with the
+	// number of references left for its blob.
+	Delete(path string) (*model.FileBlobRef, int64, error)
+	// GetUnreferenced returns, in order, up to limit hashes greater than
+	// afterHash of the blobs that have no references since before.
+	GetUnreferenced(before int64, afterHash string, limit int) ([]string, error)
+	// DeleteUnreferenced forgets the blob identified by hash if it still has
+	// no references since before, calling remove first. References to the
+	// blob can't be saved until it returns. It returns false if the blob is
+	// referenced.
+	DeleteUnreferenced(hash string, before int64, remove func() error) (bool, error)
+}
+
+// DedupFileBackend stores file contents once per SHA-256 digest on top of
+// another FileBackend, keeping the mapping of logical paths to blobs in a
+// BlobRefStore. Copying a file, or writing content that is already stored,
+// only adds a reference. Blobs are never removed when their last reference
+// goes, since a concurrent write may be about to reference them again;
+// CollectGarbage removes them once they have been unreferenced for a while.
+//
+// Files written to the physical backend before deduplication was enabled are
+// served as they are until the file deduplication job migrates them.
+type DedupFileBackend struct {
+	physical FileBackend
+	refs     BlobRefStore
+}
+
+func NewDedupFileBackend(physical FileBackend, refs BlobRefStore) *DedupFileBackend {
+	return &DedupFileBackend{
+		physical: physical,
+		refs:     refs,
+	}
+}
+
+// PhysicalBackend returns the backend that holds the blobs.
+func (b *DedupFileBackend) PhysicalBackend() FileBackend {
+	return b.physical
+}
+
+func dedupBlobPath(hash string) string {
+	return path.Join(DedupBlobDirectory, hash[:2], hash)
+}
+
+func isDedupBlobPath(p string) bool {
+	return p == DedupBlobDirectory || strings.HasPrefix(p, DedupBlobDirectory+"/")
+}
+
+func (b *DedupFileBackend) getRef(path string) (*model.FileBlobRef, error) {
+	refs, err := b.refs.GetByPaths([]string{path})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get the blob reference for %s", path)
+	}
+	for _, ref := range refs {
+		if ref.Path == path {
+			return ref, nil
+		}
+	}
+	return nil, nil
+}
+
+func (b *DedupFileBackend) DriverName() string {
+	return driverDedup
+}
+
+func (b *DedupFileBackend) TestConnection() error {
+	return b.physical.TestConnection()
+}
+
+func (b *DedupFileBackend) Reader(path string) (ReadCloseSeeker, error) {
+	ref, err := b.getRef(path)
+	if err != nil {
+		return nil, err
+	}
+	if ref == nil {
+		return b.physical.Reader(path)
+	}
+	return b.physical.Reader(dedupBlobPath(ref.Hash))
+}
+
+func (b *DedupFileBackend) ReadFile(path string) ([]byte, error) {
+	ref, err := b.getRef(path)
+	if err != nil {
+		return nil, err
+	}
+	if ref == nil {
+		return b.physical.ReadFile(path)
+	}
+	return b.physical.ReadFile(dedupBlobPath(ref.Hash))
+}
+
+func (b *DedupFileBackend) FileExists(path string) (bool, error) {
+	ref, err := b.getRef(path)
+	if err != nil {
+		return false, err
+	}
+	if ref == nil {
+		return b.physical.FileExists(path)
+	}
+	return true, nil
+}
+
+func (b *DedupFileBackend) FileSize(path string) (int64, error) {
+	ref, err := b.getRef(path)
+	if err != nil {
+		return 0, err
+	}
+	if ref == nil {
+		return b.physical.FileSize(path)
+	}
+	return ref.Size, nil
+}
+
+func (b *DedupFileBackend) FileModTime(path string) (time.Time, error) {
+	ref, err := b.getRef(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if ref == nil {
+		return b.physical.FileModTime(path)
+	}
+	return time.UnixMilli(ref.CreateAt), nil
+}
+
+func (b *DedupFileBackend) CopyFile(oldPath, newPath string) error {
+	ref, err := b.getRef(oldPath)
+	if err != nil {
+		return err
+	}
+	if ref == nil {
+		return b.physical.CopyFile(oldPath, newPath)
+	}
+
+	return b.addRef(newPath, ref.Hash, ref.Size)
+}
+
+func (b *DedupFileBackend) MoveFile(oldPath, newPath string) error {
+	ref, err := b.getRef(oldPath)
+	if err != nil {
+		return err
+	}
+	if ref == nil {
+		return b.physical.MoveFile(oldPath, newPath)
+	}
+
+	if err := b.addRef(newPath, ref.Hash, ref.Size); err != nil {
+		return err
+	}
+	return b.release(oldPath)
+}
+
+func (b *DedupFileBackend) WriteFile(fr io.Reader, path string) (int64, error) {
+	written, err := b.writeBlob(fr, path)
+	if err != nil {
+		return written, errors.Wrapf(err, "unable to write the file %s", path)
+	}
+	return written, nil
+}
+
+// AppendFile stores the concatenation of the current contents and fr as a new
+// blob, since blobs are immutable and may be shared with other paths.
+func (b *DedupFileBackend) AppendFile(fr io.Reader, path string) (int64, error) {
+	ref, err := b.getRef(path)
+	if err != nil {
+		return 0, err
+	}
+	if ref == nil {
+		return b.physical.AppendFile(fr, path)
+	}
+
+	current, err := b.physical.Reader(dedupBlobPath(ref.Hash))
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to find the file %s to append the data", path)
+	}
+	defer current.Close()
+
+	written, err := b.writeBlob(io.MultiReader(current, fr), path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable append the data in the file %s", path)
+	}
+	return written - ref.Size, nil
+}
+
+func (b *DedupFileBackend) RemoveFile(path string) error {
+	ref, err := b.getRef(path)
+	if err != nil {
+		return err
+	}
+	if ref == nil {
+		return b.physical.RemoveFile(path)
+	}
+	return b.release(path)
+}
+
+func (b *DedupFileBackend) ListDirectory(path string) ([]string, error) {
+	return b.listDirectory(path, false)
+}
+
+func (b *DedupFileBackend) ListDirectoryRecursively(path string) ([]string, error) {
+	return b.listDirectory(path, true)
+}
+
+func (b *DedupFileBackend) listDirectory(dir string, recursive bool) ([]string, error) {
+	var physicalPaths []string
+	var err error
+	if recursive {
+		physicalPaths, err = b.physical.ListDirectoryRecursively(dir)
+	} else {
+		physicalPaths, err = b.physical.ListDirectory(dir)
+	}
+
+	refs, refsErr := b.refsUnder(dir)
+	if refsErr != nil {
+		return nil, refsErr
+	}
+	if err != nil && len(refs) == 0 {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(physicalPaths)+len(refs))
+	results := []string{}
+	add := func(p string) {
+		if !seen[p] {
+			seen[p] = true
+			results = append(results, p)
+		}
+	}
+
+	for _, p := range physicalPaths {
+		if !isDedupBlobPath(p) {
+			add(p)
+		}
+	}
+
+	prefix := dirPrefix(dir)
+	for _, ref := range refs {
+		if recursive {
+			add(ref.Path)
+			continue
+		}
+		rel := strings.TrimPrefix(ref.Path, prefix)
+		if i := strings.Index(rel, "/"); i >= 0 {
+			rel = rel[:i]
+		}
+		add(path.Join(dir, rel))
+	}
+
+	sort.Strings(results)
+	return results, nil
+}
+
+func (b *DedupFileBackend) RemoveDirectory(path string) error {
+	refs, err := b.refsUnder(path)
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if err := b.release(ref.Path); err != nil {
+			return errors.Wrapf(err, "unable to remove the directory %s", path)
+		}
+	}
+	return b.physical.RemoveDirectory(path)
+}
+
+// ZipReader will create a zip of path. If path is a single file, it will zip the single file.
+// If deflate is true, the contents will be compressed. It will stream the zip to io.ReadCloser.
+func (b *DedupFileBackend) ZipReader(path string, deflate bool) (io.ReadCloser, error) {
+	ref, err := b.getRef(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	baseDir := path
+	if ref != nil {
+		files = []string{path}
+		baseDir = filepath.Dir(path)
+	} else {
+		refs, err := b.refsUnder(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(refs) == 0 {
+			return b.physical.ZipReader(path, deflate)
+		}
+		if files, err = b.ListDirectoryRecursively(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return zipFiles(b, files, baseDir, deflate), nil
+}
+
+// DedupFile moves a file that was written to the physical backend before
+// deduplication was enabled into the blob store. It returns false if the path
+// is already backed by a blob.
+func (b *DedupFileBackend) DedupFile(path string) (bool, error) {
+	if isDedupBlobPath(path) {
+		return false, nil
+	}
+
+	ref, err := b.getRef(path)
+	if err != nil {
+		return false, err
+	}
+	if ref != nil {
+		return false, nil
+	}
+
+	reader, err := b.physical.Reader(path)
+	if err != nil {
+		return false, err
+	}
+	_, err = b.writeBlob(reader, path)
+	reader.Close()
+	if err != nil {
+		return false, errors.Wrapf(err, "unable to deduplicate the file %s", path)
+	}
+	return true, nil
+}
+
+// CollectGarbage removes the blobs that have had no references for at least
+// gracePeriod, and returns how many were removed.
+func (b *DedupFileBackend) CollectGarbage(gracePeriod time.Duration) (int, error) {
+	const batchSize = 1000
+	before := time.Now().Add(-gracePeriod).UnixMilli()
+
+	var removed int
+	afterHash := ""
+	for {
+		hashes, err := b.refs.GetUnreferenced(before, afterHash, batchSize)
+		if err != nil {
+			return removed, errors.Wrap(err, "unable to list the unreferenced blobs")
+		}
+
+		for _, hash := range hashes {
+			deleted, err := b.refs.DeleteUnreferenced(hash, before, func() error {
+				return b.removeBlob(hash)
+			})
+			if err != nil {
+				return removed, errors.Wrapf(err, "unable to remove blob %s", hash)
+			}
+			if deleted {
+				removed++
+			}
+		}
+
+		if len(hashes) < batchSize {
+			return removed, nil
+		}
+		afterHash = hashes[len(hashes)-1]
+	}
+}
+
+func (b *DedupFileBackend) removeBlob(hash string) error {
+	blobPath := dedupBlobPath(hash)
+	exists, err := b.physical.FileExists(blobPath)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	return b.physical.RemoveFile(blobPath)
+}
+
+// writeBlob spools fr to a temporary file while hashing it, uploads it to the
+// physical backend unless a blob with the same digest is stored already, and
+// points path at the blob.
+func (b *DedupFileBackend) writeBlob(fr io.Reader, path string) (int64, error) {
+	tmp, err := os.CreateTemp("", "mattermost-dedup-")
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to create a temporary file")
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hasher := sha256.New()
+	written, err := io.Copy(io.MultiWriter(tmp, hasher), fr)
+	if err != nil {
+		return written, errors.Wrap(err, "unable to read the file contents")
+	}
+	hash := hex.EncodeToString(hasher.Sum(nil))
+
+	if err := b.uploadBlob(tmp, hash); err != nil {
+		return 0, err
+	}
+
+	shadowed, err := b.saveRef(path, hash, written)
+	if err != nil {
+		return 0, err
+	}
+
+	// CollectGarbage may have removed the blob between the upload and the
+	// new reference if it had no references then. Now that it is referenced
+	// it won't be collected, so a second upload is enough.
+	if err := b.uploadBlob(tmp, hash); err != nil {
+		if releaseErr := b.release(path); releaseErr != nil {
+			return 0, errors.Wrapf(releaseErr, "unable to release the blob reference for %s after failing to write blob %s: %v", path, hash, err)
+		}
+		return 0, err
+	}
+
+	if err := b.removeShadowedFile(path, shadowed); err != nil {
+		return 0, err
+	}
+	return written, nil
+}
+
+// uploadBlob uploads the contents of tmp as the blob identified by hash,
+// unless the physical backend already holds it.
+func (b *DedupFileBackend) uploadBlob(tmp *os.File, hash string) error {
+	blobPath := dedupBlobPath(hash)
+	exists, err := b.physical.FileExists(blobPath)
+	if err != nil {
+		return errors.Wrapf(err, "unable to check if blob %s exists", hash)
+	}
+	if exists {
+		return nil
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return errors.Wrap(err, "unable to rewind the temporary file")
+	}
+	if _, err := b.physical.WriteFile(tmp, blobPath); err != nil {
+		return errors.Wrapf(err, "unable to write blob %s", hash)
+	}
+	return nil
+}
+
+// addRef points path at the blob identified by hash, which another path
+// references already, releasing the blob the path referenced before, if any.
+func (b *DedupFileBackend) addRef(path, hash string, size int64) error {
+	shadowed, err := b.saveRef(path, hash, size)
+	if err != nil {
+		return err
+	}
+	return b.removeShadowedFile(path, shadowed)
+}
+
+// saveRef points path at the blob identified by hash, releasing the blob the
+// path referenced before, if any. It returns true if the path had no
+// reference, in which case the physical backend may hold a copy of a file
+// written before deduplication was enabled.
+func (b *DedupFileBackend) saveRef(path, hash string, size int64) (bool, error) {
+	existing, err := b.getRef(path)
+	if err != nil {
+		return false, err
+	}
+	if existing != nil {
+		if existing.Hash == hash {
+			return false, nil
+		}
+		if err := b.release(path); err != nil {
+			return false, err
+		}
+	}
+
+	ref := &model.FileBlobRef{
+		Path: path,
+		Hash: hash,
+		Size: size,
+	}
+	ref.PreSave()
+	if _, err := b.refs.Save(ref); err != nil {
+		return false, errors.Wrapf(err, "unable to save the blob reference for %s", path)
+	}
+	return existing == nil, nil
+}
+
+// removeShadowedFile drops the copy of a file written before deduplication
+// was enabled, which the new reference of path now shadows.
+func (b *DedupFileBackend) removeShadowedFile(path string, shadowed bool) error {
+	if !shadowed {
+		return nil
+	}
+	if exists, err := b.physical.FileExists(path); err == nil && exists {
+		if err := b.physical.RemoveFile(path); err != nil {
+			return errors.Wrapf(err, "unable to remove the original copy of %s", path)
+		}
+	}
+	return nil
+}
+
+// release drops the reference held by path. The blob is left for
+// CollectGarbage to remove once no path references it.
+func (b *DedupFileBackend) release(path string) error {
+	if _, _, err := b.refs.Delete(path); err != nil {
+		return errors.Wrapf(err, "unable to delete the blob reference for %s", path)
+	}
+	return nil
+}
+
+func (b *DedupFileBackend) refsUnder(dir string) ([]*model.FileBlobRef, error) {
+	refs, err := b.refs.GetByPathPrefix(dirPrefix(dir))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the blob references under %s", dir)
+	}
+	return refs, nil
+}
+
+// dirPrefix returns dir as a prefix of the paths it contains, so that
+// "a" does not match "ab/c".
+func dirPrefix(dir string) string {
+	dir = strings.Trim(filepath.ToSlash(dir), "/")
+	if dir == "" || dir == "." {
+		return ""
+	}
+	return dir + "/"
+}
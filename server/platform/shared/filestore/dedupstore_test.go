@@ -0,0 +1,338 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+type memoryBlobRefStore struct {
+	mut       sync.Mutex
+	refs      map[string]*model.FileBlobRef
+	refCounts map[string]int64
+	updateAts map[string]int64
+
+	// beforeSave is called by Save before saving the reference.
+	beforeSave func()
+}
+
+func newMemoryBlobRefStore() *memoryBlobRefStore {
+	return &memoryBlobRefStore{
+		refs:      map[string]*model.FileBlobRef{},
+		refCounts: map[string]int64{},
+		updateAts: map[string]int64{},
+	}
+}
+
+// age makes every blob look like it was last referenced d earlier.
+func (s *memoryBlobRefStore) age(d time.Duration) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	for hash := range s.updateAts {
+		s.updateAts[hash] -= d.Milliseconds()
+	}
+}
+
+func (s *memoryBlobRefStore) GetByPaths(paths []string) ([]*model.FileBlobRef, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	refs := []*model.FileBlobRef{}
+	for _, p := range paths {
+		if ref, ok := s.refs[p]; ok {
+			refs = append(refs, ref)
+		}
+	}
+	return refs, nil
+}
+
+func (s *memoryBlobRefStore) GetByPathPrefix(prefix string) ([]*model.FileBlobRef, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	refs := []*model.FileBlobRef{}
+	for p, ref := range s.refs {
+		if strings.HasPrefix(p, prefix) {
+			refs = append(refs, ref)
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Path < refs[j].Path })
+	return refs, nil
+}
+
+func (s *memoryBlobRefStore) Save(ref *model.FileBlobRef) (int64, error) {
+	if s.beforeSave != nil {
+		s.beforeSave()
+	}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.refs[ref.Path] = ref
+	s.refCounts[ref.Hash]++
+	s.updateAts[ref.Hash] = model.GetMillis()
+	return s.refCounts[ref.Hash], nil
+}
+
+func (s *memoryBlobRefStore) Delete(path string) (*model.FileBlobRef, int64, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	ref, ok := s.refs[path]
+	if !ok {
+		return nil, 0, errors.New("not found")
+	}
+	delete(s.refs, path)
+	s.refCounts[ref.Hash]--
+	s.updateAts[ref.Hash] = model.GetMillis()
+	return ref, s.refCounts[ref.Hash], nil
+}
+
+func (s *memoryBlobRefStore) GetUnreferenced(before int64, afterHash string, limit int) ([]string, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	hashes := []string{}
+	for hash, refCount := range s.refCounts {
+		if refCount <= 0 && s.updateAts[hash] < before && hash > afterHash {
+			hashes = append(hashes, hash)
+		}
+	}
+	sort.Strings(hashes)
+	if len(hashes) > limit {
+		hashes = hashes[:limit]
+	}
+	return hashes, nil
+}
+
+func (s *memoryBlobRefStore) DeleteUnreferenced(hash string, before int64, remove func() error) (bool, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	refCount, ok := s.refCounts[hash]
+	if !ok || refCount > 0 || s.updateAts[hash] >= before {
+		return false, nil
+	}
+	if err := remove(); err != nil {
+		return false, err
+	}
+	delete(s.refCounts, hash)
+	delete(s.updateAts, hash)
+	return true, nil
+}
+
+func setupDedupFileBackend(t *testing.T) (*DedupFileBackend, *memoryBlobRefStore) {
+	refs := newMemoryBlobRefStore()
+	backend, err := NewFileBackend(FileBackendSettings{
+		DriverName:      driverDedup,
+		DedupDriverName: driverLocal,
+		Directory:       t.TempDir(),
+		BlobRefStore:    refs,
+	})
+	require.NoError(t, err)
+
+	dedupBackend, ok := backend.(*DedupFileBackend)
+	require.True(t, ok)
+	return dedupBackend, refs
+}
+
+func countBlobs(t *testing.T, b *DedupFileBackend) int {
+	blobs, err := b.PhysicalBackend().ListDirectoryRecursively(DedupBlobDirectory)
+	require.NoError(t, err)
+	return len(blobs)
+}
+
+func TestDedupFileBackend(t *testing.T) {
+	content := []byte("hello, deduplicated world")
+
+	t.Run("identical content is stored once", func(t *testing.T) {
+		b, refs := setupDedupFileBackend(t)
+
+		written, err := b.WriteFile(bytes.NewReader(content), "a/one.txt")
+		require.NoError(t, err)
+		assert.EqualValues(t, len(content), written)
+
+		_, err = b.WriteFile(bytes.NewReader(content), "b/two.txt")
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, countBlobs(t, b))
+		assert.Len(t, refs.refs, 2)
+
+		data, err := b.ReadFile("b/two.txt")
+		require.NoError(t, err)
+		assert.Equal(t, content, data)
+
+		size, err := b.FileSize("a/one.txt")
+		require.NoError(t, err)
+		assert.EqualValues(t, len(content), size)
+	})
+
+	t.Run("copy only adds a reference", func(t *testing.T) {
+		b, refs := setupDedupFileBackend(t)
+
+		_, err := b.WriteFile(bytes.NewReader(content), "a/one.txt")
+		require.NoError(t, err)
+		require.NoError(t, b.CopyFile("a/one.txt", "c/copy.txt"))
+
+		assert.Equal(t, 1, countBlobs(t, b))
+		assert.Len(t, refs.refs, 2)
+
+		data, err := b.ReadFile("c/copy.txt")
+		require.NoError(t, err)
+		assert.Equal(t, content, data)
+	})
+
+	t.Run("blob is collected once unreferenced for the grace period", func(t *testing.T) {
+		b, refs := setupDedupFileBackend(t)
+
+		_, err := b.WriteFile(bytes.NewReader(content), "a/one.txt")
+		require.NoError(t, err)
+		require.NoError(t, b.CopyFile("a/one.txt", "a/two.txt"))
+
+		require.NoError(t, b.RemoveFile("a/one.txt"))
+		assert.Equal(t, 1, countBlobs(t, b))
+
+		exists, err := b.FileExists("a/one.txt")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		removed, err := b.CollectGarbage(time.Hour)
+		require.NoError(t, err)
+		assert.Zero(t, removed)
+
+		require.NoError(t, b.RemoveFile("a/two.txt"))
+		assert.Equal(t, 1, countBlobs(t, b))
+
+		removed, err = b.CollectGarbage(time.Hour)
+		require.NoError(t, err)
+		assert.Zero(t, removed)
+		assert.Equal(t, 1, countBlobs(t, b))
+
+		refs.age(2 * time.Hour)
+		removed, err = b.CollectGarbage(time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, 1, removed)
+		assert.Equal(t, 0, countBlobs(t, b))
+	})
+
+	t.Run("blob collected while it is written again", func(t *testing.T) {
+		b, refs := setupDedupFileBackend(t)
+
+		_, err := b.WriteFile(bytes.NewReader(content), "a/one.txt")
+		require.NoError(t, err)
+		require.NoError(t, b.RemoveFile("a/one.txt"))
+		refs.age(2 * time.Hour)
+
+		// The blob is still there when the write checks for it, but it is
+		// collected before the write references it.
+		refs.beforeSave = func() {
+			refs.beforeSave = nil
+			removed, err := b.CollectGarbage(time.Hour)
+			require.NoError(t, err)
+			require.Equal(t, 1, removed)
+		}
+		_, err = b.WriteFile(bytes.NewReader(content), "b/two.txt")
+		require.NoError(t, err)
+
+		data, err := b.ReadFile("b/two.txt")
+		require.NoError(t, err)
+		assert.Equal(t, content, data)
+		assert.Equal(t, 1, countBlobs(t, b))
+	})
+
+	t.Run("move and append", func(t *testing.T) {
+		b, _ := setupDedupFileBackend(t)
+
+		_, err := b.WriteFile(bytes.NewReader(content), "a/one.txt")
+		require.NoError(t, err)
+		require.NoError(t, b.CopyFile("a/one.txt", "a/two.txt"))
+		require.NoError(t, b.MoveFile("a/two.txt", "b/moved.txt"))
+
+		written, err := b.AppendFile(bytes.NewReader([]byte("!")), "b/moved.txt")
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, written)
+
+		data, err := b.ReadFile("b/moved.txt")
+		require.NoError(t, err)
+		assert.Equal(t, append(append([]byte{}, content...), '!'), data)
+
+		data, err = b.ReadFile("a/one.txt")
+		require.NoError(t, err)
+		assert.Equal(t, content, data)
+
+		assert.Equal(t, 2, countBlobs(t, b))
+	})
+
+	t.Run("directory listings hide blobs", func(t *testing.T) {
+		b, refs := setupDedupFileBackend(t)
+
+		_, err := b.WriteFile(bytes.NewReader(content), "dir/one.txt")
+		require.NoError(t, err)
+		_, err = b.WriteFile(bytes.NewReader(content), "dir/sub/two.txt")
+		require.NoError(t, err)
+
+		paths, err := b.ListDirectory("")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dir"}, paths)
+
+		paths, err = b.ListDirectory("dir")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dir/one.txt", "dir/sub"}, paths)
+
+		paths, err = b.ListDirectoryRecursively("dir")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dir/one.txt", "dir/sub/two.txt"}, paths)
+
+		require.NoError(t, b.RemoveDirectory("dir"))
+		assert.Empty(t, refs.refs)
+
+		refs.age(2 * time.Hour)
+		_, err = b.CollectGarbage(time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, 0, countBlobs(t, b))
+	})
+
+	t.Run("files written before deduplication", func(t *testing.T) {
+		b, refs := setupDedupFileBackend(t)
+
+		_, err := b.PhysicalBackend().WriteFile(bytes.NewReader(content), "legacy/file.txt")
+		require.NoError(t, err)
+
+		data, err := b.ReadFile("legacy/file.txt")
+		require.NoError(t, err)
+		assert.Equal(t, content, data)
+
+		deduped, err := b.DedupFile("legacy/file.txt")
+		require.NoError(t, err)
+		assert.True(t, deduped)
+		assert.Len(t, refs.refs, 1)
+		assert.Equal(t, 1, countBlobs(t, b))
+
+		exists, err := b.PhysicalBackend().FileExists("legacy/file.txt")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		data, err = b.ReadFile("legacy/file.txt")
+		require.NoError(t, err)
+		assert.Equal(t, content, data)
+
+		deduped, err = b.DedupFile("legacy/file.txt")
+		require.NoError(t, err)
+		assert.False(t, deduped)
+	})
+
+	t.Run("requires a blob reference store", func(t *testing.T) {
+		_, err := NewFileBackend(FileBackendSettings{
+			DriverName:      driverDedup,
+			DedupDriverName: driverLocal,
+			Directory:       t.TempDir(),
+		})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,1011 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// EncryptionKeyDirectory is the directory of the underlying backend that
+	// holds the wrapped data key of every encrypted file. It is hidden from
+	// directory listings.
+	EncryptionKeyDirectory = "encryption_keys"
+
+	encryptionKeySize      = 32
+	encryptionNonceSize    = 12
+	encryptionTagSize      = 16
+	encryptionPartSaltSize = 32
+	encryptedSegmentSize   = 64 * 1024
+	sealedSegmentSize      = encryptedSegmentSize + encryptionTagSize
+	encryptedFileVersion   = 3
+	encryptionKeyIdLength  = 16
+	fileKeyGenerationWidth = 20
+)
+
+type encryptionKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+func newEncryptionKey(key []byte) (*encryptionKey, error) {
+	if len(key) != encryptionKeySize {
+		return nil, errors.Errorf("encryption keys must be %d bytes long", encryptionKeySize)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(key)
+	return &encryptionKey{
+		id:   hex.EncodeToString(sum[:])[:encryptionKeyIdLength],
+		aead: aead,
+	}, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create the block cipher")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create the GCM cipher")
+	}
+	return aead, nil
+}
+
+// EncryptionKeyring holds the master keys used to wrap the per-file data
+// keys. New files are always wrapped with the active key, while retired keys
+// are only used to unwrap the keys of files written before a rotation.
+type EncryptionKeyring struct {
+	active *encryptionKey
+	keys   map[string]*encryptionKey
+}
+
+// NewEncryptionKeyring creates a keyring from base64 encoded AES-256 keys.
+func NewEncryptionKeyring(activeKey string, retiredKeys []string) (*EncryptionKeyring, error) {
+	active, err := parseEncryptionKey(activeKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid active encryption key")
+	}
+
+	keyring := &EncryptionKeyring{
+		active: active,
+		keys:   map[string]*encryptionKey{active.id: active},
+	}
+	for _, retiredKey := range retiredKeys {
+		key, err := parseEncryptionKey(retiredKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid retired encryption key")
+		}
+		if _, ok := keyring.keys[key.id]; !ok {
+			keyring.keys[key.id] = key
+		}
+	}
+	return keyring, nil
+}
+
+// LoadEncryptionKeyring creates a keyring from the configured keys. When
+// keyFile is set, its first key is the active one and any following keys are
+// retired, taking precedence over activeKey. Empty lines and lines starting
+// with # are ignored.
+func LoadEncryptionKeyring(activeKey, keyFile string, retiredKeys []string) (*EncryptionKeyring, error) {
+	if keyFile == "" {
+		return NewEncryptionKeyring(activeKey, retiredKeys)
+	}
+
+	f, err := os.Open(keyFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open the encryption key file %s", keyFile)
+	}
+	defer f.Close()
+
+	var keys []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keys = append(keys, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "unable to read the encryption key file %s", keyFile)
+	}
+	if len(keys) == 0 {
+		return nil, errors.Errorf("the encryption key file %s does not contain any key", keyFile)
+	}
+
+	return NewEncryptionKeyring(keys[0], append(keys[1:], retiredKeys...))
+}
+
+func parseEncryptionKey(encoded string) (*encryptionKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decode the key")
+	}
+	return newEncryptionKey(key)
+}
+
+// ActiveKeyId returns the identifier of the key new data keys are wrapped with.
+func (k *EncryptionKeyring) ActiveKeyId() string {
+	return k.active.id
+}
+
+func (k *EncryptionKeyring) wrap(dataKey []byte) (*encryptedFileKey, error) {
+	nonce := make([]byte, encryptionNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "unable to generate a nonce")
+	}
+	return &encryptedFileKey{
+		Version: encryptedFileVersion,
+		KeyId:   k.active.id,
+		DataKey: k.active.aead.Seal(nonce, nonce, dataKey, []byte(k.active.id)),
+	}, nil
+}
+
+func (k *EncryptionKeyring) unwrap(fileKey *encryptedFileKey) ([]byte, error) {
+	key, ok := k.keys[fileKey.KeyId]
+	if !ok {
+		return nil, errors.Errorf("unknown encryption key %s", fileKey.KeyId)
+	}
+	if len(fileKey.DataKey) < encryptionNonceSize {
+		return nil, errors.New("the wrapped data key is too short")
+	}
+	nonce, sealed := fileKey.DataKey[:encryptionNonceSize], fileKey.DataKey[encryptionNonceSize:]
+	dataKey, err := key.aead.Open(nil, nonce, sealed, []byte(key.id))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to unwrap the data key")
+	}
+	return dataKey, nil
+}
+
+// encryptedFileKey is the metadata stored alongside every encrypted file.
+type encryptedFileKey struct {
+	Version int    `json:"version"`
+	KeyId   string `json:"key_id"`
+	DataKey []byte `json:"data_key"`
+	// Parts holds the plaintext size of every part of the file but the last
+	// one, whose size follows from the size of the file.
+	Parts []int64 `json:"parts,omitempty"`
+	// PartSalts holds the random salt of every part of the file, from which
+	// the key of the part is derived.
+	PartSalts [][]byte `json:"part_salts"`
+
+	// generation is the name the metadata is stored under.
+	generation string
+}
+
+// encryptedPart is one write of an encrypted file: the initial contents or
+// an append. Its segments are numbered after the ones of the previous parts,
+// and its last segment is sealed with a flag that detects truncation.
+type encryptedPart struct {
+	salt         []byte
+	aead         cipher.AEAD
+	offset       int64
+	size         int64
+	cipherOffset int64
+	cipherSize   int64
+	firstSegment int64
+	segments     int64
+}
+
+// sealedPartSize returns the size an encrypted part of size bytes takes in
+// the underlying backend. Even an empty part has a final segment.
+func sealedPartSize(size int64) (cipherSize, segments int64) {
+	segments = max(1, (size+encryptedSegmentSize-1)/encryptedSegmentSize)
+	return size + segments*encryptionTagSize, segments
+}
+
+// parts returns the layout of an encrypted file of cipherSize bytes.
+func (k *encryptedFileKey) parts(cipherSize int64) ([]encryptedPart, error) {
+	if len(k.PartSalts) != len(k.Parts)+1 {
+		return nil, errors.New("the data key does not describe every part of the file")
+	}
+
+	parts := make([]encryptedPart, 0, len(k.Parts)+1)
+	var offset, cipherOffset, segment int64
+	for i, size := range k.Parts {
+		partCipherSize, segments := sealedPartSize(size)
+		parts = append(parts, encryptedPart{
+			salt:         k.PartSalts[i],
+			offset:       offset,
+			size:         size,
+			cipherOffset: cipherOffset,
+			cipherSize:   partCipherSize,
+			firstSegment: segment,
+			segments:     segments,
+		})
+		offset += size
+		cipherOffset += partCipherSize
+		segment += segments
+	}
+
+	rest := cipherSize - cipherOffset
+	if rest < encryptionTagSize || (rest%sealedSegmentSize != 0 && rest%sealedSegmentSize < encryptionTagSize) {
+		return nil, errors.New("the encrypted file is truncated")
+	}
+	segments := (rest + sealedSegmentSize - 1) / sealedSegmentSize
+	return append(parts, encryptedPart{
+		salt:         k.PartSalts[len(k.Parts)],
+		offset:       offset,
+		size:         rest - segments*encryptionTagSize,
+		cipherOffset: cipherOffset,
+		cipherSize:   rest,
+		firstSegment: segment,
+		segments:     segments,
+	}), nil
+}
+
+// EncryptedFileBackend encrypts file contents at rest on top of another
+// FileBackend. Every file has its own random data key. Every write of the
+// file, the initial contents or an append, is a part encrypted with
+// AES-256-GCM under a key derived from the data key and a random salt of the
+// part, so that no two writes share a key even when a copy of the file is
+// appended to along with the original. Parts are sealed in independent
+// segments so that reads can seek, following the STREAM construction:
+// segment nonces hold the index of the segment and a flag marking the last
+// one of the part, so that reordered or truncated segments are detected.
+//
+// The data key, wrapped by the active master key, is stored in
+// EncryptionKeyDirectory under the path of the file, as one version per
+// write. A new version is written before the contents it applies to and the
+// previous ones are only removed once the contents are written, so that a
+// failed write never leaves a file without its key. Keys can be rotated
+// without rewriting the file contents.
+//
+// Files written before encryption was enabled have no data key and are
+// served as they are.
+type EncryptedFileBackend struct {
+	physical FileBackend
+	keyring  *EncryptionKeyring
+}
+
+func NewEncryptedFileBackend(physical FileBackend, keyring *EncryptionKeyring) *EncryptedFileBackend {
+	return &EncryptedFileBackend{
+		physical: physical,
+		keyring:  keyring,
+	}
+}
+
+// newEncryptedFileBackendFromSettings wraps backend if encryption is enabled
+// in settings.
+func newEncryptedFileBackendFromSettings(backend FileBackend, settings FileBackendSettings) (FileBackend, error) {
+	if !settings.EncryptionEnabled {
+		return backend, nil
+	}
+	keyring, err := LoadEncryptionKeyring(settings.EncryptionKey, settings.EncryptionKeyFile, settings.EncryptionRetiredKeys)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load the file encryption keys")
+	}
+	return NewEncryptedFileBackend(backend, keyring), nil
+}
+
+// FindEncryptedFileBackends returns the encrypting backends among fb and the
+// backends it wraps, if any. A tiered backend has one for each tier.
+func FindEncryptedFileBackends(fb FileBackend) []*EncryptedFileBackend {
+	switch b := fb.(type) {
+	case *EncryptedFileBackend:
+		return []*EncryptedFileBackend{b}
+	case *DedupFileBackend:
+		return FindEncryptedFileBackends(b.PhysicalBackend())
+	case *TieredFileBackend:
+		return append(FindEncryptedFileBackends(b.HotBackend()), FindEncryptedFileBackends(b.ColdBackend())...)
+	}
+	return nil
+}
+
+// PhysicalBackend returns the backend that holds the encrypted contents.
+func (b *EncryptedFileBackend) PhysicalBackend() FileBackend {
+	return b.physical
+}
+
+// fileKeyDirectory returns the directory holding the versions of the data
+// key of p.
+func fileKeyDirectory(p string) string {
+	return path.Join(EncryptionKeyDirectory, p)
+}
+
+// fileKeyPath returns the path of a version of the data key of p.
+func fileKeyPath(p, generation string) string {
+	return path.Join(EncryptionKeyDirectory, p, generation)
+}
+
+func isEncryptionKeyPath(p string) bool {
+	return p == EncryptionKeyDirectory || strings.HasPrefix(p, EncryptionKeyDirectory+"/")
+}
+
+// isFileKeyGeneration returns whether name is the name of a version of a
+// data key, as opposed to the directory of the keys of another file.
+func isFileKeyGeneration(name string) bool {
+	if len(name) != fileKeyGenerationWidth {
+		return false
+	}
+	_, err := strconv.ParseUint(name, 10, 64)
+	return err == nil
+}
+
+// nextFileKeyGeneration returns a version name that sorts after current.
+func nextFileKeyGeneration(current string) string {
+	next := uint64(time.Now().UnixNano())
+	if n, err := strconv.ParseUint(current, 10, 64); err == nil && n >= next {
+		next = n + 1
+	}
+	return fmt.Sprintf("%0*d", fileKeyGenerationWidth, next)
+}
+
+// newPartSalt returns the random salt of a new part.
+func newPartSalt() ([]byte, error) {
+	salt := make([]byte, encryptionPartSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, errors.Wrap(err, "unable to generate a salt")
+	}
+	return salt, nil
+}
+
+// partAEAD returns the cipher of the part with the given salt, keyed with
+// HMAC-SHA256(dataKey, salt).
+func partAEAD(dataKey, salt []byte) (cipher.AEAD, error) {
+	mac := hmac.New(sha256.New, dataKey)
+	mac.Write(salt)
+	return newAEAD(mac.Sum(nil))
+}
+
+// segmentNonce returns the nonce of the segment with the given index, which
+// is flagged if it is the last segment of its part.
+func segmentNonce(index int64, last bool) []byte {
+	nonce := make([]byte, encryptionNonceSize)
+	binary.BigEndian.PutUint64(nonce[encryptionNonceSize-9:encryptionNonceSize-1], uint64(index))
+	if last {
+		nonce[encryptionNonceSize-1] = 1
+	}
+	return nonce
+}
+
+// listFileKeys returns the versions of the data key of path, oldest first.
+func (b *EncryptedFileBackend) listFileKeys(path string) ([]string, error) {
+	keyPaths, err := b.physical.ListDirectory(fileKeyDirectory(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the data keys of %s", path)
+	}
+	generations := make([]string, 0, len(keyPaths))
+	for _, keyPath := range keyPaths {
+		if name := filepath.Base(keyPath); isFileKeyGeneration(name) {
+			generations = append(generations, name)
+		}
+	}
+	sort.Strings(generations)
+	return generations, nil
+}
+
+// readFileKey returns the latest version of the wrapped data key of path, or
+// nil if it is not encrypted.
+func (b *EncryptedFileBackend) readFileKey(path string) (*encryptedFileKey, error) {
+	generations, err := b.listFileKeys(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(generations) == 0 {
+		return nil, nil
+	}
+	generation := generations[len(generations)-1]
+
+	data, err := b.physical.ReadFile(fileKeyPath(path, generation))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the data key of %s", path)
+	}
+	var fileKey encryptedFileKey
+	if err := json.Unmarshal(data, &fileKey); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the data key of %s", path)
+	}
+	if fileKey.Version != encryptedFileVersion {
+		return nil, errors.Errorf("unsupported encryption version %d for %s", fileKey.Version, path)
+	}
+	fileKey.generation = generation
+	return &fileKey, nil
+}
+
+// loadFileKey returns the data key of path, or nil if it is not encrypted.
+func (b *EncryptedFileBackend) loadFileKey(path string) (*encryptedFileKey, []byte, error) {
+	fileKey, err := b.readFileKey(path)
+	if err != nil || fileKey == nil {
+		return nil, nil, err
+	}
+	dataKey, err := b.keyring.unwrap(fileKey)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to decrypt the data key of %s", path)
+	}
+	return fileKey, dataKey, nil
+}
+
+// saveFileKey stores fileKey as a new version of the data key of path, which
+// takes precedence over the version named after.
+func (b *EncryptedFileBackend) saveFileKey(path string, fileKey *encryptedFileKey, after string) error {
+	data, err := json.Marshal(fileKey)
+	if err != nil {
+		return errors.Wrapf(err, "unable to serialize the data key of %s", path)
+	}
+	generation := nextFileKeyGeneration(after)
+	if _, err := b.physical.WriteFile(bytes.NewReader(data), fileKeyPath(path, generation)); err != nil {
+		return errors.Wrapf(err, "unable to write the data key of %s", path)
+	}
+	fileKey.generation = generation
+	return nil
+}
+
+// removeFileKey removes a version of the data key of path that turned out
+// not to apply to the file contents.
+func (b *EncryptedFileBackend) removeFileKey(path string, fileKey *encryptedFileKey) {
+	if err := b.physical.RemoveFile(fileKeyPath(path, fileKey.generation)); err != nil {
+		mlog.Warn("Unable to remove an unused data key", mlog.String("path", path), mlog.Err(err))
+	}
+}
+
+// removeOldFileKeys removes the versions of the data key of path that
+// precede fileKey. Leftover versions don't prevent reading the file.
+func (b *EncryptedFileBackend) removeOldFileKeys(path string, fileKey *encryptedFileKey) {
+	generations, err := b.listFileKeys(path)
+	if err == nil {
+		for _, generation := range generations {
+			if generation >= fileKey.generation {
+				break
+			}
+			if err = b.physical.RemoveFile(fileKeyPath(path, generation)); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		mlog.Warn("Unable to remove the previous data keys", mlog.String("path", path), mlog.Err(err))
+	}
+}
+
+// removeFileKeys removes every version of the data key of path.
+func (b *EncryptedFileBackend) removeFileKeys(path string) error {
+	if err := b.physical.RemoveDirectory(fileKeyDirectory(path)); err != nil {
+		return errors.Wrapf(err, "unable to remove the data keys of %s", path)
+	}
+	return nil
+}
+
+func (b *EncryptedFileBackend) DriverName() string {
+	return b.physical.DriverName()
+}
+
+func (b *EncryptedFileBackend) TestConnection() error {
+	return b.physical.TestConnection()
+}
+
+func (b *EncryptedFileBackend) Reader(path string) (ReadCloseSeeker, error) {
+	fileKey, dataKey, err := b.loadFileKey(path)
+	if err != nil {
+		return nil, err
+	}
+	if dataKey == nil {
+		return b.physical.Reader(path)
+	}
+
+	parts, err := b.fileParts(path, fileKey)
+	if err != nil {
+		return nil, err
+	}
+	for i := range parts {
+		if parts[i].aead, err = partAEAD(dataKey, parts[i].salt); err != nil {
+			return nil, err
+		}
+	}
+	r, err := b.physical.Reader(path)
+	if err != nil {
+		return nil, err
+	}
+	return newDecryptingReader(r, parts), nil
+}
+
+func (b *EncryptedFileBackend) fileParts(path string, fileKey *encryptedFileKey) ([]encryptedPart, error) {
+	cipherSize, err := b.physical.FileSize(path)
+	if err != nil {
+		return nil, err
+	}
+	parts, err := fileKey.parts(cipherSize)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the file %s", path)
+	}
+	return parts, nil
+}
+
+func (b *EncryptedFileBackend) ReadFile(path string) ([]byte, error) {
+	r, err := b.Reader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read file %s", path)
+	}
+	return data, nil
+}
+
+func (b *EncryptedFileBackend) FileExists(path string) (bool, error) {
+	return b.physical.FileExists(path)
+}
+
+func (b *EncryptedFileBackend) FileSize(path string) (int64, error) {
+	fileKey, err := b.readFileKey(path)
+	if err != nil {
+		return 0, err
+	}
+	if fileKey == nil {
+		return b.physical.FileSize(path)
+	}
+	parts, err := b.fileParts(path, fileKey)
+	if err != nil {
+		return 0, err
+	}
+	last := parts[len(parts)-1]
+	return last.offset + last.size, nil
+}
+
+func (b *EncryptedFileBackend) FileModTime(path string) (time.Time, error) {
+	return b.physical.FileModTime(path)
+}
+
+func (b *EncryptedFileBackend) CopyFile(oldPath, newPath string) error {
+	return b.transferFile(oldPath, newPath, b.physical.CopyFile)
+}
+
+func (b *EncryptedFileBackend) MoveFile(oldPath, newPath string) error {
+	if err := b.transferFile(oldPath, newPath, b.physical.MoveFile); err != nil {
+		return err
+	}
+	return b.removeFileKeys(oldPath)
+}
+
+// transferFile copies or moves the contents of oldPath to newPath with
+// transfer, giving newPath the data key of oldPath. A copy can be appended
+// to along with the original since every append has its own salt.
+func (b *EncryptedFileBackend) transferFile(oldPath, newPath string, transfer func(string, string) error) error {
+	fileKey, err := b.readFileKey(oldPath)
+	if err != nil {
+		return err
+	}
+	if fileKey == nil {
+		if err := transfer(oldPath, newPath); err != nil {
+			return err
+		}
+		return b.removeFileKeys(newPath)
+	}
+
+	current, err := b.readFileKey(newPath)
+	if err != nil {
+		return err
+	}
+	var after string
+	if current != nil {
+		after = current.generation
+	}
+	if err := b.saveFileKey(newPath, fileKey, after); err != nil {
+		return err
+	}
+	if err := transfer(oldPath, newPath); err != nil {
+		b.removeFileKey(newPath, fileKey)
+		return err
+	}
+	b.removeOldFileKeys(newPath, fileKey)
+	return nil
+}
+
+func (b *EncryptedFileBackend) WriteFile(fr io.Reader, path string) (int64, error) {
+	return b.writeFile(fr, path, b.physical.WriteFile)
+}
+
+// WriteFileContext passes ctx to the underlying backend when it supports
+// context writes.
+func (b *EncryptedFileBackend) WriteFileContext(ctx context.Context, fr io.Reader, path string) (int64, error) {
+	return b.writeFile(fr, path, func(r io.Reader, p string) (int64, error) {
+		return TryWriteFileContext(ctx, b.physical, r, p)
+	})
+}
+
+func (b *EncryptedFileBackend) writeFile(fr io.Reader, path string, write func(io.Reader, string) (int64, error)) (int64, error) {
+	current, err := b.readFileKey(path)
+	if err != nil {
+		return 0, err
+	}
+	var after string
+	if current != nil {
+		after = current.generation
+	}
+
+	dataKey, err := newDataKey()
+	if err != nil {
+		return 0, err
+	}
+	salt, err := newPartSalt()
+	if err != nil {
+		return 0, err
+	}
+	aead, err := partAEAD(dataKey, salt)
+	if err != nil {
+		return 0, err
+	}
+	fileKey, err := b.keyring.wrap(dataKey)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to encrypt the data key of %s", path)
+	}
+	fileKey.PartSalts = [][]byte{salt}
+	if err := b.saveFileKey(path, fileKey, after); err != nil {
+		return 0, err
+	}
+
+	enc := newEncryptingReader(fr, aead, 0)
+	if _, err := write(enc, path); err != nil {
+		b.removeFileKey(path, fileKey)
+		return enc.written, err
+	}
+	b.removeOldFileKeys(path, fileKey)
+	return enc.written, nil
+}
+
+func newDataKey() ([]byte, error) {
+	dataKey := make([]byte, encryptionKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, errors.Wrap(err, "unable to generate a data key")
+	}
+	return dataKey, nil
+}
+
+// AppendFile encrypts fr as a new part of the file, with a salt of its own,
+// whose segments follow the ones already written.
+func (b *EncryptedFileBackend) AppendFile(fr io.Reader, path string) (int64, error) {
+	fileKey, dataKey, err := b.loadFileKey(path)
+	if err != nil {
+		return 0, err
+	}
+	if dataKey == nil {
+		return b.physical.AppendFile(fr, path)
+	}
+
+	parts, err := b.fileParts(path, fileKey)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to find the file %s to append the data", path)
+	}
+	last := parts[len(parts)-1]
+	salt, err := newPartSalt()
+	if err != nil {
+		return 0, err
+	}
+	aead, err := partAEAD(dataKey, salt)
+	if err != nil {
+		return 0, err
+	}
+
+	// The size of the current last part is recorded before appending, since
+	// it can't be told from the size of the file once the new part follows.
+	// A failed append leaves the salt of the new part unused.
+	appended := *fileKey
+	appended.Parts = append(slices.Clone(fileKey.Parts), last.size)
+	appended.PartSalts = append(slices.Clone(fileKey.PartSalts), salt)
+	if err := b.saveFileKey(path, &appended, fileKey.generation); err != nil {
+		return 0, err
+	}
+
+	enc := newEncryptingReader(fr, aead, last.firstSegment+last.segments)
+	if _, err := b.physical.AppendFile(enc, path); err != nil {
+		b.removeFileKey(path, &appended)
+		return enc.written, err
+	}
+	b.removeOldFileKeys(path, &appended)
+	return enc.written, nil
+}
+
+func (b *EncryptedFileBackend) RemoveFile(path string) error {
+	if err := b.physical.RemoveFile(path); err != nil {
+		return err
+	}
+	return b.removeFileKeys(path)
+}
+
+func (b *EncryptedFileBackend) ListDirectory(path string) ([]string, error) {
+	paths, err := b.physical.ListDirectory(path)
+	if err != nil {
+		return nil, err
+	}
+	return withoutEncryptionKeys(paths), nil
+}
+
+func (b *EncryptedFileBackend) ListDirectoryRecursively(path string) ([]string, error) {
+	paths, err := b.physical.ListDirectoryRecursively(path)
+	if err != nil {
+		return nil, err
+	}
+	return withoutEncryptionKeys(paths), nil
+}
+
+func withoutEncryptionKeys(paths []string) []string {
+	results := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if !isEncryptionKeyPath(p) {
+			results = append(results, p)
+		}
+	}
+	return results
+}
+
+func (b *EncryptedFileBackend) RemoveDirectory(path string) error {
+	if err := b.physical.RemoveDirectory(path); err != nil {
+		return err
+	}
+	if dirPrefix(path) == "" {
+		return nil
+	}
+	return b.physical.RemoveDirectory(fileKeyDirectory(path))
+}
+
+// ZipReader will create a zip of path. If path is a single file, it will zip the single file.
+// If deflate is true, the contents will be compressed. It will stream the zip to io.ReadCloser.
+func (b *EncryptedFileBackend) ZipReader(path string, deflate bool) (io.ReadCloser, error) {
+	files, err := b.ListDirectoryRecursively(path)
+	if err != nil || len(files) == 0 {
+		// path is a single file, or does not exist.
+		generations, err := b.listFileKeys(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(generations) == 0 {
+			return b.physical.ZipReader(path, deflate)
+		}
+		return zipFiles(b, []string{path}, filepath.Dir(path), deflate), nil
+	}
+
+	keys, err := b.physical.ListDirectoryRecursively(fileKeyDirectory(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the data keys under %s", path)
+	}
+	if len(keys) == 0 {
+		return b.physical.ZipReader(path, deflate)
+	}
+	return zipFiles(b, files, path, deflate), nil
+}
+
+// ListEncryptedFiles returns the paths of every file that has a data key.
+func (b *EncryptedFileBackend) ListEncryptedFiles() ([]string, error) {
+	keyPaths, err := b.physical.ListDirectoryRecursively(EncryptionKeyDirectory)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list the data keys")
+	}
+	paths := make([]string, 0, len(keyPaths))
+	seen := make(map[string]bool, len(keyPaths))
+	for _, keyPath := range keyPaths {
+		if !isFileKeyGeneration(filepath.Base(keyPath)) {
+			continue
+		}
+		p := strings.TrimPrefix(filepath.Dir(keyPath), EncryptionKeyDirectory+"/")
+		if !seen[p] {
+			seen[p] = true
+			paths = append(paths, p)
+		}
+	}
+	return paths, nil
+}
+
+// RewrapFileKey wraps the data key of path with the active master key,
+// leaving the file contents untouched. It returns false if the data key
+// already uses the active master key.
+func (b *EncryptedFileBackend) RewrapFileKey(path string) (bool, error) {
+	fileKey, dataKey, err := b.loadFileKey(path)
+	if err != nil {
+		return false, err
+	}
+	if fileKey == nil {
+		return false, errors.Errorf("the file %s is not encrypted", path)
+	}
+	if fileKey.KeyId == b.keyring.ActiveKeyId() {
+		return false, nil
+	}
+
+	rewrapped, err := b.keyring.wrap(dataKey)
+	if err != nil {
+		return false, errors.Wrapf(err, "unable to encrypt the data key of %s", path)
+	}
+	rewrapped.Parts = fileKey.Parts
+	rewrapped.PartSalts = fileKey.PartSalts
+	if err := b.saveFileKey(path, rewrapped, fileKey.generation); err != nil {
+		return false, err
+	}
+	b.removeOldFileKeys(path, rewrapped)
+	return true, nil
+}
+
+// encryptingReader seals the contents of src as one part, in segments of
+// encryptedSegmentSize bytes numbered from the given segment index.
+type encryptingReader struct {
+	src     *bufio.Reader
+	aead    cipher.AEAD
+	index   int64
+	buf     []byte
+	sealed  []byte
+	pending []byte
+	done    bool
+	written int64
+}
+
+func newEncryptingReader(src io.Reader, aead cipher.AEAD, firstSegment int64) *encryptingReader {
+	return &encryptingReader{
+		src:    bufio.NewReader(src),
+		aead:   aead,
+		index:  firstSegment,
+		buf:    make([]byte, encryptedSegmentSize),
+		sealed: make([]byte, 0, sealedSegmentSize),
+	}
+}
+
+func (r *encryptingReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		// A segment is the last one if the contents end with it, which for a
+		// full segment is only known by looking ahead.
+		n, err := io.ReadFull(r.src, r.buf)
+		last := false
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			last = true
+		} else if err != nil {
+			return 0, err
+		} else if _, err := r.src.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return 0, err
+		}
+
+		r.written += int64(n)
+		r.sealed = r.aead.Seal(r.sealed[:0], segmentNonce(r.index, last), r.buf[:n], nil)
+		r.pending = r.sealed
+		r.index++
+		r.done = last
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// decryptingReader opens the segments of an encrypted file as they are read,
+// seeking in the underlying file to serve range requests.
+type decryptingReader struct {
+	src       ReadCloseSeeker
+	parts     []encryptedPart
+	size      int64
+	offset    int64
+	srcOffset int64
+	index     int64
+	segment   []byte
+	buf       []byte
+	// endVerified is set once the last segment of the file is authenticated.
+	endVerified bool
+}
+
+func newDecryptingReader(src ReadCloseSeeker, parts []encryptedPart) *decryptingReader {
+	last := parts[len(parts)-1]
+	return &decryptingReader{
+		src:   src,
+		parts: parts,
+		size:  last.offset + last.size,
+		index: -1,
+		buf:   make([]byte, sealedSegmentSize),
+	}
+}
+
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		// The end of the contents is only trusted once the segment flagged
+		// as the last one has been opened.
+		if !r.endVerified {
+			last := r.parts[len(r.parts)-1]
+			if err := r.loadSegment(last, last.segments-1); err != nil {
+				return 0, err
+			}
+		}
+		return 0, io.EOF
+	}
+
+	// Parts are sorted by offset, and empty parts never hold the offset.
+	i := sort.Search(len(r.parts), func(i int) bool {
+		return r.parts[i].offset+r.parts[i].size > r.offset
+	})
+	part := r.parts[i]
+	segment := (r.offset - part.offset) / encryptedSegmentSize
+	if part.firstSegment+segment != r.index {
+		if err := r.loadSegment(part, segment); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.segment[r.offset-part.offset-segment*encryptedSegmentSize:])
+	r.offset += int64(n)
+	return n, nil
+}
+
+func (r *decryptingReader) loadSegment(part encryptedPart, segment int64) error {
+	start := part.cipherOffset + segment*sealedSegmentSize
+	if start != r.srcOffset {
+		if _, err := r.src.Seek(start, io.SeekStart); err != nil {
+			return errors.Wrap(err, "unable to seek in the encrypted file")
+		}
+		r.srcOffset = start
+	}
+
+	length := min(int64(sealedSegmentSize), part.cipherOffset+part.cipherSize-start)
+	n, err := io.ReadFull(r.src, r.buf[:length])
+	r.srcOffset += int64(n)
+	if err != nil {
+		return errors.Wrap(err, "unable to read the encrypted file")
+	}
+
+	last := segment == part.segments-1
+	index := part.firstSegment + segment
+	opened, err := part.aead.Open(r.segment[:0], segmentNonce(index, last), r.buf[:length], nil)
+	if err != nil {
+		r.index = -1
+		return errors.Wrap(err, "unable to decrypt the file")
+	}
+	r.segment = opened
+	r.index = index
+	if last && part.firstSegment == r.parts[len(r.parts)-1].firstSegment {
+		r.endVerified = true
+	}
+	return nil
+}
+
+func (r *decryptingReader) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = r.offset + offset
+	case io.SeekEnd:
+		abs = r.size + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if abs < 0 {
+		return 0, errors.New("negative position")
+	}
+	r.offset = abs
+	return abs, nil
+}
+
+func (r *decryptingReader) Close() error {
+	return r.src.Close()
+}
@@ -0,0 +1,413 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEncryptionKey(t *testing.T) string {
+	key := make([]byte, encryptionKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func setupEncryptedFileBackend(t *testing.T, dir, activeKey string, retiredKeys ...string) *EncryptedFileBackend {
+	backend, err := NewFileBackend(FileBackendSettings{
+		DriverName:            driverLocal,
+		Directory:             dir,
+		EncryptionEnabled:     true,
+		EncryptionKey:         activeKey,
+		EncryptionRetiredKeys: retiredKeys,
+	})
+	require.NoError(t, err)
+
+	encryptedBackend, ok := backend.(*EncryptedFileBackend)
+	require.True(t, ok)
+	return encryptedBackend
+}
+
+func randomBytes(t *testing.T, n int) []byte {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	require.NoError(t, err)
+	return b
+}
+
+// failingWriteBackend fails to write file contents, leaving the files as they
+// are, while it writes data keys.
+type failingWriteBackend struct {
+	FileBackend
+}
+
+func (b *failingWriteBackend) WriteFile(fr io.Reader, path string) (int64, error) {
+	if isEncryptionKeyPath(path) {
+		return b.FileBackend.WriteFile(fr, path)
+	}
+	return 0, errors.New("write failure")
+}
+
+func (b *failingWriteBackend) AppendFile(fr io.Reader, path string) (int64, error) {
+	return 0, errors.New("write failure")
+}
+
+func (b *failingWriteBackend) CopyFile(oldPath, newPath string) error {
+	return errors.New("write failure")
+}
+
+func TestEncryptedFileBackend(t *testing.T) {
+	t.Run("contents are encrypted at rest", func(t *testing.T) {
+		dir := t.TempDir()
+		b := setupEncryptedFileBackend(t, dir, newTestEncryptionKey(t))
+		content := []byte("this is a secret attachment")
+
+		written, err := b.WriteFile(bytes.NewReader(content), "data/secret.txt")
+		require.NoError(t, err)
+		assert.EqualValues(t, len(content), written)
+
+		raw, err := os.ReadFile(filepath.Join(dir, "data/secret.txt"))
+		require.NoError(t, err)
+		assert.NotContains(t, string(raw), "secret")
+
+		data, err := b.ReadFile("data/secret.txt")
+		require.NoError(t, err)
+		assert.Equal(t, content, data)
+
+		size, err := b.FileSize("data/secret.txt")
+		require.NoError(t, err)
+		assert.EqualValues(t, len(content), size)
+	})
+
+	t.Run("seekable reads across segments", func(t *testing.T) {
+		b := setupEncryptedFileBackend(t, t.TempDir(), newTestEncryptionKey(t))
+		content := randomBytes(t, 3*encryptedSegmentSize+123)
+
+		_, err := b.WriteFile(bytes.NewReader(content), "data/large.bin")
+		require.NoError(t, err)
+
+		r, err := b.Reader("data/large.bin")
+		require.NoError(t, err)
+		defer r.Close()
+
+		for _, offset := range []int64{encryptedSegmentSize - 10, 5, 2*encryptedSegmentSize + 7, int64(len(content)) - 50} {
+			_, err = r.Seek(offset, io.SeekStart)
+			require.NoError(t, err)
+			buf := make([]byte, 40)
+			_, err = io.ReadFull(r, buf)
+			require.NoError(t, err)
+			assert.Equal(t, content[offset:offset+40], buf)
+		}
+
+		end, err := r.Seek(0, io.SeekEnd)
+		require.NoError(t, err)
+		assert.EqualValues(t, len(content), end)
+
+		_, err = r.Seek(0, io.SeekStart)
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, content, data)
+	})
+
+	t.Run("append", func(t *testing.T) {
+		dir := t.TempDir()
+		b := setupEncryptedFileBackend(t, dir, newTestEncryptionKey(t))
+
+		content := randomBytes(t, encryptedSegmentSize+10)
+		_, err := b.WriteFile(bytes.NewReader(content), "data/appended.bin")
+		require.NoError(t, err)
+
+		for _, n := range []int{100, encryptedSegmentSize, 0, 3} {
+			body, err := os.ReadFile(filepath.Join(dir, "data/appended.bin"))
+			require.NoError(t, err)
+
+			extra := randomBytes(t, n)
+			written, err := b.AppendFile(bytes.NewReader(extra), "data/appended.bin")
+			require.NoError(t, err)
+			assert.EqualValues(t, n, written)
+			content = append(content, extra...)
+
+			// Appending only adds segments after the existing ones.
+			appendedBody, err := os.ReadFile(filepath.Join(dir, "data/appended.bin"))
+			require.NoError(t, err)
+			assert.Equal(t, body, appendedBody[:len(body)])
+
+			data, err := b.ReadFile("data/appended.bin")
+			require.NoError(t, err)
+			assert.Equal(t, content, data)
+
+			size, err := b.FileSize("data/appended.bin")
+			require.NoError(t, err)
+			assert.EqualValues(t, len(content), size)
+		}
+
+		r, err := b.Reader("data/appended.bin")
+		require.NoError(t, err)
+		defer r.Close()
+		offset := int64(encryptedSegmentSize + 50)
+		_, err = r.Seek(offset, io.SeekStart)
+		require.NoError(t, err)
+		buf := make([]byte, 100)
+		_, err = io.ReadFull(r, buf)
+		require.NoError(t, err)
+		assert.Equal(t, content[offset:offset+100], buf)
+
+		generations, err := b.listFileKeys("data/appended.bin")
+		require.NoError(t, err)
+		assert.Len(t, generations, 1)
+	})
+
+	t.Run("truncation is detected", func(t *testing.T) {
+		dir := t.TempDir()
+		b := setupEncryptedFileBackend(t, dir, newTestEncryptionKey(t))
+
+		_, err := b.WriteFile(bytes.NewReader(randomBytes(t, 2*encryptedSegmentSize+10)), "data/file.bin")
+		require.NoError(t, err)
+		_, err = b.WriteFile(bytes.NewReader(randomBytes(t, encryptedSegmentSize)), "data/appended.bin")
+		require.NoError(t, err)
+		_, err = b.AppendFile(bytes.NewReader(randomBytes(t, encryptedSegmentSize)), "data/appended.bin")
+		require.NoError(t, err)
+		_, err = b.WriteFile(bytes.NewReader(nil), "data/empty.bin")
+		require.NoError(t, err)
+
+		for name, size := range map[string]int64{
+			"data/file.bin":     2 * sealedSegmentSize,
+			"data/appended.bin": sealedSegmentSize,
+			"data/empty.bin":    0,
+		} {
+			require.NoError(t, os.Truncate(filepath.Join(dir, name), size))
+			_, err := b.ReadFile(name)
+			assert.Error(t, err, name)
+		}
+	})
+
+	t.Run("failed writes keep the previous contents", func(t *testing.T) {
+		b := setupEncryptedFileBackend(t, t.TempDir(), newTestEncryptionKey(t))
+		content := []byte("previous contents")
+
+		_, err := b.WriteFile(bytes.NewReader(content), "data/file.txt")
+		require.NoError(t, err)
+
+		failing := NewEncryptedFileBackend(&failingWriteBackend{FileBackend: b.PhysicalBackend()}, b.keyring)
+		_, err = failing.WriteFile(bytes.NewReader([]byte("new contents")), "data/file.txt")
+		require.Error(t, err)
+		_, err = failing.AppendFile(bytes.NewReader([]byte("more contents")), "data/file.txt")
+		require.Error(t, err)
+		require.Error(t, failing.CopyFile("data/file.txt", "data/file.txt"))
+
+		data, err := b.ReadFile("data/file.txt")
+		require.NoError(t, err)
+		assert.Equal(t, content, data)
+
+		generations, err := b.listFileKeys("data/file.txt")
+		require.NoError(t, err)
+		assert.Len(t, generations, 1)
+	})
+
+	t.Run("copy, move and remove carry the data key", func(t *testing.T) {
+		dir := t.TempDir()
+		b := setupEncryptedFileBackend(t, dir, newTestEncryptionKey(t))
+		content := []byte("copy me")
+
+		_, err := b.WriteFile(bytes.NewReader(content), "a/file.txt")
+		require.NoError(t, err)
+		require.NoError(t, b.CopyFile("a/file.txt", "b/copy.txt"))
+		require.NoError(t, b.MoveFile("a/file.txt", "c/moved.txt"))
+
+		for _, p := range []string{"b/copy.txt", "c/moved.txt"} {
+			data, err := b.ReadFile(p)
+			require.NoError(t, err)
+			assert.Equal(t, content, data)
+		}
+
+		_, err = os.Stat(filepath.Join(dir, EncryptionKeyDirectory, "a/file.txt"))
+		assert.True(t, os.IsNotExist(err))
+
+		require.NoError(t, b.RemoveFile("b/copy.txt"))
+		_, err = os.Stat(filepath.Join(dir, EncryptionKeyDirectory, "b/copy.txt"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("appends to a copy and its original use different part keys", func(t *testing.T) {
+		dir := t.TempDir()
+		b := setupEncryptedFileBackend(t, dir, newTestEncryptionKey(t))
+		content := randomBytes(t, 100)
+
+		_, err := b.WriteFile(bytes.NewReader(content), "a/file.bin")
+		require.NoError(t, err)
+		require.NoError(t, b.CopyFile("a/file.bin", "b/copy.bin"))
+
+		extra := map[string][]byte{
+			"a/file.bin": randomBytes(t, 50),
+			"b/copy.bin": randomBytes(t, 50),
+		}
+		for p, e := range extra {
+			_, err = b.AppendFile(bytes.NewReader(e), p)
+			require.NoError(t, err)
+		}
+
+		var salts [][]byte
+		for p, e := range extra {
+			data, err := b.ReadFile(p)
+			require.NoError(t, err)
+			assert.Equal(t, append(slices.Clone(content), e...), data)
+
+			fileKey, err := b.readFileKey(p)
+			require.NoError(t, err)
+			require.Len(t, fileKey.PartSalts, 2)
+			salts = append(salts, fileKey.PartSalts[1])
+		}
+		assert.NotEqual(t, salts[0], salts[1])
+
+		// The segments appended at the same offset are sealed under
+		// different keys.
+		original, err := os.ReadFile(filepath.Join(dir, "a/file.bin"))
+		require.NoError(t, err)
+		copied, err := os.ReadFile(filepath.Join(dir, "b/copy.bin"))
+		require.NoError(t, err)
+		require.Equal(t, len(original), len(copied))
+		assert.NotEqual(t, original[len(original)-encryptionTagSize:], copied[len(copied)-encryptionTagSize:])
+	})
+
+	t.Run("listings hide data keys", func(t *testing.T) {
+		b := setupEncryptedFileBackend(t, t.TempDir(), newTestEncryptionKey(t))
+
+		_, err := b.WriteFile(bytes.NewReader([]byte("1")), "dir/one.txt")
+		require.NoError(t, err)
+
+		paths, err := b.ListDirectory("")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dir"}, paths)
+
+		paths, err = b.ListDirectoryRecursively("")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dir/one.txt"}, paths)
+	})
+
+	t.Run("zip reader decrypts", func(t *testing.T) {
+		b := setupEncryptedFileBackend(t, t.TempDir(), newTestEncryptionKey(t))
+
+		_, err := b.WriteFile(bytes.NewReader([]byte("one")), "dir/one.txt")
+		require.NoError(t, err)
+		_, err = b.WriteFile(bytes.NewReader([]byte("two")), "dir/sub/two.txt")
+		require.NoError(t, err)
+
+		r, err := b.ZipReader("dir", false)
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		contents := map[string]string{}
+		for _, f := range zr.File {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			rc.Close()
+			contents[f.Name] = string(content)
+		}
+		assert.Equal(t, map[string]string{"one.txt": "one", "sub/two.txt": "two"}, contents)
+	})
+
+	t.Run("files written before encryption", func(t *testing.T) {
+		dir := t.TempDir()
+		plain, err := NewFileBackend(FileBackendSettings{DriverName: driverLocal, Directory: dir})
+		require.NoError(t, err)
+		_, err = plain.WriteFile(bytes.NewReader([]byte("legacy")), "legacy.txt")
+		require.NoError(t, err)
+
+		b := setupEncryptedFileBackend(t, dir, newTestEncryptionKey(t))
+		data, err := b.ReadFile("legacy.txt")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("legacy"), data)
+	})
+
+	t.Run("key rotation", func(t *testing.T) {
+		dir := t.TempDir()
+		oldKey := newTestEncryptionKey(t)
+		newKey := newTestEncryptionKey(t)
+		content := randomBytes(t, 1000)
+
+		b := setupEncryptedFileBackend(t, dir, oldKey)
+		_, err := b.WriteFile(bytes.NewReader(content), "data/file.bin")
+		require.NoError(t, err)
+		body, err := os.ReadFile(filepath.Join(dir, "data/file.bin"))
+		require.NoError(t, err)
+
+		b = setupEncryptedFileBackend(t, dir, newKey, oldKey)
+		paths, err := b.ListEncryptedFiles()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"data/file.bin"}, paths)
+
+		rewrapped, err := b.RewrapFileKey("data/file.bin")
+		require.NoError(t, err)
+		assert.True(t, rewrapped)
+
+		rewrapped, err = b.RewrapFileKey("data/file.bin")
+		require.NoError(t, err)
+		assert.False(t, rewrapped)
+
+		rotatedBody, err := os.ReadFile(filepath.Join(dir, "data/file.bin"))
+		require.NoError(t, err)
+		assert.Equal(t, body, rotatedBody)
+
+		b = setupEncryptedFileBackend(t, dir, newKey)
+		data, err := b.ReadFile("data/file.bin")
+		require.NoError(t, err)
+		assert.Equal(t, content, data)
+
+		b = setupEncryptedFileBackend(t, dir, oldKey)
+		_, err = b.ReadFile("data/file.bin")
+		require.Error(t, err)
+	})
+}
+
+func TestLoadEncryptionKeyring(t *testing.T) {
+	activeKey := newTestEncryptionKey(t)
+	retiredKey := newTestEncryptionKey(t)
+
+	t.Run("invalid key", func(t *testing.T) {
+		_, err := LoadEncryptionKeyring("not a key", "", nil)
+		require.Error(t, err)
+
+		_, err = LoadEncryptionKeyring(base64.StdEncoding.EncodeToString([]byte("short")), "", nil)
+		require.Error(t, err)
+	})
+
+	t.Run("key file", func(t *testing.T) {
+		keyFile := filepath.Join(t.TempDir(), "keys")
+		require.NoError(t, os.WriteFile(keyFile, []byte("# active key first\n"+activeKey+"\n\n"+retiredKey+"\n"), 0600))
+
+		keyring, err := LoadEncryptionKeyring("", keyFile, nil)
+		require.NoError(t, err)
+
+		expected, err := NewEncryptionKeyring(activeKey, []string{retiredKey})
+		require.NoError(t, err)
+		assert.Equal(t, expected.ActiveKeyId(), keyring.ActiveKeyId())
+		assert.Len(t, keyring.keys, 2)
+	})
+
+	t.Run("empty key file", func(t *testing.T) {
+		keyFile := filepath.Join(t.TempDir(), "keys")
+		require.NoError(t, os.WriteFile(keyFile, []byte("\n"), 0600))
+
+		_, err := LoadEncryptionKeyring(activeKey, keyFile, nil)
+		require.Error(t, err)
+	})
+}
@@ -13,8 +13,11 @@ import (
 )
 
 const (
-	driverS3    = "amazons3"
-	driverLocal = "local"
+	driverS3     = "amazons3"
+	driverLocal  = "local"
+	driverDedup  = "dedup"
+	driverWebDAV = "webdav"
+	driverSFTP   = "sftp"
 )
 
 type ReadCloseSeeker interface {
@@ -65,17 +68,85 @@ type FileBackendSettings struct {
 	AmazonS3PresignExpiresSeconds      int64
 	AmazonS3UploadPartSizeBytes        int64
 	AmazonS3StorageClass               string
+	// DedupDriverName is the driver used to store blobs when DriverName is
+	// the deduplicating driver.
+	DedupDriverName string
+	// BlobRefStore is required by the deduplicating driver.
+	BlobRefStore BlobRefStore
+	// EncryptionEnabled encrypts the files written to the local or S3
+	// driver with the configured master keys.
+	EncryptionEnabled     bool
+	EncryptionKey         string
+	EncryptionKeyFile     string
+	EncryptionRetiredKeys []string
+	// TieredStorageEnabled moves cold files written to the local driver
+	// to the configured S3 bucket.
+	TieredStorageEnabled bool
+	WebDAVURL            string
+	WebDAVUsername       string
+	WebDAVPassword       string
+	// SFTPHost is the host:port of the SFTP server, port 22 being assumed
+	// when it is missing. SFTPHostKey is the server public key in the
+	// authorized_keys format, which is required as the host is always
+	// verified.
+	SFTPHost       string
+	SFTPUsername   string
+	SFTPPassword   string
+	SFTPPrivateKey string
+	SFTPHostKey    string
+	SFTPDirectory  string
 }
 
 func NewFileBackendSettingsFromConfig(fileSettings *model.FileSettings, enableComplianceFeature bool, skipVerify bool) FileBackendSettings {
-	if *fileSettings.DriverName == model.ImageDriverLocal {
+	var dedupDriverName string
+	if *fileSettings.DriverName == model.ImageDriverDedup {
+		dedupDriverName = model.SafeDereference(fileSettings.DedupDriverName)
+	}
+
+	tieredStorageEnabled := *fileSettings.DriverName == model.ImageDriverLocal && model.SafeDereference(fileSettings.EnableTieredStorage)
+
+	if isRemoteFileDriver(*fileSettings.DriverName) || isRemoteFileDriver(dedupDriverName) {
+		return FileBackendSettings{
+			DriverName:            *fileSettings.DriverName,
+			Directory:             *fileSettings.Directory,
+			DedupDriverName:       dedupDriverName,
+			EncryptionEnabled:     model.SafeDereference(fileSettings.EnableEncryption),
+			EncryptionKey:         model.SafeDereference(fileSettings.EncryptionKey),
+			EncryptionKeyFile:     model.SafeDereference(fileSettings.EncryptionKeyFile),
+			EncryptionRetiredKeys: fileSettings.EncryptionRetiredKeys,
+			SkipVerify:            skipVerify,
+			WebDAVURL:             model.SafeDereference(fileSettings.WebDAVURL),
+			WebDAVUsername:        model.SafeDereference(fileSettings.WebDAVUsername),
+			WebDAVPassword:        model.SafeDereference(fileSettings.WebDAVPassword),
+			SFTPHost:              model.SafeDereference(fileSettings.SFTPHost),
+			SFTPUsername:          model.SafeDereference(fileSettings.SFTPUsername),
+			SFTPPassword:          model.SafeDereference(fileSettings.SFTPPassword),
+			SFTPPrivateKey:        model.SafeDereference(fileSettings.SFTPPrivateKey),
+			SFTPHostKey:           model.SafeDereference(fileSettings.SFTPHostKey),
+			SFTPDirectory:         model.SafeDereference(fileSettings.SFTPDirectory),
+		}
+	}
+
+	if (*fileSettings.DriverName == model.ImageDriverLocal || dedupDriverName == model.ImageDriverLocal) && !tieredStorageEnabled {
 		return FileBackendSettings{
-			DriverName: *fileSettings.DriverName,
-			Directory:  *fileSettings.Directory,
+			DriverName:            *fileSettings.DriverName,
+			Directory:             *fileSettings.Directory,
+			DedupDriverName:       dedupDriverName,
+			EncryptionEnabled:     model.SafeDereference(fileSettings.EnableEncryption),
+			EncryptionKey:         model.SafeDereference(fileSettings.EncryptionKey),
+			EncryptionKeyFile:     model.SafeDereference(fileSettings.EncryptionKeyFile),
+			EncryptionRetiredKeys: fileSettings.EncryptionRetiredKeys,
 		}
 	}
 	return FileBackendSettings{
 		DriverName:                         *fileSettings.DriverName,
+		Directory:                          *fileSettings.Directory,
+		DedupDriverName:                    dedupDriverName,
+		TieredStorageEnabled:               tieredStorageEnabled,
+		EncryptionEnabled:                  model.SafeDereference(fileSettings.EnableEncryption),
+		EncryptionKey:                      model.SafeDereference(fileSettings.EncryptionKey),
+		EncryptionKeyFile:                  model.SafeDereference(fileSettings.EncryptionKeyFile),
+		EncryptionRetiredKeys:              fileSettings.EncryptionRetiredKeys,
 		AmazonS3AccessKeyId:                *fileSettings.AmazonS3AccessKeyId,
 		AmazonS3SecretAccessKey:            *fileSettings.AmazonS3SecretAccessKey,
 		AmazonS3Bucket:                     *fileSettings.AmazonS3Bucket,
@@ -100,6 +171,21 @@ func NewExportFileBackendSettingsFromConfig(fileSettings *model.FileSettings, en
 			Directory:  *fileSettings.ExportDirectory,
 		}
 	}
+	if isRemoteFileDriver(*fileSettings.ExportDriverName) {
+		return FileBackendSettings{
+			DriverName:     *fileSettings.ExportDriverName,
+			SkipVerify:     skipVerify,
+			WebDAVURL:      model.SafeDereference(fileSettings.ExportWebDAVURL),
+			WebDAVUsername: model.SafeDereference(fileSettings.ExportWebDAVUsername),
+			WebDAVPassword: model.SafeDereference(fileSettings.ExportWebDAVPassword),
+			SFTPHost:       model.SafeDereference(fileSettings.ExportSFTPHost),
+			SFTPUsername:   model.SafeDereference(fileSettings.ExportSFTPUsername),
+			SFTPPassword:   model.SafeDereference(fileSettings.ExportSFTPPassword),
+			SFTPPrivateKey: model.SafeDereference(fileSettings.ExportSFTPPrivateKey),
+			SFTPHostKey:    model.SafeDereference(fileSettings.ExportSFTPHostKey),
+			SFTPDirectory:  model.SafeDereference(fileSettings.ExportSFTPDirectory),
+		}
+	}
 	return FileBackendSettings{
 		DriverName:                         *fileSettings.ExportDriverName,
 		AmazonS3AccessKeyId:                *fileSettings.ExportAmazonS3AccessKeyId,
@@ -120,7 +206,18 @@ func NewExportFileBackendSettingsFromConfig(fileSettings *model.FileSettings, en
 	}
 }
 
+// isRemoteFileDriver reports whether the driver stores files on a WebDAV or
+// SFTP server.
+func isRemoteFileDriver(driverName string) bool {
+	return driverName == driverWebDAV || driverName == driverSFTP
+}
+
 func (settings *FileBackendSettings) CheckMandatoryS3Fields() error {
+	// The WebDAV and SFTP drivers are checked when connecting to the server.
+	if isRemoteFileDriver(settings.DriverName) || isRemoteFileDriver(settings.DedupDriverName) {
+		return nil
+	}
+
 	if settings.AmazonS3Bucket == "" {
 		return errors.New("missing s3 bucket settings")
 	}
@@ -154,15 +251,74 @@ func newFileBackend(settings FileBackendSettings, canBeCloud bool) (FileBackend,
 		if err != nil {
 			return nil, errors.Wrap(err, "unable to connect to the s3 backend")
 		}
-		return backend, nil
+		return newEncryptedFileBackendFromSettings(backend, settings)
 	case driverLocal:
-		return &LocalFileBackend{
+		backend, err := newEncryptedFileBackendFromSettings(&LocalFileBackend{
 			directory: settings.Directory,
-		}, nil
+		}, settings)
+		if err != nil || !settings.TieredStorageEnabled {
+			return backend, err
+		}
+		coldSettings := settings
+		coldSettings.DriverName = driverS3
+		coldSettings.TieredStorageEnabled = false
+		cold, err := newFileBackend(coldSettings, canBeCloud)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to initialize the cold storage tier")
+		}
+		return NewTieredFileBackend(backend, cold), nil
+	case driverWebDAV:
+		backend, err := NewWebDAVFileBackend(settings)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to initialize the webdav backend")
+		}
+		return newEncryptedFileBackendFromSettings(backend, settings)
+	case driverSFTP:
+		backend, err := NewSFTPFileBackend(settings)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to initialize the sftp backend")
+		}
+		return newEncryptedFileBackendFromSettings(backend, settings)
+	case driverDedup:
+		if settings.BlobRefStore == nil {
+			return nil, errors.New("the dedup filestorage driver requires a blob reference store")
+		}
+		if settings.DedupDriverName == driverDedup {
+			return nil, errors.New("the dedup filestorage driver cannot be nested")
+		}
+		physicalSettings := settings
+		physicalSettings.DriverName = settings.DedupDriverName
+		physicalSettings.TieredStorageEnabled = false
+		physical, err := newFileBackend(physicalSettings, canBeCloud)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to initialize the dedup physical backend")
+		}
+		return NewDedupFileBackend(physical, settings.BlobRefStore), nil
 	}
 	return nil, errors.New("no valid filestorage driver found")
 }
 
+// CloseFileBackend closes the connections held by fb and the backends it
+// wraps, if any. It must be called once a backend that is not kept, like one
+// built to test a configuration, is no longer used.
+func CloseFileBackend(fb FileBackend) error {
+	switch b := fb.(type) {
+	case *EncryptedFileBackend:
+		return CloseFileBackend(b.PhysicalBackend())
+	case *DedupFileBackend:
+		return CloseFileBackend(b.PhysicalBackend())
+	case *TieredFileBackend:
+		err := CloseFileBackend(b.HotBackend())
+		if coldErr := CloseFileBackend(b.ColdBackend()); err == nil {
+			err = coldErr
+		}
+		return err
+	case io.Closer:
+		return b.Close()
+	}
+	return nil
+}
+
 // TryWriteFileContext checks if the file backend supports context writes and passes the context in that case.
 // Should the file backend not support contexts, it just calls WriteFile instead. This can be used to disable
 // the timeouts for long writes (like exports).
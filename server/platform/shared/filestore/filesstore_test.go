@@ -836,6 +836,29 @@ func TestNewExportFileBackendSettingsFromConfig(t *testing.T) {
 
 		require.Equal(t, expected, actual)
 	})
+
+	t.Run("sftp filestore", func(t *testing.T) {
+		expected := FileBackendSettings{
+			DriverName:    driverSFTP,
+			SkipVerify:    true,
+			SFTPHost:      "files.example.com:2222",
+			SFTPUsername:  "mattermost",
+			SFTPPassword:  "password",
+			SFTPHostKey:   "ssh-ed25519 AAAA",
+			SFTPDirectory: "exports",
+		}
+
+		actual := NewExportFileBackendSettingsFromConfig(&model.FileSettings{
+			ExportDriverName:    model.NewPointer(driverSFTP),
+			ExportSFTPHost:      model.NewPointer("files.example.com:2222"),
+			ExportSFTPUsername:  model.NewPointer("mattermost"),
+			ExportSFTPPassword:  model.NewPointer("password"),
+			ExportSFTPHostKey:   model.NewPointer("ssh-ed25519 AAAA"),
+			ExportSFTPDirectory: model.NewPointer("exports"),
+		}, false, true)
+
+		require.Equal(t, expected, actual)
+	})
 }
 
 func (s *FileBackendTestSuite) TestZipReaderSingleFile() {
@@ -0,0 +1,415 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const sftpDialTimeout = 30 * time.Second
+
+// SFTPFileBackend stores files in a directory of a remote host over SFTP. The
+// connection is established on first use and re-established once it is lost.
+type SFTPFileBackend struct {
+	host       string
+	username   string
+	password   string
+	privateKey string
+	hostKey    string
+	directory  string
+
+	mu     sync.Mutex
+	conn   *ssh.Client
+	client *sftp.Client
+}
+
+func NewSFTPFileBackend(settings FileBackendSettings) (*SFTPFileBackend, error) {
+	if settings.SFTPHost == "" {
+		return nil, errors.New("missing sftp host")
+	}
+	// The host key is always verified: unlike TLS certificates, there is no
+	// authority to fall back to, so skipping the check would accept any host.
+	if settings.SFTPHostKey == "" {
+		return nil, errors.New("missing sftp host key")
+	}
+
+	host := settings.SFTPHost
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "22")
+	}
+
+	directory := settings.SFTPDirectory
+	if directory == "" {
+		directory = "."
+	}
+
+	return &SFTPFileBackend{
+		host:       host,
+		username:   settings.SFTPUsername,
+		password:   settings.SFTPPassword,
+		privateKey: settings.SFTPPrivateKey,
+		hostKey:    settings.SFTPHostKey,
+		directory:  directory,
+	}, nil
+}
+
+func (b *SFTPFileBackend) sshConfig() (*ssh.ClientConfig, error) {
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(b.hostKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse the sftp host key")
+	}
+	config := &ssh.ClientConfig{
+		User:            b.username,
+		HostKeyCallback: ssh.FixedHostKey(hostKey),
+		Timeout:         sftpDialTimeout,
+	}
+
+	if b.privateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(b.privateKey))
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to parse the sftp private key")
+		}
+		config.Auth = append(config.Auth, ssh.PublicKeys(signer))
+	}
+	if b.password != "" {
+		config.Auth = append(config.Auth, ssh.Password(b.password))
+	}
+	return config, nil
+}
+
+// connect returns a connected client, dialing the host if needed.
+func (b *SFTPFileBackend) connect() (*sftp.Client, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.client != nil {
+		return b.client, nil
+	}
+
+	config, err := b.sshConfig()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := ssh.Dial("tcp", b.host, config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to connect to the sftp host %s", b.host)
+	}
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrapf(err, "unable to start the sftp session on %s", b.host)
+	}
+	b.conn = conn
+	b.client = client
+
+	// Forget the client once the connection is lost so that the next call
+	// dials the host again.
+	go func() {
+		conn.Wait()
+		client.Close()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if b.client == client {
+			b.conn = nil
+			b.client = nil
+		}
+	}()
+
+	return client, nil
+}
+
+// Close closes the connection to the host, if any. The backend dials the host
+// again if it is used afterwards.
+func (b *SFTPFileBackend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.client == nil {
+		return nil
+	}
+	client, conn := b.client, b.conn
+	b.client = nil
+	b.conn = nil
+
+	err := client.Close()
+	if connErr := conn.Close(); err == nil && !errors.Is(connErr, net.ErrClosed) {
+		err = connErr
+	}
+	return err
+}
+
+func (b *SFTPFileBackend) fullPath(p string) string {
+	return path.Join(b.directory, p)
+}
+
+func (b *SFTPFileBackend) DriverName() string {
+	return driverSFTP
+}
+
+func (b *SFTPFileBackend) TestConnection() error {
+	f := bytes.NewReader([]byte("testingwrite"))
+	if _, err := b.WriteFile(f, TestFilePath); err != nil {
+		return errors.Wrap(err, "unable to write to the sftp storage")
+	}
+	b.RemoveFile(TestFilePath)
+	mlog.Debug("Able to write files to sftp storage.")
+	return nil
+}
+
+func (b *SFTPFileBackend) stat(p string) (fs.FileInfo, error) {
+	c, err := b.connect()
+	if err != nil {
+		return nil, err
+	}
+	return c.Stat(b.fullPath(p))
+}
+
+func (b *SFTPFileBackend) Reader(p string) (ReadCloseSeeker, error) {
+	c, err := b.connect()
+	if err != nil {
+		return nil, err
+	}
+	f, err := c.Open(b.fullPath(p))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open file %s", p)
+	}
+	return f, nil
+}
+
+func (b *SFTPFileBackend) ReadFile(p string) ([]byte, error) {
+	r, err := b.Reader(p)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read file %s", p)
+	}
+	return data, nil
+}
+
+func (b *SFTPFileBackend) FileExists(p string) (bool, error) {
+	_, err := b.stat(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "unable to know if file %s exists", p)
+	}
+	return true, nil
+}
+
+func (b *SFTPFileBackend) FileSize(p string) (int64, error) {
+	info, err := b.stat(p)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to get file size for %s", p)
+	}
+	return info.Size(), nil
+}
+
+func (b *SFTPFileBackend) FileModTime(p string) (time.Time, error) {
+	info, err := b.stat(p)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "unable to get modification time for file %s", p)
+	}
+	return info.ModTime(), nil
+}
+
+func (b *SFTPFileBackend) CopyFile(oldPath, newPath string) error {
+	r, err := b.Reader(oldPath)
+	if err != nil {
+		return errors.Wrapf(err, "unable to copy file from %s to %s", oldPath, newPath)
+	}
+	defer r.Close()
+
+	if _, err := b.WriteFile(r, newPath); err != nil {
+		return errors.Wrapf(err, "unable to copy file from %s to %s", oldPath, newPath)
+	}
+	return nil
+}
+
+func (b *SFTPFileBackend) MoveFile(oldPath, newPath string) error {
+	c, err := b.connect()
+	if err != nil {
+		return err
+	}
+	if err := c.MkdirAll(path.Dir(b.fullPath(newPath))); err != nil {
+		return errors.Wrapf(err, "unable to create the new destination directory %s", path.Dir(newPath))
+	}
+
+	if _, ok := c.HasExtension("posix-rename@openssh.com"); ok {
+		err = c.PosixRename(b.fullPath(oldPath), b.fullPath(newPath))
+	} else {
+		// Version 3 of the protocol does not overwrite existing files on rename.
+		if err = c.Remove(b.fullPath(newPath)); err == nil || errors.Is(err, fs.ErrNotExist) {
+			err = c.Rename(b.fullPath(oldPath), b.fullPath(newPath))
+		}
+	}
+	if err != nil {
+		return errors.Wrapf(err, "unable to move the file to %s from %s", newPath, oldPath)
+	}
+	return nil
+}
+
+func (b *SFTPFileBackend) WriteFile(fr io.Reader, p string) (int64, error) {
+	c, err := b.connect()
+	if err != nil {
+		return 0, err
+	}
+	if err := c.MkdirAll(path.Dir(b.fullPath(p))); err != nil {
+		return 0, errors.Wrapf(err, "unable to create the directory %s for the file %s", path.Dir(p), p)
+	}
+
+	f, err := c.OpenFile(b.fullPath(p), os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to open the file %s to write the data", p)
+	}
+	written, err := io.Copy(f, fr)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return written, errors.Wrapf(err, "unable write the data in the file %s", p)
+	}
+	return written, nil
+}
+
+func (b *SFTPFileBackend) AppendFile(fr io.Reader, p string) (int64, error) {
+	c, err := b.connect()
+	if err != nil {
+		return 0, err
+	}
+	f, err := c.OpenFile(b.fullPath(p), os.O_WRONLY)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to find the file %s to append the data", p)
+	}
+
+	// The offset is set explicitly as servers handle the append flag of the
+	// protocol inconsistently.
+	if _, err = f.Seek(0, io.SeekEnd); err != nil {
+		f.Close()
+		return 0, errors.Wrapf(err, "unable to find the end of the file %s", p)
+	}
+	written, err := io.Copy(f, fr)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return written, errors.Wrapf(err, "unable append the data in the file %s", p)
+	}
+	return written, nil
+}
+
+func (b *SFTPFileBackend) RemoveFile(p string) error {
+	c, err := b.connect()
+	if err != nil {
+		return err
+	}
+	if err := c.Remove(b.fullPath(p)); err != nil {
+		return errors.Wrapf(err, "unable to remove the file %s", p)
+	}
+	return nil
+}
+
+func (b *SFTPFileBackend) readDir(p string) ([]fs.FileInfo, error) {
+	c, err := b.connect()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := c.ReadDir(b.fullPath(p))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the directory %s", p)
+	}
+	return entries, nil
+}
+
+func (b *SFTPFileBackend) ListDirectory(p string) ([]string, error) {
+	entries, err := b.readDir(p)
+	if err != nil {
+		return nil, err
+	}
+	results := []string{}
+	for _, entry := range entries {
+		results = append(results, path.Join(p, entry.Name()))
+	}
+	return results, nil
+}
+
+func (b *SFTPFileBackend) ListDirectoryRecursively(p string) ([]string, error) {
+	return b.appendRecursively(p, MaxRecursionDepth)
+}
+
+func (b *SFTPFileBackend) appendRecursively(p string, maxDepth int) ([]string, error) {
+	entries, err := b.readDir(p)
+	if err != nil {
+		return nil, err
+	}
+	results := []string{}
+	for _, entry := range entries {
+		entryPath := path.Join(p, entry.Name())
+		if !entry.IsDir() {
+			results = append(results, entryPath)
+			continue
+		}
+		if maxDepth <= 0 {
+			mlog.Warn("Max depth reached, skipping any further directories", mlog.Int("depth", maxDepth), mlog.String("path", entryPath))
+			results = append(results, entryPath)
+			continue
+		}
+		nested, err := b.appendRecursively(entryPath, maxDepth-1)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, nested...)
+	}
+	return results, nil
+}
+
+func (b *SFTPFileBackend) RemoveDirectory(p string) error {
+	c, err := b.connect()
+	if err != nil {
+		return err
+	}
+	if err := c.RemoveAll(b.fullPath(p)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return errors.Wrapf(err, "unable to remove the directory %s", p)
+	}
+	return nil
+}
+
+// ZipReader will create a zip of path. If path is a single file, it will zip the single file.
+// If deflate is true, the contents will be compressed. It will stream the zip to io.ReadCloser.
+func (b *SFTPFileBackend) ZipReader(p string, deflate bool) (io.ReadCloser, error) {
+	// Like S3, a missing path zips to an empty archive.
+	info, err := b.stat(p)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, errors.Wrapf(err, "unable to stat path %s", p)
+	}
+	if err == nil && !info.IsDir() {
+		return zipFiles(b, []string{p}, path.Dir(p), deflate), nil
+	}
+
+	files, err := b.ListDirectoryRecursively(p)
+	if err != nil {
+		return nil, err
+	}
+	return zipFiles(b, files, p, deflate), nil
+}
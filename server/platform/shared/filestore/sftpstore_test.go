@@ -0,0 +1,239 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/ssh"
+)
+
+// testSFTPServer serves a local directory over SSH.
+type testSFTPServer struct {
+	root     string
+	listener net.Listener
+	hostKey  ssh.Signer
+}
+
+func newTestSFTPServer(t *testing.T) *testSFTPServer {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostKey, err := ssh.NewSignerFromKey(privateKey)
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	server := &testSFTPServer{
+		root:     t.TempDir(),
+		listener: listener,
+		hostKey:  hostKey,
+	}
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == "mattermost" && string(password) == "password" {
+				return nil, nil
+			}
+			return nil, errors.New("invalid credentials")
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serveConn(conn, config)
+		}
+	}()
+
+	return server
+}
+
+func (s *testSFTPServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *testSFTPServer) HostKey() string {
+	return string(ssh.MarshalAuthorizedKey(s.hostKey.PublicKey()))
+}
+
+func (s *testSFTPServer) serveConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range requests {
+				ok := req.Type == "subsystem" && bytes.Equal(req.Payload[4:], []byte("sftp"))
+				req.Reply(ok, nil)
+				if !ok {
+					continue
+				}
+				go func() {
+					defer channel.Close()
+					server, err := sftp.NewServer(channel, sftp.WithServerWorkingDirectory(s.root))
+					if err != nil {
+						return
+					}
+					server.Serve()
+				}()
+			}
+		}()
+	}
+}
+
+func TestSFTPFileBackendTestSuite(t *testing.T) {
+	server := newTestSFTPServer(t)
+	require.NoError(t, os.Mkdir(filepath.Join(server.root, "data"), 0700))
+
+	suite.Run(t, &FileBackendTestSuite{
+		settings: FileBackendSettings{
+			DriverName:    driverSFTP,
+			SFTPHost:      server.Addr(),
+			SFTPUsername:  "mattermost",
+			SFTPPassword:  "password",
+			SFTPHostKey:   server.HostKey(),
+			SFTPDirectory: "data",
+		},
+	})
+}
+
+// sftpTestChunkSize is the maximum size of the data of a single request.
+const sftpTestChunkSize = 1 << 15
+
+func TestSFTPFileBackend(t *testing.T) {
+	server := newTestSFTPServer(t)
+
+	t.Run("wrong credentials", func(t *testing.T) {
+		backend, err := NewSFTPFileBackend(FileBackendSettings{
+			SFTPHost:     server.Addr(),
+			SFTPUsername: "mattermost",
+			SFTPPassword: "wrong",
+			SFTPHostKey:  server.HostKey(),
+		})
+		require.NoError(t, err)
+		require.Error(t, backend.TestConnection())
+	})
+
+	t.Run("host key mismatch", func(t *testing.T) {
+		other := newTestSFTPServer(t)
+		backend, err := NewSFTPFileBackend(FileBackendSettings{
+			SFTPHost:     server.Addr(),
+			SFTPUsername: "mattermost",
+			SFTPPassword: "password",
+			SFTPHostKey:  other.HostKey(),
+		})
+		require.NoError(t, err)
+		require.Error(t, backend.TestConnection())
+	})
+
+	t.Run("host key is required even when skipping verification", func(t *testing.T) {
+		_, err := NewSFTPFileBackend(FileBackendSettings{
+			SFTPHost:     server.Addr(),
+			SFTPUsername: "mattermost",
+			SFTPPassword: "password",
+			SkipVerify:   true,
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("files larger than a single request", func(t *testing.T) {
+		backend, err := NewSFTPFileBackend(FileBackendSettings{
+			SFTPHost:     server.Addr(),
+			SFTPUsername: "mattermost",
+			SFTPPassword: "password",
+			SFTPHostKey:  server.HostKey(),
+		})
+		require.NoError(t, err)
+
+		data := make([]byte, 3*sftpTestChunkSize+17)
+		_, err = rand.Read(data)
+		require.NoError(t, err)
+
+		written, err := backend.WriteFile(bytes.NewReader(data), "large/file.bin")
+		require.NoError(t, err)
+		assert.EqualValues(t, len(data), written)
+
+		read, err := backend.ReadFile("large/file.bin")
+		require.NoError(t, err)
+		assert.Equal(t, data, read)
+
+		r, err := backend.Reader("large/file.bin")
+		require.NoError(t, err)
+		defer r.Close()
+		_, err = r.Seek(int64(2*sftpTestChunkSize), io.SeekStart)
+		require.NoError(t, err)
+		read, err = io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, data[2*sftpTestChunkSize:], read)
+	})
+
+	t.Run("reconnects after the connection is lost", func(t *testing.T) {
+		backend, err := NewSFTPFileBackend(FileBackendSettings{
+			SFTPHost:     server.Addr(),
+			SFTPUsername: "mattermost",
+			SFTPPassword: "password",
+			SFTPHostKey:  server.HostKey(),
+		})
+		require.NoError(t, err)
+		require.NoError(t, backend.TestConnection())
+
+		backend.conn.Close()
+		require.Eventually(t, func() bool {
+			exists, err := backend.FileExists("missing")
+			return err == nil && !exists
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+	t.Run("close", func(t *testing.T) {
+		backend, err := NewSFTPFileBackend(FileBackendSettings{
+			SFTPHost:     server.Addr(),
+			SFTPUsername: "mattermost",
+			SFTPPassword: "password",
+			SFTPHostKey:  server.HostKey(),
+		})
+		require.NoError(t, err)
+		require.NoError(t, backend.TestConnection())
+
+		conn := backend.conn
+		require.NoError(t, CloseFileBackend(NewEncryptedFileBackend(backend, nil)))
+		assert.Nil(t, backend.client)
+		assert.Error(t, conn.Wait())
+		require.NoError(t, backend.Close())
+
+		// The backend dials the host again if it is used after being closed.
+		exists, err := backend.FileExists("missing")
+		require.NoError(t, err)
+		assert.False(t, exists)
+		require.NoError(t, backend.Close())
+	})
+}
@@ -0,0 +1,315 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// TieredFileBackend stores files across two backends. New files are always
+// written to the hot backend, typically the local disk, and are later moved to
+// the cold backend, typically S3, by MoveToCold. Callers that know the tier of
+// a file, from the StorageTier of its FileInfo, read it with TierReader. Other
+// reads are served from whichever backend holds the file, checking the hot
+// backend first.
+type TieredFileBackend struct {
+	hot  FileBackend
+	cold FileBackend
+}
+
+func NewTieredFileBackend(hot, cold FileBackend) *TieredFileBackend {
+	return &TieredFileBackend{
+		hot:  hot,
+		cold: cold,
+	}
+}
+
+// HotBackend returns the backend that new files are written to.
+func (b *TieredFileBackend) HotBackend() FileBackend {
+	return b.hot
+}
+
+// ColdBackend returns the backend that files are moved to once cold.
+func (b *TieredFileBackend) ColdBackend() FileBackend {
+	return b.cold
+}
+
+func (b *TieredFileBackend) DriverName() string {
+	return b.hot.DriverName()
+}
+
+func (b *TieredFileBackend) TestConnection() error {
+	if err := b.hot.TestConnection(); err != nil {
+		return err
+	}
+	if err := b.cold.TestConnection(); err != nil {
+		return errors.Wrap(err, "unable to connect to the cold storage tier")
+	}
+	return nil
+}
+
+// backendFor returns the backend holding path, falling back to the hot
+// backend when neither does so that callers get its not found error. It is
+// only used for the files whose tier isn't known, which are usually in the hot
+// backend.
+func (b *TieredFileBackend) backendFor(path string) (FileBackend, error) {
+	inHot, err := b.hot.FileExists(path)
+	if err != nil {
+		return nil, err
+	}
+	if inHot {
+		return b.hot, nil
+	}
+
+	inCold, err := b.cold.FileExists(path)
+	if err != nil {
+		return nil, err
+	}
+	if inCold {
+		return b.cold, nil
+	}
+	return b.hot, nil
+}
+
+func (b *TieredFileBackend) Reader(path string) (ReadCloseSeeker, error) {
+	backend, err := b.backendFor(path)
+	if err != nil {
+		return nil, err
+	}
+	return backend.Reader(path)
+}
+
+// TierReader returns a reader for path from the given tier without checking
+// which tier holds the file. The other tier is only tried if the file can't be
+// opened, as it may have been moved since its tier was recorded.
+func (b *TieredFileBackend) TierReader(path, tier string) (ReadCloseSeeker, error) {
+	backend, other := b.hot, b.cold
+	if tier == model.FileStorageTierCold {
+		backend, other = b.cold, b.hot
+	}
+
+	r, err := backend.Reader(path)
+	if err == nil {
+		return r, nil
+	}
+	if exists, existsErr := other.FileExists(path); existsErr == nil && exists {
+		return other.Reader(path)
+	}
+	return nil, err
+}
+
+func (b *TieredFileBackend) ReadFile(path string) ([]byte, error) {
+	backend, err := b.backendFor(path)
+	if err != nil {
+		return nil, err
+	}
+	return backend.ReadFile(path)
+}
+
+func (b *TieredFileBackend) FileExists(path string) (bool, error) {
+	inHot, err := b.hot.FileExists(path)
+	if err != nil || inHot {
+		return inHot, err
+	}
+	return b.cold.FileExists(path)
+}
+
+func (b *TieredFileBackend) FileSize(path string) (int64, error) {
+	backend, err := b.backendFor(path)
+	if err != nil {
+		return 0, err
+	}
+	return backend.FileSize(path)
+}
+
+func (b *TieredFileBackend) FileModTime(path string) (time.Time, error) {
+	backend, err := b.backendFor(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return backend.FileModTime(path)
+}
+
+// CopyFile copies the file within the tier that holds it.
+func (b *TieredFileBackend) CopyFile(oldPath, newPath string) error {
+	backend, err := b.backendFor(oldPath)
+	if err != nil {
+		return err
+	}
+	return backend.CopyFile(oldPath, newPath)
+}
+
+// MoveFile moves the file within the tier that holds it.
+func (b *TieredFileBackend) MoveFile(oldPath, newPath string) error {
+	backend, err := b.backendFor(oldPath)
+	if err != nil {
+		return err
+	}
+	return backend.MoveFile(oldPath, newPath)
+}
+
+func (b *TieredFileBackend) WriteFile(fr io.Reader, path string) (int64, error) {
+	return b.hot.WriteFile(fr, path)
+}
+
+func (b *TieredFileBackend) AppendFile(fr io.Reader, path string) (int64, error) {
+	backend, err := b.backendFor(path)
+	if err != nil {
+		return 0, err
+	}
+	return backend.AppendFile(fr, path)
+}
+
+// RemoveFile removes the file from both tiers, so that a copy left behind by
+// an interrupted move is cleaned up as well.
+func (b *TieredFileBackend) RemoveFile(path string) error {
+	inCold, err := b.cold.FileExists(path)
+	if err != nil {
+		return err
+	}
+	if inCold {
+		if err := b.cold.RemoveFile(path); err != nil {
+			return err
+		}
+	}
+
+	inHot, err := b.hot.FileExists(path)
+	if err != nil {
+		return err
+	}
+	if inHot || !inCold {
+		return b.hot.RemoveFile(path)
+	}
+	return nil
+}
+
+func mergeListings(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	merged := make([]string, 0, len(a)+len(b))
+	for _, listing := range [][]string{a, b} {
+		for _, p := range listing {
+			if !seen[p] {
+				seen[p] = true
+				merged = append(merged, p)
+			}
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
+
+func (b *TieredFileBackend) ListDirectory(path string) ([]string, error) {
+	hotPaths, err := b.hot.ListDirectory(path)
+	if err != nil {
+		return nil, err
+	}
+	coldPaths, err := b.cold.ListDirectory(path)
+	if err != nil {
+		return nil, err
+	}
+	return mergeListings(hotPaths, coldPaths), nil
+}
+
+func (b *TieredFileBackend) ListDirectoryRecursively(path string) ([]string, error) {
+	hotPaths, err := b.hot.ListDirectoryRecursively(path)
+	if err != nil {
+		return nil, err
+	}
+	coldPaths, err := b.cold.ListDirectoryRecursively(path)
+	if err != nil {
+		return nil, err
+	}
+	return mergeListings(hotPaths, coldPaths), nil
+}
+
+func (b *TieredFileBackend) RemoveDirectory(path string) error {
+	if err := b.cold.RemoveDirectory(path); err != nil {
+		return err
+	}
+	return b.hot.RemoveDirectory(path)
+}
+
+func (b *TieredFileBackend) ZipReader(path string, deflate bool) (io.ReadCloser, error) {
+	inHot, err := b.hot.FileExists(path)
+	if err != nil {
+		return nil, err
+	}
+	if !inHot {
+		return b.cold.ZipReader(path, deflate)
+	}
+
+	coldPaths, err := b.cold.ListDirectoryRecursively(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(coldPaths) == 0 {
+		return b.hot.ZipReader(path, deflate)
+	}
+
+	// The directory is split across both tiers.
+	paths, err := b.ListDirectoryRecursively(path)
+	if err != nil {
+		return nil, err
+	}
+	return zipFiles(b, paths, path, deflate), nil
+}
+
+// MoveToCold moves the file at path from the hot to the cold tier. The hot
+// copy is only removed once the cold copy has been fully written.
+func (b *TieredFileBackend) MoveToCold(path string) error {
+	return moveBetweenTiers(b.hot, b.cold, path)
+}
+
+// MoveToHot moves the file at path from the cold back to the hot tier.
+func (b *TieredFileBackend) MoveToHot(path string) error {
+	return moveBetweenTiers(b.cold, b.hot, path)
+}
+
+func moveBetweenTiers(src, dst FileBackend, path string) error {
+	exists, err := src.FileExists(path)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		// Already moved, possibly by an earlier run that was interrupted
+		// before the FileInfo could be updated.
+		if exists, err = dst.FileExists(path); err != nil {
+			return err
+		} else if !exists {
+			return errors.Errorf("unable to find the file %s in either storage tier", path)
+		}
+		return nil
+	}
+
+	r, err := src.Reader(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	size, err := src.FileSize(path)
+	if err != nil {
+		return err
+	}
+
+	written, err := dst.WriteFile(r, path)
+	if err != nil {
+		return errors.Wrapf(err, "unable to write the file %s to the destination tier", path)
+	}
+	if written != size {
+		dst.RemoveFile(path)
+		return errors.Errorf("unable to move the file %s between storage tiers: wrote %d of %d bytes", path, written, size)
+	}
+
+	if err := src.RemoveFile(path); err != nil {
+		return errors.Wrapf(err, "unable to remove the file %s from the source tier", path)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func setupTieredFileBackend(t *testing.T) (*TieredFileBackend, FileBackend, FileBackend) {
+	hot := &LocalFileBackend{directory: t.TempDir()}
+	cold := &LocalFileBackend{directory: t.TempDir()}
+	return NewTieredFileBackend(hot, cold), hot, cold
+}
+
+func TestTieredFileBackend(t *testing.T) {
+	t.Run("writes go to the hot tier", func(t *testing.T) {
+		b, hot, cold := setupTieredFileBackend(t)
+
+		_, err := b.WriteFile(bytes.NewReader([]byte("hello")), "data/file.txt")
+		require.NoError(t, err)
+
+		exists, err := hot.FileExists("data/file.txt")
+		require.NoError(t, err)
+		assert.True(t, exists)
+		exists, err = cold.FileExists("data/file.txt")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("reads follow the file across tiers", func(t *testing.T) {
+		b, hot, cold := setupTieredFileBackend(t)
+		content := []byte("cold content")
+
+		_, err := b.WriteFile(bytes.NewReader(content), "data/file.txt")
+		require.NoError(t, err)
+		require.NoError(t, b.MoveToCold("data/file.txt"))
+
+		exists, err := hot.FileExists("data/file.txt")
+		require.NoError(t, err)
+		assert.False(t, exists)
+		data, err := cold.ReadFile("data/file.txt")
+		require.NoError(t, err)
+		assert.Equal(t, content, data)
+
+		data, err = b.ReadFile("data/file.txt")
+		require.NoError(t, err)
+		assert.Equal(t, content, data)
+
+		r, err := b.Reader("data/file.txt")
+		require.NoError(t, err)
+		data, err = io.ReadAll(r)
+		require.NoError(t, err)
+		r.Close()
+		assert.Equal(t, content, data)
+
+		size, err := b.FileSize("data/file.txt")
+		require.NoError(t, err)
+		assert.EqualValues(t, len(content), size)
+
+		require.NoError(t, b.MoveToHot("data/file.txt"))
+		exists, err = cold.FileExists("data/file.txt")
+		require.NoError(t, err)
+		assert.False(t, exists)
+		data, err = b.ReadFile("data/file.txt")
+		require.NoError(t, err)
+		assert.Equal(t, content, data)
+	})
+
+	t.Run("reads from a known tier", func(t *testing.T) {
+		b, _, _ := setupTieredFileBackend(t)
+		content := []byte("tiered content")
+
+		_, err := b.WriteFile(bytes.NewReader(content), "data/file.txt")
+		require.NoError(t, err)
+		require.NoError(t, b.MoveToCold("data/file.txt"))
+
+		readTier := func(tier string) []byte {
+			t.Helper()
+			r, err := b.TierReader("data/file.txt", tier)
+			require.NoError(t, err)
+			defer r.Close()
+			data, err := io.ReadAll(r)
+			require.NoError(t, err)
+			return data
+		}
+		assert.Equal(t, content, readTier(model.FileStorageTierCold))
+
+		// The file may have moved since its tier was recorded.
+		assert.Equal(t, content, readTier(model.FileStorageTierHot))
+
+		_, err = b.TierReader("data/missing.txt", model.FileStorageTierHot)
+		require.Error(t, err)
+	})
+
+	t.Run("moving twice is a no-op", func(t *testing.T) {
+		b, _, _ := setupTieredFileBackend(t)
+
+		_, err := b.WriteFile(bytes.NewReader([]byte("x")), "file.txt")
+		require.NoError(t, err)
+		require.NoError(t, b.MoveToCold("file.txt"))
+		require.NoError(t, b.MoveToCold("file.txt"))
+
+		require.Error(t, b.MoveToCold("missing.txt"))
+	})
+
+	t.Run("missing files", func(t *testing.T) {
+		b, _, _ := setupTieredFileBackend(t)
+
+		exists, err := b.FileExists("missing.txt")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		_, err = b.ReadFile("missing.txt")
+		require.Error(t, err)
+	})
+
+	t.Run("copy and remove", func(t *testing.T) {
+		b, _, cold := setupTieredFileBackend(t)
+
+		_, err := b.WriteFile(bytes.NewReader([]byte("x")), "a/file.txt")
+		require.NoError(t, err)
+		require.NoError(t, b.MoveToCold("a/file.txt"))
+		require.NoError(t, b.CopyFile("a/file.txt", "b/file.txt"))
+
+		exists, err := cold.FileExists("b/file.txt")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		require.NoError(t, b.RemoveFile("a/file.txt"))
+		exists, err = b.FileExists("a/file.txt")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("listings and zips span both tiers", func(t *testing.T) {
+		b, _, _ := setupTieredFileBackend(t)
+
+		_, err := b.WriteFile(bytes.NewReader([]byte("one")), "dir/one.txt")
+		require.NoError(t, err)
+		_, err = b.WriteFile(bytes.NewReader([]byte("two")), "dir/two.txt")
+		require.NoError(t, err)
+		require.NoError(t, b.MoveToCold("dir/two.txt"))
+
+		paths, err := b.ListDirectoryRecursively("dir")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dir/one.txt", "dir/two.txt"}, paths)
+
+		r, err := b.ZipReader("dir", false)
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		contents := map[string]string{}
+		for _, f := range zr.File {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			rc.Close()
+			contents[f.Name] = string(content)
+		}
+		assert.Equal(t, map[string]string{"one.txt": "one", "two.txt": "two"}, contents)
+
+		require.NoError(t, b.RemoveDirectory("dir"))
+		paths, err = b.ListDirectoryRecursively("dir")
+		require.NoError(t, err)
+		assert.Empty(t, paths)
+	})
+}
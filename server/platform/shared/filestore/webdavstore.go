@@ -0,0 +1,594 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// webdavRequestTimeout bounds a whole request, including its body, so it
+	// leaves room for the transfer of large files.
+	webdavRequestTimeout = 10 * time.Minute
+	// webdavResponseHeaderTimeout detects servers that stop answering
+	// without waiting for webdavRequestTimeout.
+	webdavResponseHeaderTimeout = time.Minute
+)
+
+const webdavPropfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:resourcetype/><d:getcontentlength/><d:getlastmodified/></d:prop></d:propfind>`
+
+// WebDAVFileBackend stores files in a collection of a WebDAV server.
+type WebDAVFileBackend struct {
+	baseURL  *url.URL
+	username string
+	password string
+	client   *http.Client
+}
+
+// webdavStatusError is returned when the server answers with an unexpected
+// status code.
+type webdavStatusError struct {
+	Method     string
+	Path       string
+	StatusCode int
+}
+
+func (e *webdavStatusError) Error() string {
+	return fmt.Sprintf("webdav: %s %s returned status %d", e.Method, e.Path, e.StatusCode)
+}
+
+func (e *webdavStatusError) Is(target error) bool {
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return target == fs.ErrNotExist
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return target == fs.ErrPermission
+	}
+	return false
+}
+
+type webdavResource struct {
+	Path    string
+	IsDir   bool
+	Size    int64
+	ModTime time.Time
+}
+
+type webdavMultistatus struct {
+	Responses []struct {
+		Href      string `xml:"href"`
+		Propstats []struct {
+			Status string `xml:"status"`
+			Prop   struct {
+				ResourceType struct {
+					Collection *struct{} `xml:"collection"`
+				} `xml:"resourcetype"`
+				ContentLength string `xml:"getcontentlength"`
+				LastModified  string `xml:"getlastmodified"`
+			} `xml:"prop"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+func NewWebDAVFileBackend(settings FileBackendSettings) (*WebDAVFileBackend, error) {
+	baseURL, err := url.Parse(settings.WebDAVURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse the webdav url")
+	}
+	if baseURL.Scheme != "http" && baseURL.Scheme != "https" {
+		return nil, errors.Errorf("unsupported webdav url scheme %q", baseURL.Scheme)
+	}
+	if !strings.HasSuffix(baseURL.Path, "/") {
+		baseURL.Path += "/"
+	}
+	baseURL.RawPath = ""
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: settings.SkipVerify}
+	transport.ResponseHeaderTimeout = webdavResponseHeaderTimeout
+
+	return &WebDAVFileBackend{
+		baseURL:  baseURL,
+		username: settings.WebDAVUsername,
+		password: settings.WebDAVPassword,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   webdavRequestTimeout,
+			// Redirects are not followed as they would turn WebDAV methods
+			// into GET requests.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}, nil
+}
+
+func (b *WebDAVFileBackend) url(p string, collection bool) string {
+	u := *b.baseURL
+	u.Path += strings.TrimPrefix(path.Clean("/"+p), "/")
+	if collection && !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	return u.String()
+}
+
+func (b *WebDAVFileBackend) do(method, p string, collection bool, body io.Reader, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequest(method, b.url(p, collection), body)
+	if err != nil {
+		return nil, err
+	}
+	if section, ok := body.(*io.SectionReader); ok {
+		req.ContentLength = section.Size()
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if b.username != "" || b.password != "" {
+		req.SetBasicAuth(b.username, b.password)
+	}
+	return b.client.Do(req)
+}
+
+// request sends a request and checks that the response has one of the
+// expected status codes. The response body must be closed by the caller.
+func (b *WebDAVFileBackend) request(method, p string, collection bool, body io.Reader, header http.Header, expected ...int) (*http.Response, error) {
+	resp, err := b.do(method, p, collection, body, header)
+	if err != nil {
+		return nil, err
+	}
+	for _, code := range expected {
+		if resp.StatusCode == code {
+			return resp, nil
+		}
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return nil, &webdavStatusError{Method: method, Path: p, StatusCode: resp.StatusCode}
+}
+
+func (b *WebDAVFileBackend) propfind(p string, depth string, collection bool) ([]webdavResource, error) {
+	header := http.Header{
+		"Depth":        {depth},
+		"Content-Type": {"application/xml; charset=utf-8"},
+	}
+	resp, err := b.request("PROPFIND", p, collection, strings.NewReader(webdavPropfindBody), header, http.StatusMultiStatus, http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Servers redirect to the canonical URL of a collection, which ends
+	// with a slash.
+	if resp.StatusCode != http.StatusMultiStatus {
+		if collection {
+			return nil, &webdavStatusError{Method: "PROPFIND", Path: p, StatusCode: resp.StatusCode}
+		}
+		return b.propfind(p, depth, true)
+	}
+
+	var multistatus webdavMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&multistatus); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the webdav properties of %s", p)
+	}
+
+	resources := make([]webdavResource, 0, len(multistatus.Responses))
+	for _, response := range multistatus.Responses {
+		href, err := url.Parse(response.Href)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid webdav href %s", response.Href)
+		}
+		resource := webdavResource{
+			Path: strings.Trim(strings.TrimPrefix(href.Path, b.baseURL.Path), "/"),
+		}
+		for _, propstat := range response.Propstats {
+			if !strings.Contains(propstat.Status, " 200 ") {
+				continue
+			}
+			prop := propstat.Prop
+			resource.IsDir = resource.IsDir || prop.ResourceType.Collection != nil
+			if prop.ContentLength != "" {
+				resource.Size, _ = strconv.ParseInt(strings.TrimSpace(prop.ContentLength), 10, 64)
+			}
+			if prop.LastModified != "" {
+				resource.ModTime, _ = http.ParseTime(strings.TrimSpace(prop.LastModified))
+			}
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+func (b *WebDAVFileBackend) stat(p string) (*webdavResource, error) {
+	resources, err := b.propfind(p, "0", false)
+	if err != nil {
+		return nil, err
+	}
+	if len(resources) == 0 {
+		return nil, &webdavStatusError{Method: "PROPFIND", Path: p, StatusCode: http.StatusNotFound}
+	}
+	return &resources[0], nil
+}
+
+func (b *WebDAVFileBackend) DriverName() string {
+	return driverWebDAV
+}
+
+func (b *WebDAVFileBackend) TestConnection() error {
+	f := bytes.NewReader([]byte("testingwrite"))
+	if _, err := b.WriteFile(f, TestFilePath); err != nil {
+		return errors.Wrap(err, "unable to write to the webdav storage")
+	}
+	b.RemoveFile(TestFilePath)
+	mlog.Debug("Able to write files to webdav storage.")
+	return nil
+}
+
+func (b *WebDAVFileBackend) Reader(p string) (ReadCloseSeeker, error) {
+	resource, err := b.stat(p)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open file %s", p)
+	}
+	if resource.IsDir {
+		return nil, errors.Errorf("unable to open file %s: is a directory", p)
+	}
+	return &webdavReader{backend: b, path: p, size: resource.Size}, nil
+}
+
+func (b *WebDAVFileBackend) ReadFile(p string) ([]byte, error) {
+	resp, err := b.request(http.MethodGet, p, false, nil, nil, http.StatusOK)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read file %s", p)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read file %s", p)
+	}
+	return data, nil
+}
+
+func (b *WebDAVFileBackend) FileExists(p string) (bool, error) {
+	_, err := b.stat(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "unable to know if file %s exists", p)
+	}
+	return true, nil
+}
+
+func (b *WebDAVFileBackend) FileSize(p string) (int64, error) {
+	resource, err := b.stat(p)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to get file size for %s", p)
+	}
+	return resource.Size, nil
+}
+
+func (b *WebDAVFileBackend) FileModTime(p string) (time.Time, error) {
+	resource, err := b.stat(p)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "unable to get modification time for file %s", p)
+	}
+	return resource.ModTime, nil
+}
+
+// mkcolAll creates the collection dir along with any missing parent.
+func (b *WebDAVFileBackend) mkcolAll(dir string) error {
+	dir = strings.Trim(path.Clean("/"+dir), "/")
+	if dir == "" {
+		return nil
+	}
+
+	resp, err := b.request("MKCOL", dir, true, nil, nil, http.StatusCreated, http.StatusMethodNotAllowed, http.StatusConflict)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		// 405 Method Not Allowed is returned when the collection exists.
+		return nil
+	}
+
+	// 409 Conflict is returned when the parent collection is missing.
+	if err = b.mkcolAll(path.Dir(dir)); err != nil {
+		return err
+	}
+	resp, err = b.request("MKCOL", dir, true, nil, nil, http.StatusCreated, http.StatusMethodNotAllowed)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (b *WebDAVFileBackend) copyOrMove(method, oldPath, newPath string) error {
+	if err := b.mkcolAll(path.Dir(newPath)); err != nil {
+		return errors.Wrapf(err, "unable to create the new destination directory %s", path.Dir(newPath))
+	}
+	header := http.Header{
+		"Destination": {b.url(newPath, false)},
+		"Overwrite":   {"T"},
+	}
+	resp, err := b.request(method, oldPath, false, nil, header, http.StatusCreated, http.StatusNoContent)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (b *WebDAVFileBackend) CopyFile(oldPath, newPath string) error {
+	if err := b.copyOrMove("COPY", oldPath, newPath); err != nil {
+		return errors.Wrapf(err, "unable to copy file from %s to %s", oldPath, newPath)
+	}
+	return nil
+}
+
+func (b *WebDAVFileBackend) MoveFile(oldPath, newPath string) error {
+	if err := b.copyOrMove("MOVE", oldPath, newPath); err != nil {
+		return errors.Wrapf(err, "unable to move the file to %s from %s", newPath, oldPath)
+	}
+	return nil
+}
+
+func (b *WebDAVFileBackend) WriteFile(fr io.Reader, p string) (int64, error) {
+	if err := b.mkcolAll(path.Dir(p)); err != nil {
+		return 0, errors.Wrapf(err, "unable to create the directory %s for the file %s", path.Dir(p), p)
+	}
+	return b.put(fr, p)
+}
+
+func (b *WebDAVFileBackend) put(fr io.Reader, p string) (int64, error) {
+	cr := &webdavCountingReader{r: fr}
+	resp, err := b.request(http.MethodPut, p, false, cr, nil, http.StatusOK, http.StatusCreated, http.StatusNoContent)
+	if err != nil {
+		return cr.n, errors.Wrapf(err, "unable write the data in the file %s", p)
+	}
+	resp.Body.Close()
+	return cr.n, nil
+}
+
+// AppendFile writes the data at the end of the file with a partial PUT
+// request, the Content-Range header giving its position. Servers that don't
+// support partial updates should reject the request, like sabre/dav does, and
+// the append then fails without touching the file. Servers that ignore the
+// header instead replace the file with the data, which is caught by checking
+// the size of the file afterwards. The data is staged in a temporary file as
+// the range needs its length.
+func (b *WebDAVFileBackend) AppendFile(fr io.Reader, p string) (int64, error) {
+	resource, err := b.stat(p)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to find the file %s to append the data", p)
+	}
+
+	tmp, err := os.CreateTemp("", "webdav-append")
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to create a temporary file")
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	n, err := io.Copy(tmp, fr)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to read the data to append to the file %s", p)
+	}
+	if n == 0 {
+		return 0, nil
+	}
+
+	header := http.Header{
+		"Content-Range": {fmt.Sprintf("bytes %d-%d/*", resource.Size, resource.Size+n-1)},
+	}
+	resp, err := b.request(http.MethodPut, p, false, io.NewSectionReader(tmp, 0, n), header, http.StatusOK, http.StatusCreated, http.StatusNoContent)
+	var statusErr *webdavStatusError
+	if errors.As(err, &statusErr) && (statusErr.StatusCode == http.StatusBadRequest || statusErr.StatusCode == http.StatusNotImplemented) {
+		return 0, errors.Wrapf(err, "unable append the data in the file %s: the webdav server does not support partial updates", p)
+	}
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable append the data in the file %s", p)
+	}
+	resp.Body.Close()
+
+	appended, err := b.stat(p)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to check the data appended to the file %s", p)
+	}
+	if appended.Size != resource.Size+n {
+		return 0, errors.Errorf("unable append the data in the file %s: the webdav server does not support partial updates, the file has %d bytes instead of %d", p, appended.Size, resource.Size+n)
+	}
+	return n, nil
+}
+
+func (b *WebDAVFileBackend) RemoveFile(p string) error {
+	resp, err := b.request(http.MethodDelete, p, false, nil, nil, http.StatusOK, http.StatusNoContent)
+	if err != nil {
+		return errors.Wrapf(err, "unable to remove the file %s", p)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// readDir returns the members of the collection p, or nothing when it does
+// not exist.
+func (b *WebDAVFileBackend) readDir(p string) ([]webdavResource, error) {
+	resources, err := b.propfind(p, "1", true)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the directory %s", p)
+	}
+
+	self := strings.Trim(path.Clean("/"+p), "/")
+	members := make([]webdavResource, 0, len(resources))
+	for _, resource := range resources {
+		if resource.Path != self {
+			members = append(members, resource)
+		}
+	}
+	return members, nil
+}
+
+func (b *WebDAVFileBackend) ListDirectory(p string) ([]string, error) {
+	members, err := b.readDir(p)
+	if err != nil {
+		return nil, err
+	}
+	results := []string{}
+	for _, member := range members {
+		results = append(results, path.Join(p, path.Base(member.Path)))
+	}
+	return results, nil
+}
+
+func (b *WebDAVFileBackend) ListDirectoryRecursively(p string) ([]string, error) {
+	return b.appendRecursively(p, MaxRecursionDepth)
+}
+
+func (b *WebDAVFileBackend) appendRecursively(p string, maxDepth int) ([]string, error) {
+	members, err := b.readDir(p)
+	if err != nil {
+		return nil, err
+	}
+	results := []string{}
+	for _, member := range members {
+		memberPath := path.Join(p, path.Base(member.Path))
+		if !member.IsDir {
+			results = append(results, memberPath)
+			continue
+		}
+		if maxDepth <= 0 {
+			mlog.Warn("Max depth reached, skipping any further directories", mlog.Int("depth", maxDepth), mlog.String("path", memberPath))
+			results = append(results, memberPath)
+			continue
+		}
+		nested, err := b.appendRecursively(memberPath, maxDepth-1)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, nested...)
+	}
+	return results, nil
+}
+
+func (b *WebDAVFileBackend) RemoveDirectory(p string) error {
+	resp, err := b.request(http.MethodDelete, p, true, nil, nil, http.StatusOK, http.StatusNoContent, http.StatusNotFound)
+	if err != nil {
+		return errors.Wrapf(err, "unable to remove the directory %s", p)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// ZipReader will create a zip of path. If path is a single file, it will zip the single file.
+// If deflate is true, the contents will be compressed. It will stream the zip to io.ReadCloser.
+func (b *WebDAVFileBackend) ZipReader(p string, deflate bool) (io.ReadCloser, error) {
+	// Like S3, a missing path zips to an empty archive.
+	resource, err := b.stat(p)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, errors.Wrapf(err, "unable to stat path %s", p)
+	}
+	if err == nil && !resource.IsDir {
+		return zipFiles(b, []string{p}, path.Dir(p), deflate), nil
+	}
+
+	files, err := b.ListDirectoryRecursively(p)
+	if err != nil {
+		return nil, err
+	}
+	return zipFiles(b, files, p, deflate), nil
+}
+
+type webdavCountingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *webdavCountingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// webdavReader reads a remote file, issuing a ranged GET request from the
+// current offset whenever it is read after a seek.
+type webdavReader struct {
+	backend *WebDAVFileBackend
+	path    string
+	size    int64
+	offset  int64
+	body    io.ReadCloser
+}
+
+func (r *webdavReader) Read(p []byte) (int, error) {
+	if r.body == nil {
+		if r.offset >= r.size {
+			return 0, io.EOF
+		}
+		var header http.Header
+		expected := http.StatusOK
+		if r.offset > 0 {
+			header = http.Header{"Range": {fmt.Sprintf("bytes=%d-", r.offset)}}
+			expected = http.StatusPartialContent
+		}
+		resp, err := r.backend.request(http.MethodGet, r.path, false, nil, header, expected)
+		if err != nil {
+			return 0, errors.Wrapf(err, "unable to read file %s", r.path)
+		}
+		r.body = resp.Body
+	}
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	return n, err
+}
+
+func (r *webdavReader) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = r.offset + offset
+	case io.SeekEnd:
+		abs = r.size + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if abs < 0 {
+		return 0, errors.New("negative position")
+	}
+	if abs != r.offset && r.body != nil {
+		r.body.Close()
+		r.body = nil
+	}
+	r.offset = abs
+	return abs, nil
+}
+
+func (r *webdavReader) Close() error {
+	if r.body == nil {
+		return nil
+	}
+	return r.body.Close()
+}
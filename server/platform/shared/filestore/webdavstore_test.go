@@ -0,0 +1,171 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/webdav"
+)
+
+// webdavPartialPut is how a test WebDAV server handles partial PUT requests.
+type webdavPartialPut int
+
+const (
+	// webdavPartialPutSupported writes the data at the given range, like
+	// Apache mod_dav.
+	webdavPartialPutSupported webdavPartialPut = iota
+	// webdavPartialPutRejected rejects the request, like sabre/dav.
+	webdavPartialPutRejected
+	// webdavPartialPutIgnored ignores the Content-Range header and replaces
+	// the file with the data, like the handler of golang.org/x/net/webdav.
+	webdavPartialPutIgnored
+)
+
+// newTestWebDAVServer serves a local directory over WebDAV, handling partial
+// PUT requests as partialPut tells.
+func newTestWebDAVServer(t *testing.T, partialPut webdavPartialPut) *httptest.Server {
+	root := t.TempDir()
+	handler := &webdav.Handler{
+		Prefix:     "/remote.php/dav",
+		FileSystem: webdav.Dir(root),
+		LockSystem: webdav.NewMemLS(),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "mattermost" || password != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if contentRange := r.Header.Get("Content-Range"); r.Method == http.MethodPut && contentRange != "" && partialPut != webdavPartialPutIgnored {
+			var start, end int64
+			if _, err := fmt.Sscanf(contentRange, "bytes %d-%d/*", &start, &end); err != nil || partialPut == webdavPartialPutRejected {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			f, err := os.OpenFile(filepath.Join(root, filepath.FromSlash(strings.TrimPrefix(r.URL.Path, handler.Prefix))), os.O_WRONLY, 0)
+			if err != nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			defer f.Close()
+			if _, err := io.Copy(io.NewOffsetWriter(f, start), io.LimitReader(r.Body, end-start+1)); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWebDAVFileBackendTestSuite(t *testing.T) {
+	server := newTestWebDAVServer(t, webdavPartialPutSupported)
+
+	suite.Run(t, &FileBackendTestSuite{
+		settings: FileBackendSettings{
+			DriverName:     driverWebDAV,
+			WebDAVURL:      server.URL + "/remote.php/dav",
+			WebDAVUsername: "mattermost",
+			WebDAVPassword: "password",
+		},
+	})
+}
+
+func TestWebDAVFileBackend(t *testing.T) {
+	server := newTestWebDAVServer(t, webdavPartialPutSupported)
+
+	t.Run("wrong credentials", func(t *testing.T) {
+		backend, err := NewWebDAVFileBackend(FileBackendSettings{
+			WebDAVURL:      server.URL + "/remote.php/dav",
+			WebDAVUsername: "mattermost",
+			WebDAVPassword: "wrong",
+		})
+		require.NoError(t, err)
+		require.Error(t, backend.TestConnection())
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		_, err := NewWebDAVFileBackend(FileBackendSettings{WebDAVURL: "ftp://files.example.com"})
+		require.Error(t, err)
+	})
+
+	t.Run("reader seeks with ranged requests", func(t *testing.T) {
+		backend, err := NewWebDAVFileBackend(FileBackendSettings{
+			WebDAVURL:      server.URL + "/remote.php/dav",
+			WebDAVUsername: "mattermost",
+			WebDAVPassword: "password",
+		})
+		require.NoError(t, err)
+
+		_, err = backend.WriteFile(bytes.NewReader([]byte("0123456789")), "seek/file.txt")
+		require.NoError(t, err)
+
+		r, err := backend.Reader("seek/file.txt")
+		require.NoError(t, err)
+		defer r.Close()
+
+		_, err = r.Seek(4, io.SeekStart)
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "456789", string(data))
+
+		_, err = r.Seek(-3, io.SeekEnd)
+		require.NoError(t, err)
+		data, err = io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "789", string(data))
+	})
+
+	t.Run("append fails when partial updates are not supported", func(t *testing.T) {
+		server := newTestWebDAVServer(t, webdavPartialPutRejected)
+		backend, err := NewWebDAVFileBackend(FileBackendSettings{
+			WebDAVURL:      server.URL + "/remote.php/dav",
+			WebDAVUsername: "mattermost",
+			WebDAVPassword: "password",
+		})
+		require.NoError(t, err)
+
+		_, err = backend.WriteFile(bytes.NewReader([]byte("0123")), "append/file.txt")
+		require.NoError(t, err)
+
+		_, err = backend.AppendFile(bytes.NewReader([]byte("4567")), "append/file.txt")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not support partial updates")
+
+		data, err := backend.ReadFile("append/file.txt")
+		require.NoError(t, err)
+		assert.Equal(t, "0123", string(data))
+	})
+	t.Run("append fails when partial updates are ignored", func(t *testing.T) {
+		server := newTestWebDAVServer(t, webdavPartialPutIgnored)
+		backend, err := NewWebDAVFileBackend(FileBackendSettings{
+			WebDAVURL:      server.URL + "/remote.php/dav",
+			WebDAVUsername: "mattermost",
+			WebDAVPassword: "password",
+		})
+		require.NoError(t, err)
+
+		_, err = backend.WriteFile(bytes.NewReader([]byte("0123")), "append/file.txt")
+		require.NoError(t, err)
+
+		_, err = backend.AppendFile(bytes.NewReader([]byte("4567")), "append/file.txt")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not support partial updates")
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package filestore
+
+import (
+	"archive/zip"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// zipFiles streams a zip of files read through fb. Entry names are relative
+// to baseDir. It is used by backends that cannot zip the underlying storage
+// directly because they transform the stored contents.
+func zipFiles(fb FileBackend, files []string, baseDir string, deflate bool) io.ReadCloser {
+	deflateMethod := zip.Store
+	if deflate {
+		deflateMethod = zip.Deflate
+	}
+
+	prefix := dirPrefix(baseDir)
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		defer pw.Close()
+
+		zipWriter := zip.NewWriter(pw)
+		defer zipWriter.Close()
+
+		for _, file := range files {
+			if err := copyToZipWriter(fb, zipWriter, file, strings.TrimPrefix(file, prefix), deflateMethod); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+	}()
+
+	return pr
+}
+
+func copyToZipWriter(fb FileBackend, zipWriter *zip.Writer, file, name string, deflateMethod uint16) error {
+	modTime, err := fb.FileModTime(file)
+	if err != nil {
+		return errors.Wrapf(err, "unable to get modification time for %s", file)
+	}
+
+	header := &zip.FileHeader{
+		Name:     name,
+		Method:   deflateMethod,
+		Modified: modTime,
+	}
+	header.SetMode(0644) // rw-r--r-- permissions
+
+	writer, err := zipWriter.CreateHeader(header)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create zip entry for %s", file)
+	}
+
+	reader, err := fb.Reader(file)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create reader for %s", file)
+	}
+	defer reader.Close()
+
+	if _, err := io.Copy(writer, reader); err != nil {
+		return errors.Wrapf(err, "unable to copy content for %s", file)
+	}
+	return nil
+}
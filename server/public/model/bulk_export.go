@@ -13,4 +13,8 @@ type BulkExportOpts struct {
 	IncludeArchivedChannels bool
 	IncludeRolesAndSchemes  bool
 	CreateArchive           bool
+
+	// Since, when not zero, makes the export incremental: only the entities created, updated or deleted after this
+	// time, in milliseconds, are exported. The deleted ones are exported as tombstones, the "delete" lines.
+	Since int64
 }
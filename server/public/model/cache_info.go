@@ -0,0 +1,18 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+// CacheInfo describes a cache of a node along with its statistics since the node started.
+type CacheInfo struct {
+	Name string `json:"name"`
+	// Type is the type of the cache, one of the CacheType constants.
+	Type string `json:"type"`
+	// Size is the maximum number of items of the cache, or 0 if it is not bounded.
+	Size int `json:"size"`
+	// ItemCount is the number of items in the cache, or -1 if unknown.
+	ItemCount int     `json:"item_count"`
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	HitRatio  float64 `json:"hit_ratio"`
+}
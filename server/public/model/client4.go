@@ -196,6 +196,14 @@ func (c *Client4) userCategoryRoute(userID, teamID string) string {
 	return c.userRoute(userID) + c.teamRoute(teamID) + "/channels/categories"
 }
 
+func (c *Client4) userSavedSearchesRoute(userID string) string {
+	return c.userRoute(userID) + "/saved_searches"
+}
+
+func (c *Client4) userSavedSearchRoute(userID, savedSearchID string) string {
+	return c.userSavedSearchesRoute(userID) + "/" + savedSearchID
+}
+
 func (c *Client4) userAccessTokensRoute() string {
 	return c.usersRoute() + "/tokens"
 }
@@ -361,6 +369,10 @@ func (c *Client4) testS3Route() string {
 	return "/file/s3_test"
 }
 
+func (c *Client4) fileStorageTiersRoute() string {
+	return "/file/storage_tiers"
+}
+
 func (c *Client4) databaseRoute() string {
 	return "/database"
 }
@@ -4530,6 +4542,51 @@ func (c *Client4) SearchPostsWithMatches(ctx context.Context, teamId string, ter
 	return &psr, BuildResponse(r), nil
 }
 
+// SearchSimilarPosts returns the posts whose meaning is the closest to the
+// terms, the most similar first. An empty teamId searches all the teams.
+func (c *Client4) SearchSimilarPosts(ctx context.Context, teamId string, terms string, perPage int) (*PostSearchResults, *Response, error) {
+	params := SearchParameter{
+		Terms:   &terms,
+		PerPage: &perPage,
+	}
+	js, err := json.Marshal(params)
+	if err != nil {
+		return nil, nil, NewAppError("SearchSimilarPosts", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	var route string
+	if teamId == "" {
+		route = c.postsRoute() + "/search/similar"
+	} else {
+		route = c.teamRoute(teamId) + "/posts/search/similar"
+	}
+	r, err := c.DoAPIPost(ctx, route, string(js))
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var psr PostSearchResults
+	if err := json.NewDecoder(r.Body).Decode(&psr); err != nil {
+		return nil, nil, NewAppError("SearchSimilarPosts", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &psr, BuildResponse(r), nil
+}
+
+// GetRelatedPosts returns the posts whose meaning is the closest to the given
+// post, the most similar first.
+func (c *Client4) GetRelatedPosts(ctx context.Context, postId string, perPage int) (*PostSearchResults, *Response, error) {
+	query := fmt.Sprintf("?per_page=%v", perPage)
+	r, err := c.DoAPIGet(ctx, c.postRoute(postId)+"/related"+query, "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var psr PostSearchResults
+	if err := json.NewDecoder(r.Body).Decode(&psr); err != nil {
+		return nil, nil, NewAppError("GetRelatedPosts", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &psr, BuildResponse(r), nil
+}
+
 // DoPostAction performs a post action.
 func (c *Client4) DoPostAction(ctx context.Context, postId, actionId string) (*Response, error) {
 	r, err := c.DoAPIPost(ctx, c.postRoute(postId)+"/actions/"+actionId, "")
@@ -4905,6 +4962,20 @@ func (c *Client4) TestS3Connection(ctx context.Context, config *Config) (*Respon
 	return BuildResponse(r), nil
 }
 
+// GetFileStorageTierUsage returns the number of files, and their total size, held by each storage tier.
+func (c *Client4) GetFileStorageTierUsage(ctx context.Context) ([]*FileStorageTierUsage, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.fileStorageTiersRoute(), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var usage []*FileStorageTierUsage
+	if err := json.NewDecoder(r.Body).Decode(&usage); err != nil {
+		return nil, nil, NewAppError("GetFileStorageTierUsage", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return usage, BuildResponse(r), nil
+}
+
 // GetConfig will retrieve the server config with some sanitized items.
 func (c *Client4) GetConfig(ctx context.Context) (*Config, *Response, error) {
 	r, err := c.DoAPIGet(ctx, c.configRoute(), "")
@@ -5006,6 +5077,30 @@ func (c *Client4) InvalidateCaches(ctx context.Context) (*Response, error) {
 	return BuildResponse(r), nil
 }
 
+// GetCaches returns the caches of the server along with their statistics.
+func (c *Client4) GetCaches(ctx context.Context) ([]*CacheInfo, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.cacheRoute(), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var list []*CacheInfo
+	if err := json.NewDecoder(r.Body).Decode(&list); err != nil {
+		return nil, nil, NewAppError("GetCaches", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return list, BuildResponse(r), nil
+}
+
+// PurgeCache empties the named cache on every node of the cluster.
+func (c *Client4) PurgeCache(ctx context.Context, name string) (*Response, error) {
+	r, err := c.DoAPIPost(ctx, c.cacheRoute()+"/"+url.PathEscape(name)+"/purge", "")
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return BuildResponse(r), nil
+}
+
 // UpdateConfig will update the server configuration.
 func (c *Client4) UpdateConfig(ctx context.Context, config *Config) (*Config, *Response, error) {
 	buf, err := json.Marshal(config)
@@ -6723,6 +6818,88 @@ func (c *Client4) DeleteDraft(ctx context.Context, userId, channelId, rootId str
 	return df, BuildResponse(r), nil
 }
 
+// Saved Searches Section
+
+// CreateSavedSearch saves a posts search for the user.
+func (c *Client4) CreateSavedSearch(ctx context.Context, userID string, savedSearch *SavedSearch) (*SavedSearch, *Response, error) {
+	buf, err := json.Marshal(savedSearch)
+	if err != nil {
+		return nil, nil, NewAppError("CreateSavedSearch", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	r, err := c.DoAPIPostBytes(ctx, c.userSavedSearchesRoute(userID), buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var created SavedSearch
+	if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+		return nil, nil, NewAppError("CreateSavedSearch", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &created, BuildResponse(r), nil
+}
+
+// GetSavedSearches returns the saved searches of the user.
+func (c *Client4) GetSavedSearches(ctx context.Context, userID string) ([]*SavedSearch, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.userSavedSearchesRoute(userID), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var savedSearches []*SavedSearch
+	if err := json.NewDecoder(r.Body).Decode(&savedSearches); err != nil {
+		return nil, nil, NewAppError("GetSavedSearches", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return savedSearches, BuildResponse(r), nil
+}
+
+// GetSavedSearch returns a saved search of the user.
+func (c *Client4) GetSavedSearch(ctx context.Context, userID, savedSearchID string) (*SavedSearch, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.userSavedSearchRoute(userID, savedSearchID), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var savedSearch SavedSearch
+	if err := json.NewDecoder(r.Body).Decode(&savedSearch); err != nil {
+		return nil, nil, NewAppError("GetSavedSearch", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &savedSearch, BuildResponse(r), nil
+}
+
+// PatchSavedSearch partially updates a saved search of the user.
+func (c *Client4) PatchSavedSearch(ctx context.Context, userID, savedSearchID string, patch *SavedSearchPatch) (*SavedSearch, *Response, error) {
+	buf, err := json.Marshal(patch)
+	if err != nil {
+		return nil, nil, NewAppError("PatchSavedSearch", "api.marshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+
+	r, err := c.DoAPIPutBytes(ctx, c.userSavedSearchRoute(userID, savedSearchID)+"/patch", buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var patched SavedSearch
+	if err := json.NewDecoder(r.Body).Decode(&patched); err != nil {
+		return nil, nil, NewAppError("PatchSavedSearch", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &patched, BuildResponse(r), nil
+}
+
+// DeleteSavedSearch deletes a saved search of the user.
+func (c *Client4) DeleteSavedSearch(ctx context.Context, userID, savedSearchID string) (*Response, error) {
+	r, err := c.DoAPIDelete(ctx, c.userSavedSearchRoute(userID, savedSearchID))
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return BuildResponse(r), nil
+}
+
 // Commands Section
 
 // CreateCommand will create a new command if the user have the right permissions.
@@ -8724,6 +8901,20 @@ func (c *Client4) ListImports(ctx context.Context) ([]string, *Response, error)
 	return c.ArrayFromJSON(r.Body), BuildResponse(r), nil
 }
 
+// GetImportDryRunReport returns the report of what the dry run import job found the import would change.
+func (c *Client4) GetImportDryRunReport(ctx context.Context, jobID string) (*ImportDryRunReport, *Response, error) {
+	r, err := c.DoAPIGet(ctx, c.importsRoute()+"/dry_run/"+jobID, "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var report ImportDryRunReport
+	if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+		return nil, nil, NewAppError("GetImportDryRunReport", "api.unmarshal_error", nil, "", http.StatusInternalServerError).Wrap(err)
+	}
+	return &report, BuildResponse(r), nil
+}
+
 func (c *Client4) ListExports(ctx context.Context) ([]string, *Response, error) {
 	r, err := c.DoAPIGet(ctx, c.exportsRoute(), "")
 	if err != nil {
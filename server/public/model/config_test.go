@@ -6,6 +6,7 @@ package model
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"reflect"
 	"testing"
@@ -487,6 +488,72 @@ func TestMessageExportSettingsIsValidGlobalRelayEmailAddressInvalid(t *testing.T
 	require.NotNil(t, mes.isValid())
 }
 
+func TestMessageExportSettingsIsValidEml(t *testing.T) {
+	for _, exportFormat := range []string{ComplianceExportTypeEml, ComplianceExportTypeMbox} {
+		mes := &MessageExportSettings{
+			EnableExport:        NewPointer(true),
+			ExportFormat:        NewPointer(exportFormat),
+			ExportFromTimestamp: NewPointer(int64(0)),
+			DailyRunTime:        NewPointer("15:04"),
+			BatchSize:           NewPointer(100),
+		}
+
+		// should pass because everything is valid
+		require.Nil(t, mes.isValid())
+	}
+}
+
+func TestMessageExportSettingsIsValidJsonl(t *testing.T) {
+	mes := &MessageExportSettings{
+		EnableExport:        NewPointer(true),
+		ExportFormat:        NewPointer(ComplianceExportTypeJsonl),
+		ExportFromTimestamp: NewPointer(int64(0)),
+		DailyRunTime:        NewPointer("15:04"),
+		BatchSize:           NewPointer(100),
+	}
+
+	// should pass because everything is valid
+	require.Nil(t, mes.isValid())
+}
+
+func TestMessageExportSettingsIsValidWebhook(t *testing.T) {
+	mes := &MessageExportSettings{
+		EnableExport:        NewPointer(true),
+		ExportFormat:        NewPointer(ComplianceExportTypeWebhook),
+		ExportFromTimestamp: NewPointer(int64(0)),
+		DailyRunTime:        NewPointer("15:04"),
+		BatchSize:           NewPointer(100),
+	}
+
+	// should fail because the webhook settings are missing
+	require.NotNil(t, mes.isValid())
+
+	mes.WebhookSettings = &WebhookMessageExportSettings{}
+	mes.WebhookSettings.SetDefaults()
+
+	// should fail because the URL is missing
+	appErr := mes.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.message_export.webhook.url.app_error", appErr.Id)
+
+	mes.WebhookSettings.URL = NewPointer("https://siem.example.com/mattermost")
+
+	// should fail because the signing secret is too short
+	appErr = mes.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.message_export.webhook.signing_secret.app_error", appErr.Id)
+
+	mes.WebhookSettings.SigningSecret = NewPointer("0123456789abcdef0123456789abcdef")
+
+	// should pass because everything is valid
+	require.Nil(t, mes.isValid())
+
+	mes.WebhookSettings.RunIntervalMinutes = NewPointer(0)
+
+	// should fail because the run interval is invalid
+	require.NotNil(t, mes.isValid())
+}
+
 func TestMessageExportSettingsIsValidActiance(t *testing.T) {
 	mes := &MessageExportSettings{
 		EnableExport:        NewPointer(true),
@@ -1376,9 +1443,14 @@ func TestConfigSanitize(t *testing.T) {
 
 	*c.LdapSettings.BindPassword = "foo"
 	*c.FileSettings.AmazonS3SecretAccessKey = "bar"
+	*c.FileSettings.EncryptionKey = "key"
+	c.FileSettings.EncryptionRetiredKeys = []string{"retired"}
+	*c.FileSettings.SFTPPrivateKey = "private key"
+	*c.FileSettings.ExportWebDAVPassword = "password"
 	*c.EmailSettings.SMTPPassword = "baz"
 	*c.GitLabSettings.Secret = "bingo"
 	*c.OpenIdSettings.Secret = "secret"
+	*c.MessageExportSettings.WebhookSettings.SigningSecret = "signing secret"
 	c.SqlSettings.DataSourceReplicas = []string{"stuff"}
 	c.SqlSettings.DataSourceSearchReplicas = []string{"stuff"}
 	c.SqlSettings.ReplicaLagSettings = []*ReplicaLagSettings{{
@@ -1392,9 +1464,15 @@ func TestConfigSanitize(t *testing.T) {
 	assert.Equal(t, FakeSetting, *c.LdapSettings.BindPassword)
 	assert.Equal(t, FakeSetting, *c.FileSettings.PublicLinkSalt)
 	assert.Equal(t, FakeSetting, *c.FileSettings.AmazonS3SecretAccessKey)
+	assert.Equal(t, FakeSetting, *c.FileSettings.EncryptionKey)
+	assert.Equal(t, FakeSetting, c.FileSettings.EncryptionRetiredKeys[0])
+	assert.Equal(t, FakeSetting, *c.FileSettings.SFTPPrivateKey)
+	assert.Equal(t, FakeSetting, *c.FileSettings.ExportWebDAVPassword)
+	assert.Equal(t, "", *c.FileSettings.SFTPPassword)
 	assert.Equal(t, FakeSetting, *c.EmailSettings.SMTPPassword)
 	assert.Equal(t, FakeSetting, *c.GitLabSettings.Secret)
 	assert.Equal(t, FakeSetting, *c.OpenIdSettings.Secret)
+	assert.Equal(t, FakeSetting, *c.MessageExportSettings.WebhookSettings.SigningSecret)
 	assert.Equal(t, FakeSetting, *c.SqlSettings.DataSource)
 	assert.Equal(t, FakeSetting, *c.SqlSettings.AtRestEncryptKey)
 	assert.Equal(t, FakeSetting, *c.ElasticsearchSettings.Password)
@@ -1707,6 +1785,173 @@ func TestConfigExportSettingsIsValid(t *testing.T) {
 	require.Equal(t, "model.config.is_valid.export.retention_days_too_low.app_error", appErr.Id)
 }
 
+func TestConfigFileSettingsEncryptionIsValid(t *testing.T) {
+	cfg := Config{}
+	cfg.SetDefaults()
+
+	*cfg.FileSettings.EnableEncryption = true
+	appErr := cfg.FileSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.file_encryption_key.app_error", appErr.Id)
+
+	*cfg.FileSettings.EncryptionKey = "not a key"
+	appErr = cfg.FileSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.file_encryption_key.app_error", appErr.Id)
+
+	*cfg.FileSettings.EncryptionKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	require.Nil(t, cfg.FileSettings.isValid())
+
+	cfg.FileSettings.EncryptionRetiredKeys = []string{"c2hvcnQ="}
+	appErr = cfg.FileSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.file_encryption_key.app_error", appErr.Id)
+
+	*cfg.FileSettings.EncryptionKey = ""
+	cfg.FileSettings.EncryptionRetiredKeys = nil
+	*cfg.FileSettings.EncryptionKeyFile = "/etc/mattermost/file.keys"
+	require.Nil(t, cfg.FileSettings.isValid())
+}
+
+func TestConfigFileSettingsTieredStorageIsValid(t *testing.T) {
+	cfg := Config{}
+	cfg.SetDefaults()
+
+	*cfg.FileSettings.EnableTieredStorage = true
+	require.Nil(t, cfg.FileSettings.isValid())
+
+	*cfg.FileSettings.TieredStorageColdAfterDays = -1
+	appErr := cfg.FileSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.tiered_storage_threshold.app_error", appErr.Id)
+
+	*cfg.FileSettings.TieredStorageColdAfterDays = 0
+	*cfg.FileSettings.TieredStorageColdAboveSizeMB = 100
+	require.Nil(t, cfg.FileSettings.isValid())
+
+	*cfg.FileSettings.DriverName = ImageDriverS3
+	appErr = cfg.FileSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.tiered_storage_driver.app_error", appErr.Id)
+}
+
+func TestConfigFileSettingsExtractContentIsValid(t *testing.T) {
+	cfg := Config{}
+	cfg.SetDefaults()
+
+	require.True(t, *cfg.FileSettings.ExtractContentOOXML)
+	require.Equal(t, FileSettingsDefaultExtractContentMaxSizeMB, *cfg.FileSettings.ExtractContentMaxSizeMB)
+	require.Nil(t, cfg.FileSettings.isValid())
+
+	*cfg.FileSettings.ExtractContentMaxSizeMB = 0
+	appErr := cfg.FileSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.extract_content_limits.app_error", appErr.Id)
+
+	*cfg.FileSettings.ExtractContentMaxSizeMB = 10
+	*cfg.FileSettings.ExtractContentTimeoutSeconds = -1
+	appErr = cfg.FileSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.extract_content_limits.app_error", appErr.Id)
+}
+
+func TestConfigFileSettingsOCRIsValid(t *testing.T) {
+	cfg := Config{}
+	cfg.SetDefaults()
+
+	require.False(t, *cfg.FileSettings.ExtractContentOCR)
+	*cfg.FileSettings.ExtractContentOCR = true
+	require.Nil(t, cfg.FileSettings.isValid())
+
+	*cfg.FileSettings.ExtractContentOCRLanguage = "eng+chi_sim"
+	require.Nil(t, cfg.FileSettings.isValid())
+
+	for _, language := range []string{"", "-l", "eng deu", "eng+"} {
+		*cfg.FileSettings.ExtractContentOCRLanguage = language
+		appErr := cfg.FileSettings.isValid()
+		require.NotNil(t, appErr, language)
+		require.Equal(t, "model.config.is_valid.extract_content_ocr_language.app_error", appErr.Id)
+	}
+	*cfg.FileSettings.ExtractContentOCRLanguage = "eng"
+
+	*cfg.FileSettings.ExtractContentOCRURL = "not a url"
+	appErr := cfg.FileSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.extract_content_ocr_url.app_error", appErr.Id)
+
+	*cfg.FileSettings.ExtractContentOCRURL = "http://ocr:8080/extract"
+	require.Nil(t, cfg.FileSettings.isValid())
+
+	*cfg.FileSettings.ExtractContentOCRTimeoutSeconds = 0
+	appErr = cfg.FileSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.extract_content_limits.app_error", appErr.Id)
+}
+
+func TestConfigFileSettingsRemoteDriversIsValid(t *testing.T) {
+	t.Run("webdav", func(t *testing.T) {
+		cfg := Config{}
+		cfg.SetDefaults()
+
+		*cfg.FileSettings.DriverName = ImageDriverWebDAV
+		appErr := cfg.FileSettings.isValid()
+		require.NotNil(t, appErr)
+		require.Equal(t, "model.config.is_valid.webdav_url.app_error", appErr.Id)
+
+		*cfg.FileSettings.WebDAVURL = "ftp://files.example.com"
+		appErr = cfg.FileSettings.isValid()
+		require.NotNil(t, appErr)
+		require.Equal(t, "model.config.is_valid.webdav_url.app_error", appErr.Id)
+
+		*cfg.FileSettings.WebDAVURL = "https://files.example.com/remote.php/dav/files/mattermost"
+		require.Nil(t, cfg.FileSettings.isValid())
+	})
+
+	t.Run("sftp", func(t *testing.T) {
+		cfg := Config{}
+		cfg.SetDefaults()
+
+		*cfg.FileSettings.DriverName = ImageDriverSFTP
+		appErr := cfg.FileSettings.isValid()
+		require.NotNil(t, appErr)
+		require.Equal(t, "model.config.is_valid.sftp_host.app_error", appErr.Id)
+
+		*cfg.FileSettings.SFTPHost = "files.example.com:22"
+		appErr = cfg.FileSettings.isValid()
+		require.NotNil(t, appErr)
+		require.Equal(t, "model.config.is_valid.sftp_host_key.app_error", appErr.Id)
+
+		*cfg.FileSettings.SFTPHostKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIO2iEzQAsXsvKk8w5VKjsZ+uRBH7Z6v8XHzA5E3jS1yQ"
+		appErr = cfg.FileSettings.isValid()
+		require.NotNil(t, appErr)
+		require.Equal(t, "model.config.is_valid.sftp_credentials.app_error", appErr.Id)
+
+		*cfg.FileSettings.SFTPPassword = "password"
+		require.Nil(t, cfg.FileSettings.isValid())
+	})
+
+	t.Run("dedicated export store", func(t *testing.T) {
+		cfg := Config{}
+		cfg.SetDefaults()
+
+		*cfg.FileSettings.DedicatedExportStore = true
+		*cfg.FileSettings.ExportDriverName = ImageDriverDedup
+		appErr := cfg.FileSettings.isValid()
+		require.NotNil(t, appErr)
+		require.Equal(t, "model.config.is_valid.export_file_driver.app_error", appErr.Id)
+
+		*cfg.FileSettings.ExportDriverName = ImageDriverSFTP
+		*cfg.FileSettings.ExportSFTPHost = "files.example.com"
+		*cfg.FileSettings.ExportSFTPHostKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIO2iEzQAsXsvKk8w5VKjsZ+uRBH7Z6v8XHzA5E3jS1yQ"
+		appErr = cfg.FileSettings.isValid()
+		require.NotNil(t, appErr)
+		require.Equal(t, "model.config.is_valid.sftp_credentials.app_error", appErr.Id)
+
+		*cfg.FileSettings.ExportSFTPPrivateKey = "key"
+		require.Nil(t, cfg.FileSettings.isValid())
+	})
+}
+
 func TestConfigServiceSettingsIsValid(t *testing.T) {
 	t.Run("local socket file should exist if local mode enabled", func(t *testing.T) {
 		cfg := Config{}
@@ -2148,3 +2393,176 @@ func TestFilterConfig(t *testing.T) {
 		require.False(t, ok)
 	})
 }
+
+func TestConfigPostgresSearchSettingsIsValid(t *testing.T) {
+	cfg := Config{}
+	cfg.SetDefaults()
+
+	require.Nil(t, cfg.PostgresSearchSettings.isValid(DatabaseDriverMysql))
+
+	*cfg.PostgresSearchSettings.EnableSearching = true
+	appErr := cfg.PostgresSearchSettings.isValid(DatabaseDriverPostgres)
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.postgres_search.enable_searching.app_error", appErr.Id)
+
+	*cfg.PostgresSearchSettings.EnableIndexing = true
+	require.Nil(t, cfg.PostgresSearchSettings.isValid(DatabaseDriverPostgres))
+
+	appErr = cfg.PostgresSearchSettings.isValid(DatabaseDriverMysql)
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.postgres_search.driver.app_error", appErr.Id)
+
+	for _, textSearchConfig := range []string{"simple", "pg_catalog.english", "my_config2"} {
+		*cfg.PostgresSearchSettings.TextSearchConfig = textSearchConfig
+		require.Nil(t, cfg.PostgresSearchSettings.isValid(DatabaseDriverPostgres), textSearchConfig)
+	}
+
+	for _, textSearchConfig := range []string{"", "English", "english'; --", "a.b.c", "1english"} {
+		*cfg.PostgresSearchSettings.TextSearchConfig = textSearchConfig
+		appErr = cfg.PostgresSearchSettings.isValid(DatabaseDriverPostgres)
+		require.NotNil(t, appErr, textSearchConfig)
+		require.Equal(t, "model.config.is_valid.postgres_search.text_search_config.app_error", appErr.Id)
+	}
+	*cfg.PostgresSearchSettings.TextSearchConfig = "english"
+
+	*cfg.PostgresSearchSettings.BatchSize = 0
+	appErr = cfg.PostgresSearchSettings.isValid(DatabaseDriverPostgres)
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.postgres_search.bulk_indexing_batch_size.app_error", appErr.Id)
+}
+
+func TestConfigVectorSearchSettingsIsValid(t *testing.T) {
+	cfg := Config{}
+	cfg.SetDefaults()
+
+	require.Nil(t, cfg.VectorSearchSettings.isValid(DatabaseDriverMysql, false))
+
+	*cfg.VectorSearchSettings.EnableSearching = true
+	appErr := cfg.VectorSearchSettings.isValid(DatabaseDriverPostgres, false)
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.vector_search.enable_searching.app_error", appErr.Id)
+
+	*cfg.VectorSearchSettings.EnableIndexing = true
+	appErr = cfg.VectorSearchSettings.isValid(DatabaseDriverPostgres, false)
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.vector_search.embedding_url.app_error", appErr.Id)
+
+	*cfg.VectorSearchSettings.EmbeddingURL = "http://localhost:8081/v1/embeddings"
+	require.Nil(t, cfg.VectorSearchSettings.isValid(DatabaseDriverPostgres, false))
+
+	appErr = cfg.VectorSearchSettings.isValid(DatabaseDriverMysql, false)
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.vector_search.driver.app_error", appErr.Id)
+
+	*cfg.VectorSearchSettings.Backend = VectorSearchBackendHNSW
+	appErr = cfg.VectorSearchSettings.isValid(DatabaseDriverMysql, false)
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.vector_search.index_dir.app_error", appErr.Id)
+
+	*cfg.VectorSearchSettings.IndexDir = "/var/lib/mattermost/vectors"
+	require.Nil(t, cfg.VectorSearchSettings.isValid(DatabaseDriverMysql, false))
+
+	appErr = cfg.VectorSearchSettings.isValid(DatabaseDriverMysql, true)
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.vector_search.cluster.app_error", appErr.Id)
+
+	*cfg.VectorSearchSettings.Backend = "faiss"
+	appErr = cfg.VectorSearchSettings.isValid(DatabaseDriverMysql, false)
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.vector_search.backend.app_error", appErr.Id)
+	*cfg.VectorSearchSettings.Backend = VectorSearchBackendHNSW
+
+	for _, dimensions := range []int{0, VectorSearchSettingsMaxEmbeddingDimensions + 1} {
+		*cfg.VectorSearchSettings.EmbeddingDimensions = dimensions
+		appErr = cfg.VectorSearchSettings.isValid(DatabaseDriverMysql, false)
+		require.NotNil(t, appErr)
+		require.Equal(t, "model.config.is_valid.vector_search.embedding_dimensions.app_error", appErr.Id)
+	}
+	*cfg.VectorSearchSettings.EmbeddingDimensions = VectorSearchSettingsDefaultEmbeddingDimensions
+
+	*cfg.VectorSearchSettings.RequestTimeoutSeconds = 0
+	appErr = cfg.VectorSearchSettings.isValid(DatabaseDriverMysql, false)
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.vector_search.request_timeout_seconds.app_error", appErr.Id)
+	*cfg.VectorSearchSettings.RequestTimeoutSeconds = VectorSearchSettingsDefaultRequestTimeoutSeconds
+
+	*cfg.VectorSearchSettings.BatchSize = 0
+	appErr = cfg.VectorSearchSettings.isValid(DatabaseDriverMysql, false)
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.vector_search.bulk_indexing_batch_size.app_error", appErr.Id)
+}
+
+func TestRateLimitSettingsIsValidPolicies(t *testing.T) {
+	cfg := Config{}
+	cfg.SetDefaults()
+
+	require.Empty(t, cfg.RateLimitSettings.Policies)
+	require.Nil(t, cfg.RateLimitSettings.isValid())
+
+	policy := &RateLimitPolicy{
+		Name:      NewPointer("posts"),
+		Routes:    []string{"/api/v4/posts/**"},
+		Methods:   []string{http.MethodPost},
+		AuthTypes: []string{RateLimitAuthTypeBot, RateLimitAuthTypePersonalAccessToken},
+	}
+	cfg.RateLimitSettings.Policies = []*RateLimitPolicy{policy}
+	cfg.RateLimitSettings.SetDefaults()
+	require.Equal(t, 10, *policy.PerSec)
+	require.Equal(t, 100, *policy.MaxBurst)
+	require.Empty(t, policy.Roles)
+	require.Nil(t, cfg.RateLimitSettings.isValid())
+
+	for name, test := range map[string]struct {
+		change func(p *RateLimitPolicy)
+		id     string
+	}{
+		"no name":           {func(p *RateLimitPolicy) { *p.Name = "" }, "model.config.is_valid.rate_limit_policy_name.app_error"},
+		"no rate":           {func(p *RateLimitPolicy) { *p.PerSec = 0 }, "model.config.is_valid.rate_limit_policy_rate.app_error"},
+		"no burst":          {func(p *RateLimitPolicy) { *p.MaxBurst = 0 }, "model.config.is_valid.rate_limit_policy_burst.app_error"},
+		"relative route":    {func(p *RateLimitPolicy) { p.Routes = []string{"api/v4/posts"} }, "model.config.is_valid.rate_limit_policy_route.app_error"},
+		"malformed route":   {func(p *RateLimitPolicy) { p.Routes = []string{"/api/v4/[posts"} }, "model.config.is_valid.rate_limit_policy_route.app_error"},
+		"unknown auth type": {func(p *RateLimitPolicy) { p.AuthTypes = []string{"oauth"} }, "model.config.is_valid.rate_limit_policy_auth_type.app_error"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			invalid := &RateLimitPolicy{Name: NewPointer("posts")}
+			invalid.SetDefaults()
+			test.change(invalid)
+			settings := cfg.RateLimitSettings
+			settings.Policies = []*RateLimitPolicy{invalid}
+
+			appErr := settings.isValid()
+			require.NotNil(t, appErr)
+			require.Equal(t, test.id, appErr.Id)
+		})
+	}
+
+	t.Run("duplicate name", func(t *testing.T) {
+		settings := cfg.RateLimitSettings
+		settings.Policies = []*RateLimitPolicy{policy, policy}
+
+		appErr := settings.isValid()
+		require.NotNil(t, appErr)
+		require.Equal(t, "model.config.is_valid.rate_limit_policy_duplicate.app_error", appErr.Id)
+	})
+}
+
+func TestCacheSettingsIsValidHybrid(t *testing.T) {
+	cfg := Config{}
+	cfg.SetDefaults()
+
+	cfg.CacheSettings.CacheType = NewPointer(CacheTypeHybrid)
+	appErr := cfg.CacheSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.empty_redis_address.app_error", appErr.Id)
+
+	cfg.CacheSettings.RedisAddress = NewPointer("localhost:6379")
+	cfg.CacheSettings.RedisDB = NewPointer(0)
+	cfg.CacheSettings.LocalCacheSizes = map[string]int{"UserProfileByIds": 1000, "Team": 0}
+	require.Nil(t, cfg.CacheSettings.isValid())
+	require.True(t, cfg.CacheSettings.UsesRedis())
+
+	cfg.CacheSettings.LocalCacheSizes["Team"] = -1
+	appErr = cfg.CacheSettings.isValid()
+	require.NotNil(t, appErr)
+	require.Equal(t, "model.config.is_valid.local_cache_size.app_error", appErr.Id)
+}
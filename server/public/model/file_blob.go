@@ -0,0 +1,57 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"regexp"
+)
+
+const (
+	FileBlobRefPathMaxLength = 512
+	FileBlobHashLength       = 64
+)
+
+var validFileBlobHash = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// FileBlobRef maps a logical file path to the content-addressed blob that
+// holds its bytes when the deduplicating file backend is in use.
+type FileBlobRef struct {
+	Path     string `json:"path"`
+	Hash     string `json:"hash"`
+	Size     int64  `json:"size"`
+	CreateAt int64  `json:"create_at"`
+}
+
+func (r *FileBlobRef) PreSave() {
+	if r.CreateAt == 0 {
+		r.CreateAt = GetMillis()
+	}
+}
+
+func (r *FileBlobRef) IsValid() *AppError {
+	if r.Path == "" || len(r.Path) > FileBlobRefPathMaxLength {
+		return NewAppError("FileBlobRef.IsValid", "model.file_blob_ref.is_valid.path.app_error", nil, "path="+r.Path, http.StatusBadRequest)
+	}
+
+	if !IsValidFileBlobHash(r.Hash) {
+		return NewAppError("FileBlobRef.IsValid", "model.file_blob_ref.is_valid.hash.app_error", nil, "path="+r.Path, http.StatusBadRequest)
+	}
+
+	if r.Size < 0 {
+		return NewAppError("FileBlobRef.IsValid", "model.file_blob_ref.is_valid.size.app_error", nil, "path="+r.Path, http.StatusBadRequest)
+	}
+
+	if r.CreateAt == 0 {
+		return NewAppError("FileBlobRef.IsValid", "model.file_blob_ref.is_valid.create_at.app_error", nil, "path="+r.Path, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// IsValidFileBlobHash reports whether hash is a lowercase, hex-encoded
+// SHA-256 digest.
+func IsValidFileBlobHash(hash string) bool {
+	return len(hash) == FileBlobHashLength && validFileBlobHash.MatchString(hash)
+}
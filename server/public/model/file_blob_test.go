@@ -0,0 +1,54 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileBlobRefIsValid(t *testing.T) {
+	ref := &FileBlobRef{
+		Path:     "fake/path.png",
+		Hash:     strings.Repeat("ab", 32),
+		Size:     10,
+		CreateAt: 1234,
+	}
+
+	t.Run("Valid blob reference", func(t *testing.T) {
+		assert.Nil(t, ref.IsValid())
+	})
+
+	t.Run("Empty path is not valid", func(t *testing.T) {
+		ref.Path = ""
+		assert.NotNil(t, ref.IsValid())
+		ref.Path = "fake/path.png"
+	})
+
+	t.Run("Uppercase hash is not valid", func(t *testing.T) {
+		ref.Hash = strings.Repeat("AB", 32)
+		assert.NotNil(t, ref.IsValid())
+		ref.Hash = strings.Repeat("ab", 32)
+	})
+
+	t.Run("Short hash is not valid", func(t *testing.T) {
+		ref.Hash = "abcd"
+		assert.NotNil(t, ref.IsValid())
+		ref.Hash = strings.Repeat("ab", 32)
+	})
+
+	t.Run("Negative size is not valid", func(t *testing.T) {
+		ref.Size = -1
+		assert.NotNil(t, ref.IsValid())
+		ref.Size = 10
+	})
+
+	t.Run("CreateAt 0 is not valid", func(t *testing.T) {
+		ref.CreateAt = 0
+		assert.NotNil(t, ref.IsValid())
+		ref.CreateAt = 1234
+	})
+}
@@ -15,6 +15,14 @@ const (
 	FileinfoSortBySize    = "Size"
 )
 
+const (
+	// FileStorageTierHot is the primary file store that new files are written to.
+	FileStorageTierHot = "hot"
+	// FileStorageTierCold is the secondary file store that old or large files are moved to
+	// when tiered storage is enabled.
+	FileStorageTierCold = "cold"
+)
+
 // GetFileInfosOptions contains options for getting FileInfos
 type GetFileInfosOptions struct {
 	// UserIds optionally limits the FileInfos to those created by the given users.
@@ -57,6 +65,18 @@ type FileInfo struct {
 	Content         string  `json:"-"`
 	RemoteId        *string `json:"remote_id"`
 	Archived        bool    `json:"archived"`
+	StorageTier     string  `json:"storage_tier,omitempty"`
+}
+
+// FileStorageTierUsage summarizes how many files, and how many bytes, a storage tier holds.
+type FileStorageTierUsage struct {
+	Tier      string `json:"tier"`
+	FileCount int64  `json:"file_count"`
+	TotalSize int64  `json:"total_size"`
+}
+
+func IsValidFileStorageTier(tier string) bool {
+	return tier == FileStorageTierHot || tier == FileStorageTierCold
 }
 
 func (fi *FileInfo) Auditable() map[string]interface{} {
@@ -90,6 +110,10 @@ func (fi *FileInfo) PreSave() {
 	if fi.RemoteId == nil {
 		fi.RemoteId = NewPointer("")
 	}
+
+	if fi.StorageTier == "" {
+		fi.StorageTier = FileStorageTierHot
+	}
 }
 
 func (fi *FileInfo) IsValid() *AppError {
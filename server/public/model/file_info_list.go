@@ -7,6 +7,14 @@ import (
 	"sort"
 )
 
+// FileSearchHit is a file found by a search engine, with its relevance score
+// and the fragments of its name and content matching the search terms.
+type FileSearchHit struct {
+	Id         string
+	Score      float64
+	Highlights []string
+}
+
 type FileInfoList struct {
 	Order          []string             `json:"order"`
 	FileInfos      map[string]*FileInfo `json:"file_infos"`
@@ -14,6 +22,10 @@ type FileInfoList struct {
 	PrevFileInfoId string               `json:"prev_file_info_id"`
 	// If there are inaccessible files, FirstInaccessibleFileTime is the time of the latest inaccessible file
 	FirstInaccessibleFileTime int64 `json:"first_inaccessible_file_time"`
+	// Scores and Highlights are set by the search engines able to rank and
+	// highlight the files they find.
+	Scores     map[string]float64  `json:"scores,omitempty"`
+	Highlights map[string][]string `json:"highlights,omitempty"`
 }
 
 func NewFileInfoList() *FileInfoList {
@@ -84,6 +96,22 @@ func (o *FileInfoList) Extend(other *FileInfoList) {
 	o.UniqueOrder()
 }
 
+// RemoveMissingHits drops the scores and highlights of the files which are
+// no longer part of the list, so that no fragment of the files which have
+// been filtered out is returned.
+func (o *FileInfoList) RemoveMissingHits() {
+	for fileInfoId := range o.Scores {
+		if _, ok := o.FileInfos[fileInfoId]; !ok {
+			delete(o.Scores, fileInfoId)
+		}
+	}
+	for fileInfoId := range o.Highlights {
+		if _, ok := o.FileInfos[fileInfoId]; !ok {
+			delete(o.Highlights, fileInfoId)
+		}
+	}
+}
+
 func (o *FileInfoList) SortByCreateAt() {
 	sort.Slice(o.Order, func(i, j int) bool {
 		return o.FileInfos[o.Order[i]].CreateAt > o.FileInfos[o.Order[j]].CreateAt
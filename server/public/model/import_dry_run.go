@@ -0,0 +1,94 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+const (
+	ImportDryRunActionCreate = "create"
+	ImportDryRunActionUpdate = "update"
+	ImportDryRunActionDelete = "delete"
+	ImportDryRunActionNoOp   = "no_op"
+
+	// ImportDryRunReportMaxChanges is the maximum number of changes listed by a dry run report, the changes past it
+	// are only counted.
+	ImportDryRunReportMaxChanges = 1000
+
+	// ImportDryRunReportsDir is the directory of the reports of the dry run import jobs in the file store. The
+	// reports are deleted along with the imports, after the retention days of the import settings.
+	ImportDryRunReportsDir = "import_reports"
+)
+
+// ImportDryRunCounts counts the entities of a type by the action the import would take on them.
+type ImportDryRunCounts struct {
+	Create int64 `json:"create"`
+	Update int64 `json:"update"`
+	Delete int64 `json:"delete"`
+	NoOp   int64 `json:"no_op"`
+}
+
+// ImportDryRunField is a field of an entity the import would change.
+type ImportDryRunField struct {
+	Name string `json:"name"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+// ImportDryRunChange is an entity the import would create, update or delete.
+type ImportDryRunChange struct {
+	Line   int                 `json:"line"`
+	Entity string              `json:"entity"`
+	Name   string              `json:"name"`
+	Action string              `json:"action"`
+	Fields []ImportDryRunField `json:"fields,omitempty"`
+}
+
+// ImportDryRunReport is what an import would change, computed against the data of the server without changing it.
+type ImportDryRunReport struct {
+	// Entities counts the actions by entity type: "team", "channel", "user", "team_member", "post"...
+	Entities map[string]*ImportDryRunCounts `json:"entities"`
+	// Changes lists the entities the import would create, update or delete, without the posts it would create.
+	Changes []ImportDryRunChange `json:"changes"`
+	// TruncatedChanges is the number of changes past ImportDryRunReportMaxChanges, which are not listed.
+	TruncatedChanges int64 `json:"truncated_changes"`
+}
+
+func NewImportDryRunReport() *ImportDryRunReport {
+	return &ImportDryRunReport{
+		Entities: make(map[string]*ImportDryRunCounts),
+		Changes:  []ImportDryRunChange{},
+	}
+}
+
+// Add counts the action on the entity, and lists the change unless it is a no-op or the creation of a post.
+func (r *ImportDryRunReport) Add(change ImportDryRunChange) {
+	counts, ok := r.Entities[change.Entity]
+	if !ok {
+		counts = &ImportDryRunCounts{}
+		r.Entities[change.Entity] = counts
+	}
+
+	switch change.Action {
+	case ImportDryRunActionCreate:
+		counts.Create++
+	case ImportDryRunActionUpdate:
+		counts.Update++
+	case ImportDryRunActionDelete:
+		counts.Delete++
+	default:
+		counts.NoOp++
+		return
+	}
+
+	if change.Action == ImportDryRunActionCreate && isImportDryRunPostEntity(change.Entity) {
+		return
+	}
+	if len(r.Changes) >= ImportDryRunReportMaxChanges {
+		r.TruncatedChanges++
+		return
+	}
+	r.Changes = append(r.Changes, change)
+}
+
+func isImportDryRunPostEntity(entity string) bool {
+	return entity == "post" || entity == "reply" || entity == "direct_post"
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportDryRunReportAdd(t *testing.T) {
+	report := NewImportDryRunReport()
+
+	report.Add(ImportDryRunChange{Line: 2, Entity: "team", Name: "team", Action: ImportDryRunActionNoOp})
+	report.Add(ImportDryRunChange{Line: 3, Entity: "channel", Name: "team/channel", Action: ImportDryRunActionUpdate, Fields: []ImportDryRunField{{Name: "display_name", Old: "Channel", New: "Renamed"}}})
+	report.Add(ImportDryRunChange{Line: 4, Entity: "channel", Name: "team/other", Action: ImportDryRunActionCreate})
+	report.Add(ImportDryRunChange{Line: 5, Entity: "post", Name: "team/channel/user/1", Action: ImportDryRunActionCreate})
+	report.Add(ImportDryRunChange{Line: 6, Entity: "post", Name: "team/channel/user/2", Action: ImportDryRunActionDelete})
+
+	assert.Equal(t, map[string]*ImportDryRunCounts{
+		"team":    {NoOp: 1},
+		"channel": {Create: 1, Update: 1},
+		"post":    {Create: 1, Delete: 1},
+	}, report.Entities)
+	assert.Equal(t, []int{3, 4, 6}, []int{report.Changes[0].Line, report.Changes[1].Line, report.Changes[2].Line})
+	assert.Len(t, report.Changes, 3)
+	assert.Zero(t, report.TruncatedChanges)
+
+	t.Run("truncated changes", func(t *testing.T) {
+		report := NewImportDryRunReport()
+		for i := 0; i < ImportDryRunReportMaxChanges+2; i++ {
+			report.Add(ImportDryRunChange{Line: i, Entity: "user", Action: ImportDryRunActionCreate})
+		}
+
+		assert.Equal(t, int64(ImportDryRunReportMaxChanges+2), report.Entities["user"].Create)
+		assert.Len(t, report.Changes, ImportDryRunReportMaxChanges)
+		assert.Equal(t, int64(2), report.TruncatedChanges)
+	})
+}
@@ -24,6 +24,7 @@ const (
 	JobTypeActiveUsers                   = "active_users"
 	JobTypeImportProcess                 = "import_process"
 	JobTypeImportDelete                  = "import_delete"
+	JobTypeImportMSTeams                 = "import_msteams"
 	JobTypeExportProcess                 = "export_process"
 	JobTypeExportDelete                  = "export_delete"
 	JobTypeCloud                         = "cloud"
@@ -42,8 +43,17 @@ const (
 	JobTypeRefreshMaterializedViews      = "refresh_materialized_views"
 	JobTypeDeleteOrphanDraftsMigration   = "delete_orphan_drafts_migration"
 	JobTypeExportUsersToCSV              = "export_users_to_csv"
+	JobTypeExportGuestAccountsReport     = "export_guest_accounts_report"
+	JobTypeExportChannelActivityReport   = "export_channel_activity_report"
+	JobTypeExportTeamMembershipReport    = "export_team_membership_report"
 	JobTypeDeleteDmsPreferencesMigration = "delete_dms_preferences_migration"
 	JobTypeMobileSessionMetadata         = "mobile_session_metadata"
+	JobTypeFileDeduplication             = "file_deduplication"
+	JobTypeFileEncryptionKeyRotation     = "file_encryption_key_rotation"
+	JobTypeFileTiering                   = "file_tiering"
+	JobTypePostgresSearchIndexing        = "postgres_search_indexing"
+	JobTypeSavedSearchAlerts             = "saved_search_alerts"
+	JobTypeVectorSearchIndexing          = "vector_search_indexing"
 
 	JobStatusPending         = "pending"
 	JobStatusInProgress      = "in_progress"
@@ -68,6 +78,7 @@ var AllJobTypes = [...]string{
 	JobTypeActiveUsers,
 	JobTypeImportProcess,
 	JobTypeImportDelete,
+	JobTypeImportMSTeams,
 	JobTypeExportProcess,
 	JobTypeExportDelete,
 	JobTypeCloud,
@@ -77,6 +88,12 @@ var AllJobTypes = [...]string{
 	JobTypeCleanupDesktopTokens,
 	JobTypeRefreshMaterializedViews,
 	JobTypeMobileSessionMetadata,
+	JobTypeFileDeduplication,
+	JobTypeFileEncryptionKeyRotation,
+	JobTypeFileTiering,
+	JobTypePostgresSearchIndexing,
+	JobTypeSavedSearchAlerts,
+	JobTypeVectorSearchIndexing,
 }
 
 type Job struct {
@@ -76,6 +76,7 @@ const (
 	PostPropsGroupHighlightDisabled   = "disable_group_highlight"
 	PostPropsPreviewedPost            = "previewed_post"
 	PostPropsForceNotification        = "force_notification"
+	PostPropsImportId                 = "import_id"
 
 	PostPriorityUrgent               = "urgent"
 	PostPropsRequestedAck            = "requested_ack"
@@ -205,6 +206,7 @@ type SearchParameter struct {
 	Page                   *int    `json:"page"`
 	PerPage                *int    `json:"per_page"`
 	IncludeDeletedChannels *bool   `json:"include_deleted_channels"`
+	Sort                   *string `json:"sort"`
 }
 
 func (sp SearchParameter) Auditable() map[string]interface{} {
@@ -215,6 +217,7 @@ func (sp SearchParameter) Auditable() map[string]interface{} {
 		"page":                     sp.Page,
 		"per_page":                 sp.PerPage,
 		"include_deleted_channels": sp.IncludeDeletedChannels,
+		"sort":                     sp.Sort,
 	}
 }
 
@@ -522,6 +525,7 @@ func (o *Post) SanitizeProps() {
 	membersToSanitize := []string{
 		PropsAddChannelMember,
 		PostPropsForceNotification,
+		PostPropsImportId,
 	}
 
 	for _, member := range membersToSanitize {
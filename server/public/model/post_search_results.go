@@ -10,15 +10,46 @@ import (
 
 type PostSearchMatches map[string][]string
 
+// PostSearchHit is a post found by a search engine, with its relevance score
+// and the fragments of its message matching the search terms.
+type PostSearchHit struct {
+	Id         string
+	Score      float64
+	Highlights []string
+}
+
 type PostSearchResults struct {
 	*PostList
-	Matches PostSearchMatches `json:"matches"`
+	Matches    PostSearchMatches   `json:"matches"`
+	Scores     map[string]float64  `json:"scores,omitempty"`
+	Highlights map[string][]string `json:"highlights,omitempty"`
 }
 
 func MakePostSearchResults(posts *PostList, matches PostSearchMatches) *PostSearchResults {
 	return &PostSearchResults{
-		posts,
-		matches,
+		PostList: posts,
+		Matches:  matches,
+	}
+}
+
+// RemoveMissingHits drops the scores and highlights of the posts which are
+// no longer part of the results, so that no fragment of the messages which
+// have been filtered out is returned.
+func (o *PostSearchResults) RemoveMissingHits() {
+	var posts map[string]*Post
+	if o.PostList != nil {
+		posts = o.PostList.Posts
+	}
+
+	for postID := range o.Scores {
+		if _, ok := posts[postID]; !ok {
+			delete(o.Scores, postID)
+		}
+	}
+	for postID := range o.Highlights {
+		if _, ok := posts[postID]; !ok {
+			delete(o.Highlights, postID)
+		}
 	}
 }
 
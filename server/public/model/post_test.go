@@ -140,6 +140,7 @@ func TestPostSanitizeProps(t *testing.T) {
 		Props: StringInterface{
 			PropsAddChannelMember:      "no good",
 			PostPropsForceNotification: "no good",
+			PostPropsImportId:          "no good",
 			"attachments":              "good",
 		},
 	}
@@ -148,6 +149,7 @@ func TestPostSanitizeProps(t *testing.T) {
 
 	require.Nil(t, post3.GetProp(PropsAddChannelMember))
 	require.Nil(t, post3.GetProp(PostPropsForceNotification))
+	require.Nil(t, post3.GetProp(PostPropsImportId))
 
 	require.NotNil(t, post3.GetProp("attachments"))
 }
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"slices"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -17,10 +18,14 @@ const (
 	ReportDurationLast6Months   = "last_6_months"
 
 	ReportingMaxPageSize = 100
+
+	ReportExportFormatCSV  = "csv"
+	ReportExportFormatJSON = "json"
+	ReportExportFormatXLSX = "xlsx"
 )
 
 var (
-	ReportExportFormats = []string{"csv"}
+	ReportExportFormats = []string{ReportExportFormatCSV, ReportExportFormatJSON, ReportExportFormatXLSX}
 
 	UserReportSortColumns = []string{"CreateAt", "Username", "FirstName", "LastName", "Nickname", "Email", "Roles"}
 )
@@ -157,6 +162,102 @@ func (u *UserReportQuery) ToReport() *UserReport {
 	}
 }
 
+type ChannelActivityReportOptions struct {
+	TeamId        string
+	FromChannelId string
+	PageSize      int
+	StartAt       int64
+	EndAt         int64
+}
+
+// ChannelActivityReport is the activity of a team channel, PostCount counting the posts in the date range of the
+// report.
+type ChannelActivityReport struct {
+	ChannelId   string
+	TeamName    string
+	ChannelName string
+	DisplayName string
+	Type        ChannelType
+	CreateAt    int64
+	DeleteAt    int64
+	LastPostAt  int64
+	MemberCount int64
+	PostCount   int64
+}
+
+func (c *ChannelActivityReport) ToReport() []string {
+	return []string{
+		c.ChannelId,
+		c.TeamName,
+		c.ChannelName,
+		c.DisplayName,
+		string(c.Type),
+		time.UnixMilli(c.CreateAt).String(),
+		reportTime(c.LastPostAt),
+		strconv.FormatInt(c.MemberCount, 10),
+		strconv.FormatInt(c.PostCount, 10),
+		reportTime(c.DeleteAt),
+	}
+}
+
+type TeamMembershipReportOptions struct {
+	TeamId     string
+	FromTeamId string
+	FromUserId string
+	PageSize   int
+}
+
+// TeamMembershipReport is a membership of a user in a team, including the memberships the user left.
+type TeamMembershipReport struct {
+	TeamId        string
+	TeamName      string
+	UserId        string
+	Username      string
+	Email         string
+	ExplicitRoles string
+	SchemeGuest   bool
+	SchemeUser    bool
+	SchemeAdmin   bool
+	CreateAt      int64
+	DeleteAt      int64
+	UserDeleteAt  int64
+}
+
+func (t *TeamMembershipReport) Roles() string {
+	roles := strings.Fields(t.ExplicitRoles)
+	if t.SchemeGuest {
+		roles = append(roles, TeamGuestRoleId)
+	}
+	if t.SchemeUser {
+		roles = append(roles, TeamUserRoleId)
+	}
+	if t.SchemeAdmin {
+		roles = append(roles, TeamAdminRoleId)
+	}
+	return strings.Join(roles, " ")
+}
+
+func (t *TeamMembershipReport) ToReport() []string {
+	return []string{
+		t.TeamId,
+		t.TeamName,
+		t.UserId,
+		t.Username,
+		t.Email,
+		t.Roles(),
+		reportTime(t.CreateAt),
+		reportTime(t.DeleteAt),
+		reportTime(t.UserDeleteAt),
+	}
+}
+
+func reportTime(millis int64) string {
+	if millis <= 0 {
+		return ""
+	}
+	return time.UnixMilli(millis).String()
+}
+
 func IsValidReportExportFormat(format string) bool {
 	for _, fmt := range ReportExportFormats {
 		if format == fmt {
@@ -0,0 +1,134 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	SavedSearchNameMaxRunes  = 64
+	SavedSearchTermsMaxRunes = 1024
+	SavedSearchMaxPerUser    = 50
+)
+
+// SavedSearch is a posts search saved by a user, which can be re-run
+// periodically to alert the user of the new posts matching it.
+type SavedSearch struct {
+	Id     string `json:"id"`
+	UserId string `json:"user_id"`
+	// TeamId is the team searched, or empty to search all the teams of the
+	// user.
+	TeamId         string `json:"team_id"`
+	Name           string `json:"name"`
+	Terms          string `json:"terms"`
+	IsOrSearch     bool   `json:"is_or_search"`
+	TimeZoneOffset int    `json:"time_zone_offset"`
+	EnableAlerts   bool   `json:"enable_alerts"`
+	// LastRunAt is the creation time of the newest post matched by the
+	// previous runs. Only the posts created after it are alerted.
+	LastRunAt int64 `json:"last_run_at"`
+	CreateAt  int64 `json:"create_at"`
+	UpdateAt  int64 `json:"update_at"`
+}
+
+func (o *SavedSearch) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"id":            o.Id,
+		"user_id":       o.UserId,
+		"team_id":       o.TeamId,
+		"enable_alerts": o.EnableAlerts,
+		"create_at":     o.CreateAt,
+		"update_at":     o.UpdateAt,
+	}
+}
+
+func (o *SavedSearch) IsValid() *AppError {
+	if !IsValidId(o.Id) {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(o.UserId) {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.user_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.TeamId != "" && !IsValidId(o.TeamId) {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.team_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.Name == "" || utf8.RuneCountInString(o.Name) > SavedSearchNameMaxRunes {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.name.app_error", map[string]any{"MaxLength": SavedSearchNameMaxRunes}, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if strings.TrimSpace(o.Terms) == "" || utf8.RuneCountInString(o.Terms) > SavedSearchTermsMaxRunes {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.terms.app_error", map[string]any{"MaxLength": SavedSearchTermsMaxRunes}, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.UpdateAt == 0 {
+		return NewAppError("SavedSearch.IsValid", "model.saved_search.is_valid.update_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (o *SavedSearch) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	o.Name = strings.TrimSpace(SanitizeUnicode(o.Name))
+	if o.CreateAt == 0 {
+		o.CreateAt = GetMillis()
+	}
+	o.UpdateAt = o.CreateAt
+
+	// Only the posts created after the search has been saved are alerted.
+	if o.LastRunAt == 0 {
+		o.LastRunAt = o.CreateAt
+	}
+}
+
+func (o *SavedSearch) PreUpdate() {
+	o.UpdateAt = GetMillis()
+	o.Name = strings.TrimSpace(SanitizeUnicode(o.Name))
+}
+
+type SavedSearchPatch struct {
+	Name           *string `json:"name"`
+	Terms          *string `json:"terms"`
+	IsOrSearch     *bool   `json:"is_or_search"`
+	TimeZoneOffset *int    `json:"time_zone_offset"`
+	EnableAlerts   *bool   `json:"enable_alerts"`
+}
+
+func (o *SavedSearchPatch) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"name":          o.Name,
+		"enable_alerts": o.EnableAlerts,
+	}
+}
+
+func (o *SavedSearch) Patch(patch *SavedSearchPatch) {
+	if patch.Name != nil {
+		o.Name = *patch.Name
+	}
+	if patch.Terms != nil {
+		o.Terms = *patch.Terms
+	}
+	if patch.IsOrSearch != nil {
+		o.IsOrSearch = *patch.IsOrSearch
+	}
+	if patch.TimeZoneOffset != nil {
+		o.TimeZoneOffset = *patch.TimeZoneOffset
+	}
+	if patch.EnableAlerts != nil {
+		o.EnableAlerts = *patch.EnableAlerts
+	}
+}
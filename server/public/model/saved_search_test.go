@@ -0,0 +1,87 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSavedSearchIsValid(t *testing.T) {
+	newSavedSearch := func() *SavedSearch {
+		return &SavedSearch{
+			Id:       NewId(),
+			UserId:   NewId(),
+			Name:     "My search",
+			Terms:    "hello from:someone",
+			CreateAt: 1234,
+			UpdateAt: 1234,
+		}
+	}
+
+	t.Run("valid saved search", func(t *testing.T) {
+		assert.Nil(t, newSavedSearch().IsValid())
+	})
+
+	t.Run("valid saved search in a team", func(t *testing.T) {
+		search := newSavedSearch()
+		search.TeamId = NewId()
+		assert.Nil(t, search.IsValid())
+	})
+
+	testCases := []struct {
+		Name   string
+		Update func(*SavedSearch)
+	}{
+		{"invalid id", func(s *SavedSearch) { s.Id = "invalid" }},
+		{"invalid user id", func(s *SavedSearch) { s.UserId = "" }},
+		{"invalid team id", func(s *SavedSearch) { s.TeamId = "invalid" }},
+		{"empty name", func(s *SavedSearch) { s.Name = "" }},
+		{"name too long", func(s *SavedSearch) { s.Name = strings.Repeat("a", SavedSearchNameMaxRunes+1) }},
+		{"blank terms", func(s *SavedSearch) { s.Terms = "  " }},
+		{"terms too long", func(s *SavedSearch) { s.Terms = strings.Repeat("a", SavedSearchTermsMaxRunes+1) }},
+		{"missing create at", func(s *SavedSearch) { s.CreateAt = 0 }},
+		{"missing update at", func(s *SavedSearch) { s.UpdateAt = 0 }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			search := newSavedSearch()
+			tc.Update(search)
+			assert.NotNil(t, search.IsValid())
+		})
+	}
+}
+
+func TestSavedSearchPreSave(t *testing.T) {
+	search := &SavedSearch{Name: "  My search "}
+	search.PreSave()
+
+	assert.True(t, IsValidId(search.Id))
+	assert.Equal(t, "My search", search.Name)
+	assert.NotZero(t, search.CreateAt)
+	assert.Equal(t, search.CreateAt, search.UpdateAt)
+	assert.Equal(t, search.CreateAt, search.LastRunAt)
+}
+
+func TestSavedSearchPatch(t *testing.T) {
+	search := &SavedSearch{
+		Name:       "My search",
+		Terms:      "hello",
+		IsOrSearch: false,
+	}
+
+	search.Patch(&SavedSearchPatch{
+		Terms:        NewPointer("hello world"),
+		IsOrSearch:   NewPointer(true),
+		EnableAlerts: NewPointer(true),
+	})
+
+	assert.Equal(t, "My search", search.Name)
+	assert.Equal(t, "hello world", search.Terms)
+	assert.True(t, search.IsOrSearch)
+	assert.True(t, search.EnableAlerts)
+}
@@ -10,6 +10,11 @@ import (
 	"time"
 )
 
+const (
+	SearchSortRecent    = "recent"
+	SearchSortRelevance = "relevance"
+)
+
 var searchTermPuncStart = regexp.MustCompile(`^[^\pL\d\s#"]+`)
 var searchTermPuncEnd = regexp.MustCompile(`[^\pL\p{M}\d\s*"]+$`)
 
@@ -35,6 +40,11 @@ type SearchParams struct {
 	// True if this search doesn't originate from a "current user".
 	SearchWithoutUserId bool   `json:"search_without_user_id,omitempty"`
 	Modifier            string `json:"modifier"`
+	// Sort is either SearchSortRecent, the default, or SearchSortRelevance.
+	Sort string `json:"sort,omitempty"`
+	// ReadChannels are the channels recently viewed by the user, whose
+	// results are boosted when sorting by relevance.
+	ReadChannels []string `json:"-"`
 }
 
 // Returns the epoch timestamp of the start of the day specified by SearchParams.AfterDate
@@ -387,6 +397,12 @@ func ParseSearchParams(text string, timeZoneOffset int) []*SearchParams {
 	return paramsList
 }
 
+// IsValidSearchSort returns whether the sort is one of the supported
+// search sort orders.
+func IsValidSearchSort(sort string) bool {
+	return sort == SearchSortRecent || sort == SearchSortRelevance
+}
+
 func IsSearchParamsListValid(paramsList []*SearchParams) *AppError {
 	// All SearchParams should have same IncludeDeletedChannels value.
 	for _, params := range paramsList {
@@ -326,6 +326,18 @@ func (wsc *WebSocketClient) GetStatusesByIds(userIds []string) {
 	wsc.SendMessage("get_statuses_by_ids", data)
 }
 
+// Subscribe narrows down the events sent to this connection to the event types, channels and teams of
+// the subscription, in addition to the ones it is already subscribed to.
+func (wsc *WebSocketClient) Subscribe(subscription *WebSocketSubscription) {
+	wsc.SendMessage(WebsocketSubscribeAction, subscription.ToMap())
+}
+
+// Unsubscribe removes the event types, channels and teams of the subscription from the ones this
+// connection is subscribed to, or all of them if the subscription is empty.
+func (wsc *WebSocketClient) Unsubscribe(subscription *WebSocketSubscription) {
+	wsc.SendMessage(WebsocketUnsubscribeAction, subscription.ToMap())
+}
+
 // UpdateActiveChannel sets the current channel that the user is viewing.
 func (wsc *WebSocketClient) UpdateActiveChannel(channelID string) {
 	data := map[string]any{
@@ -8,6 +8,7 @@ import (
 	"io"
 	"maps"
 	"strconv"
+	"strings"
 )
 
 type WebsocketEventType string
@@ -103,6 +104,104 @@ const (
 	WebSocketMsgTypeEvent    = "event"
 )
 
+// websocketEventTypes are the event types sent by the server. A new event type must be added here so that
+// websocket connections can subscribe to it.
+var websocketEventTypes = map[WebsocketEventType]bool{
+	WebsocketEventTyping:                              true,
+	WebsocketEventPosted:                              true,
+	WebsocketEventPostEdited:                          true,
+	WebsocketEventPostDeleted:                         true,
+	WebsocketEventPostUnread:                          true,
+	WebsocketEventChannelConverted:                    true,
+	WebsocketEventChannelCreated:                      true,
+	WebsocketEventChannelDeleted:                      true,
+	WebsocketEventChannelRestored:                     true,
+	WebsocketEventChannelUpdated:                      true,
+	WebsocketEventChannelMemberUpdated:                true,
+	WebsocketEventChannelSchemeUpdated:                true,
+	WebsocketEventDirectAdded:                         true,
+	WebsocketEventGroupAdded:                          true,
+	WebsocketEventNewUser:                             true,
+	WebsocketEventAddedToTeam:                         true,
+	WebsocketEventLeaveTeam:                           true,
+	WebsocketEventUpdateTeam:                          true,
+	WebsocketEventDeleteTeam:                          true,
+	WebsocketEventRestoreTeam:                         true,
+	WebsocketEventUpdateTeamScheme:                    true,
+	WebsocketEventUserAdded:                           true,
+	WebsocketEventUserUpdated:                         true,
+	WebsocketEventUserRoleUpdated:                     true,
+	WebsocketEventMemberroleUpdated:                   true,
+	WebsocketEventUserRemoved:                         true,
+	WebsocketEventPreferenceChanged:                   true,
+	WebsocketEventPreferencesChanged:                  true,
+	WebsocketEventPreferencesDeleted:                  true,
+	WebsocketEventEphemeralMessage:                    true,
+	WebsocketEventStatusChange:                        true,
+	WebsocketEventHello:                               true,
+	WebsocketAuthenticationChallenge:                  true,
+	WebsocketEventReactionAdded:                       true,
+	WebsocketEventReactionRemoved:                     true,
+	WebsocketEventResponse:                            true,
+	WebsocketEventEmojiAdded:                          true,
+	WebsocketEventChannelViewed:                       true,
+	WebsocketEventMultipleChannelsViewed:              true,
+	WebsocketEventPluginStatusesChanged:               true,
+	WebsocketEventPluginEnabled:                       true,
+	WebsocketEventPluginDisabled:                      true,
+	WebsocketEventRoleUpdated:                         true,
+	WebsocketEventLicenseChanged:                      true,
+	WebsocketEventConfigChanged:                       true,
+	WebsocketEventOpenDialog:                          true,
+	WebsocketEventGuestsDeactivated:                   true,
+	WebsocketEventUserActivationStatusChange:          true,
+	WebsocketEventReceivedGroup:                       true,
+	WebsocketEventReceivedGroupAssociatedToTeam:       true,
+	WebsocketEventReceivedGroupNotAssociatedToTeam:    true,
+	WebsocketEventReceivedGroupAssociatedToChannel:    true,
+	WebsocketEventReceivedGroupNotAssociatedToChannel: true,
+	WebsocketEventGroupMemberDelete:                   true,
+	WebsocketEventGroupMemberAdd:                      true,
+	WebsocketEventSidebarCategoryCreated:              true,
+	WebsocketEventSidebarCategoryUpdated:              true,
+	WebsocketEventSidebarCategoryDeleted:              true,
+	WebsocketEventSidebarCategoryOrderUpdated:         true,
+	WebsocketEventCloudPaymentStatusUpdated:           true,
+	WebsocketEventCloudSubscriptionChanged:            true,
+	WebsocketEventThreadUpdated:                       true,
+	WebsocketEventThreadFollowChanged:                 true,
+	WebsocketEventThreadReadChanged:                   true,
+	WebsocketFirstAdminVisitMarketplaceStatusReceived: true,
+	WebsocketEventDraftCreated:                        true,
+	WebsocketEventDraftUpdated:                        true,
+	WebsocketEventDraftDeleted:                        true,
+	WebsocketEventAcknowledgementAdded:                true,
+	WebsocketEventAcknowledgementRemoved:              true,
+	WebsocketEventPersistentNotificationTriggered:     true,
+	WebsocketEventHostedCustomerSignupProgressUpdated: true,
+	WebsocketEventChannelBookmarkCreated:              true,
+	WebsocketEventChannelBookmarkUpdated:              true,
+	WebsocketEventChannelBookmarkDeleted:              true,
+	WebsocketEventChannelBookmarkSorted:               true,
+	WebsocketPresenceIndicator:                        true,
+	WebsocketPostedNotifyAck:                          true,
+	WebsocketScheduledPostCreated:                     true,
+	WebsocketScheduledPostUpdated:                     true,
+	WebsocketScheduledPostDeleted:                     true,
+	WebsocketEventCPAFieldCreated:                     true,
+	WebsocketEventCPAFieldUpdated:                     true,
+	WebsocketEventCPAFieldDeleted:                     true,
+	WebsocketEventCPAValuesUpdated:                    true,
+}
+
+// websocketPluginEventPrefix prefixes the types of the events sent by the plugins.
+const websocketPluginEventPrefix = "custom_"
+
+// IsValidWebsocketEventType returns whether the server sends events of the given type, plugin events included.
+func IsValidWebsocketEventType(eventType WebsocketEventType) bool {
+	return websocketEventTypes[eventType] || (strings.HasPrefix(string(eventType), websocketPluginEventPrefix) && len(eventType) > len(websocketPluginEventPrefix))
+}
+
 type ActiveQueueItem struct {
 	Type string          `json:"type"` // websocket event or websocket response
 	Buf  json.RawMessage `json:"buf"`
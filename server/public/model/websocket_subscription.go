@@ -0,0 +1,74 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+)
+
+const (
+	WebsocketSubscribeAction   = "subscribe"
+	WebsocketUnsubscribeAction = "unsubscribe"
+
+	WebsocketSubscriptionMaxEntries = 1000
+)
+
+// WebSocketSubscription narrows down the events sent to a websocket connection to the given event types
+// and to the given channels and teams. An empty list doesn't filter anything, and the events that are not
+// scoped to a channel or a team, like the ones sent to the user, are only filtered by their type.
+type WebSocketSubscription struct {
+	Events     []WebsocketEventType `json:"events"`
+	ChannelIds []string             `json:"channel_ids"`
+	TeamIds    []string             `json:"team_ids"`
+}
+
+// WebSocketSubscriptionFromMap reads the subscription from the data of a websocket request.
+func WebSocketSubscriptionFromMap(data map[string]any) *WebSocketSubscription {
+	s := &WebSocketSubscription{
+		ChannelIds: ArrayFromInterface(data["channel_ids"]),
+		TeamIds:    ArrayFromInterface(data["team_ids"]),
+	}
+	for _, event := range ArrayFromInterface(data["events"]) {
+		s.Events = append(s.Events, WebsocketEventType(event))
+	}
+	return s
+}
+
+func (s *WebSocketSubscription) ToMap() map[string]any {
+	events := make([]string, 0, len(s.Events))
+	for _, event := range s.Events {
+		events = append(events, string(event))
+	}
+	return map[string]any{
+		"events":      events,
+		"channel_ids": append([]string{}, s.ChannelIds...),
+		"team_ids":    append([]string{}, s.TeamIds...),
+	}
+}
+
+func (s *WebSocketSubscription) IsEmpty() bool {
+	return len(s.Events) == 0 && len(s.ChannelIds) == 0 && len(s.TeamIds) == 0
+}
+
+func (s *WebSocketSubscription) IsValid() *AppError {
+	if len(s.Events)+len(s.ChannelIds)+len(s.TeamIds) > WebsocketSubscriptionMaxEntries {
+		return NewAppError("WebSocketSubscription.IsValid", "model.websocket_subscription.is_valid.too_many.app_error", map[string]any{"Max": WebsocketSubscriptionMaxEntries}, "", http.StatusBadRequest)
+	}
+	for _, event := range s.Events {
+		if !IsValidWebsocketEventType(event) {
+			return NewAppError("WebSocketSubscription.IsValid", "model.websocket_subscription.is_valid.event.app_error", nil, "event="+string(event), http.StatusBadRequest)
+		}
+	}
+	for _, id := range s.ChannelIds {
+		if !IsValidId(id) {
+			return NewAppError("WebSocketSubscription.IsValid", "model.websocket_subscription.is_valid.channel_id.app_error", nil, "channel_id="+id, http.StatusBadRequest)
+		}
+	}
+	for _, id := range s.TeamIds {
+		if !IsValidId(id) {
+			return NewAppError("WebSocketSubscription.IsValid", "model.websocket_subscription.is_valid.team_id.app_error", nil, "team_id="+id, http.StatusBadRequest)
+		}
+	}
+	return nil
+}
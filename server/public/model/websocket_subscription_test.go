@@ -0,0 +1,109 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSocketSubscriptionFromMap(t *testing.T) {
+	s := &WebSocketSubscription{
+		Events:     []WebsocketEventType{WebsocketEventPosted, WebsocketEventTyping},
+		ChannelIds: []string{NewId()},
+	}
+
+	// The data of the requests goes through JSON between the client and the server.
+	buf, err := json.Marshal(s.ToMap())
+	require.NoError(t, err)
+	var data map[string]any
+	require.NoError(t, json.Unmarshal(buf, &data))
+
+	fromMap := WebSocketSubscriptionFromMap(data)
+	assert.Equal(t, s.Events, fromMap.Events)
+	assert.Equal(t, s.ChannelIds, fromMap.ChannelIds)
+	assert.Empty(t, fromMap.TeamIds)
+
+	assert.True(t, WebSocketSubscriptionFromMap(nil).IsEmpty())
+}
+
+func TestWebSocketSubscriptionIsValid(t *testing.T) {
+	for name, tc := range map[string]struct {
+		subscription *WebSocketSubscription
+		errorID      string
+	}{
+		"valid": {
+			subscription: &WebSocketSubscription{Events: []WebsocketEventType{WebsocketEventPosted}, ChannelIds: []string{NewId()}, TeamIds: []string{NewId()}},
+		},
+		"empty event": {
+			subscription: &WebSocketSubscription{Events: []WebsocketEventType{""}},
+			errorID:      "model.websocket_subscription.is_valid.event.app_error",
+		},
+		"unknown event": {
+			subscription: &WebSocketSubscription{Events: []WebsocketEventType{"postd"}},
+			errorID:      "model.websocket_subscription.is_valid.event.app_error",
+		},
+		"plugin event": {
+			subscription: &WebSocketSubscription{Events: []WebsocketEventType{"custom_com.example.plugin_event"}},
+		},
+		"plugin prefix only": {
+			subscription: &WebSocketSubscription{Events: []WebsocketEventType{"custom_"}},
+			errorID:      "model.websocket_subscription.is_valid.event.app_error",
+		},
+		"invalid channel id": {
+			subscription: &WebSocketSubscription{ChannelIds: []string{"channel"}},
+			errorID:      "model.websocket_subscription.is_valid.channel_id.app_error",
+		},
+		"invalid team id": {
+			subscription: &WebSocketSubscription{TeamIds: []string{"team"}},
+			errorID:      "model.websocket_subscription.is_valid.team_id.app_error",
+		},
+		"too many entries": {
+			subscription: &WebSocketSubscription{ChannelIds: strings.Split(strings.Repeat(NewId()+",", WebsocketSubscriptionMaxEntries), ",")},
+			errorID:      "model.websocket_subscription.is_valid.too_many.app_error",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			appErr := tc.subscription.IsValid()
+			if tc.errorID == "" {
+				assert.Nil(t, appErr)
+				return
+			}
+			require.NotNil(t, appErr)
+			assert.Equal(t, tc.errorID, appErr.Id)
+		})
+	}
+}
+
+func TestWebsocketEventTypes(t *testing.T) {
+	// Every event type constant must be known for the connections to subscribe to it.
+	file, err := parser.ParseFile(token.NewFileSet(), "websocket_message.go", nil, 0)
+	require.NoError(t, err)
+
+	var count int
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			if ident, ok := value.Type.(*ast.Ident); !ok || ident.Name != "WebsocketEventType" {
+				continue
+			}
+			for _, name := range value.Names {
+				count++
+				assert.True(t, IsValidWebsocketEventType(WebsocketEventType(strings.Trim(value.Values[0].(*ast.BasicLit).Value, `"`))), name.Name)
+			}
+		}
+	}
+	assert.Equal(t, len(websocketEventTypes), count)
+}